@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
 
@@ -18,6 +19,7 @@ import (
 	"github.com/soochol/upal/internal/db"
 	"github.com/soochol/upal/internal/generate"
 	"github.com/soochol/upal/internal/llmutil"
+	"github.com/soochol/upal/internal/logging"
 	upalmodel "github.com/soochol/upal/internal/model"
 	"github.com/soochol/upal/internal/notify"
 	"github.com/soochol/upal/internal/repository"
@@ -42,14 +44,27 @@ func main() {
 }
 
 func serve() {
+	slog.SetDefault(slog.New(logging.NewContextHandler(slog.NewTextHandler(os.Stderr, nil))))
+
 	cfg, err := config.LoadDefault()
 	if err != nil {
 		slog.Error("config error", "err", err)
 		os.Exit(1)
 	}
 
+	// logLevel is shared with the API so POST /api/admin/log-level takes
+	// effect without a restart. Load has already validated both settings.
+	logLevel := new(slog.LevelVar)
+	if lvl, err := logging.ParseLevel(cfg.Logging.Level); err == nil {
+		logLevel.Set(lvl)
+	}
+	if h, err := logging.NewHandler(os.Stderr, cfg.Logging.Format, logLevel); err == nil {
+		slog.SetDefault(slog.New(h))
+	}
+
 	llms := make(map[string]adkmodel.LLM)
 	providerTypes := make(map[string]string) // name → type
+	breakers := upalmodel.NewCircuitBreakers(cfg.CircuitBreaker)
 
 	for name, pc := range cfg.Providers {
 		llm, ok := upalmodel.BuildLLM(name, pc)
@@ -57,7 +72,7 @@ func serve() {
 			slog.Warn("unknown provider type, skipping", "name", name, "type", pc.Type)
 			continue
 		}
-		llms[name] = llm
+		llms[name] = breakers.Wrap(name, llm)
 		providerTypes[name] = pc.Type
 	}
 
@@ -80,12 +95,14 @@ func serve() {
 
 	toolReg := tools.NewRegistry()
 	toolReg.RegisterNative(tools.WebSearch)
-	toolReg.Register(&tools.HTTPRequestTool{})
+	httpTool := &tools.HTTPRequestTool{}
+	toolReg.Register(httpTool)
 	toolReg.Register(&tools.PythonExecTool{})
 	toolReg.Register(&tools.GetWebpageTool{})
-	toolReg.Register(&tools.RSSFeedTool{})
-	toolReg.Register(tools.NewContentStoreTool(filepath.Join(dataDir, "content_store.json")))
-	toolReg.Register(tools.NewPublishTool(filepath.Join(dataDir, "published")))
+	rssTool := &tools.RSSFeedTool{}
+	toolReg.Register(rssTool)
+	publishTool := tools.NewPublishTool(filepath.Join(dataDir, "published"))
+	toolReg.Register(publishTool)
 	toolReg.Register(&tools.VideoMergeTool{OutputDir: outputDir})
 	toolReg.Register(&tools.RemotionRenderTool{OutputDir: outputDir})
 	sessionService := session.InMemoryService()
@@ -99,6 +116,7 @@ func serve() {
 		} else {
 			database = d
 			defer database.Close()
+			database.SetFloatNumbers(cfg.Runs.FloatNumbers)
 			if err := database.Migrate(context.Background()); err != nil {
 				slog.Error("database migration failed", "err", err)
 				os.Exit(1)
@@ -107,6 +125,21 @@ func serve() {
 		}
 	}
 
+	// Content store backend: JSON file by default, Postgres when configured and available.
+	contentStore := tools.ContentStoreBackend(tools.NewFileContentStore(filepath.Join(dataDir, "content_store.json")))
+	if cfg.ContentStore.Backend == "postgres" {
+		if database != nil {
+			contentStore = repository.NewPersistentContentStore(database)
+		} else {
+			slog.Warn("content store backend 'postgres' requires a database, falling back to file")
+		}
+	}
+	toolReg.Register(tools.NewContentStoreToolWithBackend(contentStore))
+	for name, rl := range cfg.Tools.RateLimits {
+		toolReg.SetRateLimit(name, rl.Calls, rl.Per)
+	}
+	rssTool.SetContentStore(contentStore)
+
 	// Create auth service (requires database for user storage).
 	var authSvc *services.AuthService
 	if database != nil {
@@ -114,7 +147,7 @@ func serve() {
 		if host == "" || host == "0.0.0.0" {
 			host = "localhost"
 		}
-		baseURL := fmt.Sprintf("http://%s:%d", host, cfg.Server.Port)
+		baseURL := fmt.Sprintf("http://%s:%d%s", host, cfg.Server.Port, cfg.Server.BasePathPrefix())
 		authSvc = services.NewAuthService(database, cfg.Auth, baseURL)
 		authSvc.StartCleanup(context.Background())
 	}
@@ -147,6 +180,22 @@ func serve() {
 		triggerRepo = repository.NewPersistentTriggerRepository(memTriggerRepo, database)
 	}
 
+	// Create audit log repository (in-memory, or persistent if DB is available).
+	memAuditRepo := repository.NewMemoryAuditRepository()
+	var auditRepo repository.AuditRepository = memAuditRepo
+	if database != nil {
+		auditRepo = repository.NewPersistentAuditRepository(memAuditRepo, database)
+	}
+	auditSvc := services.NewAuditService(auditRepo)
+
+	// Maintenance flag (persistent if DB is available so it survives restarts).
+	memMaintenanceRepo := repository.NewMemoryMaintenanceRepository()
+	var maintenanceRepo repository.MaintenanceRepository = memMaintenanceRepo
+	if database != nil {
+		maintenanceRepo = repository.NewPersistentMaintenanceRepository(memMaintenanceRepo, database)
+	}
+	maintenanceSvc := services.NewMaintenanceService(context.Background(), maintenanceRepo)
+
 	// Skills registry — created early so prompts are available to services.
 	skillReg := skills.New()
 
@@ -160,7 +209,7 @@ func serve() {
 	runHistorySvc.CleanupOrphanedRuns(context.Background())
 
 	// Create concurrency limiter from config (with defaults).
-	concurrencyLimits := cfg.Scheduler
+	concurrencyLimits := cfg.Scheduler.ConcurrencyLimits
 	if concurrencyLimits.GlobalMax <= 0 {
 		concurrencyLimits.GlobalMax = 10
 	}
@@ -169,11 +218,28 @@ func serve() {
 	}
 	limiter := services.NewConcurrencyLimiter(concurrencyLimits)
 
+	// Run manager for background execution with event buffering.
+	runManager := services.NewRunManager(cfg.Runs.TTL)
+	defer runManager.Stop()
+
 	// Create retry executor and scheduler service.
 	retryExecutor := services.NewRetryExecutor(workflowSvc, runHistorySvc)
+	retryExecutor.SetRunManager(runManager)
+	retryExecutor.SetStartLimiter(limiter)
 	schedulerSvc := scheduler.NewSchedulerService(
 		scheduleRepo, workflowSvc, retryExecutor, limiter, runHistorySvc,
 	)
+	schedulerSvc.SetAutoPauseAfter(cfg.Scheduler.AutoPauseAfter)
+	schedulerSvc.SetDefaultTimezone(cfg.Scheduler.DefaultTimezone)
+	schedulerSvc.SetAuditRecorder(auditSvc)
+	schedulerSvc.SetMaintenanceGate(maintenanceSvc)
+	if cfg.Scheduler.Queue {
+		if database != nil {
+			schedulerSvc.SetRunQueue(repository.NewPersistentRunQueueRepository(database), cfg.Scheduler.QueueLease)
+		} else {
+			slog.Warn("scheduler queue requires a database, running ticks in-process")
+		}
+	}
 
 	// Start the scheduler (loads existing schedules from repo).
 	if err := schedulerSvc.Start(context.Background()); err != nil {
@@ -188,6 +254,11 @@ func serve() {
 	srv.SetConcurrencyLimiter(limiter)
 	srv.SetRetryExecutor(retryExecutor)
 	srv.SetTriggerRepository(triggerRepo)
+	srv.SetWebhookConfig(cfg.Webhooks)
+	srv.SetWorkflowLimits(cfg.WorkflowLimits)
+	srv.SetAuditService(auditSvc)
+	srv.SetMaintenanceService(maintenanceSvc)
+	srv.SetLogLevel(logLevel)
 	if authSvc != nil {
 		srv.SetAuthService(authSvc)
 	}
@@ -204,6 +275,8 @@ func serve() {
 	enc, _ := upalcrypto.NewEncryptor(nil)
 	connSvc := services.NewConnectionService(connRepo, enc)
 	srv.SetConnectionService(connSvc)
+	httpTool.SetConnectionResolver(connSvc)
+	publishTool.SetConnectionResolver(connSvc, cfg.Tools.PublishConnection)
 
 	// AI provider management (persistent if DB is available).
 	memAIProviderRepo := repository.NewMemoryAIProviderRepository()
@@ -216,34 +289,8 @@ func serve() {
 
 	// Migrate config.yaml providers to DB on first startup (when DB is empty).
 	if len(cfg.Providers) > 0 {
-		startupCtx := context.Background()
-		if existing, err := aiProviderSvc.ListAll(startupCtx); err == nil && len(existing) == 0 {
-			typeToCategory := make(map[string]upal.AIProviderCategory)
-			for cat, types := range upal.ValidProviderTypes {
-				for _, t := range types {
-					typeToCategory[t] = cat
-				}
-			}
-			for name, pc := range cfg.Providers {
-				cat, ok := typeToCategory[pc.Type]
-				if !ok {
-					slog.Warn("skipping migration: unknown provider type", "name", name, "type", pc.Type)
-					continue
-				}
-				modelName, _ := upalmodel.FirstModelForType(pc.Type)
-				p := &upal.AIProvider{
-					Name:     name,
-					Category: cat,
-					Type:     pc.Type,
-					Model:    modelName,
-					APIKey:   pc.APIKey,
-				}
-				if err := aiProviderSvc.Create(startupCtx, p); err != nil {
-					slog.Warn("failed to migrate config.yaml provider to DB", "name", name, "err", err)
-				} else {
-					slog.Info("migrated config.yaml provider to DB", "name", name, "type", pc.Type)
-				}
-			}
+		if err := aiProviderSvc.ImportConfigProviders(context.Background(), cfg.Providers); err != nil {
+			slog.Warn("failed to migrate config.yaml providers", "err", err)
 		}
 	}
 
@@ -260,26 +307,49 @@ func serve() {
 				URL:    upalmodel.DefaultURLForType(p.Type),
 			}
 			if llm, ok := upalmodel.BuildLLM(p.Name, pc); ok {
-				llms[p.Name] = llm
+				llms[p.Name] = breakers.Wrap(p.Name, llm)
 				providerTypes[p.Name] = p.Type
 			} else {
 				slog.Warn("failed to build LLM from DB provider, skipping", "name", p.Name, "type", p.Type)
 			}
 		}
 		// Override default LLM if a DB provider is marked as default.
-		for _, p := range dbProviders {
-			if p.IsDefault && p.Category == upal.AICategoryLLM {
-				if llm, ok := llms[p.Name]; ok {
-					defaultLLM = llm
-					if modelName, ok := upalmodel.FirstModelForType(p.Type); ok {
-						defaultModelName = modelName
-					}
+		if p, err := aiProviderSvc.DefaultLLM(context.Background()); err == nil {
+			if llm, ok := llms[p.Name]; ok {
+				defaultLLM = llm
+				if modelName, ok := upalmodel.FirstModelForType(p.Type); ok {
+					defaultModelName = modelName
 				}
 			}
 		}
 		// Update resolver with potentially new defaults.
 		resolver = llmutil.NewMapResolver(llms, defaultLLM, defaultModelName)
 	}
+	if len(llms) == 0 {
+		slog.Warn("no LLM providers configured: generation and runs with agent nodes answer 503 until one is configured")
+	}
+
+	// Output node layout generation: validate the configured model now that
+	// every provider is registered.
+	if lc := cfg.Layout; lc.Model != "" && !lc.Disabled {
+		if _, _, err := resolver.Resolve(lc.Model); err != nil {
+			slog.Error("layout model not available", "model", lc.Model, "err", err)
+			os.Exit(1)
+		}
+	}
+	workflowSvc.SetLayoutConfig(cfg.Layout.Model, cfg.Layout.Disabled)
+	if mc := cfg.Moderation; mc.Provider != "" {
+		if pc, ok := cfg.Providers[mc.Provider]; ok {
+			workflowSvc.SetModerator(upalmodel.NewOpenAIModerator(pc.APIKey, pc.URL, mc.Model))
+		} else {
+			slog.Warn("moderation provider not configured", "provider", mc.Provider)
+		}
+	}
+	runCtxProviders := make(map[string]upal.ProviderInfo, len(cfg.Providers))
+	for name, pc := range cfg.Providers {
+		runCtxProviders[name] = upal.ProviderInfo{Type: pc.Type, URL: pc.URL}
+	}
+	workflowSvc.SetRunContextSource(runCtxProviders, cfg.Redacted())
 
 	// Build effective provider configs by merging config.yaml + DB providers.
 	effectiveProviders := make(map[string]config.ProviderConfig, len(providerTypes))
@@ -295,20 +365,46 @@ func serve() {
 	publishChannelRepo := repository.NewMemoryPublishChannelRepository()
 	srv.SetPublishChannelRepo(publishChannelRepo)
 
-	// Notification sender registry.
-	senderReg := notify.NewSenderRegistry()
-	senderReg.Register(&notify.TelegramSender{})
-	senderReg.Register(&notify.SlackSender{})
-	senderReg.Register(&notify.SMTPSender{})
+	// Configure file storage
+	store, err := storage.NewLocalStorage("./uploads")
+	if err != nil {
+		slog.Error("storage error", "err", err)
+		os.Exit(1)
+	}
+
+	// Notification sender registry: built-in types plus any registered with
+	// notify.RegisterSenderType.
+	senderReg := notify.NewDefaultSenderRegistry(notify.SenderDeps{Storage: store})
+	if sa := cfg.Scheduler.SaturationAlert; sa.After > 0 && sa.ConnectionID != "" {
+		limiter.SetSaturationAlert(sa.After, services.SaturationNotifier(senderReg, connSvc, sa.ConnectionID))
+	}
+
+	// Outbound run events fanned out to subscribed connections.
+	memEventSubRepo := repository.NewMemoryEventSubscriptionRepository()
+	var eventSubRepo repository.EventSubscriptionRepository = memEventSubRepo
+	if database != nil {
+		eventSubRepo = repository.NewPersistentEventSubscriptionRepository(memEventSubRepo, database)
+	}
+	srv.SetEventSubscriptionRepo(eventSubRepo)
+	runHistorySvc.SetEventPublisher(services.NewEventBus(eventSubRepo, senderReg, connSvc))
+
+	// Imported OpenAPI toolsets, registered again from storage.
+	memOpenAPIImports := repository.NewMemoryOpenAPIImportRepository()
+	var openAPIImports repository.OpenAPIImportRepository = memOpenAPIImports
+	if database != nil {
+		openAPIImports = repository.NewPersistentOpenAPIImportRepository(memOpenAPIImports, database)
+	}
+	srv.SetOpenAPIImportRepo(openAPIImports)
+	srv.RestoreOpenAPITools(context.Background())
 
 	// Execution registry for pause/resume (pipeline stage approval).
 	execReg := services.NewExecutionRegistry()
 	srv.SetExecutionRegistry(execReg)
 
-	// Run manager for background execution with event buffering.
-	runManager := services.NewRunManager(cfg.Runs.TTL)
-	defer runManager.Stop()
 	srv.SetRunManager(runManager)
+	srv.SetSSEHeartbeat(cfg.Runs.Heartbeat)
+	srv.SetFloatNumbers(cfg.Runs.FloatNumbers)
+	srv.SetPreviewTimeout(cfg.Server.Timeouts.Preview)
 
 	// Generation manager for background LLM generation (workflow, pipeline).
 	genManager := services.NewGenerationManager(cfg.Runs.TTL)
@@ -317,6 +413,7 @@ func serve() {
 
 	// RunPublisher bridges workflow execution into RunManager + RunHistoryService.
 	publisher := runpub.NewRunPublisher(workflowSvc, runManager, runHistorySvc, execReg)
+	publisher.SetConcurrencyLimiter(limiter)
 	srv.SetRunPublisher(publisher)
 
 	// Pipeline
@@ -334,6 +431,7 @@ func serve() {
 
 	pipelineSvc := services.NewPipelineService(pipelineRepo, pipelineRunRepo)
 	pipelineRunner := services.NewPipelineRunner(pipelineRunRepo)
+	pipelineRunner.SetConcurrencyLimiter(limiter)
 	pipelineRunner.RegisterExecutor(services.NewWorkflowStageExecutor(workflowSvc))
 	pipelineRunner.RegisterExecutor(services.NewApprovalStageExecutor(senderReg, connSvc))
 	pipelineRunner.RegisterExecutor(services.NewNotificationStageExecutor(senderReg, connSvc))
@@ -346,6 +444,22 @@ func serve() {
 	schedulerSvc.SetPipelineRunner(pipelineRunner)
 	schedulerSvc.SetPipelineService(pipelineSvc)
 
+	srv.SetSearchService(services.NewSearchService(repo, pipelineSvc, runHistorySvc))
+
+	suggestSvc := services.NewWorkflowSuggestService(repo)
+	regressionChecker := services.NewRegressionChecker(workflowSvc)
+	if ec := cfg.Embeddings; ec.Provider != "" {
+		if pc, ok := cfg.Providers[ec.Provider]; ok {
+			embedder := upalmodel.NewOpenAIEmbedder(pc.APIKey, pc.URL, ec.Model)
+			suggestSvc.SetEmbedder(embedder)
+			regressionChecker.SetComparator(upal.RegressionEmbedding, services.EmbeddingComparator{Embedder: embedder})
+		} else {
+			slog.Warn("embeddings provider not configured, using keyword suggestions", "provider", ec.Provider)
+		}
+	}
+	srv.SetWorkflowSuggestService(suggestSvc)
+	srv.SetRegressionChecker(regressionChecker)
+
 	// Content media pipeline
 	memContentSessionRepo := repository.NewMemoryContentSessionRepository()
 	memSourceFetchRepo := repository.NewMemorySourceFetchRepository()
@@ -444,30 +558,27 @@ func serve() {
 		}
 		gen := generate.New(defaultLLM, defaultModelName, skillReg, toolInfos, modelOpts)
 		gen.SetLLMResolver(resolver)
+		gen.SetLanguage(cfg.Generator.Language)
+		gen.SetWorkflowLimits(cfg.WorkflowLimits)
 		defaultLLMFunc := func(ctx context.Context) (adkmodel.LLM, string, error) {
-			providers, err := aiProviderSvc.ListAll(ctx)
+			p, err := aiProviderSvc.DefaultLLM(ctx)
 			if err != nil {
 				return nil, "", err
 			}
-			for _, p := range providers {
-				if p.IsDefault && p.Category == upal.AICategoryLLM {
-					pc := config.ProviderConfig{
-						Type:   p.Type,
-						APIKey: p.APIKey,
-						URL:    upalmodel.DefaultURLForType(p.Type),
-					}
-					built, ok := upalmodel.BuildLLM(p.Name, pc)
-					if !ok {
-						return nil, "", fmt.Errorf("failed to build LLM for provider %s", p.Name)
-					}
-					modelName := p.Model
-					if modelName == "" {
-						modelName, _ = upalmodel.FirstModelForType(p.Type)
-					}
-					return built, modelName, nil
-				}
+			pc := config.ProviderConfig{
+				Type:   p.Type,
+				APIKey: p.APIKey,
+				URL:    upalmodel.DefaultURLForType(p.Type),
+			}
+			built, ok := upalmodel.BuildLLM(p.Name, pc)
+			if !ok {
+				return nil, "", fmt.Errorf("failed to build LLM for provider %s", p.Name)
+			}
+			modelName := p.Model
+			if modelName == "" {
+				modelName, _ = upalmodel.FirstModelForType(p.Type)
 			}
-			return nil, "", fmt.Errorf("no default LLM provider configured")
+			return breakers.Wrap(p.Name, built), modelName, nil
 		}
 		gen.SetDefaultLLMFunc(defaultLLMFunc)
 		gen.SetModelsFunc(func(ctx context.Context) []upal.ModelSummary {
@@ -508,20 +619,26 @@ func serve() {
 		}
 	}
 	srv.SetProviderConfigs(effectiveProviders)
+	srv.SetProviderBreakers(breakers)
+	srv.SetReachabilityChecker(upalmodel.NewReachabilityChecker(0))
+	warmer := upalmodel.NewWarmer(llms, effectiveProviders, cfg.Warmup)
+	srv.SetWarmer(warmer)
+	go warmer.Preflight(context.Background())
+	srv.SetNodeRunner(workflowSvc)
+	srv.SetTemplateResolver(workflowSvc)
 	srv.SetServerConfig(cfg.Server, cfg.Generator)
 
 	// Enable A2A protocol endpoints.
 	a2aURL := fmt.Sprintf("http://localhost:%d", cfg.Server.Port)
+	if cfg.Server.PublicURL != "" {
+		a2aURL = strings.TrimRight(cfg.Server.PublicURL, "/")
+	}
 	srv.SetA2ABaseURL(a2aURL)
-	slog.Info("A2A enabled", "card", a2aURL+"/.well-known/agent-card.json")
+	slog.Info("A2A enabled", "card", strings.TrimSuffix(a2aURL, cfg.Server.BasePathPrefix())+cfg.Server.BasePathPrefix()+"/.well-known/agent-card.json")
 
-	// Configure file storage
-	store, err := storage.NewLocalStorage("./uploads")
-	if err != nil {
-		slog.Error("storage error", "err", err)
-		os.Exit(1)
-	}
+	// Wire file storage (created above for the sender registry).
 	srv.SetStorage(store)
+	runHistorySvc.SetStorage(store)
 	nodeReg.Register(agents.NewAssetNodeBuilder(store))
 
 	// Backfill missing descriptions for existing workflows and pipeline stages.
@@ -544,7 +661,7 @@ func serve() {
 			}
 			pipes, _ := pipelineSvc.List(ctx)
 			for _, p := range pipes {
-				if generate.BackfillStageDescriptions(p) {
+				if generate.BackfillStageDescriptions(p) > 0 {
 					if err := pipelineSvc.Update(ctx, p); err != nil {
 						slog.Warn("backfill: pipeline update failed", "id", p.ID, "err", err)
 					}
@@ -1,8 +1,8 @@
 package agents
 
 import (
+	"context"
 	"encoding/base64"
-	"fmt"
 	"log/slog"
 	"regexp"
 	"strings"
@@ -26,8 +26,10 @@ func BuildAgent(nd *upal.NodeDefinition, llms map[string]adkmodel.LLM, toolReg *
 	})
 }
 
-// templatePattern matches {{key}} or {{key.subkey}} placeholders.
-var templatePattern = regexp.MustCompile(`\{\{(\w+(?:\.\w+)*)\}\}`)
+// templatePattern matches {{key}} or {{key.subkey}} placeholders, optionally
+// followed by a filter pipeline such as {{key | upper}} or
+// {{key | default:"N/A"}}. See renderPlaceholder.
+var templatePattern = regexp.MustCompile(`\{\{(\w+(?:\.\w+)*)((?:\s*\|\s*\w+(?::"(?:[^"\\]|\\.)*")?)*)\s*\}\}`)
 
 // namedLLM wraps an LLM to override Name() with a specific model name.
 // ADK uses Name() as req.Model in API requests, so each agent node needs
@@ -43,20 +45,31 @@ func (n *namedLLM) Name() string { return n.name }
 // resolveTemplateFromState replaces {{key}} placeholders in a template string
 // with values from session state. Unresolved placeholders are left as-is.
 func resolveTemplateFromState(template string, state session.State) string {
-	return templatePattern.ReplaceAllStringFunc(template, func(match string) string {
-		key := strings.Trim(match, "{}")
+	lookup := func(key string) (any, bool) {
 		val, err := state.Get(key)
-		if err != nil || val == nil {
-			return match
-		}
-		return fmt.Sprintf("%v", val)
+		return val, err == nil
+	}
+	return templatePattern.ReplaceAllStringFunc(template, func(match string) string {
+		return renderPlaceholder(match, lookup)
+	})
+}
+
+// ResolveTemplate replaces {{key}} placeholders in a template string with
+// values from a plain map. Unresolved placeholders are left as-is.
+func ResolveTemplate(template string, values map[string]any) string {
+	lookup := func(key string) (any, bool) {
+		val, ok := values[key]
+		return val, ok
+	}
+	return templatePattern.ReplaceAllStringFunc(template, func(match string) string {
+		return renderPlaceholder(match, lookup)
 	})
 }
 
 // buildPromptParts converts a resolved prompt string into genai Parts.
 // Segments that are bare data URIs (from asset image nodes) become inline
 // image parts; everything else becomes text parts.
-func buildPromptParts(prompt string) []*genai.Part {
+func buildPromptParts(ctx context.Context, prompt string) []*genai.Part {
 	if !strings.Contains(prompt, "data:image/") {
 		return []*genai.Part{genai.NewPartFromText(prompt)}
 	}
@@ -88,7 +101,7 @@ func buildPromptParts(prompt string) []*genai.Part {
 		if p := parseDataURIPart(uri); p != nil {
 			parts = append(parts, p)
 		} else {
-			slog.Warn("buildPromptParts: failed to parse data URI, falling back to text", "uri_prefix", uri[:min(len(uri), 40)])
+			slog.WarnContext(ctx, "buildPromptParts: failed to parse data URI, falling back to text", "uri_prefix", uri[:min(len(uri), 40)])
 			parts = append(parts, genai.NewPartFromText(uri))
 		}
 	}
@@ -2,14 +2,26 @@ package agents
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"iter"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/soochol/upal/internal/llmutil"
+	upalmodel "github.com/soochol/upal/internal/model"
 	"github.com/soochol/upal/internal/tools"
 	"github.com/soochol/upal/internal/upal"
+	"google.golang.org/adk/agent"
 	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
 	"google.golang.org/genai"
 )
 
@@ -134,6 +146,95 @@ func TestResolveTemplateFromState(t *testing.T) {
 	}
 }
 
+func TestResolveTemplate_Filters(t *testing.T) {
+	values := map[string]any{
+		"name":  "World",
+		"empty": "",
+		"data":  map[string]any{"score": 3},
+		"quote": `say "hi"`,
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{{name | upper}}", "WORLD"},
+		{"{{name | lower}}", "world"},
+		{"{{name|upper}}", "WORLD"},
+		{`{{missing | default:"N/A"}}`, "N/A"},
+		{`{{empty | default:"N/A"}}`, "N/A"},
+		{`{{name | default:"N/A"}}`, "World"},
+		{`{{missing | default:"n/a" | upper}}`, "N/A"},
+		{`{{missing | default:"say \"x\""}}`, `say "x"`},
+		{"{{data | json}}", `{"score":3}`},
+		{"{{quote | json}}", `"say \"hi\""`},
+		{"{{name | shout}}", "World"},
+		{"{{missing | upper}}", "{{missing | upper}}"},
+		{"{{missing}}", "{{missing}}"},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveTemplate(tt.template, values); got != tt.expected {
+			t.Errorf("ResolveTemplate(%q) = %q, want %q", tt.template, got, tt.expected)
+		}
+	}
+}
+
+func TestResolveTemplate_Numbers(t *testing.T) {
+	values := map[string]any{
+		"id":    json.Number("9223372036854775807"),
+		"big":   float64(1e21),
+		"ratio": 0.25,
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"order {{id}}", "order 9223372036854775807"},
+		{"{{id | json}}", "9223372036854775807"},
+		{"{{big}}", "1000000000000000000000"},
+		{"{{ratio}}", "0.25"},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveTemplate(tt.template, values); got != tt.expected {
+			t.Errorf("ResolveTemplate(%q) = %q, want %q", tt.template, got, tt.expected)
+		}
+	}
+}
+
+func TestEvaluateCondition_JSONNumbers(t *testing.T) {
+	state := &testState{data: map[string]any{
+		"count":   json.Number("42"),
+		"payload": map[string]any{"score": json.Number("0.75")},
+	}}
+	for _, expr := range []string{"count > 40", "count == 42", "payload.score >= 0.5"} {
+		ok, err := evaluateCondition(expr, state)
+		if err != nil || !ok {
+			t.Errorf("evaluateCondition(%q) = %v, %v; want true", expr, ok, err)
+		}
+	}
+}
+
+func TestResolveTemplate_Now(t *testing.T) {
+	got := ResolveTemplate("{{now}}", nil)
+	if _, err := time.Parse(time.RFC3339, got); err != nil {
+		t.Errorf("{{now}} = %q, want an RFC 3339 timestamp", got)
+	}
+	// A value named "now" takes precedence over the built-in.
+	if got := ResolveTemplate("{{now}}", map[string]any{"now": "later"}); got != "later" {
+		t.Errorf("{{now}} with a value = %q, want %q", got, "later")
+	}
+}
+
+func TestResolveTemplateFromState_Filters(t *testing.T) {
+	state := &testState{data: map[string]any{"topic": "go"}}
+	if got := resolveTemplateFromState(`About {{topic | upper}}, by {{author | default:"staff"}}`, state); got != "About GO, by staff" {
+		t.Errorf("got %q", got)
+	}
+}
+
 // testState implements session.State for testing.
 type testState struct {
 	data map[string]any
@@ -163,7 +264,7 @@ func (s *testState) All() iter.Seq2[string, any] {
 }
 
 func TestBuildPromptParts_PlainText(t *testing.T) {
-	parts := buildPromptParts("hello world")
+	parts := buildPromptParts(context.Background(), "hello world")
 	if len(parts) != 1 {
 		t.Fatalf("want 1 part, got %d", len(parts))
 	}
@@ -175,7 +276,7 @@ func TestBuildPromptParts_PlainText(t *testing.T) {
 func TestBuildPromptParts_WithImage(t *testing.T) {
 	// Minimal base64 encoded 1 byte
 	prompt := "before data:image/png;base64,AA== after"
-	parts := buildPromptParts(prompt)
+	parts := buildPromptParts(context.Background(), prompt)
 	if len(parts) != 3 {
 		t.Fatalf("want 3 parts (text, image, text), got %d", len(parts))
 	}
@@ -357,3 +458,516 @@ func TestBuildAgent_Tool_MissingToolConfig(t *testing.T) {
 		t.Errorf("expected 'missing required config field' in error, got: %v", err)
 	}
 }
+
+// hangingLLM blocks until its context is cancelled. With callTool set, the
+// first turn instead asks for that tool.
+type hangingLLM struct{ callTool string }
+
+func (m *hangingLLM) Name() string { return "hanging" }
+func (m *hangingLLM) GenerateContent(ctx context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		if m.callTool != "" && len(req.Contents) == 1 {
+			yield(&adkmodel.LLMResponse{Content: &genai.Content{Role: "model", Parts: []*genai.Part{
+				genai.NewPartFromFunctionCall(m.callTool, map[string]any{}),
+			}}}, nil)
+			return
+		}
+		<-ctx.Done()
+		yield(nil, ctx.Err())
+	}
+}
+
+// hangingTool blocks until its context is cancelled.
+type hangingTool struct{}
+
+func (hangingTool) Name() string                { return "hang" }
+func (hangingTool) Description() string         { return "never returns" }
+func (hangingTool) InputSchema() map[string]any { return map[string]any{"type": "object"} }
+func (hangingTool) Execute(ctx context.Context, _ any) (any, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBuildAgent_LLMNodeTimeout(t *testing.T) {
+	reg := tools.NewRegistry()
+	reg.Register(hangingTool{})
+
+	tests := []struct {
+		name  string
+		llm   *hangingLLM
+		tools []any
+	}{
+		{"llm call", &hangingLLM{}, nil},
+		{"tool call", &hangingLLM{callTool: "hang"}, []any{"hang"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llms := map[string]adkmodel.LLM{"mock": tt.llm}
+			deps := BuildDeps{LLMs: llms, LLMResolver: llmutil.NewMapResolver(llms, nil, ""), ToolReg: reg}
+			cfg := map[string]any{"model": "mock/m", "prompt": "go", "timeout_seconds": 0.05}
+			if tt.tools != nil {
+				cfg["tools"] = tt.tools
+			}
+			wf := &upal.WorkflowDefinition{
+				Name:  "timeout-test",
+				Nodes: []upal.NodeDefinition{{ID: "slow", Type: upal.NodeTypeAgent, Config: cfg}},
+			}
+			dag, err := NewDAGAgent(wf, DefaultRegistry(), deps)
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			sessionSvc := session.InMemoryService()
+			r, _ := runner.New(runner.Config{AppName: wf.Name, Agent: dag, SessionService: sessionSvc})
+			sessionSvc.Create(context.Background(), &session.CreateRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+
+			start := time.Now()
+			var runErr error
+			for _, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("run", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					runErr = err
+				}
+			}
+			if !errors.Is(runErr, ErrNodeTimeout) || !strings.Contains(runErr.Error(), "timeout") {
+				t.Fatalf("err = %v, want node timeout", runErr)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("node ran for %s, timeout not enforced", elapsed)
+			}
+		})
+	}
+}
+
+func TestBuildAgent_EmitsLLMCallPerTurn(t *testing.T) {
+	llm := &sequenceLLM{replies: []string{"draft 555-1234", "final"}}
+	llms := map[string]adkmodel.LLM{"mock": llm}
+	deps := BuildDeps{LLMs: llms, LLMResolver: llmutil.NewMapResolver(llms, nil, "")}
+	wf := &upal.WorkflowDefinition{
+		Name: "llm-call-test",
+		Nodes: []upal.NodeDefinition{{ID: "writer", Type: upal.NodeTypeAgent, Config: map[string]any{
+			"model":      "mock/m",
+			"prompt":     "write",
+			"validators": map[string]any{"must_not_match": []any{`\d{3}-\d{4}`}, "on_failure": "retry"},
+		}}},
+	}
+	dag, err := NewDAGAgent(wf, DefaultRegistry(), deps)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	sessionSvc := session.InMemoryService()
+	r, _ := runner.New(runner.Config{AppName: wf.Name, Agent: dag, SessionService: sessionSvc})
+	sessionSvc.Create(context.Background(), &session.CreateRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+
+	var calls []map[string]any
+	for ev, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("run", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		if call, ok := ev.LLMResponse.CustomMetadata["llm_call"].(map[string]any); ok {
+			if ev.Author != "writer" || !ev.LLMResponse.Partial {
+				t.Errorf("llm_call event author=%q partial=%v, want writer/true", ev.Author, ev.LLMResponse.Partial)
+			}
+			calls = append(calls, call)
+		}
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("llm_call events = %d, want one per turn (2)", len(calls))
+	}
+	for i, call := range calls {
+		if call["attempt"] != i+1 || call["model"] != "m" {
+			t.Errorf("call %d: attempt=%v model=%v, want %d/m", i, call["attempt"], call["model"], i+1)
+		}
+		if _, ok := call["latency_ms"].(int64); !ok {
+			t.Errorf("call %d: latency_ms = %v, want int64", i, call["latency_ms"])
+		}
+	}
+}
+
+func TestBuildAgent_LogitBiasAndCandidates(t *testing.T) {
+	var reqBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{
+			{"message": map[string]any{"role": "assistant", "content": " red "}, "finish_reason": "stop"},
+			{"message": map[string]any{"role": "assistant", "content": "blue"}, "finish_reason": "stop"},
+		}})
+	}))
+	defer server.Close()
+
+	llms := map[string]adkmodel.LLM{"openai": upalmodel.NewOpenAILLM("k", upalmodel.WithOpenAIBaseURL(server.URL))}
+	deps := BuildDeps{LLMs: llms, LLMResolver: llmutil.NewMapResolver(llms, nil, "")}
+	wf := &upal.WorkflowDefinition{
+		Name: "candidates-test",
+		Nodes: []upal.NodeDefinition{{ID: "writer", Type: upal.NodeTypeAgent, Config: map[string]any{
+			"model":      "openai/gpt-4o",
+			"prompt":     "Name a color",
+			"n":          2.0,
+			"logit_bias": map[string]any{"50256": -100.0},
+		}}},
+	}
+	dag, err := NewDAGAgent(wf, DefaultRegistry(), deps)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	sessionSvc := session.InMemoryService()
+	r, _ := runner.New(runner.Config{AppName: wf.Name, Agent: dag, SessionService: sessionSvc})
+	sessionSvc.Create(context.Background(), &session.CreateRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+	for _, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("run", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	}
+
+	if reqBody["n"] != 2.0 {
+		t.Errorf("n = %v, want 2", reqBody["n"])
+	}
+	if bias, _ := reqBody["logit_bias"].(map[string]any); bias["50256"] != -100.0 {
+		t.Errorf("logit_bias = %v", reqBody["logit_bias"])
+	}
+
+	got, _ := sessionSvc.Get(context.Background(), &session.GetRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+	state := got.Session.State()
+	if v, _ := state.Get("writer"); v != "red" {
+		t.Errorf("writer = %v, want first candidate", v)
+	}
+	if v, _ := state.Get("writer.candidates"); !reflect.DeepEqual(v, []any{"red", "blue"}) {
+		t.Errorf("writer.candidates = %#v", v)
+	}
+}
+
+func TestBuildAgent_FewShotExamples(t *testing.T) {
+	examples := []any{
+		map[string]any{"input": "I loved it", "output": "positive"},
+		map[string]any{"input": "Never again", "output": "negative"},
+	}
+	wantRoles := []string{"user", "assistant", "user", "assistant", "user"}
+	wantTexts := []string{"I loved it", "positive", "Never again", "negative", "Classify: it was fine"}
+
+	tests := []struct {
+		name     string
+		model    string
+		newLLM   func(url string) adkmodel.LLM
+		response map[string]any
+	}{
+		{
+			name:  "openai",
+			model: "openai/gpt-4o",
+			newLLM: func(url string) adkmodel.LLM {
+				return upalmodel.NewOpenAILLM("k", upalmodel.WithOpenAIBaseURL(url))
+			},
+			response: map[string]any{"choices": []map[string]any{
+				{"message": map[string]any{"role": "assistant", "content": "neutral"}, "finish_reason": "stop"},
+			}},
+		},
+		{
+			name:  "anthropic",
+			model: "anthropic/claude",
+			newLLM: func(url string) adkmodel.LLM {
+				return upalmodel.NewAnthropicLLM("k", upalmodel.WithAnthropicBaseURL(url))
+			},
+			response: map[string]any{
+				"content":     []map[string]any{{"type": "text", "text": "neutral"}},
+				"stop_reason": "end_turn",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reqBody map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&reqBody)
+				json.NewEncoder(w).Encode(tt.response)
+			}))
+			defer server.Close()
+
+			provider := strings.SplitN(tt.model, "/", 2)[0]
+			llms := map[string]adkmodel.LLM{provider: tt.newLLM(server.URL)}
+			deps := BuildDeps{LLMs: llms, LLMResolver: llmutil.NewMapResolver(llms, nil, "")}
+			wf := &upal.WorkflowDefinition{
+				Name: "few-shot-test",
+				Nodes: []upal.NodeDefinition{{ID: "classify", Type: upal.NodeTypeAgent, Config: map[string]any{
+					"model":         tt.model,
+					"system_prompt": "Answer with one word.",
+					"prompt":        "Classify: it was fine",
+					"examples":      examples,
+				}}},
+			}
+			dag, err := NewDAGAgent(wf, DefaultRegistry(), deps)
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			sessionSvc := session.InMemoryService()
+			r, _ := runner.New(runner.Config{AppName: wf.Name, Agent: dag, SessionService: sessionSvc})
+			sessionSvc.Create(context.Background(), &session.CreateRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+			for _, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("run", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					t.Fatalf("run: %v", err)
+				}
+			}
+
+			var roles, texts []string
+			messages, _ := reqBody["messages"].([]any)
+			for _, m := range messages {
+				msg, _ := m.(map[string]any)
+				if msg["role"] == "system" {
+					if len(roles) > 0 {
+						t.Errorf("system message after conversation turns")
+					}
+					continue
+				}
+				roles = append(roles, fmt.Sprint(msg["role"]))
+				texts = append(texts, messageText(msg["content"]))
+			}
+			if !reflect.DeepEqual(roles, wantRoles) {
+				t.Errorf("roles = %v, want %v", roles, wantRoles)
+			}
+			if !reflect.DeepEqual(texts, wantTexts) {
+				t.Errorf("texts = %q, want %q", texts, wantTexts)
+			}
+		})
+	}
+}
+
+func TestBuildAgent_FewShotExamplesInvalid(t *testing.T) {
+	llms := map[string]adkmodel.LLM{"openai": upalmodel.NewOpenAILLM("k")}
+	deps := BuildDeps{LLMs: llms, LLMResolver: llmutil.NewMapResolver(llms, nil, "")}
+	wf := &upal.WorkflowDefinition{
+		Name: "few-shot-invalid",
+		Nodes: []upal.NodeDefinition{{ID: "classify", Type: upal.NodeTypeAgent, Config: map[string]any{
+			"model":    "openai/gpt-4o",
+			"prompt":   "Classify",
+			"examples": []any{map[string]any{"input": "only input"}},
+		}}},
+	}
+	if _, err := NewDAGAgent(wf, DefaultRegistry(), deps); err == nil || !strings.Contains(err.Error(), "examples[0]") {
+		t.Fatalf("build error = %v, want an examples[0] error", err)
+	}
+}
+
+func TestBuildAgent_NoModelNoProviders(t *testing.T) {
+	deps := BuildDeps{LLMResolver: llmutil.NewMapResolver(nil, nil, "")}
+	wf := &upal.WorkflowDefinition{
+		Name:  "no-providers",
+		Nodes: []upal.NodeDefinition{{ID: "draft", Type: upal.NodeTypeAgent, Config: map[string]any{"prompt": "hi"}}},
+	}
+	if _, err := NewDAGAgent(wf, DefaultRegistry(), deps); !errors.Is(err, upal.ErrNoLLMProvider) {
+		t.Fatalf("build error = %v, want ErrNoLLMProvider", err)
+	}
+}
+
+// messageText flattens a chat message's content, which is either a string or
+// a list of {"type": "text", "text": ...} blocks.
+func messageText(content any) string {
+	if s, ok := content.(string); ok {
+		return s
+	}
+	var sb strings.Builder
+	blocks, _ := content.([]any)
+	for _, b := range blocks {
+		block, _ := b.(map[string]any)
+		if text, ok := block["text"].(string); ok {
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+func TestBuildAgent_InputDefaultAndConstant(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+		state  map[string]any
+		want   any
+	}{
+		{"provided overrides default", map[string]any{"default": "ko"}, map[string]any{"__user_input__lang": "en"}, "en"},
+		{"omitted uses default", map[string]any{"default": "ko"}, nil, "ko"},
+		{"empty uses default", map[string]any{"default": "ko"}, map[string]any{"__user_input__lang": ""}, "ko"},
+		{"omitted without default", map[string]any{}, nil, ""},
+		{"constant ignores provided", map[string]any{"default": "ko", "constant": true}, map[string]any{"__user_input__lang": "en"}, "ko"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &upal.WorkflowDefinition{
+				Name:  "input-defaults",
+				Nodes: []upal.NodeDefinition{{ID: "lang", Type: upal.NodeTypeInput, Config: tt.config}},
+			}
+			dag, err := NewDAGAgent(wf, DefaultRegistry(), BuildDeps{})
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			sessionSvc := session.InMemoryService()
+			r, _ := runner.New(runner.Config{AppName: wf.Name, Agent: dag, SessionService: sessionSvc})
+			sessionSvc.Create(context.Background(), &session.CreateRequest{AppName: wf.Name, UserID: "u", SessionID: "s", State: tt.state})
+
+			var got any
+			for ev, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("run", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					t.Fatalf("run: %v", err)
+				}
+				if ev != nil && ev.Author == "lang" {
+					got = ev.Actions.StateDelta["lang"]
+				}
+			}
+			if got != tt.want {
+				t.Errorf("input value = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// summaryLLM records the request it receives and answers with a fixed summary.
+type summaryLLM struct {
+	reply string
+	req   *adkmodel.LLMRequest
+}
+
+func (m *summaryLLM) Name() string { return "summary" }
+func (m *summaryLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	m.req = req
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText(m.reply, genai.RoleModel)}, nil)
+	}
+}
+
+func TestBuildAgent_SummaryNode(t *testing.T) {
+	history := strings.Repeat("user: what should we ship this week? assistant: the billing fix and the new onboarding flow. ", 20)
+	llm := &summaryLLM{reply: "Shipping the billing fix and new onboarding flow this week."}
+	llms := map[string]adkmodel.LLM{"mock": llm}
+	deps := BuildDeps{LLMs: llms, LLMResolver: llmutil.NewMapResolver(llms, nil, "")}
+	wf := &upal.WorkflowDefinition{
+		Name: "summary-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "history", Type: upal.NodeTypeInput, Config: map[string]any{"default": history, "constant": true}},
+			{ID: "memo", Type: upal.NodeTypeSummary, Config: map[string]any{"model": "mock/m", "source": "{{history}}", "max_words": 30.0}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "history", To: "memo"}},
+	}
+	dag, err := NewDAGAgent(wf, DefaultRegistry(), deps)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	sessionSvc := session.InMemoryService()
+	r, _ := runner.New(runner.Config{AppName: wf.Name, Agent: dag, SessionService: sessionSvc})
+	sessionSvc.Create(context.Background(), &session.CreateRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+	for _, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("run", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	}
+
+	got, _ := sessionSvc.Get(context.Background(), &session.GetRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+	summary, _ := got.Session.State().Get("memo")
+	text, _ := summary.(string)
+	if text != llm.reply || len(strings.Fields(text)) >= len(strings.Fields(history)) {
+		t.Errorf("memo = %q, want the shortened summary", summary)
+	}
+
+	if llm.req == nil {
+		t.Fatal("summary node did not call the model")
+	}
+	if prompt := llm.req.Contents[0].Parts[0].Text; !strings.Contains(prompt, history) {
+		t.Errorf("prompt does not include the resolved history: %q", prompt[:min(len(prompt), 80)])
+	}
+	if sys := llm.req.Config.SystemInstruction.Parts[0].Text; !strings.Contains(sys, "at most 30 words") {
+		t.Errorf("system prompt = %q", sys)
+	}
+	if llm.req.Config.MaxOutputTokens != 376 {
+		t.Errorf("MaxOutputTokens = %d, want 376", llm.req.Config.MaxOutputTokens)
+	}
+}
+
+func TestBuildAgent_SummaryNodeRequiresSource(t *testing.T) {
+	nd := &upal.NodeDefinition{ID: "memo", Type: upal.NodeTypeSummary, Config: map[string]any{"model": "mock/m"}}
+	if _, err := BuildAgent(nd, nil, nil, nil); err == nil || !strings.Contains(err.Error(), "source") {
+		t.Errorf("err = %v, want missing source error", err)
+	}
+}
+
+// repeatToolLLM asks for the same tool call on each of its first `calls`
+// turns, then answers with text.
+type repeatToolLLM struct {
+	tool  string
+	args  map[string]any
+	calls int
+	turn  int
+}
+
+func (m *repeatToolLLM) Name() string { return "repeat" }
+func (m *repeatToolLLM) GenerateContent(_ context.Context, _ *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		m.turn++
+		if m.turn <= m.calls {
+			yield(&adkmodel.LLMResponse{Content: &genai.Content{Role: "model", Parts: []*genai.Part{
+				genai.NewPartFromFunctionCall(m.tool, m.args),
+			}}}, nil)
+			return
+		}
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText("done", "model")}, nil)
+	}
+}
+
+// countingTool counts its executions.
+type countingTool struct{ calls atomic.Int32 }
+
+func (c *countingTool) Name() string                { return "lookup" }
+func (c *countingTool) Description() string         { return "looks something up" }
+func (c *countingTool) InputSchema() map[string]any { return map[string]any{"type": "object"} }
+func (c *countingTool) Execute(context.Context, any) (any, error) {
+	return map[string]any{"answer": c.calls.Add(1)}, nil
+}
+
+func TestBuildAgent_CacheToolResults(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		cache     bool
+		wantCalls int32
+	}{
+		{"cached", true, 1},
+		{"uncached", false, 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &countingTool{}
+			reg := tools.NewRegistry()
+			reg.Register(tool)
+			llms := map[string]adkmodel.LLM{"mock": &repeatToolLLM{tool: "lookup", args: map[string]any{"q": "go"}, calls: 3}}
+			deps := BuildDeps{LLMs: llms, LLMResolver: llmutil.NewMapResolver(llms, nil, ""), ToolReg: reg}
+			wf := &upal.WorkflowDefinition{
+				Name: "cache-test",
+				Nodes: []upal.NodeDefinition{{ID: "researcher", Type: upal.NodeTypeAgent, Config: map[string]any{
+					"model": "mock/m", "prompt": "go", "tools": []any{"lookup"}, "cache_tool_results": tt.cache,
+				}}},
+			}
+			dag, err := NewDAGAgent(wf, DefaultRegistry(), deps)
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			sessionSvc := session.InMemoryService()
+			r, _ := runner.New(runner.Config{AppName: wf.Name, Agent: dag, SessionService: sessionSvc})
+			sessionSvc.Create(context.Background(), &session.CreateRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+
+			var responses []any
+			for ev, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("run", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					t.Fatalf("run: %v", err)
+				}
+				if ev.Content == nil {
+					continue
+				}
+				for _, p := range ev.Content.Parts {
+					if p.FunctionResponse != nil {
+						responses = append(responses, p.FunctionResponse.Response["answer"])
+					}
+				}
+			}
+			if got := tool.calls.Load(); got != tt.wantCalls {
+				t.Errorf("tool executions = %d, want %d", got, tt.wantCalls)
+			}
+			if len(responses) != 3 {
+				t.Fatalf("tool responses = %v, want 3", responses)
+			}
+			if tt.cache && (responses[1] != responses[0] || responses[2] != responses[0]) {
+				t.Errorf("cached responses differ: %v", responses)
+			}
+		})
+	}
+}
@@ -15,6 +15,26 @@ import (
 type nodeOutcome struct {
 	Status upal.NodeStatus
 	Err    error
+	// Absorbed marks a failure recorded under the best_effort failure mode:
+	// default-rule edges still fire so downstream nodes run.
+	Absorbed bool
+}
+
+// NodeErrorSuffix is appended to a node ID to form the state key holding the
+// error of a node that failed under the best_effort failure mode.
+const NodeErrorSuffix = ".error"
+
+// RestoredKeyPrefix prefixes the session state flag marking a node whose
+// output was restored from a checkpoint and must not be executed again.
+const RestoredKeyPrefix = "__restored__"
+
+// restoredOutput returns the checkpointed output of nodeID, if any.
+func restoredOutput(state session.State, nodeID string) (any, bool) {
+	if flag, err := state.Get(RestoredKeyPrefix + nodeID); err != nil || flag != true {
+		return nil, false
+	}
+	output, err := state.Get(nodeID)
+	return output, err == nil
 }
 
 // shouldRun evaluates whether a node should execute based on its incoming
@@ -59,7 +79,8 @@ func shouldRun(d *dag.DAG, nodeID string, outcomes map[string]*nodeOutcome, mu *
 }
 
 // triggerMatches returns true if the edge's trigger rule is satisfied by the
-// parent node's outcome. Default (empty) trigger rule behaves as on_success.
+// parent node's outcome. Default (empty) trigger rule behaves as on_success,
+// except that it also fires after a failure absorbed by best_effort mode.
 func triggerMatches(rule upal.TriggerRule, parent *nodeOutcome) bool {
 	if parent == nil {
 		// Parent completed without recording an outcome (legacy path);
@@ -71,7 +92,9 @@ func triggerMatches(rule upal.TriggerRule, parent *nodeOutcome) bool {
 		return true
 	case upal.TriggerOnFailure:
 		return parent.Status == upal.NodeStatusFailed
-	default: // "" or on_success
+	case "":
+		return parent.Status == upal.NodeStatusCompleted || parent.Absorbed
+	default: // on_success
 		return parent.Status == upal.NodeStatusCompleted
 	}
 }
@@ -82,6 +105,10 @@ func triggerMatches(rule upal.TriggerRule, parent *nodeOutcome) bool {
 // node via the NodeRegistry, and returns a custom agent whose Run function
 // executes the DAG with goroutine fan-out: each node waits for its parent
 // nodes to complete before running.
+//
+// Under the best_effort failure mode a failing node does not stop the run:
+// its output is set to "" and its error stored under "<node>.error", and a
+// failed-status event is emitted in place of the error.
 func NewDAGAgent(wf *upal.WorkflowDefinition, registry *NodeRegistry, deps BuildDeps) (agent.Agent, error) {
 	// 1. Build the DAG from workflow definition.
 	d, err := dag.Build(wf)
@@ -104,6 +131,7 @@ func NewDAGAgent(wf *upal.WorkflowDefinition, registry *NodeRegistry, deps Build
 	}
 
 	topoOrder := d.TopologicalOrder()
+	bestEffort := wf.FailureMode == upal.FailureModeBestEffort
 
 	// 3. Return agent.New() with Run function implementing DAG execution.
 	return agent.New(agent.Config{
@@ -181,6 +209,22 @@ func NewDAGAgent(wf *upal.WorkflowDefinition, registry *NodeRegistry, deps Build
 							return
 						}
 
+						// Nodes checkpointed by an earlier run keep their
+						// restored output and count as completed.
+						if output, ok := restoredOutput(ctx.Session().State(), nodeID); ok {
+							mu.Lock()
+							outcomes[nodeID] = &nodeOutcome{Status: upal.NodeStatusCompleted}
+							mu.Unlock()
+
+							restoredEv := session.NewEvent(ctx.InvocationID())
+							restoredEv.Author = nodeID
+							restoredEv.Branch = ctx.Branch()
+							restoredEv.Actions.StateDelta["__status__"] = string(upal.NodeStatusRestored)
+							restoredEv.Actions.StateDelta[nodeID] = output
+							eventCh <- nodeEvent{restoredEv, nil}
+							return
+						}
+
 						// Evaluate incoming edge conditions.
 						if !shouldRun(d, nodeID, outcomes, &mu, ctx.Session().State()) {
 							mu.Lock()
@@ -212,6 +256,26 @@ func NewDAGAgent(wf *upal.WorkflowDefinition, registry *NodeRegistry, deps Build
 							eventCh <- nodeEvent{ev, nil}
 						}
 
+						if nodeErr != nil && bestEffort && ctx.Err() == nil {
+							mu.Lock()
+							outcomes[nodeID] = &nodeOutcome{Status: upal.NodeStatusFailed, Err: nodeErr, Absorbed: true}
+							mu.Unlock()
+
+							// Set state directly so children see it before the
+							// runner applies the event's delta.
+							state := ctx.Session().State()
+							_ = state.Set(nodeID, "")
+							_ = state.Set(nodeID+NodeErrorSuffix, nodeErr.Error())
+
+							failEv := session.NewEvent(ctx.InvocationID())
+							failEv.Author = nodeID
+							failEv.Branch = ctx.Branch()
+							failEv.Actions.StateDelta["__status__"] = string(upal.NodeStatusFailed)
+							failEv.Actions.StateDelta[nodeID] = ""
+							failEv.Actions.StateDelta[nodeID+NodeErrorSuffix] = nodeErr.Error()
+							eventCh <- nodeEvent{failEv, nil}
+							return
+						}
 						if nodeErr != nil {
 							mu.Lock()
 							outcomes[nodeID] = &nodeOutcome{Status: upal.NodeStatusFailed, Err: nodeErr}
@@ -1,6 +1,7 @@
 package agents
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -25,13 +26,19 @@ func evaluateCondition(expression string, state session.State) (bool, error) {
 	env := make(map[string]any)
 	for k, v := range state.All() {
 		if !strings.HasPrefix(k, "__") {
-			env[k] = v
+			env[k] = exprValue(v)
 		}
 	}
 
-	program, err := expr.Compile(resolved, expr.Env(env))
+	return EvaluateExpression(resolved, env)
+}
+
+// EvaluateExpression evaluates an expr-lang expression against env and
+// reports whether the result is truthy. opts are passed to the compiler after
+// expr.Env.
+func EvaluateExpression(expression string, env map[string]any, opts ...expr.Option) (bool, error) {
+	program, err := expr.Compile(expression, append([]expr.Option{expr.Env(env)}, opts...)...)
 	if err != nil {
-		// If compilation fails (e.g. undefined variables), treat as false.
 		return false, fmt.Errorf("compile condition %q: %w", expression, err)
 	}
 
@@ -43,6 +50,33 @@ func evaluateCondition(expression string, state session.State) (bool, error) {
 	return isTruthy(result), nil
 }
 
+// exprValue converts json.Number values, at any depth, to int64 when they
+// are integers that fit and to float64 otherwise, so conditions can compare
+// them with number literals.
+func exprValue(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return n
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, e := range val {
+			out[k] = exprValue(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, e := range val {
+			out[i] = exprValue(e)
+		}
+		return out
+	}
+	return v
+}
+
 // isTruthy converts a value to a boolean.
 func isTruthy(v any) bool {
 	if v == nil {
@@ -59,6 +93,9 @@ func isTruthy(v any) bool {
 		return val != 0
 	case float64:
 		return val != 0
+	case json.Number:
+		f, err := val.Float64()
+		return err != nil || f != 0
 	default:
 		return true
 	}
@@ -0,0 +1,53 @@
+package agents
+
+import (
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// fewShotExample is one input/output pair from an agent node's examples
+// config:
+//
+//	"examples": [
+//	  {"input": "...", "output": "..."},
+//	  ...
+//	]
+type fewShotExample struct {
+	input  string
+	output string
+}
+
+// parseFewShotExamples reads examples from the node Config map. Returns nil
+// when absent, and an error for entries that are not non-empty input/output
+// string pairs.
+func parseFewShotExamples(cfg map[string]any) ([]fewShotExample, error) {
+	raw, ok := cfg["examples"].([]any)
+	if !ok {
+		return nil, nil
+	}
+	examples := make([]fewShotExample, 0, len(raw))
+	for i, item := range raw {
+		m, _ := item.(map[string]any)
+		input, _ := m["input"].(string)
+		output, _ := m["output"].(string)
+		if input == "" || output == "" {
+			return nil, fmt.Errorf("examples[%d]: input and output must be non-empty strings", i)
+		}
+		examples = append(examples, fewShotExample{input: input, output: output})
+	}
+	return examples, nil
+}
+
+// fewShotContents renders examples as alternating user/model turns, to be
+// sent ahead of the node's prompt.
+func fewShotContents(examples []fewShotExample) []*genai.Content {
+	contents := make([]*genai.Content, 0, 2*len(examples))
+	for _, ex := range examples {
+		contents = append(contents,
+			genai.NewContentFromText(ex.input, genai.RoleUser),
+			genai.NewContentFromText(ex.output, genai.RoleModel),
+		)
+	}
+	return contents
+}
@@ -12,18 +12,23 @@ import (
 )
 
 // InputNodeBuilder creates agents that read user input from session state.
+// Config "default" is used when no value (or an empty string) is supplied;
+// with "constant": true the default is always used and supplied values are
+// ignored, so the input is fixed by the workflow rather than the user.
 type InputNodeBuilder struct{}
 
 func (b *InputNodeBuilder) NodeType() upal.NodeType { return upal.NodeTypeInput }
 
 func (b *InputNodeBuilder) Build(nd *upal.NodeDefinition, _ BuildDeps) (agent.Agent, error) {
-	return buildStateReaderAgent(nd.ID, "__user_input__", "Input node %s")
+	constant, _ := nd.Config["constant"].(bool)
+	return buildStateReaderAgent(nd.ID, "__user_input__", "Input node %s", nd.Config["default"], constant)
 }
 
 // buildStateReaderAgent creates an agent that reads a value from session state
-// using the given key prefix and writes it to the node's output. Used by both
-// InputNodeBuilder and RunInputNodeBuilder.
-func buildStateReaderAgent(nodeID, keyPrefix, descFmt string) (agent.Agent, error) {
+// using the given key prefix and writes it to the node's output. A missing,
+// nil or empty-string value is replaced by def; constant always uses def.
+// Used by both InputNodeBuilder and RunInputNodeBuilder.
+func buildStateReaderAgent(nodeID, keyPrefix, descFmt string, def any, constant bool) (agent.Agent, error) {
 	return agent.New(agent.Config{
 		Name:        nodeID,
 		Description: fmt.Sprintf(descFmt, nodeID),
@@ -31,7 +36,10 @@ func buildStateReaderAgent(nodeID, keyPrefix, descFmt string) (agent.Agent, erro
 			return func(yield func(*session.Event, error) bool) {
 				state := ctx.Session().State()
 				val, err := state.Get(keyPrefix + nodeID)
-				if err != nil || val == nil {
+				if constant || err != nil || val == nil || val == "" {
+					val = def
+				}
+				if val == nil {
 					val = ""
 				}
 
@@ -43,7 +51,7 @@ func buildStateReaderAgent(nodeID, keyPrefix, descFmt string) (agent.Agent, erro
 				event.LLMResponse = adkmodel.LLMResponse{
 					Content: &genai.Content{
 						Role:  "model",
-						Parts: []*genai.Part{genai.NewPartFromText(fmt.Sprintf("%v", val))},
+						Parts: []*genai.Part{genai.NewPartFromText(formatTemplateValue(val))},
 					},
 					TurnComplete: true,
 				}
@@ -2,10 +2,12 @@ package agents
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/soochol/upal/internal/llmutil"
 	upalmodel "github.com/soochol/upal/internal/model"
@@ -17,6 +19,10 @@ import (
 	"google.golang.org/genai"
 )
 
+// ErrNodeTimeout is returned when an agent node exceeds its timeout_seconds.
+// The message contains "timeout" so run-level retry treats it as transient.
+var ErrNodeTimeout = errors.New("node timeout")
+
 // LLMNodeBuilder creates agents that call an LLM with optional tool-use loop.
 type LLMNodeBuilder struct{}
 
@@ -31,6 +37,14 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 	promptTpl, _ := nd.Config["prompt"].(string)
 	outputFmt, _ := nd.Config["output"].(string)
 	outputExtract := parseOutputExtract(nd.Config)
+	validators, err := parseOutputValidators(nd.Config)
+	if err != nil {
+		return nil, fmt.Errorf("node %q: %w", nodeID, err)
+	}
+	examples, err := parseFewShotExamples(nd.Config)
+	if err != nil {
+		return nil, fmt.Errorf("node %q: %w", nodeID, err)
+	}
 
 	var temperature *float32
 	if v, ok := nd.Config["temperature"].(float64); ok {
@@ -47,6 +61,36 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 		topP = &t
 	}
 
+	// n maps to CandidateCount; logit_bias is only honoured by OpenAI-compatible
+	// providers, which read it from the call context.
+	var candidateCount int32
+	if v, ok := nd.Config["n"].(float64); ok && v > 1 {
+		candidateCount = int32(v)
+	}
+	var logitBias map[string]float64
+	if raw, ok := nd.Config["logit_bias"].(map[string]any); ok {
+		logitBias = make(map[string]float64, len(raw))
+		for token, v := range raw {
+			if f, ok := v.(float64); ok {
+				logitBias[token] = f
+			}
+		}
+	}
+
+	var thinking *genai.ThinkingConfig
+	if v, ok := nd.Config["thinking_budget"].(float64); ok && v > 0 {
+		budget := int32(v)
+		include, _ := nd.Config["include_thoughts"].(bool)
+		thinking = &genai.ThinkingConfig{ThinkingBudget: &budget, IncludeThoughts: include}
+	}
+
+	cacheToolResults, _ := nd.Config["cache_tool_results"].(bool)
+
+	var nodeTimeout time.Duration
+	if v, ok := nd.Config["timeout_seconds"].(float64); ok && v > 0 {
+		nodeTimeout = time.Duration(v * float64(time.Second))
+	}
+
 	var imageParams *upalmodel.ImageParams
 	if ratio, ok := nd.Config["aspect_ratio"].(string); ok {
 		imageParams = &upalmodel.ImageParams{}
@@ -96,6 +140,9 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 	if len(funcDecls) > 0 {
 		maxTurns = 10
 	}
+	if validators != nil && validators.onFailure == "retry" {
+		maxTurns += validators.maxRetries
+	}
 
 	return agent.New(agent.Config{
 		Name:        nodeID,
@@ -106,9 +153,10 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 
 				resolvedPrompt := resolveTemplateFromState(promptTpl, state)
 
-				contents := []*genai.Content{
-					{Role: genai.RoleUser, Parts: buildPromptParts(resolvedPrompt)},
-				}
+				// Few-shot examples go before the prompt as earlier turns; the
+				// system instruction travels separately in genCfg.
+				contents := append(fewShotContents(examples),
+					&genai.Content{Role: genai.RoleUser, Parts: buildPromptParts(ctx, resolvedPrompt)})
 
 				genCfg := &genai.GenerateContentConfig{
 					SystemInstruction: genai.NewContentFromText(systemPrompt, genai.RoleUser),
@@ -122,6 +170,12 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 				if topP != nil {
 					genCfg.TopP = topP
 				}
+				if candidateCount > 0 {
+					genCfg.CandidateCount = candidateCount
+				}
+				if thinking != nil {
+					genCfg.ThinkingConfig = thinking
+				}
 				var allTools []*genai.Tool
 				allTools = append(allTools, nativeTools...)
 				if len(funcDecls) > 0 {
@@ -131,16 +185,42 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 					genCfg.Tools = allTools
 				}
 
-				var llmCtx context.Context = ctx
+				// runCtx bounds the whole turn loop (LLM calls and tool execution)
+				// when the node sets timeout_seconds.
+				var runCtx context.Context = ctx
+				if nodeTimeout > 0 {
+					var cancel context.CancelFunc
+					runCtx, cancel = context.WithTimeout(ctx, nodeTimeout)
+					defer cancel()
+				}
+				timedOut := func() bool {
+					return nodeTimeout > 0 && ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded)
+				}
+				timeoutErr := func() error {
+					return fmt.Errorf("node %q: %w after %s", nodeID, ErrNodeTimeout, nodeTimeout)
+				}
+
+				llmCtx := runCtx
 				if imageParams != nil {
 					llmCtx = upalmodel.WithImageParams(llmCtx, *imageParams)
 				}
+				if len(logitBias) > 0 {
+					llmCtx = upalmodel.WithLogitBias(llmCtx, logitBias)
+				}
 				if nodeLogFn := nodeLogFuncFromContext(ctx); nodeLogFn != nil {
 					llmCtx = upalmodel.WithLogFunc(llmCtx, upalmodel.LogFunc(func(msg string) {
 						nodeLogFn(nodeID, msg)
 					}))
 				}
 
+				// The cache lives for this execution of the node, so results
+				// are only reused within the run.
+				var toolCache *tools.ResultCache
+				if cacheToolResults {
+					toolCache = tools.NewResultCache()
+				}
+
+				validationRetries := 0
 				for turn := 0; turn < maxTurns; turn++ {
 					req := &adkmodel.LLMRequest{
 						Model:    modelName,
@@ -148,14 +228,36 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 						Contents: contents,
 					}
 
+					if timedOut() {
+						yield(nil, timeoutErr())
+						return
+					}
+
 					var resp *adkmodel.LLMResponse
+					var callErr error
+					callStart := time.Now()
 					for r, err := range named.GenerateContent(llmCtx, req, false) {
 						if err != nil {
-							yield(nil, fmt.Errorf("LLM call failed for node %q: %w", nodeID, err))
-							return
+							callErr = err
+							break
 						}
 						resp = r
 					}
+					if !yield(llmCallEvent(ctx, nodeID, modelName, turn+1, time.Since(callStart), resp, callErr), nil) {
+						return
+					}
+					if callErr != nil {
+						if timedOut() {
+							yield(nil, timeoutErr())
+							return
+						}
+						yield(nil, fmt.Errorf("LLM call failed for node %q: %w", nodeID, callErr))
+						return
+					}
+					if timedOut() {
+						yield(nil, timeoutErr())
+						return
+					}
 
 					if resp == nil || resp.Content == nil {
 						yield(nil, fmt.Errorf("empty LLM response for node %q", nodeID))
@@ -172,6 +274,27 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 					if len(toolCalls) == 0 {
 						rawResult := strings.TrimSpace(llmutil.ExtractContentSavingAudio(resp, outputDir))
 						result := applyOutputExtract(outputExtract, rawResult)
+
+						if validators != nil {
+							if violations := validators.check(result); len(violations) > 0 {
+								switch {
+								case validators.onFailure == "retry" && validationRetries < validators.maxRetries:
+									validationRetries++
+									slog.Info("output failed validation, retrying", "node", nodeID, "attempt", validationRetries, "violations", violations)
+									contents = append(contents, resp.Content,
+										genai.NewContentFromText(correctionPrompt(violations), genai.RoleUser))
+									continue
+								case validators.onFailure == "flag":
+									slog.Warn("output failed validation", "node", nodeID, "violations", violations)
+									if nodeLogFn := nodeLogFuncFromContext(ctx); nodeLogFn != nil {
+										nodeLogFn(nodeID, "output failed validation: "+strings.Join(violations, "; "))
+									}
+								default:
+									yield(nil, fmt.Errorf("node %q: output failed validation: %s", nodeID, strings.Join(violations, "; ")))
+									return
+								}
+							}
+						}
 						_ = state.Set(nodeID, result)
 
 						event := session.NewEvent(ctx.InvocationID())
@@ -184,6 +307,16 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 							UsageMetadata: resp.UsageMetadata,
 						}
 						event.Actions.StateDelta[nodeID] = result
+						// With n > 1 the node value is the first completion;
+						// all of them are exposed as "<node>.candidates".
+						if raw, ok := resp.CustomMetadata[upalmodel.CandidatesMetadataKey].([]string); ok && len(raw) > 1 {
+							candidates := make([]any, len(raw))
+							for i, c := range raw {
+								candidates[i] = applyOutputExtract(outputExtract, strings.TrimSpace(c))
+							}
+							_ = state.Set(nodeID+".candidates", candidates)
+							event.Actions.StateDelta[nodeID+".candidates"] = candidates
+						}
 						yield(event, nil)
 						return
 					}
@@ -197,7 +330,11 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 					}
 
 					contents = append(contents, resp.Content)
-					toolRespContent := executeToolCalls(ctx, toolCalls, upalTools)
+					toolRespContent := executeToolCalls(runCtx, toolCalls, upalTools, toolCache)
+					if timedOut() {
+						yield(nil, timeoutErr())
+						return
+					}
 					contents = append(contents, toolRespContent)
 
 					toolRespEvent := session.NewEvent(ctx.InvocationID())
@@ -215,9 +352,38 @@ func (b *LLMNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.A
 	})
 }
 
-// executeToolCalls delegates to the shared tools.ExecuteToolCalls helper.
-func executeToolCalls(ctx context.Context, calls []*genai.FunctionCall, upalTools map[string]tools.Tool) *genai.Content {
-	resp := tools.ExecuteToolCalls(ctx, calls, upalTools)
+// llmCallEvent reports one model call made by an agent node. It is partial so
+// the runner forwards it without appending it to the session history.
+func llmCallEvent(ctx agent.InvocationContext, nodeID, model string, attempt int, latency time.Duration, resp *adkmodel.LLMResponse, err error) *session.Event {
+	call := map[string]any{
+		"model":      model,
+		"attempt":    attempt,
+		"latency_ms": latency.Milliseconds(),
+	}
+	if resp != nil && resp.UsageMetadata != nil {
+		call["tokens"] = map[string]any{
+			"input":  resp.UsageMetadata.PromptTokenCount,
+			"output": resp.UsageMetadata.CandidatesTokenCount,
+			"total":  resp.UsageMetadata.TotalTokenCount,
+		}
+	}
+	if err != nil {
+		call["error"] = err.Error()
+	}
+	event := session.NewEvent(ctx.InvocationID())
+	event.Author = nodeID
+	event.Branch = ctx.Branch()
+	event.LLMResponse = adkmodel.LLMResponse{
+		Partial:        true,
+		CustomMetadata: map[string]any{"llm_call": call},
+	}
+	return event
+}
+
+// executeToolCalls delegates to the shared tools.ExecuteToolCallsCached
+// helper; cache is nil unless the node sets cache_tool_results.
+func executeToolCalls(ctx context.Context, calls []*genai.FunctionCall, upalTools map[string]tools.Tool, cache *tools.ResultCache) *genai.Content {
+	resp := tools.ExecuteToolCallsCached(ctx, calls, upalTools, cache)
 	if resp != nil {
 		return resp
 	}
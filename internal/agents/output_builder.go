@@ -23,7 +23,11 @@ func (b *OutputNodeBuilder) NodeType() upal.NodeType { return upal.NodeTypeOutpu
 func (b *OutputNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.Agent, error) {
 	nodeID := nd.ID
 	promptTpl, _ := nd.Config["prompt"].(string)
-	formatter := output.NewFormatter(nd.Config, deps.LLMResolver, deps.HTMLLayoutPrompt)
+	formatter := output.NewFormatter(nd.Config, deps.LLMResolver, output.Layout{
+		BasePrompt:   deps.HTMLLayoutPrompt,
+		DefaultModel: deps.LayoutModel,
+		Disabled:     deps.LayoutDisabled,
+	})
 
 	return agent.New(agent.Config{
 		Name:        nodeID,
@@ -82,7 +86,7 @@ func collectOutputContent(promptTpl, nodeID string, state session.State) string
 		if err != nil || v == nil {
 			continue
 		}
-		parts = append(parts, fmt.Sprintf("%v", v))
+		parts = append(parts, formatTemplateValue(v))
 	}
 
 	return strings.Join(parts, "\n\n")
@@ -0,0 +1,200 @@
+// internal/agents/output_validate.go
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// outputValidators holds the parsed validators config for an agent node:
+//
+//	"validators": {
+//	  "must_match":     ["regex", ...],
+//	  "must_not_match": ["regex", ...],
+//	  "json_schema":    {...},
+//	  "max_length":     2000,
+//	  "on_failure":     "fail" | "retry" | "flag",
+//	  "max_retries":    1
+//	}
+type outputValidators struct {
+	mustMatch    []*regexp.Regexp
+	mustNotMatch []*regexp.Regexp
+	schema       map[string]any
+	maxLength    int
+	onFailure    string // "fail" (default) | "retry" | "flag"
+	maxRetries   int    // retry mode only; defaults to 1
+}
+
+// parseOutputValidators reads validators from the node Config map.
+// Returns nil when absent, and an error for invalid patterns or options.
+func parseOutputValidators(cfg map[string]any) (*outputValidators, error) {
+	raw, ok := cfg["validators"].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	v := &outputValidators{onFailure: "fail", maxRetries: 1}
+
+	compile := func(key string) ([]*regexp.Regexp, error) {
+		list, _ := raw[key].([]any)
+		res := make([]*regexp.Regexp, 0, len(list))
+		for _, p := range list {
+			s, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("validators.%s: patterns must be strings", key)
+			}
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return nil, fmt.Errorf("validators.%s: %w", key, err)
+			}
+			res = append(res, re)
+		}
+		return res, nil
+	}
+	var err error
+	if v.mustMatch, err = compile("must_match"); err != nil {
+		return nil, err
+	}
+	if v.mustNotMatch, err = compile("must_not_match"); err != nil {
+		return nil, err
+	}
+	v.schema, _ = raw["json_schema"].(map[string]any)
+	if n, ok := raw["max_length"].(float64); ok {
+		v.maxLength = int(n)
+	}
+	if mode, ok := raw["on_failure"].(string); ok && mode != "" {
+		switch mode {
+		case "fail", "retry", "flag":
+			v.onFailure = mode
+		default:
+			return nil, fmt.Errorf("validators.on_failure: unknown mode %q", mode)
+		}
+	}
+	if n, ok := raw["max_retries"].(float64); ok && n >= 0 {
+		v.maxRetries = int(n)
+	}
+	return v, nil
+}
+
+// check returns a description of every rule the output violates.
+func (v *outputValidators) check(output string) []string {
+	var violations []string
+	for _, re := range v.mustMatch {
+		if !re.MatchString(output) {
+			violations = append(violations, fmt.Sprintf("output must match /%s/", re))
+		}
+	}
+	for _, re := range v.mustNotMatch {
+		if loc := re.FindString(output); loc != "" {
+			violations = append(violations, fmt.Sprintf("output must not match /%s/ (found %q)", re, loc))
+		}
+	}
+	if v.maxLength > 0 && len([]rune(output)) > v.maxLength {
+		violations = append(violations, fmt.Sprintf("output is %d characters, max is %d", len([]rune(output)), v.maxLength))
+	}
+	if v.schema != nil {
+		var doc any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &doc); err != nil {
+			violations = append(violations, "output is not valid JSON")
+		} else {
+			violations = append(violations, checkJSONSchema(v.schema, doc, "$")...)
+		}
+	}
+	return violations
+}
+
+// correctionPrompt asks the model to fix the listed violations.
+func correctionPrompt(violations []string) string {
+	return "Your previous response failed validation:\n- " + strings.Join(violations, "\n- ") +
+		"\n\nRespond again with a corrected answer that satisfies every rule."
+}
+
+// checkJSONSchema validates doc against the commonly used subset of JSON
+// Schema: type, enum, required, properties, items, minLength/maxLength and
+// minimum/maximum.
+func checkJSONSchema(schema map[string]any, doc any, path string) []string {
+	var errs []string
+	if t, ok := schema["type"].(string); ok && !jsonTypeMatches(t, doc) {
+		return []string{fmt.Sprintf("%s: expected %s", path, t)}
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(doc) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s: value not in enum", path))
+		}
+	}
+	switch d := doc.(type) {
+	case map[string]any:
+		if req, ok := schema["required"].([]any); ok {
+			for _, r := range req {
+				if key, _ := r.(string); key != "" {
+					if _, present := d[key]; !present {
+						errs = append(errs, fmt.Sprintf("%s: missing required field %q", path, key))
+					}
+				}
+			}
+		}
+		if props, ok := schema["properties"].(map[string]any); ok {
+			for key, sub := range props {
+				subSchema, _ := sub.(map[string]any)
+				if val, present := d[key]; present && subSchema != nil {
+					errs = append(errs, checkJSONSchema(subSchema, val, path+"."+key)...)
+				}
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range d {
+				errs = append(errs, checkJSONSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case string:
+		if n, ok := schema["minLength"].(float64); ok && len([]rune(d)) < int(n) {
+			errs = append(errs, fmt.Sprintf("%s: shorter than %d characters", path, int(n)))
+		}
+		if n, ok := schema["maxLength"].(float64); ok && len([]rune(d)) > int(n) {
+			errs = append(errs, fmt.Sprintf("%s: longer than %d characters", path, int(n)))
+		}
+	case float64:
+		if n, ok := schema["minimum"].(float64); ok && d < n {
+			errs = append(errs, fmt.Sprintf("%s: below minimum %v", path, n))
+		}
+		if n, ok := schema["maximum"].(float64); ok && d > n {
+			errs = append(errs, fmt.Sprintf("%s: above maximum %v", path, n))
+		}
+	}
+	return errs
+}
+
+func jsonTypeMatches(t string, doc any) bool {
+	switch t {
+	case "object":
+		_, ok := doc.(map[string]any)
+		return ok
+	case "array":
+		_, ok := doc.([]any)
+		return ok
+	case "string":
+		_, ok := doc.(string)
+		return ok
+	case "number":
+		_, ok := doc.(float64)
+		return ok
+	case "integer":
+		f, ok := doc.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := doc.(bool)
+		return ok
+	case "null":
+		return doc == nil
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+// internal/agents/output_validate_test.go
+package agents
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/soochol/upal/internal/llmutil"
+	"github.com/soochol/upal/internal/upal"
+	"google.golang.org/adk/agent"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// sequenceLLM replies with each of its texts in turn and records every
+// request's final user message.
+type sequenceLLM struct {
+	mu       sync.Mutex
+	replies  []string
+	messages []string
+}
+
+func (m *sequenceLLM) Name() string { return "sequence" }
+func (m *sequenceLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	m.mu.Lock()
+	last := req.Contents[len(req.Contents)-1]
+	m.messages = append(m.messages, last.Parts[0].Text)
+	reply := m.replies[0]
+	if len(m.replies) > 1 {
+		m.replies = m.replies[1:]
+	}
+	m.mu.Unlock()
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText(reply, genai.RoleModel)}, nil)
+	}
+}
+
+// runSingleNode runs a one-node workflow and returns the node's final state value.
+func runSingleNode(t *testing.T, nd upal.NodeDefinition, llm adkmodel.LLM) (any, error) {
+	t.Helper()
+	llms := map[string]adkmodel.LLM{"mock": llm}
+	deps := BuildDeps{LLMs: llms, LLMResolver: llmutil.NewMapResolver(llms, nil, "")}
+	wf := &upal.WorkflowDefinition{Name: "validate-test", Nodes: []upal.NodeDefinition{nd}}
+	dag, err := NewDAGAgent(wf, DefaultRegistry(), deps)
+	if err != nil {
+		return nil, err
+	}
+	sessionSvc := session.InMemoryService()
+	r, _ := runner.New(runner.Config{AppName: wf.Name, Agent: dag, SessionService: sessionSvc})
+	sessionSvc.Create(context.Background(), &session.CreateRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+
+	var runErr error
+	for _, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("run", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			runErr = err
+		}
+	}
+	resp, err := sessionSvc.Get(context.Background(), &session.GetRequest{AppName: wf.Name, UserID: "u", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	val, _ := resp.Session.State().Get(nd.ID)
+	return val, runErr
+}
+
+func TestOutputValidators_MustNotMatchRetriesThenPasses(t *testing.T) {
+	llm := &sequenceLLM{replies: []string{"Call me at 555-1234 for details", "Contact us via the website"}}
+	nd := upal.NodeDefinition{ID: "writer", Type: upal.NodeTypeAgent, Config: map[string]any{
+		"model":  "mock/m",
+		"prompt": "Write a contact line",
+		"validators": map[string]any{
+			"must_not_match": []any{`\d{3}-\d{4}`},
+			"on_failure":     "retry",
+			"max_retries":    2.0,
+		},
+	}}
+
+	out, err := runSingleNode(t, nd, llm)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if out != "Contact us via the website" {
+		t.Errorf("output = %v, want the corrected reply", out)
+	}
+	if len(llm.messages) != 2 {
+		t.Fatalf("LLM calls = %d, want 2", len(llm.messages))
+	}
+	if !strings.Contains(llm.messages[1], "failed validation") || !strings.Contains(llm.messages[1], `\d{3}-\d{4}`) {
+		t.Errorf("retry message = %q, want correction prompt naming the pattern", llm.messages[1])
+	}
+}
+
+func TestOutputValidators_FailsWhenRetriesExhausted(t *testing.T) {
+	llm := &sequenceLLM{replies: []string{"way too long output"}}
+	nd := upal.NodeDefinition{ID: "writer", Type: upal.NodeTypeAgent, Config: map[string]any{
+		"model":      "mock/m",
+		"prompt":     "Be brief",
+		"validators": map[string]any{"max_length": 5.0, "on_failure": "retry"},
+	}}
+
+	_, err := runSingleNode(t, nd, llm)
+	if err == nil || !strings.Contains(err.Error(), "failed validation") {
+		t.Fatalf("err = %v, want validation failure", err)
+	}
+	if len(llm.messages) != 2 {
+		t.Errorf("LLM calls = %d, want 1 attempt + 1 retry", len(llm.messages))
+	}
+}
+
+func TestOutputValidators_Check(t *testing.T) {
+	v, err := parseOutputValidators(map[string]any{"validators": map[string]any{
+		"must_match": []any{`^\{`},
+		"json_schema": map[string]any{
+			"type":     "object",
+			"required": []any{"title", "score"},
+			"properties": map[string]any{
+				"score": map[string]any{"type": "integer", "minimum": 0.0, "maximum": 10.0},
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := v.check(`{"title": "ok", "score": 7}`); len(got) != 0 {
+		t.Errorf("valid output flagged: %v", got)
+	}
+	if got := v.check(`{"score": 11}`); len(got) != 2 {
+		t.Errorf("violations = %v, want missing title and score above maximum", got)
+	}
+	if got := v.check("not json"); len(got) != 2 {
+		t.Errorf("violations = %v, want must_match and invalid JSON", got)
+	}
+
+	if _, err := parseOutputValidators(map[string]any{"validators": map[string]any{"must_match": []any{"("}}}); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
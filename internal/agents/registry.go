@@ -32,6 +32,8 @@ type BuildDeps struct {
 	ToolReg          *tools.Registry
 	OutputDir        string // directory for saving media outputs (audio, video)
 	HTMLLayoutPrompt string // base prompt for HTML output formatting
+	LayoutModel      string // default layout model for output nodes without one
+	LayoutDisabled   bool   // output nodes return plain text instead of HTML layouts
 }
 
 // NodeRegistry maps node types to their builders.
@@ -65,7 +67,7 @@ func (r *NodeRegistry) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.Age
 }
 
 // DefaultRegistry returns a NodeRegistry pre-loaded with the built-in
-// node types (input, output, agent, tool). Useful for tests and backward compat.
+// node types (input, output, agent, tool, summary). Useful for tests and backward compat.
 func DefaultRegistry() *NodeRegistry {
 	r := NewNodeRegistry()
 	r.Register(&InputNodeBuilder{})
@@ -73,5 +75,6 @@ func DefaultRegistry() *NodeRegistry {
 	r.Register(&OutputNodeBuilder{})
 	r.Register(&LLMNodeBuilder{})
 	r.Register(&ToolNodeBuilder{})
+	r.Register(&SummaryNodeBuilder{})
 	return r
 }
@@ -12,5 +12,5 @@ type RunInputNodeBuilder struct{}
 func (b *RunInputNodeBuilder) NodeType() upal.NodeType { return upal.NodeTypeRunInput }
 
 func (b *RunInputNodeBuilder) Build(nd *upal.NodeDefinition, _ BuildDeps) (agent.Agent, error) {
-	return buildStateReaderAgent(nd.ID, "__run_input__", "Run input node %s — receives pipeline brief")
+	return buildStateReaderAgent(nd.ID, "__run_input__", "Run input node %s — receives pipeline brief", nil, false)
 }
@@ -0,0 +1,89 @@
+package agents
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+
+	"github.com/soochol/upal/internal/dag"
+	"github.com/soochol/upal/internal/upal"
+	"google.golang.org/adk/session"
+)
+
+// SimulateTemplates resolves each node's templates in topological order for
+// the given inputs (keyed by input node ID) without executing anything.
+// Input nodes take their values as at run time; agent, summary, tool and
+// asset nodes, whose real output needs an LLM, tool or file, contribute a
+// "[<node_id> output]" placeholder to the {{refs}} of downstream nodes.
+// Edge conditions are not evaluated, so every node is listed.
+func SimulateTemplates(wf *upal.WorkflowDefinition, inputs map[string]any) ([]upal.ResolvedNodeTemplate, error) {
+	d, err := dag.Build(wf)
+	if err != nil {
+		return nil, err
+	}
+	state := mapState{}
+	out := make([]upal.ResolvedNodeTemplate, 0, len(wf.Nodes))
+	for _, id := range d.TopologicalOrder() {
+		nd := d.Node(id)
+		res := upal.ResolvedNodeTemplate{NodeID: id, Type: nd.Type}
+		placeholder := fmt.Sprintf("[%s output]", id)
+
+		switch nd.Type {
+		case upal.NodeTypeInput, upal.NodeTypeRunInput:
+			val := inputs[id]
+			if constant, _ := nd.Config["constant"].(bool); constant || val == nil || val == "" {
+				val = nd.Config["default"]
+			}
+			if val == nil {
+				val = ""
+			}
+			res.Output = val
+		case upal.NodeTypeAgent:
+			promptTpl, _ := nd.Config["prompt"].(string)
+			res.Prompt = resolveTemplateFromState(promptTpl, state)
+			res.SystemPrompt, _ = nd.Config["system_prompt"].(string)
+			res.Output = placeholder
+		case upal.NodeTypeSummary:
+			cfg, err := summaryLLMConfig(nd)
+			if err != nil {
+				return nil, err
+			}
+			res.Prompt = resolveTemplateFromState(cfg["prompt"].(string), state)
+			res.SystemPrompt = cfg["system_prompt"].(string)
+			res.Output = placeholder
+		case upal.NodeTypeTool:
+			input, _ := nd.Config["input"].(map[string]any)
+			res.Input = resolveInputFromState(input, state)
+			res.Output = placeholder
+		case upal.NodeTypeOutput:
+			promptTpl, _ := nd.Config["prompt"].(string)
+			content := collectOutputContent(promptTpl, id, state)
+			res.Prompt = content
+			res.Output = content
+		default:
+			res.Output = placeholder
+		}
+		state[id] = res.Output
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// mapState is a session.State over a plain map, for resolving templates
+// outside a session.
+type mapState map[string]any
+
+func (m mapState) Get(key string) (any, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return v, nil
+}
+
+func (m mapState) Set(key string, val any) error {
+	m[key] = val
+	return nil
+}
+
+func (m mapState) All() iter.Seq2[string, any] { return maps.All(m) }
@@ -0,0 +1,80 @@
+package agents
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/soochol/upal/internal/upal"
+	"google.golang.org/adk/agent"
+)
+
+// defaultSummaryWords is the target summary length when max_words is unset.
+const defaultSummaryWords = 150
+
+// The output token budget is a backstop against runaway output, not the
+// length limit: the system prompt sets that. Words in languages such as
+// Korean or Japanese often take four or more tokens, so the budget is
+// generous per word, plus headroom for short targets.
+const (
+	summaryTokensPerWord = 4
+	summaryTokenHeadroom = 256
+)
+
+const summarySystemPrompt = `You compress long conversations and accumulated workflow context into a summary that later steps read instead of the full text.
+Keep decisions, facts, names, numbers, open questions and the user's stated preferences. Drop greetings, repetition and filler.
+Write the summary in the language of the source text. Respond with the summary only, in at most %d words.`
+
+// SummaryNodeBuilder creates memory nodes that compress accumulated state
+// (typically conversation history) into a short summary stored under the
+// node ID, so downstream prompts can reference {{node_id}} instead of the
+// full history. Config:
+//
+//	"source":       template for the text to summarize, e.g. "{{history}}" (required)
+//	"max_words":    target summary length (default 150)
+//	"instructions": optional extra guidance appended to the system prompt
+//	"model":        model ID, as for agent nodes
+//
+// It runs through LLMNodeBuilder with a summarization system prompt that
+// enforces max_words, and a generous output token budget derived from it
+// unless the node sets max_tokens.
+type SummaryNodeBuilder struct{}
+
+func (b *SummaryNodeBuilder) NodeType() upal.NodeType { return upal.NodeTypeSummary }
+
+func (b *SummaryNodeBuilder) Build(nd *upal.NodeDefinition, deps BuildDeps) (agent.Agent, error) {
+	cfg, err := summaryLLMConfig(nd)
+	if err != nil {
+		return nil, err
+	}
+	inner := *nd
+	inner.Config = cfg
+	return (&LLMNodeBuilder{}).Build(&inner, deps)
+}
+
+// summaryLLMConfig derives the agent-node config a summary node runs with.
+func summaryLLMConfig(nd *upal.NodeDefinition) (map[string]any, error) {
+	source, _ := nd.Config["source"].(string)
+	if source == "" {
+		return nil, fmt.Errorf("summary node %q: missing required config field \"source\"", nd.ID)
+	}
+	maxWords := defaultSummaryWords
+	if v, ok := nd.Config["max_words"].(float64); ok && v > 0 {
+		maxWords = int(v)
+	}
+
+	systemPrompt := fmt.Sprintf(summarySystemPrompt, maxWords)
+	if extra, _ := nd.Config["instructions"].(string); extra != "" {
+		systemPrompt += "\n\n" + extra
+	}
+
+	cfg := maps.Clone(nd.Config)
+	for _, k := range []string{"source", "max_words", "instructions", "tools", "n", "output", "output_extract"} {
+		delete(cfg, k)
+	}
+	cfg["system_prompt"] = systemPrompt
+	cfg["prompt"] = "Summarize the following:\n\n" + source
+	if _, ok := cfg["max_tokens"]; !ok {
+		cfg["max_tokens"] = float64(maxWords*summaryTokensPerWord + summaryTokenHeadroom)
+	}
+	return cfg, nil
+}
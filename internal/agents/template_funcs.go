@@ -0,0 +1,158 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templateFilterPattern matches one "| name" or `| name:"arg"` segment of a
+// placeholder's pipeline.
+var templateFilterPattern = regexp.MustCompile(`\|\s*(\w+)(?::"((?:[^"\\]|\\.)*)")?`)
+
+// templateFilter is one parsed pipeline step.
+type templateFilter struct {
+	name   string
+	arg    string
+	hasArg bool
+}
+
+// renderPlaceholder resolves one templatePattern match. Filters apply left to
+// right; unknown filters pass the value through. {{now}} falls back to the
+// current UTC time when no value named "now" exists. A placeholder whose
+// value is still missing after its filters is left as-is.
+func renderPlaceholder(match string, lookup func(key string) (any, bool)) string {
+	m := templatePattern.FindStringSubmatch(match)
+	if m == nil {
+		return match
+	}
+	val, ok := lookup(m[1])
+	if ok && val == nil {
+		ok = false
+	}
+	if !ok && m[1] == "now" {
+		val, ok = time.Now().UTC().Format(time.RFC3339), true
+	}
+
+	for _, f := range parseTemplateFilters(m[2]) {
+		val, ok = applyTemplateFilter(f, val, ok)
+	}
+	if !ok {
+		return match
+	}
+	return formatTemplateValue(val)
+}
+
+// formatTemplateValue renders a resolved value. Floats print in plain
+// decimal, so a large integer that was decoded as float64 does not turn into
+// scientific notation; json.Number prints its literal digits.
+func formatTemplateValue(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+func parseTemplateFilters(pipeline string) []templateFilter {
+	var filters []templateFilter
+	for _, m := range templateFilterPattern.FindAllStringSubmatchIndex(pipeline, -1) {
+		f := templateFilter{name: pipeline[m[2]:m[3]]}
+		if m[4] >= 0 {
+			f.arg = strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(pipeline[m[4]:m[5]])
+			f.hasArg = true
+		}
+		filters = append(filters, f)
+	}
+	return filters
+}
+
+// applyTemplateFilter runs one built-in filter. ok reports whether val holds
+// a resolved value.
+func applyTemplateFilter(f templateFilter, val any, ok bool) (any, bool) {
+	switch f.name {
+	case "default":
+		if !ok || val == "" {
+			return f.arg, true
+		}
+	case "upper":
+		if ok {
+			return strings.ToUpper(formatTemplateValue(val)), true
+		}
+	case "lower":
+		if ok {
+			return strings.ToLower(formatTemplateValue(val)), true
+		}
+	case "json":
+		if ok {
+			b, err := json.Marshal(val)
+			if err != nil {
+				return val, true
+			}
+			return string(b), true
+		}
+	}
+	return val, ok
+}
+
+// IsJSONTemplate reports whether template is a JSON document once its
+// placeholders are filled, whether they sit inside string literals or stand
+// in for whole values.
+func IsJSONTemplate(template string) bool {
+	return json.Valid([]byte(templatePattern.ReplaceAllString(template, "null")))
+}
+
+// ResolveJSONTemplate is ResolveTemplate for JSON templates (see
+// IsJSONTemplate). A value substituted inside a string literal is escaped as
+// string content; elsewhere it is inserted as-is when it is a JSON value and
+// as a quoted string otherwise. Either way the values cannot end the literal
+// or add fields to the document.
+func ResolveJSONTemplate(template string, values map[string]any) string {
+	lookup := func(key string) (any, bool) {
+		val, ok := values[key]
+		return val, ok
+	}
+	var sb strings.Builder
+	inString, escaped := false, false
+	last := 0
+	for _, loc := range templatePattern.FindAllStringIndex(template, -1) {
+		for _, c := range template[last:loc[0]] {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\' && inString:
+				escaped = true
+			case c == '"':
+				inString = !inString
+			}
+		}
+		sb.WriteString(template[last:loc[0]])
+		last = loc[1]
+
+		match := template[loc[0]:loc[1]]
+		rendered := renderPlaceholder(match, lookup)
+		switch {
+		case rendered == match:
+			sb.WriteString(match)
+		case inString:
+			quoted, _ := json.Marshal(rendered)
+			sb.Write(quoted[1 : len(quoted)-1])
+		case json.Valid([]byte(rendered)):
+			sb.WriteString(rendered)
+		default:
+			quoted, _ := json.Marshal(rendered)
+			sb.Write(quoted)
+		}
+	}
+	sb.WriteString(template[last:])
+	return sb.String()
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"maps"
+	"mime"
+	"slices"
 	"strings"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2asrv"
 	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
 	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/services"
 	"github.com/soochol/upal/internal/upal"
 	"github.com/soochol/upal/internal/upal/ports"
 )
@@ -18,11 +23,19 @@ import (
 // as A2A-callable agents. It delegates workflow execution to WorkflowExecutor.
 type upalA2AExecutor struct {
 	workflowSvc ports.WorkflowExecutor
+	runHistory  ports.RunHistoryPort
+	runManager  ports.RunManagerPort
+	// floatNumbers mirrors Server.SetFloatNumbers for JSON text inputs.
+	floatNumbers bool
 }
 
-func (e *upalA2AExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+// a2aTriggerType is the trigger type recorded on runs started over A2A. Their
+// trigger ref is the A2A task ID.
+const a2aTriggerType = "a2a"
+
+func (e *upalA2AExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) (err error) {
 	// 1. Parse the incoming A2A message.
-	workflowName, inputs, err := parseA2AMessage(reqCtx.Message)
+	workflowName, inputs, err := parseA2AMessage(reqCtx.Message, e.floatNumbers)
 	if err != nil {
 		return writeFailEvent(ctx, reqCtx, queue, err)
 	}
@@ -32,6 +45,9 @@ func (e *upalA2AExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCon
 	if err != nil {
 		return writeFailEvent(ctx, reqCtx, queue, fmt.Errorf("workflow %q not found", workflowName))
 	}
+	if inputs == nil {
+		inputs = textInputs(wf, reqCtx.Message)
+	}
 
 	// 3. Submit task.
 	if reqCtx.StoredTask == nil {
@@ -47,21 +63,48 @@ func (e *upalA2AExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCon
 		return fmt.Errorf("failed to write working: %w", err)
 	}
 
-	// 5. Execute via WorkflowExecutor.
-	events, _, runErr := e.workflowSvc.Run(ctx, wf, inputs)
+	// 5. Execute via WorkflowExecutor, recorded as a run so it is listed
+	// among active runs and can be cancelled.
+	runCtx, run, trackErr := services.TrackRun(ctx, e.runHistory, e.runManager, wf, a2aTriggerType, string(reqCtx.TaskID), inputs)
+	if trackErr != nil {
+		slog.WarnContext(ctx, "a2a: failed to create run record", "workflow", wf.Name, "err", trackErr)
+	}
+	var (
+		res     upal.RunResult
+		failure error
+	)
+	defer func() {
+		if failure == nil {
+			failure = err
+		}
+		run.Finish(runCtx, res, failure)
+	}()
+
+	events, result, runErr := e.workflowSvc.Run(runCtx, wf, inputs)
 	if runErr != nil {
+		failure = runErr
 		return writeFailEvent(ctx, reqCtx, queue, fmt.Errorf("failed to run workflow: %w", runErr))
 	}
 
 	// 6. Stream events as A2A artifacts.
+	acceptsText := acceptsOutputMode(ctx, "text/plain")
 	var artifactID a2a.ArtifactID
 	for ev := range events {
+		if !run.Observe(runCtx, ev) {
+			continue
+		}
 		if ev.Type == upal.EventError {
-			return writeFailEvent(ctx, reqCtx, queue, fmt.Errorf("%v", ev.Payload["error"]))
+			if run.Cancelled(runCtx) {
+				return e.Cancel(ctx, reqCtx, queue)
+			}
+			failure = fmt.Errorf("%v", ev.Payload["error"])
+			return writeFailEvent(ctx, reqCtx, queue, failure)
 		}
 
-		text := extractPayloadText(ev)
-		if text == "" {
+		// Images travel as file artifacts with the final outputs, so they
+		// are left out of the streamed text.
+		text, _ := splitDataURIs(extractPayloadText(ev))
+		if text == "" || !acceptsText {
 			continue
 		}
 
@@ -78,7 +121,47 @@ func (e *upalA2AExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCon
 		}
 	}
 
-	// 7. Completed.
+	// 7. Workflows with several output nodes get one named artifact per output,
+	// images in outputs become file artifacts, and JSON-accepting clients get
+	// the outputs as a single data artifact.
+	res, ok := <-result
+	if run.Cancelled(runCtx) {
+		return e.Cancel(ctx, reqCtx, queue)
+	}
+	if ok {
+		for _, nodeID := range slices.Sorted(maps.Keys(res.Outputs)) {
+			text, files := splitDataURIs(outputText(res.Outputs[nodeID]))
+			if acceptsText && len(res.Outputs) > 1 && (text != "" || len(files) == 0) {
+				artEvent := a2a.NewArtifactEvent(reqCtx, a2a.TextPart{Text: text})
+				artEvent.Artifact.Name = nodeID
+				artEvent.Artifact.Metadata = map[string]any{"node_id": nodeID}
+				if err := queue.Write(ctx, artEvent); err != nil {
+					return fmt.Errorf("failed to write output artifact: %w", err)
+				}
+			}
+			for _, file := range files {
+				if !acceptsOutputMode(ctx, file.File.(a2a.FileBytes).MimeType) {
+					continue
+				}
+				artEvent := a2a.NewArtifactEvent(reqCtx, file)
+				artEvent.Artifact.Name = nodeID
+				artEvent.Artifact.Metadata = map[string]any{"node_id": nodeID}
+				if err := queue.Write(ctx, artEvent); err != nil {
+					return fmt.Errorf("failed to write file artifact: %w", err)
+				}
+			}
+		}
+		if len(res.Outputs) > 0 && acceptsOutputMode(ctx, "application/json") {
+			artEvent := a2a.NewArtifactEvent(reqCtx, a2a.DataPart{Data: structuredOutputs(res.Outputs)})
+			artEvent.Artifact.Name = "outputs"
+			artEvent.Artifact.Metadata = map[string]any{"mime_type": "application/json"}
+			if err := queue.Write(ctx, artEvent); err != nil {
+				return fmt.Errorf("failed to write data artifact: %w", err)
+			}
+		}
+	}
+
+	// 8. Completed.
 	doneEvent := a2a.NewStatusUpdateEvent(reqCtx, a2a.TaskStateCompleted, nil)
 	doneEvent.Final = true
 	if err := queue.Write(ctx, doneEvent); err != nil {
@@ -104,6 +187,133 @@ func extractPayloadText(ev upal.WorkflowEvent) string {
 	return ""
 }
 
+// outputText renders an output node result as artifact text. Non-string
+// values are encoded as JSON.
+func outputText(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// splitDataURIs separates base64 data URIs, which image nodes emit on their
+// own lines, from the rest of a node output. The remaining text is trimmed;
+// each URI becomes a file part carrying its MIME type.
+func splitDataURIs(text string) (string, []a2a.FilePart) {
+	if !strings.Contains(text, "data:") {
+		return text, nil
+	}
+	var kept []string
+	var files []a2a.FilePart
+	for _, line := range strings.Split(text, "\n") {
+		if file, ok := dataURIFilePart(strings.TrimSpace(line)); ok {
+			files = append(files, file)
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n")), files
+}
+
+// dataURIFilePart converts a "data:<mime>;base64,<payload>" URI into a file
+// part. The payload is already base64, as FileBytes expects.
+func dataURIFilePart(uri string) (a2a.FilePart, bool) {
+	rest, ok := strings.CutPrefix(uri, "data:")
+	if !ok {
+		return a2a.FilePart{}, false
+	}
+	mimeType, payload, ok := strings.Cut(rest, ";base64,")
+	if !ok || payload == "" || strings.ContainsAny(payload, " \t") {
+		return a2a.FilePart{}, false
+	}
+	if _, _, err := mime.ParseMediaType(mimeType); err != nil {
+		return a2a.FilePart{}, false
+	}
+	return a2a.FilePart{File: a2a.FileBytes{FileMeta: a2a.FileMeta{MimeType: mimeType}, Bytes: payload}}, true
+}
+
+// structuredOutputs returns the output node results keyed by node ID for a
+// data artifact. String results holding a JSON object or array are decoded so
+// clients receive them as structured values.
+func structuredOutputs(outputs map[string]any) map[string]any {
+	data := make(map[string]any, len(outputs))
+	for nodeID, v := range outputs {
+		if text, ok := v.(string); ok {
+			trimmed := strings.TrimSpace(text)
+			if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+				var decoded any
+				if json.Unmarshal([]byte(trimmed), &decoded) == nil {
+					v = decoded
+				}
+			}
+		}
+		data[nodeID] = v
+	}
+	return data
+}
+
+// a2aOutputModesKey carries the client's accepted output modes from the
+// message/send configuration to the executor.
+type a2aOutputModesKey struct{}
+
+// outputModesInterceptor copies MessageSendConfig.AcceptedOutputModes into
+// the call context; the executor only sees the message itself.
+type outputModesInterceptor struct {
+	a2asrv.PassthroughCallInterceptor
+}
+
+func (outputModesInterceptor) Before(ctx context.Context, _ *a2asrv.CallContext, req *a2asrv.Request) (context.Context, error) {
+	if params, ok := req.Payload.(*a2a.MessageSendParams); ok && params.Config != nil && len(params.Config.AcceptedOutputModes) > 0 {
+		ctx = context.WithValue(ctx, a2aOutputModesKey{}, params.Config.AcceptedOutputModes)
+	}
+	return ctx, nil
+}
+
+// workflowRoutingInterceptor rejects messages that name an unknown workflow
+// with a JSON-RPC invalid params error, before any task is created. Messages
+// that do not name a workflow at all are left to the executor, which fails
+// the task with a usage hint.
+type workflowRoutingInterceptor struct {
+	a2asrv.PassthroughCallInterceptor
+	workflowSvc ports.WorkflowExecutor
+}
+
+func (i workflowRoutingInterceptor) Before(ctx context.Context, _ *a2asrv.CallContext, req *a2asrv.Request) (context.Context, error) {
+	params, ok := req.Payload.(*a2a.MessageSendParams)
+	if !ok {
+		return ctx, nil
+	}
+	name, _, err := parseA2AMessage(params.Message, false)
+	if err != nil {
+		return ctx, nil
+	}
+	if _, err := i.workflowSvc.Lookup(ctx, name); err != nil {
+		return ctx, fmt.Errorf("workflow %q not found: %w", name, a2a.ErrInvalidParams)
+	}
+	return ctx, nil
+}
+
+// acceptsOutputMode reports whether the client accepts mode. Clients that
+// did not list any modes accept everything.
+func acceptsOutputMode(ctx context.Context, mode string) bool {
+	modes, _ := ctx.Value(a2aOutputModesKey{}).([]string)
+	if len(modes) == 0 {
+		return true
+	}
+	if slices.Contains(modes, mode) || slices.Contains(modes, "*/*") {
+		return true
+	}
+	// A type wildcard such as "image/*" accepts any subtype.
+	if typ, _, ok := strings.Cut(mode, "/"); ok {
+		return slices.Contains(modes, typ+"/*")
+	}
+	return false
+}
+
 // writeFailEvent sends a TaskStateFailed event with the error message.
 func writeFailEvent(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue, err error) error {
 	msg := a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: err.Error()})
@@ -116,22 +326,36 @@ func writeFailEvent(ctx context.Context, reqCtx *a2asrv.RequestContext, queue ev
 }
 
 // parseA2AMessage extracts a workflow name and inputs from an A2A message.
-// Supported format: JSON text part {"workflow": "name", "inputs": {"key": "value"}}
-func parseA2AMessage(msg *a2a.Message) (string, map[string]any, error) {
+// The workflow is named either by a JSON text part
+// {"workflow": "name", "inputs": {"key": "value"}} or by the "workflow" or
+// "skill_id" message metadata, where the skill ID is the one advertised on the
+// agent card. When the name comes from metadata, a data part supplies the
+// inputs; failing that, a JSON text part's "inputs" object does. Plain text is
+// left for the executor to route into the workflow's first input node, so
+// inputs is nil in that case. Numbers in a JSON text part decode as
+// json.Number unless floatNumbers is set (see Server.SetFloatNumbers).
+func parseA2AMessage(msg *a2a.Message, floatNumbers bool) (string, map[string]any, error) {
 	if msg == nil || len(msg.Parts) == 0 {
 		return "", nil, fmt.Errorf("empty message")
 	}
 
-	// Extract text from first TextPart.
+	// Extract text from the first TextPart and data from the first DataPart.
 	var text string
+	var data map[string]any
 	for _, part := range msg.Parts {
-		if tp, ok := part.(a2a.TextPart); ok {
-			text = tp.Text
-			break
+		switch p := part.(type) {
+		case a2a.TextPart:
+			if text == "" {
+				text = p.Text
+			}
+		case a2a.DataPart:
+			if data == nil {
+				data = p.Data
+			}
 		}
 	}
-	if text == "" {
-		return "", nil, fmt.Errorf("no text content in message")
+	if text == "" && data == nil {
+		return "", nil, fmt.Errorf("no text or data content in message")
 	}
 
 	// Try JSON format.
@@ -139,63 +363,206 @@ func parseA2AMessage(msg *a2a.Message) (string, map[string]any, error) {
 		Workflow string         `json:"workflow"`
 		Inputs   map[string]any `json:"inputs"`
 	}
-	if err := json.Unmarshal([]byte(text), &structured); err == nil && structured.Workflow != "" {
+	isJSON := unmarshalInputs([]byte(text), &structured, floatNumbers) == nil
+	if isJSON && structured.Workflow != "" {
 		return structured.Workflow, structured.Inputs, nil
 	}
 
 	// Try metadata-based workflow name.
-	if msg.Metadata != nil {
-		if wfName, ok := msg.Metadata["workflow"].(string); ok && wfName != "" {
-			return wfName, nil, nil
+	for _, key := range []string{"workflow", "skill_id", "skillId"} {
+		wfName, ok := msg.Metadata[key].(string)
+		if !ok || wfName == "" {
+			continue
+		}
+		if data != nil {
+			return wfName, data, nil
+		}
+		if isJSON && structured.Inputs != nil {
+			return wfName, structured.Inputs, nil
 		}
+		return wfName, nil, nil
 	}
 
-	return "", nil, fmt.Errorf("could not determine workflow; send JSON: {\"workflow\": \"name\", \"inputs\": {...}}")
+	return "", nil, fmt.Errorf("could not determine workflow; send JSON: {\"workflow\": \"name\", \"inputs\": {...}} or set the \"workflow\" or \"skill_id\" message metadata")
 }
 
-// buildAgentCard generates a dynamic AgentCard reflecting current workflows.
-func (s *Server) buildAgentCard(ctx context.Context) *a2a.AgentCard {
-	workflows, _ := s.repo.List(ctx)
-
-	skills := make([]a2a.AgentSkill, 0, len(workflows))
-	for _, wf := range workflows {
-		var inputIDs []string
+// textInputs routes a plain-text message into the workflow's first input
+// node. It returns nil when there is no text or no input node.
+func textInputs(wf *upal.WorkflowDefinition, msg *a2a.Message) map[string]any {
+	for _, part := range msg.Parts {
+		tp, ok := part.(a2a.TextPart)
+		if !ok || tp.Text == "" {
+			continue
+		}
 		for _, nd := range wf.Nodes {
 			if nd.Type == upal.NodeTypeInput {
-				inputIDs = append(inputIDs, nd.ID)
+				return map[string]any{nd.ID: tp.Text}
 			}
 		}
+		return nil
+	}
+	return nil
+}
 
-		description := fmt.Sprintf("Execute workflow %q", wf.Name)
-		if len(inputIDs) > 0 {
-			description += fmt.Sprintf(" (inputs: %s)", strings.Join(inputIDs, ", "))
-		}
+// genericA2ASkill is advertised when no workflows exist yet, so clients still
+// learn the message format the executor expects.
+var genericA2ASkill = a2a.AgentSkill{
+	ID:          "workflow-execute",
+	Name:        "Execute workflow",
+	Description: "Execute a saved Upal workflow by name with the given inputs.",
+	Tags:        []string{"workflow", "upal"},
+	Examples:    []string{`{"workflow": "my-workflow", "inputs": {"input-1": "..."}}`},
+	InputModes:  []string{"application/json"},
+	OutputModes: []string{"text/plain", "application/json"},
+}
+
+// buildAgentCard generates a dynamic AgentCard reflecting current workflows.
+func (s *Server) buildAgentCard(ctx context.Context) *a2a.AgentCard {
+	workflows, _ := s.repo.List(ctx)
 
-		example := fmt.Sprintf(`{"workflow": "%s", "inputs": {%s}}`,
-			wf.Name, buildExampleInputs(inputIDs))
+	skills := make([]a2a.AgentSkill, 0, len(workflows))
+	inputSchemas := make(map[string]any, len(workflows))
+	for _, wf := range workflows {
+		skills = append(skills, workflowSkill(wf))
+		inputSchemas[wf.Name] = workflowInputSchema(wf)
+	}
+	if len(skills) == 0 {
+		skills = append(skills, genericA2ASkill)
+	}
 
-		skills = append(skills, a2a.AgentSkill{
-			ID:          wf.Name,
-			Name:        wf.Name,
-			Description: description,
-			Tags:        []string{"workflow", "upal"},
-			Examples:    []string{example},
-		})
+	capabilities := a2a.AgentCapabilities{Streaming: true}
+	if len(inputSchemas) > 0 {
+		capabilities.Extensions = []a2a.AgentExtension{{
+			URI:         a2aInputSchemaExtensionURI,
+			Description: "JSON Schema of each workflow skill's inputs, keyed by skill ID under params.skills.",
+			Params:      map[string]any{"skills": inputSchemas},
+		}}
 	}
 
 	return &a2a.AgentCard{
 		Name:               "Upal",
 		Description:        "Visual AI workflow platform. Each skill represents a saved workflow.",
-		URL:                s.a2aBaseURL + "/a2a",
+		URL:                baseURLFromContext(ctx, s.a2aBaseURL) + "/a2a",
 		Version:            "0.2.0",
 		ProtocolVersion:    "0.2",
 		DefaultInputModes:  []string{"application/json", "text/plain"},
-		DefaultOutputModes: []string{"text/plain"},
-		Capabilities:       a2a.AgentCapabilities{Streaming: true},
+		DefaultOutputModes: []string{"text/plain", "application/json"},
+		Capabilities:       capabilities,
 		Skills:             skills,
 	}
 }
 
+// workflowSkill describes wf as an A2A skill. Workflows with input nodes need
+// a JSON message carrying the inputs; workflows without them can also be
+// started by a plain-text message naming the workflow in its metadata. Output
+// nodes add the structured "outputs" JSON artifact to the text artifacts.
+func workflowSkill(wf *upal.WorkflowDefinition) a2a.AgentSkill {
+	var inputIDs []string
+	hasOutputs := false
+	for _, nd := range wf.Nodes {
+		switch nd.Type {
+		case upal.NodeTypeInput:
+			inputIDs = append(inputIDs, nd.ID)
+		case upal.NodeTypeOutput:
+			hasOutputs = true
+		}
+	}
+
+	description := wf.Description
+	if description == "" {
+		description = fmt.Sprintf("Execute workflow %q", wf.Name)
+	}
+	if len(inputIDs) > 0 {
+		description += fmt.Sprintf(" (inputs: %s)", strings.Join(inputIDs, ", "))
+	}
+
+	example := fmt.Sprintf(`{"workflow": "%s", "inputs": {%s}}`,
+		wf.Name, buildExampleInputs(inputIDs))
+
+	inputModes := []string{"application/json"}
+	if len(inputIDs) == 0 {
+		inputModes = append(inputModes, "text/plain")
+	}
+	outputModes := []string{"text/plain"}
+	if hasOutputs {
+		outputModes = append(outputModes, "application/json")
+	}
+
+	return a2a.AgentSkill{
+		ID:          wf.Name,
+		Name:        wf.Name,
+		Description: description,
+		Tags:        append([]string{"workflow", "upal"}, wf.Tags...),
+		Examples:    []string{example},
+		InputModes:  inputModes,
+		OutputModes: outputModes,
+	}
+}
+
+// a2aInputSchemaExtensionURI identifies the agent card extension carrying
+// per-skill input schemas; AgentSkill itself has no metadata field.
+const a2aInputSchemaExtensionURI = "https://github.com/soochol/upal/a2a/extensions/input-schema/v1"
+
+// workflowInputSchema describes wf's input nodes as a JSON Schema object so
+// A2A clients can render a form. Each property is derived from the node
+// config the editor and generator write: "label" (title), "description" or
+// "prompt", and "default", whose JSON type also sets the property type
+// (string otherwise). An input is required unless it has a default; constant
+// inputs are omitted since supplied values are ignored.
+func workflowInputSchema(wf *upal.WorkflowDefinition) map[string]any {
+	properties := map[string]any{}
+	required := []any{}
+	for _, nd := range wf.Nodes {
+		if nd.Type != upal.NodeTypeInput {
+			continue
+		}
+		if constant, _ := nd.Config["constant"].(bool); constant {
+			continue
+		}
+		def := nd.Config["default"]
+		prop := map[string]any{"type": jsonSchemaType(def)}
+		if label, _ := nd.Config["label"].(string); label != "" {
+			prop["title"] = label
+		}
+		desc, _ := nd.Config["description"].(string)
+		if desc == "" {
+			desc, _ = nd.Config["prompt"].(string)
+		}
+		if desc != "" {
+			prop["description"] = desc
+		}
+		if def != nil {
+			prop["default"] = def
+		} else {
+			required = append(required, nd.ID)
+		}
+		properties[nd.ID] = prop
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// jsonSchemaType returns the JSON Schema type of a decoded JSON value,
+// defaulting to string.
+func jsonSchemaType(v any) string {
+	switch v.(type) {
+	case float64, json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return "string"
+	}
+}
+
 func buildExampleInputs(inputIDs []string) string {
 	parts := make([]string, len(inputIDs))
 	for i, id := range inputIDs {
@@ -208,15 +575,22 @@ func buildExampleInputs(inputIDs []string) string {
 func (s *Server) setupA2ARoutes(r chi.Router) {
 	executor := &upalA2AExecutor{
 		workflowSvc: s.workflowSvc,
+		runHistory:  s.runHistorySvc,
+		runManager:  s.runManager,
+
+		floatNumbers: s.floatNumbers,
 	}
 
-	reqHandler := a2asrv.NewHandler(executor)
+	reqHandler := a2asrv.NewHandler(executor,
+		a2asrv.WithCallInterceptor(outputModesInterceptor{}),
+		a2asrv.WithCallInterceptor(workflowRoutingInterceptor{workflowSvc: s.workflowSvc}),
+	)
 
 	// Dynamic agent card — regenerated on every request to reflect current workflows.
 	cardProducer := a2asrv.AgentCardProducerFn(func(ctx context.Context) (*a2a.AgentCard, error) {
 		return s.buildAgentCard(ctx), nil
 	})
-	r.Handle(a2asrv.WellKnownAgentCardPath, a2asrv.NewAgentCardHandler(cardProducer))
+	r.Handle(a2asrv.WellKnownAgentCardPath, s.withBaseURL(a2asrv.NewAgentCardHandler(cardProducer)))
 
 	// JSON-RPC endpoint for A2A protocol.
 	r.Handle("/a2a", a2asrv.NewJSONRPCHandler(reqHandler))
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/a2aproject/a2a-go/a2a"
@@ -65,10 +69,10 @@ func TestAgentCardDynamic(t *testing.T) {
 	srv.SetA2ABaseURL("http://localhost:8080")
 	ctx := context.Background()
 
-	// Initially empty.
+	// Initially only the generic fallback skill.
 	card := srv.buildAgentCard(ctx)
-	if len(card.Skills) != 0 {
-		t.Fatalf("expected 0 skills, got %d", len(card.Skills))
+	if len(card.Skills) != 1 || card.Skills[0].ID != "workflow-execute" {
+		t.Fatalf("expected the generic fallback skill, got %+v", card.Skills)
 	}
 
 	// Add a workflow.
@@ -99,12 +103,108 @@ func TestAgentCardDynamic(t *testing.T) {
 	}
 }
 
+func TestAgentCardWorkflowSkill(t *testing.T) {
+	srv := newTestServer()
+	srv.SetA2ABaseURL("http://localhost:8080")
+	ctx := context.Background()
+
+	srv.repo.Create(ctx, &upal.WorkflowDefinition{
+		Name:        "summarize",
+		Description: "Summarize an article.",
+		Tags:        []string{"news"},
+		Nodes: []upal.NodeDefinition{
+			{ID: "article", Type: upal.NodeTypeInput},
+			{ID: "out", Type: upal.NodeTypeOutput},
+		},
+	})
+	srv.repo.Create(ctx, &upal.WorkflowDefinition{
+		Name:  "ping",
+		Nodes: []upal.NodeDefinition{{ID: "a", Type: upal.NodeTypeAgent}},
+	})
+
+	req := httptest.NewRequest("GET", "/.well-known/agent-card.json", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var card a2a.AgentCard
+	if err := json.Unmarshal(w.Body.Bytes(), &card); err != nil {
+		t.Fatalf("decode card: %v", err)
+	}
+	skills := map[string]a2a.AgentSkill{}
+	for _, sk := range card.Skills {
+		skills[sk.ID] = sk
+	}
+	if _, ok := skills["workflow-execute"]; ok || len(skills) != 2 {
+		t.Fatalf("expected only workflow skills, got %+v", card.Skills)
+	}
+
+	sum := skills["summarize"]
+	if sum.Name != "summarize" || sum.Description != "Summarize an article. (inputs: article)" {
+		t.Errorf("unexpected skill: %+v", sum)
+	}
+	if !slices.Equal(sum.InputModes, []string{"application/json"}) ||
+		!slices.Equal(sum.OutputModes, []string{"text/plain", "application/json"}) {
+		t.Errorf("summarize modes = %v / %v", sum.InputModes, sum.OutputModes)
+	}
+	if !slices.Contains(sum.Tags, "news") {
+		t.Errorf("workflow tags missing: %v", sum.Tags)
+	}
+
+	ping := skills["ping"]
+	if ping.Description != `Execute workflow "ping"` {
+		t.Errorf("ping description = %q", ping.Description)
+	}
+	if !slices.Equal(ping.InputModes, []string{"application/json", "text/plain"}) ||
+		!slices.Equal(ping.OutputModes, []string{"text/plain"}) {
+		t.Errorf("ping modes = %v / %v", ping.InputModes, ping.OutputModes)
+	}
+}
+
+func TestAgentCardSkillInputSchema(t *testing.T) {
+	srv := newTestServer()
+	srv.SetA2ABaseURL("http://localhost:8080")
+	srv.repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "translate",
+		Nodes: []upal.NodeDefinition{
+			{ID: "text", Type: upal.NodeTypeInput, Config: map[string]any{"label": "Text", "prompt": "Text to translate"}},
+			{ID: "count", Type: upal.NodeTypeInput, Config: map[string]any{"label": "Variants", "default": 1.0}},
+			{ID: "style", Type: upal.NodeTypeInput, Config: map[string]any{"constant": true, "default": "formal"}},
+			{ID: "out", Type: upal.NodeTypeOutput},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/agent-card.json", nil))
+	var card a2a.AgentCard
+	if err := json.Unmarshal(w.Body.Bytes(), &card); err != nil {
+		t.Fatalf("decode card: %v", err)
+	}
+	if len(card.Capabilities.Extensions) != 1 || card.Capabilities.Extensions[0].URI != a2aInputSchemaExtensionURI {
+		t.Fatalf("unexpected extensions: %+v", card.Capabilities.Extensions)
+	}
+	skills, _ := card.Capabilities.Extensions[0].Params["skills"].(map[string]any)
+	schema, _ := skills["translate"].(map[string]any)
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"text":  map[string]any{"type": "string", "title": "Text", "description": "Text to translate"},
+			"count": map[string]any{"type": "number", "title": "Variants", "default": 1.0},
+		},
+		"required": []any{"text"},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("input schema = %v\nwant %v", schema, want)
+	}
+}
+
 func TestParseA2AMessageJSON(t *testing.T) {
 	msg := a2a.NewMessage(a2a.MessageRoleUser,
 		a2a.TextPart{Text: `{"workflow": "my-wf", "inputs": {"input-1": "hello"}}`},
 	)
 
-	name, inputs, err := parseA2AMessage(msg)
+	name, inputs, err := parseA2AMessage(msg, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -122,7 +222,7 @@ func TestParseA2AMessageMetadata(t *testing.T) {
 	)
 	msg.Metadata = map[string]any{"workflow": "meta-wf"}
 
-	name, _, err := parseA2AMessage(msg)
+	name, _, err := parseA2AMessage(msg, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -132,13 +232,13 @@ func TestParseA2AMessageMetadata(t *testing.T) {
 }
 
 func TestParseA2AMessageEmpty(t *testing.T) {
-	_, _, err := parseA2AMessage(nil)
+	_, _, err := parseA2AMessage(nil, false)
 	if err == nil {
 		t.Fatal("expected error for nil message")
 	}
 
 	msg := a2a.NewMessage(a2a.MessageRoleUser)
-	_, _, err = parseA2AMessage(msg)
+	_, _, err = parseA2AMessage(msg, false)
 	if err == nil {
 		t.Fatal("expected error for empty parts")
 	}
@@ -149,7 +249,7 @@ func TestParseA2AMessageNoWorkflow(t *testing.T) {
 		a2a.TextPart{Text: "just some text without workflow info"},
 	)
 
-	_, _, err := parseA2AMessage(msg)
+	_, _, err := parseA2AMessage(msg, false)
 	if err == nil {
 		t.Fatal("expected error when no workflow specified")
 	}
@@ -167,3 +267,265 @@ func TestBuildExampleInputs(t *testing.T) {
 		t.Errorf("expected empty string for nil, got %q", result)
 	}
 }
+
+// sendA2AMessage posts a blocking message/send JSON-RPC call and returns the task.
+func sendA2AMessage(t *testing.T, srv *Server, text string, acceptedModes []string) *a2a.Task {
+	t.Helper()
+	params := a2a.MessageSendParams{
+		Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: text}),
+	}
+	if acceptedModes != nil {
+		params.Config = &a2a.MessageSendConfig{AcceptedOutputModes: acceptedModes}
+	}
+	task, rpcErr := callA2ASend(t, srv, params)
+	if task == nil {
+		t.Fatalf("expected a task, got error %+v", rpcErr)
+	}
+	return task
+}
+
+// a2aRPCError is the error member of a JSON-RPC response.
+type a2aRPCError struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Data    map[string]any `json:"data"`
+}
+
+// callA2ASend posts a message/send JSON-RPC call and returns either the task
+// or the JSON-RPC error.
+func callA2ASend(t *testing.T, srv *Server, params a2a.MessageSendParams) (*a2a.Task, *a2aRPCError) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "message/send", "params": params})
+	req := httptest.NewRequest("POST", "/a2a", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Result *a2a.Task    `json:"result"`
+		Error  *a2aRPCError `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || (resp.Result == nil && resp.Error == nil) {
+		t.Fatalf("decode response (err=%v): %s", err, w.Body.String())
+	}
+	return resp.Result, resp.Error
+}
+
+// artifactPartKinds lists "text" / "data" for every part of every artifact.
+func artifactPartKinds(task *a2a.Task) (kinds []string, data map[string]any) {
+	for _, art := range task.Artifacts {
+		for _, p := range art.Parts {
+			switch part := p.(type) {
+			case a2a.TextPart:
+				kinds = append(kinds, "text")
+			case a2a.DataPart:
+				kinds = append(kinds, "data")
+				data = part.Data
+			}
+		}
+	}
+	return kinds, data
+}
+
+func TestA2AExecute_StructuredOutput(t *testing.T) {
+	srv := newTestServer()
+	srv.SetA2ABaseURL("http://localhost:8080")
+	srv.repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "echo",
+		Nodes: []upal.NodeDefinition{
+			{ID: "topic", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "result", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": `{"topic": "{{topic}}", "ok": true}`}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "topic", To: "result"}},
+	})
+	msg := `{"workflow": "echo", "inputs": {"topic": "go"}}`
+
+	task := sendA2AMessage(t, srv, msg, []string{"text/plain", "application/json"})
+	if task.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("task state = %s", task.Status.State)
+	}
+	kinds, data := artifactPartKinds(task)
+	if !slices.Contains(kinds, "text") || !slices.Contains(kinds, "data") {
+		t.Fatalf("artifact parts = %v, want text and data", kinds)
+	}
+	want := map[string]any{"result": map[string]any{"topic": "go", "ok": true}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("data artifact = %v, want %v", data, want)
+	}
+
+	// JSON-only clients get just the data artifact.
+	kinds, _ = artifactPartKinds(sendA2AMessage(t, srv, msg, []string{"application/json"}))
+	if !slices.Equal(kinds, []string{"data"}) {
+		t.Errorf("application/json only: artifact parts = %v, want [data]", kinds)
+	}
+
+	// Text-only clients get no data artifact.
+	kinds, _ = artifactPartKinds(sendA2AMessage(t, srv, msg, []string{"text/plain"}))
+	if slices.Contains(kinds, "data") || len(kinds) == 0 {
+		t.Errorf("text/plain only: artifact parts = %v, want text only", kinds)
+	}
+}
+
+func TestA2AExecute_TargetsWorkflow(t *testing.T) {
+	srv := newTestServer()
+	srv.SetA2ABaseURL("http://localhost:8080")
+	ctx := context.Background()
+	for _, name := range []string{"greet", "shout"} {
+		srv.repo.Create(ctx, &upal.WorkflowDefinition{
+			Name: name,
+			Nodes: []upal.NodeDefinition{
+				{ID: "text", Type: upal.NodeTypeInput, Config: map[string]any{}},
+				{ID: "result", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": name + ": {{text}}"}},
+			},
+			Edges: []upal.EdgeDefinition{{From: "text", To: "result"}},
+		})
+	}
+	jsonOnly := &a2a.MessageSendConfig{AcceptedOutputModes: []string{"application/json"}}
+
+	// Plain text targeted by skill ID lands in the workflow's input node.
+	msg := a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "hello"})
+	msg.Metadata = map[string]any{"skill_id": "shout"}
+	task, rpcErr := callA2ASend(t, srv, a2a.MessageSendParams{Message: msg, Config: jsonOnly})
+	if task == nil {
+		t.Fatalf("unexpected error: %+v", rpcErr)
+	}
+	if _, data := artifactPartKinds(task); data["result"] != "shout: hello" {
+		t.Errorf("skill_id: outputs = %v, want shout workflow with text input", data)
+	}
+
+	// A data part supplies the inputs when metadata names the workflow.
+	msg = a2a.NewMessage(a2a.MessageRoleUser, a2a.DataPart{Data: map[string]any{"text": "from data"}})
+	msg.Metadata = map[string]any{"workflow": "greet"}
+	task, rpcErr = callA2ASend(t, srv, a2a.MessageSendParams{Message: msg, Config: jsonOnly})
+	if task == nil {
+		t.Fatalf("unexpected error: %+v", rpcErr)
+	}
+	if _, data := artifactPartKinds(task); data["result"] != "greet: from data" {
+		t.Errorf("data part: outputs = %v, want greet workflow with data input", data)
+	}
+
+	// An unknown workflow is a JSON-RPC invalid params error, not a task.
+	msg = a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "hello"})
+	msg.Metadata = map[string]any{"skill_id": "missing"}
+	task, rpcErr = callA2ASend(t, srv, a2a.MessageSendParams{Message: msg})
+	if task != nil || rpcErr == nil || rpcErr.Code != -32602 {
+		t.Fatalf("expected invalid params error, got task=%v err=%+v", task, rpcErr)
+	}
+	if detail, _ := rpcErr.Data["error"].(string); !strings.Contains(detail, `"missing"`) {
+		t.Errorf("error data = %v, want workflow name", rpcErr.Data)
+	}
+}
+
+func TestParseA2AMessageSkillIDWithData(t *testing.T) {
+	msg := a2a.NewMessage(a2a.MessageRoleUser, a2a.DataPart{Data: map[string]any{"topic": "go"}})
+	msg.Metadata = map[string]any{"skill_id": "wf"}
+
+	name, inputs, err := parseA2AMessage(msg, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "wf" || inputs["topic"] != "go" {
+		t.Errorf("got name=%q inputs=%v", name, inputs)
+	}
+}
+
+func TestA2AFilePartJSONRoundTrip(t *testing.T) {
+	file, ok := dataURIFilePart("data:image/png;base64,iVBORw0KGgo=")
+	if !ok {
+		t.Fatal("data URI not recognised")
+	}
+	msg := a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "caption"}, file, a2a.DataPart{Data: map[string]any{"n": float64(1)}})
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"kind":"file"`) || !strings.Contains(string(raw), `"mimeType":"image/png"`) || !strings.Contains(string(raw), `"bytes":"iVBORw0KGgo="`) {
+		t.Errorf("marshaled message = %s", raw)
+	}
+
+	var decoded a2a.Message
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Parts) != 3 {
+		t.Fatalf("decoded parts = %#v", decoded.Parts)
+	}
+	fp, ok := decoded.Parts[1].(a2a.FilePart)
+	if !ok {
+		t.Fatalf("part 1 = %T, want FilePart", decoded.Parts[1])
+	}
+	if fb, ok := fp.File.(a2a.FileBytes); !ok || fb.MimeType != "image/png" || fb.Bytes != "iVBORw0KGgo=" {
+		t.Errorf("file content = %#v", fp.File)
+	}
+
+	// A file part alongside text and data does not disturb input parsing.
+	decoded.Metadata = map[string]any{"workflow": "wf"}
+	name, inputs, err := parseA2AMessage(&decoded, false)
+	if err != nil || name != "wf" || !reflect.DeepEqual(inputs, map[string]any{"n": float64(1)}) {
+		t.Errorf("parseA2AMessage = %q %v %v", name, inputs, err)
+	}
+}
+
+func TestSplitDataURIs(t *testing.T) {
+	text, files := splitDataURIs("Here is the chart:\ndata:image/jpeg;base64,/9j/4AAQ\nEnjoy. data:not-a-uri")
+	if text != "Here is the chart:\nEnjoy. data:not-a-uri" {
+		t.Errorf("text = %q", text)
+	}
+	if len(files) != 1 || files[0].File.(a2a.FileBytes).MimeType != "image/jpeg" {
+		t.Errorf("files = %#v", files)
+	}
+	if text, files := splitDataURIs("plain"); text != "plain" || files != nil {
+		t.Errorf("plain text = %q %v", text, files)
+	}
+}
+
+func TestA2AExecute_ImageOutputAsFilePart(t *testing.T) {
+	srv := newTestServer()
+	srv.SetA2ABaseURL("http://localhost:8080")
+	srv.repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "draw",
+		Nodes: []upal.NodeDefinition{
+			{ID: "image", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "result", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": "A cat:\n{{image}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "image", To: "result"}},
+	})
+	msg := `{"workflow": "draw", "inputs": {"image": "data:image/png;base64,iVBORw0KGgo="}}`
+
+	task := sendA2AMessage(t, srv, msg, []string{"text/plain", "image/*"})
+	if task.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("task state = %s", task.Status.State)
+	}
+	var texts []string
+	var files []a2a.FileBytes
+	for _, art := range task.Artifacts {
+		for _, p := range art.Parts {
+			switch part := p.(type) {
+			case a2a.TextPart:
+				texts = append(texts, part.Text)
+			case a2a.FilePart:
+				files = append(files, part.File.(a2a.FileBytes))
+			}
+		}
+	}
+	if len(files) != 1 || files[0].MimeType != "image/png" || files[0].Bytes != "iVBORw0KGgo=" {
+		t.Errorf("file artifacts = %#v", files)
+	}
+	for _, text := range texts {
+		if strings.Contains(text, "base64") {
+			t.Errorf("data URI leaked into text artifact: %q", text)
+		}
+	}
+
+	// Clients that do not accept images get no file artifact.
+	task = sendA2AMessage(t, srv, msg, []string{"text/plain"})
+	for _, art := range task.Artifacts {
+		for _, p := range art.Parts {
+			if _, ok := p.(a2a.FilePart); ok {
+				t.Errorf("text-only client received a file part")
+			}
+		}
+	}
+}
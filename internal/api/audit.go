@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// SetAuditService enables the audit log middleware and GET /api/audit.
+func (s *Server) SetAuditService(svc *services.AuditService) { s.auditSvc = svc }
+
+// auditedResources maps the first path segment under /api to the audited
+// resource type. Schedules are audited by the scheduler service itself.
+var auditedResources = map[string]string{
+	"workflows":   "workflow",
+	"triggers":    "trigger",
+	"connections": "connection",
+	"pipelines":   "pipeline",
+}
+
+// auditNote lets handlers attach details the middleware cannot see: the ID
+// of a newly created resource and the field changes of an update.
+type auditNote struct {
+	resourceID string
+	changes    []upal.AuditChange
+}
+
+type auditNoteKey struct{}
+
+func auditNoteFrom(ctx context.Context) *auditNote {
+	n, _ := ctx.Value(auditNoteKey{}).(*auditNote)
+	return n
+}
+
+// auditCreated records the ID of the resource created by this request.
+func auditCreated(r *http.Request, id string) {
+	if n := auditNoteFrom(r.Context()); n != nil {
+		n.resourceID = id
+	}
+}
+
+// auditChanged records the field-level diff of the resource updated by this request.
+func auditChanged(r *http.Request, before, after any) {
+	if n := auditNoteFrom(r.Context()); n != nil {
+		n.changes = upal.DiffFields(before, after)
+	}
+}
+
+// markWorkflowEdited records the request's user as wf's last editor. Only
+// user edits are stamped, so backfills and other system writes keep
+// updated_by naming the last person to change the workflow.
+func markWorkflowEdited(r *http.Request, wf *upal.WorkflowDefinition) {
+	wf.UpdatedBy, wf.UpdatedAt = upal.UserIDFromContext(r.Context()), time.Now()
+}
+
+// markPipelineEdited is markWorkflowEdited for pipelines.
+func markPipelineEdited(r *http.Request, p *upal.Pipeline) {
+	p.UpdatedBy, p.UpdatedAt = upal.UserIDFromContext(r.Context()), time.Now()
+}
+
+// auditing reports whether this request will produce an audit entry, so
+// handlers can skip loading the previous state otherwise.
+func auditing(r *http.Request) bool { return auditNoteFrom(r.Context()) != nil }
+
+// auditMiddleware records successful create/update/delete requests on the
+// audited resources. Collection POSTs are creates, and PUT/PATCH/DELETE on a
+// single item are updates and deletes. Action sub-routes such as
+// /workflows/{name}/run are not audited.
+func (s *Server) auditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.auditSvc == nil || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		note := &auditNote{}
+		r = r.WithContext(context.WithValue(r.Context(), auditNoteKey{}, note))
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= 400 {
+			return
+		}
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil {
+			return
+		}
+		resourceType, action, idParam, ok := classifyAuditRoute(r.Method, rctx.RoutePattern())
+		if !ok {
+			return
+		}
+		resourceID := note.resourceID
+		if idParam != "" {
+			resourceID = rctx.URLParam(idParam)
+		}
+		s.auditSvc.Record(r.Context(), &upal.AuditEntry{
+			Action:       action,
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			Method:       r.Method,
+			Path:         r.URL.Path,
+			Status:       status,
+			Changes:      note.changes,
+		})
+	})
+}
+
+// classifyAuditRoute maps a matched route pattern such as
+// "/api/workflows/{name}" to the audited resource, action, and the URL
+// parameter holding the resource ID.
+func classifyAuditRoute(method, pattern string) (resourceType string, action upal.AuditAction, idParam string, ok bool) {
+	rest, found := strings.CutPrefix(pattern, "/api/")
+	if !found {
+		return "", "", "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(rest, "/"), "/")
+	resourceType, ok = auditedResources[parts[0]]
+	if !ok {
+		return "", "", "", false
+	}
+	switch {
+	case len(parts) == 1 && method == http.MethodPost:
+		return resourceType, upal.AuditCreate, "", true
+	case len(parts) == 2 && strings.HasPrefix(parts[1], "{"):
+		idParam = strings.Trim(parts[1], "{}")
+		switch method {
+		case http.MethodPut, http.MethodPatch:
+			return resourceType, upal.AuditUpdate, idParam, true
+		case http.MethodDelete:
+			return resourceType, upal.AuditDelete, idParam, true
+		}
+	}
+	return "", "", "", false
+}
+
+// listAudit handles GET /api/audit?resource_type=&resource_id=&action=&limit=.
+func (s *Server) listAudit(w http.ResponseWriter, r *http.Request) {
+	if s.auditSvc == nil {
+		http.Error(w, "audit log not configured", http.StatusServiceUnavailable)
+		return
+	}
+	q := r.URL.Query()
+	filter := upal.AuditFilter{
+		ResourceType: q.Get("resource_type"),
+		ResourceID:   q.Get("resource_id"),
+		Action:       upal.AuditAction(q.Get("action")),
+		Limit:        100,
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = min(n, 1000)
+	}
+	entries, err := s.auditSvc.List(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, orEmpty(entries))
+}
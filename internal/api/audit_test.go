@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestAudit_WorkflowUpdateRecordsDiff(t *testing.T) {
+	srv := newTestServer()
+	srv.SetAuditService(services.NewAuditService(repository.NewMemoryAuditRepository()))
+	handler := srv.Handler()
+
+	do := func(method, path string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, &buf))
+		return w
+	}
+
+	wf := upal.WorkflowDefinition{
+		Name:        "audited",
+		Description: "first",
+		Nodes: []upal.NodeDefinition{
+			{ID: "in", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "in", To: "out"}},
+	}
+	if w := do("POST", "/api/workflows", wf); w.Code != http.StatusCreated {
+		t.Fatalf("create: %d %s", w.Code, w.Body.String())
+	}
+	wf.Description = "second"
+	wf.Nodes = wf.Nodes[1:]
+	wf.Edges = nil
+	if w := do("PUT", "/api/workflows/audited", wf); w.Code != http.StatusOK {
+		t.Fatalf("update: %d %s", w.Code, w.Body.String())
+	}
+	// Failed and non-CRUD requests are not audited.
+	do("PUT", "/api/workflows/audited", "not a workflow")
+	do("POST", "/api/workflows/audited/run", nil)
+
+	w := do("GET", "/api/audit?resource_type=workflow", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list audit: %d %s", w.Code, w.Body.String())
+	}
+	var entries []upal.AuditEntry
+	json.NewDecoder(w.Body).Decode(&entries)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+
+	update, create := entries[0], entries[1]
+	if create.Action != upal.AuditCreate || create.ResourceID != "audited" {
+		t.Errorf("create entry = %+v", create)
+	}
+	if update.Action != upal.AuditUpdate || update.ResourceID != "audited" || update.Actor != "default" {
+		t.Errorf("update entry = %+v", update)
+	}
+	changes := map[string]upal.AuditChange{}
+	for _, c := range update.Changes {
+		changes[c.Field] = c
+	}
+	if c := changes["description"]; c.Before != "first" || c.After != "second" {
+		t.Errorf("description change = %+v", c)
+	}
+	if c := changes["nodes"]; c.Summary != "2 → 1 items" {
+		t.Errorf("nodes change = %+v", c)
+	}
+	if _, ok := changes["name"]; ok {
+		t.Error("unchanged field recorded in diff")
+	}
+}
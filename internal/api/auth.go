@@ -118,7 +118,7 @@ func (s *Server) authCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	setRefreshTokenCookie(w, refreshToken)
-	redirectURL := "/?token=" + accessToken
+	redirectURL := s.basePath + "/?token=" + accessToken
 	if s.frontendURL != "" {
 		redirectURL = s.frontendURL + "/?token=" + accessToken
 	}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/soochol/upal/internal/generate"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// BackfillRequest is the optional body of POST /api/admin/backfill-descriptions.
+// With no filters every workflow and pipeline is considered. Otherwise only
+// workflows named in Workflows or carrying one of Tags, and pipelines whose
+// ID or name is in Pipelines, are processed.
+type BackfillRequest struct {
+	Workflows []string `json:"workflows,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Pipelines []string `json:"pipelines,omitempty"`
+}
+
+func (r BackfillRequest) filtered() bool {
+	return len(r.Workflows) > 0 || len(r.Tags) > 0 || len(r.Pipelines) > 0
+}
+
+func (r BackfillRequest) matchWorkflow(wf *upal.WorkflowDefinition) bool {
+	if !r.filtered() || slices.Contains(r.Workflows, wf.Name) {
+		return true
+	}
+	for _, tag := range wf.Tags {
+		if slices.Contains(r.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r BackfillRequest) matchPipeline(p *upal.Pipeline) bool {
+	return !r.filtered() || slices.Contains(r.Pipelines, p.ID) || slices.Contains(r.Pipelines, p.Name)
+}
+
+// BackfillProgress reports the outcome for one workflow or pipeline. Done and
+// Total count items processed so far across both kinds.
+type BackfillProgress struct {
+	Type               string `json:"type"` // always "progress"
+	Kind               string `json:"kind"` // "workflow" | "pipeline"
+	Name               string `json:"name"`
+	ID                 string `json:"id,omitempty"`
+	DescriptionUpdated bool   `json:"description_updated,omitempty"`
+	NodesUpdated       int    `json:"nodes_updated,omitempty"`
+	StagesUpdated      int    `json:"stages_updated,omitempty"`
+	Error              string `json:"error,omitempty"`
+	Done               int    `json:"done"`
+	Total              int    `json:"total"`
+}
+
+// BackfillSummary totals a backfill run. Items is only set on the plain JSON
+// response; the NDJSON stream emits each item as it completes instead.
+type BackfillSummary struct {
+	Type             string             `json:"type"` // always "summary"
+	WorkflowsScanned int                `json:"workflows_scanned"`
+	WorkflowsUpdated int                `json:"workflows_updated"`
+	NodesUpdated     int                `json:"nodes_updated"`
+	PipelinesScanned int                `json:"pipelines_scanned"`
+	PipelinesUpdated int                `json:"pipelines_updated"`
+	StagesUpdated    int                `json:"stages_updated"`
+	Items            []BackfillProgress `json:"items,omitempty"`
+}
+
+// adminBackfillDescriptions handles POST /api/admin/backfill-descriptions and
+// its older alias POST /api/generate/backfill; both require an admin.
+// It fills in missing workflow, node and stage descriptions for the selected
+// items and skips anything already described, so repeated calls are cheap.
+// With ?format=ndjson one progress line is streamed per item followed by the
+// summary; otherwise the summary is returned once everything is done.
+func (s *Server) adminBackfillDescriptions(w http.ResponseWriter, r *http.Request) {
+	if !s.requireGenerator(w, r) {
+		return
+	}
+	var req BackfillRequest
+	if r.ContentLength != 0 && !decodeJSON(w, r, &req) {
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "ndjson" {
+		http.Error(w, "format must be json or ndjson", http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+
+	var wfs []*upal.WorkflowDefinition
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, wf := range all {
+		if req.matchWorkflow(wf) {
+			wfs = append(wfs, wf)
+		}
+	}
+	var pipes []*upal.Pipeline
+	if s.pipelineSvc != nil {
+		allPipes, err := s.pipelineSvc.List(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, p := range allPipes {
+			if req.matchPipeline(p) {
+				pipes = append(pipes, p)
+			}
+		}
+	}
+
+	summary := BackfillSummary{Type: "summary", WorkflowsScanned: len(wfs), PipelinesScanned: len(pipes)}
+	total := len(wfs) + len(pipes)
+
+	var emit func(BackfillProgress)
+	if format == "ndjson" {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		emit = func(p BackfillProgress) {
+			enc.Encode(p)
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	} else {
+		emit = func(p BackfillProgress) { summary.Items = append(summary.Items, p) }
+	}
+
+	done := 0
+	for _, wf := range wfs {
+		done++
+		item := BackfillProgress{Type: "progress", Kind: "workflow", Name: wf.Name, Done: done, Total: total}
+		item.DescriptionUpdated, item.NodesUpdated = s.generator.BackfillWorkflow(ctx, wf)
+		if item.DescriptionUpdated || item.NodesUpdated > 0 {
+			if err := s.repo.Update(ctx, wf.Name, wf); err != nil {
+				slog.Warn("backfill: save workflow failed", "name", wf.Name, "err", err)
+				item.Error = err.Error()
+			} else {
+				summary.WorkflowsUpdated++
+				summary.NodesUpdated += item.NodesUpdated
+			}
+		}
+		emit(item)
+	}
+	for _, p := range pipes {
+		done++
+		item := BackfillProgress{Type: "progress", Kind: "pipeline", Name: p.Name, ID: p.ID, Done: done, Total: total}
+		if item.StagesUpdated = generate.BackfillStageDescriptions(p); item.StagesUpdated > 0 {
+			if err := s.pipelineSvc.Update(ctx, p); err != nil {
+				slog.Warn("backfill: save pipeline failed", "id", p.ID, "err", err)
+				item.Error = err.Error()
+			} else {
+				summary.PipelinesUpdated++
+				summary.StagesUpdated += item.StagesUpdated
+			}
+		}
+		emit(item)
+	}
+
+	if format == "ndjson" {
+		json.NewEncoder(w).Encode(summary)
+		return
+	}
+	writeJSON(w, summary)
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/soochol/upal/internal/config"
+	"github.com/soochol/upal/internal/generate"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// describeLLM answers every request with a fixed description and counts calls.
+type describeLLM struct{ calls atomic.Int32 }
+
+func (l *describeLLM) Name() string { return "describe" }
+
+func (l *describeLLM) GenerateContent(context.Context, *adkmodel.LLMRequest, bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	l.calls.Add(1)
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText("생성된 설명입니다.", genai.RoleModel)}, nil)
+	}
+}
+
+func newBackfillTestServer(t *testing.T) (*Server, *describeLLM) {
+	t.Helper()
+	srv := newTestServer()
+	llm := &describeLLM{}
+	srv.SetGenerator(generate.New(llm, "m", noopSkills{}, nil, nil), "m")
+	srv.SetPipelineService(services.NewPipelineService(repository.NewMemoryPipelineRepository(), repository.NewMemoryPipelineRunRepository()))
+
+	ctx := context.Background()
+	for _, wf := range []*upal.WorkflowDefinition{
+		{Name: "undescribed", Tags: []string{"news"}, Nodes: []upal.NodeDefinition{
+			{ID: "in", Type: upal.NodeTypeInput, Config: map[string]any{"label": "주제"}},
+			{ID: "write", Type: upal.NodeTypeAgent, Config: map[string]any{"prompt": "write"}},
+		}},
+		{Name: "described", Description: "이미 설명이 있습니다.", Nodes: []upal.NodeDefinition{
+			{ID: "write", Type: upal.NodeTypeAgent, Config: map[string]any{"description": "이미 있음"}},
+		}},
+		{Name: "other", Nodes: []upal.NodeDefinition{
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		}},
+	} {
+		if err := srv.repo.Create(ctx, wf); err != nil {
+			t.Fatalf("create workflow: %v", err)
+		}
+	}
+	if err := srv.pipelineSvc.Create(ctx, &upal.Pipeline{ID: "pipe-1", Name: "daily", Stages: []upal.Stage{
+		{ID: "s1", Type: "approval"},
+		{ID: "s2", Type: "notification", Description: "이미 있음"},
+	}}); err != nil {
+		t.Fatalf("create pipeline: %v", err)
+	}
+	return srv, llm
+}
+
+func postBackfill(t *testing.T, srv *Server, query, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/admin/backfill-descriptions"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminBackfill_FillsOnlyMissing(t *testing.T) {
+	srv, llm := newBackfillTestServer(t)
+
+	w := postBackfill(t, srv, "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var sum BackfillSummary
+	json.Unmarshal(w.Body.Bytes(), &sum)
+	// undescribed: workflow + 2 nodes; other: workflow + 1 node; daily: 1 stage.
+	if sum.WorkflowsScanned != 3 || sum.WorkflowsUpdated != 2 || sum.NodesUpdated != 3 ||
+		sum.PipelinesScanned != 1 || sum.PipelinesUpdated != 1 || sum.StagesUpdated != 1 {
+		t.Errorf("unexpected summary: %+v", sum)
+	}
+	if len(sum.Items) != 4 || sum.Items[3].Done != 4 || sum.Items[3].Total != 4 {
+		t.Errorf("unexpected progress items: %+v", sum.Items)
+	}
+	// Two workflow descriptions and one agent node; input/output nodes are rule-based.
+	if got := llm.calls.Load(); got != 3 {
+		t.Errorf("LLM calls = %d, want 3", got)
+	}
+
+	ctx := context.Background()
+	described, _ := srv.repo.Get(ctx, "described")
+	if described.Description != "이미 설명이 있습니다." || described.Nodes[0].Config["description"] != "이미 있음" {
+		t.Errorf("existing descriptions were overwritten: %+v", described)
+	}
+	undescribed, _ := srv.repo.Get(ctx, "undescribed")
+	if undescribed.Description != "생성된 설명입니다." || undescribed.Nodes[1].Config["description"] != "생성된 설명입니다." {
+		t.Errorf("descriptions not filled: %+v", undescribed)
+	}
+	p, _ := srv.pipelineSvc.Get(ctx, "pipe-1")
+	if p.Stages[0].Description == "" || p.Stages[1].Description != "이미 있음" {
+		t.Errorf("unexpected stage descriptions: %+v", p.Stages)
+	}
+
+	// A second pass finds nothing left to describe.
+	w = postBackfill(t, srv, "", "")
+	sum = BackfillSummary{}
+	json.Unmarshal(w.Body.Bytes(), &sum)
+	if sum.WorkflowsUpdated != 0 || sum.NodesUpdated != 0 || sum.StagesUpdated != 0 {
+		t.Errorf("second pass updated again: %+v", sum)
+	}
+	if got := llm.calls.Load(); got != 3 {
+		t.Errorf("LLM calls after second pass = %d, want 3", got)
+	}
+}
+
+func TestAdminBackfill_KeepsLastEditor(t *testing.T) {
+	srv, _ := newBackfillTestServer(t)
+	ctx := context.Background()
+
+	w := doJSON(srv, "PUT", "/api/workflows/other", `{"name":"other","nodes":[{"id":"out","type":"output","config":{}}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("edit: got %d: %s", w.Code, w.Body.String())
+	}
+	edited, _ := srv.repo.Get(ctx, "other")
+	if edited.UpdatedBy != "default" || edited.UpdatedAt.IsZero() {
+		t.Fatalf("edit not stamped: %s at %v", edited.UpdatedBy, edited.UpdatedAt)
+	}
+	editedAt := edited.UpdatedAt
+
+	if w := postBackfill(t, srv, "", `{"workflows":["other"]}`); w.Code != http.StatusOK {
+		t.Fatalf("backfill: got %d: %s", w.Code, w.Body.String())
+	}
+	got, _ := srv.repo.Get(ctx, "other")
+	if got.Description == "" {
+		t.Fatal("backfill did not describe the workflow")
+	}
+	if got.UpdatedBy != "default" || !got.UpdatedAt.Equal(editedAt) {
+		t.Errorf("backfill restamped the workflow: %s at %v, want %v", got.UpdatedBy, got.UpdatedAt, editedAt)
+	}
+}
+
+func TestAdminBackfill_TargetsSubsetAndStreams(t *testing.T) {
+	srv, _ := newBackfillTestServer(t)
+
+	w := postBackfill(t, srv, "?format=ndjson", `{"tags":["news"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var lines []map[string]any
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatalf("decode line %q: %v", sc.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected progress + summary lines, got %v", lines)
+	}
+	if lines[0]["type"] != "progress" || lines[0]["name"] != "undescribed" || lines[0]["nodes_updated"] != 2.0 {
+		t.Errorf("unexpected progress line: %v", lines[0])
+	}
+	if lines[1]["type"] != "summary" || lines[1]["workflows_updated"] != 1.0 || lines[1]["pipelines_scanned"] != 0.0 {
+		t.Errorf("unexpected summary line: %v", lines[1])
+	}
+
+	other, _ := srv.repo.Get(context.Background(), "other")
+	if other.Description != "" {
+		t.Errorf("workflow outside the target was described: %q", other.Description)
+	}
+
+	// Name targeting reaches pipelines by name as well as workflows.
+	w = postBackfill(t, srv, "", `{"workflows":["other"],"pipelines":["daily"]}`)
+	var sum BackfillSummary
+	json.Unmarshal(w.Body.Bytes(), &sum)
+	if sum.WorkflowsScanned != 1 || sum.WorkflowsUpdated != 1 || sum.PipelinesUpdated != 1 {
+		t.Errorf("unexpected summary: %+v", sum)
+	}
+}
+
+func TestAdminBackfill_InvalidFormat(t *testing.T) {
+	srv, _ := newBackfillTestServer(t)
+	if w := postBackfill(t, srv, "?format=xml", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestAdminBackfill_RequiresAdmin(t *testing.T) {
+	srv, llm := newBackfillTestServer(t)
+	srv.SetAuthService(services.NewAuthService(nil, config.AuthConfig{
+		Google:    config.OAuthProviderConfig{ClientID: "id", ClientSecret: "secret"},
+		JWTSecret: testJWTSecret,
+		Admins:    []string{"user-admin"},
+	}, "http://localhost:8080"))
+
+	post := func(path, userID string) int {
+		token, _, err := srv.authSvc.GenerateTokens(context.Background(), &upal.User{ID: userID}, "")
+		if err != nil {
+			t.Fatalf("GenerateTokens: %v", err)
+		}
+		req := httptest.NewRequest("POST", path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w.Code
+	}
+	for _, path := range []string{"/api/admin/backfill-descriptions", "/api/generate/backfill"} {
+		if code := post(path, "user-other"); code != http.StatusForbidden {
+			t.Errorf("%s as non-admin: got %d, want 403", path, code)
+		}
+	}
+	if llm.calls.Load() != 0 {
+		t.Errorf("non-admin backfill called the model %d times", llm.calls.Load())
+	}
+	if code := post("/api/generate/backfill", "user-admin"); code != http.StatusOK {
+		t.Errorf("alias as admin: got %d, want 200", code)
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type baseURLKey struct{}
+
+// publicBaseURL returns the externally reachable base URL of the server,
+// including the configured base path. The configured public URL wins;
+// otherwise it is derived from the X-Forwarded-Proto/X-Forwarded-Host
+// headers set by a reverse proxy. fallback is used when neither is
+// available.
+func (s *Server) publicBaseURL(r *http.Request, fallback string) string {
+	return s.withBasePath(s.publicOrigin(r, fallback))
+}
+
+func (s *Server) publicOrigin(r *http.Request, fallback string) string {
+	if s.publicURL != "" {
+		return strings.TrimRight(s.publicURL, "/")
+	}
+	if host := firstForwardedValue(r.Header.Get("X-Forwarded-Host")); host != "" {
+		proto := firstForwardedValue(r.Header.Get("X-Forwarded-Proto"))
+		if proto == "" {
+			proto = "https"
+		}
+		return proto + "://" + host
+	}
+	return fallback
+}
+
+// withBasePath appends the base path to base unless base already ends with
+// it, as a public URL configured with the sub-path does.
+func (s *Server) withBasePath(base string) string {
+	if s.basePath == "" || strings.HasSuffix(base, s.basePath) {
+		return base
+	}
+	return base + s.basePath
+}
+
+// mountAtBasePath serves h under the base path with the prefix stripped, so
+// routes, middleware and the static frontend see root-relative paths. The
+// bare base path redirects to its trailing-slash form; other paths are 404.
+func (s *Server) mountAtBasePath(h http.Handler) http.Handler {
+	stripped := http.StripPrefix(s.basePath, h)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, s.basePath+"/"):
+			stripped.ServeHTTP(w, r)
+		case r.URL.Path == s.basePath:
+			target := s.basePath + "/"
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// firstForwardedValue returns the first entry of a comma-separated
+// X-Forwarded-* header, which is the one set by the outermost proxy.
+func firstForwardedValue(v string) string {
+	if i := strings.IndexByte(v, ','); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v)
+}
+
+// withBaseURL stores the resolved public base URL in the request context so
+// handlers that only receive a context (e.g. the A2A card producer) can use it.
+func (s *Server) withBaseURL(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := s.publicBaseURL(r, s.a2aBaseURL)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), baseURLKey{}, base)))
+	})
+}
+
+// baseURLFromContext returns the base URL stored by withBaseURL, or fallback.
+func baseURLFromContext(ctx context.Context, fallback string) string {
+	if v, ok := ctx.Value(baseURLKey{}).(string); ok && v != "" {
+		return v
+	}
+	return fallback
+}
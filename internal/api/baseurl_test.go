@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/soochol/upal/internal/config"
+)
+
+func TestCreateTrigger_WebhookURLFromForwardedHeaders(t *testing.T) {
+	srv := newTestServerWithTriggers()
+
+	req := httptest.NewRequest("POST", "/api/triggers", strings.NewReader(`{"workflow_name": "wf"}`))
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "upal.example.com, internal-proxy")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		WebhookURL string `json:"webhook_url"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if !strings.HasPrefix(resp.WebhookURL, "https://upal.example.com/api/hooks/trig-") {
+		t.Errorf("webhook_url: got %q", resp.WebhookURL)
+	}
+}
+
+func TestCreateTrigger_WebhookURLFromPublicURL(t *testing.T) {
+	srv := newTestServerWithTriggers()
+	srv.SetServerConfig(config.ServerConfig{PublicURL: "https://public.example.com/"}, config.GeneratorConfig{})
+
+	req := httptest.NewRequest("POST", "/api/triggers", strings.NewReader(`{"workflow_name": "wf"}`))
+	req.Header.Set("X-Forwarded-Host", "ignored.example.com")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	var resp struct {
+		WebhookURL string `json:"webhook_url"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if !strings.HasPrefix(resp.WebhookURL, "https://public.example.com/api/hooks/trig-") {
+		t.Errorf("webhook_url: got %q", resp.WebhookURL)
+	}
+}
+
+func TestAgentCard_URLFromForwardedHeaders(t *testing.T) {
+	srv := newTestServer()
+	srv.SetA2ABaseURL("http://localhost:8080")
+
+	req := httptest.NewRequest("GET", "/.well-known/agent-card.json", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "agents.example.com")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	var card a2a.AgentCard
+	if err := json.Unmarshal(w.Body.Bytes(), &card); err != nil {
+		t.Fatalf("failed to decode agent card: %v", err)
+	}
+	if card.URL != "https://agents.example.com/a2a" {
+		t.Errorf("expected URL 'https://agents.example.com/a2a', got %q", card.URL)
+	}
+}
+
+func TestAgentCard_URLFromPublicURL(t *testing.T) {
+	srv := newTestServer()
+	srv.SetA2ABaseURL("http://localhost:8080")
+	srv.SetServerConfig(config.ServerConfig{PublicURL: "https://public.example.com"}, config.GeneratorConfig{})
+
+	req := httptest.NewRequest("GET", "/.well-known/agent-card.json", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	var card a2a.AgentCard
+	if err := json.Unmarshal(w.Body.Bytes(), &card); err != nil {
+		t.Fatalf("failed to decode agent card: %v", err)
+	}
+	if card.URL != "https://public.example.com/a2a" {
+		t.Errorf("expected URL 'https://public.example.com/a2a', got %q", card.URL)
+	}
+}
+
+func TestBasePath_RoutesMountedUnderPrefix(t *testing.T) {
+	srv := newTestServerWithTriggers()
+	srv.SetA2ABaseURL("http://localhost:8080")
+	srv.SetServerConfig(config.ServerConfig{BasePath: "upal/"}, config.GeneratorConfig{})
+	h := srv.Handler()
+
+	for path, want := range map[string]int{
+		"/upal/api/workflows":               http.StatusOK,
+		"/upal/.well-known/agent-card.json": http.StatusOK,
+		"/upal/api/hooks/missing-trigger":   http.StatusNotFound,
+		"/api/workflows":                    http.StatusNotFound,
+		"/.well-known/agent-card.json":      http.StatusNotFound,
+		"/upalx/api/workflows":              http.StatusNotFound,
+		"/upal":                             http.StatusMovedPermanently,
+	} {
+		method := "GET"
+		if strings.Contains(path, "/hooks/") {
+			method = "POST"
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		if w.Code != want {
+			t.Errorf("%s %s: got %d, want %d", method, path, w.Code, want)
+		}
+		if want == http.StatusMovedPermanently && w.Header().Get("Location") != "/upal/" {
+			t.Errorf("%s: redirect to %q, want /upal/", path, w.Header().Get("Location"))
+		}
+	}
+}
+
+func TestBasePath_AdvertisedURLs(t *testing.T) {
+	srv := newTestServerWithTriggers()
+	srv.SetA2ABaseURL("http://localhost:8080")
+	srv.SetServerConfig(config.ServerConfig{BasePath: "/upal"}, config.GeneratorConfig{})
+	h := srv.Handler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/upal/.well-known/agent-card.json", nil))
+	var card a2a.AgentCard
+	if err := json.Unmarshal(w.Body.Bytes(), &card); err != nil {
+		t.Fatalf("failed to decode agent card: %v", err)
+	}
+	if card.URL != "http://localhost:8080/upal/a2a" {
+		t.Errorf("agent card URL: got %q, want http://localhost:8080/upal/a2a", card.URL)
+	}
+
+	req := httptest.NewRequest("POST", "/upal/api/triggers", strings.NewReader(`{"workflow_name": "wf"}`))
+	req.Header.Set("X-Forwarded-Host", "shared.example.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp struct {
+		WebhookURL string `json:"webhook_url"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if !strings.HasPrefix(resp.WebhookURL, "https://shared.example.com/upal/api/hooks/trig-") {
+		t.Errorf("webhook_url: got %q", resp.WebhookURL)
+	}
+
+	// A public URL that already carries the base path is not prefixed twice.
+	srv.SetServerConfig(config.ServerConfig{BasePath: "/upal", PublicURL: "https://shared.example.com/upal/"}, config.GeneratorConfig{})
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/upal/.well-known/agent-card.json", nil))
+	json.Unmarshal(w.Body.Bytes(), &card)
+	if card.URL != "https://shared.example.com/upal/a2a" {
+		t.Errorf("agent card URL with public URL: got %q", card.URL)
+	}
+}
@@ -39,8 +39,7 @@ func (s *Server) configureNode(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "node_type is required", http.StatusBadRequest)
 		return
 	}
-	if s.generator == nil {
-		http.Error(w, "generator not configured (no providers available)", http.StatusServiceUnavailable)
+	if !s.requireGenerator(w, r) {
 		return
 	}
 
@@ -76,8 +76,7 @@ func (s *Server) configurePipeline(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "message is required", http.StatusBadRequest)
 		return
 	}
-	if s.generator == nil {
-		http.Error(w, "generator not configured (no providers available)", http.StatusServiceUnavailable)
+	if !s.requireGenerator(w, r) {
 		return
 	}
 
@@ -20,6 +20,7 @@ func (s *Server) createConnection(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	auditCreated(r, conn.ID)
 	writeJSONStatus(w, http.StatusCreated, conn.Safe())
 }
 
@@ -49,10 +50,18 @@ func (s *Server) updateConnection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	conn.ID = id
+	var before *upal.ConnectionSafe
+	if auditing(r) {
+		if prev, err := s.connectionSvc.Get(r.Context(), id); err == nil {
+			safe := prev.Safe()
+			before = &safe
+		}
+	}
 	if err := s.connectionSvc.Update(r.Context(), &conn); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	auditChanged(r, before, conn.Safe())
 	writeJSON(w, conn.Safe())
 }
 
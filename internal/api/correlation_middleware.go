@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// runIDHeader carries the correlation ID on requests and responses.
+const runIDHeader = "X-Run-ID"
+
+// CorrelationMiddleware attaches a correlation ID to the request context so
+// every log line emitted while serving it can be tied together. An ID sent
+// by the client (X-Run-ID or X-Request-ID) is reused; otherwise one is
+// generated. The ID is echoed back in the X-Run-ID response header.
+func CorrelationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(runIDHeader)
+		if id == "" {
+			id = r.Header.Get("X-Request-ID")
+		}
+		if id == "" {
+			id = upal.GenerateID("req")
+		}
+		w.Header().Set(runIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(upal.WithRunID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/logging"
+)
+
+func TestCorrelationMiddleware_LogsShareRunID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	h := CorrelationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "first")
+		logger.With("node", "n1").WarnContext(r.Context(), "second")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/workflows", nil))
+
+	id := w.Header().Get(runIDHeader)
+	if id == "" {
+		t.Fatal("expected X-Run-ID response header")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("unmarshal log line: %v", err)
+		}
+		if rec["run_id"] != id {
+			t.Errorf("log %q: run_id = %v, want %q", rec["msg"], rec["run_id"], id)
+		}
+	}
+}
+
+func TestCorrelationMiddleware_ReusesClientID(t *testing.T) {
+	h := CorrelationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "client-123")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if got := w.Header().Get(runIDHeader); got != "client-123" {
+		t.Errorf("X-Run-ID: got %q, want %q", got, "client-123")
+	}
+}
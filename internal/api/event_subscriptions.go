@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func (s *Server) createEventSubscription(w http.ResponseWriter, r *http.Request) {
+	sub := upal.EventSubscription{Enabled: true}
+	if !decodeJSON(w, r, &sub) {
+		return
+	}
+	if err := validateEventSubscription(&sub); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sub.ID = upal.NewEventSubscriptionID()
+	sub.CreatedAt = time.Now()
+	if err := s.eventSubRepo.Create(r.Context(), &sub); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSONStatus(w, http.StatusCreated, sub)
+}
+
+func (s *Server) listEventSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.eventSubRepo.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, orEmpty(subs))
+}
+
+func (s *Server) getEventSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, err := s.eventSubRepo.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "event subscription not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, sub)
+}
+
+func (s *Server) updateEventSubscription(w http.ResponseWriter, r *http.Request) {
+	existing, err := s.eventSubRepo.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "event subscription not found", http.StatusNotFound)
+		return
+	}
+	sub := upal.EventSubscription{Enabled: true}
+	if !decodeJSON(w, r, &sub) {
+		return
+	}
+	if err := validateEventSubscription(&sub); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sub.ID = existing.ID
+	sub.CreatedAt = existing.CreatedAt
+	if err := s.eventSubRepo.Update(r.Context(), &sub); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, sub)
+}
+
+func (s *Server) deleteEventSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := s.eventSubRepo.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "event subscription not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateEventSubscription(sub *upal.EventSubscription) error {
+	if sub.ConnectionID == "" {
+		return errors.New("connection_id is required")
+	}
+	for _, t := range sub.Events {
+		if !upal.ValidEventType(t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	if sub.RetryPolicy != nil {
+		if err := sub.RetryPolicy.Validate(); err != nil {
+			return fmt.Errorf("retry_policy: %w", err)
+		}
+	}
+	return nil
+}
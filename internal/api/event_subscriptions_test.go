@@ -0,0 +1,69 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestEventSubscription_CRUD(t *testing.T) {
+	srv := newTestServer()
+	srv.SetEventSubscriptionRepo(repository.NewMemoryEventSubscriptionRepository())
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/api/event-subscriptions", `{"name":"crm","connection_id":"conn-1","events":["run.completed"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: got %d; body: %s", w.Code, w.Body.String())
+	}
+	var created upal.EventSubscription
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.ID == "" || !created.Enabled || created.CreatedAt.IsZero() {
+		t.Fatalf("create response: %+v", created)
+	}
+
+	w = do("PUT", "/api/event-subscriptions/"+created.ID, `{"name":"crm","connection_id":"conn-1","enabled":false}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: got %d; body: %s", w.Code, w.Body.String())
+	}
+	w = do("GET", "/api/event-subscriptions/"+created.ID, "")
+	var got upal.EventSubscription
+	json.Unmarshal(w.Body.Bytes(), &got)
+	if got.Enabled || len(got.Events) != 0 || !got.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("after update: %+v", got)
+	}
+
+	if w := do("DELETE", "/api/event-subscriptions/"+created.ID, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("delete: got %d", w.Code)
+	}
+	if w := do("GET", "/api/event-subscriptions/"+created.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("get after delete: got %d, want 404", w.Code)
+	}
+}
+
+func TestEventSubscription_CreateValidation(t *testing.T) {
+	srv := newTestServer()
+	srv.SetEventSubscriptionRepo(repository.NewMemoryEventSubscriptionRepository())
+	for _, body := range []string{
+		`{"name":"no connection"}`,
+		`{"connection_id":"c","events":["run.started"]}`,
+		`{"connection_id":"c","retry_policy":{"max_retries":-1}}`,
+	} {
+		req := httptest.NewRequest("POST", "/api/event-subscriptions", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", body, w.Code)
+		}
+	}
+}
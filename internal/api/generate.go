@@ -16,11 +16,13 @@ import (
 type GenerateRequest struct {
 	Description      string                   `json:"description"`
 	ExistingWorkflow *upal.WorkflowDefinition `json:"existing_workflow,omitempty"`
+	Language         string                   `json:"language,omitempty"`
 }
 
 type GeneratePipelineRequest struct {
 	Description      string         `json:"description"`
 	ExistingPipeline *upal.Pipeline `json:"existing_pipeline,omitempty"`
+	Language         string         `json:"language,omitempty"`
 }
 
 func (s *Server) generatePipeline(w http.ResponseWriter, r *http.Request) {
@@ -32,8 +34,7 @@ func (s *Server) generatePipeline(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "description is required", http.StatusBadRequest)
 		return
 	}
-	if s.generator == nil {
-		http.Error(w, "generator not configured (no providers available)", http.StatusServiceUnavailable)
+	if !s.requireGenerator(w, r) {
 		return
 	}
 
@@ -54,6 +55,7 @@ func (s *Server) generatePipeline(w http.ResponseWriter, r *http.Request) {
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
+		ctx = generate.WithLanguage(ctx, req.Language)
 
 		bundle, err := s.generator.GeneratePipelineBundle(ctx, req.Description, req.ExistingPipeline, workflowSummaries, pipelineSummaries)
 		if err != nil {
@@ -90,8 +92,7 @@ func (s *Server) generateWorkflow(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "description is required", http.StatusBadRequest)
 		return
 	}
-	if s.generator == nil {
-		http.Error(w, "generator not configured (no providers available)", http.StatusServiceUnavailable)
+	if !s.requireGenerator(w, r) {
 		return
 	}
 
@@ -107,6 +108,7 @@ func (s *Server) generateWorkflow(w http.ResponseWriter, r *http.Request) {
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
+		ctx = generate.WithLanguage(ctx, req.Language)
 
 		wf, err := s.generator.Generate(ctx, req.Description, req.ExistingWorkflow, workflowSummaries)
 		if err != nil {
@@ -139,8 +141,7 @@ func (s *Server) getGeneration(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) generateWorkflowThumbnail(w http.ResponseWriter, r *http.Request) {
-	if s.generator == nil {
-		http.Error(w, "generator not configured (no providers available)", http.StatusServiceUnavailable)
+	if !s.requireGenerator(w, r) {
 		return
 	}
 
@@ -169,55 +170,8 @@ func (s *Server) generateWorkflowThumbnail(w http.ResponseWriter, r *http.Reques
 	writeJSON(w, map[string]string{"thumbnail_svg": svg})
 }
 
-func (s *Server) backfillDescriptions(w http.ResponseWriter, r *http.Request) {
-	if s.generator == nil {
-		http.Error(w, "generator not configured (no providers available)", http.StatusServiceUnavailable)
-		return
-	}
-
-	workflowsUpdated := 0
-	nodesUpdated := 0
-	stagesUpdated := 0
-
-	if wfs, err := s.repo.List(r.Context()); err == nil {
-		for _, wf := range s.generator.BackfillWorkflowDescriptions(r.Context(), wfs) {
-			if saveErr := s.repo.Update(r.Context(), wf.Name, wf); saveErr != nil {
-				slog.Warn("backfill: save workflow failed", "name", wf.Name, "err", saveErr)
-				continue
-			}
-			workflowsUpdated++
-		}
-		for _, wf := range s.generator.BackfillNodeDescriptions(r.Context(), wfs) {
-			if saveErr := s.repo.Update(r.Context(), wf.Name, wf); saveErr != nil {
-				slog.Warn("backfill: save workflow nodes failed", "name", wf.Name, "err", saveErr)
-				continue
-			}
-			nodesUpdated++
-		}
-	}
-
-	if pipelines, err := s.pipelineSvc.List(r.Context()); err == nil {
-		for _, p := range pipelines {
-			if generate.BackfillStageDescriptions(p) {
-				if saveErr := s.pipelineSvc.Update(r.Context(), p); saveErr != nil {
-					slog.Warn("backfill: save pipeline failed", "id", p.ID, "err", saveErr)
-					continue
-				}
-				stagesUpdated++
-			}
-		}
-	}
-
-	writeJSON(w, map[string]int{
-		"workflows_updated": workflowsUpdated,
-		"nodes_updated":     nodesUpdated,
-		"stages_updated":    stagesUpdated,
-	})
-}
-
 func (s *Server) generatePipelineThumbnail(w http.ResponseWriter, r *http.Request) {
-	if s.generator == nil {
-		http.Error(w, "generator not configured (no providers available)", http.StatusServiceUnavailable)
+	if !s.requireGenerator(w, r) {
 		return
 	}
 
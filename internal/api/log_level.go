@@ -0,0 +1,44 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/soochol/upal/internal/logging"
+)
+
+// SetLogLevel enables /api/admin/log-level, which reads and changes level.
+// level must be the one the default logger's handler was built with.
+func (s *Server) SetLogLevel(level *slog.LevelVar) { s.logLevel = level }
+
+// LogLevelRequest is the body of POST /api/admin/log-level.
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// getLogLevel handles GET /api/admin/log-level.
+func (s *Server) getLogLevel(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"level": s.logLevel.Level().String()})
+}
+
+// setLogLevel handles POST /api/admin/log-level. The change applies to every
+// logger immediately and lasts until the next change or restart.
+func (s *Server) setLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req LogLevelRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Level == "" {
+		http.Error(w, "level is required", http.StatusBadRequest)
+		return
+	}
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	previous := s.logLevel.Level()
+	s.logLevel.Set(level)
+	slog.InfoContext(r.Context(), "log level changed", "from", previous, "to", level)
+	writeJSON(w, map[string]string{"level": level.String(), "previous": previous.String()})
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/logging"
+)
+
+func TestLogLevel_ChangeAffectsDebugLogs(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	h, err := logging.NewHandler(&buf, logging.FormatText, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(h)
+
+	srv := newTestServer()
+	srv.SetLogLevel(level)
+	do := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest(method, "/api/admin/log-level", strings.NewReader(body)))
+		return w
+	}
+
+	logger.Debug("before")
+	if w := do("POST", `{"level":"debug"}`); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"level":"DEBUG"`) {
+		t.Fatalf("set debug: got %d %s", w.Code, w.Body.String())
+	}
+	logger.Debug("during")
+	if w := do("POST", `{"level":"warn"}`); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"previous":"DEBUG"`) {
+		t.Fatalf("set warn: got %d %s", w.Code, w.Body.String())
+	}
+	logger.Debug("after")
+
+	out := buf.String()
+	if strings.Contains(out, "msg=before") || !strings.Contains(out, "msg=during") || strings.Contains(out, "msg=after") {
+		t.Errorf("debug output did not follow the level:\n%s", out)
+	}
+	if w := do("GET", ""); !strings.Contains(w.Body.String(), `"level":"WARN"`) {
+		t.Errorf("GET log-level = %s", w.Body.String())
+	}
+	if w := do("POST", `{"level":"loud"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown level: got %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// SetMaintenanceService enables /api/admin/maintenance and makes run-start
+// endpoints honour maintenance mode.
+func (s *Server) SetMaintenanceService(svc *services.MaintenanceService) { s.maintenanceSvc = svc }
+
+// MaintenanceRequest is the body of POST /api/admin/maintenance.
+type MaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// getMaintenance handles GET /api/admin/maintenance.
+func (s *Server) getMaintenance(w http.ResponseWriter, r *http.Request) {
+	st, err := s.maintenanceSvc.State(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, st)
+}
+
+// setMaintenance handles POST /api/admin/maintenance.
+func (s *Server) setMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req MaintenanceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	st, err := s.maintenanceSvc.Set(r.Context(), req.Enabled, req.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, st)
+}
+
+// rejectInMaintenance guards endpoints that start new work. While maintenance
+// mode is on they answer 503 with a "maintenance" status instead of running.
+func (s *Server) rejectInMaintenance(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.maintenanceSvc == nil || !s.maintenanceSvc.InMaintenance() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		writeJSONStatus(w, http.StatusServiceUnavailable, map[string]any{
+			"status": "maintenance",
+			"error":  upal.ErrMaintenance.Error(),
+		})
+	})
+}
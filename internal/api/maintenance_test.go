@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/config"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestMaintenanceMode_RejectsRuns(t *testing.T) {
+	srv := newTestServer()
+	srv.SetMaintenanceService(services.NewMaintenanceService(context.Background(), repository.NewMemoryMaintenanceRepository()))
+	seedWorkflow(t, srv, "maint-wf")
+	h := srv.Handler()
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/api/admin/maintenance", `{"enabled":true,"reason":"db migration"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("enable maintenance: got %d: %s", w.Code, w.Body.String())
+	}
+	var st upal.MaintenanceState
+	json.Unmarshal(w.Body.Bytes(), &st)
+	if !st.Enabled || st.Reason != "db migration" {
+		t.Fatalf("unexpected state: %+v", st)
+	}
+
+	for _, path := range []string{
+		"/api/workflows/maint-wf/run",
+		"/api/workflows/maint-wf/preview",
+		"/api/workflows/maint-wf/nodes/in/test",
+		"/api/hooks/any-trigger",
+	} {
+		w = do("POST", path, `{"inputs":{}}`)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("POST %s: got %d, want 503", path, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), `"maintenance"`) {
+			t.Errorf("POST %s: body %s lacks maintenance status", path, w.Body.String())
+		}
+	}
+
+	if w = do("GET", "/api/runs", ""); w.Code != http.StatusOK {
+		t.Errorf("GET /api/runs during maintenance: got %d, want 200", w.Code)
+	}
+	if w = do("GET", "/api/admin/maintenance", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"enabled":true`) {
+		t.Errorf("GET maintenance: got %d %s", w.Code, w.Body.String())
+	}
+
+	do("POST", "/api/admin/maintenance", `{"enabled":false}`)
+	if w = do("POST", "/api/workflows/maint-wf/run", `{"inputs":{}}`); w.Code != http.StatusAccepted {
+		t.Errorf("run after maintenance: got %d, want 202", w.Code)
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	srv := newTestServer()
+	handler := srv.requireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	call := func(userID string) int {
+		req := httptest.NewRequest("POST", "/api/admin/maintenance", nil)
+		req = req.WithContext(upal.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Without auth the single default user runs the server.
+	if code := call("default"); code != http.StatusNoContent {
+		t.Errorf("auth disabled: got %d, want 204", code)
+	}
+
+	cfg := config.AuthConfig{
+		Google:    config.OAuthProviderConfig{ClientID: "id", ClientSecret: "secret"},
+		JWTSecret: testJWTSecret,
+		Admins:    []string{"user-admin"},
+	}
+	srv.authSvc = services.NewAuthService(nil, cfg, "http://localhost:8080")
+	if code := call("user-admin"); code != http.StatusNoContent {
+		t.Errorf("admin: got %d, want 204", code)
+	}
+	if code := call("user-other"); code != http.StatusForbidden {
+		t.Errorf("non-admin: got %d, want 403", code)
+	}
+}
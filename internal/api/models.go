@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
 
 	"github.com/soochol/upal/internal/config"
 	upalmodel "github.com/soochol/upal/internal/model"
@@ -17,7 +19,8 @@ func (s *Server) listModels(w http.ResponseWriter, r *http.Request) {
 	configs := s.effectiveProviderConfigs(ctx)
 
 	staticConfigs := make(map[string]struct{})
-	for name, pc := range configs {
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		pc := configs[name]
 		if upalmodel.IsOllama(pc) {
 			cat, opts := upalmodel.OptionsForType(pc.Type)
 			ollamaModels := upalmodel.DiscoverOllamaModels(name, pc.URL, cat, opts)
@@ -39,6 +39,11 @@ func (s *Server) suggestWorkflowName(w http.ResponseWriter, r *http.Request) {
 		respondWithName(buildFallbackName(wf))
 		return
 	}
+	llm := s.generator.LLM(r.Context())
+	if llm == nil {
+		respondWithName(buildFallbackName(wf))
+		return
+	}
 
 	wfJSON, _ := json.MarshalIndent(wf, "", "  ")
 
@@ -53,7 +58,7 @@ func (s *Server) suggestWorkflowName(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var resp *adkmodel.LLMResponse
-	for r, err := range s.generator.LLM(r.Context()).GenerateContent(r.Context(), llmReq, false) {
+	for r, err := range llm.GenerateContent(r.Context(), llmReq, false) {
 		if err != nil {
 			respondWithName(buildFallbackName(wf))
 			return
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/generate"
+	"github.com/soochol/upal/internal/llmutil"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// newNoLLMServer returns a server with no LLM providers: an empty resolver
+// and a generator without a default LLM.
+func newNoLLMServer(t *testing.T) *Server {
+	t.Helper()
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", llmutil.NewMapResolver(nil, nil, ""))
+	srv := NewServer(nil, wfSvc, repo, nil)
+	srv.SetGenerator(generate.New(nil, "", noopSkills{}, nil, nil), "")
+	if err := repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name:  "writer",
+		Nodes: []upal.NodeDefinition{{ID: "draft", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "openai/gpt-4o", "prompt": "hi"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func assertNoLLMResponse(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", w.Code, w.Body.String())
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body is not JSON: %s", w.Body.String())
+	}
+	if body["code"] != "no_llm_providers" || !strings.Contains(body["error"], upal.ErrNoLLMProvider.Error()) {
+		t.Errorf("unexpected body: %v", body)
+	}
+}
+
+func TestNoLLMProvider_RunStartReturns503(t *testing.T) {
+	srv := newNoLLMServer(t)
+	for _, path := range []string{"/api/workflows/writer/run", "/api/workflows/writer/preview"} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", path, strings.NewReader(`{}`)))
+			assertNoLLMResponse(t, w)
+		})
+	}
+}
+
+func TestNoLLMProvider_GenerateReturns503(t *testing.T) {
+	srv := newNoLLMServer(t)
+	for _, path := range []string{"/api/generate", "/api/generate-pipeline"} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", path, strings.NewReader(`{"description":"summarise the news"}`)))
+			assertNoLLMResponse(t, w)
+		})
+	}
+}
+
+func TestNoLLMProvider_OtherValidationErrorsStay400(t *testing.T) {
+	srv := newTestServer()
+	srv.workflowSvc = services.NewWorkflowService(srv.repo, nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "",
+		llmutil.NewMapResolver(map[string]adkmodel.LLM{"anthropic": nil}, nil, ""))
+	w := httptest.NewRecorder()
+	body := `{"workflow":{"nodes":[{"id":"a","type":"agent","config":{"model":"openai/gpt-4o"}}]}}`
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/adhoc/run", strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unknown provider with others configured: got %d, want 400: %s", w.Code, w.Body.String())
+	}
+}
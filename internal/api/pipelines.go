@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/soochol/upal/internal/services"
 	"github.com/soochol/upal/internal/upal"
 )
 
@@ -19,10 +20,12 @@ func (s *Server) createPipeline(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "name is required", http.StatusBadRequest)
 		return
 	}
+	markPipelineEdited(r, &p)
 	if err := s.pipelineSvc.Create(r.Context(), &p); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	auditCreated(r, p.ID)
 	if s.schedulerSvc != nil {
 		if err := s.schedulerSvc.SyncPipelineSchedules(r.Context(), &p); err == nil {
 			_ = s.pipelineSvc.Update(r.Context(), &p)
@@ -31,6 +34,24 @@ func (s *Server) createPipeline(w http.ResponseWriter, r *http.Request) {
 	writeJSONStatus(w, http.StatusCreated, p)
 }
 
+// validatePipeline handles POST /api/pipelines/validate. It checks a
+// pipeline definition without saving it and lists every issue found.
+func (s *Server) validatePipeline(w http.ResponseWriter, r *http.Request) {
+	var p upal.Pipeline
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	var conns services.ConnectionLookup
+	if s.connectionSvc != nil {
+		conns = s.connectionSvc
+	}
+	issues := services.ValidatePipeline(r.Context(), &p, s.workflowSvc, conns)
+	writeJSON(w, map[string]any{
+		"valid":  len(issues) == 0,
+		"issues": orEmpty(issues),
+	})
+}
+
 func (s *Server) listPipelines(w http.ResponseWriter, r *http.Request) {
 	pipelines, err := s.pipelineSvc.List(r.Context())
 	if err != nil {
@@ -57,10 +78,16 @@ func (s *Server) updatePipeline(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	p.ID = id
+	var before *upal.Pipeline
+	if auditing(r) {
+		before, _ = s.pipelineSvc.Get(r.Context(), id)
+	}
+	markPipelineEdited(r, &p)
 	if err := s.pipelineSvc.Update(r.Context(), &p); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	auditChanged(r, before, &p)
 	if s.schedulerSvc != nil {
 		if err := s.schedulerSvc.SyncPipelineSchedules(r.Context(), &p); err == nil {
 			_ = s.pipelineSvc.Update(r.Context(), &p)
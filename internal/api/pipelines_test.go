@@ -2,6 +2,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/crypto"
 	"github.com/soochol/upal/internal/repository"
 	"github.com/soochol/upal/internal/services"
 	"github.com/soochol/upal/internal/upal"
@@ -175,3 +177,55 @@ func TestApprovePipelineRun_NotWaiting(t *testing.T) {
 		t.Errorf("expected 400, got %d", w.Code)
 	}
 }
+
+func TestValidatePipeline(t *testing.T) {
+	srv := newTestServer()
+	seedWorkflow(t, srv, "known-wf")
+	enc, _ := crypto.NewEncryptor(nil)
+	connSvc := services.NewConnectionService(repository.NewMemoryConnectionRepository(), enc)
+	connSvc.Create(context.Background(), &upal.Connection{ID: "conn_ok", Name: "slack", Type: upal.ConnTypeSlack})
+	srv.SetConnectionService(connSvc)
+
+	tests := []struct {
+		name      string
+		stage     upal.Stage
+		wantField string // empty means the stage is valid
+	}{
+		{"valid workflow", upal.Stage{ID: "s", Type: "workflow", Config: upal.StageConfig{WorkflowName: "known-wf"}}, ""},
+		{"unknown stage type", upal.Stage{ID: "s", Type: "teleport"}, "type"},
+		{"unknown workflow", upal.Stage{ID: "s", Type: "workflow", Config: upal.StageConfig{WorkflowName: "ghost"}}, "config.workflow_name"},
+		{"missing workflow name", upal.Stage{ID: "s", Type: "workflow"}, "config.workflow_name"},
+		{"bad cron", upal.Stage{ID: "s", Type: "schedule", Config: upal.StageConfig{Cron: "0 9 * *"}}, "config.cron"},
+		{"valid cron", upal.Stage{ID: "s", Type: "schedule", Config: upal.StageConfig{Cron: "0 9 * * 1-5", Timezone: "Asia/Seoul"}}, ""},
+		{"unknown approval connection", upal.Stage{ID: "s", Type: "approval", Config: upal.StageConfig{ConnectionID: "conn_missing"}}, "config.connection_id"},
+		{"notification without connection", upal.Stage{ID: "s", Type: "notification", Config: upal.StageConfig{Message: "hi"}}, "config.connection_id"},
+		{"valid notification", upal.Stage{ID: "s", Type: "notification", Config: upal.StageConfig{ConnectionID: "conn_ok"}}, ""},
+		{"collect without sources", upal.Stage{ID: "s", Type: "collect"}, "config.sources"},
+		{"collect source missing url", upal.Stage{ID: "s", Type: "collect", Config: upal.StageConfig{Sources: []upal.CollectSource{{Type: "rss"}}}}, "config.sources[0].url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(upal.Pipeline{Name: "p", Stages: []upal.Stage{tt.stage}})
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/pipelines/validate", bytes.NewReader(body)))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Valid  bool                 `json:"valid"`
+				Issues []upal.PipelineIssue `json:"issues"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+
+			if tt.wantField == "" {
+				if !resp.Valid || len(resp.Issues) != 0 {
+					t.Errorf("issues = %+v, want none", resp.Issues)
+				}
+				return
+			}
+			if resp.Valid || len(resp.Issues) != 1 || resp.Issues[0].Field != tt.wantField || resp.Issues[0].StageID != "s" {
+				t.Errorf("issues = %+v, want one issue on %s", resp.Issues, tt.wantField)
+			}
+		})
+	}
+}
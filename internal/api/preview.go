@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// defaultPreviewTimeout bounds a preview run when SetPreviewTimeout was not
+// called.
+const defaultPreviewTimeout = 2 * time.Minute
+
+// SetPreviewTimeout sets the hard limit on a workflow preview run, including
+// the wait for a concurrency slot.
+func (s *Server) SetPreviewTimeout(d time.Duration) { s.previewTimeout = d }
+
+// previewWorkflow handles POST /api/workflows/{name}/preview. It runs the
+// workflow inline and streams its events as SSE in the format of
+// /api/runs/{id}/events, but creates no run record and records no node runs
+// or token usage. The body is a RunRequest as for /run, so an unsaved draft
+// can be previewed. The run holds a concurrency slot and is cancelled when
+// the preview timeout elapses or the client disconnects.
+func (s *Server) previewWorkflow(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+	}
+	req := parseRunRequest(body, s.floatNumbers)
+
+	wf := req.Workflow
+	if wf != nil {
+		wf.Name = name
+	} else {
+		var err error
+		wf, err = s.workflowSvc.Lookup(r.Context(), name)
+		if err != nil {
+			http.Error(w, "workflow not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	overrides, err := parseModelOverrides(r.Header, wf)
+	if err != nil {
+		writeJSONStatus(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	wf = upal.ApplyModelOverrides(wf, overrides)
+	if err := s.workflowSvc.Validate(wf); err != nil {
+		writeRunValidationError(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	timeout := s.previewTimeout
+	if timeout <= 0 {
+		timeout = defaultPreviewTimeout
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	if s.limiter != nil {
+		if err := s.limiter.Acquire(ctx, wf.Name); err != nil {
+			http.Error(w, "timed out waiting for a concurrency slot", http.StatusGatewayTimeout)
+			return
+		}
+		defer s.limiter.Release(wf.Name)
+	}
+
+	events, result, err := s.workflowSvc.Run(ctx, wf, req.Inputs)
+	if errors.Is(err, upal.ErrModerationBlocked) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	seq := 0
+	var runErr string
+	for events != nil {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			switch ev.Type {
+			case upal.EventRunContext:
+				continue
+			case upal.EventNodeStarted:
+				ev.Payload["started_at"] = time.Now().UnixMilli()
+			case upal.EventNodeCompleted:
+				ev.Payload["completed_at"] = time.Now().UnixMilli()
+			case upal.EventError:
+				runErr = fmt.Sprintf("%v", ev.Payload["error"])
+			}
+			writeSSEEvent(w, upal.EventRecord{Seq: seq, WorkflowEvent: ev})
+			flusher.Flush()
+			seq++
+		case <-ctx.Done():
+			// Drain so the cancelled run can finish sending.
+			go func() {
+				for range events {
+				}
+			}()
+			events = nil
+		}
+	}
+
+	payload := map[string]any{"status": "completed", "preview": true}
+	if err := ctx.Err(); err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return // client went away
+		}
+		payload["status"] = string(upal.RunStatusFailed)
+		payload["error"] = fmt.Sprintf("preview timed out after %v", timeout)
+	} else if res, ok := <-result; runErr != "" || !ok {
+		if runErr == "" {
+			runErr = "workflow produced no result"
+		}
+		payload["status"] = string(upal.RunStatusFailed)
+		payload["error"] = runErr
+	} else {
+		payload["session_id"] = res.SessionID
+		payload["state"] = res.State
+		if len(res.NodeErrors) > 0 {
+			payload["status"] = string(upal.RunStatusCompletedWithErrors)
+			payload["node_errors"] = res.NodeErrors
+		}
+	}
+	writeDoneEvent(w, payload)
+	flusher.Flush()
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func newPreviewTestServer(t *testing.T) *Server {
+	t.Helper()
+	srv := newTestServer()
+	wf := &upal.WorkflowDefinition{
+		Name: "draft",
+		Nodes: []upal.NodeDefinition{
+			{ID: "topic", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "topic", To: "out"}},
+	}
+	if err := srv.repo.Create(context.Background(), wf); err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func TestPreviewWorkflow_StreamsWithoutRunRecord(t *testing.T) {
+	srv := newPreviewTestServer(t)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/draft/preview", strings.NewReader(`{"inputs":{"topic":"go"}}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"id: 0\nevent: node_started", "event: node_completed", "event: done", `"status":"completed"`, `"preview":true`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("stream missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "run_id") {
+		t.Errorf("preview reported a run ID:\n%s", body)
+	}
+
+	runs, total, err := srv.runHistorySvc.ListAllRuns(context.Background(), 10, 0, "")
+	if err != nil || total != 0 || len(runs) != 0 {
+		t.Errorf("preview created run records: %d (err %v)", total, err)
+	}
+}
+
+func TestPreviewWorkflow_WaitsForConcurrencySlot(t *testing.T) {
+	srv := newPreviewTestServer(t)
+	limiter := services.NewConcurrencyLimiter(upal.ConcurrencyLimits{GlobalMax: 1, PerWorkflow: 1})
+	srv.SetConcurrencyLimiter(limiter)
+	srv.SetPreviewTimeout(50 * time.Millisecond)
+
+	if !limiter.TryAcquire("draft") {
+		t.Fatal("could not take the only slot")
+	}
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/draft/preview", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504 while saturated, got %d: %s", w.Code, w.Body.String())
+	}
+
+	limiter.Release("draft")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/draft/preview", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "event: done") {
+		t.Fatalf("expected a streamed preview once the slot was free, got %d: %s", w.Code, w.Body.String())
+	}
+}
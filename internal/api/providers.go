@@ -0,0 +1,137 @@
+package api
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/config"
+	upalmodel "github.com/soochol/upal/internal/model"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// SetProviderBreakers exposes per-provider circuit breaker state via
+// GET /api/providers/status.
+func (s *Server) SetProviderBreakers(b *upalmodel.CircuitBreakers) { s.providerBreakers = b }
+
+// getProviderStatus handles GET /api/providers/status.
+func (s *Server) getProviderStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, orEmpty(s.providerBreakers.Status()))
+}
+
+// SetWarmer enables POST /api/providers/{name}/warm.
+func (s *Server) SetWarmer(w *upalmodel.Warmer) { s.warmer = w }
+
+// warmProvider handles POST /api/providers/{name}/warm. It sends one warm-up
+// request to a local provider and reports the latency; a failed request is
+// answered with 502 and the error in the result.
+func (s *Server) warmProvider(w http.ResponseWriter, r *http.Request) {
+	res, err := s.warmer.Warm(r.Context(), chi.URLParam(r, "name"))
+	switch {
+	case errors.Is(err, upalmodel.ErrUnknownProvider):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, upalmodel.ErrNotLocalProvider):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case res.Error != "":
+		writeJSONStatus(w, http.StatusBadGateway, res)
+	default:
+		writeJSON(w, res)
+	}
+}
+
+// SetReachabilityChecker adds a cached endpoint probe to each provider in
+// GET /api/providers. Without one, reachability is omitted.
+func (s *Server) SetReachabilityChecker(c *upalmodel.ReachabilityChecker) { s.reachability = c }
+
+// ProviderStatus describes one configured provider. Credentials are never
+// included: HasAPIKey only says whether one is set, and the URL has its
+// user info and query string removed.
+type ProviderStatus struct {
+	Name         string                   `json:"name"`
+	Type         string                   `json:"type"`
+	Category     upal.ModelCategory       `json:"category"`
+	URL          string                   `json:"url,omitempty"`
+	HasAPIKey    bool                     `json:"has_api_key"`
+	IsDefault    bool                     `json:"is_default"`
+	Breaker      *upalmodel.BreakerStatus `json:"breaker,omitempty"`
+	Reachability *upalmodel.Reachability  `json:"reachability,omitempty"`
+}
+
+// ProvidersResponse is the body of GET /api/providers.
+type ProvidersResponse struct {
+	Providers       []ProviderStatus `json:"providers"`
+	DefaultProvider string           `json:"default_provider,omitempty"`
+	DefaultModel    string           `json:"default_model,omitempty"`
+}
+
+// listProviders handles GET /api/providers. It lists the providers loaded at
+// startup plus any registered since, with their circuit breaker state and
+// cached reachability, and the default LLM provider and model.
+func (s *Server) listProviders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	configs := maps.Clone(s.providerConfigs)
+	if configs == nil {
+		configs = make(map[string]config.ProviderConfig)
+	}
+	if s.aiProviderSvc != nil {
+		maps.Copy(configs, s.effectiveProviderConfigs(ctx))
+	}
+	defaults := s.defaultProviderNames(ctx)
+	breakers := make(map[string]upalmodel.BreakerStatus)
+	for _, b := range s.providerBreakers.Status() {
+		breakers[b.Provider] = b
+	}
+
+	resp := ProvidersResponse{Providers: make([]ProviderStatus, 0, len(configs)), DefaultModel: s.defaultGenerateModel}
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		pc := configs[name]
+		cat, _ := upalmodel.OptionsForType(pc.Type)
+		p := ProviderStatus{
+			Name:      name,
+			Type:      pc.Type,
+			Category:  cat,
+			URL:       redactURL(pc.URL),
+			HasAPIKey: pc.APIKey != "",
+			IsDefault: defaults[name],
+		}
+		if b, ok := breakers[name]; ok {
+			p.Breaker = &b
+		}
+		resp.Providers = append(resp.Providers, p)
+	}
+	if s.aiProviderSvc != nil {
+		if p, err := s.aiProviderSvc.DefaultLLM(ctx); err == nil {
+			resp.DefaultProvider = p.Name
+		}
+	}
+
+	if s.reachability != nil {
+		var wg sync.WaitGroup
+		for i := range resp.Providers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if res, ok := s.reachability.Check(ctx, configs[resp.Providers[i].Name]); ok {
+					resp.Providers[i].Reachability = &res
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	writeJSON(w, resp)
+}
+
+// redactURL strips credentials that may be embedded in a provider URL.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
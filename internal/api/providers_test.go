@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/config"
+	"github.com/soochol/upal/internal/crypto"
+	upalmodel "github.com/soochol/upal/internal/model"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+	adkmodel "google.golang.org/adk/model"
+)
+
+func TestWarmProvider(t *testing.T) {
+	srv := newTestServer()
+	local, remote := &describeLLM{}, &describeLLM{}
+	srv.SetWarmer(upalmodel.NewWarmer(
+		map[string]adkmodel.LLM{"ollama": local, "claude": remote},
+		map[string]config.ProviderConfig{
+			"ollama": {Type: "ollama"},
+			"claude": {Type: "anthropic"},
+		},
+		config.WarmupConfig{Models: map[string]string{"ollama": "llama3"}},
+	))
+
+	warm := func(name string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/providers/"+name+"/warm", nil))
+		return w
+	}
+
+	w := warm("ollama")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var res upalmodel.WarmupResult
+	json.Unmarshal(w.Body.Bytes(), &res)
+	if res.Provider != "ollama" || res.Model != "llama3" || local.calls.Load() != 1 {
+		t.Errorf("unexpected result %+v (calls=%d)", res, local.calls.Load())
+	}
+
+	if w := warm("claude"); w.Code != http.StatusBadRequest || remote.calls.Load() != 0 {
+		t.Errorf("remote provider: status %d, calls %d", w.Code, remote.calls.Load())
+	}
+	if w := warm("missing"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown provider: expected 404, got %d", w.Code)
+	}
+}
+
+func TestListProviders_RedactsSecrets(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer upstream.Close()
+
+	srv := newTestServer()
+	srv.SetProviderConfigs(map[string]config.ProviderConfig{
+		"local": {
+			Type:    "openai",
+			URL:     strings.Replace(upstream.URL, "http://", "http://user:pw-secret@", 1) + "?key=q-secret",
+			APIKey:  "sk-static-secret",
+			Headers: map[string]string{"Authorization": "Bearer hdr-secret"},
+		},
+		"down":   {Type: "ollama", URL: "http://127.0.0.1:1"},
+		"claude": {Type: "claude-code"},
+	})
+	srv.SetReachabilityChecker(upalmodel.NewReachabilityChecker(time.Minute))
+	enc, err := crypto.NewEncryptor([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aiSvc := services.NewAIProviderService(repository.NewMemoryAIProviderRepository(), enc)
+	if err := aiSvc.Create(context.Background(), &upal.AIProvider{Name: "claude", Category: upal.AICategoryLLM, Type: "claude-code", APIKey: "sk-db-secret", IsDefault: true}); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetAIProviderService(aiSvc)
+	srv.defaultGenerateModel = "sonnet"
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/providers", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if body := w.Body.String(); strings.Contains(body, "secret") {
+		t.Fatalf("response leaks a secret: %s", body)
+	}
+	var resp ProvidersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.DefaultProvider != "claude" || resp.DefaultModel != "sonnet" {
+		t.Errorf("defaults = %q/%q", resp.DefaultProvider, resp.DefaultModel)
+	}
+	got := make(map[string]ProviderStatus)
+	for _, p := range resp.Providers {
+		got[p.Name] = p
+	}
+	if len(got) != 3 {
+		t.Fatalf("providers = %+v", resp.Providers)
+	}
+	if p := got["local"]; !p.HasAPIKey || p.URL != upstream.URL || p.Reachability == nil || !p.Reachability.Reachable {
+		t.Errorf("local = %+v (reachability %+v)", p, p.Reachability)
+	}
+	if p := got["down"]; p.HasAPIKey || p.Reachability == nil || p.Reachability.Reachable {
+		t.Errorf("down = %+v (reachability %+v)", p, p.Reachability)
+	}
+	if p := got["claude"]; !p.IsDefault || !p.HasAPIKey || p.Reachability != nil {
+		t.Errorf("claude = %+v", p)
+	}
+}
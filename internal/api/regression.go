@@ -0,0 +1,129 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// SetRegressionChecker enables POST /api/workflows/{name}/regression-check.
+func (s *Server) SetRegressionChecker(c *services.RegressionChecker) { s.regressionChecker = c }
+
+// SetBaselineRequest is the body of PUT /api/workflows/{name}/baseline.
+type SetBaselineRequest struct {
+	RunID string `json:"run_id"`
+}
+
+// setWorkflowBaseline handles PUT /api/workflows/{name}/baseline, marking a
+// successful run of the workflow as the baseline for regression checks.
+func (s *Server) setWorkflowBaseline(w http.ResponseWriter, r *http.Request) {
+	if s.runHistorySvc == nil {
+		http.Error(w, "run history not available", http.StatusNotFound)
+		return
+	}
+	name := chi.URLParam(r, "name")
+	var req SetBaselineRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.RunID == "" {
+		http.Error(w, "run_id is required", http.StatusBadRequest)
+		return
+	}
+	wf, err := s.repo.Get(r.Context(), name)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	run, err := s.runHistorySvc.GetRun(r.Context(), req.RunID)
+	if err != nil {
+		http.Error(w, "run not found", http.StatusNotFound)
+		return
+	}
+	if run.WorkflowName != name {
+		http.Error(w, "run belongs to workflow "+run.WorkflowName, http.StatusBadRequest)
+		return
+	}
+	if run.Status != upal.RunStatusSuccess {
+		http.Error(w, "only successful runs can be a baseline", http.StatusBadRequest)
+		return
+	}
+
+	wf.BaselineRunID = run.ID
+	if err := s.repo.Update(r.Context(), name, wf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, wf)
+}
+
+// clearWorkflowBaseline handles DELETE /api/workflows/{name}/baseline.
+func (s *Server) clearWorkflowBaseline(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	wf, err := s.repo.Get(r.Context(), name)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	wf.BaselineRunID = ""
+	if err := s.repo.Update(r.Context(), name, wf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegressionCheckRequest is the body of POST
+// /api/workflows/{name}/regression-check. Mode is "exact" (default),
+// "normalized" or "embedding"; a zero Threshold uses the mode's default.
+type RegressionCheckRequest struct {
+	Mode      string  `json:"mode,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// regressionCheck handles POST /api/workflows/{name}/regression-check. It
+// runs the current workflow definition with the baseline run's inputs and
+// returns the per-output similarity and an overall pass/fail.
+func (s *Server) regressionCheck(w http.ResponseWriter, r *http.Request) {
+	if s.regressionChecker == nil || s.runHistorySvc == nil {
+		http.Error(w, "regression checks not available", http.StatusServiceUnavailable)
+		return
+	}
+	var req RegressionCheckRequest
+	if r.ContentLength != 0 && !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Threshold < 0 || req.Threshold > 1 {
+		http.Error(w, "threshold must be between 0 and 1", http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	wf, err := s.repo.Get(ctx, chi.URLParam(r, "name"))
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	if wf.BaselineRunID == "" {
+		http.Error(w, "workflow has no baseline run; set one with PUT /api/workflows/{name}/baseline", http.StatusBadRequest)
+		return
+	}
+	baseline, err := s.runHistorySvc.GetRun(ctx, wf.BaselineRunID)
+	if err != nil {
+		http.Error(w, "baseline run not found", http.StatusNotFound)
+		return
+	}
+
+	result, err := s.regressionChecker.Check(ctx, wf, baseline, req.Mode, req.Threshold)
+	if errors.Is(err, services.ErrUnknownComparisonMode) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, result)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// stubComparator scores every output pair with a fixed similarity.
+type stubComparator struct{ score float64 }
+
+func (c stubComparator) Compare(context.Context, string, string) (float64, error) {
+	return c.score, nil
+}
+
+// newRegressionTestServer stores an echo workflow and a successful baseline
+// run whose output was "hello v1" for input "hello".
+func newRegressionTestServer(t *testing.T) (*Server, *services.RegressionChecker, string) {
+	t.Helper()
+	srv := newTestServer()
+	checker := services.NewRegressionChecker(srv.workflowSvc)
+	srv.SetRegressionChecker(checker)
+
+	ctx := context.Background()
+	wf := &upal.WorkflowDefinition{
+		Name: "echo",
+		Nodes: []upal.NodeDefinition{
+			{ID: "text", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "result", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": "{{text}} v1"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "text", To: "result"}},
+	}
+	if err := srv.repo.Create(ctx, wf); err != nil {
+		t.Fatalf("create workflow: %v", err)
+	}
+	run, _ := srv.runHistorySvc.StartRun(ctx, "echo", "manual", "", map[string]any{"text": "hello"}, wf)
+	srv.runHistorySvc.CompleteRun(ctx, run.ID, map[string]any{"__output__": map[string]any{"result": "hello v1"}})
+	return srv, checker, run.ID
+}
+
+func doJSON(srv *Server, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	return w
+}
+
+func TestRegressionCheck(t *testing.T) {
+	srv, checker, runID := newRegressionTestServer(t)
+
+	if w := doJSON(srv, "POST", "/api/workflows/echo/regression-check", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("without baseline: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(srv, "PUT", "/api/workflows/echo/baseline", `{"run_id":"`+runID+`"}`); w.Code != http.StatusOK {
+		t.Fatalf("set baseline: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	check := func(body string) upal.RegressionResult {
+		t.Helper()
+		w := doJSON(srv, "POST", "/api/workflows/echo/regression-check", body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("regression check: expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var res upal.RegressionResult
+		json.Unmarshal(w.Body.Bytes(), &res)
+		return res
+	}
+
+	// Unchanged workflow reproduces the baseline exactly.
+	res := check(`{"mode":"exact"}`)
+	if !res.Passed || res.Similarity != 1 || res.BaselineRunID != runID || len(res.Outputs) != 1 || res.Outputs[0].Actual != "hello v1" {
+		t.Errorf("unchanged workflow: %+v", res)
+	}
+
+	// A stub comparator decides pass/fail against the threshold.
+	checker.SetComparator("stub", stubComparator{score: 0.8})
+	if res := check(`{"mode":"stub","threshold":0.75}`); !res.Passed || res.Similarity != 0.8 {
+		t.Errorf("stub 0.8 >= 0.75 should pass: %+v", res)
+	}
+	if res := check(`{"mode":"stub","threshold":0.9}`); res.Passed || res.Outputs[0].Passed {
+		t.Errorf("stub 0.8 < 0.9 should fail: %+v", res)
+	}
+
+	// Changing the prompt is a regression under exact comparison.
+	wf, _ := srv.repo.Get(context.Background(), "echo")
+	wf.Nodes[1].Config["prompt"] = "{{text}} v2"
+	srv.repo.Update(context.Background(), "echo", wf)
+	if res := check(""); res.Passed || res.Outputs[0].Actual != "hello v2" || res.Mode != upal.RegressionExact {
+		t.Errorf("changed prompt should fail: %+v", res)
+	}
+
+	if w := doJSON(srv, "POST", "/api/workflows/echo/regression-check", `{"mode":"bogus"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown mode: expected 400, got %d", w.Code)
+	}
+}
+
+func TestSetWorkflowBaseline_Validation(t *testing.T) {
+	srv, _, runID := newRegressionTestServer(t)
+	ctx := context.Background()
+
+	srv.repo.Create(ctx, &upal.WorkflowDefinition{Name: "other"})
+	if w := doJSON(srv, "PUT", "/api/workflows/other/baseline", `{"run_id":"`+runID+`"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("run of another workflow: expected 400, got %d", w.Code)
+	}
+	failed, _ := srv.runHistorySvc.StartRun(ctx, "echo", "manual", "", nil, nil)
+	srv.runHistorySvc.FailRun(ctx, failed.ID, "boom")
+	if w := doJSON(srv, "PUT", "/api/workflows/echo/baseline", `{"run_id":"`+failed.ID+`"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("failed run: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(srv, "PUT", "/api/workflows/echo/baseline", `{"run_id":"missing"}`); w.Code != http.StatusNotFound {
+		t.Errorf("missing run: expected 404, got %d", w.Code)
+	}
+
+	// Saving the workflow from the editor keeps the baseline.
+	doJSON(srv, "PUT", "/api/workflows/echo/baseline", `{"run_id":"`+runID+`"}`)
+	doJSON(srv, "PUT", "/api/workflows/echo", `{"name":"echo","nodes":[],"edges":[]}`)
+	if wf, _ := srv.repo.Get(ctx, "echo"); wf.BaselineRunID != runID {
+		t.Errorf("baseline lost on update: %q", wf.BaselineRunID)
+	}
+	if w := doJSON(srv, "DELETE", "/api/workflows/echo/baseline", ""); w.Code != http.StatusNoContent {
+		t.Errorf("clear baseline: expected 204, got %d", w.Code)
+	}
+	if wf, _ := srv.repo.Get(ctx, "echo"); wf.BaselineRunID != "" {
+		t.Errorf("baseline not cleared: %q", wf.BaselineRunID)
+	}
+}
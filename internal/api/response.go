@@ -53,3 +53,38 @@ func writeServiceError(w http.ResponseWriter, err error, defaultStatus int) {
 		http.Error(w, err.Error(), defaultStatus)
 	}
 }
+
+// writeNoLLMProvider answers 503 for a request that needs an LLM when none is
+// configured. The "no_llm_providers" code lets clients tell it apart from
+// other failures and point the user at provider settings.
+func writeNoLLMProvider(w http.ResponseWriter, err error) {
+	writeJSONStatus(w, http.StatusServiceUnavailable, map[string]string{
+		"error": err.Error(),
+		"code":  "no_llm_providers",
+	})
+}
+
+// requireGenerator reports whether generation can run, answering 503 when no
+// generator is set up or it has no LLM provider to use.
+func (s *Server) requireGenerator(w http.ResponseWriter, r *http.Request) bool {
+	if s.generator == nil {
+		http.Error(w, "generator not configured (no providers available)", http.StatusServiceUnavailable)
+		return false
+	}
+	if err := s.generator.Available(r.Context()); errors.Is(err, upal.ErrNoLLMProvider) {
+		writeNoLLMProvider(w, err)
+		return false
+	}
+	return true
+}
+
+// writeRunValidationError answers a run request whose workflow failed
+// WorkflowExecutor.Validate: 503 when an agent node has no LLM provider to
+// run on, 400 otherwise.
+func writeRunValidationError(w http.ResponseWriter, err error) {
+	if errors.Is(err, upal.ErrNoLLMProvider) {
+		writeNoLLMProvider(w, err)
+		return
+	}
+	writeJSONStatus(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+}
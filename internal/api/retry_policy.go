@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// RetryDelay is the wait before one retry attempt of a previewed policy.
+type RetryDelay struct {
+	Attempt int           `json:"attempt"`
+	Delay   time.Duration `json:"delay"`
+	Display string        `json:"display"`
+}
+
+// RetryPolicyPreview is the response of POST /api/retry-policy/preview.
+type RetryPolicyPreview struct {
+	Delays []RetryDelay  `json:"delays"`
+	Total  time.Duration `json:"total"`
+}
+
+// previewRetryPolicy handles POST /api/retry-policy/preview. It returns the
+// backoff before each retry the policy would make, using the same
+// computation as the retry executor.
+func (s *Server) previewRetryPolicy(w http.ResponseWriter, r *http.Request) {
+	var policy upal.RetryPolicy
+	if !decodeJSON(w, r, &policy) {
+		return
+	}
+	if err := policy.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	preview := RetryPolicyPreview{Delays: []RetryDelay{}}
+	for i, d := range services.RetryDelays(policy) {
+		preview.Delays = append(preview.Delays, RetryDelay{Attempt: i + 1, Delay: d, Display: d.String()})
+		preview.Total += d
+	}
+	writeJSON(w, preview)
+}
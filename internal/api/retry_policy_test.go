@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreviewRetryPolicy_CapsAtMaxDelay(t *testing.T) {
+	srv := newTestServer()
+	body := `{"max_retries":5,"initial_delay":1000000000,"max_delay":5000000000,"backoff_factor":2}`
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/retry-policy/preview", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d, body: %s", w.Code, w.Body.String())
+	}
+
+	var preview RetryPolicyPreview
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if len(preview.Delays) != len(want) {
+		t.Fatalf("got %d delays, want %d", len(preview.Delays), len(want))
+	}
+	for i, d := range preview.Delays {
+		if d.Attempt != i+1 || d.Delay != want[i] {
+			t.Errorf("delay %d = attempt %d / %v, want attempt %d / %v", i, d.Attempt, d.Delay, i+1, want[i])
+		}
+	}
+	if preview.Delays[3].Display != "5s" {
+		t.Errorf("display = %q, want 5s", preview.Delays[3].Display)
+	}
+	if preview.Total != 17*time.Second {
+		t.Errorf("total = %v, want 17s", preview.Total)
+	}
+}
+
+func TestPreviewRetryPolicy_RejectsInvalid(t *testing.T) {
+	srv := newTestServer()
+	for _, body := range []string{
+		`{"max_retries":3,"initial_delay":1000000000,"max_delay":5000000000,"backoff_factor":0.5}`,
+		`{"max_retries":-1,"initial_delay":1000000000,"max_delay":5000000000,"backoff_factor":2}`,
+		`{"max_retries":3,"initial_delay":-1,"max_delay":5000000000,"backoff_factor":2}`,
+	} {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/retry-policy/preview", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", body, w.Code)
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/soochol/upal/internal/upal"
@@ -17,62 +20,196 @@ type RunRequest struct {
 	Workflow *upal.WorkflowDefinition `json:"workflow,omitempty"`
 }
 
+// runWorkflow handles POST /api/workflows/{name}/run and starts the run in
+// the background, answering 202 with its ID. The body is a RunRequest or,
+// for simple integrations, the inputs object itself. Requests carrying
+// upal.DefaultSignatureHeader skip bearer auth (see AuthMiddleware), so they
+// must be signed with the workflow's webhook secret (see
+// setWorkflowWebhookSecret) and cannot supply an inline workflow.
+// Token-authenticated requests need no signature.
 func (s *Server) runWorkflow(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 
-	var req RunRequest
+	var body []byte
 	if r.Body != nil {
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			req.Inputs = nil
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
 		}
 	}
+	req := parseRunRequest(body, s.floatNumbers)
 
-	var wf *upal.WorkflowDefinition
-	if req.Workflow != nil {
-		wf = req.Workflow
-		wf.Name = name
-	} else {
-		var err error
-		wf, err = s.workflowSvc.Lookup(r.Context(), name)
-		if err != nil {
+	stored, lookupErr := s.workflowSvc.Lookup(r.Context(), name)
+	signature := r.Header.Get(upal.DefaultSignatureHeader)
+	if signature != "" {
+		if lookupErr != nil {
 			http.Error(w, "workflow not found", http.StatusNotFound)
 			return
 		}
+		if stored.WebhookSecret == "" || !verifyHMAC(body, stored.WebhookSecret, signature) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		if req.Workflow != nil {
+			http.Error(w, "signed requests cannot supply a workflow definition", http.StatusBadRequest)
+			return
+		}
 	}
 
-	if err := s.workflowSvc.Validate(wf); err != nil {
+	wf := stored
+	if req.Workflow != nil {
+		wf = req.Workflow
+		wf.Name = name
+	} else if lookupErr != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+
+	overrides, err := parseModelOverrides(r.Header, wf)
+	if err != nil {
 		writeJSONStatus(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
 	}
+	// Apply before Validate so an override naming an unknown provider is rejected here.
+	wf = upal.ApplyModelOverrides(wf, overrides)
+
+	if err := s.workflowSvc.Validate(wf); err != nil {
+		writeRunValidationError(w, err)
+		return
+	}
 
 	var runID string
 	if s.runHistorySvc != nil {
 		record, err := s.runHistorySvc.StartRun(r.Context(), name, "manual", "", req.Inputs, wf)
 		if err != nil {
-			slog.Warn("failed to create run record", "err", err)
+			slog.WarnContext(r.Context(), "failed to create run record", "err", err)
 		} else {
 			runID = record.ID
 		}
 	}
 
+	if runID != "" {
+		w.Header().Set(runIDHeader, runID)
+	}
+
 	if s.runManager != nil && s.runPublisher != nil && runID != "" {
 		s.runManager.Register(runID)
-		go s.runPublisher.Launch(context.Background(), runID, wf, req.Inputs)
+		go s.runPublisher.Launch(upal.WithRunID(context.Background(), runID), runID, wf, req.Inputs)
 	}
 
 	writeJSONStatus(w, http.StatusAccepted, map[string]string{"run_id": runID})
 }
 
+// parseRunRequest decodes a run body: a RunRequest when it has an "inputs"
+// or "workflow" key, otherwise a bare inputs object. Malformed bodies run with
+// no inputs. Input numbers decode as described at unmarshalInputs; those in
+// an inline workflow stay float64 like any stored definition.
+func parseRunRequest(body []byte, floatNumbers bool) RunRequest {
+	var req RunRequest
+	var fields map[string]json.RawMessage
+	if len(body) == 0 || json.Unmarshal(body, &fields) != nil {
+		return req
+	}
+	_, hasInputs := fields["inputs"]
+	_, hasWorkflow := fields["workflow"]
+	if hasInputs || hasWorkflow {
+		if json.Unmarshal(body, &req) != nil {
+			req.Inputs = nil
+		} else if hasInputs {
+			req.Inputs = nil
+			unmarshalInputs(fields["inputs"], &req.Inputs, floatNumbers)
+		}
+		return req
+	}
+	unmarshalInputs(body, &req.Inputs, floatNumbers)
+	if len(req.Inputs) == 0 {
+		req.Inputs = nil
+	}
+	return req
+}
+
+// modelOverrideHeader substitutes the model of every agent node for one run;
+// modelOverrideHeader + "-{nodeID}" targets a single node.
+const modelOverrideHeader = "X-Model-Override"
+
+// parseModelOverrides reads model override headers. Header names are
+// canonicalized by net/http, so node IDs are matched case-insensitively.
+// Provider validity is checked afterwards by WorkflowExecutor.Validate.
+func parseModelOverrides(h http.Header, wf *upal.WorkflowDefinition) (upal.ModelOverrides, error) {
+	var o upal.ModelOverrides
+	o.Default = strings.TrimSpace(h.Get(modelOverrideHeader))
+	prefix := modelOverrideHeader + "-"
+	for key, vals := range h {
+		if !strings.HasPrefix(key, prefix) || len(vals) == 0 {
+			continue
+		}
+		suffix := strings.TrimPrefix(key, prefix)
+		nodeID := ""
+		for _, n := range wf.Nodes {
+			if strings.EqualFold(n.ID, suffix) {
+				nodeID = n.ID
+				break
+			}
+		}
+		if nodeID == "" {
+			return o, fmt.Errorf("%s: no node %q in workflow %q", key, suffix, wf.Name)
+		}
+		if o.Nodes == nil {
+			o.Nodes = make(map[string]string)
+		}
+		o.Nodes[nodeID] = strings.TrimSpace(vals[0])
+	}
+	return o, nil
+}
+
+// SetSSEHeartbeat sets the interval of keep-alive comments on run event
+// streams. Zero disables them.
+func (s *Server) SetSSEHeartbeat(d time.Duration) { s.sseHeartbeat = d }
+
+// SetFloatNumbers makes run request, rerun, A2A and webhook inputs decode
+// numbers as float64 rather than json.Number.
+func (s *Server) SetFloatNumbers(enabled bool) { s.floatNumbers = enabled }
+
+// unmarshalInputs decodes JSON run inputs, keeping numbers as json.Number
+// unless SetFloatNumbers was enabled.
+func unmarshalInputs(data []byte, v any, floatNumbers bool) error {
+	if floatNumbers {
+		return json.Unmarshal(data, v)
+	}
+	return upal.UnmarshalInputs(data, v)
+}
+
+// decodeInputsJSON is decodeJSON for request bodies that carry run inputs: it
+// decodes numbers according to SetFloatNumbers.
+func (s *Server) decodeInputsJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || unmarshalInputs(body, dst, s.floatNumbers) != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
 // streamRunEvents streams execution events for a run via SSE.
-// Supports reconnection via Last-Event-ID header.
+// Supports reconnection via the Last-Event-ID header (or a last_event_id
+// query parameter for clients that cannot set headers): only buffered events
+// after that sequence number are replayed.
 func (s *Server) streamRunEvents(w http.ResponseWriter, r *http.Request) {
 	runID := chi.URLParam(r, "id")
 
+	idStr := r.Header.Get("Last-Event-ID")
+	if idStr == "" {
+		idStr = r.URL.Query().Get("last_event_id")
+	}
 	lastSeq := -1
-	if idStr := r.Header.Get("Last-Event-ID"); idStr != "" {
-		if n, err := strconv.Atoi(idStr); err == nil {
-			lastSeq = n
+	if idStr != "" {
+		n, err := strconv.Atoi(idStr)
+		if err != nil || n < -1 {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
 		}
+		lastSeq = n
 	}
 	startSeq := lastSeq + 1
 
@@ -114,10 +251,22 @@ func (s *Server) streamRunEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Heartbeat comments keep proxies from closing the stream during long
+	// gaps between events; they stop when the run finishes and we return.
+	var heartbeat <-chan time.Time
+	if s.sseHeartbeat > 0 {
+		ticker := time.NewTicker(s.sseHeartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
 	for {
 		select {
 		case <-r.Context().Done():
 			return
+		case <-heartbeat:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
 		case <-notify:
 			nextSeq := startSeq + len(events)
 			events, notify, done, donePayload, found = s.runManager.Subscribe(runID, nextSeq)
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/storage"
+)
+
+// listRunArtifacts handles GET /api/runs/{id}/artifacts.
+func (s *Server) listRunArtifacts(w http.ResponseWriter, r *http.Request) {
+	if s.runHistorySvc == nil {
+		http.Error(w, "run history not available", http.StatusNotFound)
+		return
+	}
+	run, err := s.runHistorySvc.GetRun(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "run not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, orEmpty(run.Artifacts))
+}
+
+// getRunArtifact handles GET /api/runs/{id}/artifacts/{name}, streaming the
+// stored file with the content type recorded at save time.
+func (s *Server) getRunArtifact(w http.ResponseWriter, r *http.Request) {
+	if s.runHistorySvc == nil {
+		http.Error(w, "run history not available", http.StatusNotFound)
+		return
+	}
+	if s.storage == nil {
+		http.Error(w, "file storage not configured", http.StatusServiceUnavailable)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	run, err := s.runHistorySvc.GetRun(r.Context(), id)
+	if err != nil {
+		http.Error(w, "run not found", http.StatusNotFound)
+		return
+	}
+	name := chi.URLParam(r, "name")
+	for _, a := range run.Artifacts {
+		if a.Name != name {
+			continue
+		}
+		_, rc, err := s.storage.Get(r.Context(), a.FileID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				http.Error(w, "artifact not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+			}
+			return
+		}
+		defer rc.Close()
+		w.Header().Set("Content-Type", a.ContentType)
+		if _, err := io.Copy(w, rc); err != nil {
+			slog.Warn("getRunArtifact: copy interrupted", "run", id, "artifact", name, "err", err)
+		}
+		return
+	}
+	http.Error(w, "artifact not found", http.StatusNotFound)
+}
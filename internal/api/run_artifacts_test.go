@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/storage"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestRunArtifacts_HTMLOutput(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	runHistorySvc := services.NewRunHistoryService(repository.NewMemoryRunRepository())
+	runHistorySvc.SetStorage(store)
+
+	srv := newTestServer()
+	srv.SetRunHistoryService(runHistorySvc)
+	srv.SetStorage(store)
+
+	ctx := context.Background()
+	record, err := runHistorySvc.StartRun(ctx, "layout-wf", "manual", "", nil, nil)
+	if err != nil {
+		t.Fatalf("StartRun: %v", err)
+	}
+	page := "<!DOCTYPE html><html><body><h1>Report</h1></body></html>"
+	outputs := map[string]any{
+		"out1":       page,
+		"__output__": map[string]any{"out1": page},
+	}
+	if err := runHistorySvc.CompleteRun(ctx, record.ID, outputs); err != nil {
+		t.Fatalf("CompleteRun: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/runs/"+record.ID+"/artifacts", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list artifacts: got %d; body: %s", w.Code, w.Body.String())
+	}
+	var artifacts []upal.RunArtifact
+	if err := json.Unmarshal(w.Body.Bytes(), &artifacts); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].Name != "out1.html" {
+		t.Fatalf("artifacts: got %+v, want one out1.html", artifacts)
+	}
+
+	req = httptest.NewRequest("GET", "/api/runs/"+record.ID+"/artifacts/out1.html", nil)
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get artifact: got %d; body: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type: got %q, want text/html", ct)
+	}
+	if w.Body.String() != page {
+		t.Errorf("body: got %q, want %q", w.Body.String(), page)
+	}
+
+	req = httptest.NewRequest("GET", "/api/runs/"+record.ID+"/artifacts/missing.txt", nil)
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing artifact: got %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// runExportPageSize bounds how many run records are held in memory at once
+// while exporting.
+const runExportPageSize = 100
+
+var runExportColumns = []string{
+	"id", "workflow_name", "trigger_type", "trigger_ref", "status",
+	"inputs", "outputs", "error", "retry_of", "rerun_of", "retry_count",
+	"created_at", "started_at", "completed_at",
+}
+
+// exportRuns handles GET /api/runs/export?format=ndjson|csv. It accepts the
+// same status filter as listRuns and pages through the repository by
+// (created_at, id), writing each page before fetching the next.
+func (s *Server) exportRuns(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "ndjson"
+	}
+	if format != "ndjson" && format != "csv" {
+		http.Error(w, "format must be ndjson or csv", http.StatusBadRequest)
+		return
+	}
+	status := r.URL.Query().Get("status")
+
+	var write func(*upal.RunRecord) error
+	var flush func()
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="runs.csv"`)
+		cw := csv.NewWriter(w)
+		if err := cw.Write(runExportColumns); err != nil {
+			return
+		}
+		write = func(run *upal.RunRecord) error { return cw.Write(runCSVRow(run)) }
+		flush = cw.Flush
+	default:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="runs.ndjson"`)
+		enc := json.NewEncoder(w)
+		write = func(run *upal.RunRecord) error { return enc.Encode(run) }
+		flush = func() {}
+	}
+	flusher, _ := w.(http.Flusher)
+
+	if s.runHistorySvc == nil {
+		flush()
+		return
+	}
+	var after *upal.RunCursor
+	for {
+		runs, err := s.runHistorySvc.ListAllRunsAfter(r.Context(), status, after, runExportPageSize)
+		if err != nil {
+			// Headers are already sent; the truncated body is all we can signal.
+			slog.WarnContext(r.Context(), "run export aborted", "err", err)
+			flush()
+			return
+		}
+		for _, run := range runs {
+			if err := write(run); err != nil {
+				return
+			}
+		}
+		flush()
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if len(runs) < runExportPageSize {
+			break
+		}
+		last := runs[len(runs)-1]
+		after = &upal.RunCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func runCSVRow(run *upal.RunRecord) []string {
+	inputs, _ := json.Marshal(run.Inputs)
+	outputs, _ := json.Marshal(run.Outputs)
+	return []string{
+		run.ID, run.WorkflowName, run.TriggerType, run.TriggerRef, string(run.Status),
+		string(inputs), string(outputs), derefString(run.Error), derefString(run.RetryOf), derefString(run.RerunOf),
+		strconv.Itoa(run.RetryCount),
+		run.CreatedAt.Format(time.RFC3339), formatTimePtr(run.StartedAt), formatTimePtr(run.CompletedAt),
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
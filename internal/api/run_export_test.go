@@ -0,0 +1,130 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+func newExportTestServer(t *testing.T, n int) *Server {
+	t.Helper()
+	runHistorySvc := services.NewRunHistoryService(repository.NewMemoryRunRepository())
+	srv := newTestServer()
+	srv.SetRunHistoryService(runHistorySvc)
+
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		record, err := runHistorySvc.StartRun(ctx, "export-wf", "manual", "", map[string]any{"n": i}, nil)
+		if err != nil {
+			t.Fatalf("StartRun: %v", err)
+		}
+		if err := runHistorySvc.CompleteRun(ctx, record.ID, map[string]any{"out": "done"}); err != nil {
+			t.Fatalf("CompleteRun: %v", err)
+		}
+	}
+	return srv
+}
+
+func TestExportRuns_NDJSON(t *testing.T) {
+	// More runs than one page so the export crosses a page boundary.
+	const n = runExportPageSize + 5
+	srv := newExportTestServer(t, n)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/export?format=ndjson", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+	}
+
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		var run upal.RunRecord
+		if err := json.Unmarshal(sc.Bytes(), &run); err != nil {
+			t.Fatalf("line %d is not a run record: %v", len(seen)+1, err)
+		}
+		seen[run.ID] = true
+	}
+	if len(seen) != n {
+		t.Errorf("exported %d distinct runs, want %d", len(seen), n)
+	}
+}
+
+// arrivingRunHistory records a new run after each page is listed, as a busy
+// server would while an export is streaming.
+type arrivingRunHistory struct {
+	ports.RunHistoryPort
+}
+
+func (h arrivingRunHistory) ListAllRunsAfter(ctx context.Context, status string, after *upal.RunCursor, limit int) ([]*upal.RunRecord, error) {
+	runs, err := h.RunHistoryPort.ListAllRunsAfter(ctx, status, after, limit)
+	h.StartRun(ctx, "export-wf", "manual", "", nil, nil)
+	return runs, err
+}
+
+func TestExportRuns_NewRunsDoNotShiftPages(t *testing.T) {
+	const n = 2*runExportPageSize + 5
+	srv := newExportTestServer(t, n)
+	srv.SetRunHistoryService(arrivingRunHistory{srv.runHistorySvc})
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/export?format=ndjson", nil))
+
+	lines := 0
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		var run upal.RunRecord
+		if err := json.Unmarshal(sc.Bytes(), &run); err != nil {
+			t.Fatalf("line %d is not a run record: %v", lines+1, err)
+		}
+		lines++
+		seen[run.ID] = true
+	}
+	if lines != n || len(seen) != n {
+		t.Errorf("exported %d lines, %d distinct runs, want %d of each", lines, len(seen), n)
+	}
+}
+
+func TestExportRuns_CSV(t *testing.T) {
+	srv := newExportTestServer(t, 2)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/export?format=csv&status=success", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+	}
+
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d, want header + 2", len(rows))
+	}
+	if rows[0][0] != "id" || rows[0][5] != "inputs" {
+		t.Errorf("header = %v", rows[0])
+	}
+	if rows[1][6] != `{"out":"done"}` {
+		t.Errorf("outputs column = %q, want JSON-encoded outputs", rows[1][6])
+	}
+}
+
+func TestExportRuns_UnknownFormat(t *testing.T) {
+	srv := newExportTestServer(t, 0)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/export?format=xml", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
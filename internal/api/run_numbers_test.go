@@ -0,0 +1,161 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/llmutil"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	runpub "github.com/soochol/upal/internal/services/run"
+	"github.com/soochol/upal/internal/upal"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// promptLLM records the text of the last prompt it received.
+type promptLLM struct {
+	mu     sync.Mutex
+	prompt string
+}
+
+func (l *promptLLM) Name() string { return "prompt" }
+
+func (l *promptLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	var sb strings.Builder
+	for _, c := range req.Contents {
+		for _, p := range c.Parts {
+			sb.WriteString(p.Text)
+		}
+	}
+	l.mu.Lock()
+	l.prompt = sb.String()
+	l.mu.Unlock()
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func (l *promptLLM) last() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.prompt
+}
+
+func TestRunWorkflow_PreservesLargeIntegerInputs(t *testing.T) {
+	llm := &promptLLM{}
+	llms := map[string]adkmodel.LLM{"p": llm}
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", llmutil.NewMapResolver(llms, nil, ""))
+	srv := NewServer(nil, wfSvc, repo, nil)
+	runHistorySvc := services.NewRunHistoryService(repository.NewMemoryRunRepository())
+	srv.SetRunHistoryService(runHistorySvc)
+	rm := services.NewRunManager(5 * time.Minute)
+	srv.SetRunManager(rm)
+	srv.SetRunPublisher(runpub.NewRunPublisher(wfSvc, rm, runHistorySvc, nil))
+
+	wf := upal.WorkflowDefinition{
+		Name: "id-wf",
+		Nodes: []upal.NodeDefinition{
+			{ID: "order_id", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "lookup", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "p/m", "prompt": "Look up order {{order_id}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "order_id", To: "lookup"}},
+	}
+	body, _ := json.Marshal(wf)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create workflow: got %d, want 201", w.Code)
+	}
+
+	// 2^63-1 is far past float64's exact integer range.
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/id-wf/run",
+		strings.NewReader(`{"inputs":{"order_id":9223372036854775807}}`)))
+	var started map[string]string
+	json.Unmarshal(w.Body.Bytes(), &started)
+	rec := waitForRun(t, runHistorySvc, started["run_id"])
+	if rec.Status != upal.RunStatusSuccess {
+		t.Fatalf("run status = %s, error = %v", rec.Status, rec.Error)
+	}
+	if got, want := llm.last(), "Look up order 9223372036854775807"; got != want {
+		t.Errorf("prompt = %q, want %q", got, want)
+	}
+}
+
+func TestParseInputs_FloatNumbers(t *testing.T) {
+	body := []byte(`{"id": 9007199254740993}`)
+
+	if got := parseWebhookPayload("application/json", "", body, false)["id"]; got != json.Number("9007199254740993") {
+		t.Errorf("webhook id = %#v, want json.Number", got)
+	}
+	if got := parseRunRequest(body, false).Inputs["id"]; got != json.Number("9007199254740993") {
+		t.Errorf("run input id = %#v, want json.Number", got)
+	}
+	// float_numbers restores the lossy float64 decoding.
+	if got := parseRunRequest(body, true).Inputs["id"]; got != float64(9007199254740992) {
+		t.Errorf("run input id with float numbers = %#v, want float64", got)
+	}
+
+	msg := a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: `{"workflow": "wf", "inputs": {"id": 9007199254740993}}`})
+	if _, inputs, _ := parseA2AMessage(msg, false); inputs["id"] != json.Number("9007199254740993") {
+		t.Errorf("a2a input id = %#v, want json.Number", inputs["id"])
+	}
+	if _, inputs, _ := parseA2AMessage(msg, true); inputs["id"] != float64(9007199254740992) {
+		t.Errorf("a2a input id with float numbers = %#v, want float64", inputs["id"])
+	}
+}
+
+func TestRerunRun_PreservesLargeIntegerOverrides(t *testing.T) {
+	srv := newTestServer()
+
+	wf := upal.WorkflowDefinition{
+		Name: "rerun-id-wf",
+		Nodes: []upal.NodeDefinition{
+			{ID: "order_id", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "order_id", To: "out"}},
+	}
+	body, _ := json.Marshal(wf)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create workflow: got %d, want 201", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/rerun-id-wf/run",
+		strings.NewReader(`{"inputs":{"order_id":1}}`)))
+	var first map[string]string
+	json.Unmarshal(w.Body.Bytes(), &first)
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+first["run_id"]+"/rerun",
+		strings.NewReader(`{"inputs":{"order_id":9223372036854775807}}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("rerun: got %d, body: %s", w.Code, w.Body.String())
+	}
+	var second map[string]string
+	json.Unmarshal(w.Body.Bytes(), &second)
+
+	rec, err := srv.runHistorySvc.GetRun(context.Background(), second["run_id"])
+	if err != nil {
+		t.Fatalf("get rerun: %v", err)
+	}
+	if got := rec.Inputs["order_id"]; got != json.Number("9223372036854775807") {
+		t.Errorf("rerun order_id = %#v, want json.Number", got)
+	}
+}
@@ -1,20 +1,30 @@
 package api
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"iter"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/llmutil"
 	"github.com/soochol/upal/internal/repository"
 	"github.com/soochol/upal/internal/services"
 	runpub "github.com/soochol/upal/internal/services/run"
 	"github.com/soochol/upal/internal/upal"
+	adkmodel "google.golang.org/adk/model"
 	"google.golang.org/adk/session"
+	"google.golang.org/genai"
 )
 
 // newTestServer creates a Server with a MemoryRepository and WorkflowService for tests.
@@ -100,6 +110,38 @@ func TestRunWorkflow_Returns202(t *testing.T) {
 	}
 }
 
+func TestRunWorkflow_ModelOverrideHeaders(t *testing.T) {
+	llms := map[string]adkmodel.LLM{"anthropic": nil}
+	resolver := llmutil.NewMapResolver(llms, nil, "")
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", resolver)
+	srv := NewServer(nil, wfSvc, repo, nil)
+
+	body := `{"workflow":{"nodes":[{"id":"agent1","type":"agent","config":{"model":"anthropic/claude"}}]}}`
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"unknown provider", "X-Model-Override", "bogus/model", http.StatusBadRequest},
+		{"unknown node", "X-Model-Override-missing", "anthropic/claude", http.StatusBadRequest},
+		{"node override", "X-Model-Override-agent1", "anthropic/other", http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/workflows/override-wf/run", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("got %d, want %d, body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestRunWorkflow_EmptyBody(t *testing.T) {
 	srv := newTestServer()
 
@@ -150,3 +192,662 @@ func TestStreamRunEvents_NotFound(t *testing.T) {
 		t.Fatalf("expected 404, got %d, body: %s", w.Code, w.Body.String())
 	}
 }
+
+func TestRerunRun_MergesInputOverrides(t *testing.T) {
+	srv := newTestServer()
+
+	wf := upal.WorkflowDefinition{
+		Name: "rerun-wf",
+		Nodes: []upal.NodeDefinition{
+			{ID: "topic", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "tone", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "topic", To: "out"}, {From: "tone", To: "out"}},
+	}
+	body, _ := json.Marshal(wf)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create workflow: got %d, want 201", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/rerun-wf/run",
+		strings.NewReader(`{"inputs":{"topic":"go","tone":"formal"}}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("run: got %d, body: %s", w.Code, w.Body.String())
+	}
+	var first map[string]string
+	json.Unmarshal(w.Body.Bytes(), &first)
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+first["run_id"]+"/rerun",
+		strings.NewReader(`{"inputs":{"tone":"casual"}}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("rerun: got %d, body: %s", w.Code, w.Body.String())
+	}
+	var second map[string]string
+	json.Unmarshal(w.Body.Bytes(), &second)
+	if second["run_id"] == "" || second["run_id"] == first["run_id"] {
+		t.Fatalf("rerun run_id = %q, want a new run", second["run_id"])
+	}
+	if second["rerun_of"] != first["run_id"] {
+		t.Errorf("rerun_of = %q, want %q", second["rerun_of"], first["run_id"])
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/"+second["run_id"], nil))
+	var record upal.RunRecord
+	if err := json.Unmarshal(w.Body.Bytes(), &record); err != nil {
+		t.Fatalf("decode run: %v", err)
+	}
+	if record.RerunOf == nil || *record.RerunOf != first["run_id"] {
+		t.Errorf("record.RerunOf = %v, want %q", record.RerunOf, first["run_id"])
+	}
+	if record.Inputs["topic"] != "go" || record.Inputs["tone"] != "casual" {
+		t.Errorf("inputs = %v, want topic=go tone=casual", record.Inputs)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/missing/rerun", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown run: got %d, want 404", w.Code)
+	}
+}
+
+// switchLLM counts calls and fails them while broken is set.
+type switchLLM struct {
+	mu     sync.Mutex
+	calls  int
+	broken bool
+}
+
+func (l *switchLLM) Name() string { return "switch" }
+
+func (l *switchLLM) GenerateContent(_ context.Context, _ *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	l.mu.Lock()
+	l.calls++
+	broken := l.broken
+	l.mu.Unlock()
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		if broken {
+			yield(nil, errors.New("upstream API unavailable"))
+			return
+		}
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func (l *switchLLM) set(broken bool) {
+	l.mu.Lock()
+	l.broken = broken
+	l.mu.Unlock()
+}
+
+func (l *switchLLM) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.calls
+}
+
+// waitForRun polls until the run leaves the pending/running states.
+func waitForRun(t *testing.T, svc *services.RunHistoryService, id string) *upal.RunRecord {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		rec, err := svc.GetRun(context.Background(), id)
+		if err == nil && rec.Status != upal.RunStatusRunning && rec.Status != upal.RunStatusPending {
+			return rec
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("run %s did not finish", id)
+	return nil
+}
+
+func TestResumeRun_ReusesUpstreamCheckpoints(t *testing.T) {
+	upstream, downstream := &switchLLM{}, &switchLLM{broken: true}
+	llms := map[string]adkmodel.LLM{"up": upstream, "down": downstream}
+	resolver := llmutil.NewMapResolver(llms, nil, "")
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", resolver)
+	srv := NewServer(nil, wfSvc, repo, nil)
+	runHistorySvc := services.NewRunHistoryService(repository.NewMemoryRunRepository())
+	srv.SetRunHistoryService(runHistorySvc)
+	rm := services.NewRunManager(5 * time.Minute)
+	srv.SetRunManager(rm)
+	srv.SetRunPublisher(runpub.NewRunPublisher(wfSvc, rm, runHistorySvc, nil))
+
+	wf := upal.WorkflowDefinition{
+		Name: "resume-wf",
+		Nodes: []upal.NodeDefinition{
+			{ID: "topic", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "research", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "up/m", "prompt": "{{topic}}"}},
+			{ID: "publish", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "down/m", "prompt": "{{research}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "topic", To: "research"}, {From: "research", To: "publish"}},
+	}
+	body, _ := json.Marshal(wf)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create workflow: got %d, want 201", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/resume-wf/run",
+		strings.NewReader(`{"inputs":{"topic":"go"}}`)))
+	var first map[string]string
+	json.Unmarshal(w.Body.Bytes(), &first)
+	if rec := waitForRun(t, runHistorySvc, first["run_id"]); rec.Status != upal.RunStatusFailed {
+		t.Fatalf("first run status = %s, want failed", rec.Status)
+	}
+	upstreamCalls := upstream.count()
+	if upstreamCalls == 0 {
+		t.Fatal("expected the upstream node to run")
+	}
+
+	downstream.set(false)
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+first["run_id"]+"/resume", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("resume: got %d, body: %s", w.Code, w.Body.String())
+	}
+	var resumed struct {
+		RunID       string   `json:"run_id"`
+		ResumedFrom string   `json:"resumed_from"`
+		ReusedNodes []string `json:"reused_nodes"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resumed)
+	if resumed.ResumedFrom != first["run_id"] {
+		t.Errorf("resumed_from = %q, want %q", resumed.ResumedFrom, first["run_id"])
+	}
+	if strings.Join(resumed.ReusedNodes, ",") != "research,topic" {
+		t.Errorf("reused_nodes = %v, want [research topic]", resumed.ReusedNodes)
+	}
+
+	rec := waitForRun(t, runHistorySvc, resumed.RunID)
+	if rec.Status != upal.RunStatusSuccess {
+		t.Fatalf("resumed run status = %s, error = %v", rec.Status, rec.Error)
+	}
+	if got := upstream.count(); got != upstreamCalls {
+		t.Errorf("upstream node re-executed: %d calls, want %d", got, upstreamCalls)
+	}
+	if rec.Outputs["research"] != "ok" || rec.Outputs["publish"] != "ok" {
+		t.Errorf("outputs = %v, want research and publish results", rec.Outputs)
+	}
+
+	// A succeeded run cannot be resumed.
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+resumed.RunID+"/resume", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("resume succeeded run: got %d, want 409", w.Code)
+	}
+}
+
+// blockingLLM signals each call on started and blocks until its context ends.
+type blockingLLM struct{ started chan struct{} }
+
+func (l *blockingLLM) Name() string { return "blocking" }
+
+func (l *blockingLLM) GenerateContent(ctx context.Context, _ *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		l.started <- struct{}{}
+		<-ctx.Done()
+		yield(nil, ctx.Err())
+	}
+}
+
+func TestActiveRuns_ListAndCancel(t *testing.T) {
+	llm := &blockingLLM{started: make(chan struct{}, 1)}
+	llms := map[string]adkmodel.LLM{"slow": llm}
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", llmutil.NewMapResolver(llms, nil, ""))
+	srv := NewServer(nil, wfSvc, repo, nil)
+	runHistorySvc := services.NewRunHistoryService(repository.NewMemoryRunRepository())
+	srv.SetRunHistoryService(runHistorySvc)
+	rm := services.NewRunManager(5 * time.Minute)
+	srv.SetRunManager(rm)
+	srv.SetRunPublisher(runpub.NewRunPublisher(wfSvc, rm, runHistorySvc, nil))
+
+	wf := upal.WorkflowDefinition{
+		Name:  "slow-wf",
+		Nodes: []upal.NodeDefinition{{ID: "think", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "slow/m", "prompt": "go"}}},
+	}
+	body, _ := json.Marshal(wf)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create workflow: got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/slow-wf/run", strings.NewReader(`{"inputs":{}}`)))
+	var started map[string]string
+	json.Unmarshal(w.Body.Bytes(), &started)
+	runID := started["run_id"]
+	select {
+	case <-llm.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run never reached the LLM call")
+	}
+
+	// The node_started event is published asynchronously; poll until it lands.
+	var list struct {
+		Runs []upal.ActiveRun `json:"runs"`
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		w = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/active", nil))
+		if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+			t.Fatalf("decode active runs: %v (%s)", err, w.Body.String())
+		}
+		if len(list.Runs) != 1 || list.Runs[0].CurrentNode != "" {
+			break
+		}
+	}
+	if len(list.Runs) != 1 {
+		t.Fatalf("active runs = %+v, want the blocking run", list.Runs)
+	}
+	got := list.Runs[0]
+	if got.RunID != runID || got.WorkflowName != "slow-wf" || got.CurrentNode != "think" || got.TriggerType != "manual" || got.StartedAt.IsZero() {
+		t.Errorf("active run = %+v", got)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+runID+"/cancel", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("cancel: got %d, body: %s", w.Code, w.Body.String())
+	}
+	for deadline := time.Now().Add(5 * time.Second); len(list.Runs) > 0 && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		w = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/active", nil))
+		json.Unmarshal(w.Body.Bytes(), &list)
+	}
+	if len(list.Runs) != 0 {
+		t.Fatalf("active runs after cancel = %+v, want none", list.Runs)
+	}
+	if rec, _ := runHistorySvc.GetRun(context.Background(), runID); rec.Status != upal.RunStatusCancelled {
+		t.Errorf("status = %q, want cancelled", rec.Status)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+runID+"/cancel", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("second cancel: got %d, want 409", w.Code)
+	}
+}
+
+func TestCancelRun_KeepsPartialOutputs(t *testing.T) {
+	slow := &blockingLLM{started: make(chan struct{}, 1)}
+	llms := map[string]adkmodel.LLM{"fast": &describeLLM{}, "slow": slow}
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", llmutil.NewMapResolver(llms, nil, ""))
+	srv := NewServer(nil, wfSvc, repo, nil)
+	runHistorySvc := services.NewRunHistoryService(repository.NewMemoryRunRepository())
+	srv.SetRunHistoryService(runHistorySvc)
+	rm := services.NewRunManager(5 * time.Minute)
+	srv.SetRunManager(rm)
+	srv.SetRunPublisher(runpub.NewRunPublisher(wfSvc, rm, runHistorySvc, nil))
+
+	if err := repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "two-step",
+		Nodes: []upal.NodeDefinition{
+			{ID: "draft", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "fast/m", "prompt": "write"}},
+			{ID: "polish", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "slow/m", "prompt": "polish {{draft}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "draft", To: "polish"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/two-step/run", strings.NewReader(`{"inputs":{}}`)))
+	var started map[string]string
+	json.Unmarshal(w.Body.Bytes(), &started)
+	runID := started["run_id"]
+	select {
+	case <-slow.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run never reached the second node")
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+runID+"/cancel", strings.NewReader(`{"reason":"wrong topic"}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("cancel: got %d, body: %s", w.Code, w.Body.String())
+	}
+
+	// The record is written before the run manager sees the run finish.
+	var done bool
+	var payload map[string]any
+	for deadline := time.Now().Add(5 * time.Second); !done && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		_, _, done, payload, _ = rm.Subscribe(runID, 0)
+	}
+	state, _ := payload["state"].(map[string]any)
+	if !done || payload["cancel_reason"] != "wrong topic" || state["draft"] != "생성된 설명입니다." {
+		t.Errorf("done payload = %v", payload)
+	}
+
+	rec, _ := runHistorySvc.GetRun(context.Background(), runID)
+	if rec.Status != upal.RunStatusCancelled || rec.CancelReason != "wrong topic" {
+		t.Fatalf("status = %q, cancel_reason = %q", rec.Status, rec.CancelReason)
+	}
+	if rec.Outputs["draft"] != "생성된 설명입니다." {
+		t.Errorf("outputs = %v, want the completed draft node", rec.Outputs)
+	}
+	if _, ok := rec.Outputs["polish"]; ok {
+		t.Errorf("outputs include the cancelled node: %v", rec.Outputs)
+	}
+}
+
+// sseFrame is one parsed server-sent event.
+type sseFrame struct {
+	id    int
+	event string
+}
+
+// readSSEFrames reads n events from an SSE stream, or until it ends.
+func readSSEFrames(t *testing.T, br *bufio.Reader, n int) []sseFrame {
+	t.Helper()
+	var frames []sseFrame
+	cur := sseFrame{id: -1}
+	for len(frames) < n {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			cur.id, _ = strconv.Atoi(strings.TrimPrefix(line, "id: "))
+		case strings.HasPrefix(line, "event: "):
+			cur.event = strings.TrimPrefix(line, "event: ")
+		case line == "" && cur.event != "":
+			frames = append(frames, cur)
+			cur = sseFrame{id: -1}
+		}
+	}
+	return frames
+}
+
+func TestStreamRunEvents_ResumesFromLastEventID(t *testing.T) {
+	srv := newTestServer()
+	rm := srv.runManager
+	rm.Register("run-sse")
+	appendEvents := func(n int) {
+		for i := 0; i < n; i++ {
+			rm.Append("run-sse", upal.EventRecord{WorkflowEvent: upal.WorkflowEvent{
+				Type: upal.EventNodeStarted, NodeID: "n", Payload: map[string]any{"i": i},
+			}})
+		}
+	}
+	appendEvents(5)
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	connect := func(lastID string) (*http.Response, *bufio.Reader) {
+		req, _ := http.NewRequest("GET", ts.URL+"/api/runs/run-sse/events", nil)
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		return resp, bufio.NewReader(resp.Body)
+	}
+
+	// First connection consumes part of the buffer, then drops.
+	resp, br := connect("")
+	first := readSSEFrames(t, br, 3)
+	resp.Body.Close()
+	if len(first) != 3 || first[2].id != 2 {
+		t.Fatalf("first connection frames = %+v", first)
+	}
+
+	// More events arrive while the client is disconnected.
+	appendEvents(3)
+	rm.Complete("run-sse", map[string]any{"status": "completed"})
+
+	resp, br = connect(strconv.Itoa(first[len(first)-1].id))
+	defer resp.Body.Close()
+	second := readSSEFrames(t, br, 100)
+
+	var ids []int
+	for _, f := range append(first, second...) {
+		if f.event != "done" {
+			ids = append(ids, f.id)
+		}
+	}
+	for i, id := range ids {
+		if id != i {
+			t.Fatalf("event ids = %v, want 0..7 with no duplicates or gaps", ids)
+		}
+	}
+	if len(ids) != 8 {
+		t.Fatalf("event ids = %v, want 8 events", ids)
+	}
+	if last := second[len(second)-1]; last.event != "done" {
+		t.Errorf("last frame = %+v, want done", last)
+	}
+}
+
+func TestStreamRunEvents_InvalidLastEventID(t *testing.T) {
+	srv := newTestServer()
+	srv.runManager.Register("run-bad")
+	req := httptest.NewRequest("GET", "/api/runs/run-bad/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestStreamRunEvents_Heartbeat(t *testing.T) {
+	srv := newTestServer()
+	srv.SetSSEHeartbeat(10 * time.Millisecond)
+	rm := srv.runManager
+	rm.Register("run-hb")
+	rm.Append("run-hb", upal.EventRecord{WorkflowEvent: upal.WorkflowEvent{Type: upal.EventNodeStarted, NodeID: "n"}})
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(ts.URL + "/api/runs/run-hb/events")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	br := bufio.NewReader(resp.Body)
+
+	// No events arrive for a while: the stream carries heartbeats instead.
+	heartbeats := 0
+	for heartbeats < 3 {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read during gap: %v", err)
+		}
+		if line == ": keep-alive\n" {
+			heartbeats++
+		}
+	}
+
+	// Once the run completes the stream ends with done and no more heartbeats.
+	rm.Complete("run-hb", map[string]any{"status": "completed"})
+	rest, err := io.ReadAll(br)
+	if err != nil {
+		t.Fatalf("read after completion: %v", err)
+	}
+	doneAt := strings.Index(string(rest), "event: done")
+	if doneAt < 0 {
+		t.Fatalf("stream ended without done event: %q", rest)
+	}
+	if strings.Contains(string(rest[doneAt:]), "keep-alive") {
+		t.Errorf("heartbeat after done: %q", rest[doneAt:])
+	}
+}
+
+// readRunChanges reads "run" frames from a run-list stream until match
+// returns true or the stream ends.
+func readRunChanges(t *testing.T, br *bufio.Reader, match func(upal.RunChange) bool) []upal.RunChange {
+	t.Helper()
+	var changes []upal.RunChange
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return changes
+		}
+		data, ok := strings.CutPrefix(strings.TrimRight(line, "\n"), "data: ")
+		if !ok {
+			continue
+		}
+		var change upal.RunChange
+		if err := json.Unmarshal([]byte(data), &change); err != nil {
+			t.Fatalf("decode change %q: %v", data, err)
+		}
+		changes = append(changes, change)
+		if match(change) {
+			return changes
+		}
+	}
+}
+
+func TestStreamRunList_NewRunAppears(t *testing.T) {
+	srv := newTestServer()
+	srv.repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "echo",
+		Nodes: []upal.NodeDefinition{
+			{ID: "question", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "answer", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "question", To: "answer"}},
+	})
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(ts.URL + "/api/runs/stream")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	runResp, err := client.Post(ts.URL+"/api/workflows/echo/run", "application/json", strings.NewReader(`{"inputs":{"question":"ping"}}`))
+	if err != nil {
+		t.Fatalf("start run: %v", err)
+	}
+	var started struct {
+		RunID string `json:"run_id"`
+	}
+	json.NewDecoder(runResp.Body).Decode(&started)
+	runResp.Body.Close()
+	if started.RunID == "" {
+		t.Fatalf("start run: status %d, no run_id", runResp.StatusCode)
+	}
+
+	changes := readRunChanges(t, bufio.NewReader(resp.Body), func(c upal.RunChange) bool {
+		return c.Run.ID == started.RunID && c.Run.CompletedAt != nil
+	})
+	if len(changes) == 0 {
+		t.Fatal("stream ended without changes")
+	}
+	first := changes[0]
+	if first.Type != "created" || first.Run.ID != started.RunID || first.Run.WorkflowName != "echo" || first.Run.Status != upal.RunStatusRunning {
+		t.Errorf("first change = %+v", first)
+	}
+	last := changes[len(changes)-1]
+	if last.Run.ID != started.RunID || last.Type != "updated" || last.Run.Status != upal.RunStatusSuccess {
+		t.Errorf("last change = %s %s %s", last.Type, last.Run.ID, last.Run.Status)
+	}
+}
+
+func TestStreamRunList_SinceReplaysRecentRuns(t *testing.T) {
+	srv := newTestServer()
+	ctx := context.Background()
+	cutoff := time.Now()
+	record, err := srv.runHistorySvc.StartRun(ctx, "wf", "manual", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqCtx, cancel := context.WithCancel(ctx)
+	req := httptest.NewRequest("GET", "/api/runs/stream?since="+cutoff.Add(-time.Second).Format(time.RFC3339), nil).WithContext(reqCtx)
+	w := httptest.NewRecorder()
+	cancel() // the replay is written before the handler waits for changes
+	srv.Handler().ServeHTTP(w, req)
+
+	changes := readRunChanges(t, bufio.NewReader(w.Body), func(upal.RunChange) bool { return false })
+	if len(changes) != 1 || changes[0].Run.ID != record.ID {
+		t.Errorf("replayed changes = %+v", changes)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/stream?since=yesterday", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid since: expected 400, got %d", w.Code)
+	}
+}
+
+func TestRerunRun_WaitsForRunStartLimit(t *testing.T) {
+	srv := newTestServer()
+	limiter := services.NewConcurrencyLimiter(upal.ConcurrencyLimits{PerWorkflowStartsPerWindow: 1, StartWindow: time.Hour})
+	srv.runPublisher.SetConcurrencyLimiter(limiter)
+	runHistorySvc := srv.runHistorySvc.(*services.RunHistoryService)
+
+	wf := upal.WorkflowDefinition{
+		Name: "limited-wf",
+		Nodes: []upal.NodeDefinition{
+			{ID: "topic", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "topic", To: "out"}},
+	}
+	body, _ := json.Marshal(wf)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create workflow: got %d, want 201", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/limited-wf/run", strings.NewReader(`{"inputs":{"topic":"go"}}`)))
+	var first map[string]string
+	json.Unmarshal(w.Body.Bytes(), &first)
+	if rec := waitForRun(t, runHistorySvc, first["run_id"]); rec.Status != upal.RunStatusSuccess {
+		t.Fatalf("first run status = %s", rec.Status)
+	}
+
+	// The window allows one start per hour, so the rerun has to wait.
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+first["run_id"]+"/rerun", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("rerun: got %d, body: %s", w.Code, w.Body.String())
+	}
+	var second map[string]string
+	json.Unmarshal(w.Body.Bytes(), &second)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for limiter.Stats().StartRate.Delayed == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if limiter.Stats().StartRate.Delayed != 1 {
+		t.Fatal("rerun did not wait for the start window")
+	}
+	if rec, _ := runHistorySvc.GetRun(context.Background(), second["run_id"]); len(rec.NodeRuns) > 0 {
+		t.Errorf("waiting rerun executed nodes: %+v", rec.NodeRuns)
+	}
+
+	// A waiting run can still be cancelled.
+	if !srv.runManager.Cancel(second["run_id"], "no longer needed") {
+		t.Fatal("Cancel returned false for a waiting run")
+	}
+	if rec := waitForRun(t, runHistorySvc, second["run_id"]); rec.Status != upal.RunStatusCancelled {
+		t.Errorf("cancelled rerun status = %s, want cancelled", rec.Status)
+	}
+}
@@ -1,10 +1,22 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
 )
 
 func (s *Server) listRuns(w http.ResponseWriter, r *http.Request) {
@@ -39,6 +51,293 @@ func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, run)
 }
 
+// listActiveRuns handles GET /api/runs/active. Runs whose record the caller
+// cannot read are left out.
+func (s *Server) listActiveRuns(w http.ResponseWriter, r *http.Request) {
+	active := []upal.ActiveRun{}
+	if s.runManager == nil {
+		writeJSON(w, map[string]any{"runs": active})
+		return
+	}
+	for _, run := range s.runManager.Active() {
+		if s.runHistorySvc != nil {
+			record, err := s.runHistorySvc.GetRun(r.Context(), run.RunID)
+			if err != nil {
+				continue
+			}
+			run.TriggerType, run.TriggerRef = record.TriggerType, record.TriggerRef
+			if run.WorkflowName == "" {
+				run.WorkflowName = record.WorkflowName
+			}
+		}
+		active = append(active, run)
+	}
+	writeJSON(w, map[string]any{"runs": active})
+}
+
+// runFeed is implemented by run history services that broadcast run changes.
+type runFeed interface {
+	SubscribeRuns() (<-chan upal.RunChange, func())
+}
+
+// runStreamReplayLimit bounds how many recent runs a since= replay scans.
+const runStreamReplayLimit = 200
+
+// streamRunList handles GET /api/runs/stream: a server-sent events feed of
+// run records as they are created, change status and complete. Each frame is
+// a "run" event whose data is a upal.RunChange. With ?since=<RFC3339>, runs
+// created or finished after that time are sent first as "updated" changes, so
+// a dashboard can reconnect without re-fetching the list; a change racing the
+// replay may be delivered twice.
+func (s *Server) streamRunList(w http.ResponseWriter, r *http.Request) {
+	feed, ok := s.runHistorySvc.(runFeed)
+	if !ok {
+		http.Error(w, "run streaming not available", http.StatusServiceUnavailable)
+		return
+	}
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "invalid since: expected RFC3339 time", http.StatusBadRequest)
+			return
+		}
+		since = t
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before replaying so nothing written in between is missed.
+	changes, unsubscribe := feed.SubscribeRuns()
+	defer unsubscribe()
+
+	var replay []*upal.RunRecord
+	if !since.IsZero() {
+		runs, _, err := s.runHistorySvc.ListAllRuns(r.Context(), runStreamReplayLimit, 0, "")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, run := range runs {
+			if run.CreatedAt.After(since) || (run.CompletedAt != nil && run.CompletedAt.After(since)) {
+				replay = append(replay, run)
+			}
+		}
+		slices.SortFunc(replay, func(a, b *upal.RunRecord) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	for _, run := range replay {
+		snapshot := *run
+		snapshot.WorkflowDef = nil
+		writeRunChange(w, upal.RunChange{Type: "updated", Run: &snapshot})
+	}
+	flusher.Flush()
+
+	var heartbeat <-chan time.Time
+	if s.sseHeartbeat > 0 {
+		ticker := time.NewTicker(s.sseHeartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	userID := upal.UserIDFromContext(r.Context())
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case change := <-changes:
+			if change.UserID != userID {
+				continue
+			}
+			writeRunChange(w, change)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeRunChange(w http.ResponseWriter, change upal.RunChange) {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: run\ndata: %s\n\n", data)
+}
+
+// CancelRunRequest is the optional body of POST /api/runs/{id}/cancel.
+type CancelRunRequest struct {
+	Reason string `json:"reason"`
+}
+
+// defaultCancelReason is recorded when a cancel request gives no reason.
+const defaultCancelReason = "cancelled by request"
+
+// cancelRun handles POST /api/runs/{id}/cancel. Cancellation is asynchronous:
+// the run is recorded as cancelled, with the reason and the outputs of the
+// nodes that completed, once its execution stops.
+func (s *Server) cancelRun(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req CancelRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Reason = strings.TrimSpace(req.Reason); req.Reason == "" {
+		req.Reason = defaultCancelReason
+	}
+	if s.runHistorySvc != nil {
+		if _, err := s.runHistorySvc.GetRun(r.Context(), id); err != nil {
+			http.Error(w, "run not found", http.StatusNotFound)
+			return
+		}
+	}
+	if s.runManager == nil || !s.runManager.Cancel(id, req.Reason) {
+		http.Error(w, "run is not executing", http.StatusConflict)
+		return
+	}
+	writeJSONStatus(w, http.StatusAccepted, map[string]string{"run_id": id, "status": "cancelling"})
+}
+
+// RerunRequest carries input overrides for replaying a past run.
+type RerunRequest struct {
+	Inputs map[string]any `json:"inputs"`
+}
+
+// rerunRun starts a new run of a past run's workflow definition, with the
+// original inputs merged under any overrides from the request body.
+func (s *Server) rerunRun(w http.ResponseWriter, r *http.Request) {
+	if s.runHistorySvc == nil {
+		http.Error(w, "run history not available", http.StatusNotFound)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	original, err := s.runHistorySvc.GetRun(r.Context(), id)
+	if err != nil {
+		http.Error(w, "run not found", http.StatusNotFound)
+		return
+	}
+
+	var req RerunRequest
+	if r.ContentLength != 0 && !s.decodeInputsJSON(w, r, &req) {
+		return
+	}
+
+	// Prefer the definition the original run executed so edits made since
+	// then do not change what is being replayed.
+	wf := original.WorkflowDef
+	if wf == nil {
+		wf, err = s.workflowSvc.Lookup(r.Context(), original.WorkflowName)
+		if err != nil {
+			http.Error(w, "workflow not found", http.StatusNotFound)
+			return
+		}
+	}
+	if err := s.workflowSvc.Validate(wf); err != nil {
+		writeRunValidationError(w, err)
+		return
+	}
+
+	inputs := make(map[string]any, len(original.Inputs)+len(req.Inputs))
+	for k, v := range original.Inputs {
+		inputs[k] = v
+	}
+	for k, v := range req.Inputs {
+		inputs[k] = v
+	}
+
+	record, err := s.runHistorySvc.StartRerun(r.Context(), original, inputs, wf)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to create rerun record", "err", err)
+		http.Error(w, "failed to start rerun", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(runIDHeader, record.ID)
+
+	if s.runManager != nil && s.runPublisher != nil {
+		s.runManager.Register(record.ID)
+		go s.runPublisher.Launch(upal.WithRunID(context.Background(), record.ID), record.ID, wf, inputs)
+	}
+
+	writeJSONStatus(w, http.StatusAccepted, map[string]string{"run_id": record.ID, "rerun_of": original.ID})
+}
+
+// resumeRun restarts a failed run from its first failed or incomplete node.
+// Upstream nodes whose checkpointed outputs are still valid for the current
+// workflow definition are restored rather than executed again.
+func (s *Server) resumeRun(w http.ResponseWriter, r *http.Request) {
+	if s.runHistorySvc == nil {
+		http.Error(w, "run history not available", http.StatusNotFound)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	original, err := s.runHistorySvc.GetRun(r.Context(), id)
+	if err != nil {
+		http.Error(w, "run not found", http.StatusNotFound)
+		return
+	}
+	if original.Status != upal.RunStatusFailed && original.Status != upal.RunStatusCancelled {
+		http.Error(w, "only failed or cancelled runs can be resumed", http.StatusConflict)
+		return
+	}
+
+	// Resume against the current definition so a fix made after the failure
+	// takes effect; checkpoints of nodes that changed are discarded.
+	wf, err := s.workflowSvc.Lookup(r.Context(), original.WorkflowName)
+	if err != nil {
+		if original.WorkflowDef == nil {
+			http.Error(w, "workflow not found", http.StatusNotFound)
+			return
+		}
+		wf = original.WorkflowDef
+	}
+	if err := s.workflowSvc.Validate(wf); err != nil {
+		writeRunValidationError(w, err)
+		return
+	}
+
+	restored, err := services.ReusableOutputs(original, wf)
+	if err != nil {
+		writeJSONStatus(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	reused := make([]string, 0, len(restored))
+	for nodeID := range restored {
+		reused = append(reused, nodeID)
+	}
+	sort.Strings(reused)
+
+	record, err := s.runHistorySvc.StartRerun(r.Context(), original, original.Inputs, wf)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to create resumed run record", "err", err)
+		http.Error(w, "failed to resume run", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(runIDHeader, record.ID)
+
+	if s.runManager != nil && s.runPublisher != nil {
+		s.runManager.Register(record.ID)
+		ctx := upal.WithRestoredOutputs(upal.WithRunID(context.Background(), record.ID), restored)
+		go s.runPublisher.Launch(ctx, record.ID, wf, original.Inputs)
+	}
+
+	writeJSONStatus(w, http.StatusAccepted, map[string]any{
+		"run_id":       record.ID,
+		"resumed_from": original.ID,
+		"reused_nodes": reused,
+	})
+}
+
 func (s *Server) listWorkflowRuns(w http.ResponseWriter, r *http.Request) {
 	if s.runHistorySvc == nil {
 		writeJSON(w, map[string]any{"runs": []any{}, "total": 0})
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CopyScheduleRequest is the body of POST /api/schedules/{id}/copy.
+type CopyScheduleRequest struct {
+	WorkflowName string `json:"workflow_name"`
+}
+
+// copySchedule handles POST /api/schedules/{id}/copy. It creates a schedule
+// for another workflow with the source schedule's cron, timezone, inputs and
+// retry policy, and returns it with 201.
+func (s *Server) copySchedule(w http.ResponseWriter, r *http.Request) {
+	var req CopyScheduleRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.WorkflowName == "" {
+		http.Error(w, "workflow_name is required", http.StatusBadRequest)
+		return
+	}
+	if _, err := s.repo.Get(r.Context(), req.WorkflowName); err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	sched, err := s.schedulerSvc.CopySchedule(r.Context(), chi.URLParam(r, "id"), req.WorkflowName)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSONStatus(w, http.StatusCreated, sched)
+}
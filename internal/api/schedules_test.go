@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services/scheduler"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestCopySchedule(t *testing.T) {
+	srv := newTestServer()
+	schedRepo := repository.NewMemoryScheduleRepository()
+	schedSvc := scheduler.NewSchedulerService(schedRepo, nil, nil, nil, nil)
+	srv.SetSchedulerService(schedSvc)
+	seedWorkflow(t, srv, "digest-v2")
+
+	ctx := context.Background()
+	src := &upal.Schedule{
+		WorkflowName: "digest",
+		CronExpr:     "0 9 * * 1-5",
+		Timezone:     "Asia/Seoul",
+		Enabled:      true,
+		Inputs:       map[string]any{"topic": "ai"},
+		RetryPolicy:  &upal.RetryPolicy{MaxRetries: 2, InitialDelay: time.Second, MaxDelay: time.Minute, BackoffFactor: 2},
+		ActiveHours:  &upal.ActiveHours{Start: "08:00", End: "18:00", Days: []string{"mon", "tue"}},
+	}
+	if err := schedSvc.AddSchedule(ctx, src); err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+	src.ConsecutiveFailures = 2
+	src.LastOutcome = upal.ScheduleOutcomeSkippedBlackout
+	schedRepo.Update(ctx, src)
+
+	do := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/schedules/"+id+"/copy", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(src.ID, `{"workflow_name":"digest-v2"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var copied upal.Schedule
+	json.Unmarshal(w.Body.Bytes(), &copied)
+
+	if copied.ID == "" || copied.ID == src.ID {
+		t.Errorf("copy ID = %q, want a new ID", copied.ID)
+	}
+	if copied.WorkflowName != "digest-v2" {
+		t.Errorf("workflow_name = %q, want digest-v2", copied.WorkflowName)
+	}
+	if copied.CronExpr != src.CronExpr || copied.Timezone != src.Timezone || copied.Enabled != src.Enabled {
+		t.Errorf("cron/timezone/enabled = %q/%q/%v, want %q/%q/%v",
+			copied.CronExpr, copied.Timezone, copied.Enabled, src.CronExpr, src.Timezone, src.Enabled)
+	}
+	if !reflect.DeepEqual(copied.Inputs, src.Inputs) {
+		t.Errorf("inputs = %v, want %v", copied.Inputs, src.Inputs)
+	}
+	if !reflect.DeepEqual(copied.RetryPolicy, src.RetryPolicy) {
+		t.Errorf("retry_policy = %+v, want %+v", copied.RetryPolicy, src.RetryPolicy)
+	}
+	if !reflect.DeepEqual(copied.ActiveHours, src.ActiveHours) {
+		t.Errorf("active_hours = %+v, want %+v", copied.ActiveHours, src.ActiveHours)
+	}
+	if copied.ConsecutiveFailures != 0 || copied.LastOutcome != "" {
+		t.Errorf("run history was copied: failures=%d outcome=%q", copied.ConsecutiveFailures, copied.LastOutcome)
+	}
+	if _, err := schedRepo.Get(ctx, copied.ID); err != nil {
+		t.Errorf("copy was not stored: %v", err)
+	}
+	if orig, _ := schedRepo.Get(ctx, src.ID); orig.WorkflowName != "digest" {
+		t.Errorf("source schedule changed: %+v", orig)
+	}
+
+	if w := do("sched-missing", `{"workflow_name":"digest-v2"}`); w.Code != http.StatusNotFound {
+		t.Errorf("missing schedule: expected 404, got %d", w.Code)
+	}
+	if w := do(src.ID, `{"workflow_name":"nope"}`); w.Code != http.StatusNotFound {
+		t.Errorf("missing workflow: expected 404, got %d", w.Code)
+	}
+	if w := do(src.ID, `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("missing workflow_name: expected 400, got %d", w.Code)
+	}
+}
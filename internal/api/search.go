@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// search handles GET /api/search?q=...&type=workflow,pipeline,run&limit=N.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	if s.searchSvc == nil {
+		http.Error(w, "search not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	var types []upal.SearchResultType
+	if raw := r.URL.Query().Get("type"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			switch st := upal.SearchResultType(strings.TrimSpace(t)); st {
+			case upal.SearchTypeWorkflow, upal.SearchTypePipeline, upal.SearchTypeRun:
+				types = append(types, st)
+			default:
+				http.Error(w, "unknown type: "+t, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	results, err := s.searchSvc.Search(r.Context(), q, types, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, orEmpty(results))
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func newTestServerWithSearch(t *testing.T) (*Server, *services.RunHistoryService) {
+	t.Helper()
+	srv := newTestServer()
+	runHistorySvc := services.NewRunHistoryService(repository.NewMemoryRunRepository())
+	pipelineSvc := services.NewPipelineService(repository.NewMemoryPipelineRepository(), repository.NewMemoryPipelineRunRepository())
+	srv.SetSearchService(services.NewSearchService(srv.repo, pipelineSvc, runHistorySvc))
+	return srv, runHistorySvc
+}
+
+func doSearch(t *testing.T, srv *Server, query string) []upal.SearchResult {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/search?"+query, nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var results []upal.SearchResult
+	if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return results
+}
+
+func TestSearch_WorkflowByNodeLabel(t *testing.T) {
+	srv, _ := newTestServerWithSearch(t)
+	ctx := context.Background()
+	srv.repo.Create(ctx, &upal.WorkflowDefinition{
+		Name: "news-digest",
+		Nodes: []upal.NodeDefinition{
+			{ID: "agent1", Type: upal.NodeTypeAgent, Config: map[string]any{"label": "Summarize Headlines"}},
+		},
+	})
+	srv.repo.Create(ctx, &upal.WorkflowDefinition{Name: "unrelated"})
+
+	results := doSearch(t, srv, "q=headlines&type=workflow")
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %+v", len(results), results)
+	}
+	if results[0].Type != upal.SearchTypeWorkflow || results[0].ID != "news-digest" {
+		t.Errorf("unexpected result: %+v", results[0])
+	}
+	if results[0].Snippet != "Summarize Headlines" {
+		t.Errorf("snippet: got %q", results[0].Snippet)
+	}
+}
+
+func TestSearch_RunByInputValue(t *testing.T) {
+	srv, runHistorySvc := newTestServerWithSearch(t)
+	ctx := context.Background()
+	run, err := runHistorySvc.StartRun(ctx, "translator", "manual", "", map[string]any{"text": "kimchi recipe"}, nil)
+	if err != nil {
+		t.Fatalf("start run: %v", err)
+	}
+	runHistorySvc.StartRun(ctx, "translator", "manual", "", map[string]any{"text": "pasta"}, nil)
+
+	results := doSearch(t, srv, "q=kimchi")
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %+v", len(results), results)
+	}
+	if results[0].Type != upal.SearchTypeRun || results[0].ID != run.ID {
+		t.Errorf("unexpected result: %+v", results[0])
+	}
+}
+
+func TestSearch_Validation(t *testing.T) {
+	srv, _ := newTestServerWithSearch(t)
+
+	for _, query := range []string{"", "q=x&type=bogus"} {
+		req := httptest.NewRequest("GET", "/api/search?"+query, nil)
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("query %q: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestSearch_SnippetAfterLengthChangingLowercase(t *testing.T) {
+	srv, _ := newTestServerWithSearch(t)
+	// "İ" lowercases to a longer byte sequence, shifting offsets in the
+	// lowercased text past those of the original.
+	srv.repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name:        "trip",
+		Description: strings.Repeat("İ", 30) + " Weekend itinerary",
+	})
+
+	results := doSearch(t, srv, "q=weekend&type=workflow")
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %+v", len(results), results)
+	}
+	if want := "…" + strings.Repeat("İ", 20) + " Weekend itinerary"; results[0].Snippet != want {
+		t.Errorf("snippet: got %q", results[0].Snippet)
+	}
+}
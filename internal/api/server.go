@@ -1,6 +1,7 @@
 package api
 
 import (
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -10,18 +11,22 @@ import (
 	"github.com/soochol/upal/internal/chat"
 	"github.com/soochol/upal/internal/config"
 	"github.com/soochol/upal/internal/generate"
+	upalmodel "github.com/soochol/upal/internal/model"
 	"github.com/soochol/upal/internal/repository"
 	"github.com/soochol/upal/internal/services"
 	runpub "github.com/soochol/upal/internal/services/run"
 	"github.com/soochol/upal/internal/skills"
 	"github.com/soochol/upal/internal/storage"
 	"github.com/soochol/upal/internal/tools"
+	"github.com/soochol/upal/internal/upal"
 	"github.com/soochol/upal/internal/upal/ports"
 	adkmodel "google.golang.org/adk/model"
 )
 
 type Server struct {
 	workflowSvc          ports.WorkflowExecutor
+	nodeRunner           ports.NodeRunner
+	templateResolver     ports.TemplateResolver
 	runHistorySvc        ports.RunHistoryPort
 	schedulerSvc         ports.SchedulerPort
 	limiter              *services.ConcurrencyLimiter
@@ -35,6 +40,8 @@ type Server struct {
 	providerConfigs      map[string]config.ProviderConfig
 	skills               skills.Provider
 	a2aBaseURL           string
+	publicURL            string
+	basePath             string
 	retryExecutor        ports.RetryExecutor
 	connectionSvc        ports.ConnectionPort
 	executionReg         ports.ExecutionRegistryPort
@@ -45,16 +52,34 @@ type Server struct {
 	contentSvc           ports.ContentSessionPort
 	collector            *services.ContentCollector
 	publishChannelRepo   repository.PublishChannelRepository
+	eventSubRepo         repository.EventSubscriptionRepository
+	openAPIImports       repository.OpenAPIImportRepository
 	generationManager    *services.GenerationManager
 	aiProviderSvc        *services.AIProviderService
 	authSvc              *services.AuthService
 	frontendURL          string
 	sessionSvc           *services.SessionService
 	runSvc               *services.RunService
+	searchSvc            *services.SearchService
+	providerBreakers     *upalmodel.CircuitBreakers
+	reachability         *upalmodel.ReachabilityChecker
+	warmer               *upalmodel.Warmer
+	auditSvc             *services.AuditService
+	maintenanceSvc       *services.MaintenanceService
+	webhookCfg           config.WebhookConfig
+	workflowSuggestSvc   *services.WorkflowSuggestService
+	regressionChecker    *services.RegressionChecker
+	sseHeartbeat         time.Duration
+	floatNumbers         bool
+	previewTimeout       time.Duration
+	logLevel             *slog.LevelVar
+	requestTimeouts      config.RequestTimeoutConfig
+	webhookBackoff       retryBackoff
 	corsOrigins          []string
 	thumbnailTimeout     time.Duration
 	uploadMaxSize        int64
 	chatHandler          *chat.Handler
+	workflowLimits       upal.WorkflowLimits
 }
 
 func (s *Server) SetProviderConfigs(configs map[string]config.ProviderConfig) {
@@ -74,14 +99,18 @@ func (s *Server) Handler() http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(CorrelationMiddleware)
 	r.Use(cors.Handler(cors.Options{
 		AllowOriginFunc:  s.allowOrigin,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
 		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{runIDHeader},
 		AllowCredentials: true,
 	}))
 	r.Use(AuthMiddleware(s.authSvc))
+	r.Use(s.auditMiddleware)
 	r.Route("/api", func(r chi.Router) {
+		r.Use(s.requestTimeout)
 		r.Route("/auth", func(r chi.Router) {
 			r.Get("/login/{provider}", s.authLogin)
 			r.Get("/callback/{provider}", s.authCallback)
@@ -93,31 +122,73 @@ func (s *Server) Handler() http.Handler {
 			r.Post("/", s.createWorkflow)
 			r.Get("/", s.listWorkflows)
 			r.Post("/suggest-name", s.suggestWorkflowName)
+			r.Post("/suggest", s.suggestWorkflows)
 			r.Get("/{name}", s.getWorkflow)
+			r.Get("/{name}/graph", s.getWorkflowGraph)
 			r.Put("/{name}", s.updateWorkflow)
+			r.Patch("/{name}", s.patchWorkflow)
 			r.Delete("/{name}", s.deleteWorkflow)
-			r.Post("/{name}/run", s.runWorkflow)
+			r.With(s.rejectInMaintenance).Post("/{name}/run", s.runWorkflow)
+			r.With(s.rejectInMaintenance).Post("/{name}/preview", s.previewWorkflow)
+			r.With(s.rejectInMaintenance).Post("/{name}/nodes/{nodeId}/test", s.testWorkflowNode)
+			r.Post("/{name}/resolve-templates", s.resolveWorkflowTemplates)
 			r.Post("/{name}/thumbnail", s.generateWorkflowThumbnail)
 			r.Get("/{name}/runs", s.listWorkflowRuns)
+			r.Put("/{name}/baseline", s.setWorkflowBaseline)
+			r.Delete("/{name}/baseline", s.clearWorkflowBaseline)
+			r.Put("/{name}/webhook-secret", s.setWorkflowWebhookSecret)
+			r.Delete("/{name}/webhook-secret", s.clearWorkflowWebhookSecret)
+			r.With(s.rejectInMaintenance).Post("/{name}/regression-check", s.regressionCheck)
 			r.Get("/{name}/triggers", s.listTriggers)
 		})
 		r.Route("/runs", func(r chi.Router) {
 			r.Get("/", s.listRuns)
+			r.Get("/export", s.exportRuns)
+			r.Get("/active", s.listActiveRuns)
+			r.Get("/stream", s.streamRunList)
 			r.Get("/{id}", s.getRun)
 			r.Get("/{id}/events", s.streamRunEvents)
+			r.Get("/{id}/artifacts", s.listRunArtifacts)
+			r.Get("/{id}/artifacts/{name}", s.getRunArtifact)
+			r.Post("/{id}/cancel", s.cancelRun)
 			r.Post("/{id}/nodes/{nodeId}/resume", s.resumeNode)
+			r.With(s.rejectInMaintenance).Post("/{id}/rerun", s.rerunRun)
+			r.With(s.rejectInMaintenance).Post("/{id}/resume", s.resumeRun)
 		})
+		r.Get("/audit", s.listAudit)
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(s.requireAdmin)
+			if s.maintenanceSvc != nil {
+				r.Get("/maintenance", s.getMaintenance)
+				r.Post("/maintenance", s.setMaintenance)
+			}
+			if s.logLevel != nil {
+				r.Get("/log-level", s.getLogLevel)
+				r.Post("/log-level", s.setLogLevel)
+			}
+			r.Post("/backfill-descriptions", s.adminBackfillDescriptions)
+		})
+		if s.schedulerSvc != nil {
+			r.Route("/schedules", func(r chi.Router) {
+				r.Post("/{id}/copy", s.copySchedule)
+			})
+		}
 		r.Route("/triggers", func(r chi.Router) {
 			r.Post("/", s.createTrigger)
 			r.Delete("/{id}", s.deleteTrigger)
+			r.With(s.rejectInMaintenance).Post("/{id}/replay-failed", s.replayFailedDeliveries)
+			if s.webhookCfg.FireN {
+				r.With(s.requireAdmin, s.rejectInMaintenance).Post("/{id}/fire-n", s.fireTriggerN)
+			}
 		})
 		r.Route("/pipelines", func(r chi.Router) {
 			r.Post("/", s.createPipeline)
 			r.Get("/", s.listPipelines)
+			r.Post("/validate", s.validatePipeline)
 			r.Get("/{id}", s.getPipeline)
 			r.Put("/{id}", s.updatePipeline)
 			r.Delete("/{id}", s.deletePipeline)
-			r.Post("/{id}/start", s.startPipeline)
+			r.With(s.rejectInMaintenance).Post("/{id}/start", s.startPipeline)
 			r.Get("/{id}/runs", s.listPipelineRuns)
 			r.Post("/{id}/runs/{runId}/approve", s.approvePipelineRun)
 			r.Post("/{id}/runs/{runId}/reject", s.rejectPipelineRun)
@@ -137,7 +208,7 @@ func (s *Server) Handler() http.Handler {
 				r.Delete("/{id}", s.deleteContentSession)
 				r.Patch("/{id}/settings", s.patchSessionSettings)
 				r.Post("/{id}/collect", s.collectSession)
-				r.Post("/{id}/run", s.runSessionInstance)
+				r.With(s.rejectInMaintenance).Post("/{id}/run", s.runSessionInstance)
 				r.Post("/{id}/activate", s.activateSession)
 				r.Post("/{id}/deactivate", s.deactivateSession)
 				r.Post("/{id}/produce", s.produceContentSession)
@@ -159,11 +230,13 @@ func (s *Server) Handler() http.Handler {
 				r.Post("/{id}/create-session", s.createSessionFromSurge)
 			})
 		}
-		r.Post("/hooks/{id}", s.handleWebhook)
+		r.With(s.rejectInMaintenance).Post("/hooks/{id}", s.handleWebhook)
+		r.Get("/search", s.search)
+		r.Post("/retry-policy/preview", s.previewRetryPolicy)
 		r.Post("/generate", s.generateWorkflow)
 		r.Get("/generate/{id}", s.getGeneration)
 		r.Post("/generate-pipeline", s.generatePipeline)
-		r.Post("/generate/backfill", s.backfillDescriptions)
+		r.With(s.requireAdmin).Post("/generate/backfill", s.adminBackfillDescriptions) // alias of /admin/backfill-descriptions
 		r.Post("/nodes/configure", s.configureNode)
 		if s.chatHandler != nil {
 			r.Post("/chat", s.chatHandler.ServeHTTP)
@@ -173,7 +246,15 @@ func (s *Server) Handler() http.Handler {
 		r.Get("/files/{id}/serve", s.serveFile)
 		r.Delete("/files/{id}", s.deleteFile)
 		r.Get("/models", s.listModels)
+		r.Get("/providers", s.listProviders)
+		r.Get("/providers/status", s.getProviderStatus)
+		if s.warmer != nil {
+			r.Post("/providers/{name}/warm", s.warmProvider)
+		}
 		r.Get("/tools", s.listAvailableTools)
+		r.Get("/tools/stats", s.getToolStats)
+		r.Post("/tools/openapi", s.importOpenAPITools)
+		r.Delete("/tools/openapi/{namespace}", s.deleteOpenAPITools)
 		if s.connectionSvc != nil {
 			r.Route("/connections", func(r chi.Router) {
 				r.Post("/", s.createConnection)
@@ -192,6 +273,15 @@ func (s *Server) Handler() http.Handler {
 				r.Delete("/{id}", s.deletePublishChannel)
 			})
 		}
+		if s.eventSubRepo != nil {
+			r.Route("/event-subscriptions", func(r chi.Router) {
+				r.Post("/", s.createEventSubscription)
+				r.Get("/", s.listEventSubscriptions)
+				r.Get("/{id}", s.getEventSubscription)
+				r.Put("/{id}", s.updateEventSubscription)
+				r.Delete("/{id}", s.deleteEventSubscription)
+			})
+		}
 		if s.aiProviderSvc != nil {
 			r.Route("/ai-providers", func(r chi.Router) {
 				r.Post("/", s.createAIProvider)
@@ -247,6 +337,9 @@ func (s *Server) Handler() http.Handler {
 	// Serve static files (frontend)
 	r.Handle("/*", StaticHandler("web/dist"))
 
+	if s.basePath != "" {
+		return s.mountAtBasePath(r)
+	}
 	return r
 }
 
@@ -268,6 +361,15 @@ func (s *Server) SetRetryExecutor(executor ports.RetryExecutor)   { s.retryExecu
 func (s *Server) SetTriggerRepository(repo repository.TriggerRepository) { s.triggerRepo = repo }
 func (s *Server) SetConnectionService(svc ports.ConnectionPort)   { s.connectionSvc = svc }
 func (s *Server) SetPublishChannelRepo(repo repository.PublishChannelRepository) { s.publishChannelRepo = repo }
+func (s *Server) SetEventSubscriptionRepo(repo repository.EventSubscriptionRepository) {
+	s.eventSubRepo = repo
+}
+
+// SetOpenAPIImportRepo enables storing imported OpenAPI toolsets; see
+// RestoreOpenAPITools.
+func (s *Server) SetOpenAPIImportRepo(repo repository.OpenAPIImportRepository) {
+	s.openAPIImports = repo
+}
 func (s *Server) SetExecutionRegistry(reg ports.ExecutionRegistryPort) { s.executionReg = reg }
 func (s *Server) SetRunManager(rm ports.RunManagerPort)           { s.runManager = rm }
 func (s *Server) SetRunPublisher(pub *runpub.RunPublisher)        { s.runPublisher = pub }
@@ -281,13 +383,22 @@ func (s *Server) SetAuthService(svc *services.AuthService)             { s.authS
 func (s *Server) SetFrontendURL(url string)                            { s.frontendURL = url }
 func (s *Server) SetSessionService(svc *services.SessionService)       { s.sessionSvc = svc }
 func (s *Server) SetRunService(svc *services.RunService)               { s.runSvc = svc }
+func (s *Server) SetSearchService(svc *services.SearchService)         { s.searchSvc = svc }
+
+func (s *Server) SetWebhookConfig(cfg config.WebhookConfig) { s.webhookCfg = cfg }
 
 func (s *Server) SetChatHandler(h *chat.Handler) { s.chatHandler = h }
 
+// SetWorkflowLimits bounds the size of workflows accepted on create and update.
+func (s *Server) SetWorkflowLimits(l upal.WorkflowLimits) { s.workflowLimits = l }
+
 func (s *Server) SetServerConfig(cfg config.ServerConfig, genCfg config.GeneratorConfig) {
 	s.thumbnailTimeout = genCfg.ThumbnailTimeout
 	s.uploadMaxSize = cfg.UploadMaxSize
 	s.corsOrigins = cfg.CORSOrigins
+	s.publicURL = cfg.PublicURL
+	s.basePath = cfg.BasePathPrefix()
+	s.requestTimeouts = cfg.Timeouts
 }
 
 func (s *Server) allowOrigin(_ *http.Request, origin string) bool {
@@ -330,3 +441,13 @@ func (s *Server) listAvailableTools(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result)
 }
 
+// getToolStats handles GET /api/tools/stats: per-tool call counts, errors,
+// throttled calls and latency since startup.
+func (s *Server) getToolStats(w http.ResponseWriter, r *http.Request) {
+	var result []tools.ToolStats
+	if s.toolReg != nil {
+		result = s.toolReg.Stats()
+	}
+	writeJSON(w, orEmpty(result))
+}
+
@@ -66,3 +66,149 @@ func TestAPI_GetWorkflow(t *testing.T) {
 		t.Fatalf("status: got %d, want 200", w.Code)
 	}
 }
+
+func TestAPI_PatchWorkflow(t *testing.T) {
+	seed := upal.WorkflowDefinition{
+		Name:    "patch-wf",
+		Version: 1,
+		Nodes: []upal.NodeDefinition{
+			{ID: "in", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "agent", Type: upal.NodeTypeAgent, Config: map[string]any{"prompt": "old", "model": "anthropic/claude"}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{
+			{From: "in", To: "agent"},
+			{From: "agent", To: "out"},
+			{From: "in", To: "out"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		delta    string
+		wantCode int
+		check    func(t *testing.T, wf upal.WorkflowDefinition)
+	}{
+		{
+			name:     "add node",
+			delta:    `{"node_changes":[{"op":"add","node":{"id":"extra","type":"agent","config":{"prompt":"hi","model":"anthropic/claude"}}}],"edge_changes":[{"op":"add","edge":{"from":"agent","to":"extra"}}]}`,
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, wf upal.WorkflowDefinition) {
+				if len(wf.Nodes) != 4 || wf.Nodes[3].ID != "extra" {
+					t.Errorf("nodes: got %+v", wf.Nodes)
+				}
+				if len(wf.Edges) != 4 {
+					t.Errorf("edges: got %d, want 4", len(wf.Edges))
+				}
+			},
+		},
+		{
+			name:     "update config field",
+			delta:    `{"node_changes":[{"op":"set_config","node_id":"agent","config":{"prompt":"new","model":"anthropic/claude"}}]}`,
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, wf upal.WorkflowDefinition) {
+				cfg := wf.Nodes[1].Config
+				if cfg["prompt"] != "new" || cfg["model"] != "anthropic/claude" {
+					t.Errorf("config: got %v", cfg)
+				}
+			},
+		},
+		{
+			name:     "remove edge",
+			delta:    `{"edge_changes":[{"op":"remove","edge":{"from":"in","to":"out"}}]}`,
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, wf upal.WorkflowDefinition) {
+				for _, e := range wf.Edges {
+					if e.From == "in" && e.To == "out" {
+						t.Errorf("edge in -> out still present")
+					}
+				}
+			},
+		},
+		{
+			name:     "orphan output node",
+			delta:    `{"node_changes":[{"op":"remove","node_id":"agent"}],"edge_changes":[{"op":"remove","edge":{"from":"in","to":"out"}}]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown node",
+			delta:    `{"node_changes":[{"op":"set_config","node_id":"ghost","config":{"x":1}}]}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer()
+			body, _ := json.Marshal(seed)
+			req := httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			srv.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+			req = httptest.NewRequest("PATCH", "/api/workflows/patch-wf", bytes.NewReader([]byte(tt.delta)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Fatalf("status: got %d, want %d; body: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.check == nil {
+				return
+			}
+
+			// The merged definition must be persisted.
+			req = httptest.NewRequest("GET", "/api/workflows/patch-wf", nil)
+			w = httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, req)
+			var got upal.WorkflowDefinition
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestAPI_CreateWorkflowValidationIssues(t *testing.T) {
+	srv := newTestServer()
+	wf := upal.WorkflowDefinition{
+		Name: "invalid-wf",
+		Nodes: []upal.NodeDefinition{
+			{ID: "in", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "agent", Type: upal.NodeTypeAgent, Config: map[string]any{"prompt": "hi"}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{
+			{From: "in", To: "agent"},
+			{From: "agent", To: "ghost"},
+		},
+	}
+	want := []upal.ValidationIssue{
+		{Path: "nodes[1].config.model", Message: "required"},
+		{Path: "edges[1].to", Message: `unknown node "ghost"`},
+	}
+	body, _ := json.Marshal(wf)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body)),
+		httptest.NewRequest("PUT", "/api/workflows/invalid-wf", bytes.NewReader(body)),
+	} {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("%s: status: got %d, want 422; body: %s", req.Method, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Error  string                 `json:"error"`
+			Issues []upal.ValidationIssue `json:"issues"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: decode: %v", req.Method, err)
+		}
+		if len(resp.Issues) != len(want) || resp.Issues[0] != want[0] || resp.Issues[1] != want[1] {
+			t.Errorf("%s: issues: got %+v, want %+v", req.Method, resp.Issues, want)
+		}
+		if wantErr := `invalid workflow: nodes[1].config.model: required; edges[1].to: unknown node "ghost"`; resp.Error != wantErr {
+			t.Errorf("%s: error: got %q, want %q", req.Method, resp.Error, wantErr)
+		}
+	}
+}
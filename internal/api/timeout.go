@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// longRequestSegments are the final path segments of /api endpoints that do
+// model or workflow work before responding; they get the long timeout.
+var longRequestSegments = map[string]bool{
+	"generate":              true,
+	"generate-pipeline":     true,
+	"generate-workflow":     true,
+	"backfill":              true,
+	"backfill-descriptions": true,
+	"suggest":               true,
+	"suggest-name":          true,
+	"regression-check":      true,
+	"run":                   true,
+	"test":                  true,
+	"thumbnail":             true,
+	"configure":             true,
+	"collect":               true,
+	"produce":               true,
+	"publish":               true,
+	"retry-analyze":         true,
+	"warm":                  true,
+	"upload":                true,
+	"openapi":               true,
+}
+
+// timeoutFor returns the timeout for an /api request, or zero when it is
+// exempt. Event streams, chat, workflow previews, NDJSON exports and file
+// downloads are exempt because they legitimately stay open and must not be
+// buffered.
+func (s *Server) timeoutFor(r *http.Request) time.Duration {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	last := path[strings.LastIndex(path, "/")+1:]
+	switch {
+	case last == "events", last == "stream", path == "/api/chat",
+		last == "preview" && strings.HasPrefix(path, "/api/workflows/"), last == "export", last == "serve", strings.Contains(path, "/artifacts/"),
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream"),
+		r.URL.Query().Get("format") == "ndjson":
+		return 0
+	case longRequestSegments[last], strings.HasPrefix(path, "/api/hooks/"):
+		return s.requestTimeouts.Long
+	}
+	return s.requestTimeouts.Default
+}
+
+// requestTimeout bounds each API request by timeoutFor. The handler runs with
+// a context that is cancelled at the deadline, and its response is buffered;
+// on overrun the client gets 504 and whatever the handler writes afterwards
+// is discarded. Work the handler detached from the request context, such as
+// background runs, carries on.
+func (s *Server) requestTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := s.timeoutFor(r)
+		if d <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		tw := &timeoutWriter{header: make(http.Header)}
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			next.ServeHTTP(tw, r)
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			dst := w.Header()
+			for k, v := range tw.header {
+				dst[k] = v
+			}
+			if tw.code == 0 {
+				tw.code = http.StatusOK
+			}
+			w.WriteHeader(tw.code)
+			w.Write(tw.buf.Bytes())
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.timedOut = true
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				slog.WarnContext(ctx, "request timed out", "method", r.Method, "path", r.URL.Path, "timeout", d)
+				http.Error(w, "request timed out", http.StatusGatewayTimeout)
+			}
+		}
+	})
+}
+
+// timeoutWriter buffers a handler's response until it finishes, so a timeout
+// can still replace it with a 504.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header { return tw.header }
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/chat"
+	"github.com/soochol/upal/internal/config"
+	adkmodel "google.golang.org/adk/model"
+)
+
+func TestRequestTimeout_SlowHandlerCutOffRunContinues(t *testing.T) {
+	srv := newTestServer()
+	srv.requestTimeouts = config.RequestTimeoutConfig{Default: time.Second, Long: 20 * time.Millisecond}
+
+	runDone := make(chan struct{})
+	handlerErr := make(chan error, 1)
+	slow := srv.requestTimeout(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Like runWorkflow, the run itself is detached from the request.
+		go func() {
+			time.Sleep(60 * time.Millisecond)
+			close(runDone)
+		}()
+		<-r.Context().Done()
+		handlerErr <- r.Context().Err()
+		w.Write([]byte("too late"))
+	}))
+
+	req := httptest.NewRequest("POST", "/api/workflows/wf/run", nil)
+	w := httptest.NewRecorder()
+	start := time.Now()
+	slow.ServeHTTP(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d: %s", w.Code, w.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request took %v; the long timeout was not applied", elapsed)
+	}
+	if err := <-handlerErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("handler context error = %v", err)
+	}
+	select {
+	case <-runDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("background run did not complete after the request timed out")
+	}
+	if w.Body.String() != "request timed out\n" {
+		t.Errorf("late write leaked into the response: %q", w.Body.String())
+	}
+}
+
+func TestRequestTimeout_FastHandlerPassesThrough(t *testing.T) {
+	srv := newTestServer()
+	srv.requestTimeouts = config.RequestTimeoutConfig{Default: time.Second}
+
+	h := srv.requestTimeout(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		writeJSONStatus(w, http.StatusCreated, map[string]string{"ok": "true"})
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows", nil))
+	if w.Code != http.StatusCreated || w.Header().Get("X-Test") != "yes" || w.Body.String() != "{\"ok\":\"true\"}\n" {
+		t.Errorf("unexpected response: %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+}
+
+func TestRequestTimeout_PerRoute(t *testing.T) {
+	srv := newTestServer()
+	srv.requestTimeouts = config.RequestTimeoutConfig{Default: time.Second, Long: time.Minute}
+
+	for path, want := range map[string]time.Duration{
+		"/api/workflows":                       time.Second,
+		"/api/workflows/wf":                    time.Second,
+		"/api/generate":                        time.Minute,
+		"/api/workflows/wf/nodes/n1/test":      time.Minute,
+		"/api/hooks/trig_1":                    time.Minute,
+		"/api/runs/r1/events":                  0,
+		"/api/runs/stream":                     0,
+		"/api/workflows/wf/preview":            0,
+		"/api/chat":                            0,
+		"/api/retry-policy/preview":            time.Second,
+		"/api/runs/r1/artifacts/report":        0,
+		"/api/files/f1/serve":                  0,
+		"/api/generate/backfill?format=ndjson": 0,
+		"/api/admin/backfill-descriptions":     time.Minute,
+	} {
+		if got := srv.timeoutFor(httptest.NewRequest("GET", path, nil)); got != want {
+			t.Errorf("%s: timeout = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestRequestTimeout_ChatStreamsWithDefaultTimeouts(t *testing.T) {
+	srv := newTestServer()
+	srv.requestTimeouts = config.RequestTimeoutConfig{Default: 30 * time.Second, Long: 10 * time.Minute}
+	llm := &describeLLM{}
+	srv.SetChatHandler(chat.NewHandler(chat.NewRegistry(), noopSkills{}, nil, func(context.Context) (adkmodel.LLM, string, error) {
+		return llm, "m", nil
+	}))
+
+	// Like the web client, the request does not ask for text/event-stream.
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/chat", strings.NewReader(`{"message":"hi","page":"workflows"}`))
+	req.Header.Set("Content-Type", "application/json")
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("chat: got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	if !w.Flushed {
+		t.Error("chat response was not flushed")
+	}
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/tools"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// maxOpenAPISpecUpload caps uploaded OpenAPI documents.
+const maxOpenAPISpecUpload = 5 << 20
+
+// OpenAPIImportRequest is the JSON body of POST /api/tools/openapi. Exactly
+// one of URL or Spec (the document text, JSON or YAML) is required; BaseURL
+// overrides the spec's first server.
+type OpenAPIImportRequest struct {
+	Namespace    string `json:"namespace"`
+	URL          string `json:"url,omitempty"`
+	Spec         string `json:"spec,omitempty"`
+	BaseURL      string `json:"base_url,omitempty"`
+	ConnectionID string `json:"connection_id,omitempty"`
+}
+
+// OpenAPIImportResponse lists the tools generated for a namespace.
+type OpenAPIImportResponse struct {
+	Namespace string           `json:"namespace"`
+	Title     string           `json:"title,omitempty"`
+	BaseURL   string           `json:"base_url"`
+	Tools     []tools.ToolInfo `json:"tools"`
+}
+
+// importOpenAPITools handles POST /api/tools/openapi. It accepts either the
+// JSON OpenAPIImportRequest or a multipart upload with the document in the
+// "spec" file field and the other request fields as form values, and
+// registers one tool per operation under the namespace, replacing any tools
+// previously imported there. Names already taken outside the namespace are
+// refused with 409, and a namespace imported by another user with 403. The
+// import is stored, owned by the caller, so RestoreOpenAPITools can register
+// it again after a restart.
+func (s *Server) importOpenAPITools(w http.ResponseWriter, r *http.Request) {
+	if s.toolReg == nil {
+		http.Error(w, "tool registry not configured", http.StatusServiceUnavailable)
+		return
+	}
+	var req OpenAPIImportRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, maxOpenAPISpecUpload+1<<20)
+		if err := r.ParseMultipartForm(maxOpenAPISpecUpload); err != nil {
+			http.Error(w, "spec too large (max 5MB)", http.StatusBadRequest)
+			return
+		}
+		req.Namespace = r.FormValue("namespace")
+		req.BaseURL = r.FormValue("base_url")
+		req.ConnectionID = r.FormValue("connection_id")
+		file, _, err := r.FormFile("spec")
+		if err != nil {
+			http.Error(w, "missing spec field", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			http.Error(w, "failed to read spec", http.StatusBadRequest)
+			return
+		}
+		req.Spec = string(data)
+	} else if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if (req.URL == "") == (req.Spec == "") {
+		http.Error(w, "exactly one of url or spec is required", http.StatusBadRequest)
+		return
+	}
+	if !s.ownsOpenAPINamespace(r.Context(), req.Namespace) {
+		http.Error(w, "namespace is owned by another user", http.StatusForbidden)
+		return
+	}
+	if req.ConnectionID != "" {
+		if s.connectionSvc == nil {
+			http.Error(w, "connections are not configured", http.StatusBadRequest)
+			return
+		}
+		if _, err := s.connectionSvc.Get(r.Context(), req.ConnectionID); err != nil {
+			http.Error(w, "connection not found: "+req.ConnectionID, http.StatusBadRequest)
+			return
+		}
+	}
+
+	spec := []byte(req.Spec)
+	if req.URL != "" {
+		var err error
+		if spec, err = tools.LoadOpenAPISpec(r.Context(), req.URL); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+	imp := &upal.OpenAPIImport{
+		Namespace:    req.Namespace,
+		Spec:         string(spec),
+		BaseURL:      req.BaseURL,
+		ConnectionID: req.ConnectionID,
+		UserID:       upal.UserIDFromContext(r.Context()),
+		CreatedAt:    time.Now(),
+	}
+	set, err := s.openAPIToolset(imp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	generated := set.Tools()
+	if err := s.toolReg.RegisterNamespace(set.Namespace, generated); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if s.openAPIImports != nil {
+		if err := s.openAPIImports.Save(r.Context(), imp); err != nil {
+			slog.WarnContext(r.Context(), "failed to save openapi import", "namespace", imp.Namespace, "err", err)
+		}
+	}
+	resp := OpenAPIImportResponse{Namespace: set.Namespace, Title: set.Title, BaseURL: set.BaseURL}
+	for _, t := range generated {
+		resp.Tools = append(resp.Tools, tools.ToolInfo{Name: t.Name(), Description: t.Description()})
+	}
+	writeJSONStatus(w, http.StatusCreated, resp)
+}
+
+// openAPIToolset builds the tools of an import. BaseURL, when set, overrides
+// the spec's first server, and the result must be absolute.
+func (s *Server) openAPIToolset(imp *upal.OpenAPIImport) (*tools.OpenAPIToolset, error) {
+	set, err := tools.NewOpenAPIToolset(imp.Namespace, []byte(imp.Spec))
+	if err != nil {
+		return nil, err
+	}
+	if imp.BaseURL != "" {
+		set.BaseURL = imp.BaseURL
+	}
+	if !strings.HasPrefix(set.BaseURL, "http://") && !strings.HasPrefix(set.BaseURL, "https://") {
+		return nil, fmt.Errorf("spec has no absolute server url; pass base_url")
+	}
+	set.ConnectionID = imp.ConnectionID
+	set.Owner = imp.UserID
+	if s.connectionSvc != nil {
+		set.SetConnectionResolver(s.connectionSvc)
+	}
+	return set, nil
+}
+
+// RestoreOpenAPITools registers the tools of every stored OpenAPI import.
+// An import that no longer builds or registers is logged and skipped.
+func (s *Server) RestoreOpenAPITools(ctx context.Context) {
+	if s.toolReg == nil || s.openAPIImports == nil {
+		return
+	}
+	imports, err := s.openAPIImports.List(ctx)
+	if err != nil {
+		slog.Warn("failed to list openapi imports", "err", err)
+		return
+	}
+	for _, imp := range imports {
+		set, err := s.openAPIToolset(imp)
+		if err == nil {
+			err = s.toolReg.RegisterNamespace(set.Namespace, set.Tools())
+		}
+		if err != nil {
+			slog.Warn("failed to restore openapi tools", "namespace", imp.Namespace, "err", err)
+		}
+	}
+}
+
+// ownsOpenAPINamespace reports whether the caller may replace or delete the
+// namespace: it is new, was imported by the caller, or predates ownership.
+func (s *Server) ownsOpenAPINamespace(ctx context.Context, namespace string) bool {
+	if s.openAPIImports == nil {
+		return true
+	}
+	imp, err := s.openAPIImports.Get(ctx, namespace)
+	return err != nil || imp.UserID == "" || imp.UserID == upal.UserIDFromContext(ctx)
+}
+
+// deleteOpenAPITools handles DELETE /api/tools/openapi/{namespace}. Only the
+// user who imported the namespace may delete it.
+func (s *Server) deleteOpenAPITools(w http.ResponseWriter, r *http.Request) {
+	ns := chi.URLParam(r, "namespace")
+	if !s.ownsOpenAPINamespace(r.Context(), ns) {
+		http.Error(w, "namespace is owned by another user", http.StatusForbidden)
+		return
+	}
+	if s.toolReg == nil || !s.toolReg.UnregisterNamespace(ns) {
+		http.Error(w, "namespace not found", http.StatusNotFound)
+		return
+	}
+	if s.openAPIImports != nil {
+		if err := s.openAPIImports.Delete(r.Context(), ns); err != nil {
+			slog.WarnContext(r.Context(), "failed to delete openapi import", "namespace", ns, "err", err)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/tools"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type pingTool struct{}
+
+func (pingTool) Name() string                { return "ping" }
+func (pingTool) Description() string         { return "Replies pong" }
+func (pingTool) InputSchema() map[string]any { return map[string]any{"type": "object"} }
+func (pingTool) Execute(context.Context, any) (any, error) {
+	return "pong", nil
+}
+
+func TestGetToolStats(t *testing.T) {
+	srv := newTestServer()
+	srv.toolReg = tools.NewRegistry()
+	srv.toolReg.Register(pingTool{})
+	srv.toolReg.Execute(context.Background(), "ping", nil)
+	srv.toolReg.Execute(context.Background(), "ping", nil)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/tools/stats", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var stats []tools.ToolStats
+	json.Unmarshal(w.Body.Bytes(), &stats)
+	if len(stats) != 1 || stats[0].Name != "ping" || stats[0].Calls != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestImportOpenAPITools(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	srv := newTestServer()
+	srv.toolReg = tools.NewRegistry()
+	spec := `{"openapi": "3.0.0", "info": {"title": "Echo"}, "paths": {"/echo/{id}": {"get": {"operationId": "echo", "parameters": [{"name": "id", "in": "path"}]}}}}`
+
+	importBody := func(fields map[string]any) string {
+		b, _ := json.Marshal(fields)
+		return string(b)
+	}
+
+	w := doJSON(srv, "POST", "/api/tools/openapi", importBody(map[string]any{"namespace": "svc", "spec": spec}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("missing base url: expected 400, got %d", w.Code)
+	}
+
+	w = doJSON(srv, "POST", "/api/tools/openapi", importBody(map[string]any{"namespace": "svc", "spec": spec, "base_url": upstream.URL}))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp OpenAPIImportResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Title != "Echo" || len(resp.Tools) != 1 || resp.Tools[0].Name != "svc_echo" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	out, err := srv.toolReg.Execute(context.Background(), "svc_echo", map[string]any{"id": "7"})
+	if err != nil || out.(map[string]any)["body"] != "/echo/7" {
+		t.Errorf("execute = %v, %v", out, err)
+	}
+
+	w = doJSON(srv, "DELETE", "/api/tools/openapi/svc", "")
+	if w.Code != http.StatusNoContent {
+		t.Errorf("delete: expected 204, got %d", w.Code)
+	}
+	if _, ok := srv.toolReg.Get("svc_echo"); ok {
+		t.Error("tool still registered after delete")
+	}
+}
+
+func TestImportOpenAPITools_RestoredAfterRestart(t *testing.T) {
+	imports := repository.NewMemoryOpenAPIImportRepository()
+	spec := `{"openapi": "3.0.0", "paths": {"/ping": {"get": {"operationId": "ping"}}}}`
+
+	srv := newTestServer()
+	srv.toolReg = tools.NewRegistry()
+	srv.SetOpenAPIImportRepo(imports)
+	body, _ := json.Marshal(map[string]any{"namespace": "svc", "spec": spec, "base_url": "https://api.example.com"})
+	if w := doJSON(srv, "POST", "/api/tools/openapi", string(body)); w.Code != http.StatusCreated {
+		t.Fatalf("import: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	restarted := newTestServer()
+	restarted.toolReg = tools.NewRegistry()
+	restarted.SetOpenAPIImportRepo(imports)
+	restarted.RestoreOpenAPITools(context.Background())
+	if _, ok := restarted.toolReg.Get("svc_ping"); !ok {
+		t.Fatal("imported tool not registered after restart")
+	}
+
+	if w := doJSON(restarted, "DELETE", "/api/tools/openapi/svc", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("delete: expected 204, got %d", w.Code)
+	}
+	if stored, _ := imports.List(context.Background()); len(stored) != 0 {
+		t.Errorf("delete left %d stored imports", len(stored))
+	}
+}
+
+func TestImportOpenAPITools_NameCollision(t *testing.T) {
+	srv := newTestServer()
+	srv.toolReg = tools.NewRegistry()
+	imports := repository.NewMemoryOpenAPIImportRepository()
+	srv.SetOpenAPIImportRepo(imports)
+
+	// Namespaces are prefixed, so "svc" yields "svc_ping"; a built-in with
+	// that exact name must not be replaced.
+	srv.toolReg.Register(renamedTool{pingTool{}, "svc_ping"})
+	spec := `{"openapi": "3.0.0", "paths": {"/ping": {"get": {"operationId": "ping"}}}}`
+	body, _ := json.Marshal(map[string]any{"namespace": "svc", "spec": spec, "base_url": "https://api.example.com"})
+	if w := doJSON(srv, "POST", "/api/tools/openapi", string(body)); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if out, _ := srv.toolReg.Execute(context.Background(), "svc_ping", nil); out != "pong" {
+		t.Errorf("built-in tool was replaced: got %v", out)
+	}
+	if stored, _ := imports.List(context.Background()); len(stored) != 0 {
+		t.Errorf("refused import was stored")
+	}
+}
+
+func TestImportOpenAPITools_OwnedByImporter(t *testing.T) {
+	srv := newTestServer()
+	srv.toolReg = tools.NewRegistry()
+	imports := repository.NewMemoryOpenAPIImportRepository()
+	srv.SetOpenAPIImportRepo(imports)
+	srv.SetAuthService(newTestAuthService())
+
+	as := func(userID, method, path, body string) int {
+		token, _, err := srv.authSvc.GenerateTokens(context.Background(), &upal.User{ID: userID}, "")
+		if err != nil {
+			t.Fatalf("GenerateTokens: %v", err)
+		}
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w.Code
+	}
+	spec := `{"openapi": "3.0.0", "paths": {"/ping": {"get": {"operationId": "ping"}}}}`
+	body, _ := json.Marshal(map[string]any{"namespace": "svc", "spec": spec, "base_url": "https://api.example.com"})
+
+	if code := as("user-a", "POST", "/api/tools/openapi", string(body)); code != http.StatusCreated {
+		t.Fatalf("import: got %d", code)
+	}
+	if stored, _ := imports.Get(context.Background(), "svc"); stored == nil || stored.UserID != "user-a" {
+		t.Fatalf("stored import = %+v, want owner user-a", stored)
+	}
+
+	// Another user can neither replace nor delete the namespace.
+	other, _ := json.Marshal(map[string]any{"namespace": "svc", "spec": spec, "base_url": "https://evil.example.com"})
+	if code := as("user-b", "POST", "/api/tools/openapi", string(other)); code != http.StatusForbidden {
+		t.Errorf("re-import by another user: got %d, want 403", code)
+	}
+	if code := as("user-b", "DELETE", "/api/tools/openapi/svc", ""); code != http.StatusForbidden {
+		t.Errorf("delete by another user: got %d, want 403", code)
+	}
+	if stored, _ := imports.Get(context.Background(), "svc"); stored == nil || stored.BaseURL != "https://api.example.com" {
+		t.Errorf("import changed by another user: %+v", stored)
+	}
+	if _, ok := srv.toolReg.Get("svc_ping"); !ok {
+		t.Error("tool unregistered by another user")
+	}
+
+	if code := as("user-a", "POST", "/api/tools/openapi", string(body)); code != http.StatusCreated {
+		t.Errorf("re-import by owner: got %d", code)
+	}
+	if code := as("user-a", "DELETE", "/api/tools/openapi/svc", ""); code != http.StatusNoContent {
+		t.Errorf("delete by owner: got %d", code)
+	}
+}
+
+type renamedTool struct {
+	tools.Tool
+	name string
+}
+
+func (t renamedTool) Name() string { return t.name }
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/config"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// maxFireCount bounds a single fire-n request.
+const maxFireCount = 1000
+
+// FireNRequest asks for a trigger to be fired Count times. Payload is a
+// template rendered per fire with {{i}} (0-based index), {{n}} (Count) and
+// {{now}}; it is then decoded like a webhook body. Wait blocks the response
+// until every started run has finished.
+type FireNRequest struct {
+	Count   int    `json:"count"`
+	Payload string `json:"payload,omitempty"`
+	Wait    bool   `json:"wait,omitempty"`
+}
+
+// FireResult is the outcome of one fire. Status is "started" or "rejected"
+// (concurrency limit reached). AcceptMs is how long the fire took to be
+// accepted; DurationMs, reported only with Wait, is the run's wall time.
+type FireResult struct {
+	Index      int     `json:"index"`
+	RunID      string  `json:"run_id,omitempty"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	RetryAfter int     `json:"retry_after,omitempty"`
+	AcceptMs   float64 `json:"accept_ms"`
+	DurationMs float64 `json:"duration_ms,omitempty"`
+}
+
+// FireNResponse summarises a fire-n request.
+type FireNResponse struct {
+	Trigger  string       `json:"trigger"`
+	Started  int          `json:"started"`
+	Rejected int          `json:"rejected"`
+	TotalMs  float64      `json:"total_ms"`
+	Fires    []FireResult `json:"fires"`
+}
+
+// fireTriggerN fires a workflow trigger repeatedly for load testing. Each
+// fire goes through the same concurrency limiter as a real webhook call, so
+// rejections reflect production behavior; their Retry-After is what a real
+// caller would be told, but load tests neither add to nor clear the backoff
+// real callers see. The route is only mounted when webhooks.fire_n is
+// enabled, and only admins may use it.
+func (s *Server) fireTriggerN(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if s.triggerRepo == nil {
+		http.Error(w, "triggers not available", http.StatusServiceUnavailable)
+		return
+	}
+	if s.runHistorySvc == nil || s.runManager == nil || s.runPublisher == nil {
+		http.Error(w, "run history not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req FireNRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Count < 1 || req.Count > maxFireCount {
+		http.Error(w, fmt.Sprintf("count must be between 1 and %d", maxFireCount), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := s.triggerRepo.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	}
+	if !trigger.Enabled {
+		http.Error(w, "trigger is disabled", http.StatusForbidden)
+		return
+	}
+	if trigger.PipelineID != "" {
+		http.Error(w, "fire-n supports workflow triggers only", http.StatusBadRequest)
+		return
+	}
+	wf, err := s.workflowSvc.Lookup(r.Context(), trigger.WorkflowName)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+
+	resp := FireNResponse{Trigger: id, Fires: make([]FireResult, req.Count)}
+	durations := make([]float64, req.Count) // written by run goroutines; read after wg.Wait
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range req.Count {
+		fireStart := time.Now()
+		res := &resp.Fires[i]
+		res.Index = i
+
+		body := agents.ResolveTemplate(req.Payload, map[string]any{
+			"i": strconv.Itoa(i),
+			"n": strconv.Itoa(req.Count),
+		})
+		inputs := mapInputs(parseWebhookPayload("application/json", trigger.Config.PayloadFormat, []byte(body), s.floatNumbers), trigger.Config.InputMapping)
+
+		slotHeld := false
+		if s.limiter != nil && s.webhookCfg.OnSaturation != config.WebhookSaturationQueue {
+			if !s.limiter.TryAcquire(wf.Name) {
+				res.Status = "rejected"
+				res.RetryAfter = int(s.webhookBackoff.peek(trigger.ID) / time.Second)
+				res.AcceptMs = msSince(fireStart)
+				resp.Rejected++
+				continue
+			}
+			slotHeld = true
+		}
+
+		record, err := s.runHistorySvc.StartRun(r.Context(), wf.Name, string(upal.TriggerWebhook), trigger.ID, inputs, wf)
+		if err != nil {
+			if slotHeld {
+				s.limiter.Release(wf.Name)
+			}
+			res.Status = "failed"
+			res.Error = err.Error()
+			res.AcceptMs = msSince(fireStart)
+			continue
+		}
+		res.RunID = record.ID
+		res.Status = "started"
+		res.AcceptMs = msSince(fireStart)
+		resp.Started++
+
+		s.runManager.Register(record.ID)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runStart := time.Now()
+			ctx := upal.WithRunID(context.Background(), record.ID)
+			if slotHeld {
+				s.runPublisher.LaunchHeld(ctx, record.ID, wf, inputs)
+				s.limiter.Release(wf.Name)
+			} else {
+				s.runPublisher.Launch(ctx, record.ID, wf, inputs)
+			}
+			durations[i] = msSince(runStart)
+		}()
+	}
+
+	if req.Wait {
+		wg.Wait()
+		for i := range resp.Fires {
+			resp.Fires[i].DurationMs = durations[i]
+		}
+	}
+	resp.TotalMs = msSince(start)
+	writeJSON(w, resp)
+}
+
+func msSince(t time.Time) float64 {
+	return float64(time.Since(t).Microseconds()) / 1000
+}
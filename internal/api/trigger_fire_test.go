@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/config"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func newFireNServer(t *testing.T, limits upal.ConcurrencyLimits) (*Server, *services.ConcurrencyLimiter) {
+	t.Helper()
+	srv := newTestServer()
+	trigRepo := repository.NewMemoryTriggerRepository()
+	srv.SetTriggerRepository(trigRepo)
+	limiter := services.NewConcurrencyLimiter(limits)
+	srv.SetConcurrencyLimiter(limiter)
+	srv.SetWebhookConfig(config.WebhookConfig{FireN: true})
+	seedWorkflow(t, srv, "load-wf")
+	trigRepo.Create(context.Background(), &upal.Trigger{
+		ID:           "trig_load",
+		WorkflowName: "load-wf",
+		Type:         upal.TriggerWebhook,
+		Config:       upal.TriggerConfig{InputMapping: map[string]string{"out1": "msg"}},
+		Enabled:      true,
+	})
+	return srv, limiter
+}
+
+func fireN(t *testing.T, srv *Server, body string) FireNResponse {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/triggers/trig_load/fire-n", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("fire-n: got %d; body: %s", w.Code, w.Body.String())
+	}
+	var resp FireNResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return resp
+}
+
+func TestFireTriggerN_StartsRuns(t *testing.T) {
+	srv, _ := newFireNServer(t, upal.ConcurrencyLimits{GlobalMax: 10, PerWorkflow: 10})
+
+	resp := fireN(t, srv, `{"count": 4, "payload": "{\"msg\": \"hello {{i}} of {{n}}\"}", "wait": true}`)
+	if resp.Started != 4 || resp.Rejected != 0 || len(resp.Fires) != 4 {
+		t.Fatalf("got started=%d rejected=%d fires=%d, want 4/0/4", resp.Started, resp.Rejected, len(resp.Fires))
+	}
+	if resp.TotalMs <= 0 {
+		t.Errorf("total_ms: got %v, want > 0", resp.TotalMs)
+	}
+
+	seen := map[string]bool{}
+	for i, f := range resp.Fires {
+		if f.Status != "started" || f.RunID == "" || seen[f.RunID] {
+			t.Fatalf("fire %d: unexpected result %+v", i, f)
+		}
+		seen[f.RunID] = true
+		if f.AcceptMs < 0 || f.DurationMs <= 0 {
+			t.Errorf("fire %d: timings not reported: %+v", i, f)
+		}
+		rec, err := srv.runHistorySvc.GetRun(context.Background(), f.RunID)
+		if err != nil {
+			t.Fatalf("fire %d: run not recorded: %v", i, err)
+		}
+		if rec.TriggerType != string(upal.TriggerWebhook) || rec.TriggerRef != "trig_load" {
+			t.Errorf("fire %d: trigger: got %s/%s", i, rec.TriggerType, rec.TriggerRef)
+		}
+		want := fmt.Sprintf("hello %d of 4", i)
+		if rec.Inputs["out1"] != want {
+			t.Errorf("fire %d: inputs: got %v, want %q", i, rec.Inputs["out1"], want)
+		}
+	}
+}
+
+func TestFireTriggerN_RespectsConcurrencyLimit(t *testing.T) {
+	srv, limiter := newFireNServer(t, upal.ConcurrencyLimits{GlobalMax: 1, PerWorkflow: 1})
+	if !limiter.TryAcquire("other-wf") {
+		t.Fatal("saturate limiter")
+	}
+	defer limiter.Release("other-wf")
+
+	resp := fireN(t, srv, `{"count": 3}`)
+	if resp.Started != 0 || resp.Rejected != 3 {
+		t.Fatalf("got started=%d rejected=%d, want 0/3", resp.Started, resp.Rejected)
+	}
+	for i, f := range resp.Fires {
+		if f.Status != "rejected" || f.RunID != "" {
+			t.Errorf("fire %d: unexpected result %+v", i, f)
+		}
+		if f.RetryAfter != 1 {
+			t.Errorf("fire %d: retry_after: got %d, want 1", i, f.RetryAfter)
+		}
+	}
+	// Load-test rejections leave the backoff real webhook callers see alone.
+	if got := srv.webhookBackoff.peek("trig_load"); got != webhookRetryAfterBase {
+		t.Errorf("webhook backoff after fire-n: got %v, want %v", got, webhookRetryAfterBase)
+	}
+}
+
+func TestFireTriggerN_DisabledByDefault(t *testing.T) {
+	srv, _ := newFireNServer(t, upal.ConcurrencyLimits{GlobalMax: 10, PerWorkflow: 10})
+	srv.SetWebhookConfig(config.WebhookConfig{})
+
+	req := httptest.NewRequest("POST", "/api/triggers/trig_load/fire-n", strings.NewReader(`{"count": 1}`))
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code == http.StatusOK {
+		t.Fatalf("expected fire-n to be unavailable without webhooks.fire_n, got %d", w.Code)
+	}
+}
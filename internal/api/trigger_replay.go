@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/upal"
+)
+
+const (
+	// defaultReplayLimit and maxReplayLimit bound how many deliveries one
+	// replay-failed request replays.
+	defaultReplayLimit = 10
+	maxReplayLimit     = 100
+	// replayScanLimit bounds how many recent runs of the trigger's workflow
+	// are searched for its deliveries.
+	replayScanLimit = 500
+)
+
+// ReplayResult is the outcome of replaying one failed delivery. DeliveryID is
+// the delivery's first run and FailedRunID its latest, failed attempt.
+// Status is "started" or "failed".
+type ReplayResult struct {
+	DeliveryID  string `json:"delivery_id"`
+	FailedRunID string `json:"failed_run_id"`
+	RunID       string `json:"run_id,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ReplayFailedResponse summarises a replay-failed request.
+type ReplayFailedResponse struct {
+	Trigger  string         `json:"trigger"`
+	Replayed int            `json:"replayed"`
+	Results  []ReplayResult `json:"results"`
+}
+
+// webhookDelivery groups the runs of one webhook call: its first run, the
+// retries of it, and any earlier replays.
+type webhookDelivery struct {
+	id     string
+	first  *upal.RunRecord
+	latest *upal.RunRecord
+}
+
+// replayFailedDeliveries handles POST /api/triggers/{id}/replay-failed. It
+// replays the most recent failed deliveries of a workflow trigger (up to
+// ?limit=, default 10) with the inputs recorded on their runs, against the
+// workflow's current definition so a fix made since then takes effect. A
+// delivery counts as failed when its latest attempt failed. New runs are
+// recorded and answered at once; they then start oldest delivery first,
+// each waiting for the concurrency limiter.
+func (s *Server) replayFailedDeliveries(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if s.triggerRepo == nil {
+		http.Error(w, "triggers not available", http.StatusServiceUnavailable)
+		return
+	}
+	if s.runHistorySvc == nil || s.runManager == nil || s.runPublisher == nil {
+		http.Error(w, "run history not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	limit := defaultReplayLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxReplayLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxReplayLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	trigger, err := s.triggerRepo.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	}
+	if trigger.PipelineID != "" {
+		http.Error(w, "replay-failed supports workflow triggers only", http.StatusBadRequest)
+		return
+	}
+	wf, err := s.workflowSvc.Lookup(r.Context(), trigger.WorkflowName)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	if err := s.workflowSvc.Validate(wf); err != nil {
+		writeRunValidationError(w, err)
+		return
+	}
+
+	deliveries, err := s.failedDeliveries(r.Context(), trigger, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := ReplayFailedResponse{Trigger: id, Results: make([]ReplayResult, 0, len(deliveries))}
+	var started []*upal.RunRecord
+	for _, d := range deliveries {
+		res := ReplayResult{DeliveryID: d.id, FailedRunID: d.latest.ID}
+		record, err := s.runHistorySvc.StartReplay(r.Context(), d.latest, d.id, wf)
+		if err != nil {
+			slog.WarnContext(r.Context(), "failed to create replay record", "trigger", id, "delivery", d.id, "err", err)
+			res.Status = "failed"
+			res.Error = err.Error()
+		} else {
+			res.RunID = record.ID
+			res.Status = "started"
+			resp.Replayed++
+			started = append(started, record)
+		}
+		resp.Results = append(resp.Results, res)
+	}
+
+	for _, record := range started {
+		s.runManager.Register(record.ID)
+	}
+	if len(started) > 0 {
+		go s.launchReplays(wf, started)
+	}
+	writeJSON(w, resp)
+}
+
+// launchReplays starts runs in order, each once it holds a concurrency slot.
+func (s *Server) launchReplays(wf *upal.WorkflowDefinition, runs []*upal.RunRecord) {
+	for _, record := range runs {
+		ctx := upal.WithRunID(context.Background(), record.ID)
+		if s.limiter == nil {
+			go s.runPublisher.Launch(ctx, record.ID, wf, record.Inputs)
+			continue
+		}
+		// Acquire only fails once its context ends, which Background never does.
+		_ = s.limiter.Acquire(context.Background(), wf.Name)
+		go func() {
+			defer s.limiter.Release(wf.Name)
+			s.runPublisher.LaunchHeld(ctx, record.ID, wf, record.Inputs)
+		}()
+	}
+}
+
+// failedDeliveries returns up to limit of trigger's most recent deliveries
+// whose latest attempt failed, oldest first.
+func (s *Server) failedDeliveries(ctx context.Context, trigger *upal.Trigger, limit int) ([]webhookDelivery, error) {
+	runs, _, err := s.runHistorySvc.ListRuns(ctx, trigger.WorkflowName, replayScanLimit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+
+	byID := make(map[string]*webhookDelivery)
+	for _, run := range runs {
+		if run.TriggerType != string(upal.TriggerWebhook) || run.TriggerRef != trigger.ID {
+			continue
+		}
+		id := run.ID
+		switch {
+		case run.RerunOf != nil:
+			id = *run.RerunOf
+		case run.RetryOf != nil:
+			id = *run.RetryOf
+		}
+		d, ok := byID[id]
+		if !ok {
+			d = &webhookDelivery{id: id}
+			byID[id] = d
+		}
+		if d.first == nil || run.CreatedAt.Before(d.first.CreatedAt) {
+			d.first = run
+		}
+		if d.latest == nil || run.CreatedAt.After(d.latest.CreatedAt) {
+			d.latest = run
+		}
+	}
+
+	var failed []webhookDelivery
+	for _, d := range byID {
+		if d.latest.Status == upal.RunStatusFailed {
+			failed = append(failed, *d)
+		}
+	}
+	slices.SortFunc(failed, func(a, b webhookDelivery) int { return a.first.CreatedAt.Compare(b.first.CreatedAt) })
+	if len(failed) > limit {
+		failed = failed[len(failed)-limit:]
+	}
+	return failed, nil
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	runpub "github.com/soochol/upal/internal/services/run"
+	"github.com/soochol/upal/internal/upal"
+	"google.golang.org/adk/session"
+)
+
+func newReplayServer(t *testing.T) (*Server, *repository.MemoryRunRepository, *services.RunHistoryService, *services.RunManager) {
+	t.Helper()
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+	srv := NewServer(nil, wfSvc, repo, nil)
+	runRepo := repository.NewMemoryRunRepository()
+	runHistorySvc := services.NewRunHistoryService(runRepo)
+	srv.SetRunHistoryService(runHistorySvc)
+	rm := services.NewRunManager(5 * time.Minute)
+	srv.SetRunManager(rm)
+	srv.SetRunPublisher(runpub.NewRunPublisher(wfSvc, rm, runHistorySvc, nil))
+	srv.SetConcurrencyLimiter(services.NewConcurrencyLimiter(upal.ConcurrencyLimits{GlobalMax: 1, PerWorkflow: 1}))
+
+	trigRepo := repository.NewMemoryTriggerRepository()
+	srv.SetTriggerRepository(trigRepo)
+	seedWorkflow(t, srv, "replay-wf")
+	trigRepo.Create(context.Background(), &upal.Trigger{
+		ID:           "trig_replay",
+		WorkflowName: "replay-wf",
+		Type:         upal.TriggerWebhook,
+		Enabled:      true,
+	})
+	return srv, runRepo, runHistorySvc, rm
+}
+
+// waitForReplay waits until the run manager reports runID done, so the
+// record is no longer being written, and returns the record.
+func waitForReplay(t *testing.T, rm *services.RunManager, runHistorySvc *services.RunHistoryService, runID string) *upal.RunRecord {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, _, done, _, _ := rm.Subscribe(runID, 0); done {
+			rec, err := runHistorySvc.GetRun(context.Background(), runID)
+			if err != nil {
+				t.Fatalf("get run %s: %v", runID, err)
+			}
+			return rec
+		}
+	}
+	t.Fatalf("run %s did not finish", runID)
+	return nil
+}
+
+// seedDelivery records a webhook run of trig_replay created at base+minute.
+func seedDelivery(t *testing.T, runRepo *repository.MemoryRunRepository, id string, minute int, status upal.RunStatus, inputs map[string]any, retryOf *string) {
+	t.Helper()
+	err := runRepo.Create(context.Background(), &upal.RunRecord{
+		ID:           id,
+		WorkflowName: "replay-wf",
+		TriggerType:  string(upal.TriggerWebhook),
+		TriggerRef:   "trig_replay",
+		Status:       status,
+		Inputs:       inputs,
+		RetryOf:      retryOf,
+		CreatedAt:    time.Date(2026, 1, 1, 0, minute, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("seed %s: %v", id, err)
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+func replayFailed(t *testing.T, srv *Server, query string) ReplayFailedResponse {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/triggers/trig_replay/replay-failed"+query, nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("replay-failed: got %d; body: %s", w.Code, w.Body.String())
+	}
+	var resp ReplayFailedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return resp
+}
+
+func TestReplayFailedDeliveries_ReplaysMostRecentInOrder(t *testing.T) {
+	srv, runRepo, runHistorySvc, rm := newReplayServer(t)
+
+	seedDelivery(t, runRepo, "run_f1", 1, upal.RunStatusFailed, map[string]any{"n": 1}, nil)
+	seedDelivery(t, runRepo, "run_ok", 2, upal.RunStatusSuccess, map[string]any{"n": 2}, nil)
+	seedDelivery(t, runRepo, "run_f3", 3, upal.RunStatusFailed, map[string]any{"n": 3}, nil)
+	// Failed, then succeeded on retry: not a failed delivery.
+	seedDelivery(t, runRepo, "run_r4", 4, upal.RunStatusFailed, map[string]any{"n": 4}, nil)
+	seedDelivery(t, runRepo, "run_r4b", 5, upal.RunStatusSuccess, map[string]any{"n": 4}, ptr("run_r4"))
+	// Failed, and its retry failed too: replayed with the retry's inputs.
+	seedDelivery(t, runRepo, "run_f6", 6, upal.RunStatusFailed, map[string]any{"n": 6}, nil)
+	seedDelivery(t, runRepo, "run_f6b", 7, upal.RunStatusFailed, map[string]any{"n": 66}, ptr("run_f6"))
+	seedDelivery(t, runRepo, "run_f8", 8, upal.RunStatusFailed, map[string]any{"n": 8}, nil)
+	// Another trigger's failure is not ours to replay.
+	if err := runRepo.Create(context.Background(), &upal.RunRecord{
+		ID: "run_other", WorkflowName: "replay-wf", TriggerType: string(upal.TriggerWebhook), TriggerRef: "trig_other",
+		Status: upal.RunStatusFailed, CreatedAt: time.Date(2026, 1, 1, 0, 9, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := replayFailed(t, srv, "?limit=3")
+	if resp.Replayed != 3 || len(resp.Results) != 3 {
+		t.Fatalf("got replayed=%d results=%d, want 3/3", resp.Replayed, len(resp.Results))
+	}
+	want := []struct {
+		delivery, failed string
+		n                int
+	}{
+		{"run_f3", "run_f3", 3},
+		{"run_f6", "run_f6b", 66},
+		{"run_f8", "run_f8", 8},
+	}
+	for i, res := range resp.Results {
+		if res.DeliveryID != want[i].delivery || res.FailedRunID != want[i].failed || res.Status != "started" || res.RunID == "" {
+			t.Fatalf("result %d: got %+v, want delivery %s from %s", i, res, want[i].delivery, want[i].failed)
+		}
+		rec := waitForReplay(t, rm, runHistorySvc, res.RunID)
+		if rec.Status != upal.RunStatusSuccess {
+			t.Errorf("replay %d: status %s, error %v", i, rec.Status, rec.Error)
+		}
+		if rec.TriggerType != string(upal.TriggerWebhook) || rec.TriggerRef != "trig_replay" {
+			t.Errorf("replay %d: trigger: got %s/%s", i, rec.TriggerType, rec.TriggerRef)
+		}
+		if rec.RerunOf == nil || *rec.RerunOf != want[i].delivery {
+			t.Errorf("replay %d: rerun_of: got %v, want %s", i, rec.RerunOf, want[i].delivery)
+		}
+		if got, _ := rec.Inputs["n"].(int); got != want[i].n {
+			t.Errorf("replay %d: inputs: got %v, want n=%d", i, rec.Inputs, want[i].n)
+		}
+		if i > 0 {
+			prev, _ := runHistorySvc.GetRun(context.Background(), resp.Results[i-1].RunID)
+			// With one concurrency slot each replay waits for the one before.
+			if rec.CompletedAt.Before(*prev.CompletedAt) {
+				t.Errorf("replay %d finished before replay %d", i, i-1)
+			}
+		}
+	}
+
+	// The replayed deliveries now end in success; only run_f1 is left.
+	resp = replayFailed(t, srv, "")
+	if resp.Replayed != 1 || resp.Results[0].DeliveryID != "run_f1" {
+		t.Fatalf("second replay: got %+v, want only run_f1", resp.Results)
+	}
+	waitForReplay(t, rm, runHistorySvc, resp.Results[0].RunID)
+}
+
+func TestReplayFailedDeliveries_InvalidLimit(t *testing.T) {
+	srv, _, _, _ := newReplayServer(t)
+	req := httptest.NewRequest("POST", "/api/triggers/trig_replay/replay-failed?limit=0", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want 400", w.Code)
+	}
+}
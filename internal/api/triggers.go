@@ -24,6 +24,16 @@ func (s *Server) createTrigger(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "workflow_name or pipeline_id is required", http.StatusBadRequest)
 		return
 	}
+	if trigger.WorkflowName != "" && trigger.PipelineID != "" {
+		http.Error(w, "workflow_name and pipeline_id are mutually exclusive", http.StatusBadRequest)
+		return
+	}
+	switch trigger.Config.PayloadFormat {
+	case "", "json", "form":
+	default:
+		http.Error(w, `payload_format must be "json" or "form"`, http.StatusBadRequest)
+		return
+	}
 
 	trigger.ID = upal.GenerateID("trig")
 	trigger.Type = upal.TriggerWebhook
@@ -40,10 +50,11 @@ func (s *Server) createTrigger(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	auditCreated(r, trigger.ID)
 
 	writeJSONStatus(w, http.StatusCreated, map[string]any{
 		"trigger":     trigger,
-		"webhook_url": "/api/hooks/" + trigger.ID,
+		"webhook_url": s.publicBaseURL(r, "") + "/api/hooks/" + trigger.ID,
 	})
 }
 
@@ -112,6 +112,15 @@ func TestCreateTrigger_MissingWorkflow(t *testing.T) {
 	}
 }
 
+func TestCreateTrigger_WorkflowAndPipelineExclusive(t *testing.T) {
+	srv := newTestServerWithTriggers()
+
+	w := createTriggerHelper(t, srv, `{"workflow_name": "wf", "pipeline_id": "pipe-1"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestCreateTrigger_CustomSecret(t *testing.T) {
 	srv := newTestServerWithTriggers()
 
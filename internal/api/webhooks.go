@@ -1,16 +1,28 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/config"
+	"github.com/soochol/upal/internal/services"
 	"github.com/soochol/upal/internal/upal"
 )
 
@@ -38,19 +50,28 @@ func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	payload := parseWebhookPayload(r.Header.Get("Content-Type"), trigger.Config.PayloadFormat, body, s.floatNumbers)
+
+	// URL-verification handshakes (e.g. Slack) come from services that cannot
+	// sign with the trigger secret. Echoing the caller's own value back starts
+	// no run, so it is answered before the signature check.
+	if challenge, ok := webhookChallenge(payload, trigger.Config.ChallengeField); ok {
+		writeJSON(w, map[string]string{trigger.Config.ChallengeField: challenge})
+		return
+	}
+
 	if trigger.Config.Secret != "" {
-		signature := r.Header.Get("X-Webhook-Signature")
+		header := trigger.Config.SignatureHeader
+		if header == "" {
+			header = upal.DefaultSignatureHeader
+		}
+		signature := r.Header.Get(header)
 		if !verifyHMAC(body, trigger.Config.Secret, signature) {
 			http.Error(w, "invalid signature", http.StatusUnauthorized)
 			return
 		}
 	}
 
-	var payload map[string]any
-	if len(body) > 0 {
-		json.Unmarshal(body, &payload)
-	}
-
 	inputs := mapInputs(payload, trigger.Config.InputMapping)
 
 	if trigger.PipelineID != "" {
@@ -77,7 +98,29 @@ func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "workflow not found", http.StatusNotFound)
 			return
 		}
+		slotHeld := false
+		if s.limiter != nil && s.webhookCfg.OnSaturation != config.WebhookSaturationQueue {
+			if !s.limiter.TryAcquire(wf.Name) {
+				retryAfter := s.webhookBackoff.next(trigger.ID)
+				slog.Warn("webhook: concurrency limit reached", "trigger", id, "workflow", wf.Name, "retry_after", retryAfter)
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+				http.Error(w, "concurrency limit reached", http.StatusServiceUnavailable)
+				return
+			}
+			s.webhookBackoff.reset(trigger.ID)
+			slotHeld = true
+		}
+		if trigger.Config.Sync {
+			s.runWebhookSync(w, r, trigger, wf, inputs, slotHeld)
+			return
+		}
 		go func() {
+			if s.limiter != nil {
+				if !slotHeld {
+					s.limiter.Acquire(context.Background(), wf.Name)
+				}
+				defer s.limiter.Release(wf.Name)
+			}
 			if s.retryExecutor != nil {
 				policy := upal.DefaultRetryPolicy()
 				events, result, err := s.retryExecutor.ExecuteWithRetry(
@@ -97,13 +140,201 @@ func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		}()
 	}
 
+	if trigger.Config.ResponseTemplate != "" {
+		writeWebhookTemplate(w, trigger.Config.ResponseTemplate, payload, id)
+		return
+	}
 	writeJSONStatus(w, http.StatusAccepted, map[string]string{
 		"status":  "accepted",
 		"trigger": id,
 	})
 }
 
+// webhookChallenge returns the payload's challenge value when the trigger
+// names a challenge field and the payload carries a non-empty string there.
+func webhookChallenge(payload map[string]any, field string) (string, bool) {
+	if field == "" {
+		return "", false
+	}
+	challenge, _ := payload[field].(string)
+	return challenge, challenge != ""
+}
+
+// writeWebhookTemplate answers an accepted webhook with the trigger's response
+// template. {{status}}, {{trigger}} and top-level payload fields are
+// substituted; in a JSON template they are JSON-escaped, and the body is sent
+// as JSON when the result parses as JSON.
+func writeWebhookTemplate(w http.ResponseWriter, tmpl string, payload map[string]any, triggerID string) {
+	values := make(map[string]any, len(payload)+2)
+	for k, v := range payload {
+		values[k] = v
+	}
+	values["status"] = "accepted"
+	values["trigger"] = triggerID
+
+	var rendered string
+	if agents.IsJSONTemplate(tmpl) {
+		rendered = agents.ResolveJSONTemplate(tmpl, values)
+	} else {
+		rendered = agents.ResolveTemplate(tmpl, values)
+	}
+	if json.Valid([]byte(rendered)) {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusAccepted)
+	io.WriteString(w, rendered)
+}
+
+// defaultSyncWebhookTimeout bounds a sync webhook run when the trigger does
+// not set timeout_seconds.
+const defaultSyncWebhookTimeout = 30 * time.Second
+
+// runWebhookSync executes the workflow inline and writes its final output as
+// the response body. Runs that exceed the trigger timeout answer 504. When
+// slotHeld is false a concurrency slot is waited for within the timeout.
+func (s *Server) runWebhookSync(w http.ResponseWriter, r *http.Request, trigger *upal.Trigger, wf *upal.WorkflowDefinition, inputs map[string]any, slotHeld bool) {
+	timeout := defaultSyncWebhookTimeout
+	if trigger.Config.TimeoutSeconds > 0 {
+		timeout = time.Duration(trigger.Config.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	if s.limiter != nil {
+		if !slotHeld {
+			if err := s.limiter.Acquire(ctx, wf.Name); err != nil {
+				http.Error(w, "timed out waiting for a concurrency slot", http.StatusGatewayTimeout)
+				return
+			}
+		}
+		defer s.limiter.Release(wf.Name)
+	}
+
+	ctx, run, err := services.TrackRun(ctx, s.runHistorySvc, s.runManager, wf, string(upal.TriggerWebhook), trigger.ID, inputs)
+	if err != nil {
+		slog.Warn("webhook: failed to create run record", "trigger", trigger.ID, "err", err)
+	}
+
+	events, result, err := s.workflowSvc.Run(ctx, wf, inputs)
+	if err != nil {
+		run.Finish(ctx, upal.RunResult{}, err)
+		if errors.Is(err, upal.ErrModerationBlocked) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var runErr string
+	for events != nil {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if !run.Observe(ctx, ev) {
+				continue
+			}
+			if ev.Type == upal.EventError {
+				if msg, ok := ev.Payload["error"].(string); ok {
+					runErr = msg
+				}
+			}
+		case <-ctx.Done():
+			events = nil
+		}
+	}
+	if run.Cancelled(ctx) {
+		run.Finish(ctx, upal.RunResult{}, nil)
+		http.Error(w, "run cancelled", http.StatusConflict)
+		return
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		slog.Warn("webhook: sync run timed out", "trigger", trigger.ID, "timeout", timeout)
+		run.Finish(ctx, upal.RunResult{}, errors.New("workflow timed out"))
+		http.Error(w, "workflow timed out", http.StatusGatewayTimeout)
+		return
+	}
+	res, ok := <-result
+	if runErr != "" || !ok {
+		if runErr == "" {
+			runErr = "workflow produced no result"
+		}
+		run.Finish(ctx, res, errors.New(runErr))
+		http.Error(w, runErr, http.StatusInternalServerError)
+		return
+	}
+	run.Finish(ctx, res, nil)
+
+	output := any(res.State)
+	if out, ok := res.State["__output__"]; ok {
+		output = out
+	}
+	writeJSON(w, map[string]any{
+		"status":     "completed",
+		"trigger":    trigger.ID,
+		"session_id": res.SessionID,
+		"output":     output,
+	})
+}
+
+// Retry-After bounds for webhooks rejected by the concurrency limiter. The
+// delay doubles with each consecutive rejection of the same trigger.
+const (
+	webhookRetryAfterBase = time.Second
+	webhookRetryAfterMax  = time.Minute
+)
+
+// retryBackoff tracks consecutive rejections per key to compute an
+// exponentially growing Retry-After. The zero value is ready to use.
+type retryBackoff struct {
+	mu      sync.Mutex
+	strikes map[string]int
+}
+
+// next records a rejection for key and returns the Retry-After for it.
+func (b *retryBackoff) next(key string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.strikes == nil {
+		b.strikes = make(map[string]int)
+	}
+	n := b.strikes[key]
+	b.strikes[key] = n + 1
+	return retryAfterFor(n)
+}
+
+// peek returns the Retry-After a rejection for key would get now, without
+// recording one.
+func (b *retryBackoff) peek(key string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return retryAfterFor(b.strikes[key])
+}
+
+// retryAfterFor doubles the base delay for each earlier strike, up to the max.
+func retryAfterFor(strikes int) time.Duration {
+	d := webhookRetryAfterBase
+	for i := 0; i < strikes && d < webhookRetryAfterMax; i++ {
+		d *= 2
+	}
+	return min(d, webhookRetryAfterMax)
+}
+
+func (b *retryBackoff) reset(key string) {
+	b.mu.Lock()
+	delete(b.strikes, key)
+	b.mu.Unlock()
+}
+
+// verifyHMAC checks a hex HMAC-SHA256 signature of payload. A "sha256="
+// scheme prefix, as sent in GitHub's X-Hub-Signature-256, is accepted.
 func verifyHMAC(payload []byte, secret, signature string) bool {
+	signature = strings.TrimPrefix(signature, "sha256=")
 	if signature == "" {
 		return false
 	}
@@ -113,6 +344,82 @@ func verifyHMAC(payload []byte, secret, signature string) bool {
 	return hmac.Equal([]byte(expected), []byte(signature))
 }
 
+// webhookMultipartMemory caps the multipart form held in memory; larger file
+// parts spill to temp files that are removed once parsed.
+const webhookMultipartMemory = 32 << 20
+
+// parseWebhookPayload decodes a webhook body into the map fed to mapInputs.
+// format ("json" | "form") overrides Content-Type detection. Malformed
+// bodies yield a nil payload rather than an error, matching JSON behaviour.
+// Form fields with one value map to a string, repeated fields to a list;
+// multipart file parts are captured as metadata, not content. JSON numbers
+// decode as described at unmarshalInputs.
+func parseWebhookPayload(contentType, format string, body []byte, floatNumbers bool) map[string]any {
+	if len(body) == 0 {
+		return nil
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "multipart/form-data" && format != "json":
+		return parseMultipartPayload(body, params["boundary"])
+	case format == "form" || (format == "" && mediaType == "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil
+		}
+		return formValues(values)
+	default:
+		var payload map[string]any
+		unmarshalInputs(body, &payload, floatNumbers)
+		return payload
+	}
+}
+
+func parseMultipartPayload(body []byte, boundary string) map[string]any {
+	if boundary == "" {
+		return nil
+	}
+	form, err := multipart.NewReader(bytes.NewReader(body), boundary).ReadForm(webhookMultipartMemory)
+	if err != nil {
+		return nil
+	}
+	defer form.RemoveAll()
+
+	payload := formValues(form.Value)
+	for name, files := range form.File {
+		meta := make([]any, len(files))
+		for i, fh := range files {
+			meta[i] = map[string]any{
+				"filename":     fh.Filename,
+				"content_type": fh.Header.Get("Content-Type"),
+				"size":         fh.Size,
+			}
+		}
+		if len(meta) == 1 {
+			payload[name] = meta[0]
+		} else {
+			payload[name] = meta
+		}
+	}
+	return payload
+}
+
+func formValues(values map[string][]string) map[string]any {
+	payload := make(map[string]any, len(values))
+	for k, vs := range values {
+		if len(vs) == 1 {
+			payload[k] = vs[0]
+			continue
+		}
+		list := make([]any, len(vs))
+		for i, v := range vs {
+			list[i] = v
+		}
+		payload[k] = list
+	}
+	return payload
+}
+
 func mapInputs(payload map[string]any, mapping map[string]string) map[string]any {
 	if len(mapping) == 0 {
 		return payload
@@ -7,12 +7,15 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/config"
 	"github.com/soochol/upal/internal/repository"
 	"github.com/soochol/upal/internal/services"
 	"github.com/soochol/upal/internal/upal"
@@ -123,6 +126,167 @@ func TestMapInputs(t *testing.T) {
 	})
 }
 
+func TestParseWebhookPayload_Form(t *testing.T) {
+	body := []byte("text=hello+world&sender=alice&tag=a&tag=b")
+	payload := parseWebhookPayload("application/x-www-form-urlencoded; charset=utf-8", "", body, false)
+
+	if payload["text"] != "hello world" || payload["sender"] != "alice" {
+		t.Errorf("payload = %v", payload)
+	}
+	if tags, _ := payload["tag"].([]any); len(tags) != 2 {
+		t.Errorf("repeated field: got %v, want 2 values", payload["tag"])
+	}
+	inputs := mapInputs(payload, map[string]string{"query": "text"})
+	if inputs["query"] != "hello world" {
+		t.Errorf("mapped inputs = %v", inputs)
+	}
+
+	// An explicit payload_format overrides a misleading Content-Type.
+	if forced := parseWebhookPayload("text/plain", "form", body, false); forced["sender"] != "alice" {
+		t.Errorf("forced form payload = %v", forced)
+	}
+}
+
+func TestParseWebhookPayload_Multipart(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("subject", "invoice")
+	fw, _ := mw.CreateFormFile("attachment", "invoice.pdf")
+	fw.Write([]byte("%PDF-1.4 fake"))
+	mw.Close()
+
+	payload := parseWebhookPayload(mw.FormDataContentType(), "", buf.Bytes(), false)
+
+	if payload["subject"] != "invoice" {
+		t.Errorf("subject = %v", payload["subject"])
+	}
+	file, ok := payload["attachment"].(map[string]any)
+	if !ok {
+		t.Fatalf("attachment = %v, want file metadata", payload["attachment"])
+	}
+	if file["filename"] != "invoice.pdf" || file["size"] != int64(13) || file["content_type"] != "application/octet-stream" {
+		t.Errorf("file metadata = %v", file)
+	}
+}
+
+func TestHandleWebhook_FormPayloadSigned(t *testing.T) {
+	srv, trigRepo := newTestServerWithWebhook()
+	seedWorkflow(t, srv, "form-wf")
+	trigRepo.Create(context.Background(), &upal.Trigger{
+		ID:           "trig_form",
+		WorkflowName: "form-wf",
+		Type:         upal.TriggerWebhook,
+		Config:       upal.TriggerConfig{Secret: "form-secret"},
+		Enabled:      true,
+		CreatedAt:    time.Now(),
+	})
+
+	payload := []byte("text=hello&sender=alice")
+	req := httptest.NewRequest("POST", "/api/hooks/trig_form", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Webhook-Signature", signPayload(payload, "form-secret"))
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status: got %d, want 202; body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleWebhook_ChallengeEcho(t *testing.T) {
+	srv, trigRepo := newTestServerWithWebhook()
+	seedWorkflow(t, srv, "slack-wf")
+	trigRepo.Create(context.Background(), &upal.Trigger{
+		ID:           "trig_slack",
+		WorkflowName: "slack-wf",
+		Type:         upal.TriggerWebhook,
+		Config:       upal.TriggerConfig{Secret: "slack-secret", ChallengeField: "challenge"},
+		Enabled:      true,
+		CreatedAt:    time.Now(),
+	})
+
+	// Slack's URL verification request is unsigned and expects the challenge back.
+	payload := []byte(`{"type":"url_verification","token":"t","challenge":"3eZbrw1aBm2rZgRNFdxV"}`)
+	req := httptest.NewRequest("POST", "/api/hooks/trig_slack", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want 200; body: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp["challenge"] != "3eZbrw1aBm2rZgRNFdxV" {
+		t.Errorf("challenge = %q, want echoed value", resp["challenge"])
+	}
+
+	// Ordinary events on the same trigger still require a signature.
+	req = httptest.NewRequest("POST", "/api/hooks/trig_slack", bytes.NewReader([]byte(`{"type":"event_callback"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned event: got %d, want 401", w.Code)
+	}
+}
+
+func TestHandleWebhook_ResponseTemplate(t *testing.T) {
+	srv, trigRepo := newTestServerWithWebhook()
+	seedWorkflow(t, srv, "tmpl-wf")
+	trigRepo.Create(context.Background(), &upal.Trigger{
+		ID:           "trig_tmpl",
+		WorkflowName: "tmpl-wf",
+		Type:         upal.TriggerWebhook,
+		Config: upal.TriggerConfig{
+			Secret:           "tmpl-secret",
+			ResponseTemplate: `{"ok":true,"ref":"{{order_id}}","via":"{{trigger}}"}`,
+		},
+		Enabled:   true,
+		CreatedAt: time.Now(),
+	})
+
+	payload := []byte(`{"order_id":"A-17"}`)
+	req := httptest.NewRequest("POST", "/api/hooks/trig_tmpl", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Signature", signPayload(payload, "tmpl-secret"))
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status: got %d, want 202; body: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if got := w.Body.String(); got != `{"ok":true,"ref":"A-17","via":"trig_tmpl"}` {
+		t.Errorf("body = %s", got)
+	}
+}
+
+func TestWriteWebhookTemplate_EscapesJSON(t *testing.T) {
+	tmpl := `{"ok":true,"ref":"{{order_id}}","count":{{count}},"note":{{note}}}`
+	payload := map[string]any{
+		"order_id": `A-17","admin":true,"x":"\`,
+		"count":    json.Number("3"),
+		"note":     "plain text",
+	}
+	w := httptest.NewRecorder()
+	writeWebhookTemplate(w, tmpl, payload, "trig_tmpl")
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json; body: %s", ct, w.Body.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("body is not JSON: %v; body: %s", err, w.Body.String())
+	}
+	want := map[string]any{"ok": true, "ref": payload["order_id"], "count": 3.0, "note": "plain text"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+}
+
 func TestHandleWebhook_Success(t *testing.T) {
 	srv, trigRepo := newTestServerWithWebhook()
 	seedWorkflow(t, srv, "test-wf")
@@ -165,6 +329,49 @@ func TestHandleWebhook_Success(t *testing.T) {
 	}
 }
 
+func TestHandleWebhook_CustomSignatureHeader(t *testing.T) {
+	srv, trigRepo := newTestServerWithWebhook()
+	seedWorkflow(t, srv, "test-wf")
+
+	secret := "hub-secret"
+	trigger := &upal.Trigger{
+		ID:           "trig_hub",
+		WorkflowName: "test-wf",
+		Type:         upal.TriggerWebhook,
+		Config:       upal.TriggerConfig{Secret: secret, SignatureHeader: "X-Hub-Signature-256"},
+		Enabled:      true,
+		CreatedAt:    time.Now(),
+	}
+	if err := trigRepo.Create(context.Background(), trigger); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	payload := []byte(`{"action":"opened"}`)
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"github style prefixed", "X-Hub-Signature-256", "sha256=" + signPayload(payload, secret), http.StatusAccepted},
+		{"bare hex", "X-Hub-Signature-256", signPayload(payload, secret), http.StatusAccepted},
+		{"default header ignored", "X-Webhook-Signature", signPayload(payload, secret), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/hooks/trig_hub", bytes.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status: got %d, want %d; body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestHandleWebhook_TriggerNotFound(t *testing.T) {
 	srv, _ := newTestServerWithWebhook()
 
@@ -358,3 +565,269 @@ func TestHandleWebhook_InputMapping(t *testing.T) {
 		t.Errorf("trigger field: got %q, want %q", resp["trigger"], "trig_mapped")
 	}
 }
+
+// blockingExecutor is a WorkflowExecutor whose runs never finish until the
+// caller's context is cancelled.
+type blockingExecutor struct{}
+
+func (blockingExecutor) Lookup(_ context.Context, name string) (*upal.WorkflowDefinition, error) {
+	return &upal.WorkflowDefinition{Name: name}, nil
+}
+
+func (blockingExecutor) Validate(*upal.WorkflowDefinition) error { return nil }
+
+func (blockingExecutor) Run(ctx context.Context, _ *upal.WorkflowDefinition, _ map[string]any) (<-chan upal.WorkflowEvent, <-chan upal.RunResult, error) {
+	events := make(chan upal.WorkflowEvent)
+	result := make(chan upal.RunResult, 1)
+	go func() {
+		<-ctx.Done()
+		close(events)
+		close(result)
+	}()
+	return events, result, nil
+}
+
+func TestHandleWebhook_SyncReturnsOutput(t *testing.T) {
+	srv, trigRepo := newTestServerWithWebhook()
+
+	wf := upal.WorkflowDefinition{
+		Name:    "sync-wf",
+		Version: 1,
+		Nodes: []upal.NodeDefinition{
+			{ID: "question", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "answer", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "question", To: "answer"}},
+	}
+	body, _ := json.Marshal(wf)
+	req := httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create workflow: got %d; body: %s", w.Code, w.Body.String())
+	}
+
+	trigger := &upal.Trigger{
+		ID:           "trig_sync",
+		WorkflowName: "sync-wf",
+		Type:         upal.TriggerWebhook,
+		Config: upal.TriggerConfig{
+			InputMapping: map[string]string{"question": "text"},
+			Sync:         true,
+		},
+		Enabled:   true,
+		CreatedAt: time.Now(),
+	}
+	if err := trigRepo.Create(context.Background(), trigger); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	req = httptest.NewRequest("POST", "/api/hooks/trig_sync", bytes.NewReader([]byte(`{"text":"ping"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want 200; body: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Status string         `json:"status"`
+		Output map[string]any `json:"output"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if resp.Status != "completed" {
+		t.Errorf("status field: got %q, want %q", resp.Status, "completed")
+	}
+	if resp.Output["answer"] != "ping" {
+		t.Errorf("output.answer: got %v, want %q", resp.Output["answer"], "ping")
+	}
+}
+
+func TestHandleWebhook_SyncTimeout(t *testing.T) {
+	trigRepo := repository.NewMemoryTriggerRepository()
+	srv := NewServer(nil, blockingExecutor{}, repository.NewMemory(), nil)
+	srv.SetTriggerRepository(trigRepo)
+
+	trigger := &upal.Trigger{
+		ID:           "trig_slow",
+		WorkflowName: "slow-wf",
+		Type:         upal.TriggerWebhook,
+		Config:       upal.TriggerConfig{Sync: true, TimeoutSeconds: 1},
+		Enabled:      true,
+		CreatedAt:    time.Now(),
+	}
+	if err := trigRepo.Create(context.Background(), trigger); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/hooks/trig_slow", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status: got %d, want 504; body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleWebhook_SyncRunIsActiveAndCancellable(t *testing.T) {
+	trigRepo := repository.NewMemoryTriggerRepository()
+	srv := NewServer(nil, blockingExecutor{}, repository.NewMemory(), nil)
+	srv.SetTriggerRepository(trigRepo)
+	runHistorySvc := services.NewRunHistoryService(repository.NewMemoryRunRepository())
+	srv.SetRunHistoryService(runHistorySvc)
+	rm := services.NewRunManager(5 * time.Minute)
+	defer rm.Stop()
+	srv.SetRunManager(rm)
+
+	if err := trigRepo.Create(context.Background(), &upal.Trigger{
+		ID:           "trig_slow",
+		WorkflowName: "slow-wf",
+		Type:         upal.TriggerWebhook,
+		Config:       upal.TriggerConfig{Sync: true, TimeoutSeconds: 30},
+		Enabled:      true,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	hook := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		srv.Handler().ServeHTTP(hook, httptest.NewRequest("POST", "/api/hooks/trig_slow", bytes.NewReader([]byte(`{}`))))
+	}()
+
+	var list struct {
+		Runs []upal.ActiveRun `json:"runs"`
+	}
+	for deadline := time.Now().Add(5 * time.Second); len(list.Runs) == 0 && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/runs/active", nil))
+		json.Unmarshal(w.Body.Bytes(), &list)
+	}
+	if len(list.Runs) != 1 || list.Runs[0].TriggerType != string(upal.TriggerWebhook) || list.Runs[0].TriggerRef != "trig_slow" {
+		t.Fatalf("active runs = %+v, want the sync webhook run", list.Runs)
+	}
+	runID := list.Runs[0].RunID
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/runs/"+runID+"/cancel", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("cancel: got %d, body: %s", w.Code, w.Body.String())
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sync webhook did not return after cancel")
+	}
+	if hook.Code != http.StatusConflict {
+		t.Errorf("webhook status: got %d, want 409", hook.Code)
+	}
+	if rec, _ := runHistorySvc.GetRun(context.Background(), runID); rec.Status != upal.RunStatusCancelled {
+		t.Errorf("status = %q, want cancelled", rec.Status)
+	}
+}
+
+func TestHandleWebhook_PipelineTrigger(t *testing.T) {
+	srv, pipelineRepo, runRepo := newTestPipelineServer(t)
+	srv.SetTriggerRepository(repository.NewMemoryTriggerRepository())
+	pipelineRepo.Create(context.Background(), &upal.Pipeline{
+		ID:     "pipe-hook",
+		Name:   "Hooked",
+		Stages: []upal.Stage{{ID: "s1", Type: "workflow"}},
+	})
+
+	// Create the trigger through the API, as a client would.
+	createReq := httptest.NewRequest("POST", "/api/triggers", bytes.NewReader([]byte(`{"pipeline_id":"pipe-hook"}`)))
+	createReq.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(createW, createReq)
+	if createW.Code != http.StatusCreated {
+		t.Fatalf("create trigger: got %d; body: %s", createW.Code, createW.Body.String())
+	}
+	var created struct {
+		Trigger upal.Trigger `json:"trigger"`
+	}
+	if err := json.Unmarshal(createW.Body.Bytes(), &created); err != nil {
+		t.Fatalf("unmarshal trigger: %v", err)
+	}
+
+	payload := []byte(`{"topic":"ai"}`)
+	req := httptest.NewRequest("POST", "/api/hooks/"+created.Trigger.ID, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Signature", signPayload(payload, created.Trigger.Config.Secret))
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("fire webhook: got %d; body: %s", w.Code, w.Body.String())
+	}
+
+	// The pipeline starts in the background; wait for its run to appear.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		runs, _ := runRepo.ListByPipeline(context.Background(), "pipe-hook")
+		if len(runs) == 1 && runs[0].Status == upal.PipelineRunCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected one completed pipeline run, got %d", len(runs))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandleWebhook_SaturatedLimiter(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		wantStatus int
+	}{
+		{"reject", config.WebhookSaturationReject, http.StatusServiceUnavailable},
+		{"queue", config.WebhookSaturationQueue, http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, trigRepo := newTestServerWithWebhook()
+			seedWorkflow(t, srv, "busy-wf")
+			limiter := services.NewConcurrencyLimiter(upal.ConcurrencyLimits{GlobalMax: 1, PerWorkflow: 1})
+			srv.SetConcurrencyLimiter(limiter)
+			srv.SetWebhookConfig(config.WebhookConfig{OnSaturation: tt.mode})
+			trigRepo.Create(context.Background(), &upal.Trigger{
+				ID:           "trig_busy",
+				WorkflowName: "busy-wf",
+				Type:         upal.TriggerWebhook,
+				Enabled:      true,
+			})
+
+			// Saturate the global limit with another workflow.
+			if !limiter.TryAcquire("other-wf") {
+				t.Fatal("saturate limiter")
+			}
+			defer limiter.Release("other-wf")
+
+			fire := func() *httptest.ResponseRecorder {
+				req := httptest.NewRequest("POST", "/api/hooks/trig_busy", bytes.NewReader([]byte(`{}`)))
+				w := httptest.NewRecorder()
+				srv.Handler().ServeHTTP(w, req)
+				return w
+			}
+
+			w := fire()
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status: got %d, want %d; body: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusServiceUnavailable {
+				return
+			}
+			if got := w.Header().Get("Retry-After"); got != "1" {
+				t.Errorf("Retry-After: got %q, want 1", got)
+			}
+			if got := fire().Header().Get("Retry-After"); got != "2" {
+				t.Errorf("second Retry-After: got %q, want 2", got)
+			}
+		})
+	}
+}
@@ -1,34 +1,71 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/dag"
 	"github.com/soochol/upal/internal/upal"
 )
 
-// validateWorkflowTools checks that every tool-type node references a tool
-// that is actually registered (custom or native).
-func (s *Server) validateWorkflowTools(wf *upal.WorkflowDefinition) error {
+// workflowToolIssues reports tool-type nodes that do not reference a
+// registered tool (custom or native).
+func (s *Server) workflowToolIssues(wf *upal.WorkflowDefinition) []upal.ValidationIssue {
 	if s.toolReg == nil {
 		return nil
 	}
-	for _, n := range wf.Nodes {
+	var issues []upal.ValidationIssue
+	for i, n := range wf.Nodes {
 		if n.Type != upal.NodeTypeTool {
 			continue
 		}
+		path := fmt.Sprintf("nodes[%d].config.tool", i)
 		toolName, _ := n.Config["tool"].(string)
 		if toolName == "" {
-			return fmt.Errorf("tool node %q: missing required config field \"tool\"", n.ID)
+			issues = append(issues, upal.ValidationIssue{Path: path, Message: "required"})
+			continue
 		}
 		_, isCustom := s.toolReg.Get(toolName)
 		isNative := s.toolReg.IsNative(toolName)
 		if !isCustom && !isNative {
-			return fmt.Errorf("tool node %q: unknown tool %q (available: use GET /api/tools to list registered tools)", n.ID, toolName)
+			issues = append(issues, upal.ValidationIssue{
+				Path:    path,
+				Message: fmt.Sprintf("unknown tool %q (available: use GET /api/tools to list registered tools)", toolName),
+			})
 		}
 	}
-	return nil
+	return issues
+}
+
+// validateWorkflow rejects wf with 422 listing every validation issue, each
+// with the path of the offending field, plus a one-line summary in "error".
+func (s *Server) validateWorkflow(w http.ResponseWriter, wf *upal.WorkflowDefinition) bool {
+	issues := append(upal.ValidateWorkflow(wf), s.workflowToolIssues(wf)...)
+	if len(issues) == 0 {
+		return true
+	}
+	verr := &upal.ValidationError{Issues: issues}
+	writeJSONStatus(w, http.StatusUnprocessableEntity, struct {
+		Error string `json:"error"`
+		*upal.ValidationError
+	}{verr.Error(), verr})
+	return false
+}
+
+// checkWorkflowLimits rejects wf with 400 naming the exceeded limit when it is
+// over the configured size limits.
+func (s *Server) checkWorkflowLimits(w http.ResponseWriter, wf *upal.WorkflowDefinition) bool {
+	var limitErr *upal.WorkflowLimitError
+	if err := s.workflowLimits.Check(wf); errors.As(err, &limitErr) {
+		writeJSONStatus(w, http.StatusBadRequest, struct {
+			Error string `json:"error"`
+			*upal.WorkflowLimitError
+		}{err.Error(), limitErr})
+		return false
+	}
+	return true
 }
 
 func (s *Server) createWorkflow(w http.ResponseWriter, r *http.Request) {
@@ -36,14 +73,18 @@ func (s *Server) createWorkflow(w http.ResponseWriter, r *http.Request) {
 	if !decodeJSON(w, r, &wf) {
 		return
 	}
-	if err := s.validateWorkflowTools(&wf); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if !s.checkWorkflowLimits(w, &wf) {
+		return
+	}
+	if !s.validateWorkflow(w, &wf) {
 		return
 	}
+	markWorkflowEdited(r, &wf)
 	if err := s.repo.Create(r.Context(), &wf); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	auditCreated(r, wf.Name)
 	writeJSONStatus(w, http.StatusCreated, wf)
 }
 
@@ -66,23 +107,93 @@ func (s *Server) getWorkflow(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, wf)
 }
 
+// getWorkflowGraph handles GET /api/workflows/{name}/graph. It returns the
+// workflow's normalized DAG, or 422 with the offending cycle when the forward
+// edges are cyclic.
+func (s *Server) getWorkflowGraph(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	wf, err := s.repo.Get(r.Context(), name)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	graph, err := dag.Analyze(wf)
+	if err != nil {
+		resp := map[string]any{"error": err.Error()}
+		var cycleErr *dag.CycleError
+		if errors.As(err, &cycleErr) {
+			resp["cycle"] = cycleErr.Cycle
+		}
+		writeJSONStatus(w, http.StatusUnprocessableEntity, resp)
+		return
+	}
+	writeJSON(w, graph)
+}
+
 func (s *Server) updateWorkflow(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	var wf upal.WorkflowDefinition
 	if !decodeJSON(w, r, &wf) {
 		return
 	}
-	if err := s.validateWorkflowTools(&wf); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if !s.checkWorkflowLimits(w, &wf) {
 		return
 	}
+	if !s.validateWorkflow(w, &wf) {
+		return
+	}
+	before, _ := s.repo.Get(r.Context(), name)
+	// The editor does not round-trip the baseline; keep it unless the
+	// request sets one (clear it with DELETE /baseline).
+	if wf.BaselineRunID == "" && before != nil {
+		wf.BaselineRunID = before.BaselineRunID
+	}
+	// Likewise the webhook secret (DELETE /webhook-secret clears it).
+	if wf.WebhookSecret == "" && before != nil {
+		wf.WebhookSecret = before.WebhookSecret
+	}
+	markWorkflowEdited(r, &wf)
 	if err := s.repo.Update(r.Context(), name, &wf); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	auditChanged(r, before, &wf)
 	writeJSON(w, wf)
 }
 
+// patchWorkflow applies a WorkflowDelta to a stored workflow, re-validates the
+// merged definition, and saves it.
+func (s *Server) patchWorkflow(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	existing, err := s.repo.Get(r.Context(), name)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	var delta upal.WorkflowDelta
+	if !decodeJSON(w, r, &delta) {
+		return
+	}
+	merged, err := upal.ApplyWorkflowDelta(existing, &delta)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !s.checkWorkflowLimits(w, merged) {
+		return
+	}
+	if !s.validateWorkflow(w, merged) {
+		return
+	}
+	markWorkflowEdited(r, merged)
+	if err := s.repo.Update(r.Context(), name, merged); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	auditChanged(r, existing, merged)
+	writeJSON(w, merged)
+}
+
 func (s *Server) deleteWorkflow(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	if err := s.repo.Delete(r.Context(), name); err != nil {
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/soochol/upal/internal/dag"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestGetWorkflowGraph_DAG(t *testing.T) {
+	srv := newTestServer()
+	srv.repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "diamond",
+		Nodes: []upal.NodeDefinition{
+			{ID: "in", Type: upal.NodeTypeInput},
+			{ID: "left", Type: upal.NodeTypeAgent},
+			{ID: "right", Type: upal.NodeTypeAgent},
+			{ID: "out", Type: upal.NodeTypeOutput},
+		},
+		Edges: []upal.EdgeDefinition{
+			{From: "in", To: "left"}, {From: "in", To: "right"},
+			{From: "left", To: "out"}, {From: "right", To: "out"},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/workflows/diamond/graph", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d, body: %s", w.Code, w.Body.String())
+	}
+	var g dag.Graph
+	if err := json.Unmarshal(w.Body.Bytes(), &g); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"in", "left", "right", "out"}; !reflect.DeepEqual(g.TopologicalOrder, want) {
+		t.Errorf("topological_order = %v, want %v", g.TopologicalOrder, want)
+	}
+	if !reflect.DeepEqual(g.Entry, []string{"in"}) || !reflect.DeepEqual(g.Terminal, []string{"out"}) {
+		t.Errorf("entry = %v, terminal = %v", g.Entry, g.Terminal)
+	}
+	if len(g.Edges) != 4 {
+		t.Errorf("got %d edges, want 4", len(g.Edges))
+	}
+	out := g.Nodes[3]
+	if out.ID != "out" || out.InDegree != 2 || out.OutDegree != 0 || !reflect.DeepEqual(out.Parents, []string{"left", "right"}) {
+		t.Errorf("out node = %+v", out)
+	}
+}
+
+func TestGetWorkflowGraph_CycleReturns422(t *testing.T) {
+	srv := newTestServer()
+	srv.repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "loop",
+		Nodes: []upal.NodeDefinition{
+			{ID: "in", Type: upal.NodeTypeInput},
+			{ID: "a", Type: upal.NodeTypeAgent},
+			{ID: "b", Type: upal.NodeTypeAgent},
+		},
+		Edges: []upal.EdgeDefinition{{From: "in", To: "a"}, {From: "a", To: "b"}, {From: "b", To: "a"}},
+	})
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/workflows/loop/graph", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("got %d, want 422", w.Code)
+	}
+	var resp struct {
+		Error string   `json:"error"`
+		Cycle []string `json:"cycle"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if want := []string{"a", "b", "a"}; !reflect.DeepEqual(resp.Cycle, want) {
+		t.Errorf("cycle = %v, want %v", resp.Cycle, want)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/workflows/missing/graph", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing workflow: got %d, want 404", w.Code)
+	}
+}
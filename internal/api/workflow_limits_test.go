@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// chainWorkflow returns a linear workflow of n agent nodes with the given prompt.
+func chainWorkflow(name string, n int, prompt string) upal.WorkflowDefinition {
+	wf := upal.WorkflowDefinition{Name: name}
+	for i := range n {
+		wf.Nodes = append(wf.Nodes, upal.NodeDefinition{
+			ID:     fmt.Sprintf("n%d", i),
+			Type:   upal.NodeTypeAgent,
+			Config: map[string]any{"model": "p/m", "prompt": prompt},
+		})
+		if i > 0 {
+			wf.Edges = append(wf.Edges, upal.EdgeDefinition{From: fmt.Sprintf("n%d", i-1), To: fmt.Sprintf("n%d", i)})
+		}
+	}
+	return wf
+}
+
+func TestWorkflowLimits_CreateAndUpdate(t *testing.T) {
+	edgesAt := chainWorkflow("edges-at", 3, "hi")
+	edgesAt.Edges = append(edgesAt.Edges, upal.EdgeDefinition{From: "n0", To: "n2"})
+	edgesOver := chainWorkflow("edges-over", 4, "hi")
+	edgesOver.Edges = append(edgesOver.Edges, upal.EdgeDefinition{From: "n0", To: "n2"})
+
+	tests := []struct {
+		name      string
+		wf        upal.WorkflowDefinition
+		wantLimit string // empty: accepted
+	}{
+		{"nodes at limit", chainWorkflow("nodes-at", 4, "hi"), ""},
+		{"nodes over limit", chainWorkflow("nodes-over", 5, "hi"), "max_nodes"},
+		{"edges at limit", edgesAt, ""},
+		{"edges over limit", edgesOver, "max_edges"},
+		{"prompt at limit", chainWorkflow("prompt-at", 1, strings.Repeat("가", 10)), ""},
+		{"prompt over limit", chainWorkflow("prompt-over", 1, strings.Repeat("가", 11)), "max_prompt_chars"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer()
+			srv.SetWorkflowLimits(upal.WorkflowLimits{MaxNodes: 4, MaxEdges: 3, MaxPromptChars: 10})
+
+			body, _ := json.Marshal(tt.wf)
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(body)))
+			checkLimitResponse(t, "create", w, tt.wantLimit)
+
+			// Update an existing small workflow to the same definition.
+			small, _ := json.Marshal(chainWorkflow(tt.wf.Name, 1, "hi"))
+			srv.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/workflows", bytes.NewReader(small)))
+			w = httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, httptest.NewRequest("PUT", "/api/workflows/"+tt.wf.Name, bytes.NewReader(body)))
+			checkLimitResponse(t, "update", w, tt.wantLimit)
+		})
+	}
+}
+
+func checkLimitResponse(t *testing.T, op string, w *httptest.ResponseRecorder, wantLimit string) {
+	t.Helper()
+	if wantLimit == "" {
+		if w.Code >= 300 {
+			t.Errorf("%s: got %d (%s), want success", op, w.Code, w.Body.String())
+		}
+		return
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("%s: got %d, want 400", op, w.Code)
+	}
+	var resp struct {
+		Error string `json:"error"`
+		Limit string `json:"limit"`
+		Max   int    `json:"max"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s: decode: %v (%s)", op, err, w.Body.String())
+	}
+	if resp.Limit != wantLimit || resp.Max == 0 || !strings.Contains(resp.Error, wantLimit) {
+		t.Errorf("%s: response = %+v, want limit %s", op, resp, wantLimit)
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+// SetNodeRunner enables POST /api/workflows/{name}/nodes/{nodeId}/test.
+func (s *Server) SetNodeRunner(r ports.NodeRunner) { s.nodeRunner = r }
+
+// testNodeRequest supplies upstream state for a single-node test run.
+// Values are keyed by node ID and stand in for {{refs}} in the node config.
+type testNodeRequest struct {
+	Values map[string]any `json:"values"`
+	DryRun bool           `json:"dry_run"`
+}
+
+// testWorkflowNode handles POST /api/workflows/{name}/nodes/{nodeId}/test.
+func (s *Server) testWorkflowNode(w http.ResponseWriter, r *http.Request) {
+	if s.nodeRunner == nil {
+		http.Error(w, "node test runs not configured", http.StatusServiceUnavailable)
+		return
+	}
+	name := chi.URLParam(r, "name")
+	nodeID := chi.URLParam(r, "nodeId")
+
+	wf, err := s.workflowSvc.Lookup(r.Context(), name)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	var node *upal.NodeDefinition
+	for i := range wf.Nodes {
+		if wf.Nodes[i].ID == nodeID {
+			node = &wf.Nodes[i]
+			break
+		}
+	}
+	if node == nil {
+		http.Error(w, "node not found", http.StatusNotFound)
+		return
+	}
+
+	var req testNodeRequest
+	if r.ContentLength != 0 {
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+	}
+
+	if node.Type == upal.NodeTypeAgent && !req.DryRun {
+		single := &upal.WorkflowDefinition{Name: wf.Name, Nodes: []upal.NodeDefinition{*node}}
+		if err := s.workflowSvc.Validate(single); err != nil {
+			writeRunValidationError(w, err)
+			return
+		}
+	}
+
+	result, err := s.nodeRunner.RunNode(r.Context(), wf, nodeID, req.Values, req.DryRun)
+	if err != nil {
+		writeJSONStatus(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, result)
+}
+
+// SetTemplateResolver enables POST /api/workflows/{name}/resolve-templates.
+func (s *Server) SetTemplateResolver(r ports.TemplateResolver) { s.templateResolver = r }
+
+// resolveTemplatesRequest supplies sample values for the workflow's input
+// nodes, keyed by node ID.
+type resolveTemplatesRequest struct {
+	Inputs map[string]any `json:"inputs"`
+}
+
+// resolveWorkflowTemplates handles POST /api/workflows/{name}/resolve-templates:
+// it returns every node's prompts with {{refs}} substituted for the sample
+// inputs, without running the workflow.
+func (s *Server) resolveWorkflowTemplates(w http.ResponseWriter, r *http.Request) {
+	if s.templateResolver == nil {
+		http.Error(w, "template resolution not configured", http.StatusServiceUnavailable)
+		return
+	}
+	wf, err := s.workflowSvc.Lookup(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+
+	var req resolveTemplatesRequest
+	if r.ContentLength != 0 {
+		if !s.decodeInputsJSON(w, r, &req) {
+			return
+		}
+	}
+
+	nodes, err := s.templateResolver.ResolveTemplates(wf, req.Inputs)
+	if err != nil {
+		writeJSONStatus(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, map[string]any{"nodes": nodes})
+}
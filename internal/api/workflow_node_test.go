@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+	"google.golang.org/adk/session"
+)
+
+func TestTestWorkflowNode(t *testing.T) {
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+	srv := NewServer(nil, wfSvc, repo, nil)
+	srv.SetNodeRunner(wfSvc)
+	repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "wf",
+		Nodes: []upal.NodeDefinition{
+			{ID: "topic", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "writer", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "stub/m", "prompt": "About {{topic}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "topic", To: "writer"}},
+	})
+
+	post := func(path string, body any) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", path, bytes.NewReader(b)))
+		return w
+	}
+
+	w := post("/api/workflows/wf/nodes/writer/test", map[string]any{"values": map[string]any{"topic": "go"}, "dry_run": true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("dry run: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var res upal.NodeTestResult
+	json.NewDecoder(w.Body).Decode(&res)
+	if res.Prompt != "About go" || !res.DryRun {
+		t.Errorf("result = %+v", res)
+	}
+
+	w = post("/api/workflows/wf/nodes/topic/test", map[string]any{"values": map[string]any{"topic": "go"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("input node: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&res)
+	if res.Output != "go" {
+		t.Errorf("input node output = %v, want go", res.Output)
+	}
+
+	if w := post("/api/workflows/wf/nodes/nope/test", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown node: expected 404, got %d", w.Code)
+	}
+	if w := post("/api/workflows/missing/nodes/writer/test", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown workflow: expected 404, got %d", w.Code)
+	}
+}
+
+func TestResolveWorkflowTemplates(t *testing.T) {
+	repo := repository.NewMemory()
+	wfSvc := services.NewWorkflowService(repo, nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+	srv := NewServer(nil, wfSvc, repo, nil)
+	srv.SetTemplateResolver(wfSvc)
+	repo.Create(context.Background(), &upal.WorkflowDefinition{
+		Name: "wf",
+		Nodes: []upal.NodeDefinition{
+			{ID: "topic", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "tone", Type: upal.NodeTypeInput, Config: map[string]any{"default": "friendly"}},
+			{ID: "writer", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "stub/m", "system_prompt": "Be concise.", "prompt": "Write a {{tone}} post about {{topic | upper}}"}},
+			{ID: "editor", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "stub/m", "prompt": "Polish: {{writer}}"}},
+			{ID: "final", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": "{{editor}}"}},
+		},
+		Edges: []upal.EdgeDefinition{
+			{From: "topic", To: "writer"}, {From: "tone", To: "writer"},
+			{From: "writer", To: "editor"}, {From: "editor", To: "final"},
+		},
+	})
+
+	body, _ := json.Marshal(map[string]any{"inputs": map[string]any{"topic": "go"}})
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/wf/resolve-templates", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Nodes []upal.ResolvedNodeTemplate `json:"nodes"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	byID := map[string]upal.ResolvedNodeTemplate{}
+	for _, n := range resp.Nodes {
+		byID[n.NodeID] = n
+	}
+	if len(resp.Nodes) != 5 {
+		t.Fatalf("nodes = %+v", resp.Nodes)
+	}
+	if got := byID["writer"]; got.Prompt != "Write a friendly post about GO" || got.SystemPrompt != "Be concise." {
+		t.Errorf("writer = %+v", got)
+	}
+	if got := byID["editor"].Prompt; got != "Polish: [writer output]" {
+		t.Errorf("editor prompt = %q", got)
+	}
+	if got := byID["final"].Output; got != "[editor output]" {
+		t.Errorf("final output = %v", got)
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/workflows/missing/resolve-templates", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown workflow: expected 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/soochol/upal/internal/services"
+)
+
+const defaultSuggestLimit = 5
+
+func (s *Server) SetWorkflowSuggestService(svc *services.WorkflowSuggestService) {
+	s.workflowSuggestSvc = svc
+}
+
+// suggestWorkflows handles POST /api/workflows/suggest, returning existing
+// workflows similar to a task description.
+func (s *Server) suggestWorkflows(w http.ResponseWriter, r *http.Request) {
+	if s.workflowSuggestSvc == nil {
+		http.Error(w, "workflow suggestions not available", http.StatusServiceUnavailable)
+		return
+	}
+	var req struct {
+		Description string `json:"description"`
+		Limit       int    `json:"limit"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		http.Error(w, "description is required", http.StatusBadRequest)
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultSuggestLimit
+	}
+	suggestions, err := s.workflowSuggestSvc.Suggest(r.Context(), req.Description, req.Limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, orEmpty(suggestions))
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// WebhookSecretRequest is the optional body of PUT
+// /api/workflows/{name}/webhook-secret. An empty Secret generates one.
+type WebhookSecretRequest struct {
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookSecretResponse tells an integration how to sign ad-hoc runs: an
+// HMAC-SHA256 of the request body, hex encoded, in SignatureHeader. RunURL
+// names the workflow's owner, since signed runs carry no bearer token.
+type WebhookSecretResponse struct {
+	Secret          string `json:"secret"`
+	SignatureHeader string `json:"signature_header"`
+	RunURL          string `json:"run_url"`
+}
+
+// setWorkflowWebhookSecret handles PUT /api/workflows/{name}/webhook-secret,
+// setting or rotating the secret that signs ad-hoc runs of the workflow.
+func (s *Server) setWorkflowWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	var req WebhookSecretRequest
+	if r.ContentLength != 0 && !decodeJSON(w, r, &req) {
+		return
+	}
+	wf, err := s.repo.Get(r.Context(), name)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	if req.Secret == "" {
+		b := make([]byte, 32)
+		rand.Read(b)
+		req.Secret = "whsec_" + hex.EncodeToString(b)
+	}
+	wf.WebhookSecret = req.Secret
+	if err := s.repo.Update(r.Context(), name, wf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	runURL := s.publicBaseURL(r, "") + "/api/workflows/" + url.PathEscape(name) + "/run"
+	if owner := upal.UserIDFromContext(r.Context()); owner != "default" {
+		runURL += "?owner=" + url.QueryEscape(owner)
+	}
+	writeJSON(w, WebhookSecretResponse{
+		Secret:          wf.WebhookSecret,
+		SignatureHeader: upal.DefaultSignatureHeader,
+		RunURL:          runURL,
+	})
+}
+
+// clearWorkflowWebhookSecret handles DELETE
+// /api/workflows/{name}/webhook-secret; signed runs are rejected afterwards.
+func (s *Server) clearWorkflowWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	wf, err := s.repo.Get(r.Context(), name)
+	if err != nil {
+		http.Error(w, "workflow not found", http.StatusNotFound)
+		return
+	}
+	wf.WebhookSecret = ""
+	if err := s.repo.Update(r.Context(), name, wf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+func signBody(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postRun(srv *Server, name, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/workflows/"+name+"/run", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		req.Header.Set(upal.DefaultSignatureHeader, signature)
+	}
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	return w
+}
+
+func TestRunWorkflow_SignedAdHocRun(t *testing.T) {
+	srv := newTestServer()
+	ctx := context.Background()
+	srv.repo.Create(ctx, &upal.WorkflowDefinition{
+		Name: "echo",
+		Nodes: []upal.NodeDefinition{
+			{ID: "question", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "answer", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "question", To: "answer"}},
+	})
+
+	// Signing before a secret is configured is rejected.
+	body := `{"question":"ping"}`
+	if w := postRun(srv, "echo", body, signBody("anything", body)); w.Code != http.StatusUnauthorized {
+		t.Fatalf("signed run without secret: got %d", w.Code)
+	}
+
+	w := doJSON(srv, "PUT", "/api/workflows/echo/webhook-secret", `{"secret":"s3cret"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("set secret: got %d: %s", w.Code, w.Body.String())
+	}
+	var secretResp WebhookSecretResponse
+	json.Unmarshal(w.Body.Bytes(), &secretResp)
+	if secretResp.Secret != "s3cret" || secretResp.SignatureHeader != upal.DefaultSignatureHeader ||
+		!strings.HasSuffix(secretResp.RunURL, "/api/workflows/echo/run") {
+		t.Errorf("unexpected secret response: %+v", secretResp)
+	}
+
+	// Inputs are posted directly, without a trigger or RunRequest wrapper.
+	w = postRun(srv, "echo", body, signBody("s3cret", body))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("signed run: got %d: %s", w.Code, w.Body.String())
+	}
+	var result map[string]string
+	json.Unmarshal(w.Body.Bytes(), &result)
+	run, err := srv.runHistorySvc.GetRun(ctx, result["run_id"])
+	if err != nil {
+		t.Fatalf("run record: %v", err)
+	}
+	if run.Inputs["question"] != "ping" {
+		t.Errorf("run inputs = %v", run.Inputs)
+	}
+
+	// The signature is optional: authenticated unsigned runs still work.
+	if w := postRun(srv, "echo", body, ""); w.Code != http.StatusAccepted {
+		t.Errorf("unsigned run with secret set: got %d", w.Code)
+	}
+	// The secret never appears in API responses.
+	if w := doJSON(srv, "GET", "/api/workflows/echo", ""); strings.Contains(w.Body.String(), "s3cret") {
+		t.Errorf("workflow response leaks the secret: %s", w.Body.String())
+	}
+	if w := doJSON(srv, "GET", "/api/runs/"+result["run_id"], ""); strings.Contains(w.Body.String(), "s3cret") {
+		t.Errorf("run response leaks the secret: %s", w.Body.String())
+	}
+
+	if w := postRun(srv, "echo", body, signBody("wrong", body)); w.Code != http.StatusUnauthorized {
+		t.Errorf("bad signature: got %d", w.Code)
+	}
+	tampered := `{"question":"pong"}`
+	if w := postRun(srv, "echo", tampered, signBody("s3cret", body)); w.Code != http.StatusUnauthorized {
+		t.Errorf("tampered body: got %d", w.Code)
+	}
+	inline := `{"inputs":{},"workflow":{"name":"echo","nodes":[]}}`
+	if w := postRun(srv, "echo", inline, signBody("s3cret", inline)); w.Code != http.StatusBadRequest {
+		t.Errorf("signed inline workflow: got %d", w.Code)
+	}
+
+	// Editor saves do not drop the secret; DELETE does.
+	doJSON(srv, "PUT", "/api/workflows/echo", `{"name":"echo","nodes":[{"id":"question","type":"input","config":{}}]}`)
+	if wf, _ := srv.repo.Get(ctx, "echo"); wf.WebhookSecret != "s3cret" {
+		t.Errorf("secret lost on update: %q", wf.WebhookSecret)
+	}
+	if w := doJSON(srv, "DELETE", "/api/workflows/echo/webhook-secret", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("clear secret: got %d", w.Code)
+	}
+	if w := postRun(srv, "echo", body, signBody("s3cret", body)); w.Code != http.StatusUnauthorized {
+		t.Errorf("signed run after clearing: got %d", w.Code)
+	}
+	if w := postRun(srv, "echo", body, ""); w.Code != http.StatusAccepted {
+		t.Errorf("unsigned run after clearing: got %d", w.Code)
+	}
+}
+
+func TestAuthMiddleware_SignedRunSkipsBearerAuth(t *testing.T) {
+	var gotUser string
+	handler := AuthMiddleware(newTestAuthService())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = upal.UserIDFromContext(r.Context())
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	req := httptest.NewRequest("POST", "/api/workflows/echo/run?owner=user-1", strings.NewReader("{}"))
+	req.Header.Set(upal.DefaultSignatureHeader, "sha256=abc")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted || gotUser != "user-1" {
+		t.Errorf("signed run: got %d as %q, want 202 as user-1", w.Code, gotUser)
+	}
+
+	// Unsigned runs and signed requests to other routes still need a token.
+	unsigned := httptest.NewRequest("POST", "/api/workflows/echo/run", nil)
+	otherRoute := httptest.NewRequest("POST", "/api/workflows/echo/preview", nil)
+	otherRoute.Header.Set(upal.DefaultSignatureHeader, "sha256=abc")
+	for _, req := range []*http.Request{unsigned, otherRoute} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: got %d, want 401", req.URL.Path, w.Code)
+		}
+	}
+}
+
+func TestRunWorkflow_BearerRunOfWorkflowWithSecret(t *testing.T) {
+	srv := newTestServer()
+	srv.SetAuthService(newTestAuthService())
+	ctx := upal.WithUserID(context.Background(), "user-42")
+	srv.repo.Create(ctx, &upal.WorkflowDefinition{
+		Name:          "echo",
+		WebhookSecret: "s3cret",
+		Nodes: []upal.NodeDefinition{
+			{ID: "question", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "answer", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "question", To: "answer"}},
+	})
+	token, _, err := srv.authSvc.GenerateTokens(context.Background(), &upal.User{ID: "user-42"}, "")
+	if err != nil {
+		t.Fatalf("GenerateTokens: %v", err)
+	}
+
+	// Like the editor's Run button: a bearer token and no signature.
+	req := httptest.NewRequest("POST", "/api/workflows/echo/run", strings.NewReader(`{"inputs":{"question":"ping"}}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("bearer run: got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Without a token the run must be signed.
+	if w := postRun(srv, "echo", `{}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous unsigned run: got %d, want 401", w.Code)
+	}
+	if w := postRun(srv, "echo", `{}`, signBody("wrong", `{}`)); w.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous badly signed run: got %d, want 401", w.Code)
+	}
+}
+
+func TestRunWorkflow_GeneratesWebhookSecret(t *testing.T) {
+	srv := newTestServer()
+	srv.repo.Create(context.Background(), &upal.WorkflowDefinition{Name: "wf"})
+	w := doJSON(srv, "PUT", "/api/workflows/wf/webhook-secret", "")
+	var resp WebhookSecretResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || !strings.HasPrefix(resp.Secret, "whsec_") {
+		t.Errorf("got %d %+v", w.Code, resp)
+	}
+	if w := doJSON(srv, "PUT", "/api/workflows/missing/webhook-secret", ""); w.Code != http.StatusNotFound {
+		t.Errorf("missing workflow: got %d", w.Code)
+	}
+}
@@ -8,25 +8,41 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/soochol/upal/internal/logging"
 	"github.com/soochol/upal/internal/upal"
 	"gopkg.in/yaml.v3"
 )
 
 // Config holds the top-level application configuration.
 type Config struct {
-	Server    ServerConfig              `yaml:"server"`
-	Database  DatabaseConfig            `yaml:"database"`
-	Auth      AuthConfig                `yaml:"auth"`
-	Providers map[string]ProviderConfig `yaml:"providers"`
-	Scheduler upal.ConcurrencyLimits    `yaml:"scheduler"`
-	Runs      RunsConfig                `yaml:"runs"`
-	Generator GeneratorConfig           `yaml:"generator"`
+	Server       ServerConfig              `yaml:"server"`
+	Database     DatabaseConfig            `yaml:"database"`
+	Auth         AuthConfig                `yaml:"auth"`
+	Providers    map[string]ProviderConfig `yaml:"providers"`
+	Scheduler    SchedulerConfig           `yaml:"scheduler"`
+	Runs         RunsConfig                `yaml:"runs"`
+	Generator    GeneratorConfig           `yaml:"generator"`
+	ContentStore ContentStoreConfig        `yaml:"content_store"`
+	// CircuitBreaker applies to every LLM provider.
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+	Webhooks       WebhookConfig        `yaml:"webhooks"`
+	Embeddings     EmbeddingsConfig     `yaml:"embeddings"`
+	Layout         LayoutConfig         `yaml:"layout"`
+	Moderation     ModerationConfig     `yaml:"moderation"`
+	// WorkflowLimits bounds workflow size on create/update and generation.
+	WorkflowLimits upal.WorkflowLimits `yaml:"workflow_limits"`
+	Warmup         WarmupConfig        `yaml:"warmup"`
+	Tools          ToolsConfig         `yaml:"tools"`
+	Logging        LoggingConfig       `yaml:"logging"`
 }
 
 type AuthConfig struct {
 	Google    OAuthProviderConfig `yaml:"google"`
 	GitHub    OAuthProviderConfig `yaml:"github"`
 	JWTSecret string             `yaml:"jwt_secret"`
+	// Admins lists the user IDs or emails allowed to use /api/admin. With
+	// auth disabled the single default user is always an admin.
+	Admins []string `yaml:"admins"`
 }
 
 type OAuthProviderConfig struct {
@@ -44,16 +60,174 @@ type ServerConfig struct {
 	Port          int      `yaml:"port"`
 	UploadMaxSize int64    `yaml:"upload_max_size"`
 	CORSOrigins   []string `yaml:"cors_origins"`
+	// PublicURL is the externally reachable base URL (e.g. behind a reverse
+	// proxy). When empty it is derived from X-Forwarded-* request headers.
+	PublicURL string `yaml:"public_url"`
+	// Timeouts bound synchronous API requests; see RequestTimeoutConfig.
+	Timeouts RequestTimeoutConfig `yaml:"timeouts"`
+	// BasePath mounts every route under a sub-path (e.g. "/upal") when the
+	// server shares a domain behind a reverse proxy. Advertised URLs such as
+	// the A2A agent card and webhook URLs include it.
+	BasePath string `yaml:"base_path"`
+}
+
+// BasePathPrefix returns BasePath as "/segment[/segment...]" without a
+// trailing slash, or "" when the server is mounted at the root.
+func (c ServerConfig) BasePathPrefix() string {
+	p := strings.Trim(strings.TrimSpace(c.BasePath), "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+// RequestTimeoutConfig holds per-route API request timeouts. Long applies to
+// endpoints that do model or workflow work inline (generation, node tests,
+// sync webhooks, ...), Default to everything else. Event streams and file
+// downloads are exempt, and runs launched in the background are unaffected.
+// Zero disables the respective timeout. Preview bounds a streamed workflow
+// preview run; zero means the two minute default.
+type RequestTimeoutConfig struct {
+	Default time.Duration `yaml:"default"`
+	Long    time.Duration `yaml:"long"`
+	Preview time.Duration `yaml:"preview"`
+}
+
+// SchedulerConfig holds scheduler concurrency limits and schedule housekeeping settings.
+type SchedulerConfig struct {
+	upal.ConcurrencyLimits `yaml:",inline"`
+	// AutoPauseAfter pauses a schedule after this many consecutive runs whose
+	// workflow could not be found. Zero disables auto-pausing.
+	AutoPauseAfter int `yaml:"auto_pause_after"`
+	// Queue routes scheduled ticks through a Postgres-backed run queue so a
+	// tick interrupted by a crash is re-run by another worker once its lease
+	// (QueueLease, default one minute) expires. Requires a database.
+	Queue      bool          `yaml:"queue"`
+	QueueLease time.Duration `yaml:"queue_lease"`
+	// SaturationAlert notifies a connection when active runs stay at
+	// GlobalMax for longer than After. Zero After disables the alert.
+	SaturationAlert SaturationAlertConfig `yaml:"saturation_alert"`
+	// DefaultTimezone is the IANA timezone given to schedules created without
+	// one. Empty means UTC.
+	DefaultTimezone string `yaml:"default_timezone"`
+}
+
+// SaturationAlertConfig configures the concurrency saturation notification.
+type SaturationAlertConfig struct {
+	After        time.Duration `yaml:"after"`
+	ConnectionID string        `yaml:"connection_id"`
 }
 
 // RunsConfig holds run manager settings.
 type RunsConfig struct {
 	TTL time.Duration `yaml:"ttl"`
+	// Heartbeat is the interval of ": keep-alive" comments on run event
+	// streams (default 15s). Zero disables them.
+	Heartbeat time.Duration `yaml:"heartbeat"`
+	// FloatNumbers decodes numbers in run request and webhook inputs as
+	// float64, as before, instead of json.Number. Integers beyond 2^53 then
+	// lose precision.
+	FloatNumbers bool `yaml:"float_numbers"`
 }
 
-// GeneratorConfig holds generation-related timeouts.
+// GeneratorConfig holds generation-related settings.
 type GeneratorConfig struct {
 	ThumbnailTimeout time.Duration `yaml:"thumbnail_timeout"`
+	// Language is the default language for user-facing text in generated
+	// workflows and pipelines: a code ("ko", "en", ...) or a language name.
+	Language string `yaml:"language"`
+}
+
+// ContentStoreConfig selects the persistence backend of the content_store tool.
+type ContentStoreConfig struct {
+	Backend string `yaml:"backend"` // "file" (default) | "postgres"
+}
+
+// Webhook saturation behaviors for WebhookConfig.OnSaturation.
+const (
+	WebhookSaturationReject = "reject"
+	WebhookSaturationQueue  = "queue"
+)
+
+// WebhookConfig controls how webhook triggers behave when the concurrency
+// limiter is saturated: "reject" answers 503 with Retry-After, "queue" accepts
+// the call and waits for a free slot in the background.
+type WebhookConfig struct {
+	OnSaturation string `yaml:"on_saturation"`
+	// FireN mounts POST /api/triggers/{id}/fire-n, a load-testing endpoint
+	// that fires a trigger many times; only auth.admins may call it. Leave
+	// off outside development.
+	FireN bool `yaml:"fire_n"`
+}
+
+// EmbeddingsConfig selects the provider used for embedding-based features
+// such as workflow suggestions. Provider names an entry in Providers that
+// speaks the OpenAI-compatible /embeddings API. Empty disables embeddings.
+type EmbeddingsConfig struct {
+	Provider string `yaml:"provider"`
+	Model    string `yaml:"model"`
+}
+
+// ModerationConfig selects the provider that screens run inputs for
+// workflows with moderation enabled. Provider names an entry in Providers that
+// speaks the OpenAI-compatible /moderations API. Empty disables moderation.
+type ModerationConfig struct {
+	Provider string `yaml:"provider"`
+	Model    string `yaml:"model"`
+}
+
+// LayoutConfig controls HTML layout generation by output nodes. Model
+// ("provider/model") is used when a node sets no layout_model of its own;
+// Disabled makes every output node return plain text, avoiding the LLM call.
+type LayoutConfig struct {
+	Model    string `yaml:"model"`
+	Disabled bool   `yaml:"disabled"`
+}
+
+// LoggingConfig sets the initial log verbosity and output format. Level is
+// "debug", "info", "warn" or "error" and can be changed while running via
+// POST /api/admin/log-level; Format is "text" (default) or "json".
+type LoggingConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// ToolsConfig holds settings for custom tools called by agents.
+type ToolsConfig struct {
+	// RateLimits caps a tool, by name, at Calls executions per Per window.
+	// Calls past the cap return a throttled error to the model.
+	RateLimits map[string]ToolRateLimit `yaml:"rate_limits"`
+	// PublishConnection names the stored connection (http, webhook or s3)
+	// the publish tool writes markdown files to. Empty keeps the local
+	// data/published directory.
+	PublishConnection string `yaml:"publish_connection"`
+}
+
+// ToolRateLimit is a sliding-window call cap for one tool.
+type ToolRateLimit struct {
+	Calls int           `yaml:"calls"`
+	Per   time.Duration `yaml:"per"`
+}
+
+// WarmupConfig controls warm-up requests to local providers (Ollama), which
+// load a model into memory on its first call. With Enabled, every local
+// provider gets one tiny request at startup; POST /api/providers/{name}/warm
+// re-warms on demand either way. Models picks the model per provider name;
+// otherwise the first model the provider reports is used. Timeout bounds each
+// warm-up request (default one minute).
+type WarmupConfig struct {
+	Enabled bool              `yaml:"enabled"`
+	Models  map[string]string `yaml:"models"`
+	Timeout time.Duration     `yaml:"timeout"`
+}
+
+// CircuitBreakerConfig controls per-provider circuit breaking. After Threshold
+// consecutive provider failures within Window, calls fail fast for Cooldown.
+// A zero Threshold disables the breaker.
+type CircuitBreakerConfig struct {
+	Threshold int           `yaml:"threshold"`
+	Window    time.Duration `yaml:"window"`
+	Cooldown  time.Duration `yaml:"cooldown"`
 }
 
 // DatabaseConfig holds database connection settings.
@@ -66,6 +240,11 @@ type ProviderConfig struct {
 	Type   string `yaml:"type"`    // e.g. "openai"
 	URL    string `yaml:"url"`     // base URL
 	APIKey string `yaml:"api_key"` // API key
+	// APIVersion overrides the provider's pinned API version (Anthropic's
+	// anthropic-version header). Empty keeps the adapter default.
+	APIVersion string `yaml:"api_version"`
+	// Headers are extra HTTP headers sent with every request to the provider.
+	Headers map[string]string `yaml:"headers"`
 }
 
 // defaults returns a Config populated with sensible default values.
@@ -75,14 +254,38 @@ func defaults() *Config {
 			Host:          "0.0.0.0",
 			Port:          8080,
 			UploadMaxSize: 50 << 20, // 50 MB
+			Timeouts: RequestTimeoutConfig{
+				Default: 30 * time.Second,
+				Long:    10 * time.Minute,
+			},
 		},
 		Database:  DatabaseConfig{},
 		Providers: map[string]ProviderConfig{},
+		Scheduler: SchedulerConfig{
+			AutoPauseAfter: 5,
+		},
 		Runs: RunsConfig{
-			TTL: 15 * time.Minute,
+			TTL:       15 * time.Minute,
+			Heartbeat: 15 * time.Second,
 		},
 		Generator: GeneratorConfig{
 			ThumbnailTimeout: 60 * time.Second,
+			Language:         "ko",
+		},
+		ContentStore: ContentStoreConfig{
+			Backend: "file",
+		},
+		CircuitBreaker: CircuitBreakerConfig{
+			Threshold: 5,
+			Window:    time.Minute,
+			Cooldown:  30 * time.Second,
+		},
+		Webhooks: WebhookConfig{
+			OnSaturation: WebhookSaturationReject,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "text",
 		},
 	}
 }
@@ -104,6 +307,19 @@ func Load(path string) (*Config, error) {
 		cfg.Providers = map[string]ProviderConfig{}
 	}
 
+	if tz := cfg.Scheduler.DefaultTimezone; tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("invalid scheduler.default_timezone %q: %w", tz, err)
+		}
+	}
+
+	if _, err := logging.ParseLevel(cfg.Logging.Level); err != nil {
+		return nil, fmt.Errorf("invalid logging.level: %w", err)
+	}
+	if f := strings.ToLower(cfg.Logging.Format); f != "" && f != logging.FormatText && f != logging.FormatJSON {
+		return nil, fmt.Errorf("invalid logging.format %q: want %q or %q", f, logging.FormatText, logging.FormatJSON)
+	}
+
 	return cfg, nil
 }
 
@@ -128,6 +344,7 @@ func LoadDefault() (*Config, error) {
 //
 // Supported variables:
 //   - DATABASE_URL         → cfg.Database.URL
+//   - PUBLIC_URL           → cfg.Server.PublicURL
 //   - {PROVIDER}_API_KEY   → cfg.Providers[provider].APIKey
 //     (provider name uppercased, hyphens replaced with underscores)
 func applyEnvOverrides(cfg *Config) {
@@ -145,6 +362,10 @@ func applyEnvOverrides(cfg *Config) {
 		}
 	}
 
+	if v := os.Getenv("PUBLIC_URL"); v != "" {
+		cfg.Server.PublicURL = v
+	}
+
 	if v := os.Getenv("CORS_ORIGINS"); v != "" {
 		cfg.Server.CORSOrigins = strings.Split(v, ",")
 	}
@@ -165,3 +386,44 @@ func applyEnvOverrides(cfg *Config) {
 		cfg.Auth.JWTSecret = v
 	}
 }
+
+// secretKeys are config keys whose values never leave the process.
+var secretKeys = map[string]bool{
+	"api_key":       true,
+	"jwt_secret":    true,
+	"client_secret": true,
+	"password":      true,
+	"token":         true,
+}
+
+// Redacted returns the config as a generic map with secrets masked, suitable
+// for attaching to run records. The database URL is masked too since it
+// usually embeds credentials.
+func (c *Config) Redacted() map[string]any {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	redactSecrets(m)
+	if db, ok := m["database"].(map[string]any); ok && db["url"] != "" {
+		db["url"] = "[redacted]"
+	}
+	return m
+}
+
+func redactSecrets(m map[string]any) {
+	for k, v := range m {
+		switch v := v.(type) {
+		case map[string]any:
+			redactSecrets(v)
+		default:
+			if secretKeys[k] && v != "" && v != nil {
+				m[k] = "[redacted]"
+			}
+		}
+	}
+}
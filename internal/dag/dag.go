@@ -3,6 +3,7 @@ package dag
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/soochol/upal/internal/upal"
 )
@@ -89,11 +90,74 @@ func (d *DAG) topoSort() ([]string, error) {
 		sort.Strings(queue)
 	}
 	if len(order) != len(d.nodes) {
-		return nil, fmt.Errorf("cycle detected in workflow graph (excluding back-edges)")
+		return nil, &CycleError{Cycle: d.findCycle(inDegree)}
 	}
 	return order, nil
 }
 
+// CycleError reports a cycle among forward edges. Cycle lists the node IDs
+// along it, starting and ending at the same node.
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return "cycle detected in workflow graph (excluding back-edges): " + strings.Join(e.Cycle, " -> ")
+}
+
+// findCycle walks the nodes topoSort could not order (positive remaining
+// in-degree) and returns the first cycle found, visiting IDs in sorted order
+// so the result is stable.
+func (d *DAG) findCycle(inDegree map[string]int) []string {
+	var stuck []string
+	for id, deg := range inDegree {
+		if deg > 0 {
+			stuck = append(stuck, id)
+		}
+	}
+	sort.Strings(stuck)
+
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = onPath
+		path = append(path, id)
+		children := append([]string(nil), d.children[id]...)
+		sort.Strings(children)
+		for _, c := range children {
+			switch state[c] {
+			case onPath:
+				for i, p := range path {
+					if p == c {
+						return append(append([]string(nil), path[i:]...), c)
+					}
+				}
+			case unvisited:
+				if cycle := visit(c); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+	for _, id := range stuck {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
 func (d *DAG) TopologicalOrder() []string        { return d.topoOrder }
 func (d *DAG) Children(nodeID string) []string    { return d.children[nodeID] }
 func (d *DAG) Parents(nodeID string) []string     { return d.parents[nodeID] }
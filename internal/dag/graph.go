@@ -0,0 +1,73 @@
+package dag
+
+import (
+	"sort"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// Graph is a normalized view of a workflow's DAG for API clients. Degrees,
+// parents and children count forward edges only; loop back-edges are listed
+// in Edges but do not affect ordering.
+type Graph struct {
+	Nodes            []GraphNode           `json:"nodes"`
+	Edges            []upal.EdgeDefinition `json:"edges"`
+	TopologicalOrder []string              `json:"topological_order"`
+	Entry            []string              `json:"entry"`    // nodes without parents
+	Terminal         []string              `json:"terminal"` // nodes without children
+}
+
+// GraphNode is one node of a Graph with its adjacency.
+type GraphNode struct {
+	ID        string        `json:"id"`
+	Type      upal.NodeType `json:"type"`
+	InDegree  int           `json:"in_degree"`
+	OutDegree int           `json:"out_degree"`
+	Parents   []string      `json:"parents"`
+	Children  []string      `json:"children"`
+}
+
+// Analyze builds wf's DAG and returns its normalized Graph. A cycle among
+// forward edges yields a *CycleError.
+func Analyze(wf *upal.WorkflowDefinition) (*Graph, error) {
+	d, err := Build(wf)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{
+		Nodes:            make([]GraphNode, 0, len(wf.Nodes)),
+		Edges:            wf.Edges,
+		TopologicalOrder: d.TopologicalOrder(),
+		Entry:            []string{},
+		Terminal:         []string{},
+	}
+	if g.Edges == nil {
+		g.Edges = []upal.EdgeDefinition{}
+	}
+	for _, id := range d.TopologicalOrder() {
+		parents := sortedCopy(d.Parents(id))
+		children := sortedCopy(d.Children(id))
+		g.Nodes = append(g.Nodes, GraphNode{
+			ID:        id,
+			Type:      d.Node(id).Type,
+			InDegree:  len(parents),
+			OutDegree: len(children),
+			Parents:   parents,
+			Children:  children,
+		})
+		if len(parents) == 0 {
+			g.Entry = append(g.Entry, id)
+		}
+		if len(children) == 0 {
+			g.Terminal = append(g.Terminal, id)
+		}
+	}
+	return g, nil
+}
+
+func sortedCopy(ids []string) []string {
+	out := append([]string{}, ids...)
+	sort.Strings(out)
+	return out
+}
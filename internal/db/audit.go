@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// CreateAuditEntry appends an entry to the audit log.
+func (d *DB) CreateAuditEntry(ctx context.Context, e *upal.AuditEntry) error {
+	changesJSON, _ := json.Marshal(e.Changes)
+	_, err := d.Pool.ExecContext(ctx,
+		`INSERT INTO audit_log (id, actor, action, resource_type, resource_id, method, path, status, changes, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		e.ID, e.Actor, string(e.Action), e.ResourceType, e.ResourceID, e.Method, e.Path, e.Status, changesJSON, e.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEntries returns an actor's audit entries matching filter, newest first.
+func (d *DB) ListAuditEntries(ctx context.Context, actor string, filter upal.AuditFilter) ([]*upal.AuditEntry, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := d.Pool.QueryContext(ctx,
+		`SELECT id, actor, action, resource_type, resource_id, method, path, status, changes, created_at
+		 FROM audit_log
+		 WHERE actor = $1
+		   AND ($2 = '' OR resource_type = $2)
+		   AND ($3 = '' OR resource_id = $3)
+		   AND ($4 = '' OR action = $4)
+		 ORDER BY created_at DESC LIMIT $5`,
+		actor, filter.ResourceType, filter.ResourceID, string(filter.Action), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*upal.AuditEntry
+	for rows.Next() {
+		e := &upal.AuditEntry{}
+		var action string
+		var changesJSON []byte
+		if err := rows.Scan(&e.ID, &e.Actor, &action, &e.ResourceType, &e.ResourceID,
+			&e.Method, &e.Path, &e.Status, &changesJSON, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		e.Action = upal.AuditAction(action)
+		json.Unmarshal(changesJSON, &e.Changes)
+		result = append(result, e)
+	}
+	return result, nil
+}
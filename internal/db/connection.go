@@ -12,9 +12,10 @@ import (
 func (d *DB) CreateConnection(ctx context.Context, userID string, c *upal.Connection) error {
 	extrasJSON, _ := json.Marshal(c.Extras)
 	_, err := d.Pool.ExecContext(ctx,
-		`INSERT INTO connections (id, user_id, name, type, host, port, login, password, token, extras)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		`INSERT INTO connections (id, user_id, name, type, host, port, login, password, token, extras, refresh_token, token_url, token_expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
 		c.ID, userID, c.Name, string(c.Type), c.Host, c.Port, c.Login, c.Password, c.Token, extrasJSON,
+		c.RefreshToken, c.TokenURL, c.TokenExpiresAt,
 	)
 	if err != nil {
 		return fmt.Errorf("insert connection: %w", err)
@@ -28,8 +29,9 @@ func (d *DB) GetConnection(ctx context.Context, userID string, id string) (*upal
 	var connType string
 
 	err := d.Pool.QueryRowContext(ctx,
-		`SELECT id, name, type, host, port, login, password, token, extras FROM connections WHERE id = $1 AND user_id = $2`, id, userID,
-	).Scan(&c.ID, &c.Name, &connType, &c.Host, &c.Port, &c.Login, &c.Password, &c.Token, &extrasJSON)
+		`SELECT id, name, type, host, port, login, password, token, extras, refresh_token, token_url, token_expires_at FROM connections WHERE id = $1 AND user_id = $2`, id, userID,
+	).Scan(&c.ID, &c.Name, &connType, &c.Host, &c.Port, &c.Login, &c.Password, &c.Token, &extrasJSON,
+		&c.RefreshToken, &c.TokenURL, &c.TokenExpiresAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("connection not found: %s", id)
 	}
@@ -44,7 +46,7 @@ func (d *DB) GetConnection(ctx context.Context, userID string, id string) (*upal
 
 func (d *DB) ListConnections(ctx context.Context, userID string) ([]*upal.Connection, error) {
 	rows, err := d.Pool.QueryContext(ctx,
-		`SELECT id, name, type, host, port, login, password, token, extras FROM connections WHERE user_id = $1 ORDER BY name`, userID,
+		`SELECT id, name, type, host, port, login, password, token, extras, refresh_token, token_url, token_expires_at FROM connections WHERE user_id = $1 ORDER BY name`, userID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list connections: %w", err)
@@ -57,7 +59,8 @@ func (d *DB) ListConnections(ctx context.Context, userID string) ([]*upal.Connec
 		var extrasJSON []byte
 		var connType string
 
-		if err := rows.Scan(&c.ID, &c.Name, &connType, &c.Host, &c.Port, &c.Login, &c.Password, &c.Token, &extrasJSON); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &connType, &c.Host, &c.Port, &c.Login, &c.Password, &c.Token, &extrasJSON,
+			&c.RefreshToken, &c.TokenURL, &c.TokenExpiresAt); err != nil {
 			return nil, fmt.Errorf("scan connection: %w", err)
 		}
 		c.Type = upal.ConnectionType(connType)
@@ -70,8 +73,10 @@ func (d *DB) ListConnections(ctx context.Context, userID string) ([]*upal.Connec
 func (d *DB) UpdateConnection(ctx context.Context, userID string, c *upal.Connection) error {
 	extrasJSON, _ := json.Marshal(c.Extras)
 	_, err := d.Pool.ExecContext(ctx,
-		`UPDATE connections SET name=$1, type=$2, host=$3, port=$4, login=$5, password=$6, token=$7, extras=$8 WHERE id=$9 AND user_id=$10`,
-		c.Name, string(c.Type), c.Host, c.Port, c.Login, c.Password, c.Token, extrasJSON, c.ID, userID,
+		`UPDATE connections SET name=$1, type=$2, host=$3, port=$4, login=$5, password=$6, token=$7, extras=$8,
+		 refresh_token=$9, token_url=$10, token_expires_at=$11 WHERE id=$12 AND user_id=$13`,
+		c.Name, string(c.Type), c.Host, c.Port, c.Login, c.Password, c.Token, extrasJSON,
+		c.RefreshToken, c.TokenURL, c.TokenExpiresAt, c.ID, userID,
 	)
 	if err != nil {
 		return fmt.Errorf("update connection: %w", err)
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// GetContentStoreValue returns the value stored under key.
+// found is false when the key does not exist.
+func (d *DB) GetContentStoreValue(ctx context.Context, userID, key string) (value string, found bool, err error) {
+	err = d.Pool.QueryRowContext(ctx,
+		`SELECT value FROM content_store WHERE user_id = $1 AND key = $2`, userID, key,
+	).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("get content store value: %w", err)
+	}
+	return value, true, nil
+}
+
+// SetContentStoreValue inserts or replaces the value stored under key.
+func (d *DB) SetContentStoreValue(ctx context.Context, userID, key, value string) error {
+	_, err := d.Pool.ExecContext(ctx,
+		`INSERT INTO content_store (user_id, key, value, updated_at)
+		 VALUES ($1, $2, $3, NOW())
+		 ON CONFLICT (user_id, key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()`,
+		userID, key, value,
+	)
+	if err != nil {
+		return fmt.Errorf("set content store value: %w", err)
+	}
+	return nil
+}
+
+// ListContentStoreKeys returns keys starting with prefix, sorted ascending.
+func (d *DB) ListContentStoreKeys(ctx context.Context, userID, prefix string) ([]string, error) {
+	rows, err := d.Pool.QueryContext(ctx,
+		`SELECT key FROM content_store WHERE user_id = $1 AND starts_with(key, $2) ORDER BY key`,
+		userID, prefix,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list content store keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, fmt.Errorf("scan content store key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// DeleteContentStoreValue removes key. Deleting a missing key is not an error.
+func (d *DB) DeleteContentStoreValue(ctx context.Context, userID, key string) error {
+	_, err := d.Pool.ExecContext(ctx,
+		`DELETE FROM content_store WHERE user_id = $1 AND key = $2`, userID, key,
+	)
+	if err != nil {
+		return fmt.Errorf("delete content store value: %w", err)
+	}
+	return nil
+}
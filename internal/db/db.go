@@ -3,12 +3,17 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+
+	"github.com/soochol/upal/internal/upal"
 )
 
 // DB wraps a database/sql connection pool for PostgreSQL.
 type DB struct {
 	Pool *sql.DB
+
+	floatNumbers bool
 }
 
 // New creates a new database connection.
@@ -30,6 +35,20 @@ func New(ctx context.Context, databaseURL string) (*DB, error) {
 	return &DB{Pool: pool}, nil
 }
 
+// SetFloatNumbers makes stored run and schedule inputs decode numbers as
+// float64 instead of json.Number, matching the API's runs.float_numbers
+// setting.
+func (d *DB) SetFloatNumbers(enabled bool) { d.floatNumbers = enabled }
+
+// unmarshalInputs decodes stored run or schedule inputs according to
+// SetFloatNumbers.
+func (d *DB) unmarshalInputs(data []byte, v any) error {
+	if d.floatNumbers {
+		return json.Unmarshal(data, v)
+	}
+	return upal.UnmarshalInputs(data, v)
+}
+
 // Close closes the connection pool.
 func (d *DB) Close() error {
 	return d.Pool.Close()
@@ -72,6 +91,7 @@ ALTER TABLE workflows ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT 'de
 -- Drop old single-column unique constraint and replace with per-user unique
 ALTER TABLE workflows DROP CONSTRAINT IF EXISTS workflows_name_key;
 CREATE UNIQUE INDEX IF NOT EXISTS idx_workflows_user_name ON workflows(user_id, name);
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS webhook_secret TEXT NOT NULL DEFAULT '';
 
 CREATE TABLE IF NOT EXISTS sessions (
     id          TEXT PRIMARY KEY,
@@ -145,6 +165,10 @@ CREATE TABLE IF NOT EXISTS schedules (
     updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 ALTER TABLE schedules ADD COLUMN IF NOT EXISTS pipeline_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS paused_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS blackout JSONB;
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS last_outcome TEXT NOT NULL DEFAULT '';
 
 CREATE TABLE IF NOT EXISTS triggers (
     id             TEXT PRIMARY KEY,
@@ -196,6 +220,7 @@ CREATE TABLE IF NOT EXISTS connections (
 
 ALTER TABLE runs ADD COLUMN IF NOT EXISTS session_id TEXT;
 ALTER TABLE runs ADD COLUMN IF NOT EXISTS workflow_definition JSONB;
+ALTER TABLE runs ADD COLUMN IF NOT EXISTS artifacts JSONB;
 
 CREATE TABLE IF NOT EXISTS content_sessions (
     id           TEXT PRIMARY KEY,
@@ -447,4 +472,101 @@ CREATE TABLE IF NOT EXISTS refresh_tokens (
 );
 CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
 CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+
+CREATE TABLE IF NOT EXISTS content_store (
+    user_id    TEXT NOT NULL DEFAULT 'default',
+    key        TEXT NOT NULL,
+    value      TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id            TEXT PRIMARY KEY,
+    actor         TEXT NOT NULL DEFAULT 'default',
+    action        TEXT NOT NULL,
+    resource_type TEXT NOT NULL,
+    resource_id   TEXT NOT NULL DEFAULT '',
+    method        TEXT NOT NULL DEFAULT '',
+    path          TEXT NOT NULL DEFAULT '',
+    status        INTEGER NOT NULL DEFAULT 0,
+    changes       JSONB NOT NULL DEFAULT '[]',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_created ON audit_log(actor, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log(resource_type, resource_id);
+
+-- Link reruns to the run they were replayed from.
+ALTER TABLE runs ADD COLUMN IF NOT EXISTS rerun_of TEXT;
+
+-- OAuth refresh credentials for connections.
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS refresh_token TEXT NOT NULL DEFAULT '';
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS token_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS token_expires_at TIMESTAMPTZ;
+
+ALTER TABLE runs ADD COLUMN IF NOT EXISTS progress INTEGER NOT NULL DEFAULT 0;
+
+-- Server-wide maintenance flag (single row).
+CREATE TABLE IF NOT EXISTS maintenance_state (
+    id         INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    enabled    BOOLEAN NOT NULL DEFAULT FALSE,
+    reason     TEXT NOT NULL DEFAULT '',
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Persistent queue of scheduled ticks claimed by scheduler workers under a lease.
+CREATE TABLE IF NOT EXISTS run_queue (
+    id               TEXT PRIMARY KEY,
+    schedule_id      TEXT NOT NULL,
+    enqueued_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    lease_owner      TEXT NOT NULL DEFAULT '',
+    lease_expires_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_run_queue_enqueued ON run_queue(enqueued_at);
+
+-- Recurring allow-window outside which schedule ticks are skipped.
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS active_hours JSONB;
+
+-- Execution environment (models, providers, redacted config) captured per run.
+ALTER TABLE runs ADD COLUMN IF NOT EXISTS run_context JSONB;
+
+-- Connections notified of run lifecycle events.
+CREATE TABLE IF NOT EXISTS event_subscriptions (
+    id             TEXT PRIMARY KEY,
+    user_id        TEXT NOT NULL DEFAULT 'default',
+    name           TEXT NOT NULL DEFAULT '',
+    connection_id  TEXT NOT NULL,
+    events         JSONB NOT NULL DEFAULT '[]',
+    workflow_name  TEXT NOT NULL DEFAULT '',
+    retry_policy   JSONB,
+    enabled        BOOLEAN NOT NULL DEFAULT true,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- OpenAPI documents imported as tool namespaces, registered again at startup.
+CREATE TABLE IF NOT EXISTS openapi_imports (
+    namespace      TEXT PRIMARY KEY,
+    spec           TEXT NOT NULL,
+    base_url       TEXT NOT NULL DEFAULT '',
+    connection_id  TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Users who created and last edited each pipeline.
+ALTER TABLE pipelines ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE pipelines ADD COLUMN IF NOT EXISTS updated_by TEXT NOT NULL DEFAULT '';
+
+-- Why a cancelled run was stopped.
+ALTER TABLE runs ADD COLUMN IF NOT EXISTS cancel_reason TEXT NOT NULL DEFAULT '';
+
+-- Completed queue items are kept briefly as idempotency keys for their tick.
+ALTER TABLE run_queue ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+-- User who imported each OpenAPI toolset and owns its namespace.
+ALTER TABLE openapi_imports ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT '';
+
+-- Keyset pagination over a user's runs, newest first.
+CREATE INDEX IF NOT EXISTS idx_runs_user_created_id ON runs(user_id, created_at DESC, id DESC);
 `
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// CreateEventSubscription stores a new event subscription.
+func (d *DB) CreateEventSubscription(ctx context.Context, userID string, s *upal.EventSubscription) error {
+	eventsJSON, _ := json.Marshal(s.Events)
+	policyJSON, _ := json.Marshal(s.RetryPolicy)
+
+	_, err := d.Pool.ExecContext(ctx,
+		`INSERT INTO event_subscriptions (id, user_id, name, connection_id, events, workflow_name, retry_policy, enabled, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		s.ID, userID, s.Name, s.ConnectionID, eventsJSON, s.WorkflowName, policyJSON, s.Enabled, s.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert event subscription: %w", err)
+	}
+	return nil
+}
+
+// GetEventSubscription retrieves an event subscription by ID.
+func (d *DB) GetEventSubscription(ctx context.Context, userID string, id string) (*upal.EventSubscription, error) {
+	row := d.Pool.QueryRowContext(ctx,
+		`SELECT id, name, connection_id, events, workflow_name, retry_policy, enabled, created_at
+		 FROM event_subscriptions WHERE id = $1 AND user_id = $2`, id, userID,
+	)
+	s, err := scanEventSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("event subscription not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get event subscription: %w", err)
+	}
+	return s, nil
+}
+
+// ListEventSubscriptions returns all event subscriptions for a user.
+func (d *DB) ListEventSubscriptions(ctx context.Context, userID string) ([]*upal.EventSubscription, error) {
+	rows, err := d.Pool.QueryContext(ctx,
+		`SELECT id, name, connection_id, events, workflow_name, retry_policy, enabled, created_at
+		 FROM event_subscriptions WHERE user_id = $1 ORDER BY created_at`, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list event subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*upal.EventSubscription
+	for rows.Next() {
+		s, err := scanEventSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan event subscription: %w", err)
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// UpdateEventSubscription replaces an event subscription's settings.
+func (d *DB) UpdateEventSubscription(ctx context.Context, userID string, s *upal.EventSubscription) error {
+	eventsJSON, _ := json.Marshal(s.Events)
+	policyJSON, _ := json.Marshal(s.RetryPolicy)
+
+	_, err := d.Pool.ExecContext(ctx,
+		`UPDATE event_subscriptions SET name = $1, connection_id = $2, events = $3, workflow_name = $4, retry_policy = $5, enabled = $6
+		 WHERE id = $7 AND user_id = $8`,
+		s.Name, s.ConnectionID, eventsJSON, s.WorkflowName, policyJSON, s.Enabled, s.ID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("update event subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteEventSubscription removes an event subscription by ID.
+func (d *DB) DeleteEventSubscription(ctx context.Context, userID string, id string) error {
+	_, err := d.Pool.ExecContext(ctx, `DELETE FROM event_subscriptions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete event subscription: %w", err)
+	}
+	return nil
+}
+
+func scanEventSubscription(row interface{ Scan(...any) error }) (*upal.EventSubscription, error) {
+	s := &upal.EventSubscription{}
+	var eventsJSON, policyJSON []byte
+	if err := row.Scan(&s.ID, &s.Name, &s.ConnectionID, &eventsJSON, &s.WorkflowName, &policyJSON, &s.Enabled, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	json.Unmarshal(eventsJSON, &s.Events)
+	json.Unmarshal(policyJSON, &s.RetryPolicy)
+	return s, nil
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// GetMaintenanceState returns the stored maintenance flag, or a disabled
+// state when none has been saved yet.
+func (d *DB) GetMaintenanceState(ctx context.Context) (*upal.MaintenanceState, error) {
+	st := &upal.MaintenanceState{}
+	err := d.Pool.QueryRowContext(ctx,
+		`SELECT enabled, reason, updated_by, updated_at FROM maintenance_state WHERE id = 1`,
+	).Scan(&st.Enabled, &st.Reason, &st.UpdatedBy, &st.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return &upal.MaintenanceState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get maintenance state: %w", err)
+	}
+	return st, nil
+}
+
+// SetMaintenanceState upserts the maintenance flag.
+func (d *DB) SetMaintenanceState(ctx context.Context, st *upal.MaintenanceState) error {
+	_, err := d.Pool.ExecContext(ctx,
+		`INSERT INTO maintenance_state (id, enabled, reason, updated_by, updated_at)
+		 VALUES (1, $1, $2, $3, $4)
+		 ON CONFLICT (id) DO UPDATE SET enabled = EXCLUDED.enabled, reason = EXCLUDED.reason,
+		   updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`,
+		st.Enabled, st.Reason, st.UpdatedBy, st.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("set maintenance state: %w", err)
+	}
+	return nil
+}
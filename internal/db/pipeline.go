@@ -16,9 +16,9 @@ func (d *DB) CreatePipeline(ctx context.Context, userID string, p *upal.Pipeline
 		return fmt.Errorf("marshal stages: %w", err)
 	}
 	_, err = d.Pool.ExecContext(ctx,
-		`INSERT INTO pipelines (id, user_id, name, description, stages, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-		p.ID, userID, p.Name, p.Description, stagesJSON, p.CreatedAt, p.UpdatedAt,
+		`INSERT INTO pipelines (id, user_id, name, description, stages, created_by, updated_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		p.ID, userID, p.Name, p.Description, stagesJSON, p.CreatedBy, p.UpdatedBy, p.CreatedAt, p.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("insert pipeline: %w", err)
@@ -31,9 +31,9 @@ func (d *DB) GetPipeline(ctx context.Context, userID string, id string) (*upal.P
 	var p upal.Pipeline
 	var stagesJSON []byte
 	err := d.Pool.QueryRowContext(ctx,
-		`SELECT id, name, description, stages, created_at, updated_at
+		`SELECT id, name, description, stages, created_by, updated_by, created_at, updated_at
 		 FROM pipelines WHERE id = $1 AND user_id = $2`, id, userID,
-	).Scan(&p.ID, &p.Name, &p.Description, &stagesJSON, &p.CreatedAt, &p.UpdatedAt)
+	).Scan(&p.ID, &p.Name, &p.Description, &stagesJSON, &p.CreatedBy, &p.UpdatedBy, &p.CreatedAt, &p.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("pipeline %q not found", id)
 	}
@@ -49,7 +49,7 @@ func (d *DB) GetPipeline(ctx context.Context, userID string, id string) (*upal.P
 // ListPipelines returns all pipelines for a user ordered by updated_at descending.
 func (d *DB) ListPipelines(ctx context.Context, userID string) ([]*upal.Pipeline, error) {
 	rows, err := d.Pool.QueryContext(ctx,
-		`SELECT id, name, description, stages, created_at, updated_at
+		`SELECT id, name, description, stages, created_by, updated_by, created_at, updated_at
 		 FROM pipelines WHERE user_id = $1 ORDER BY updated_at DESC`, userID,
 	)
 	if err != nil {
@@ -61,7 +61,7 @@ func (d *DB) ListPipelines(ctx context.Context, userID string) ([]*upal.Pipeline
 	for rows.Next() {
 		var p upal.Pipeline
 		var stagesJSON []byte
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &stagesJSON, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &stagesJSON, &p.CreatedBy, &p.UpdatedBy, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan pipeline: %w", err)
 		}
 		if err := json.Unmarshal(stagesJSON, &p.Stages); err != nil {
@@ -75,16 +75,16 @@ func (d *DB) ListPipelines(ctx context.Context, userID string) ([]*upal.Pipeline
 	return result, nil
 }
 
-// UpdatePipeline updates an existing pipeline's name, description, stages, and updated_at.
+// UpdatePipeline updates an existing pipeline's name, description, stages, updated_by and updated_at.
 func (d *DB) UpdatePipeline(ctx context.Context, userID string, p *upal.Pipeline) error {
 	stagesJSON, err := json.Marshal(p.Stages)
 	if err != nil {
 		return fmt.Errorf("marshal stages: %w", err)
 	}
 	res, err := d.Pool.ExecContext(ctx,
-		`UPDATE pipelines SET name = $1, description = $2, stages = $3, updated_at = $4
-		 WHERE id = $5 AND user_id = $6`,
-		p.Name, p.Description, stagesJSON, p.UpdatedAt, p.ID, userID,
+		`UPDATE pipelines SET name = $1, description = $2, stages = $3, updated_by = $4, updated_at = $5
+		 WHERE id = $6 AND user_id = $7`,
+		p.Name, p.Description, stagesJSON, p.UpdatedBy, p.UpdatedAt, p.ID, userID,
 	)
 	if err != nil {
 		return fmt.Errorf("update pipeline: %w", err)
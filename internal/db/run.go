@@ -14,18 +14,20 @@ func (d *DB) CreateRun(ctx context.Context, userID string, r *upal.RunRecord) er
 	inputsJSON, _ := json.Marshal(r.Inputs)
 	outputsJSON, _ := json.Marshal(r.Outputs)
 	nodeRunsJSON, _ := json.Marshal(r.NodeRuns)
+	artifactsJSON, _ := json.Marshal(r.Artifacts)
+	runContextParam := runContextParam(r.Context)
 	var wfDefJSON []byte
 	if r.WorkflowDef != nil {
 		wfDefJSON, _ = json.Marshal(r.WorkflowDef)
 	}
 
 	_, err := d.Pool.ExecContext(ctx,
-		`INSERT INTO runs (id, user_id, workflow_name, trigger_type, trigger_ref, status, inputs, outputs, error, retry_of, retry_count, node_runs, session_id, workflow_definition, created_at, started_at, completed_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+		`INSERT INTO runs (id, user_id, workflow_name, trigger_type, trigger_ref, status, inputs, outputs, error, retry_of, rerun_of, retry_count, node_runs, session_id, workflow_definition, artifacts, run_context, created_at, started_at, completed_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
 		r.ID, userID, r.WorkflowName, r.TriggerType, r.TriggerRef,
 		string(r.Status), inputsJSON, outputsJSON, r.Error,
-		r.RetryOf, r.RetryCount, nodeRunsJSON,
-		r.SessionID, wfDefJSON, r.CreatedAt, r.StartedAt, r.CompletedAt,
+		r.RetryOf, r.RerunOf, r.RetryCount, nodeRunsJSON,
+		r.SessionID, wfDefJSON, artifactsJSON, runContextParam, r.CreatedAt, r.StartedAt, r.CompletedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("insert run: %w", err)
@@ -37,15 +39,15 @@ func (d *DB) CreateRun(ctx context.Context, userID string, r *upal.RunRecord) er
 func (d *DB) GetRun(ctx context.Context, userID string, id string) (*upal.RunRecord, error) {
 	r := &upal.RunRecord{}
 	var status string
-	var inputsJSON, outputsJSON, nodeRunsJSON, wfDefJSON []byte
+	var inputsJSON, outputsJSON, nodeRunsJSON, wfDefJSON, artifactsJSON, runContextJSON []byte
 
 	err := d.Pool.QueryRowContext(ctx,
-		`SELECT id, workflow_name, trigger_type, trigger_ref, status, inputs, outputs, error, retry_of, retry_count, node_runs, session_id, workflow_definition, created_at, started_at, completed_at
+		`SELECT id, user_id, workflow_name, trigger_type, trigger_ref, status, progress, inputs, outputs, error, retry_of, rerun_of, retry_count, node_runs, session_id, workflow_definition, artifacts, run_context, cancel_reason, created_at, started_at, completed_at
 		 FROM runs WHERE id = $1 AND user_id = $2`, id, userID,
-	).Scan(&r.ID, &r.WorkflowName, &r.TriggerType, &r.TriggerRef,
-		&status, &inputsJSON, &outputsJSON, &r.Error,
-		&r.RetryOf, &r.RetryCount, &nodeRunsJSON,
-		&r.SessionID, &wfDefJSON, &r.CreatedAt, &r.StartedAt, &r.CompletedAt,
+	).Scan(&r.ID, &r.UserID, &r.WorkflowName, &r.TriggerType, &r.TriggerRef,
+		&status, &r.Progress, &inputsJSON, &outputsJSON, &r.Error,
+		&r.RetryOf, &r.RerunOf, &r.RetryCount, &nodeRunsJSON,
+		&r.SessionID, &wfDefJSON, &artifactsJSON, &runContextJSON, &r.CancelReason, &r.CreatedAt, &r.StartedAt, &r.CompletedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("run not found: %s", id)
@@ -55,13 +57,18 @@ func (d *DB) GetRun(ctx context.Context, userID string, id string) (*upal.RunRec
 	}
 
 	r.Status = upal.RunStatus(status)
-	json.Unmarshal(inputsJSON, &r.Inputs)
+	d.unmarshalInputs(inputsJSON, &r.Inputs)
 	json.Unmarshal(outputsJSON, &r.Outputs)
 	json.Unmarshal(nodeRunsJSON, &r.NodeRuns)
+	json.Unmarshal(artifactsJSON, &r.Artifacts)
 	if len(wfDefJSON) > 0 {
 		r.WorkflowDef = &upal.WorkflowDefinition{}
 		json.Unmarshal(wfDefJSON, r.WorkflowDef)
 	}
+	if len(runContextJSON) > 0 {
+		r.Context = &upal.RunContext{}
+		json.Unmarshal(runContextJSON, r.Context)
+	}
 	return r, nil
 }
 
@@ -69,12 +76,13 @@ func (d *DB) GetRun(ctx context.Context, userID string, id string) (*upal.RunRec
 func (d *DB) UpdateRun(ctx context.Context, userID string, r *upal.RunRecord) error {
 	outputsJSON, _ := json.Marshal(r.Outputs)
 	nodeRunsJSON, _ := json.Marshal(r.NodeRuns)
+	artifactsJSON, _ := json.Marshal(r.Artifacts)
 
 	_, err := d.Pool.ExecContext(ctx,
-		`UPDATE runs SET status = $1, outputs = $2, error = $3, retry_count = $4, node_runs = $5, started_at = $6, completed_at = $7
-		 WHERE id = $8 AND user_id = $9`,
-		string(r.Status), outputsJSON, r.Error, r.RetryCount, nodeRunsJSON,
-		r.StartedAt, r.CompletedAt, r.ID, userID,
+		`UPDATE runs SET status = $1, outputs = $2, error = $3, retry_count = $4, node_runs = $5, artifacts = $6, started_at = $7, completed_at = $8, progress = $9, run_context = $10, cancel_reason = $11
+		 WHERE id = $12 AND user_id = $13`,
+		string(r.Status), outputsJSON, r.Error, r.RetryCount, nodeRunsJSON, artifactsJSON,
+		r.StartedAt, r.CompletedAt, r.Progress, runContextParam(r.Context), r.CancelReason, r.ID, userID,
 	)
 	if err != nil {
 		return fmt.Errorf("update run: %w", err)
@@ -93,7 +101,7 @@ func (d *DB) ListRunsByWorkflow(ctx context.Context, userID string, workflowName
 	}
 
 	rows, err := d.Pool.QueryContext(ctx,
-		`SELECT id, workflow_name, trigger_type, trigger_ref, status, inputs, outputs, error, retry_of, retry_count, node_runs, session_id, workflow_definition, created_at, started_at, completed_at
+		`SELECT id, user_id, workflow_name, trigger_type, trigger_ref, status, progress, inputs, outputs, error, retry_of, rerun_of, retry_count, node_runs, session_id, workflow_definition, artifacts, run_context, cancel_reason, created_at, started_at, completed_at
 		 FROM runs WHERE workflow_name = $1 AND user_id = $2 ORDER BY created_at DESC LIMIT $3 OFFSET $4`,
 		workflowName, userID, limit, offset,
 	)
@@ -102,7 +110,7 @@ func (d *DB) ListRunsByWorkflow(ctx context.Context, userID string, workflowName
 	}
 	defer rows.Close()
 
-	return scanRuns(rows, total)
+	return d.scanRuns(rows, total)
 }
 
 // ListAllRuns returns all runs with pagination. status filters by run status when non-empty.
@@ -122,13 +130,13 @@ func (d *DB) ListAllRuns(ctx context.Context, userID string, limit, offset int,
 	var err error
 	if status == "" {
 		rows, err = d.Pool.QueryContext(ctx,
-			`SELECT id, workflow_name, trigger_type, trigger_ref, status, inputs, outputs, error, retry_of, retry_count, node_runs, session_id, workflow_definition, created_at, started_at, completed_at
+			`SELECT id, user_id, workflow_name, trigger_type, trigger_ref, status, progress, inputs, outputs, error, retry_of, rerun_of, retry_count, node_runs, session_id, workflow_definition, artifacts, run_context, cancel_reason, created_at, started_at, completed_at
 			 FROM runs WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`,
 			userID, limit, offset,
 		)
 	} else {
 		rows, err = d.Pool.QueryContext(ctx,
-			`SELECT id, workflow_name, trigger_type, trigger_ref, status, inputs, outputs, error, retry_of, retry_count, node_runs, session_id, workflow_definition, created_at, started_at, completed_at
+			`SELECT id, user_id, workflow_name, trigger_type, trigger_ref, status, progress, inputs, outputs, error, retry_of, rerun_of, retry_count, node_runs, session_id, workflow_definition, artifacts, run_context, cancel_reason, created_at, started_at, completed_at
 			 FROM runs WHERE status = $1 AND user_id = $2 ORDER BY created_at DESC LIMIT $3 OFFSET $4`,
 			status, userID, limit, offset,
 		)
@@ -138,7 +146,35 @@ func (d *DB) ListAllRuns(ctx context.Context, userID string, limit, offset int,
 	}
 	defer rows.Close()
 
-	return scanRuns(rows, total)
+	return d.scanRuns(rows, total)
+}
+
+// ListAllRunsAfter returns up to limit runs ordered by (created_at, id)
+// descending, starting after the given cursor (from the newest run when nil).
+// status filters by run status when non-empty.
+func (d *DB) ListAllRunsAfter(ctx context.Context, userID, status string, after *upal.RunCursor, limit int) ([]*upal.RunRecord, error) {
+	query := `SELECT id, user_id, workflow_name, trigger_type, trigger_ref, status, progress, inputs, outputs, error, retry_of, rerun_of, retry_count, node_runs, session_id, workflow_definition, artifacts, run_context, cancel_reason, created_at, started_at, completed_at
+		 FROM runs WHERE user_id = $1`
+	args := []any{userID}
+	if status != "" {
+		args = append(args, status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		query += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := d.Pool.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs, _, err := d.scanRuns(rows, 0)
+	return runs, err
 }
 
 // MarkOrphanedRunsFailed updates all running/pending runs to failed.
@@ -155,30 +191,45 @@ func (d *DB) MarkOrphanedRunsFailed(ctx context.Context) (int64, error) {
 	return n, nil
 }
 
-func scanRuns(rows *sql.Rows, total int) ([]*upal.RunRecord, int, error) {
+func (d *DB) scanRuns(rows *sql.Rows, total int) ([]*upal.RunRecord, int, error) {
 	var result []*upal.RunRecord
 	for rows.Next() {
 		r := &upal.RunRecord{}
 		var status string
-		var inputsJSON, outputsJSON, nodeRunsJSON, wfDefJSON []byte
+		var inputsJSON, outputsJSON, nodeRunsJSON, wfDefJSON, artifactsJSON, runContextJSON []byte
 
-		if err := rows.Scan(&r.ID, &r.WorkflowName, &r.TriggerType, &r.TriggerRef,
-			&status, &inputsJSON, &outputsJSON, &r.Error,
-			&r.RetryOf, &r.RetryCount, &nodeRunsJSON,
-			&r.SessionID, &wfDefJSON, &r.CreatedAt, &r.StartedAt, &r.CompletedAt,
+		if err := rows.Scan(&r.ID, &r.UserID, &r.WorkflowName, &r.TriggerType, &r.TriggerRef,
+			&status, &r.Progress, &inputsJSON, &outputsJSON, &r.Error,
+			&r.RetryOf, &r.RerunOf, &r.RetryCount, &nodeRunsJSON,
+			&r.SessionID, &wfDefJSON, &artifactsJSON, &runContextJSON, &r.CancelReason, &r.CreatedAt, &r.StartedAt, &r.CompletedAt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scan run: %w", err)
 		}
 
 		r.Status = upal.RunStatus(status)
-		json.Unmarshal(inputsJSON, &r.Inputs)
+		d.unmarshalInputs(inputsJSON, &r.Inputs)
 		json.Unmarshal(outputsJSON, &r.Outputs)
 		json.Unmarshal(nodeRunsJSON, &r.NodeRuns)
+		json.Unmarshal(artifactsJSON, &r.Artifacts)
 		if len(wfDefJSON) > 0 {
 			r.WorkflowDef = &upal.WorkflowDefinition{}
 			json.Unmarshal(wfDefJSON, r.WorkflowDef)
 		}
+		if len(runContextJSON) > 0 {
+			r.Context = &upal.RunContext{}
+			json.Unmarshal(runContextJSON, r.Context)
+		}
 		result = append(result, r)
 	}
 	return result, total, nil
 }
+
+// runContextParam encodes a run context for the run_context column; nil
+// becomes SQL NULL.
+func runContextParam(rc *upal.RunContext) any {
+	if rc == nil {
+		return nil
+	}
+	b, _ := json.Marshal(rc)
+	return b
+}
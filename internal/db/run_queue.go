@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// EnqueueRun adds an unleased item to the run queue. It reports false when
+// an item with the same ID is queued or was completed within the last day,
+// so an ID built from a schedule tick is enqueued once across instances.
+func (d *DB) EnqueueRun(ctx context.Context, q *upal.QueuedRun) (bool, error) {
+	res, err := d.Pool.ExecContext(ctx,
+		`INSERT INTO run_queue (id, schedule_id, enqueued_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (id) DO NOTHING`,
+		q.ID, q.ScheduleID, q.EnqueuedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("enqueue run: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ClaimQueuedRun leases the oldest claimable item to owner until
+// now+lease. It returns nil when nothing is claimable. SKIP LOCKED lets
+// concurrent workers claim different items without blocking each other.
+func (d *DB) ClaimQueuedRun(ctx context.Context, owner string, lease time.Duration, now time.Time) (*upal.QueuedRun, error) {
+	q := &upal.QueuedRun{}
+	var expires time.Time
+	err := d.Pool.QueryRowContext(ctx,
+		`UPDATE run_queue SET lease_owner = $1, lease_expires_at = $2, attempts = attempts + 1
+		 WHERE id = (
+		   SELECT id FROM run_queue
+		   WHERE completed_at IS NULL AND (lease_expires_at IS NULL OR lease_expires_at < $3)
+		   ORDER BY enqueued_at LIMIT 1
+		   FOR UPDATE SKIP LOCKED)
+		 RETURNING id, schedule_id, enqueued_at, attempts, lease_owner, lease_expires_at`,
+		owner, now.Add(lease), now,
+	).Scan(&q.ID, &q.ScheduleID, &q.EnqueuedAt, &q.Attempts, &q.LeaseOwner, &expires)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim queued run: %w", err)
+	}
+	q.LeaseExpiresAt = &expires
+	return q, nil
+}
+
+// RenewQueuedRunLease extends owner's lease on id. It reports false when the
+// lease is no longer held by owner.
+func (d *DB) RenewQueuedRunLease(ctx context.Context, id, owner string, expiresAt time.Time) (bool, error) {
+	res, err := d.Pool.ExecContext(ctx,
+		`UPDATE run_queue SET lease_expires_at = $1 WHERE id = $2 AND lease_owner = $3 AND completed_at IS NULL`,
+		expiresAt, id, owner,
+	)
+	if err != nil {
+		return false, fmt.Errorf("renew queued run lease: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// CompleteQueuedRun marks id done if owner still holds its lease. It reports
+// false when the lease was lost. The row is kept for a day so a late
+// instance cannot enqueue the same tick again, then pruned.
+func (d *DB) CompleteQueuedRun(ctx context.Context, id, owner string) (bool, error) {
+	res, err := d.Pool.ExecContext(ctx,
+		`WITH pruned AS (DELETE FROM run_queue WHERE completed_at < NOW() - INTERVAL '1 day')
+		 UPDATE run_queue SET completed_at = NOW() WHERE id = $1 AND lease_owner = $2 AND completed_at IS NULL`,
+		id, owner,
+	)
+	if err != nil {
+		return false, fmt.Errorf("complete queued run: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
@@ -17,13 +17,22 @@ func (d *DB) CreateSchedule(ctx context.Context, userID string, s *upal.Schedule
 	if s.RetryPolicy != nil {
 		retryParam, _ = json.Marshal(s.RetryPolicy)
 	}
+	var blackoutParam any
+	if len(s.Blackout) > 0 {
+		blackoutParam, _ = json.Marshal(s.Blackout)
+	}
+	var activeHoursParam any
+	if s.ActiveHours != nil {
+		activeHoursParam, _ = json.Marshal(s.ActiveHours)
+	}
 
 	_, err := d.Pool.ExecContext(ctx,
-		`INSERT INTO schedules (id, user_id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		`INSERT INTO schedules (id, user_id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at, consecutive_failures, paused_reason, blackout, last_outcome, active_hours)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
 		s.ID, userID, s.WorkflowName, s.PipelineID, s.CronExpr, inputsJSON,
 		s.Enabled, s.Timezone, retryParam,
 		s.NextRunAt, s.LastRunAt, s.CreatedAt, s.UpdatedAt,
+		s.ConsecutiveFailures, s.PausedReason, blackoutParam, string(s.LastOutcome), activeHoursParam,
 	)
 	if err != nil {
 		return fmt.Errorf("insert schedule: %w", err)
@@ -34,14 +43,16 @@ func (d *DB) CreateSchedule(ctx context.Context, userID string, s *upal.Schedule
 // GetSchedule retrieves a schedule by ID.
 func (d *DB) GetSchedule(ctx context.Context, userID string, id string) (*upal.Schedule, error) {
 	s := &upal.Schedule{}
-	var inputsJSON, retryJSON []byte
+	var inputsJSON, retryJSON, blackoutJSON, activeHoursJSON []byte
+	var outcome string
 
 	err := d.Pool.QueryRowContext(ctx,
-		`SELECT id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at
+		`SELECT id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at, consecutive_failures, paused_reason, blackout, last_outcome, active_hours
 		 FROM schedules WHERE id = $1 AND user_id = $2`, id, userID,
 	).Scan(&s.ID, &s.WorkflowName, &s.PipelineID, &s.CronExpr, &inputsJSON,
 		&s.Enabled, &s.Timezone, &retryJSON,
 		&s.NextRunAt, &s.LastRunAt, &s.CreatedAt, &s.UpdatedAt,
+		&s.ConsecutiveFailures, &s.PausedReason, &blackoutJSON, &outcome, &activeHoursJSON,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("schedule not found: %s", id)
@@ -50,7 +61,13 @@ func (d *DB) GetSchedule(ctx context.Context, userID string, id string) (*upal.S
 		return nil, fmt.Errorf("get schedule: %w", err)
 	}
 
-	json.Unmarshal(inputsJSON, &s.Inputs)
+	d.unmarshalInputs(inputsJSON, &s.Inputs)
+	json.Unmarshal(blackoutJSON, &s.Blackout)
+	s.LastOutcome = upal.ScheduleOutcome(outcome)
+	if len(activeHoursJSON) > 0 {
+		s.ActiveHours = &upal.ActiveHours{}
+		json.Unmarshal(activeHoursJSON, s.ActiveHours)
+	}
 	if len(retryJSON) > 0 {
 		s.RetryPolicy = &upal.RetryPolicy{}
 		json.Unmarshal(retryJSON, s.RetryPolicy)
@@ -65,13 +82,22 @@ func (d *DB) UpdateSchedule(ctx context.Context, userID string, s *upal.Schedule
 	if s.RetryPolicy != nil {
 		retryParam, _ = json.Marshal(s.RetryPolicy)
 	}
+	var blackoutParam any
+	if len(s.Blackout) > 0 {
+		blackoutParam, _ = json.Marshal(s.Blackout)
+	}
+	var activeHoursParam any
+	if s.ActiveHours != nil {
+		activeHoursParam, _ = json.Marshal(s.ActiveHours)
+	}
 
 	_, err := d.Pool.ExecContext(ctx,
-		`UPDATE schedules SET workflow_name = $1, pipeline_id = $2, cron_expr = $3, inputs = $4, enabled = $5, timezone = $6, retry_policy = $7, next_run_at = $8, last_run_at = $9, updated_at = $10
-		 WHERE id = $11 AND user_id = $12`,
+		`UPDATE schedules SET workflow_name = $1, pipeline_id = $2, cron_expr = $3, inputs = $4, enabled = $5, timezone = $6, retry_policy = $7, next_run_at = $8, last_run_at = $9, updated_at = $10, consecutive_failures = $11, paused_reason = $12, blackout = $13, last_outcome = $14, active_hours = $15
+		 WHERE id = $16 AND user_id = $17`,
 		s.WorkflowName, s.PipelineID, s.CronExpr, inputsJSON,
 		s.Enabled, s.Timezone, retryParam,
-		s.NextRunAt, s.LastRunAt, s.UpdatedAt, s.ID, userID,
+		s.NextRunAt, s.LastRunAt, s.UpdatedAt,
+		s.ConsecutiveFailures, s.PausedReason, blackoutParam, string(s.LastOutcome), activeHoursParam, s.ID, userID,
 	)
 	if err != nil {
 		return fmt.Errorf("update schedule: %w", err)
@@ -91,7 +117,7 @@ func (d *DB) DeleteSchedule(ctx context.Context, userID string, id string) error
 // ListSchedules returns all schedules for a user.
 func (d *DB) ListSchedules(ctx context.Context, userID string) ([]*upal.Schedule, error) {
 	rows, err := d.Pool.QueryContext(ctx,
-		`SELECT id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at
+		`SELECT id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at, consecutive_failures, paused_reason, blackout, last_outcome, active_hours
 		 FROM schedules WHERE user_id = $1 ORDER BY created_at DESC`, userID,
 	)
 	if err != nil {
@@ -99,13 +125,13 @@ func (d *DB) ListSchedules(ctx context.Context, userID string) ([]*upal.Schedule
 	}
 	defer rows.Close()
 
-	return scanSchedules(rows)
+	return d.scanSchedules(rows)
 }
 
 // ListDueSchedules returns enabled schedules whose next_run_at is at or before now.
 func (d *DB) ListDueSchedules(ctx context.Context, now time.Time) ([]*upal.Schedule, error) {
 	rows, err := d.Pool.QueryContext(ctx,
-		`SELECT id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at
+		`SELECT id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at, consecutive_failures, paused_reason, blackout, last_outcome, active_hours
 		 FROM schedules WHERE enabled = true AND next_run_at <= $1`, now,
 	)
 	if err != nil {
@@ -113,13 +139,13 @@ func (d *DB) ListDueSchedules(ctx context.Context, now time.Time) ([]*upal.Sched
 	}
 	defer rows.Close()
 
-	return scanSchedules(rows)
+	return d.scanSchedules(rows)
 }
 
 // ListSchedulesByPipeline returns all schedules associated with a pipeline.
 func (d *DB) ListSchedulesByPipeline(ctx context.Context, userID string, pipelineID string) ([]*upal.Schedule, error) {
 	rows, err := d.Pool.QueryContext(ctx,
-		`SELECT id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at
+		`SELECT id, workflow_name, pipeline_id, cron_expr, inputs, enabled, timezone, retry_policy, next_run_at, last_run_at, created_at, updated_at, consecutive_failures, paused_reason, blackout, last_outcome, active_hours
 		 FROM schedules WHERE pipeline_id = $1 AND user_id = $2 ORDER BY created_at DESC`, pipelineID, userID,
 	)
 	if err != nil {
@@ -127,23 +153,31 @@ func (d *DB) ListSchedulesByPipeline(ctx context.Context, userID string, pipelin
 	}
 	defer rows.Close()
 
-	return scanSchedules(rows)
+	return d.scanSchedules(rows)
 }
 
-func scanSchedules(rows *sql.Rows) ([]*upal.Schedule, error) {
+func (d *DB) scanSchedules(rows *sql.Rows) ([]*upal.Schedule, error) {
 	var result []*upal.Schedule
 	for rows.Next() {
 		s := &upal.Schedule{}
-		var inputsJSON, retryJSON []byte
+		var inputsJSON, retryJSON, blackoutJSON, activeHoursJSON []byte
+		var outcome string
 
 		if err := rows.Scan(&s.ID, &s.WorkflowName, &s.PipelineID, &s.CronExpr, &inputsJSON,
 			&s.Enabled, &s.Timezone, &retryJSON,
 			&s.NextRunAt, &s.LastRunAt, &s.CreatedAt, &s.UpdatedAt,
+			&s.ConsecutiveFailures, &s.PausedReason, &blackoutJSON, &outcome, &activeHoursJSON,
 		); err != nil {
 			return nil, fmt.Errorf("scan schedule: %w", err)
 		}
 
-		json.Unmarshal(inputsJSON, &s.Inputs)
+		d.unmarshalInputs(inputsJSON, &s.Inputs)
+		json.Unmarshal(blackoutJSON, &s.Blackout)
+		s.LastOutcome = upal.ScheduleOutcome(outcome)
+		if len(activeHoursJSON) > 0 {
+			s.ActiveHours = &upal.ActiveHours{}
+			json.Unmarshal(activeHoursJSON, s.ActiveHours)
+		}
 		if len(retryJSON) > 0 {
 			s.RetryPolicy = &upal.RetryPolicy{}
 			json.Unmarshal(retryJSON, s.RetryPolicy)
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// SearchRuns returns runs whose workflow name, inputs, or outputs match query,
+// ordered by full-text rank.
+func (d *DB) SearchRuns(ctx context.Context, userID, query string, limit int) ([]*upal.RunRecord, error) {
+	rows, err := d.Pool.QueryContext(ctx,
+		`SELECT id, workflow_name, trigger_type, trigger_ref, status, progress, inputs, outputs, error, retry_of, rerun_of, retry_count, node_runs, session_id, workflow_definition, artifacts, run_context, cancel_reason, created_at, started_at, completed_at
+		 FROM runs, plainto_tsquery('simple', $2) q
+		 WHERE user_id = $1
+		   AND to_tsvector('simple', workflow_name || ' ' || COALESCE(inputs::text, '') || ' ' || COALESCE(outputs::text, '')) @@ q
+		 ORDER BY ts_rank(to_tsvector('simple', workflow_name || ' ' || COALESCE(inputs::text, '') || ' ' || COALESCE(outputs::text, '')), q) DESC, created_at DESC
+		 LIMIT $3`,
+		userID, query, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs, _, err := d.scanRuns(rows, 0)
+	return runs, err
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// SaveOpenAPIImport creates or replaces the import for imp.Namespace.
+func (d *DB) SaveOpenAPIImport(ctx context.Context, imp *upal.OpenAPIImport) error {
+	_, err := d.Pool.ExecContext(ctx,
+		`INSERT INTO openapi_imports (namespace, spec, base_url, connection_id, user_id, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (namespace) DO UPDATE SET spec = $2, base_url = $3, connection_id = $4, user_id = $5, created_at = $6`,
+		imp.Namespace, imp.Spec, imp.BaseURL, imp.ConnectionID, imp.UserID, imp.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save openapi import: %w", err)
+	}
+	return nil
+}
+
+// GetOpenAPIImport returns the import for namespace.
+func (d *DB) GetOpenAPIImport(ctx context.Context, namespace string) (*upal.OpenAPIImport, error) {
+	imp := &upal.OpenAPIImport{}
+	err := d.Pool.QueryRowContext(ctx,
+		`SELECT namespace, spec, base_url, connection_id, user_id, created_at
+		 FROM openapi_imports WHERE namespace = $1`, namespace,
+	).Scan(&imp.Namespace, &imp.Spec, &imp.BaseURL, &imp.ConnectionID, &imp.UserID, &imp.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("openapi import not found: %s", namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get openapi import: %w", err)
+	}
+	return imp, nil
+}
+
+// ListOpenAPIImports returns every imported OpenAPI toolset, oldest first.
+func (d *DB) ListOpenAPIImports(ctx context.Context) ([]*upal.OpenAPIImport, error) {
+	rows, err := d.Pool.QueryContext(ctx,
+		`SELECT namespace, spec, base_url, connection_id, user_id, created_at
+		 FROM openapi_imports ORDER BY created_at`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list openapi imports: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*upal.OpenAPIImport
+	for rows.Next() {
+		imp := &upal.OpenAPIImport{}
+		if err := rows.Scan(&imp.Namespace, &imp.Spec, &imp.BaseURL, &imp.ConnectionID, &imp.UserID, &imp.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan openapi import: %w", err)
+		}
+		result = append(result, imp)
+	}
+	return result, rows.Err()
+}
+
+// DeleteOpenAPIImport removes the import for namespace.
+func (d *DB) DeleteOpenAPIImport(ctx context.Context, namespace string) error {
+	if _, err := d.Pool.ExecContext(ctx, `DELETE FROM openapi_imports WHERE namespace = $1`, namespace); err != nil {
+		return fmt.Errorf("delete openapi import: %w", err)
+	}
+	return nil
+}
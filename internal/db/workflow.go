@@ -41,10 +41,11 @@ func (d *DB) CreateWorkflow(ctx context.Context, userID string, wf *upal.Workflo
 	}
 
 	_, err = d.Pool.ExecContext(ctx,
-		`INSERT INTO workflows (id, user_id, name, version, definition, visibility, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		 ON CONFLICT (user_id, name) DO UPDATE SET definition = EXCLUDED.definition, version = EXCLUDED.version, updated_at = EXCLUDED.updated_at`,
-		row.ID, userID, row.Name, row.Version, defJSON, row.Visibility, row.CreatedAt, row.UpdatedAt,
+		`INSERT INTO workflows (id, user_id, name, version, definition, visibility, webhook_secret, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 ON CONFLICT (user_id, name) DO UPDATE SET definition = EXCLUDED.definition, version = EXCLUDED.version,
+		   webhook_secret = EXCLUDED.webhook_secret, updated_at = EXCLUDED.updated_at`,
+		row.ID, userID, row.Name, row.Version, defJSON, row.Visibility, wf.WebhookSecret, row.CreatedAt, row.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert workflow: %w", err)
@@ -56,13 +57,14 @@ func (d *DB) CreateWorkflow(ctx context.Context, userID string, wf *upal.Workflo
 func (d *DB) GetWorkflow(ctx context.Context, userID string, name string) (*WorkflowRow, error) {
 	var row WorkflowRow
 	var defJSON []byte
+	var secret string
 
 	err := d.Pool.QueryRowContext(ctx,
-		`SELECT id, name, version, definition, visibility, created_at, updated_at
+		`SELECT id, name, version, definition, visibility, webhook_secret, created_at, updated_at
 		 FROM workflows WHERE name = $1 AND user_id = $2`, name, userID,
-	).Scan(&row.ID, &row.Name, &row.Version, &defJSON, &row.Visibility, &row.CreatedAt, &row.UpdatedAt)
+	).Scan(&row.ID, &row.Name, &row.Version, &defJSON, &row.Visibility, &secret, &row.CreatedAt, &row.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("workflow not found: %s", name)
+		return nil, fmt.Errorf("workflow not found: %s: %w", name, err)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get workflow: %w", err)
@@ -71,13 +73,14 @@ func (d *DB) GetWorkflow(ctx context.Context, userID string, name string) (*Work
 	if err := json.Unmarshal(defJSON, &row.Definition); err != nil {
 		return nil, fmt.Errorf("unmarshal definition: %w", err)
 	}
+	row.Definition.WebhookSecret = secret
 	return &row, nil
 }
 
 // ListWorkflows returns all workflows for a user.
 func (d *DB) ListWorkflows(ctx context.Context, userID string) ([]WorkflowRow, error) {
 	rows, err := d.Pool.QueryContext(ctx,
-		`SELECT id, name, version, definition, visibility, created_at, updated_at
+		`SELECT id, name, version, definition, visibility, webhook_secret, created_at, updated_at
 		 FROM workflows WHERE user_id = $1 ORDER BY updated_at DESC`, userID,
 	)
 	if err != nil {
@@ -89,12 +92,14 @@ func (d *DB) ListWorkflows(ctx context.Context, userID string) ([]WorkflowRow, e
 	for rows.Next() {
 		var row WorkflowRow
 		var defJSON []byte
-		if err := rows.Scan(&row.ID, &row.Name, &row.Version, &defJSON, &row.Visibility, &row.CreatedAt, &row.UpdatedAt); err != nil {
+		var secret string
+		if err := rows.Scan(&row.ID, &row.Name, &row.Version, &defJSON, &row.Visibility, &secret, &row.CreatedAt, &row.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan workflow: %w", err)
 		}
 		if err := json.Unmarshal(defJSON, &row.Definition); err != nil {
 			return nil, fmt.Errorf("unmarshal definition: %w", err)
 		}
+		row.Definition.WebhookSecret = secret
 		result = append(result, row)
 	}
 	if err := rows.Err(); err != nil {
@@ -23,20 +23,36 @@ const nodeDescPlaceholder = "AI model processing step"
 func (g *Generator) BackfillWorkflowDescriptions(ctx context.Context, workflows []*upal.WorkflowDefinition) []*upal.WorkflowDefinition {
 	var updated []*upal.WorkflowDefinition
 	for _, wf := range workflows {
-		if wf.Description != "" {
-			continue
-		}
-		desc, err := g.generateWorkflowDescription(ctx, wf)
-		if err != nil {
-			log.Printf("backfill: workflow %q description failed: %v", wf.Name, err)
-			continue
+		if g.backfillWorkflowDescription(ctx, wf) {
+			updated = append(updated, wf)
 		}
-		wf.Description = desc
-		updated = append(updated, wf)
 	}
 	return updated
 }
 
+// BackfillWorkflow fills in wf's description and its missing node descriptions
+// in place. It reports whether the workflow description was filled and how
+// many node descriptions were. Already-described parts are left untouched.
+func (g *Generator) BackfillWorkflow(ctx context.Context, wf *upal.WorkflowDefinition) (described bool, nodes int) {
+	described = g.backfillWorkflowDescription(ctx, wf)
+	return described, g.backfillNodeDescriptions(ctx, wf)
+}
+
+// backfillWorkflowDescription generates wf.Description when it is empty and
+// reports whether it did.
+func (g *Generator) backfillWorkflowDescription(ctx context.Context, wf *upal.WorkflowDefinition) bool {
+	if wf.Description != "" {
+		return false
+	}
+	desc, err := g.generateWorkflowDescription(ctx, wf)
+	if err != nil {
+		log.Printf("backfill: workflow %q description failed: %v", wf.Name, err)
+		return false
+	}
+	wf.Description = desc
+	return true
+}
+
 // generateWorkflowDescription asks the LLM for a one-sentence Korean description
 // of a workflow based on its name and node structure.
 func (g *Generator) generateWorkflowDescription(ctx context.Context, wf *upal.WorkflowDefinition) (string, error) {
@@ -101,16 +117,16 @@ func buildWorkflowDescribePrompt(wf *upal.WorkflowDefinition) string {
 }
 
 // BackfillStageDescriptions fills in missing stage descriptions using a
-// rule-based approach (no LLM needed). Returns true if any stage was updated.
-func BackfillStageDescriptions(pipeline *upal.Pipeline) bool {
-	updated := false
+// rule-based approach (no LLM needed). Returns the number of stages updated.
+func BackfillStageDescriptions(pipeline *upal.Pipeline) int {
+	updated := 0
 	for i := range pipeline.Stages {
 		stage := &pipeline.Stages[i]
 		if stage.Description != "" {
 			continue
 		}
 		stage.Description = defaultStageDescription(stage)
-		updated = true
+		updated++
 	}
 	return updated
 }
@@ -121,30 +137,39 @@ func BackfillStageDescriptions(pipeline *upal.Pipeline) bool {
 func (g *Generator) BackfillNodeDescriptions(ctx context.Context, workflows []*upal.WorkflowDefinition) []*upal.WorkflowDefinition {
 	var updated []*upal.WorkflowDefinition
 	for _, wf := range workflows {
-		changed := false
-		for i := range wf.Nodes {
-			node := &wf.Nodes[i]
-			desc, _ := node.Config["description"].(string)
-			if desc != "" && desc != nodeDescPlaceholder {
+		if g.backfillNodeDescriptions(ctx, wf) > 0 {
+			updated = append(updated, wf)
+		}
+	}
+	return updated
+}
+
+// backfillNodeDescriptions fills in wf's missing or placeholder node
+// descriptions and returns how many nodes were updated.
+func (g *Generator) backfillNodeDescriptions(ctx context.Context, wf *upal.WorkflowDefinition) int {
+	updated := 0
+	for i := range wf.Nodes {
+		node := &wf.Nodes[i]
+		desc, _ := node.Config["description"].(string)
+		if desc != "" && desc != nodeDescPlaceholder {
+			continue
+		}
+		var newDesc string
+		var err error
+		if node.Type == upal.NodeTypeAgent {
+			newDesc, err = g.generateNodeDescription(ctx, node)
+			if err != nil {
+				log.Printf("backfill: node %q description failed: %v", node.ID, err)
 				continue
 			}
-			var newDesc string
-			var err error
-			if node.Type == upal.NodeTypeAgent {
-				newDesc, err = g.generateNodeDescription(ctx, node)
-				if err != nil {
-					log.Printf("backfill: node %q description failed: %v", node.ID, err)
-					continue
-				}
-			} else {
-				newDesc = defaultNodeDescription(node)
-			}
-			node.Config["description"] = newDesc
-			changed = true
+		} else {
+			newDesc = defaultNodeDescription(node)
 		}
-		if changed {
-			updated = append(updated, wf)
+		if node.Config == nil {
+			node.Config = map[string]any{}
 		}
+		node.Config["description"] = newDesc
+		updated++
 	}
 	return updated
 }
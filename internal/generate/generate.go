@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/soochol/upal/internal/llmutil"
@@ -37,6 +38,8 @@ type Generator struct {
 	llmResolver    ports.LLMResolver   // resolves "provider/model" → LLM instance (optional)
 	defaultLLMFunc DefaultLLMFunc      // dynamic default resolver (optional)
 	modelsFunc     ModelsFunc          // dynamic models resolver (optional)
+	language       string              // default output language (optional)
+	limits         upal.WorkflowLimits // size limits generated workflows must meet
 }
 
 // New creates a Generator that uses the given LLM and model name.
@@ -54,6 +57,11 @@ func New(llm adkmodel.LLM, model string, skillsProv skills.Provider, toolInfos [
 	}
 }
 
+// SetWorkflowLimits makes generation fail for workflows over the size limits.
+func (g *Generator) SetWorkflowLimits(l upal.WorkflowLimits) {
+	g.limits = l
+}
+
 // SetLLMResolver sets the resolver used for per-request model overrides.
 func (g *Generator) SetLLMResolver(r ports.LLMResolver) {
 	g.llmResolver = r
@@ -97,7 +105,7 @@ func resolveDefaultModelID(models []upal.ModelSummary, modelName string) string
 
 // currentDefault returns the current default LLM and model name.
 // Resolves dynamically from the configured DefaultLLMFunc (backed by DB settings).
-// Returns an error if no default LLM provider is configured.
+// Returns upal.ErrNoLLMProvider if no default LLM provider is configured.
 func (g *Generator) currentDefault(ctx context.Context) (adkmodel.LLM, string, error) {
 	if g.defaultLLMFunc != nil {
 		return g.defaultLLMFunc(ctx)
@@ -105,7 +113,15 @@ func (g *Generator) currentDefault(ctx context.Context) (adkmodel.LLM, string, e
 	if g.llm != nil {
 		return g.llm, g.model, nil
 	}
-	return nil, "", fmt.Errorf("no default LLM provider configured")
+	return nil, "", upal.ErrNoLLMProvider
+}
+
+// Available returns upal.ErrNoLLMProvider (possibly wrapped) when there is no
+// default LLM to generate with, so callers can refuse work up front instead
+// of failing once it has started.
+func (g *Generator) Available(ctx context.Context) error {
+	_, _, err := g.currentDefault(ctx)
+	return err
 }
 
 // resolveLLM returns the LLM and model name for a request.
@@ -176,6 +192,7 @@ func (g *Generator) Generate(ctx context.Context, description string, existingWo
 		}
 		userContent = fmt.Sprintf("Current workflow:\n%s\n\nInstruction: %s", string(wfJSON), description)
 	}
+	sysPrompt = g.applyLanguage(ctx, sysPrompt)
 
 	// Inject existing workflow summaries so the LLM understands what already exists.
 	if len(availableWorkflows) > 0 {
@@ -230,7 +247,7 @@ func (g *Generator) Generate(ctx context.Context, description string, existingWo
 	// Strip hallucinated node types before validation.
 	stripInvalidNodeTypes(&wf)
 
-	if err := validate(&wf); err != nil {
+	if err := validate(&wf, g.limits); err != nil {
 		return nil, fmt.Errorf("invalid generated workflow: %w", err)
 	}
 
@@ -294,6 +311,10 @@ func (g *Generator) generateWithSkills(ctx context.Context, sysPrompt, userConte
 			if err != nil {
 				return "", fmt.Errorf("parse %s (model output may be malformed): %w\nraw output: %s", opName, err, text)
 			}
+			if resp.FinishReason == genai.FinishReasonMaxTokens || llmutil.IsTruncatedJSON(stripped) {
+				contents = append(contents, resp.Content)
+				return g.repairTruncatedJSON(ctx, llm, modelName, genCfg, contents, stripped, resp.FinishReason, opName)
+			}
 			return stripped, nil
 		}
 
@@ -305,6 +326,69 @@ func (g *Generator) generateWithSkills(ctx context.Context, sysPrompt, userConte
 	return "", fmt.Errorf("%s: exceeded maximum turns without producing output", opName)
 }
 
+// repairMaxOutputTokens is the output budget for a continuation request when
+// the original request left it to the provider default.
+const repairMaxOutputTokens = 16384
+
+// repairTruncatedJSON recovers a JSON response cut off mid-object. It asks the
+// model once, with a larger token budget, to continue where it stopped; if
+// the result is still incomplete it falls back to closing the open brackets
+// after dropping the trailing incomplete member. contents must end with the
+// truncated model turn.
+func (g *Generator) repairTruncatedJSON(ctx context.Context, llm adkmodel.LLM, modelName string, genCfg *genai.GenerateContentConfig, contents []*genai.Content, partial string, reason genai.FinishReason, opName string) (string, error) {
+	slog.WarnContext(ctx, "generation output truncated, attempting repair", "op", opName, "finish_reason", reason)
+
+	cfg := *genCfg
+	cfg.Tools = nil // the continuation must be plain JSON text
+	cfg.MaxOutputTokens = max(2*genCfg.MaxOutputTokens, repairMaxOutputTokens)
+	req := &adkmodel.LLMRequest{
+		Model:  modelName,
+		Config: &cfg,
+		Contents: append(contents, genai.NewContentFromText(
+			"Your previous response was cut off. Continue the JSON exactly where it stopped. "+
+				"Output only the remaining characters, without repeating anything and without markdown fences.",
+			genai.RoleUser)),
+	}
+
+	candidate := partial
+	var resp *adkmodel.LLMResponse
+	var err error
+	for r, e := range llm.GenerateContent(ctx, req, false) {
+		if e != nil {
+			err = e
+			break
+		}
+		resp = r
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "generation continuation failed", "op", opName, "err", err)
+	} else if resp != nil {
+		cont := strings.TrimSpace(llmutil.ExtractText(resp))
+		cont = strings.TrimPrefix(cont, "```json")
+		cont = strings.TrimPrefix(cont, "```")
+		cont = strings.TrimSuffix(cont, "```")
+		cont = strings.TrimSpace(cont)
+		if strings.HasPrefix(cont, "{") && json.Valid([]byte(cont)) {
+			// The model re-sent the whole document instead of continuing.
+			return cont, nil
+		}
+		candidate = partial + cont
+	}
+
+	if repaired, ok := llmutil.CompleteJSON(candidate); ok {
+		return repaired, nil
+	}
+	return "", fmt.Errorf("%s: model output truncated (%s) and could not be repaired\nraw output: %s", opName, finishReasonLabel(reason), candidate)
+}
+
+// finishReasonLabel describes why a truncated response ended.
+func finishReasonLabel(reason genai.FinishReason) string {
+	if reason == "" || reason == genai.FinishReasonUnspecified {
+		return "unterminated JSON"
+	}
+	return "finish reason " + string(reason)
+}
+
 // executeSkillCalls handles get_skill function calls from the generation LLM.
 func (g *Generator) executeSkillCalls(calls []*genai.FunctionCall) *genai.Content {
 	parts := make([]*genai.Part, 0, len(calls))
@@ -463,14 +547,18 @@ func stripInvalidNodeTypes(wf *upal.WorkflowDefinition) {
 	}
 }
 
-// validate checks that the generated workflow has the minimum required structure.
-func validate(wf *upal.WorkflowDefinition) error {
+// validate checks that the generated workflow has the minimum required
+// structure and is within limits.
+func validate(wf *upal.WorkflowDefinition, limits upal.WorkflowLimits) error {
 	if wf.Name == "" {
 		return fmt.Errorf("missing workflow name")
 	}
 	if len(wf.Nodes) == 0 {
 		return fmt.Errorf("workflow has no nodes")
 	}
+	if err := limits.Check(wf); err != nil {
+		return err
+	}
 
 	nodeIDs := map[string]bool{}
 	hasInput := false
@@ -3,12 +3,14 @@ package generate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	upalmodel "github.com/soochol/upal/internal/model"
+	"github.com/soochol/upal/internal/skills"
 	"github.com/soochol/upal/internal/upal"
 )
 
@@ -182,7 +184,7 @@ func TestValidate_DuplicateNodeID(t *testing.T) {
 			{ID: "in", Type: upal.NodeTypeOutput},
 		},
 	}
-	err := validate(wf)
+	err := validate(wf, upal.WorkflowLimits{})
 	if err == nil {
 		t.Fatal("expected error for duplicate node ID")
 	}
@@ -197,7 +199,7 @@ func TestValidate_BadEdge(t *testing.T) {
 		},
 		Edges: []upal.EdgeDefinition{{From: "in", To: "nonexistent"}},
 	}
-	err := validate(wf)
+	err := validate(wf, upal.WorkflowLimits{})
 	if err == nil {
 		t.Fatal("expected error for bad edge target")
 	}
@@ -292,7 +294,7 @@ func TestValidate_RejectsUnknownType(t *testing.T) {
 			{From: "bogus", To: "out"},
 		},
 	}
-	err := validate(wf)
+	err := validate(wf, upal.WorkflowLimits{})
 	if err == nil {
 		t.Fatal("expected error for unknown node type")
 	}
@@ -308,7 +310,7 @@ func TestValidate_AgentMissingModel(t *testing.T) {
 		},
 		Edges: []upal.EdgeDefinition{{From: "in", To: "a"}, {From: "a", To: "out"}},
 	}
-	if err := validate(wf); err == nil {
+	if err := validate(wf, upal.WorkflowLimits{}); err == nil {
 		t.Fatal("expected error for agent missing model")
 	}
 }
@@ -367,7 +369,7 @@ func TestValidate_AgentMissingPrompt(t *testing.T) {
 		},
 		Edges: []upal.EdgeDefinition{{From: "in", To: "a"}, {From: "a", To: "out"}},
 	}
-	if err := validate(wf); err == nil {
+	if err := validate(wf, upal.WorkflowLimits{}); err == nil {
 		t.Fatal("expected error for agent missing prompt")
 	}
 }
@@ -626,3 +628,176 @@ func TestBuildPipelineSysPromptInjectsModelsAndTools(t *testing.T) {
 		t.Error("expected tool name in pipeline system prompt")
 	}
 }
+
+func TestGenerate_LanguageDirective(t *testing.T) {
+	wfJSON, _ := json.Marshal(upal.WorkflowDefinition{
+		Name:  "lang-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "in", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "in", To: "out"}},
+	})
+
+	var sysPrompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		for _, m := range body.Messages {
+			if m.Role == "system" {
+				sysPrompt = m.Content
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]any{"role": "assistant", "content": string(wfJSON)}, "finish_reason": "stop"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	llm := upalmodel.NewOpenAILLM("test-key", upalmodel.WithOpenAIBaseURL(server.URL))
+	gen := New(llm, "gpt-4o", skills.New(), nil, nil)
+	gen.SetLanguage("ja")
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		existing *upal.WorkflowDefinition
+		want     string
+	}{
+		{"create uses config default", context.Background(), nil, "MUST be in Japanese (日本語)"},
+		{"create uses request language", WithLanguage(context.Background(), "en"), nil, "MUST be in English"},
+		{"edit uses request language", WithLanguage(context.Background(), "Spanish"), &upal.WorkflowDefinition{Name: "lang-test"}, "MUST be in Spanish"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gen.Generate(tt.ctx, "anything", tt.existing, nil); err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			if !strings.Contains(sysPrompt, tt.want) {
+				t.Errorf("system prompt missing %q", tt.want)
+			}
+			if strings.Contains(sysPrompt, "Korean") || strings.Contains(sysPrompt, languagePlaceholder) {
+				t.Error("system prompt still contains a hardcoded or unresolved language directive")
+			}
+		})
+	}
+}
+
+// truncatedWorkflowServer answers the first chat request with the first half
+// of a workflow JSON and finish_reason "length", then answers every later
+// request with second. It records the max_tokens of each request.
+func truncatedWorkflowServer(t *testing.T, first, second string) (*httptest.Server, *[]float64) {
+	t.Helper()
+	var maxTokens []float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		mt, _ := body["max_tokens"].(float64)
+		maxTokens = append(maxTokens, mt)
+
+		content, reason := second, "stop"
+		if len(maxTokens) == 1 {
+			content, reason = first, "length"
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]any{"role": "assistant", "content": content}, "finish_reason": reason},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &maxTokens
+}
+
+const truncationTestWorkflow = `{"name": "digest", "version": 1, "nodes": [` +
+	`{"id": "user_input", "type": "input", "config": {}}, ` +
+	`{"id": "summarizer", "type": "agent", "config": {"model": "openai/gpt-4o", "prompt": "Summarize {{user_input}}"}}, ` +
+	`{"id": "final_output", "type": "output", "config": {}}], ` +
+	`"edges": [{"from": "user_input", "to": "summarizer"}, {"from": "summarizer", "to": "final_output"}]}`
+
+func TestGenerate_TruncatedOutputContinued(t *testing.T) {
+	cut := strings.Index(truncationTestWorkflow, `"edges"`) + 20
+	server, maxTokens := truncatedWorkflowServer(t, truncationTestWorkflow[:cut], truncationTestWorkflow[cut:])
+
+	llm := upalmodel.NewOpenAILLM("test-key", upalmodel.WithOpenAIBaseURL(server.URL))
+	gen := New(llm, "gpt-4o", nil, nil, nil)
+	result, err := gen.Generate(context.Background(), "Create a digest workflow", nil, nil)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(result.Nodes) != 3 || len(result.Edges) != 2 {
+		t.Errorf("got %d nodes / %d edges, want 3 / 2", len(result.Nodes), len(result.Edges))
+	}
+	if len(*maxTokens) != 2 || (*maxTokens)[1] != repairMaxOutputTokens {
+		t.Errorf("expected one continuation with max_tokens %d, got requests %v", repairMaxOutputTokens, *maxTokens)
+	}
+}
+
+func TestGenerate_TruncatedOutputBraceCompleted(t *testing.T) {
+	// The continuation is unusable, so the repair falls back to closing the
+	// JSON after the last complete edge.
+	cut := strings.LastIndex(truncationTestWorkflow, `{"from": "summarizer"`) + 10
+	server, _ := truncatedWorkflowServer(t, truncationTestWorkflow[:cut], "")
+
+	llm := upalmodel.NewOpenAILLM("test-key", upalmodel.WithOpenAIBaseURL(server.URL))
+	gen := New(llm, "gpt-4o", nil, nil, nil)
+	result, err := gen.Generate(context.Background(), "Create a digest workflow", nil, nil)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(result.Nodes) != 3 || len(result.Edges) != 1 {
+		t.Errorf("got %d nodes / %d edges, want 3 / 1", len(result.Nodes), len(result.Edges))
+	}
+}
+
+func TestGenerate_TruncatedOutputUnrepairable(t *testing.T) {
+	server, _ := truncatedWorkflowServer(t, `{"name": "digest", "nodes": [{"id": "a"`, "]]]")
+
+	llm := upalmodel.NewOpenAILLM("test-key", upalmodel.WithOpenAIBaseURL(server.URL))
+	gen := New(llm, "gpt-4o", nil, nil, nil)
+	_, err := gen.Generate(context.Background(), "Create a digest workflow", nil, nil)
+	if err == nil {
+		t.Fatal("expected an error for unrepairable output")
+	}
+	if !strings.Contains(err.Error(), "finish reason MAX_TOKENS") {
+		t.Errorf("error should surface the finish reason, got: %v", err)
+	}
+}
+
+func TestValidate_WorkflowLimits(t *testing.T) {
+	wf := &upal.WorkflowDefinition{
+		Name: "limited",
+		Nodes: []upal.NodeDefinition{
+			{ID: "in", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "a", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "p/m", "prompt": "summarize"}},
+			{ID: "out", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "in", To: "a"}, {From: "a", To: "out"}},
+	}
+	tests := []struct {
+		limits    upal.WorkflowLimits
+		wantLimit string
+	}{
+		{upal.WorkflowLimits{MaxNodes: 3, MaxEdges: 2, MaxPromptChars: 9}, ""},
+		{upal.WorkflowLimits{MaxNodes: 2}, "max_nodes"},
+		{upal.WorkflowLimits{MaxEdges: 1}, "max_edges"},
+		{upal.WorkflowLimits{MaxPromptChars: 8}, "max_prompt_chars"},
+	}
+	for _, tt := range tests {
+		err := validate(wf, tt.limits)
+		var limitErr *upal.WorkflowLimitError
+		switch {
+		case tt.wantLimit == "" && err != nil:
+			t.Errorf("limits %+v: unexpected error %v", tt.limits, err)
+		case tt.wantLimit != "" && (!errors.As(err, &limitErr) || limitErr.Limit != tt.wantLimit):
+			t.Errorf("limits %+v: err = %v, want %s violation", tt.limits, err, tt.wantLimit)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package generate
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultLanguage is used when neither the request nor the config names one.
+const DefaultLanguage = "ko"
+
+// languagePlaceholder marks where create/edit prompts name the output language.
+const languagePlaceholder = "{{language}}"
+
+// languageNames expands common language codes into the name written into
+// prompts. Anything else is passed through verbatim (e.g. "Brazilian Portuguese").
+var languageNames = map[string]string{
+	"ko": "Korean (한국어)",
+	"en": "English",
+	"ja": "Japanese (日本語)",
+	"zh": "Chinese (中文)",
+	"es": "Spanish (Español)",
+	"fr": "French (Français)",
+	"de": "German (Deutsch)",
+}
+
+type languageKey struct{}
+
+// WithLanguage sets the output language for generations run with ctx,
+// overriding the generator's configured default.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	if lang == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// SetLanguage sets the default output language for user-facing text in
+// generated workflows and pipelines.
+func (g *Generator) SetLanguage(lang string) {
+	g.language = lang
+}
+
+// languageFor resolves the output language name for a generation.
+// Priority: request (ctx) > configured default > DefaultLanguage.
+func (g *Generator) languageFor(ctx context.Context) string {
+	lang, _ := ctx.Value(languageKey{}).(string)
+	if lang == "" {
+		lang = g.language
+	}
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if name, ok := languageNames[strings.ToLower(lang)]; ok {
+		return name
+	}
+	return lang
+}
+
+// applyLanguage fills the language placeholder of a skill prompt.
+func (g *Generator) applyLanguage(ctx context.Context, prompt string) string {
+	return strings.ReplaceAll(prompt, languagePlaceholder, g.languageFor(ctx))
+}
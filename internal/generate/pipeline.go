@@ -258,7 +258,7 @@ func (g *Generator) generatePipelineCreate(ctx context.Context, description stri
 	if g.skills != nil {
 		basePrompt = g.skills.GetPrompt("pipeline-create")
 	}
-	sysPrompt := g.buildPipelineSysPrompt(ctx, basePrompt, availableWorkflows, existingPipelines)
+	sysPrompt := g.buildPipelineSysPrompt(ctx, g.applyLanguage(ctx, basePrompt), availableWorkflows, existingPipelines)
 
 	ctx = upalmodel.WithEffort(ctx, "high")
 
@@ -311,7 +311,7 @@ func (g *Generator) generatePipelineCreate(ctx context.Context, description stri
 // generatePipelineEdit asks the LLM for a delta and applies it to the existing pipeline.
 // Only stages explicitly mentioned in the delta are changed; all others are preserved verbatim.
 func (g *Generator) generatePipelineEdit(ctx context.Context, description string, existing *upal.Pipeline, availableWorkflows []WorkflowSummary, existingPipelines []PipelineSummary) (*PipelineBundle, error) {
-	sysPrompt := g.buildPipelineSysPrompt(ctx, g.skills.GetPrompt("pipeline-edit"), availableWorkflows, existingPipelines)
+	sysPrompt := g.buildPipelineSysPrompt(ctx, g.applyLanguage(ctx, g.skills.GetPrompt("pipeline-edit")), availableWorkflows, existingPipelines)
 
 	pipelineJSON, err := json.MarshalIndent(existing, "", "  ")
 	if err != nil {
@@ -459,15 +459,7 @@ func (g *Generator) buildPipelineSysPrompt(ctx context.Context, base string, ava
 }
 
 // validStageTypes is the set of stage types the pipeline editor supports.
-var validStageTypes = map[string]bool{
-	"workflow":     true,
-	"approval":     true,
-	"notification": true,
-	"schedule":     true,
-	"trigger":      true,
-	"transform":    true,
-	"collect":      true,
-}
+var validStageTypes = upal.KnownStageTypes
 
 // workflowNameSet builds a set of workflow names from a summary list.
 func workflowNameSet(workflows []WorkflowSummary) map[string]bool {
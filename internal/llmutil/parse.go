@@ -1,6 +1,7 @@
 package llmutil
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -38,3 +39,102 @@ func StripMarkdownJSON(text string) (string, error) {
 
 	return content[start:], nil
 }
+
+// jsonScan is the lexical state at the end of a JSON prefix.
+type jsonScan struct {
+	open     []byte // unclosed '{' / '[' in order
+	inString bool
+	end      int   // offset just past the closing bracket of a complete document
+	cuts     []int // offsets where the prefix can be cut to drop an incomplete member
+}
+
+// scanJSON tracks nesting and string state through text, which is expected
+// to start at the opening '{' of an object.
+func scanJSON(text string) jsonScan {
+	var s jsonScan
+	escaped := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if s.inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				s.inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			s.inString = true
+		case '{', '[':
+			s.open = append(s.open, c)
+			s.cuts = append(s.cuts, i)
+		case '}', ']':
+			if len(s.open) > 0 {
+				s.open = s.open[:len(s.open)-1]
+			}
+			if len(s.open) == 0 {
+				s.end = i + 1
+				return s
+			}
+		case ',':
+			s.cuts = append(s.cuts, i)
+		}
+	}
+	return s
+}
+
+// IsTruncatedJSON reports whether text ends before its top-level JSON object
+// is closed, e.g. because the model hit its output token limit.
+func IsTruncatedJSON(text string) bool {
+	s := scanJSON(text)
+	return s.inString || len(s.open) > 0
+}
+
+// maxCompletionAttempts bounds how far CompleteJSON backs up through a
+// truncated document.
+const maxCompletionAttempts = 64
+
+// CompleteJSON makes a best-effort repair of a truncated JSON object by
+// dropping the trailing incomplete member and closing every open bracket.
+// It returns false when no valid document could be produced. A complete
+// document is returned without any trailing text.
+func CompleteJSON(text string) (string, bool) {
+	s := scanJSON(text)
+	if s.end > 0 {
+		if doc := text[:s.end]; json.Valid([]byte(doc)) {
+			return doc, true
+		}
+		return "", false
+	}
+	if !s.inString {
+		if candidate := closeJSON(text, s.open); json.Valid([]byte(candidate)) {
+			return candidate, true
+		}
+	}
+	for i, n := len(s.cuts)-1, 0; i >= 0 && n < maxCompletionAttempts; i, n = i-1, n+1 {
+		prefix := strings.TrimRight(text[:s.cuts[i]], " \t\r\n")
+		candidate := closeJSON(prefix, scanJSON(prefix).open)
+		if json.Valid([]byte(candidate)) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// closeJSON appends the closers for open, innermost first.
+func closeJSON(prefix string, open []byte) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == '{' {
+			b.WriteByte('}')
+		} else {
+			b.WriteByte(']')
+		}
+	}
+	return b.String()
+}
@@ -87,3 +87,36 @@ func TestStripMarkdownJSON_LeadingTemplateText(t *testing.T) {
 		t.Errorf("got %q, want %q", got, want)
 	}
 }
+
+func TestCompleteJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+		ok   bool
+	}{
+		{"complete with trailing text", `{"a": 1} done`, `{"a": 1}`, true},
+		{"open object after value", `{"a": 1, "b": [1, 2]`, `{"a": 1, "b": [1, 2]}`, true},
+		{"cut inside string", `{"a": 1, "b": "unfini`, `{"a": 1}`, true},
+		{"dangling key", `{"nodes": [{"id": "x"}], "edges": [{"from": "x", "to":`, `{"nodes": [{"id": "x"}], "edges": [{"from": "x"}]}`, true},
+		{"braces inside strings", `{"prompt": "use {{input}} and }", "x": [`, `{"prompt": "use {{input}} and }", "x": []}`, true},
+		{"mismatched closers", `{"a": [}]`, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CompleteJSON(tt.in)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("CompleteJSON(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestIsTruncatedJSON(t *testing.T) {
+	if IsTruncatedJSON(`{"a": {"b": "}"}}`) {
+		t.Error("complete object reported as truncated")
+	}
+	if !IsTruncatedJSON(`{"a": {"b": "}"}`) {
+		t.Error("unterminated object not reported as truncated")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/soochol/upal/internal/upal"
 	"github.com/soochol/upal/internal/upal/ports"
 	adkmodel "google.golang.org/adk/model"
 )
@@ -21,9 +22,13 @@ func NewMapResolver(llms map[string]adkmodel.LLM, defaultLLM adkmodel.LLM, defau
 }
 
 // Resolve parses "provider/model" and returns the matching LLM + model name.
-// Empty modelID returns the system default.
+// Empty modelID returns the system default. With no providers at all (or
+// no default for an empty modelID) the error wraps upal.ErrNoLLMProvider.
 func (r *mapResolver) Resolve(modelID string) (adkmodel.LLM, string, error) {
 	if modelID == "" {
+		if r.defaultLLM == nil {
+			return nil, "", fmt.Errorf("default model: %w", upal.ErrNoLLMProvider)
+		}
 		return r.defaultLLM, r.defaultModel, nil
 	}
 	provider, modelName, ok := strings.Cut(modelID, "/")
@@ -32,6 +37,9 @@ func (r *mapResolver) Resolve(modelID string) (adkmodel.LLM, string, error) {
 	}
 	llm, found := r.llms[provider]
 	if !found {
+		if len(r.llms) == 0 {
+			return nil, "", fmt.Errorf("model %q: %w", modelID, upal.ErrNoLLMProvider)
+		}
 		return nil, "", fmt.Errorf("unknown provider %q in model ID %q", provider, modelID)
 	}
 	return llm, modelName, nil
@@ -1,19 +1,31 @@
 package llmutil
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/soochol/upal/internal/upal"
+
 	adkmodel "google.golang.org/adk/model"
 )
 
 func TestMapResolver_EmptyReturnsDefault(t *testing.T) {
-	r := NewMapResolver(nil, nil, "default-model")
-	_, model, err := r.Resolve("")
+	fake := &stubLLM{}
+	r := NewMapResolver(nil, fake, "default-model")
+	llm, model, err := r.Resolve("")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if model != "default-model" {
-		t.Errorf("expected default-model, got %s", model)
+	if llm != fake || model != "default-model" {
+		t.Errorf("expected the default LLM and default-model, got %v %s", llm, model)
+	}
+}
+
+func TestMapResolver_EmptyWithoutDefault(t *testing.T) {
+	r := NewMapResolver(nil, nil, "")
+	_, _, err := r.Resolve("")
+	if !errors.Is(err, upal.ErrNoLLMProvider) {
+		t.Fatalf("expected ErrNoLLMProvider, got %v", err)
 	}
 }
 
@@ -49,3 +61,15 @@ func TestMapResolver_ValidProviderModel(t *testing.T) {
 		t.Errorf("expected claude-sonnet-4-6, got %s", model)
 	}
 }
+
+func TestMapResolver_NoProvidersConfigured(t *testing.T) {
+	_, _, err := NewMapResolver(nil, nil, "").Resolve("openai/gpt-4o")
+	if !errors.Is(err, upal.ErrNoLLMProvider) {
+		t.Fatalf("expected ErrNoLLMProvider, got %v", err)
+	}
+
+	r := NewMapResolver(map[string]adkmodel.LLM{"anthropic": &stubLLM{}}, nil, "")
+	if _, _, err := r.Resolve("openai/gpt-4o"); err == nil || errors.Is(err, upal.ErrNoLLMProvider) {
+		t.Fatalf("unknown provider with others configured: got %v", err)
+	}
+}
@@ -12,14 +12,15 @@ import (
 )
 
 // ExtractText concatenates all text parts from an LLMResponse into a single string.
-// Returns an empty string if the response or its content is nil.
+// Thought parts (model reasoning) are skipped. Returns an empty string if the
+// response or its content is nil.
 func ExtractText(resp *adkmodel.LLMResponse) string {
 	if resp == nil || resp.Content == nil {
 		return ""
 	}
 	var text string
 	for _, p := range resp.Content.Parts {
-		if p.Text != "" {
+		if p.Text != "" && !p.Thought {
 			text += p.Text
 		}
 	}
@@ -29,13 +30,16 @@ func ExtractText(resp *adkmodel.LLMResponse) string {
 // ExtractContent extracts all content from an LLMResponse, including images.
 // Text parts are concatenated as-is. InlineData parts (images) are converted
 // to data URI strings (e.g., "data:image/png;base64,...").
-// Multiple parts are joined with newlines.
+// Multiple parts are joined with newlines. Thought parts are skipped.
 func ExtractContent(resp *adkmodel.LLMResponse) string {
 	if resp == nil || resp.Content == nil {
 		return ""
 	}
 	var parts []string
 	for _, p := range resp.Content.Parts {
+		if p.Thought {
+			continue
+		}
 		if p.Text != "" {
 			parts = append(parts, p.Text)
 		}
@@ -68,6 +72,9 @@ func ExtractContentSavingAudio(resp *adkmodel.LLMResponse, outputDir string) str
 	}
 	var parts []string
 	for _, p := range resp.Content.Parts {
+		if p.Thought {
+			continue
+		}
 		if p.Text != "" {
 			parts = append(parts, p.Text)
 			continue
@@ -0,0 +1,253 @@
+package llmutil
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genai"
+)
+
+// tokenEncoding is the tokenizer family EstimateTokens approximates.
+type tokenEncoding int
+
+const (
+	// encodingHeuristic covers providers whose tokenizers are not public
+	// (Anthropic, Gemini, local models): a per-character estimate.
+	encodingHeuristic tokenEncoding = iota
+	// encodingCL100K is OpenAI's cl100k_base (GPT-3.5, GPT-4).
+	encodingCL100K
+	// encodingO200K is OpenAI's o200k_base (GPT-4o, GPT-4.1, GPT-5, o-series),
+	// which splits like cl100k but covers non-Latin scripts more densely.
+	encodingO200K
+)
+
+// Per-message framing added by chat APIs on top of the message text, as in
+// OpenAI's published counting recipe.
+const (
+	tokensPerMessage = 3
+	tokensPerReply   = 3
+)
+
+// EstimateTokens estimates how many input tokens content costs on model,
+// before calling it. model is a "provider/model" ID or a bare model name.
+//
+// OpenAI models are estimated by splitting content the way tiktoken's
+// cl100k/o200k pre-tokenizers do (words with their leading space, digit
+// groups of up to three, punctuation runs, whitespace) and costing each piece
+// by length, which tracks real counts closely for prose and code without
+// shipping the BPE vocabularies. Other providers use a character heuristic.
+// Either way the result is an estimate for budgeting, not an exact count.
+func EstimateTokens(model, content string) int {
+	if content == "" {
+		return 0
+	}
+	enc := encodingForModel(model)
+	if enc == encodingHeuristic {
+		return heuristicTokens(model, content)
+	}
+	return bpeTokens(enc, content)
+}
+
+// EstimateContentsTokens estimates the prompt tokens of a chat request: the
+// system instruction and contents, including function calls and responses
+// as JSON, plus per-message framing.
+func EstimateContentsTokens(model string, system *genai.Content, contents []*genai.Content) int {
+	total := tokensPerReply
+	for _, c := range append([]*genai.Content{system}, contents...) {
+		if c == nil {
+			continue
+		}
+		total += tokensPerMessage
+		for _, p := range c.Parts {
+			total += estimatePartTokens(model, p)
+		}
+	}
+	return total
+}
+
+func estimatePartTokens(model string, p *genai.Part) int {
+	if p == nil {
+		return 0
+	}
+	n := EstimateTokens(model, p.Text)
+	if p.FunctionCall != nil {
+		args, _ := json.Marshal(p.FunctionCall.Args)
+		n += EstimateTokens(model, p.FunctionCall.Name) + EstimateTokens(model, string(args))
+	}
+	if p.FunctionResponse != nil {
+		resp, _ := json.Marshal(p.FunctionResponse.Response)
+		n += EstimateTokens(model, p.FunctionResponse.Name) + EstimateTokens(model, string(resp))
+	}
+	return n
+}
+
+// modelProvider returns the provider prefix of a "provider/model" ID, or ""
+// for a bare model name.
+func modelProvider(model string) string {
+	provider, _, ok := strings.Cut(model, "/")
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(provider)
+}
+
+// encodingForModel picks the tokenizer family from the model name, so an
+// OpenAI model served through another provider prefix is still matched.
+func encodingForModel(model string) tokenEncoding {
+	name := strings.ToLower(model)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	switch {
+	case strings.HasPrefix(name, "gpt-4o"), strings.HasPrefix(name, "gpt-4.1"),
+		strings.HasPrefix(name, "gpt-4.5"), strings.HasPrefix(name, "gpt-5"),
+		strings.HasPrefix(name, "chatgpt-"), strings.HasPrefix(name, "gpt-oss"),
+		len(name) >= 2 && name[0] == 'o' && name[1] >= '1' && name[1] <= '9':
+		return encodingO200K
+	case strings.HasPrefix(name, "gpt-"), strings.HasPrefix(name, "text-embedding-"):
+		return encodingCL100K
+	}
+	if modelProvider(model) == "openai" {
+		return encodingO200K
+	}
+	return encodingHeuristic
+}
+
+// bpeTokens splits s into tiktoken-style pre-tokens and sums their
+// estimated cost.
+func bpeTokens(enc tokenEncoding, s string) int {
+	runes := []rune(s)
+	n := len(runes)
+	total := 0
+	for i := 0; i < n; {
+		r := runes[i]
+		if k := contractionLen(runes[i:]); k > 0 {
+			total++
+			i += k
+			continue
+		}
+		if unicode.IsNumber(r) {
+			j := i
+			for j < n && unicode.IsNumber(runes[j]) {
+				j++
+			}
+			total += (j - i + 2) / 3 // digits group in threes
+			i = j
+			continue
+		}
+		if unicode.IsSpace(r) {
+			j := i
+			for j < n && unicode.IsSpace(runes[j]) {
+				j++
+			}
+			// The last space before a word or punctuation joins it; the
+			// rest of the run is one whitespace token.
+			if j < n && runes[j-1] == ' ' && !unicode.IsNumber(runes[j]) {
+				j--
+			}
+			if j > i {
+				total++
+				i = j
+				continue
+			}
+			i++ // leading space of the next piece
+		}
+		if unicode.IsLetter(runes[i]) {
+			var latin int
+			var cost float64
+			for ; i < n && unicode.IsLetter(runes[i]); i++ {
+				if runes[i] < unicode.MaxLatin1 {
+					latin++
+				} else {
+					cost += letterCost(enc, runes[i])
+				}
+			}
+			// Latin words of up to eight letters are usually one token;
+			// longer ones split into pieces of roughly that size.
+			total += (latin+7)/8 + int(math.Ceil(cost))
+			continue
+		}
+		j := i
+		for j < n && isPunct(runes[j]) {
+			j++
+		}
+		// Short punctuation runs such as "()", "*/" and ")," are usually
+		// one token each.
+		total += (j - i + 1) / 2
+		i = j
+	}
+	return total
+}
+
+// letterCost is the token cost of a non-Latin letter; Latin letters are
+// charged per word by bpeTokens.
+func letterCost(enc tokenEncoding, r rune) float64 {
+	switch {
+	case unicode.Is(unicode.Hangul, r):
+		if enc == encodingO200K {
+			return 0.6
+		}
+		return 1.5
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+		if enc == encodingO200K {
+			return 0.8
+		}
+		return 1.2
+	case unicode.In(r, unicode.Cyrillic, unicode.Greek, unicode.Arabic, unicode.Hebrew):
+		if enc == encodingO200K {
+			return 0.3
+		}
+		return 0.5
+	}
+	return 1
+}
+
+// contractionLen returns the length of an English contraction suffix ('s,
+// 't, 're, 've, 'm, 'll, 'd) at the start of rs, or 0.
+func contractionLen(rs []rune) int {
+	if len(rs) < 2 || rs[0] != '\'' {
+		return 0
+	}
+	switch unicode.ToLower(rs[1]) {
+	case 's', 't', 'm', 'd':
+		return 2
+	case 'r', 'v':
+		if len(rs) > 2 && unicode.ToLower(rs[2]) == 'e' {
+			return 3
+		}
+	case 'l':
+		if len(rs) > 2 && unicode.ToLower(rs[2]) == 'l' {
+			return 3
+		}
+	}
+	return 0
+}
+
+func isPunct(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// heuristicTokens estimates tokens for providers without a public
+// tokenizer: about four characters per token for Latin text (three and a
+// half for Anthropic, whose vocabulary is smaller) and roughly one token per
+// CJK or Hangul character.
+func heuristicTokens(model, s string) int {
+	charsPerToken := 4.0
+	if p := modelProvider(model); p == "anthropic" || p == "claude-code" || strings.HasPrefix(strings.ToLower(model), "claude") {
+		charsPerToken = 3.5
+	}
+	var latin, other float64
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana):
+			other++
+		case r < unicode.MaxLatin1:
+			latin++
+		default:
+			other += 0.5
+		}
+	}
+	return max(1, int(math.Ceil(latin/charsPerToken+other)))
+}
@@ -0,0 +1,111 @@
+package llmutil
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+// withinTolerance reports whether got is within 25% (at least two tokens) of
+// want.
+func withinTolerance(got, want int) bool {
+	tol := max(2, want/4)
+	return got >= want-tol && got <= want+tol
+}
+
+func TestEstimateTokens_OpenAIKnownCounts(t *testing.T) {
+	// Reference counts from tiktoken's cl100k_base encoding.
+	samples := []struct {
+		text string
+		want int
+	}{
+		{"hello world", 2},
+		{"Hello, world!", 4},
+		{"tiktoken is great!", 6},
+		{"The quick brown fox jumps over the lazy dog.", 10},
+		{"I would like to book a table for two people at seven tonight, please.", 16},
+		{"1234567", 3},
+		{strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20), 201},
+	}
+	for _, s := range samples {
+		if got := EstimateTokens("openai/gpt-4", s.text); !withinTolerance(got, s.want) {
+			t.Errorf("EstimateTokens(gpt-4, %.40q) = %d, want %d±25%%", s.text, got, s.want)
+		}
+		// o200k splits English the same way.
+		if got := EstimateTokens("openai/gpt-4o", s.text); !withinTolerance(got, s.want) {
+			t.Errorf("EstimateTokens(gpt-4o, %.40q) = %d, want %d±25%%", s.text, got, s.want)
+		}
+	}
+}
+
+func TestEstimateTokens_NonLatinScripts(t *testing.T) {
+	korean := "안녕하세요. 오늘 회의는 오후 세 시에 시작합니다."
+	cl100k := EstimateTokens("gpt-4", korean)
+	o200k := EstimateTokens("gpt-4o", korean)
+	// Hangul costs far more than its character count divided by four, and
+	// o200k encodes it more densely than cl100k.
+	if runes := len([]rune(korean)); cl100k < runes/2 {
+		t.Errorf("cl100k estimate %d is too low for %d characters", cl100k, runes)
+	}
+	if o200k >= cl100k {
+		t.Errorf("o200k estimate %d should be below cl100k estimate %d", o200k, cl100k)
+	}
+	if got := EstimateTokens("gemini/gemini-2.5-flash", "日本語のテキスト"); got != 8 {
+		t.Errorf("heuristic CJK estimate = %d, want one per character (8)", got)
+	}
+}
+
+func TestEstimateTokens_Heuristic(t *testing.T) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20) // 900 chars
+	if got := EstimateTokens("gemini/gemini-2.5-flash", text); got != 225 {
+		t.Errorf("gemini estimate = %d, want 225", got)
+	}
+	if got := EstimateTokens("anthropic/claude-sonnet-4-5", text); got != 258 {
+		t.Errorf("anthropic estimate = %d, want 258", got)
+	}
+	if got := EstimateTokens("claude-haiku-4-5", text); got != 258 {
+		t.Errorf("bare claude model estimate = %d, want 258", got)
+	}
+	if got := EstimateTokens("anthropic/claude-sonnet-4-5", ""); got != 0 {
+		t.Errorf("empty content = %d, want 0", got)
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	for model, want := range map[string]tokenEncoding{
+		"openai/gpt-4":                     encodingCL100K,
+		"gpt-3.5-turbo":                    encodingCL100K,
+		"openai/gpt-4o-mini":               encodingO200K,
+		"openai/o3":                        encodingO200K,
+		"openai/gpt-5":                     encodingO200K,
+		"openai/some-future-model":         encodingO200K,
+		"openrouter/openai/gpt-4.1":        encodingO200K,
+		"anthropic/claude-sonnet-4-5":      encodingHeuristic,
+		"gemini/gemini-2.5-pro":            encodingHeuristic,
+		"ollama/llama3":                    encodingHeuristic,
+		"anthropic/claude-opus-4-1-latest": encodingHeuristic,
+	} {
+		if got := encodingForModel(model); got != want {
+			t.Errorf("encodingForModel(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
+
+func TestEstimateContentsTokens(t *testing.T) {
+	system := genai.NewContentFromText("You are terse.", genai.RoleUser)
+	contents := []*genai.Content{
+		genai.NewContentFromText("hello world", genai.RoleUser),
+		{Role: genai.RoleModel, Parts: []*genai.Part{genai.NewPartFromFunctionCall("search", map[string]any{"q": "weather"})}},
+	}
+	got := EstimateContentsTokens("openai/gpt-4o", system, contents)
+	text := EstimateTokens("openai/gpt-4o", "You are terse.") + EstimateTokens("openai/gpt-4o", "hello world")
+	// Text plus the function call, three framing tokens per message and
+	// three for the reply.
+	if min := text + 3*tokensPerMessage + tokensPerReply + 1; got <= min {
+		t.Errorf("EstimateContentsTokens = %d, want more than %d", got, min)
+	}
+	if got := EstimateContentsTokens("openai/gpt-4o", nil, nil); got != tokensPerReply {
+		t.Errorf("empty request = %d, want %d", got, tokensPerReply)
+	}
+}
@@ -0,0 +1,35 @@
+// Package logging provides slog helpers shared by the server and background services.
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// ContextHandler wraps an slog.Handler and attaches the correlation ID found
+// in the record's context (see upal.WithRunID) as a "run_id" attribute.
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps h so context-aware log calls carry the run ID.
+func NewContextHandler(h slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: h}
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := upal.RunIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("run_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
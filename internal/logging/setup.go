@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Log output formats accepted by NewHandler.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// NewHandler builds the root handler: a text or JSON handler writing to w,
+// wrapped in a ContextHandler. Records below level are dropped; pass a
+// *slog.LevelVar to change verbosity while running. An empty format means
+// text.
+func NewHandler(w io.Writer, format string, level slog.Leveler) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return NewContextHandler(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return NewContextHandler(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (want %q or %q)", format, FormatText, FormatJSON)
+}
+
+// ParseLevel parses a level name ("debug", "info", "warn", "error", with an
+// optional offset such as "debug-2"), case-insensitively. Empty means info.
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if strings.TrimSpace(s) == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+	return l, nil
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestNewHandler_LevelVarControlsDebug(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	h, err := NewHandler(&buf, FormatText, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(h)
+
+	logger.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("debug record emitted at info level: %q", buf.String())
+	}
+	level.Set(slog.LevelDebug)
+	logger.Debug("shown")
+	if !strings.Contains(buf.String(), "msg=shown") {
+		t.Fatalf("debug record not emitted after lowering the level: %q", buf.String())
+	}
+}
+
+func TestNewHandler_JSONKeepsRunID(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewHandler(&buf, "JSON", slog.LevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(h).InfoContext(upal.WithRunID(context.Background(), "run-1"), "hello")
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("output is not JSON: %q", buf.String())
+	}
+	if rec["msg"] != "hello" || rec["run_id"] != "run-1" {
+		t.Errorf("record = %v", rec)
+	}
+
+	if _, err := NewHandler(&buf, "xml", slog.LevelInfo); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]slog.Level{
+		"":        slog.LevelInfo,
+		"debug":   slog.LevelDebug,
+		"WARN":    slog.LevelWarn,
+		" error ": slog.LevelError,
+		"info+2":  slog.LevelInfo + 2,
+	} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel accepted an unknown level")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"iter"
 	"net/http"
+	"strings"
 
 	"google.golang.org/genai"
 
@@ -22,8 +23,15 @@ var _ NativeToolProvider = (*AnthropicLLM)(nil)
 
 const (
 	defaultAnthropicBaseURL = "https://api.anthropic.com"
-	anthropicVersion        = "2023-06-01"
+	defaultAnthropicVersion = "2023-06-01"
 	defaultMaxTokens        = 4096
+
+	// Extended thinking: the API rejects budgets below minThinkingBudget.
+	// Thinking enabled without a budget gets minThinkingBudget, or
+	// highEffortThinkingBudget for high-effort calls.
+	minThinkingBudget        = 1024
+	highEffortThinkingBudget = 8192
+	thinkingBeta             = "interleaved-thinking-2025-05-14"
 )
 
 // AnthropicOption configures an AnthropicLLM.
@@ -37,10 +45,30 @@ func WithAnthropicBaseURL(url string) AnthropicOption {
 	}
 }
 
+// WithAnthropicVersion overrides the anthropic-version header sent with
+// every request. Empty keeps the default.
+func WithAnthropicVersion(version string) AnthropicOption {
+	return func(a *AnthropicLLM) {
+		if version != "" {
+			a.version = version
+		}
+	}
+}
+
+// WithAnthropicHeaders adds extra headers to every request. They are applied
+// last, so they can also replace the default headers.
+func WithAnthropicHeaders(headers map[string]string) AnthropicOption {
+	return func(a *AnthropicLLM) {
+		a.headers = headers
+	}
+}
+
 // AnthropicLLM implements the ADK model.LLM interface for the Anthropic Messages API.
 type AnthropicLLM struct {
 	apiKey  string
 	baseURL string
+	version string
+	headers map[string]string
 	client  *http.Client
 }
 
@@ -49,6 +77,7 @@ func NewAnthropicLLM(apiKey string, opts ...AnthropicOption) *AnthropicLLM {
 	a := &AnthropicLLM{
 		apiKey:  apiKey,
 		baseURL: defaultAnthropicBaseURL,
+		version: defaultAnthropicVersion,
 		client:  &http.Client{},
 	}
 	for _, opt := range opts {
@@ -80,6 +109,10 @@ func (a *AnthropicLLM) GenerateContent(ctx context.Context, req *adkmodel.LLMReq
 // generate performs a synchronous call to the Anthropic Messages API.
 func (a *AnthropicLLM) generate(ctx context.Context, req *adkmodel.LLMRequest) (*adkmodel.LLMResponse, error) {
 	body := a.buildRequestBody(req)
+	budget := anthropicThinkingBudget(ctx, req)
+	if budget > 0 {
+		applyAnthropicThinking(body, budget)
+	}
 
 	jsonData, err := json.Marshal(body)
 	if err != nil {
@@ -92,10 +125,13 @@ func (a *AnthropicLLM) generate(ctx context.Context, req *adkmodel.LLMRequest) (
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("x-api-key", a.apiKey)
-	httpReq.Header.Set("anthropic-version", anthropicVersion)
-	if beta := anthropicBetaFeatures(req); beta != "" {
+	httpReq.Header.Set("anthropic-version", a.version)
+	if beta := anthropicBetaFeatures(req, budget > 0); beta != "" {
 		httpReq.Header.Set("anthropic-beta", beta)
 	}
+	for k, v := range a.headers {
+		httpReq.Header.Set(k, v)
+	}
 
 	resp, err := a.client.Do(httpReq)
 	if err != nil {
@@ -105,7 +141,7 @@ func (a *AnthropicLLM) generate(ctx context.Context, req *adkmodel.LLMRequest) (
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Anthropic API error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{Provider: "Anthropic", StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var apiResp anthropicAPIResponse
@@ -113,7 +149,50 @@ func (a *AnthropicLLM) generate(ctx context.Context, req *adkmodel.LLMRequest) (
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return a.convertResponse(&apiResp), nil
+	llmResp := a.convertResponse(&apiResp)
+	if req.Config != nil && req.Config.ThinkingConfig != nil && req.Config.ThinkingConfig.IncludeThoughts {
+		for _, p := range llmResp.Content.Parts {
+			if p.Thought && p.Text != "" {
+				emitLog(ctx, "thinking: "+p.Text)
+			}
+		}
+	}
+	return llmResp, nil
+}
+
+// anthropicThinkingBudget returns the extended-thinking token budget for req,
+// or 0 when thinking is off. Thinking is only on when asked for: an explicit
+// ThinkingConfig budget wins; otherwise a WithThinking context enables it
+// with a budget sized by the effort level. Effort alone never enables it.
+func anthropicThinkingBudget(ctx context.Context, req *adkmodel.LLMRequest) int32 {
+	var budget int32
+	switch {
+	case req.Config != nil && req.Config.ThinkingConfig != nil && req.Config.ThinkingConfig.ThinkingBudget != nil:
+		budget = *req.Config.ThinkingConfig.ThinkingBudget
+	case thinkingFromContext(ctx):
+		budget = minThinkingBudget
+		if effortFromContext(ctx) == "high" {
+			budget = highEffortThinkingBudget
+		}
+	}
+	if budget <= 0 {
+		return 0
+	}
+	return max(budget, minThinkingBudget)
+}
+
+// applyAnthropicThinking enables extended thinking on a request body. The API
+// requires max_tokens to exceed the budget and does not accept a custom
+// temperature alongside thinking.
+func applyAnthropicThinking(body map[string]any, budget int32) {
+	body["thinking"] = map[string]any{
+		"type":          "enabled",
+		"budget_tokens": budget,
+	}
+	if maxTokens, _ := body["max_tokens"].(int32); maxTokens <= budget {
+		body["max_tokens"] = budget + defaultMaxTokens
+	}
+	delete(body, "temperature")
 }
 
 // buildRequestBody converts an LLMRequest into the Anthropic API request body.
@@ -200,6 +279,24 @@ func (a *AnthropicLLM) convertModelContent(content *genai.Content) map[string]an
 	var blocks []map[string]any
 
 	for _, part := range content.Parts {
+		if part.Thought {
+			// Thinking blocks must be passed back unmodified, with their
+			// signature, for the API to accept a tool-use continuation.
+			switch {
+			case part.Text != "":
+				blocks = append(blocks, map[string]any{
+					"type":      "thinking",
+					"thinking":  part.Text,
+					"signature": string(part.ThoughtSignature),
+				})
+			case len(part.ThoughtSignature) > 0:
+				blocks = append(blocks, map[string]any{
+					"type": "redacted_thinking",
+					"data": string(part.ThoughtSignature),
+				})
+			}
+			continue
+		}
 		if part.Text != "" {
 			blocks = append(blocks, map[string]any{
 				"type": "text",
@@ -289,6 +386,19 @@ func (a *AnthropicLLM) convertResponse(apiResp *anthropicAPIResponse) *adkmodel.
 		switch block.Type {
 		case "text":
 			parts = append(parts, genai.NewPartFromText(block.Text))
+		case "thinking":
+			// Kept as thought parts so they can be replayed on the next turn;
+			// callers extracting the answer skip them.
+			parts = append(parts, &genai.Part{
+				Text:             block.Thinking,
+				Thought:          true,
+				ThoughtSignature: []byte(block.Signature),
+			})
+		case "redacted_thinking":
+			parts = append(parts, &genai.Part{
+				Thought:          true,
+				ThoughtSignature: []byte(block.Data),
+			})
 		case "tool_use":
 			args, ok := block.Input.(map[string]any)
 			if !ok {
@@ -347,28 +457,40 @@ type anthropicAPIResponse struct {
 }
 
 type anthropicContentBlock struct {
-	Type  string `json:"type"`
-	Text  string `json:"text,omitempty"`
-	ID    string `json:"id,omitempty"`
-	Name  string `json:"name,omitempty"`
-	Input any    `json:"input,omitempty"`
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Input     any    `json:"input,omitempty"`
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"` // redacted_thinking payload
 }
 
-// anthropicBetaFeatures returns the anthropic-beta header value for native tools.
-func anthropicBetaFeatures(req *adkmodel.LLMRequest) string {
-	if req.Config == nil {
-		return ""
-	}
-	for _, tool := range req.Config.Tools {
-		if tool.GoogleSearch != nil {
-			return "web-search-2025-03-05"
+// anthropicBetaFeatures returns the anthropic-beta header value for native
+// tools and extended thinking.
+func anthropicBetaFeatures(req *adkmodel.LLMRequest, thinking bool) string {
+	var features []string
+	if req.Config != nil {
+		for _, tool := range req.Config.Tools {
+			if tool.GoogleSearch != nil {
+				features = append(features, "web-search-2025-03-05")
+				break
+			}
 		}
 	}
-	return ""
+	if thinking {
+		features = append(features, thinkingBeta)
+	}
+	return strings.Join(features, ",")
 }
 
 func init() {
 	RegisterProvider("anthropic", func(name string, cfg config.ProviderConfig) adkmodel.LLM {
-		return NewAnthropicLLM(cfg.APIKey)
+		opts := []AnthropicOption{WithAnthropicVersion(cfg.APIVersion), WithAnthropicHeaders(cfg.Headers)}
+		if cfg.URL != "" {
+			opts = append(opts, WithAnthropicBaseURL(cfg.URL))
+		}
+		return NewAnthropicLLM(cfg.APIKey, opts...)
 	})
 }
@@ -11,6 +11,8 @@ import (
 	"google.golang.org/genai"
 
 	adkmodel "google.golang.org/adk/model"
+
+	"github.com/soochol/upal/internal/config"
 )
 
 func TestAnthropicLLM_Name(t *testing.T) {
@@ -620,3 +622,168 @@ func TestAnthropicLLM_MaxTokensFromConfig(t *testing.T) {
 		t.Errorf("max_tokens = %v, want 2048", maxTokens)
 	}
 }
+
+func TestAnthropicLLM_ExtendedThinking(t *testing.T) {
+	var requests []map[string]any
+	var receivedHeaders http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		json.Unmarshal(body, &req)
+		requests = append(requests, req)
+
+		resp := map[string]any{
+			"content": []map[string]any{
+				{"type": "thinking", "thinking": "The user wants a haiku.", "signature": "sig-1"},
+				{"type": "redacted_thinking", "data": "opaque"},
+				{"type": "text", "text": "Autumn moonlight"},
+			},
+			"stop_reason": "end_turn",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	llm := NewAnthropicLLM("test-key", WithAnthropicBaseURL(server.URL))
+
+	budget := int32(2000)
+	temp := float32(0.2)
+	req := &adkmodel.LLMRequest{
+		Model:    "claude-sonnet-4-20250514",
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{genai.NewPartFromText("Write a haiku")}}},
+		Config: &genai.GenerateContentConfig{
+			MaxOutputTokens: 1024,
+			Temperature:     &temp,
+			ThinkingConfig:  &genai.ThinkingConfig{ThinkingBudget: &budget, IncludeThoughts: true},
+		},
+	}
+
+	var logs []string
+	ctx := WithLogFunc(context.Background(), func(msg string) { logs = append(logs, msg) })
+	var resp *adkmodel.LLMResponse
+	for r, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp = r
+	}
+
+	thinking, ok := requests[0]["thinking"].(map[string]any)
+	if !ok {
+		t.Fatal("thinking block missing from request")
+	}
+	if thinking["type"] != "enabled" || thinking["budget_tokens"] != float64(2000) {
+		t.Errorf("thinking = %v, want enabled with budget 2000", thinking)
+	}
+	if got := requests[0]["max_tokens"]; got != float64(2000+defaultMaxTokens) {
+		t.Errorf("max_tokens = %v, want it raised above the budget", got)
+	}
+	if _, ok := requests[0]["temperature"]; ok {
+		t.Error("temperature must be omitted when thinking is enabled")
+	}
+	if got := receivedHeaders.Get("anthropic-beta"); got != thinkingBeta {
+		t.Errorf("anthropic-beta = %q, want %q", got, thinkingBeta)
+	}
+
+	// Thinking is kept as thought parts but is not the answer.
+	if len(resp.Content.Parts) != 3 {
+		t.Fatalf("got %d parts, want 3", len(resp.Content.Parts))
+	}
+	if p := resp.Content.Parts[0]; !p.Thought || p.Text != "The user wants a haiku." || string(p.ThoughtSignature) != "sig-1" {
+		t.Errorf("part[0] = %+v, want thinking part with signature", p)
+	}
+	if p := resp.Content.Parts[1]; !p.Thought || string(p.ThoughtSignature) != "opaque" {
+		t.Errorf("part[1] = %+v, want redacted thinking part", p)
+	}
+	if len(logs) != 1 || logs[0] != "thinking: The user wants a haiku." {
+		t.Errorf("logs = %v, want surfaced thinking text", logs)
+	}
+
+	// Replaying the response on the next turn must send the thinking blocks back intact.
+	req.Contents = append(req.Contents, resp.Content, genai.NewContentFromText("Another", genai.RoleUser))
+	for _, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	assistant := requests[1]["messages"].([]any)[1].(map[string]any)
+	blocks := assistant["content"].([]any)
+	if len(blocks) != 3 {
+		t.Fatalf("replayed assistant blocks = %v, want thinking, redacted_thinking, text", blocks)
+	}
+	first := blocks[0].(map[string]any)
+	if first["type"] != "thinking" || first["signature"] != "sig-1" {
+		t.Errorf("block[0] = %v, want signed thinking block", first)
+	}
+	if second := blocks[1].(map[string]any); second["type"] != "redacted_thinking" || second["data"] != "opaque" {
+		t.Errorf("block[1] = %v, want redacted_thinking block", second)
+	}
+}
+
+func TestAnthropicThinkingBudget_FromEffort(t *testing.T) {
+	req := &adkmodel.LLMRequest{}
+	if got := anthropicThinkingBudget(context.Background(), req); got != 0 {
+		t.Errorf("default budget = %d, want 0 (thinking off)", got)
+	}
+	high := WithEffort(context.Background(), "high")
+	if got := anthropicThinkingBudget(high, req); got != 0 {
+		t.Errorf("high effort without thinking = %d, want 0 (thinking off)", got)
+	}
+	if got := anthropicThinkingBudget(WithThinking(context.Background(), true), req); got != minThinkingBudget {
+		t.Errorf("thinking budget = %d, want %d", got, minThinkingBudget)
+	}
+	if got := anthropicThinkingBudget(WithThinking(high, true), req); got != highEffortThinkingBudget {
+		t.Errorf("high effort thinking budget = %d, want %d", got, highEffortThinkingBudget)
+	}
+	small := int32(100)
+	req.Config = &genai.GenerateContentConfig{ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: &small}}
+	if got := anthropicThinkingBudget(high, req); got != minThinkingBudget {
+		t.Errorf("small budget = %d, want clamped to %d", got, minThinkingBudget)
+	}
+}
+
+func TestAnthropicLLM_ConfiguredVersionAndHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		json.NewEncoder(w).Encode(map[string]any{
+			"content":     []map[string]any{{"type": "text", "text": "ok"}},
+			"stop_reason": "end_turn",
+		})
+	}))
+	defer server.Close()
+
+	llm, ok := BuildLLM("claude", config.ProviderConfig{
+		Type:       "anthropic",
+		URL:        server.URL,
+		APIKey:     "k",
+		APIVersion: "2026-01-01",
+		Headers:    map[string]string{"X-Tenant": "acme"},
+	})
+	if !ok {
+		t.Fatal("BuildLLM returned no LLM for type anthropic")
+	}
+	req := &adkmodel.LLMRequest{Model: "claude-x", Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)}}
+	for _, err := range llm.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v := got.Get("anthropic-version"); v != "2026-01-01" {
+		t.Errorf("anthropic-version = %q, want configured 2026-01-01", v)
+	}
+	if v := got.Get("X-Tenant"); v != "acme" {
+		t.Errorf("X-Tenant = %q, want acme", v)
+	}
+
+	// An empty version keeps the pinned default.
+	llm = NewAnthropicLLM("k", WithAnthropicBaseURL(server.URL), WithAnthropicVersion(""))
+	for range llm.GenerateContent(context.Background(), req, false) {
+	}
+	if v := got.Get("anthropic-version"); v != defaultAnthropicVersion {
+		t.Errorf("anthropic-version = %q, want default %q", v, defaultAnthropicVersion)
+	}
+}
@@ -0,0 +1,231 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/soochol/upal/internal/config"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// ErrCircuitOpen is returned without contacting the provider while its
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is the lifecycle state of a provider circuit breaker.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// BreakerStatus is a point-in-time snapshot of one provider's breaker.
+type BreakerStatus struct {
+	Provider            string       `json:"provider"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAt             *time.Time   `json:"retry_at,omitempty"`
+	LastError           string       `json:"last_error,omitempty"`
+}
+
+// CircuitBreaker fast-fails calls to a provider after Threshold consecutive
+// provider failures within Window. Once Cooldown has elapsed, a single probe
+// call is let through (half-open): success closes the breaker, failure
+// re-opens it for another cooldown.
+type CircuitBreaker struct {
+	provider  string
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu           sync.Mutex
+	state        BreakerState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	probing      bool
+	lastErr      string
+}
+
+// NewCircuitBreaker creates a closed breaker for the named provider.
+func NewCircuitBreaker(provider string, cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		provider:  provider,
+		threshold: cfg.Threshold,
+		window:    cfg.Window,
+		cooldown:  cfg.Cooldown,
+		now:       time.Now,
+		state:     BreakerClosed,
+	}
+}
+
+// Allow reports whether a call may proceed, returning an error wrapping
+// ErrCircuitOpen if not. Every allowed call must be followed by Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return fmt.Errorf("provider %q: %w", b.provider, ErrCircuitOpen)
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("provider %q: %w", b.provider, ErrCircuitOpen)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// Record reports the outcome of an allowed call. Errors that are not provider
+// failures (see IsProviderFailure) count as a healthy response.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !IsProviderFailure(err) {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	now := b.now()
+	b.lastErr = err.Error()
+	if b.state == BreakerHalfOpen {
+		b.state = BreakerOpen
+		b.openedAt = now
+		return
+	}
+	if b.failures == 0 || (b.window > 0 && now.Sub(b.firstFailure) > b.window) {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = now
+	}
+}
+
+// Status returns a snapshot of the breaker.
+func (b *CircuitBreaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st := BreakerStatus{
+		Provider:            b.provider,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastErr,
+	}
+	if b.state != BreakerClosed {
+		opened := b.openedAt
+		retry := opened.Add(b.cooldown)
+		st.OpenedAt = &opened
+		st.RetryAt = &retry
+	}
+	return st
+}
+
+// CircuitBreakers holds one breaker per provider so their state can be
+// reported together.
+type CircuitBreakers struct {
+	cfg config.CircuitBreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+// NewCircuitBreakers creates an empty breaker set. A non-positive
+// cfg.Threshold disables breaking: Wrap returns LLMs unchanged.
+func NewCircuitBreakers(cfg config.CircuitBreakerConfig) *CircuitBreakers {
+	return &CircuitBreakers{cfg: cfg, breakers: make(map[string]*CircuitBreaker)}
+}
+
+// Wrap decorates llm with the breaker for the named provider, creating the
+// breaker on first use.
+func (c *CircuitBreakers) Wrap(provider string, llm adkmodel.LLM) adkmodel.LLM {
+	if c == nil || c.cfg.Threshold <= 0 || llm == nil {
+		return llm
+	}
+	c.mu.Lock()
+	b, ok := c.breakers[provider]
+	if !ok {
+		b = NewCircuitBreaker(provider, c.cfg)
+		c.breakers[provider] = b
+	}
+	c.mu.Unlock()
+
+	wrapped := &breakerLLM{LLM: llm, breaker: b}
+	if native, ok := llm.(NativeToolProvider); ok {
+		return &nativeBreakerLLM{breakerLLM: wrapped, native: native}
+	}
+	return wrapped
+}
+
+// Status returns the state of every known breaker, sorted by provider name.
+func (c *CircuitBreakers) Status() []BreakerStatus {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	out := make([]BreakerStatus, 0, len(c.breakers))
+	for _, b := range c.breakers {
+		out = append(out, b.Status())
+	}
+	c.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
+
+// breakerLLM guards an LLM's GenerateContent calls with a CircuitBreaker.
+type breakerLLM struct {
+	adkmodel.LLM
+	breaker *CircuitBreaker
+}
+
+func (l *breakerLLM) GenerateContent(ctx context.Context, req *adkmodel.LLMRequest, stream bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		if err := l.breaker.Allow(); err != nil {
+			yield(nil, err)
+			return
+		}
+		var callErr error
+		for resp, err := range l.LLM.GenerateContent(ctx, req, stream) {
+			if err != nil && callErr == nil {
+				callErr = err
+			}
+			if !yield(resp, err) {
+				break
+			}
+		}
+		l.breaker.Record(callErr)
+	}
+}
+
+// nativeBreakerLLM preserves the NativeToolProvider capability of the
+// wrapped LLM.
+type nativeBreakerLLM struct {
+	*breakerLLM
+	native NativeToolProvider
+}
+
+func (l *nativeBreakerLLM) NativeTool(name string) (*genai.Tool, bool) {
+	return l.native.NativeTool(name)
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/config"
+	adkmodel "google.golang.org/adk/model"
+)
+
+// flakyLLM returns err from every call and counts how often it was reached.
+type flakyLLM struct {
+	err   error
+	calls int
+}
+
+func (l *flakyLLM) Name() string { return "flaky" }
+
+func (l *flakyLLM) GenerateContent(_ context.Context, _ *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		l.calls++
+		if l.err != nil {
+			yield(nil, l.err)
+			return
+		}
+		yield(&adkmodel.LLMResponse{}, nil)
+	}
+}
+
+func callOnce(llm adkmodel.LLM) error {
+	for _, err := range llm.GenerateContent(context.Background(), &adkmodel.LLMRequest{}, false) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestBreakers(clock *time.Time) (*CircuitBreakers, func(string, adkmodel.LLM) adkmodel.LLM) {
+	cbs := NewCircuitBreakers(config.CircuitBreakerConfig{Threshold: 3, Window: time.Minute, Cooldown: 30 * time.Second})
+	wrap := func(name string, llm adkmodel.LLM) adkmodel.LLM {
+		wrapped := cbs.Wrap(name, llm)
+		cbs.breakers[name].now = func() time.Time { return *clock }
+		return wrapped
+	}
+	return cbs, wrap
+}
+
+func TestCircuitBreaker_OpenCooldownRecover(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	cbs, wrap := newTestBreakers(&clock)
+	inner := &flakyLLM{err: &APIError{Provider: "Test", StatusCode: http.StatusServiceUnavailable}}
+	llm := wrap("test", inner)
+
+	for i := 0; i < 3; i++ {
+		if err := callOnce(llm); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: breaker opened too early", i)
+		}
+	}
+	if st := cbs.Status()[0]; st.State != BreakerOpen {
+		t.Fatalf("state = %s, want open", st.State)
+	}
+
+	// Open: fast-fail without reaching the provider.
+	if err := callOnce(llm); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if inner.calls != 3 {
+		t.Fatalf("provider calls = %d, want 3", inner.calls)
+	}
+
+	// After cooldown a failing probe re-opens the breaker.
+	clock = clock.Add(31 * time.Second)
+	if err := callOnce(llm); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("expected probe to reach provider")
+	}
+	if st := cbs.Status()[0]; st.State != BreakerOpen {
+		t.Fatalf("state after failed probe = %s, want open", st.State)
+	}
+	if err := callOnce(llm); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen after failed probe, got %v", err)
+	}
+
+	// A successful probe closes it.
+	clock = clock.Add(31 * time.Second)
+	inner.err = nil
+	if err := callOnce(llm); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	st := cbs.Status()[0]
+	if st.State != BreakerClosed || st.ConsecutiveFailures != 0 {
+		t.Fatalf("status after recovery = %+v, want closed", st)
+	}
+}
+
+func TestCircuitBreaker_IgnoresClientErrors(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	cbs, wrap := newTestBreakers(&clock)
+	llm := wrap("test", &flakyLLM{err: &APIError{Provider: "Test", StatusCode: http.StatusBadRequest}})
+
+	for i := 0; i < 5; i++ {
+		callOnce(llm)
+	}
+	if st := cbs.Status()[0]; st.State != BreakerClosed {
+		t.Fatalf("state = %s, want closed for 4xx errors", st.State)
+	}
+}
+
+func TestCircuitBreaker_FailuresOutsideWindowReset(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	cbs, wrap := newTestBreakers(&clock)
+	llm := wrap("test", &flakyLLM{err: &APIError{Provider: "Test", StatusCode: http.StatusBadGateway}})
+
+	callOnce(llm)
+	callOnce(llm)
+	clock = clock.Add(2 * time.Minute)
+	callOnce(llm)
+	st := cbs.Status()[0]
+	if st.State != BreakerClosed || st.ConsecutiveFailures != 1 {
+		t.Fatalf("status = %+v, want closed with 1 failure", st)
+	}
+}
+
+func TestIsProviderFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", &APIError{StatusCode: http.StatusInternalServerError}, true},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"local error", errors.New("marshal request: boom"), false},
+	}
+	for _, tt := range tests {
+		if got := IsProviderFailure(tt.err); got != tt.want {
+			t.Errorf("%s: IsProviderFailure = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
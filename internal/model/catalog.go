@@ -1,6 +1,8 @@
 package model
 
 import (
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/soochol/upal/internal/config"
@@ -120,10 +122,12 @@ var knownModels = map[string][]modelEntry{
 
 // KnownModelIDs returns the list of available model IDs (provider/model format)
 // derived from provider configs. Used to inject into LLM prompts so the generator
-// and configurator can select from actually available models.
+// and configurator can select from actually available models. Providers are
+// listed in name order so prompts are stable across restarts.
 func KnownModelIDs(configs map[string]config.ProviderConfig) []string {
 	var ids []string
-	for name, pc := range configs {
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		pc := configs[name]
 		if known, ok := knownModels[pc.Type]; ok {
 			for _, m := range known {
 				ids = append(ids, name+"/"+m.Name)
@@ -135,9 +139,11 @@ func KnownModelIDs(configs map[string]config.ProviderConfig) []string {
 
 // KnownModelsGrouped returns all ModelInfo entries with category/tier metadata.
 // Used by the generator to inject categorized model guidance into prompts.
+// Providers are listed in name order.
 func KnownModelsGrouped(configs map[string]config.ProviderConfig) []upal.ModelInfo {
 	var models []upal.ModelInfo
-	for name, pc := range configs {
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		pc := configs[name]
 		cat := modelCategoryByType[pc.Type]
 		if known, ok := knownModels[pc.Type]; ok {
 			for _, m := range known {
@@ -156,10 +162,12 @@ func KnownModelsGrouped(configs map[string]config.ProviderConfig) []upal.ModelIn
 	return models
 }
 
-// AllStaticModels returns the full list of statically known models with options populated.
+// AllStaticModels returns the full list of statically known models with options
+// populated, with providers in name order.
 func AllStaticModels(configs map[string]config.ProviderConfig) []upal.ModelInfo {
 	var models []upal.ModelInfo
-	for name, pc := range configs {
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		pc := configs[name]
 		cat, opts := OptionsForType(pc.Type)
 		if known, ok := knownModels[pc.Type]; ok {
 			for _, m := range known {
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/soochol/upal/internal/config"
@@ -27,3 +29,20 @@ func TestIsOllama(t *testing.T) {
 		})
 	}
 }
+
+func TestKnownModelIDs_ProviderOrder(t *testing.T) {
+	configs := map[string]config.ProviderConfig{
+		"zeta":  {Type: "anthropic"},
+		"alpha": {Type: "anthropic"},
+		"mid":   {Type: "gemini"},
+	}
+	first := KnownModelIDs(configs)
+	for i := 0; i < 20; i++ {
+		if got := KnownModelIDs(configs); !slices.Equal(got, first) {
+			t.Fatalf("order changed between calls:\n%v\n%v", first, got)
+		}
+	}
+	if !strings.HasPrefix(first[0], "alpha/") || !strings.HasPrefix(first[len(first)-1], "zeta/") {
+		t.Errorf("providers not in name order: %v", first)
+	}
+}
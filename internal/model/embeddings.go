@@ -0,0 +1,74 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OpenAIEmbedder calls the OpenAI-compatible /embeddings endpoint.
+type OpenAIEmbedder struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAIEmbedder creates an embedder for model. An empty baseURL uses the
+// OpenAI API.
+func NewOpenAIEmbedder(apiKey, baseURL, model string) *OpenAIEmbedder {
+	if baseURL == "" {
+		baseURL = openaiDefaultBaseURL
+	}
+	return &OpenAIEmbedder{apiKey: apiKey, baseURL: baseURL, model: model, client: http.DefaultClient}
+}
+
+// Embed returns one vector per input text, in input order.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	encoded, err := json.Marshal(map[string]any{"model": e.model, "input": texts})
+	if err != nil {
+		return nil, fmt.Errorf("embeddings: marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("embeddings: create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embeddings: %w", &APIError{Provider: "OpenAI", StatusCode: resp.StatusCode, Body: string(body)})
+	}
+
+	var out struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("embeddings: decode response: %w", err)
+	}
+	if len(out.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings: got %d vectors for %d inputs", len(out.Data), len(texts))
+	}
+	vectors := make([][]float64, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings: response index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
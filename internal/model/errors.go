@@ -0,0 +1,49 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"google.golang.org/genai"
+)
+
+// APIError is returned when a provider's HTTP API responds with a
+// non-success status code.
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// IsProviderFailure reports whether err indicates that the provider itself is
+// unhealthy — transport failures, rate limiting, or 5xx responses — as opposed
+// to a bad request or a cancelled caller. Only these failures count towards
+// tripping a circuit breaker.
+func IsProviderFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	status := 0
+	var apiErr *APIError
+	var genaiErr genai.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.StatusCode
+	case errors.As(err, &genaiErr):
+		status = genaiErr.Code
+	}
+	if status == 0 {
+		// Without a response only transport failures (DNS, connection refused,
+		// timeouts) implicate the provider; encoding errors are our own.
+		var netErr net.Error
+		return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+	}
+	return status == http.StatusTooManyRequests || status >= 500
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// OpenAIModerator calls the OpenAI-compatible /moderations endpoint.
+type OpenAIModerator struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAIModerator creates a moderator for model. An empty baseURL uses the
+// OpenAI API; an empty model lets the endpoint pick its default.
+func NewOpenAIModerator(apiKey, baseURL, model string) *OpenAIModerator {
+	if baseURL == "" {
+		baseURL = openaiDefaultBaseURL
+	}
+	return &OpenAIModerator{apiKey: apiKey, baseURL: baseURL, model: model, client: http.DefaultClient}
+}
+
+// Moderate screens texts and returns the sorted categories flagged in any of
+// them. An empty result means every text passed.
+func (m *OpenAIModerator) Moderate(ctx context.Context, texts []string) ([]string, error) {
+	reqBody := map[string]any{"input": texts}
+	if m.model != "" {
+		reqBody["model"] = m.model
+	}
+	encoded, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("moderation: marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/moderations", bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("moderation: create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("moderation: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("moderation: %w", &APIError{Provider: "OpenAI", StatusCode: resp.StatusCode, Body: string(body)})
+	}
+
+	var out struct {
+		Results []struct {
+			Flagged    bool            `json:"flagged"`
+			Categories map[string]bool `json:"categories"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("moderation: decode response: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var flagged []string
+	for _, r := range out.Results {
+		if !r.Flagged {
+			continue
+		}
+		n := len(flagged)
+		for cat, hit := range r.Categories {
+			if hit && !seen[cat] {
+				seen[cat] = true
+				flagged = append(flagged, cat)
+			}
+		}
+		if len(flagged) == n && !seen["flagged"] {
+			// Flagged without a category breakdown.
+			seen["flagged"] = true
+			flagged = append(flagged, "flagged")
+		}
+	}
+	sort.Strings(flagged)
+	return flagged, nil
+}
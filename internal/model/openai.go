@@ -41,12 +41,36 @@ func WithOpenAIName(name string) OpenAIOption {
 	}
 }
 
+// WithOpenAIHeaders adds extra headers to every chat completion request.
+// They are applied last, so they can also replace the default headers.
+func WithOpenAIHeaders(headers map[string]string) OpenAIOption {
+	return func(o *OpenAILLM) {
+		o.headers = headers
+	}
+}
+
+// logitBiasKey is the context key for an OpenAI logit_bias map.
+type logitBiasKey struct{}
+
+// WithLogitBias returns a context carrying an OpenAI logit_bias map (token ID
+// to bias in [-100, 100]). genai.GenerateContentConfig has no equivalent field,
+// so OpenAILLM reads it from the context.
+func WithLogitBias(ctx context.Context, bias map[string]float64) context.Context {
+	return context.WithValue(ctx, logitBiasKey{}, bias)
+}
+
+// CandidatesMetadataKey is the LLMResponse.CustomMetadata key under which
+// OpenAILLM reports the text of every completion when more than one was
+// requested (CandidateCount > 1). Content always holds the first.
+const CandidatesMetadataKey = "candidates"
+
 // OpenAILLM implements the ADK model.LLM interface for the OpenAI Chat Completions API.
 // It also works with OpenAI-compatible APIs such as Ollama and LM Studio.
 type OpenAILLM struct {
 	apiKey  string
 	baseURL string
 	name    string
+	headers map[string]string
 	client  *http.Client
 }
 
@@ -79,7 +103,7 @@ func (o *OpenAILLM) Name() string {
 func (o *OpenAILLM) GenerateContent(ctx context.Context, req *adkmodel.LLMRequest, stream bool) iter.Seq2[*adkmodel.LLMResponse, error] {
 	return func(yield func(*adkmodel.LLMResponse, error) bool) {
 		// Build the OpenAI request body.
-		body, err := o.buildRequestBody(req)
+		body, err := o.buildRequestBody(ctx, req)
 		if err != nil {
 			yield(nil, fmt.Errorf("openai: failed to build request: %w", err))
 			return
@@ -101,6 +125,9 @@ func (o *OpenAILLM) GenerateContent(ctx context.Context, req *adkmodel.LLMReques
 		if o.apiKey != "" {
 			httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
 		}
+		for k, v := range o.headers {
+			httpReq.Header.Set(k, v)
+		}
 
 		httpResp, err := o.client.Do(httpReq)
 		if err != nil {
@@ -116,7 +143,7 @@ func (o *OpenAILLM) GenerateContent(ctx context.Context, req *adkmodel.LLMReques
 		}
 
 		if httpResp.StatusCode != http.StatusOK {
-			yield(nil, fmt.Errorf("openai: API returned status %d: %s", httpResp.StatusCode, string(respBody)))
+			yield(nil, fmt.Errorf("openai: %w", &APIError{Provider: "OpenAI", StatusCode: httpResp.StatusCode, Body: string(respBody)}))
 			return
 		}
 
@@ -137,7 +164,7 @@ func (o *OpenAILLM) GenerateContent(ctx context.Context, req *adkmodel.LLMReques
 }
 
 // buildRequestBody converts an LLMRequest into an OpenAI chat completions request body.
-func (o *OpenAILLM) buildRequestBody(req *adkmodel.LLMRequest) (map[string]any, error) {
+func (o *OpenAILLM) buildRequestBody(ctx context.Context, req *adkmodel.LLMRequest) (map[string]any, error) {
 	body := map[string]any{
 		"model":  req.Model,
 		"stream": false,
@@ -195,6 +222,12 @@ func (o *OpenAILLM) buildRequestBody(req *adkmodel.LLMRequest) (map[string]any,
 		if req.Config.PresencePenalty != nil {
 			body["presence_penalty"] = *req.Config.PresencePenalty
 		}
+		if req.Config.CandidateCount > 1 {
+			body["n"] = req.Config.CandidateCount
+		}
+	}
+	if bias, ok := ctx.Value(logitBiasKey{}).(map[string]float64); ok && len(bias) > 0 {
+		body["logit_bias"] = bias
 	}
 
 	return body, nil
@@ -391,6 +424,24 @@ func (o *OpenAILLM) convertResponse(resp *openaiChatResponse) (*adkmodel.LLMResp
 		TurnComplete: true,
 	}
 
+	// Map OpenAI finish_reason to genai.FinishReason
+	switch choice.FinishReason {
+	case "stop", "tool_calls":
+		llmResp.FinishReason = genai.FinishReasonStop
+	case "length":
+		llmResp.FinishReason = genai.FinishReasonMaxTokens
+	case "content_filter":
+		llmResp.FinishReason = genai.FinishReasonSafety
+	}
+
+	if len(resp.Choices) > 1 {
+		candidates := make([]string, len(resp.Choices))
+		for i, c := range resp.Choices {
+			candidates[i] = c.Message.Content
+		}
+		llmResp.CustomMetadata = map[string]any{CandidatesMetadataKey: candidates}
+	}
+
 	if resp.Usage.TotalTokens > 0 {
 		llmResp.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{
 			PromptTokenCount:     resp.Usage.PromptTokens,
@@ -468,7 +519,7 @@ type openaiFunction struct {
 
 func init() {
 	RegisterProvider("openai", func(name string, cfg config.ProviderConfig) adkmodel.LLM {
-		opts := []OpenAIOption{WithOpenAIName(name)}
+		opts := []OpenAIOption{WithOpenAIName(name), WithOpenAIHeaders(cfg.Headers)}
 		if cfg.URL != "" {
 			opts = append(opts, WithOpenAIBaseURL(cfg.URL))
 		}
@@ -492,3 +492,85 @@ func TestOpenAILLM_NoAuthHeader(t *testing.T) {
 		}
 	}
 }
+
+func TestOpenAILLM_LogitBiasAndCandidates(t *testing.T) {
+	var reqBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		resp := map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]any{"role": "assistant", "content": "first"}, "finish_reason": "stop"},
+				{"message": map[string]any{"role": "assistant", "content": "second"}, "finish_reason": "stop"},
+				{"message": map[string]any{"role": "assistant", "content": "third"}, "finish_reason": "length"},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	llm := NewOpenAILLM("test-key", WithOpenAIBaseURL(server.URL))
+	req := &adkmodel.LLMRequest{
+		Model:    "gpt-4o",
+		Config:   &genai.GenerateContentConfig{CandidateCount: 3},
+		Contents: []*genai.Content{genai.NewContentFromText("Name a color", genai.RoleUser)},
+	}
+	ctx := WithLogitBias(context.Background(), map[string]float64{"50256": -100, "1234": 5})
+
+	var resp *adkmodel.LLMResponse
+	for r, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent returned error: %v", err)
+		}
+		resp = r
+	}
+
+	if reqBody["n"] != 3.0 {
+		t.Errorf("n = %v, want 3", reqBody["n"])
+	}
+	bias, ok := reqBody["logit_bias"].(map[string]any)
+	if !ok || bias["50256"] != -100.0 || bias["1234"] != 5.0 {
+		t.Errorf("logit_bias = %v", reqBody["logit_bias"])
+	}
+
+	if got := resp.Content.Parts[0].Text; got != "first" {
+		t.Errorf("content = %q, want first", got)
+	}
+	candidates, _ := resp.CustomMetadata[CandidatesMetadataKey].([]string)
+	if len(candidates) != 3 || candidates[0] != "first" || candidates[1] != "second" || candidates[2] != "third" {
+		t.Errorf("candidates = %v", resp.CustomMetadata[CandidatesMetadataKey])
+	}
+}
+
+func TestOpenAILLM_OmitsLogitBiasAndNByDefault(t *testing.T) {
+	var reqBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{
+			{"message": map[string]any{"role": "assistant", "content": "only"}, "finish_reason": "stop"},
+		}})
+	}))
+	defer server.Close()
+
+	llm := NewOpenAILLM("test-key", WithOpenAIBaseURL(server.URL))
+	req := &adkmodel.LLMRequest{
+		Model:    "gpt-4o",
+		Config:   &genai.GenerateContentConfig{CandidateCount: 1},
+		Contents: []*genai.Content{genai.NewContentFromText("Hi", genai.RoleUser)},
+	}
+	for resp, err := range llm.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent returned error: %v", err)
+		}
+		if resp.CustomMetadata != nil {
+			t.Errorf("CustomMetadata = %v, want nil for a single choice", resp.CustomMetadata)
+		}
+	}
+	if _, ok := reqBody["n"]; ok {
+		t.Errorf("n should be omitted, got %v", reqBody["n"])
+	}
+	if _, ok := reqBody["logit_bias"]; ok {
+		t.Errorf("logit_bias should be omitted, got %v", reqBody["logit_bias"])
+	}
+}
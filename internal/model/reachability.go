@@ -0,0 +1,143 @@
+package model
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/soochol/upal/internal/config"
+)
+
+const (
+	defaultReachabilityTTL     = time.Minute
+	defaultReachabilityTimeout = 5 * time.Second
+	geminiDefaultBaseURL       = "https://generativelanguage.googleapis.com"
+)
+
+// Reachability is the result of probing a provider's endpoint. Any HTTP
+// response counts as reachable, including auth failures; only transport
+// errors (DNS, refused connections, timeouts) do not. Error names the kind of
+// failure rather than quoting it, since transport errors include the probe
+// URL and a configured URL may carry credentials.
+type Reachability struct {
+	Reachable bool      `json:"reachable"`
+	LatencyMS int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Reachability error categories.
+const (
+	reachTimeout     = "timeout"
+	reachDNS         = "dns lookup failed"
+	reachRefused     = "connection refused"
+	reachTLS         = "tls handshake failed"
+	reachInvalidURL  = "invalid url"
+	reachUnreachable = "unreachable"
+)
+
+// ReachabilityChecker probes provider endpoints and caches each result for a
+// TTL, so status pages can poll it without hammering providers.
+type ReachabilityChecker struct {
+	client *http.Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]Reachability // keyed by probe URL
+}
+
+// NewReachabilityChecker returns a checker caching results for ttl. A
+// non-positive ttl uses one minute.
+func NewReachabilityChecker(ttl time.Duration) *ReachabilityChecker {
+	if ttl <= 0 {
+		ttl = defaultReachabilityTTL
+	}
+	return &ReachabilityChecker{
+		client: &http.Client{Timeout: defaultReachabilityTimeout},
+		ttl:    ttl,
+		now:    time.Now,
+		cache:  make(map[string]Reachability),
+	}
+}
+
+// Check returns the cached reachability of pc's endpoint, probing it when
+// the cached result is missing or stale. ok is false when the provider has
+// no network endpoint to probe (e.g. the Claude Code CLI).
+func (c *ReachabilityChecker) Check(ctx context.Context, pc config.ProviderConfig) (res Reachability, ok bool) {
+	url := ProbeURL(pc)
+	if url == "" {
+		return Reachability{}, false
+	}
+	c.mu.Lock()
+	cached, hit := c.cache[url]
+	c.mu.Unlock()
+	if hit && c.now().Sub(cached.CheckedAt) < c.ttl {
+		return cached, true
+	}
+
+	res = Reachability{CheckedAt: c.now()}
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		res.Error = reachInvalidURL
+	} else if resp, err := c.client.Do(req); err != nil {
+		res.Error = reachabilityError(err)
+	} else {
+		resp.Body.Close()
+		res.Reachable = true
+	}
+	res.LatencyMS = time.Since(start).Milliseconds()
+
+	// A probe cut short by the caller says nothing about the provider.
+	if ctx.Err() == nil {
+		c.mu.Lock()
+		c.cache[url] = res
+		c.mu.Unlock()
+	}
+	return res, true
+}
+
+// reachabilityError maps a transport error to its category.
+func reachabilityError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var recordErr tls.RecordHeaderError
+	switch {
+	case errors.As(err, &dnsErr):
+		return reachDNS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return reachTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return reachRefused
+	case errors.As(err, &certErr), errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr), errors.As(err, &recordErr):
+		return reachTLS
+	}
+	return reachUnreachable
+}
+
+// ProbeURL returns the base URL a provider's requests go to: its configured
+// URL, else the default for its type, else "".
+func ProbeURL(pc config.ProviderConfig) string {
+	if pc.URL != "" {
+		return pc.URL
+	}
+	switch pc.Type {
+	case "anthropic":
+		return defaultAnthropicBaseURL
+	case "openai", "openai-tts":
+		return openaiDefaultBaseURL
+	case "gemini", "gemini-image":
+		return geminiDefaultBaseURL
+	}
+	return DefaultURLForType(pc.Type)
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/config"
+)
+
+func TestReachabilityChecker_CachesForTTL(t *testing.T) {
+	var hits atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer upstream.Close()
+
+	now := time.Now()
+	c := NewReachabilityChecker(time.Minute)
+	c.now = func() time.Time { return now }
+	pc := config.ProviderConfig{Type: "openai", URL: upstream.URL}
+
+	for range 2 {
+		res, ok := c.Check(context.Background(), pc)
+		if !ok || !res.Reachable {
+			t.Fatalf("Check = %+v, %v; want reachable", res, ok)
+		}
+	}
+	if hits.Load() != 1 {
+		t.Errorf("probes within TTL = %d, want 1", hits.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	c.Check(context.Background(), pc)
+	if hits.Load() != 2 {
+		t.Errorf("probes after TTL = %d, want 2", hits.Load())
+	}
+}
+
+func TestReachabilityChecker_NoEndpoint(t *testing.T) {
+	if _, ok := NewReachabilityChecker(0).Check(context.Background(), config.ProviderConfig{Type: "claude-code"}); ok {
+		t.Error("claude-code provider reported a probe result")
+	}
+	if got := ProbeURL(config.ProviderConfig{Type: "anthropic"}); got != defaultAnthropicBaseURL {
+		t.Errorf("ProbeURL(anthropic) = %q", got)
+	}
+}
+
+func TestReachabilityChecker_ErrorOmitsURL(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	url := upstream.URL
+	upstream.Close() // nothing listens there any more
+
+	pc := config.ProviderConfig{Type: "openai", URL: "http://user:s3cret@" + strings.TrimPrefix(url, "http://") + "/v1?key=s3cret"}
+	res, ok := NewReachabilityChecker(0).Check(context.Background(), pc)
+	if !ok || res.Reachable {
+		t.Fatalf("Check = %+v, %v; want unreachable", res, ok)
+	}
+	if res.Error != reachRefused {
+		t.Errorf("Error = %q, want %q", res.Error, reachRefused)
+	}
+}
+
+func TestReachabilityError_Categories(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&net.DNSError{Err: "no such host", Name: "api.example.com"}, reachDNS},
+		{fmt.Errorf("Get %q: %w", "https://api.example.com", context.DeadlineExceeded), reachTimeout},
+		{errors.New("Get \"https://api.example.com\": EOF"), reachUnreachable},
+	}
+	for _, tt := range tests {
+		if got := reachabilityError(tt.err); got != tt.want {
+			t.Errorf("reachabilityError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -29,7 +29,8 @@ func BuildLLM(providerName string, cfg config.ProviderConfig) (adkmodel.LLM, boo
 	if cfg.URL != "" {
 		return NewOpenAILLM(cfg.APIKey,
 			WithOpenAIBaseURL(cfg.URL),
-			WithOpenAIName(providerName)), true
+			WithOpenAIName(providerName),
+			WithOpenAIHeaders(cfg.Headers)), true
 	}
 	return nil, false
 }
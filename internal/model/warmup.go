@@ -0,0 +1,133 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/soochol/upal/internal/config"
+	"github.com/soochol/upal/internal/upal"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+const defaultWarmupTimeout = time.Minute
+
+var (
+	// ErrUnknownProvider is returned by Warmer.Warm for an unconfigured provider.
+	ErrUnknownProvider = errors.New("unknown provider")
+	// ErrNotLocalProvider is returned by Warmer.Warm for remote providers,
+	// which have no cold start worth paying for.
+	ErrNotLocalProvider = errors.New("not a local provider")
+)
+
+// WarmupResult reports one warm-up request.
+type WarmupResult struct {
+	Provider  string `json:"provider"`
+	Model     string `json:"model"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Warmer sends a one-token request to local providers so the model is loaded
+// before the first real call.
+type Warmer struct {
+	llms    map[string]adkmodel.LLM
+	configs map[string]config.ProviderConfig
+	cfg     config.WarmupConfig
+	// discover returns the provider's first installed model; swapped in tests.
+	discover func(name string, pc config.ProviderConfig) string
+}
+
+// NewWarmer returns a Warmer for the local providers among configs that have
+// an entry in llms.
+func NewWarmer(llms map[string]adkmodel.LLM, configs map[string]config.ProviderConfig, cfg config.WarmupConfig) *Warmer {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultWarmupTimeout
+	}
+	return &Warmer{llms: llms, configs: configs, cfg: cfg, discover: firstOllamaModel}
+}
+
+// Preflight warms every local provider when warm-up is enabled.
+func (w *Warmer) Preflight(ctx context.Context) []WarmupResult {
+	if !w.cfg.Enabled {
+		return nil
+	}
+	return w.WarmAll(ctx)
+}
+
+// WarmAll warms every local provider in turn and returns one result each.
+func (w *Warmer) WarmAll(ctx context.Context) []WarmupResult {
+	var results []WarmupResult
+	for name, pc := range w.configs {
+		if _, ok := w.llms[name]; !ok || !IsOllama(pc) {
+			continue
+		}
+		res, _ := w.Warm(ctx, name)
+		results = append(results, res)
+	}
+	return results
+}
+
+// Warm sends one warm-up request to the named provider and logs its latency.
+// A failed request is reported in the result's Error; the returned error is
+// only set when the provider cannot be warmed at all.
+func (w *Warmer) Warm(ctx context.Context, name string) (WarmupResult, error) {
+	res := WarmupResult{Provider: name}
+	llm, ok := w.llms[name]
+	pc, hasConfig := w.configs[name]
+	if !ok || !hasConfig {
+		return res, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+	if !IsOllama(pc) {
+		return res, fmt.Errorf("%w: %q", ErrNotLocalProvider, name)
+	}
+
+	res.Model = w.cfg.Models[name]
+	if res.Model == "" {
+		res.Model = w.discover(name, pc)
+	}
+	if res.Model == "" {
+		res.Error = "no model to warm up"
+		slog.Warn("provider warm-up skipped", "provider", name, "reason", res.Error)
+		return res, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+	defer cancel()
+	req := &adkmodel.LLMRequest{
+		Model:    res.Model,
+		Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)},
+		Config:   &genai.GenerateContentConfig{MaxOutputTokens: 1},
+	}
+	start := time.Now()
+	for _, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			res.Error = err.Error()
+			break
+		}
+	}
+	res.LatencyMS = time.Since(start).Milliseconds()
+
+	if res.Error != "" {
+		slog.Warn("provider warm-up failed", "provider", name, "model", res.Model, "latency_ms", res.LatencyMS, "err", res.Error)
+	} else {
+		slog.Info("provider warmed up", "provider", name, "model", res.Model, "latency_ms", res.LatencyMS)
+	}
+	return res, nil
+}
+
+// firstOllamaModel returns the first model installed on an Ollama provider.
+func firstOllamaModel(name string, pc config.ProviderConfig) string {
+	url := pc.URL
+	if url == "" {
+		url = DefaultURLForType(pc.Type)
+	}
+	models := DiscoverOllamaModels(name, url, upal.ModelCategoryText, nil)
+	if len(models) == 0 {
+		return ""
+	}
+	return models[0].Name
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/soochol/upal/internal/config"
+	adkmodel "google.golang.org/adk/model"
+)
+
+// recordingLLM records the model of every request it receives.
+type recordingLLM struct{ models []string }
+
+func (l *recordingLLM) Name() string { return "recording" }
+
+func (l *recordingLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		l.models = append(l.models, req.Model)
+		yield(&adkmodel.LLMResponse{}, nil)
+	}
+}
+
+func newTestWarmer(cfg config.WarmupConfig) (*Warmer, *recordingLLM, *recordingLLM) {
+	local, remote := &recordingLLM{}, &recordingLLM{}
+	w := NewWarmer(
+		map[string]adkmodel.LLM{"local": local, "remote": remote},
+		map[string]config.ProviderConfig{
+			"local":  {Type: "ollama", URL: "http://localhost:11434"},
+			"remote": {Type: "anthropic", APIKey: "k"},
+		},
+		cfg,
+	)
+	w.discover = func(string, config.ProviderConfig) string { return "llama3" }
+	return w, local, remote
+}
+
+func TestWarmer_PreflightWarmsLocalProviders(t *testing.T) {
+	w, local, remote := newTestWarmer(config.WarmupConfig{Enabled: true})
+
+	results := w.Preflight(context.Background())
+	if len(results) != 1 || results[0].Provider != "local" || results[0].Model != "llama3" || results[0].Error != "" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if len(local.models) != 1 || local.models[0] != "llama3" {
+		t.Errorf("local provider calls = %v, want one warm-up for llama3", local.models)
+	}
+	if len(remote.models) != 0 {
+		t.Errorf("remote provider was warmed: %v", remote.models)
+	}
+}
+
+func TestWarmer_PreflightDisabled(t *testing.T) {
+	w, local, _ := newTestWarmer(config.WarmupConfig{})
+	if results := w.Preflight(context.Background()); results != nil || len(local.models) != 0 {
+		t.Errorf("disabled preflight warmed providers: %+v", results)
+	}
+}
+
+func TestWarmer_Warm(t *testing.T) {
+	w, local, _ := newTestWarmer(config.WarmupConfig{Models: map[string]string{"local": "qwen2"}})
+	ctx := context.Background()
+
+	res, err := w.Warm(ctx, "local")
+	if err != nil || res.Model != "qwen2" || len(local.models) != 1 || local.models[0] != "qwen2" {
+		t.Errorf("configured model not warmed: res=%+v err=%v calls=%v", res, err, local.models)
+	}
+	if _, err := w.Warm(ctx, "remote"); !errors.Is(err, ErrNotLocalProvider) {
+		t.Errorf("remote err = %v, want ErrNotLocalProvider", err)
+	}
+	if _, err := w.Warm(ctx, "missing"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("missing err = %v, want ErrUnknownProvider", err)
+	}
+}
@@ -3,8 +3,10 @@ package notify
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
+	"github.com/soochol/upal/internal/storage"
 	"github.com/soochol/upal/internal/upal"
 )
 
@@ -16,14 +18,61 @@ type Sender interface {
 	Send(ctx context.Context, conn *upal.Connection, message string) error
 }
 
+// SenderDeps carries shared services that sender factories may use.
+type SenderDeps struct {
+	// Storage resolves file IDs, e.g. SMTP attachments.
+	Storage storage.Storage
+}
+
+// SenderFactory creates the sender for a connection type.
+type SenderFactory func(deps SenderDeps) (Sender, error)
+
+var (
+	senderTypesMu sync.RWMutex
+	senderTypes   = map[upal.ConnectionType]SenderFactory{
+		upal.ConnTypeTelegram: func(SenderDeps) (Sender, error) { return &TelegramSender{}, nil },
+		upal.ConnTypeSlack:    func(SenderDeps) (Sender, error) { return &SlackSender{}, nil },
+		upal.ConnTypeSMTP:     func(d SenderDeps) (Sender, error) { return &SMTPSender{Storage: d.Storage}, nil },
+		upal.ConnTypeWebhook:  func(SenderDeps) (Sender, error) { return &WebhookSender{}, nil },
+	}
+)
+
+// RegisterSenderType makes a connection type available to every registry
+// created by NewDefaultSenderRegistry afterwards. Plugins call it from an
+// init function; registering a built-in type replaces its sender.
+func RegisterSenderType(connType upal.ConnectionType, factory SenderFactory) {
+	senderTypesMu.Lock()
+	defer senderTypesMu.Unlock()
+	senderTypes[connType] = factory
+}
+
 // SenderRegistry maps connection types to their senders.
 type SenderRegistry struct {
-	mu      sync.RWMutex
-	senders map[upal.ConnectionType]Sender
+	mu        sync.RWMutex
+	deps      SenderDeps
+	senders   map[upal.ConnectionType]Sender
+	factories map[upal.ConnectionType]SenderFactory
 }
 
 func NewSenderRegistry() *SenderRegistry {
-	return &SenderRegistry{senders: make(map[upal.ConnectionType]Sender)}
+	return &SenderRegistry{
+		senders:   make(map[upal.ConnectionType]Sender),
+		factories: make(map[upal.ConnectionType]SenderFactory),
+	}
+}
+
+// NewDefaultSenderRegistry returns a registry with the built-in senders and
+// any types added through RegisterSenderType. Factories run on first use
+// with deps.
+func NewDefaultSenderRegistry(deps SenderDeps) *SenderRegistry {
+	r := NewSenderRegistry()
+	r.deps = deps
+	senderTypesMu.RLock()
+	defer senderTypesMu.RUnlock()
+	for connType, factory := range senderTypes {
+		r.factories[connType] = factory
+	}
+	return r
 }
 
 // Register adds a sender for a connection type.
@@ -31,15 +80,57 @@ func (r *SenderRegistry) Register(s Sender) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.senders[s.Type()] = s
+	delete(r.factories, s.Type())
 }
 
-// Get returns the sender for the given connection type.
-func (r *SenderRegistry) Get(connType upal.ConnectionType) (Sender, error) {
+// RegisterType registers a factory for a connection type name. The sender is
+// created on first use and cached; registering the type again replaces it.
+func (r *SenderRegistry) RegisterType(connType upal.ConnectionType, factory SenderFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[connType] = factory
+	delete(r.senders, connType)
+}
+
+// Types returns the connection types that have a sender, sorted.
+func (r *SenderRegistry) Types() []upal.ConnectionType {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	types := make([]upal.ConnectionType, 0, len(r.senders)+len(r.factories))
+	for t := range r.senders {
+		types = append(types, t)
+	}
+	for t := range r.factories {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// Get returns the sender for the given connection type, creating it from its
+// factory on first use.
+func (r *SenderRegistry) Get(connType upal.ConnectionType) (Sender, error) {
+	r.mu.RLock()
 	s, ok := r.senders[connType]
+	r.mu.RUnlock()
+	if ok {
+		return s, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.senders[connType]; ok {
+		return s, nil
+	}
+	factory, ok := r.factories[connType]
 	if !ok {
 		return nil, fmt.Errorf("no sender registered for connection type %q", connType)
 	}
+	s, err := factory(r.deps)
+	if err != nil {
+		return nil, fmt.Errorf("create sender for connection type %q: %w", connType, err)
+	}
+	r.senders[connType] = s
+	delete(r.factories, connType)
 	return s, nil
 }
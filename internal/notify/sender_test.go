@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/soochol/upal/internal/storage"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type recordingSender struct {
+	connType upal.ConnectionType
+	messages []string
+}
+
+func (s *recordingSender) Type() upal.ConnectionType { return s.connType }
+
+func (s *recordingSender) Send(_ context.Context, _ *upal.Connection, message string) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestSenderRegistry_RegisterTypeBuildsOnce(t *testing.T) {
+	reg := NewSenderRegistry()
+	calls := 0
+	reg.RegisterType("pager", func(SenderDeps) (Sender, error) {
+		calls++
+		return &recordingSender{connType: "pager"}, nil
+	})
+	reg.RegisterType("broken", func(SenderDeps) (Sender, error) { return nil, errors.New("no api key") })
+
+	first, err := reg.Get("pager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := reg.Get("pager")
+	if first != second || calls != 1 {
+		t.Errorf("factory called %d times; senders differ: %v", calls, first != second)
+	}
+	if _, err := reg.Get("broken"); err == nil {
+		t.Error("expected factory error")
+	}
+	if _, err := reg.Get("missing"); err == nil {
+		t.Error("expected error for unregistered type")
+	}
+	if got := reg.Types(); !reflect.DeepEqual(got, []upal.ConnectionType{"broken", "pager"}) {
+		t.Errorf("Types() = %v", got)
+	}
+}
+
+func TestNewDefaultSenderRegistry(t *testing.T) {
+	RegisterSenderType("test-plugin", func(SenderDeps) (Sender, error) {
+		return &recordingSender{connType: "test-plugin"}, nil
+	})
+	t.Cleanup(func() {
+		senderTypesMu.Lock()
+		delete(senderTypes, "test-plugin")
+		senderTypesMu.Unlock()
+	})
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := NewDefaultSenderRegistry(SenderDeps{Storage: store})
+	want := []upal.ConnectionType{upal.ConnTypeSlack, upal.ConnTypeSMTP, upal.ConnTypeTelegram, "test-plugin", upal.ConnTypeWebhook}
+	if got := reg.Types(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Types() = %v, want %v", got, want)
+	}
+	s, err := reg.Get(upal.ConnTypeSMTP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.(*SMTPSender).Storage != store {
+		t.Error("smtp sender did not receive storage from deps")
+	}
+	if _, err := reg.Get("test-plugin"); err != nil {
+		t.Errorf("plugin type not available: %v", err)
+	}
+}
@@ -34,10 +34,14 @@ func (s *SlackSender) Send(ctx context.Context, conn *upal.Connection, message s
 		client = http.DefaultClient
 	}
 
-	payload := map[string]string{"text": message}
+	// text doubles as the notification fallback when blocks are present.
+	payload := map[string]any{"text": message}
 	if channel != "" {
 		payload["channel"] = channel
 	}
+	if blocks, _ := conn.Extras["blocks"].([]upal.NotificationBlock); len(blocks) > 0 {
+		payload["blocks"] = SlackBlocks(blocks)
+	}
 	body, _ := json.Marshal(payload)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
@@ -57,3 +61,34 @@ func (s *SlackSender) Send(ctx context.Context, conn *upal.Connection, message s
 	}
 	return nil
 }
+
+// SlackBlocks converts notification blocks into Slack Block Kit JSON.
+// Unknown block types are dropped.
+func SlackBlocks(blocks []upal.NotificationBlock) []map[string]any {
+	out := make([]map[string]any, 0, len(blocks))
+	for _, b := range blocks {
+		switch b.Type {
+		case "header":
+			out = append(out, map[string]any{
+				"type": "header",
+				"text": map[string]any{"type": "plain_text", "text": b.Text},
+			})
+		case "section":
+			block := map[string]any{"type": "section"}
+			if b.Text != "" {
+				block["text"] = map[string]any{"type": "mrkdwn", "text": b.Text}
+			}
+			if len(b.Fields) > 0 {
+				fields := make([]map[string]any, len(b.Fields))
+				for i, f := range b.Fields {
+					fields[i] = map[string]any{"type": "mrkdwn", "text": f}
+				}
+				block["fields"] = fields
+			}
+			out = append(out, block)
+		case "divider":
+			out = append(out, map[string]any{"type": "divider"})
+		}
+	}
+	return out
+}
@@ -1,33 +1,116 @@
 package notify
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
 
+	"github.com/soochol/upal/internal/storage"
 	"github.com/soochol/upal/internal/upal"
 )
 
+// maxEmailAttachmentBytes caps the combined size of attachments on one
+// message; most providers reject mail above roughly 25MB once encoded.
+const maxEmailAttachmentBytes = 18 << 20
+
 // SMTPSender sends messages via SMTP email.
-type SMTPSender struct{}
+//
+// Recipients come from the connection extras "to", "cc" and "bcc" (a
+// comma-separated string or a list). Optional extras: "subject", "html" (an
+// HTML body; the message becomes its plain-text alternative) and
+// "attachments" (storage file IDs, e.g. run artifact file_ids, loaded from
+// Storage).
+type SMTPSender struct {
+	// Storage resolves attachment file IDs. Nil rejects messages with
+	// attachments.
+	Storage storage.Storage
+	// SendMail delivers the built message. Nil uses smtp.SendMail.
+	SendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// emailAttachment is a file attached to an outgoing email.
+type emailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// emailMessage is the content of an outgoing email before MIME encoding.
+// Bcc recipients are deliberately absent: they only appear in the envelope.
+type emailMessage struct {
+	From        *mail.Address
+	To          []*mail.Address
+	Cc          []*mail.Address
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []emailAttachment
+}
 
 func (s *SMTPSender) Type() upal.ConnectionType { return upal.ConnTypeSMTP }
 
 func (s *SMTPSender) Send(ctx context.Context, conn *upal.Connection, message string) error {
-	to, _ := conn.Extras["to"].(string)
-	if to == "" {
+	to, err := parseRecipients(conn.Extras["to"])
+	if err != nil {
+		return fmt.Errorf("smtp connection %q: invalid 'to': %w", conn.ID, err)
+	}
+	if len(to) == 0 {
 		return fmt.Errorf("smtp connection %q missing 'to' in extras", conn.ID)
 	}
+	cc, err := parseRecipients(conn.Extras["cc"])
+	if err != nil {
+		return fmt.Errorf("smtp connection %q: invalid 'cc': %w", conn.ID, err)
+	}
+	bcc, err := parseRecipients(conn.Extras["bcc"])
+	if err != nil {
+		return fmt.Errorf("smtp connection %q: invalid 'bcc': %w", conn.ID, err)
+	}
 
-	from := conn.Login
-	if from == "" {
+	if conn.Login == "" {
 		return fmt.Errorf("smtp connection %q missing login (from address)", conn.ID)
 	}
+	from, err := mail.ParseAddress(conn.Login)
+	if err != nil {
+		return fmt.Errorf("smtp connection %q: invalid from address: %w", conn.ID, err)
+	}
 
 	subject, _ := conn.Extras["subject"].(string)
 	if subject == "" {
 		subject = "Upal Notification"
 	}
+	html, _ := conn.Extras["html"].(string)
+
+	fileIDs, err := parseFileIDs(conn.Extras["attachments"])
+	if err != nil {
+		return fmt.Errorf("smtp connection %q: invalid 'attachments': %w", conn.ID, err)
+	}
+	attachments, err := s.loadAttachments(ctx, fileIDs)
+	if err != nil {
+		return fmt.Errorf("smtp connection %q: %w", conn.ID, err)
+	}
+
+	msg, err := buildMIMEMessage(emailMessage{
+		From:        from,
+		To:          to,
+		Cc:          cc,
+		Subject:     subject,
+		Text:        message,
+		HTML:        html,
+		Attachments: attachments,
+	})
+	if err != nil {
+		return fmt.Errorf("smtp build message: %w", err)
+	}
 
 	host := conn.Host
 	port := conn.Port
@@ -36,16 +119,242 @@ func (s *SMTPSender) Send(ctx context.Context, conn *upal.Connection, message st
 	}
 	addr := fmt.Sprintf("%s:%d", host, port)
 
-	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
-		from, to, subject, message)
-
 	var auth smtp.Auth
 	if conn.Password != "" {
-		auth = smtp.PlainAuth("", from, conn.Password, host)
+		auth = smtp.PlainAuth("", from.Address, conn.Password, host)
 	}
 
-	if err := smtp.SendMail(addr, auth, from, []string{to}, []byte(msg)); err != nil {
+	send := s.SendMail
+	if send == nil {
+		send = smtp.SendMail
+	}
+	if err := send(addr, auth, from.Address, envelopeRecipients(to, cc, bcc), msg); err != nil {
 		return fmt.Errorf("smtp send: %w", err)
 	}
 	return nil
 }
+
+// loadAttachments reads the given storage files, enforcing the combined size
+// cap.
+func (s *SMTPSender) loadAttachments(ctx context.Context, fileIDs []string) ([]emailAttachment, error) {
+	if len(fileIDs) == 0 {
+		return nil, nil
+	}
+	if s.Storage == nil {
+		return nil, fmt.Errorf("attachments require file storage")
+	}
+	var total int
+	out := make([]emailAttachment, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		info, rc, err := s.Storage.Get(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %q: %w", id, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, int64(maxEmailAttachmentBytes-total+1)))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("attachment %q: %w", id, err)
+		}
+		total += len(data)
+		if total > maxEmailAttachmentBytes {
+			return nil, fmt.Errorf("attachments exceed %dMB", maxEmailAttachmentBytes>>20)
+		}
+		out = append(out, emailAttachment{Filename: info.Filename, ContentType: info.ContentType, Data: data})
+	}
+	return out, nil
+}
+
+// parseRecipients validates a recipient extra: a comma-separated address
+// list or a list of addresses. Nil and empty values yield no recipients.
+// parseFileIDs reads the attachments extra: a list of storage file IDs,
+// either typed or decoded from JSON.
+func parseFileIDs(v any) ([]string, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return val, nil
+	case []any:
+		ids := make([]string, 0, len(val))
+		for _, item := range val {
+			id, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("file ID %v is not a string", item)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	default:
+		return nil, fmt.Errorf("unsupported attachments value %T", v)
+	}
+}
+
+func parseRecipients(v any) ([]*mail.Address, error) {
+	var raw []string
+	switch val := v.(type) {
+	case nil:
+	case string:
+		if strings.TrimSpace(val) != "" {
+			raw = []string{val}
+		}
+	case []string:
+		raw = val
+	case []any:
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("recipient %v is not a string", item)
+			}
+			raw = append(raw, s)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported recipient value %T", v)
+	}
+
+	var out []*mail.Address
+	for _, r := range raw {
+		list, err := mail.ParseAddressList(r)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", r, err)
+		}
+		out = append(out, list...)
+	}
+	return out, nil
+}
+
+// envelopeRecipients returns the deduplicated RCPT TO addresses.
+func envelopeRecipients(lists ...[]*mail.Address) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range lists {
+		for _, a := range list {
+			key := strings.ToLower(a.Address)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, a.Address)
+		}
+	}
+	return out
+}
+
+// buildMIMEMessage encodes m as an RFC 5322 message. A plain message is a
+// single text/plain part; an HTML body adds a multipart/alternative with the
+// text as fallback; attachments wrap the body in multipart/mixed.
+func buildMIMEMessage(m emailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	writeHeader := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	writeHeader("From", m.From.String())
+	writeHeader("To", joinAddresses(m.To))
+	if len(m.Cc) > 0 {
+		writeHeader("Cc", joinAddresses(m.Cc))
+	}
+	writeHeader("Subject", mime.QEncoding.Encode("UTF-8", m.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("MIME-Version", "1.0")
+
+	bodyHeader, body, err := buildBodyPart(m.Text, m.HTML)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Attachments) == 0 {
+		for _, k := range []string{"Content-Type", "Content-Transfer-Encoding"} {
+			if v := bodyHeader.Get(k); v != "" {
+				writeHeader(k, v)
+			}
+		}
+		buf.WriteString("\r\n")
+		buf.Write(body)
+		return buf.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	writeHeader("Content-Type", "multipart/mixed; boundary="+mixed.Boundary())
+	buf.WriteString("\r\n")
+	part, err := mixed.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	part.Write(body)
+	for _, a := range m.Attachments {
+		ct := a.ContentType
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(ct, map[string]string{"name": a.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64Lines(part, a.Data)
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// buildBodyPart returns the headers and encoded content of the message body:
+// text/plain alone, or multipart/alternative with text and HTML.
+func buildBodyPart(text, html string) (textproto.MIMEHeader, []byte, error) {
+	textHeader := textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}
+	if html == "" {
+		return textHeader, quotedPrintable(text), nil
+	}
+
+	var buf bytes.Buffer
+	alt := multipart.NewWriter(&buf)
+	for _, p := range []struct {
+		header  textproto.MIMEHeader
+		content string
+	}{
+		{textHeader, text},
+		{textproto.MIMEHeader{
+			"Content-Type":              {"text/html; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		}, html},
+	} {
+		part, err := alt.CreatePart(p.header)
+		if err != nil {
+			return nil, nil, err
+		}
+		part.Write(quotedPrintable(p.content))
+	}
+	if err := alt.Close(); err != nil {
+		return nil, nil, err
+	}
+	return textproto.MIMEHeader{"Content-Type": {"multipart/alternative; boundary=" + alt.Boundary()}}, buf.Bytes(), nil
+}
+
+func quotedPrintable(s string) []byte {
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+// writeBase64Lines writes data base64-encoded in 76-character lines.
+func writeBase64Lines(w io.Writer, data []byte) {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		io.WriteString(w, enc[:76]+"\r\n")
+		enc = enc[76:]
+	}
+	io.WriteString(w, enc+"\r\n")
+}
+
+func joinAddresses(list []*mail.Address) string {
+	parts := make([]string, len(list))
+	for i, a := range list {
+		parts[i] = a.String()
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/storage"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestBuildMIMEMessage_HTMLWithAttachment(t *testing.T) {
+	raw, err := buildMIMEMessage(emailMessage{
+		From:        &mail.Address{Address: "bot@example.com"},
+		To:          []*mail.Address{{Name: "Ops", Address: "ops@example.com"}},
+		Cc:          []*mail.Address{{Address: "lead@example.com"}},
+		Subject:     "Weekly report — 주간",
+		Text:        "See attached.",
+		HTML:        "<h1>Weekly</h1>",
+		Attachments: []emailAttachment{{Filename: "report.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4 fake")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	if got := msg.Header.Get("To"); got != `"Ops" <ops@example.com>` {
+		t.Errorf("To = %q", got)
+	}
+	if got := msg.Header.Get("Cc"); got != "<lead@example.com>" {
+		t.Errorf("Cc = %q", got)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != "Weekly report — 주간" {
+		t.Errorf("Subject = %q", subject)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q", mediaType)
+	}
+	mixed := multipart.NewReader(msg.Body, params["boundary"])
+
+	// First part: the alternative body with text and HTML.
+	body, err := mixed.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	altType, altParams, _ := mime.ParseMediaType(body.Header.Get("Content-Type"))
+	if altType != "multipart/alternative" {
+		t.Fatalf("body Content-Type = %q", altType)
+	}
+	alt := multipart.NewReader(body, altParams["boundary"])
+	var types, contents []string
+	for {
+		p, err := alt.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(p) // multipart decodes quoted-printable
+		types = append(types, p.Header.Get("Content-Type"))
+		contents = append(contents, string(data))
+	}
+	if strings.Join(types, "|") != "text/plain; charset=UTF-8|text/html; charset=UTF-8" {
+		t.Errorf("alternative parts = %v", types)
+	}
+	if len(contents) == 2 && (contents[0] != "See attached." || contents[1] != "<h1>Weekly</h1>") {
+		t.Errorf("alternative contents = %q", contents)
+	}
+
+	// Second part: the base64 attachment.
+	att, err := mixed.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if att.FileName() != "report.pdf" || !strings.HasPrefix(att.Header.Get("Content-Type"), "application/pdf") {
+		t.Errorf("attachment headers = %v", att.Header)
+	}
+	if att.Header.Get("Content-Transfer-Encoding") != "base64" {
+		t.Errorf("attachment encoding = %q", att.Header.Get("Content-Transfer-Encoding"))
+	}
+	encoded, _ := io.ReadAll(att)
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil || string(decoded) != "%PDF-1.4 fake" {
+		t.Errorf("attachment data = %q (%v)", decoded, err)
+	}
+	if _, err := mixed.NextPart(); err != io.EOF {
+		t.Errorf("expected exactly two parts, got err %v", err)
+	}
+}
+
+func TestBuildMIMEMessage_PlainText(t *testing.T) {
+	raw, err := buildMIMEMessage(emailMessage{
+		From:    &mail.Address{Address: "bot@example.com"},
+		To:      []*mail.Address{{Address: "ops@example.com"}},
+		Subject: "Done",
+		Text:    "Pipeline finished.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := msg.Header.Get("Content-Type"); ct != "text/plain; charset=UTF-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if body, _ := io.ReadAll(msg.Body); string(body) != "Pipeline finished." {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := store.Save(context.Background(), "data.csv", "text/csv", strings.NewReader("a,b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	sender := &SMTPSender{Storage: store, SendMail: func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		return nil
+	}}
+	conn := &upal.Connection{ID: "mail", Type: upal.ConnTypeSMTP, Host: "smtp.example.com", Login: "bot@example.com", Extras: map[string]any{
+		"to":          "a@example.com, B <b@example.com>",
+		"cc":          []any{"c@example.com"},
+		"bcc":         []string{"hidden@example.com", "A@example.com"},
+		"attachments": []any{info.ID}, // as decoded from the stored JSON
+	}}
+	if err := sender.Send(context.Background(), conn, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if gotAddr != "smtp.example.com:587" || gotFrom != "bot@example.com" {
+		t.Errorf("addr/from = %q %q", gotAddr, gotFrom)
+	}
+	// Bcc recipients are in the envelope (deduplicated) but not the headers.
+	if strings.Join(gotTo, ",") != "a@example.com,b@example.com,c@example.com,hidden@example.com" {
+		t.Errorf("envelope = %v", gotTo)
+	}
+	if bytes.Contains(gotMsg, []byte("hidden@example.com")) {
+		t.Error("bcc recipient leaked into the message headers")
+	}
+	if !bytes.Contains(gotMsg, []byte("filename=data.csv")) {
+		t.Error("attachment missing from message")
+	}
+}
+
+func TestSMTPSender_ValidatesRecipients(t *testing.T) {
+	sender := &SMTPSender{SendMail: func(string, smtp.Auth, string, []string, []byte) error {
+		t.Fatal("message sent despite invalid configuration")
+		return nil
+	}}
+	for name, extras := range map[string]map[string]any{
+		"missing to":     {},
+		"invalid to":     {"to": "not an address"},
+		"invalid cc":     {"to": "a@example.com", "cc": []any{"ok@example.com", "broken@"}},
+		"invalid bcc":    {"to": "a@example.com", "bcc": 42},
+		"no storage":     {"to": "a@example.com", "attachments": []string{"file-1"}},
+		"bad attachment": {"to": "a@example.com", "attachments": []any{42}},
+	} {
+		conn := &upal.Connection{ID: "mail", Login: "bot@example.com", Extras: extras}
+		if err := sender.Send(context.Background(), conn, "hi"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package notify
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -128,6 +129,51 @@ func TestSlackSender_Send(t *testing.T) {
 	}
 }
 
+func TestSlackSender_SendBlocks(t *testing.T) {
+	var payload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	s := &SlackSender{Client: srv.Client()}
+	conn := &upal.Connection{
+		ID:   "conn-1",
+		Type: upal.ConnTypeSlack,
+		Extras: map[string]any{
+			"webhook_url": srv.URL,
+			"blocks": []upal.NotificationBlock{
+				{Type: "header", Text: "Daily report"},
+				{Type: "section", Text: "*3* runs completed", Fields: []string{"*Failed*\n0", "*Duration*\n4m"}},
+				{Type: "divider"},
+			},
+		},
+	}
+	if err := s.Send(context.Background(), conn, "Daily report: 3 runs completed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if payload["text"] != "Daily report: 3 runs completed" {
+		t.Errorf("fallback text = %v", payload["text"])
+	}
+	blocks, ok := payload["blocks"].([]any)
+	if !ok || len(blocks) != 3 {
+		t.Fatalf("blocks = %v, want 3 blocks", payload["blocks"])
+	}
+	header := blocks[0].(map[string]any)
+	if header["type"] != "header" || header["text"].(map[string]any)["text"] != "Daily report" {
+		t.Errorf("header block = %v", header)
+	}
+	section := blocks[1].(map[string]any)
+	if fields, _ := section["fields"].([]any); len(fields) != 2 {
+		t.Errorf("section fields = %v, want 2", section["fields"])
+	}
+	if blocks[2].(map[string]any)["type"] != "divider" {
+		t.Errorf("third block = %v, want divider", blocks[2])
+	}
+}
+
 func TestSMTPSender_Type(t *testing.T) {
 	s := &SMTPSender{}
 	if s.Type() != upal.ConnTypeSMTP {
@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// WebhookSender POSTs a JSON body to an arbitrary HTTP endpoint. The message
+// is sent verbatim when it is a JSON document (a rendered body template);
+// plain text is wrapped as {"text": message}.
+type WebhookSender struct {
+	Client *http.Client
+}
+
+func (s *WebhookSender) Type() upal.ConnectionType { return upal.ConnTypeWebhook }
+
+func (s *WebhookSender) Send(ctx context.Context, conn *upal.Connection, message string) error {
+	url, _ := conn.Extras["url"].(string)
+	if url == "" {
+		url = conn.Host
+	}
+	if url == "" {
+		return fmt.Errorf("webhook connection %q missing url", conn.ID)
+	}
+
+	headers, err := WebhookHeaders(conn)
+	if err != nil {
+		return err
+	}
+
+	body := []byte(message)
+	if !json.Valid(body) {
+		body, _ = json.Marshal(map[string]any{"text": message})
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if conn.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+conn.Token)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WebhookHeaders returns the headers stored in conn.Extras["headers"], which
+// is either an object or a JSON-encoded object of string values.
+func WebhookHeaders(conn *upal.Connection) (map[string]string, error) {
+	headers := make(map[string]string)
+	switch h := conn.Extras["headers"].(type) {
+	case nil:
+	case map[string]any:
+		for k, v := range h {
+			headers[k] = fmt.Sprintf("%v", v)
+		}
+	case map[string]string:
+		for k, v := range h {
+			headers[k] = v
+		}
+	case string:
+		if h == "" {
+			break
+		}
+		if err := json.Unmarshal([]byte(h), &headers); err != nil {
+			return nil, fmt.Errorf("webhook connection %q: invalid headers: %w", conn.ID, err)
+		}
+	default:
+		return nil, fmt.Errorf("webhook connection %q: headers must be an object", conn.ID)
+	}
+	return headers, nil
+}
@@ -64,31 +64,53 @@ func (f *PassthroughFormatter) Format(_ agent.InvocationContext, content string)
 	return content, nil
 }
 
+// Layout holds server-wide settings for HTML layout generation.
+type Layout struct {
+	BasePrompt   string // platform-level constraints prepended to the node's system_prompt
+	DefaultModel string // used when the node sets neither layout_model nor model
+	Disabled     bool   // skip the LLM call and return plain content
+}
+
 // NewFormatter resolves the appropriate Formatter from node config and available LLMs.
 // For "md" output_format, returns a PassthroughFormatter (no LLM call).
 // For "html" (or unset), returns an HTMLFormatter if system_prompt is configured,
 // otherwise falls back to PassthroughFormatter for backward compatibility.
-// basePrompt contains platform-level constraints prepended to the user-authored system_prompt.
-func NewFormatter(config map[string]any, resolver ports.LLMResolver, basePrompt string) Formatter {
-	format, _ := config["output_format"].(string)
-
-	switch format {
-	case "md":
+// The layout model is chosen by LayoutModelID. A disabled layout always
+// yields a PassthroughFormatter.
+func NewFormatter(config map[string]any, resolver ports.LLMResolver, layout Layout) Formatter {
+	modelID, ok := LayoutModelID(config, layout)
+	if !ok || resolver == nil {
 		return &PassthroughFormatter{}
-	default: // "html" or legacy (no format set)
-		systemPrompt, _ := config["system_prompt"].(string)
-		if systemPrompt == "" || resolver == nil {
-			return &PassthroughFormatter{}
-		}
-		modelID, _ := config["model"].(string)
-		llm, modelName, err := resolver.Resolve(modelID)
-		if err != nil || llm == nil {
-			return &PassthroughFormatter{}
-		}
-		return &HTMLFormatter{
-			LLM:          llm,
-			ModelName:    modelName,
-			SystemPrompt: basePrompt + "\n\n" + systemPrompt,
-		}
 	}
+	llm, modelName, err := resolver.Resolve(modelID)
+	if err != nil || llm == nil {
+		return &PassthroughFormatter{}
+	}
+	systemPrompt, _ := config["system_prompt"].(string)
+	return &HTMLFormatter{
+		LLM:          llm,
+		ModelName:    modelName,
+		SystemPrompt: layout.BasePrompt + "\n\n" + systemPrompt,
+	}
+}
+
+// LayoutModelID reports the model an output node lays out its content with:
+// the node's layout_model, then its model, then layout.DefaultModel. An empty
+// ID means the resolver's default. ok is false when the node makes no LLM call:
+// its output_format is "md", it has no system_prompt, or layout is disabled.
+func LayoutModelID(config map[string]any, layout Layout) (modelID string, ok bool) {
+	if format, _ := config["output_format"].(string); format == "md" {
+		return "", false
+	}
+	if systemPrompt, _ := config["system_prompt"].(string); layout.Disabled || systemPrompt == "" {
+		return "", false
+	}
+	modelID, _ = config["layout_model"].(string)
+	if modelID == "" {
+		modelID, _ = config["model"].(string)
+	}
+	if modelID == "" {
+		modelID = layout.DefaultModel
+	}
+	return modelID, true
 }
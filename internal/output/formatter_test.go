@@ -0,0 +1,69 @@
+package output
+
+import (
+	"errors"
+	"testing"
+
+	adkmodel "google.golang.org/adk/model"
+)
+
+// recordingResolver returns llm for every model ID it knows and remembers
+// the last requested ID.
+type recordingResolver struct {
+	known map[string]bool
+	last  string
+}
+
+func (r *recordingResolver) Resolve(modelID string) (adkmodel.LLM, string, error) {
+	r.last = modelID
+	if !r.known[modelID] {
+		return nil, "", errors.New("unknown model")
+	}
+	return stubLLM{}, modelID, nil
+}
+
+type stubLLM struct{ adkmodel.LLM }
+
+func TestNewFormatter_UsesDefaultLayoutModel(t *testing.T) {
+	resolver := &recordingResolver{known: map[string]bool{"anthropic/layout": true}}
+	cfg := map[string]any{"system_prompt": "clean magazine style"}
+
+	f := NewFormatter(cfg, resolver, Layout{BasePrompt: "base", DefaultModel: "anthropic/layout"})
+	html, ok := f.(*HTMLFormatter)
+	if !ok {
+		t.Fatalf("formatter = %T, want *HTMLFormatter", f)
+	}
+	if html.ModelName != "anthropic/layout" || resolver.last != "anthropic/layout" {
+		t.Errorf("resolved %q, want the configured layout model", resolver.last)
+	}
+	if html.SystemPrompt != "base\n\nclean magazine style" {
+		t.Errorf("system prompt = %q", html.SystemPrompt)
+	}
+}
+
+func TestNewFormatter_NodeLayoutModelWins(t *testing.T) {
+	resolver := &recordingResolver{known: map[string]bool{"openai/node": true, "anthropic/layout": true}}
+	cfg := map[string]any{"system_prompt": "x", "layout_model": "openai/node"}
+
+	NewFormatter(cfg, resolver, Layout{DefaultModel: "anthropic/layout"})
+	if resolver.last != "openai/node" {
+		t.Errorf("resolved %q, want the node's layout_model", resolver.last)
+	}
+}
+
+func TestNewFormatter_DisabledReturnsPlainText(t *testing.T) {
+	resolver := &recordingResolver{known: map[string]bool{"anthropic/layout": true}}
+	cfg := map[string]any{"system_prompt": "x"}
+
+	f := NewFormatter(cfg, resolver, Layout{DefaultModel: "anthropic/layout", Disabled: true})
+	if _, ok := f.(*PassthroughFormatter); !ok {
+		t.Fatalf("formatter = %T, want *PassthroughFormatter", f)
+	}
+	if resolver.last != "" {
+		t.Errorf("resolver called with %q while layout is disabled", resolver.last)
+	}
+	out, err := f.Format(nil, "plain content")
+	if err != nil || out != "plain content" {
+		t.Errorf("Format = %q, %v; want content unchanged", out, err)
+	}
+}
@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	memstore "github.com/soochol/upal/internal/repository/memory"
 	"github.com/soochol/upal/internal/upal"
@@ -34,8 +36,20 @@ func (r *MemoryAIProviderRepository) Get(ctx context.Context, id string) (*upal.
 	return p, err
 }
 
+// List returns providers ordered by category, then name, matching the
+// persistent repository.
 func (r *MemoryAIProviderRepository) List(ctx context.Context) ([]*upal.AIProvider, error) {
-	return r.store.All(ctx)
+	providers, err := r.store.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(providers, func(a, b *upal.AIProvider) int {
+		if c := cmp.Compare(a.Category, b.Category); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return providers, nil
 }
 
 func (r *MemoryAIProviderRepository) Update(ctx context.Context, p *upal.AIProvider) error {
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// AuditRepository stores the append-only audit log.
+type AuditRepository interface {
+	Create(ctx context.Context, entry *upal.AuditEntry) error
+	// List returns matching entries, newest first.
+	List(ctx context.Context, filter upal.AuditFilter) ([]*upal.AuditEntry, error)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	memstore "github.com/soochol/upal/internal/repository/memory"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type MemoryAuditRepository struct {
+	store *memstore.Store[*upal.AuditEntry]
+}
+
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{
+		store: memstore.New(func(e *upal.AuditEntry) string { return e.ID }),
+	}
+}
+
+func (r *MemoryAuditRepository) Create(ctx context.Context, entry *upal.AuditEntry) error {
+	return r.store.Set(ctx, entry)
+}
+
+func (r *MemoryAuditRepository) List(ctx context.Context, filter upal.AuditFilter) ([]*upal.AuditEntry, error) {
+	actor := upal.UserIDFromContext(ctx)
+	entries, err := r.store.Filter(ctx, func(e *upal.AuditEntry) bool {
+		return e.Actor == actor &&
+			(filter.ResourceType == "" || e.ResourceType == filter.ResourceType) &&
+			(filter.ResourceID == "" || e.ResourceID == filter.ResourceID) &&
+			(filter.Action == "" || e.Action == filter.Action)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
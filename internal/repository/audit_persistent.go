@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/soochol/upal/internal/db"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type PersistentAuditRepository struct {
+	mem *MemoryAuditRepository
+	db  *db.DB
+}
+
+func NewPersistentAuditRepository(mem *MemoryAuditRepository, database *db.DB) *PersistentAuditRepository {
+	return &PersistentAuditRepository{mem: mem, db: database}
+}
+
+func (r *PersistentAuditRepository) Create(ctx context.Context, entry *upal.AuditEntry) error {
+	_ = r.mem.Create(ctx, entry)
+	if err := r.db.CreateAuditEntry(ctx, entry); err != nil {
+		slog.Warn("db create audit entry failed, in-memory only", "err", err)
+	}
+	return nil
+}
+
+func (r *PersistentAuditRepository) List(ctx context.Context, filter upal.AuditFilter) ([]*upal.AuditEntry, error) {
+	entries, err := r.db.ListAuditEntries(ctx, upal.UserIDFromContext(ctx), filter)
+	if err == nil {
+		return entries, nil
+	}
+	slog.Warn("db list audit entries failed, falling back to in-memory", "err", err)
+	return r.mem.List(ctx, filter)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "context"
+
+// ContentStoreRepository persists the key-value pairs behind the
+// content_store tool, scoped to the user carried by the context.
+type ContentStoreRepository interface {
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	Set(ctx context.Context, key, value string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// ContentStoreDB defines the database methods used by the persistent content store.
+type ContentStoreDB interface {
+	GetContentStoreValue(ctx context.Context, userID, key string) (value string, found bool, err error)
+	SetContentStoreValue(ctx context.Context, userID, key, value string) error
+	ListContentStoreKeys(ctx context.Context, userID, prefix string) ([]string, error)
+	DeleteContentStoreValue(ctx context.Context, userID, key string) error
+}
+
+// PersistentContentStore stores pairs in the content_store table so every
+// replica shares the same state. It keeps no in-memory copy: a value written
+// by another replica must be visible on the next read.
+type PersistentContentStore struct {
+	db ContentStoreDB
+}
+
+func NewPersistentContentStore(db ContentStoreDB) *PersistentContentStore {
+	return &PersistentContentStore{db: db}
+}
+
+func (r *PersistentContentStore) Get(ctx context.Context, key string) (string, bool, error) {
+	return r.db.GetContentStoreValue(ctx, upal.UserIDFromContext(ctx), key)
+}
+
+func (r *PersistentContentStore) Set(ctx context.Context, key, value string) error {
+	return r.db.SetContentStoreValue(ctx, upal.UserIDFromContext(ctx), key, value)
+}
+
+func (r *PersistentContentStore) List(ctx context.Context, prefix string) ([]string, error) {
+	return r.db.ListContentStoreKeys(ctx, upal.UserIDFromContext(ctx), prefix)
+}
+
+func (r *PersistentContentStore) Delete(ctx context.Context, key string) error {
+	return r.db.DeleteContentStoreValue(ctx, upal.UserIDFromContext(ctx), key)
+}
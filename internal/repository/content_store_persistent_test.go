@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// stubContentStoreDB is a fake DB keeping pairs per user.
+type stubContentStoreDB struct {
+	values map[string]map[string]string
+	err    error
+}
+
+func (s *stubContentStoreDB) GetContentStoreValue(_ context.Context, userID, key string) (string, bool, error) {
+	if s.err != nil {
+		return "", false, s.err
+	}
+	v, ok := s.values[userID][key]
+	return v, ok, nil
+}
+func (s *stubContentStoreDB) SetContentStoreValue(_ context.Context, userID, key, value string) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.values == nil {
+		s.values = make(map[string]map[string]string)
+	}
+	if s.values[userID] == nil {
+		s.values[userID] = make(map[string]string)
+	}
+	s.values[userID][key] = value
+	return nil
+}
+func (s *stubContentStoreDB) ListContentStoreKeys(_ context.Context, userID, prefix string) ([]string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var keys []string
+	for k := range s.values[userID] {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+func (s *stubContentStoreDB) DeleteContentStoreValue(_ context.Context, userID, key string) error {
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.values[userID], key)
+	return nil
+}
+
+func TestPersistentContentStore_ScopesPairsToUser(t *testing.T) {
+	store := repository.NewPersistentContentStore(&stubContentStoreDB{})
+	alice := upal.WithUserID(context.Background(), "alice")
+	bob := upal.WithUserID(context.Background(), "bob")
+
+	for _, kv := range [][2]string{
+		{"seen:https://a.com", "1"},
+		{"seen:https://b.com", "2"},
+		{"other:key", "3"},
+	} {
+		if err := store.Set(alice, kv[0], kv[1]); err != nil {
+			t.Fatalf("set %s: %v", kv[0], err)
+		}
+	}
+	if err := store.Set(alice, "other:key", "4"); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+
+	if v, found, err := store.Get(alice, "other:key"); err != nil || !found || v != "4" {
+		t.Errorf("get: got %q/%v/%v, want 4", v, found, err)
+	}
+	if _, found, _ := store.Get(bob, "other:key"); found {
+		t.Error("another user's key was visible")
+	}
+
+	keys, err := store.List(alice, "seen:")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "seen:https://a.com" || keys[1] != "seen:https://b.com" {
+		t.Errorf("expected sorted seen: keys, got %v", keys)
+	}
+
+	if err := store.Delete(alice, "other:key"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, found, _ := store.Get(alice, "other:key"); found {
+		t.Error("key still present after delete")
+	}
+}
+
+func TestPersistentContentStore_ReturnsDBErrors(t *testing.T) {
+	errDown := errors.New("db down")
+	store := repository.NewPersistentContentStore(&stubContentStoreDB{err: errDown})
+	ctx := context.Background()
+
+	if _, _, err := store.Get(ctx, "k"); !errors.Is(err, errDown) {
+		t.Errorf("get: got %v, want the db error", err)
+	}
+	if err := store.Set(ctx, "k", "v"); !errors.Is(err, errDown) {
+		t.Errorf("set: got %v, want the db error", err)
+	}
+}
@@ -0,0 +1,78 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+
+	"github.com/soochol/upal/internal/db"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// newPostgresContentStore connects to UPAL_TEST_DATABASE_URL, runs the
+// schema migrations, and returns a store scoped to a throwaway user.
+func newPostgresContentStore(t *testing.T) (*repository.PersistentContentStore, context.Context) {
+	t.Helper()
+	url := os.Getenv("UPAL_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("UPAL_TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	database, err := db.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := database.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	userID := upal.GenerateID("test-user")
+	t.Cleanup(func() {
+		database.Pool.ExecContext(context.Background(), `DELETE FROM content_store WHERE user_id = $1`, userID)
+	})
+	return repository.NewPersistentContentStore(database), upal.WithUserID(ctx, userID)
+}
+
+func TestPostgresContentStore_SetGetList(t *testing.T) {
+	store, ctx := newPostgresContentStore(t)
+
+	for _, kv := range [][2]string{
+		{"seen:https://a.com", "1"},
+		{"seen:https://b.com", "2"},
+		{"other:key", "3"},
+	} {
+		if err := store.Set(ctx, kv[0], kv[1]); err != nil {
+			t.Fatalf("set %s: %v", kv[0], err)
+		}
+	}
+	// Overwrite must upsert rather than fail on the primary key.
+	if err := store.Set(ctx, "other:key", "4"); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+
+	if v, found, err := store.Get(ctx, "other:key"); err != nil || !found || v != "4" {
+		t.Errorf("get other:key = %q, %v, %v; want 4", v, found, err)
+	}
+	if _, found, err := store.Get(ctx, "missing"); err != nil || found {
+		t.Errorf("get missing: found=%v err=%v", found, err)
+	}
+
+	keys, err := store.List(ctx, "seen:")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if want := []string{"seen:https://a.com", "seen:https://b.com"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("list seen: = %v, want %v", keys, want)
+	}
+
+	// Another user sees none of the pairs.
+	other := upal.WithUserID(context.Background(), upal.GenerateID("test-user"))
+	if keys, err := store.List(other, ""); err != nil || len(keys) != 0 {
+		t.Errorf("other user's list = %v, %v", keys, err)
+	}
+}
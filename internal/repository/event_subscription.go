@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+type EventSubscriptionRepository interface {
+	Create(ctx context.Context, sub *upal.EventSubscription) error
+	Get(ctx context.Context, id string) (*upal.EventSubscription, error)
+	List(ctx context.Context) ([]*upal.EventSubscription, error)
+	Update(ctx context.Context, sub *upal.EventSubscription) error
+	Delete(ctx context.Context, id string) error
+}
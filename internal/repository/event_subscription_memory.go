@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	memstore "github.com/soochol/upal/internal/repository/memory"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type MemoryEventSubscriptionRepository struct {
+	store *memstore.Store[*upal.EventSubscription]
+}
+
+func NewMemoryEventSubscriptionRepository() *MemoryEventSubscriptionRepository {
+	return &MemoryEventSubscriptionRepository{
+		store: memstore.New(func(s *upal.EventSubscription) string { return s.ID }),
+	}
+}
+
+func (r *MemoryEventSubscriptionRepository) Create(ctx context.Context, sub *upal.EventSubscription) error {
+	if r.store.Has(ctx, sub.ID) {
+		return fmt.Errorf("event subscription %q already exists", sub.ID)
+	}
+	return r.store.Set(ctx, sub)
+}
+
+func (r *MemoryEventSubscriptionRepository) Get(ctx context.Context, id string) (*upal.EventSubscription, error) {
+	sub, err := r.store.Get(ctx, id)
+	if errors.Is(err, memstore.ErrNotFound) {
+		return nil, fmt.Errorf("event subscription %q: %w", id, ErrNotFound)
+	}
+	return sub, err
+}
+
+func (r *MemoryEventSubscriptionRepository) List(ctx context.Context) ([]*upal.EventSubscription, error) {
+	return r.store.All(ctx)
+}
+
+func (r *MemoryEventSubscriptionRepository) Update(ctx context.Context, sub *upal.EventSubscription) error {
+	if !r.store.Has(ctx, sub.ID) {
+		return fmt.Errorf("event subscription %q: %w", sub.ID, ErrNotFound)
+	}
+	return r.store.Set(ctx, sub)
+}
+
+func (r *MemoryEventSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	err := r.store.Delete(ctx, id)
+	if errors.Is(err, memstore.ErrNotFound) {
+		return fmt.Errorf("event subscription %q: %w", id, ErrNotFound)
+	}
+	return err
+}
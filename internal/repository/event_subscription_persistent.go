@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/soochol/upal/internal/db"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type PersistentEventSubscriptionRepository struct {
+	mem *MemoryEventSubscriptionRepository
+	db  *db.DB
+}
+
+func NewPersistentEventSubscriptionRepository(mem *MemoryEventSubscriptionRepository, database *db.DB) *PersistentEventSubscriptionRepository {
+	return &PersistentEventSubscriptionRepository{mem: mem, db: database}
+}
+
+func (r *PersistentEventSubscriptionRepository) Create(ctx context.Context, sub *upal.EventSubscription) error {
+	if err := r.mem.Create(ctx, sub); err != nil {
+		return err
+	}
+	userID := upal.UserIDFromContext(ctx)
+	if err := r.db.CreateEventSubscription(ctx, userID, sub); err != nil {
+		slog.Warn("db create event subscription failed, in-memory only", "err", err)
+	}
+	return nil
+}
+
+func (r *PersistentEventSubscriptionRepository) Get(ctx context.Context, id string) (*upal.EventSubscription, error) {
+	sub, err := r.mem.Get(ctx, id)
+	if err == nil {
+		return sub, nil
+	}
+
+	userID := upal.UserIDFromContext(ctx)
+	dbSub, dbErr := r.db.GetEventSubscription(ctx, userID, id)
+	if dbErr != nil {
+		return nil, err // return original ErrNotFound
+	}
+
+	_ = r.mem.Create(ctx, dbSub)
+	return dbSub, nil
+}
+
+func (r *PersistentEventSubscriptionRepository) List(ctx context.Context) ([]*upal.EventSubscription, error) {
+	userID := upal.UserIDFromContext(ctx)
+	subs, err := r.db.ListEventSubscriptions(ctx, userID)
+	if err == nil {
+		return subs, nil
+	}
+	slog.Warn("db list event subscriptions failed, falling back to in-memory", "err", err)
+	return r.mem.List(ctx)
+}
+
+func (r *PersistentEventSubscriptionRepository) Update(ctx context.Context, sub *upal.EventSubscription) error {
+	if _, err := r.Get(ctx, sub.ID); err != nil {
+		return err
+	}
+	_ = r.mem.Update(ctx, sub)
+	userID := upal.UserIDFromContext(ctx)
+	if err := r.db.UpdateEventSubscription(ctx, userID, sub); err != nil {
+		slog.Warn("db update event subscription failed, in-memory only", "err", err)
+	}
+	return nil
+}
+
+func (r *PersistentEventSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	_ = r.mem.Delete(ctx, id)
+	userID := upal.UserIDFromContext(ctx)
+	if err := r.db.DeleteEventSubscription(ctx, userID, id); err != nil {
+		slog.Warn("db delete event subscription failed", "err", err)
+	}
+	return nil
+}
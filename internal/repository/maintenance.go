@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// MaintenanceRepository stores the server-wide maintenance flag.
+type MaintenanceRepository interface {
+	Get(ctx context.Context) (*upal.MaintenanceState, error)
+	Set(ctx context.Context, st *upal.MaintenanceState) error
+}
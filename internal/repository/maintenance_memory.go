@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+type MemoryMaintenanceRepository struct {
+	mu    sync.RWMutex
+	state upal.MaintenanceState
+}
+
+func NewMemoryMaintenanceRepository() *MemoryMaintenanceRepository {
+	return &MemoryMaintenanceRepository{}
+}
+
+func (r *MemoryMaintenanceRepository) Get(_ context.Context) (*upal.MaintenanceState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	st := r.state
+	return &st, nil
+}
+
+func (r *MemoryMaintenanceRepository) Set(_ context.Context, st *upal.MaintenanceState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state = *st
+	return nil
+}
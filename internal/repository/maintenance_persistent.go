@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/soochol/upal/internal/db"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type PersistentMaintenanceRepository struct {
+	mem *MemoryMaintenanceRepository
+	db  *db.DB
+}
+
+func NewPersistentMaintenanceRepository(mem *MemoryMaintenanceRepository, database *db.DB) *PersistentMaintenanceRepository {
+	return &PersistentMaintenanceRepository{mem: mem, db: database}
+}
+
+func (r *PersistentMaintenanceRepository) Get(ctx context.Context) (*upal.MaintenanceState, error) {
+	st, err := r.db.GetMaintenanceState(ctx)
+	if err == nil {
+		_ = r.mem.Set(ctx, st)
+		return st, nil
+	}
+	slog.Warn("db get maintenance state failed, falling back to in-memory", "err", err)
+	return r.mem.Get(ctx)
+}
+
+func (r *PersistentMaintenanceRepository) Set(ctx context.Context, st *upal.MaintenanceState) error {
+	_ = r.mem.Set(ctx, st)
+	if err := r.db.SetMaintenanceState(ctx, st); err != nil {
+		slog.Warn("db set maintenance state failed, in-memory only", "err", err)
+	}
+	return nil
+}
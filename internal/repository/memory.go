@@ -20,6 +20,7 @@ func NewMemory() *MemoryRepository {
 }
 
 func (r *MemoryRepository) Create(ctx context.Context, wf *upal.WorkflowDefinition) error {
+	stampWorkflow(wf, nil)
 	return r.store.Set(ctx, wf)
 }
 
@@ -36,6 +37,8 @@ func (r *MemoryRepository) List(ctx context.Context) ([]*upal.WorkflowDefinition
 }
 
 func (r *MemoryRepository) Update(ctx context.Context, name string, wf *upal.WorkflowDefinition) error {
+	prev, _ := r.store.Get(ctx, name)
+	stampWorkflow(wf, prev)
 	if name != wf.Name {
 		_ = r.store.Delete(ctx, name)
 	}
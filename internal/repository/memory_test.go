@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestMemoryRepository_OwnershipMetadata(t *testing.T) {
+	repo := NewMemory()
+	alice := upal.WithUserID(context.Background(), "alice")
+	bob := upal.WithUserID(context.Background(), "bob")
+
+	// created_* follow the editor stamped by the API handler, whatever the
+	// client sent.
+	wf := &upal.WorkflowDefinition{Name: "digest", CreatedBy: "mallory", CreatedAt: time.Unix(0, 0), UpdatedBy: "alice", UpdatedAt: time.Now()}
+	if err := repo.Create(alice, wf); err != nil {
+		t.Fatal(err)
+	}
+	created, _ := repo.Get(alice, "digest")
+	if created.CreatedBy != "alice" || created.UpdatedBy != "alice" || created.CreatedAt.IsZero() || !created.UpdatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("after create: %+v", created)
+	}
+	createdAt := created.CreatedAt
+
+	time.Sleep(time.Millisecond)
+	edit := &upal.WorkflowDefinition{Name: "digest-v2", Description: "renamed", UpdatedBy: "bob", UpdatedAt: time.Now()}
+	if err := repo.Update(bob, "digest", edit); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.Get(bob, "digest-v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CreatedBy != "alice" || !got.CreatedAt.Equal(createdAt) {
+		t.Errorf("created_* changed on update: %s at %v", got.CreatedBy, got.CreatedAt)
+	}
+	if got.UpdatedBy != "bob" || !got.UpdatedAt.After(createdAt) {
+		t.Errorf("updated_* not refreshed: %s at %v", got.UpdatedBy, got.UpdatedAt)
+	}
+	editedAt := got.UpdatedAt
+
+	// A system write, such as a description backfill, is not an edit.
+	backfilled := &upal.WorkflowDefinition{Name: "digest-v2", Description: "filled in"}
+	if err := repo.Update(context.Background(), "digest-v2", backfilled); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.Get(context.Background(), "digest-v2")
+	if got.UpdatedBy != "bob" || !got.UpdatedAt.Equal(editedAt) {
+		t.Errorf("system write changed updated_*: %s at %v", got.UpdatedBy, got.UpdatedAt)
+	}
+}
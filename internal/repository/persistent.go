@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -35,12 +37,16 @@ func (r *PersistentRepository) Get(ctx context.Context, name string) (*upal.Work
 
 	userID := upal.UserIDFromContext(ctx)
 	row, dbErr := r.db.GetWorkflow(ctx, userID, name)
-	if dbErr != nil {
+	if errors.Is(dbErr, sql.ErrNoRows) {
 		return nil, err
 	}
+	if dbErr != nil {
+		return nil, dbErr
+	}
 
-	_ = r.mem.Create(ctx, &row.Definition)
-	return &row.Definition, nil
+	wf = rowDefinition(row)
+	_ = r.mem.store.Set(ctx, wf)
+	return wf, nil
 }
 
 func (r *PersistentRepository) List(ctx context.Context) ([]*upal.WorkflowDefinition, error) {
@@ -49,7 +55,7 @@ func (r *PersistentRepository) List(ctx context.Context) ([]*upal.WorkflowDefini
 	if err == nil {
 		result := make([]*upal.WorkflowDefinition, len(rows))
 		for i := range rows {
-			result[i] = &rows[i].Definition
+			result[i] = rowDefinition(&rows[i])
 		}
 		return result, nil
 	}
@@ -58,6 +64,8 @@ func (r *PersistentRepository) List(ctx context.Context) ([]*upal.WorkflowDefini
 }
 
 func (r *PersistentRepository) Update(ctx context.Context, name string, wf *upal.WorkflowDefinition) error {
+	// Load the stored definition so the memory update keeps its created_*.
+	_, _ = r.Get(ctx, name)
 	_ = r.mem.Update(ctx, name, wf)
 	userID := upal.UserIDFromContext(ctx)
 	// CreateWorkflow uses upsert (INSERT ON CONFLICT DO UPDATE).
@@ -75,3 +83,13 @@ func (r *PersistentRepository) Delete(ctx context.Context, name string) error {
 	}
 	return nil
 }
+
+// rowDefinition returns a row's definition, taking the timestamps from the
+// row for definitions saved before they carried their own.
+func rowDefinition(row *db.WorkflowRow) *upal.WorkflowDefinition {
+	wf := &row.Definition
+	if wf.CreatedAt.IsZero() {
+		wf.CreatedAt, wf.UpdatedAt = row.CreatedAt, row.UpdatedAt
+	}
+	return wf
+}
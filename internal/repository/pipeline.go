@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/soochol/upal/internal/upal"
 )
@@ -21,3 +22,20 @@ type PipelineRunRepository interface {
 	ListByPipeline(ctx context.Context, pipelineID string) ([]*upal.PipelineRun, error)
 	Update(ctx context.Context, run *upal.PipelineRun) error
 }
+
+// stampPipeline fills in p's ownership metadata for a save, like
+// stampWorkflow: prev's created_* are kept, its updated_* too unless the API
+// handler stamped a user edit, and a new pipeline is created by its editor.
+func stampPipeline(p, prev *upal.Pipeline) {
+	if prev != nil && !prev.CreatedAt.IsZero() {
+		p.CreatedBy, p.CreatedAt = prev.CreatedBy, prev.CreatedAt
+		if p.UpdatedAt.IsZero() {
+			p.UpdatedBy, p.UpdatedAt = prev.UpdatedBy, prev.UpdatedAt
+		}
+		return
+	}
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = time.Now()
+	}
+	p.CreatedBy, p.CreatedAt = p.UpdatedBy, p.UpdatedAt
+}
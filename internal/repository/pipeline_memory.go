@@ -23,6 +23,7 @@ func (r *MemoryPipelineRepository) Create(ctx context.Context, p *upal.Pipeline)
 	if r.store.Has(ctx, p.ID) {
 		return fmt.Errorf("pipeline %q already exists", p.ID)
 	}
+	stampPipeline(p, nil)
 	return r.store.Set(ctx, p)
 }
 
@@ -39,9 +40,11 @@ func (r *MemoryPipelineRepository) List(ctx context.Context) ([]*upal.Pipeline,
 }
 
 func (r *MemoryPipelineRepository) Update(ctx context.Context, p *upal.Pipeline) error {
-	if !r.store.Has(ctx, p.ID) {
+	prev, err := r.store.Get(ctx, p.ID)
+	if err != nil {
 		return fmt.Errorf("pipeline %q: %w", p.ID, ErrNotFound)
 	}
+	stampPipeline(p, prev)
 	return r.store.Set(ctx, p)
 }
 
@@ -113,3 +113,41 @@ func TestMemoryPipelineRunRepo_CRUD(t *testing.T) {
 		t.Errorf("expected updated status, got %q", got.Status)
 	}
 }
+
+func TestMemoryPipelineRepo_OwnershipMetadata(t *testing.T) {
+	repo := NewMemoryPipelineRepository()
+	alice := upal.WithUserID(context.Background(), "alice")
+	bob := upal.WithUserID(context.Background(), "bob")
+
+	if err := repo.Create(alice, &upal.Pipeline{ID: "pipe-own", Name: "Own", UpdatedBy: "alice", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	created, _ := repo.Get(alice, "pipe-own")
+	createdAt := created.CreatedAt
+	if created.CreatedBy != "alice" || created.UpdatedBy != "alice" || createdAt.IsZero() {
+		t.Fatalf("after create: %+v", created)
+	}
+
+	time.Sleep(time.Millisecond)
+	// A fresh value, as decoded from a PUT body, with forged created_*.
+	if err := repo.Update(bob, &upal.Pipeline{ID: "pipe-own", Name: "Renamed", CreatedBy: "mallory", UpdatedBy: "bob", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := repo.Get(bob, "pipe-own")
+	if got.CreatedBy != "alice" || !got.CreatedAt.Equal(createdAt) {
+		t.Errorf("created_* changed on update: %s at %v", got.CreatedBy, got.CreatedAt)
+	}
+	if got.UpdatedBy != "bob" || !got.UpdatedAt.After(createdAt) {
+		t.Errorf("updated_* not refreshed: %s at %v", got.UpdatedBy, got.UpdatedAt)
+	}
+	editedAt := got.UpdatedAt
+
+	// A system write, such as scheduler housekeeping, is not an edit.
+	if err := repo.Update(context.Background(), &upal.Pipeline{ID: "pipe-own", Name: "Renamed"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.Get(context.Background(), "pipe-own")
+	if got.UpdatedBy != "bob" || !got.UpdatedAt.Equal(editedAt) {
+		t.Errorf("system write changed updated_*: %s at %v", got.UpdatedBy, got.UpdatedAt)
+	}
+}
@@ -52,7 +52,7 @@ func (r *PersistentPipelineRepository) Get(ctx context.Context, id string) (*upa
 	if err != nil {
 		return nil, err
 	}
-	_ = r.mem.Create(ctx, p)
+	_ = r.mem.store.Set(ctx, p)
 	return p, nil
 }
 
@@ -67,6 +67,8 @@ func (r *PersistentPipelineRepository) List(ctx context.Context) ([]*upal.Pipeli
 }
 
 func (r *PersistentPipelineRepository) Update(ctx context.Context, p *upal.Pipeline) error {
+	// Load the stored pipeline so the memory update keeps its created_*.
+	_, _ = r.Get(ctx, p.ID)
 	_ = r.mem.Update(ctx, p)
 	userID := upal.UserIDFromContext(ctx)
 	if err := r.db.UpdatePipeline(ctx, userID, p); err != nil {
@@ -183,3 +183,22 @@ func TestPersistentPipelineRunRepository_CreateAndListByPipeline(t *testing.T) {
 		t.Errorf("expected 1 run, got %d", len(list))
 	}
 }
+
+func TestPersistentPipelineRepository_UpdateKeepsStoredCreator(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	stored := &upal.Pipeline{ID: "pipe-db", Name: "Old", CreatedBy: "alice", UpdatedBy: "alice", CreatedAt: createdAt, UpdatedAt: createdAt}
+	stub := &stubPipelineDB{pipelines: []*upal.Pipeline{stored}}
+	// An empty memory repository, as after a restart.
+	repo := repository.NewPersistentPipelineRepository(repository.NewMemoryPipelineRepository(), stub)
+
+	p := &upal.Pipeline{ID: "pipe-db", Name: "New", UpdatedBy: "bob", UpdatedAt: time.Now()}
+	if err := repo.Update(upal.WithUserID(context.Background(), "bob"), p); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if p.CreatedBy != "alice" || !p.CreatedAt.Equal(createdAt) {
+		t.Errorf("created_* = %s at %v, want alice at %v", p.CreatedBy, p.CreatedAt, createdAt)
+	}
+	if p.UpdatedBy != "bob" || !p.UpdatedAt.After(createdAt) {
+		t.Errorf("updated_* = %s at %v", p.UpdatedBy, p.UpdatedAt)
+	}
+}
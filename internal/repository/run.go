@@ -12,4 +12,10 @@ type RunRepository interface {
 	Update(ctx context.Context, record *upal.RunRecord) error
 	ListByWorkflow(ctx context.Context, workflowName string, limit, offset int) ([]*upal.RunRecord, int, error)
 	ListAll(ctx context.Context, limit, offset int, status string) ([]*upal.RunRecord, int, error)
+	// ListAllAfter returns up to limit runs, newest first by (CreatedAt, ID),
+	// that sort after the cursor; a nil cursor starts from the newest run.
+	ListAllAfter(ctx context.Context, status string, after *upal.RunCursor, limit int) ([]*upal.RunRecord, error)
+	// Search returns up to limit runs whose workflow name, inputs, or outputs
+	// contain every word of query, best match first.
+	Search(ctx context.Context, query string, limit int) ([]*upal.RunRecord, error)
 }
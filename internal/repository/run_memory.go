@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/soochol/upal/internal/upal"
@@ -87,6 +89,67 @@ func (r *MemoryRunRepository) ListAll(_ context.Context, limit, offset int, stat
 	return sortAndPaginate(all, limit, offset), len(all), nil
 }
 
+func (r *MemoryRunRepository) ListAllAfter(_ context.Context, status string, after *upal.RunCursor, limit int) ([]*upal.RunRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var page []*upal.RunRecord
+	for _, rec := range r.records {
+		if status != "" && string(rec.Status) != status {
+			continue
+		}
+		if after != nil && !runBefore(rec, after) {
+			continue
+		}
+		page = append(page, rec)
+	}
+	sort.Slice(page, func(i, j int) bool {
+		return runBefore(page[j], &upal.RunCursor{CreatedAt: page[i].CreatedAt, ID: page[i].ID})
+	})
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page, nil
+}
+
+// runBefore reports whether rec sorts after cursor in the newest-first
+// (CreatedAt, ID) order.
+func runBefore(rec *upal.RunRecord, cursor *upal.RunCursor) bool {
+	if !rec.CreatedAt.Equal(cursor.CreatedAt) {
+		return rec.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return rec.ID < cursor.ID
+}
+
+// Search scans the stored runs, most recent first, for ones containing every
+// word of query.
+func (r *MemoryRunRepository) Search(_ context.Context, query string, limit int) ([]*upal.RunRecord, error) {
+	terms := strings.Fields(strings.ToLower(query))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []*upal.RunRecord
+	for _, rec := range r.records {
+		if runContainsAll(rec, terms) {
+			matched = append(matched, rec)
+		}
+	}
+	return sortAndPaginate(matched, limit, 0), nil
+}
+
+func runContainsAll(rec *upal.RunRecord, terms []string) bool {
+	inputs, _ := json.Marshal(rec.Inputs)
+	outputs, _ := json.Marshal(rec.Outputs)
+	text := strings.ToLower(rec.WorkflowName + " " + string(inputs) + " " + string(outputs))
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
 // sortAndPaginate sorts runs by CreatedAt descending and returns the requested page.
 func sortAndPaginate(runs []*upal.RunRecord, limit, offset int) []*upal.RunRecord {
 	sort.Slice(runs, func(i, j int) bool {
@@ -19,7 +19,10 @@ func NewPersistentRunRepository(mem *MemoryRunRepository, database *db.DB) *Pers
 
 func (r *PersistentRunRepository) Create(ctx context.Context, record *upal.RunRecord) error {
 	_ = r.mem.Create(ctx, record)
-	userID := upal.UserIDFromContext(ctx)
+	userID := record.UserID
+	if userID == "" {
+		userID = upal.UserIDFromContext(ctx)
+	}
 	if err := r.db.CreateRun(ctx, userID, record); err != nil {
 		slog.Warn("db create run failed, in-memory only", "err", err)
 	}
@@ -44,7 +47,10 @@ func (r *PersistentRunRepository) Get(ctx context.Context, id string) (*upal.Run
 
 func (r *PersistentRunRepository) Update(ctx context.Context, record *upal.RunRecord) error {
 	_ = r.mem.Update(ctx, record)
-	userID := upal.UserIDFromContext(ctx)
+	userID := record.UserID
+	if userID == "" {
+		userID = upal.UserIDFromContext(ctx)
+	}
 	if err := r.db.UpdateRun(ctx, userID, record); err != nil {
 		slog.Warn("db update run failed, in-memory only", "err", err)
 	}
@@ -74,3 +80,23 @@ func (r *PersistentRunRepository) ListAll(ctx context.Context, limit, offset int
 	slog.Warn("db list all runs failed, falling back to in-memory", "err", err)
 	return r.mem.ListAll(ctx, limit, offset, status)
 }
+
+func (r *PersistentRunRepository) ListAllAfter(ctx context.Context, status string, after *upal.RunCursor, limit int) ([]*upal.RunRecord, error) {
+	userID := upal.UserIDFromContext(ctx)
+	runs, err := r.db.ListAllRunsAfter(ctx, userID, status, after, limit)
+	if err == nil {
+		return runs, nil
+	}
+	slog.Warn("db list runs after cursor failed, falling back to in-memory", "err", err)
+	return r.mem.ListAllAfter(ctx, status, after, limit)
+}
+
+func (r *PersistentRunRepository) Search(ctx context.Context, query string, limit int) ([]*upal.RunRecord, error) {
+	userID := upal.UserIDFromContext(ctx)
+	runs, err := r.db.SearchRuns(ctx, userID, query, limit)
+	if err == nil {
+		return runs, nil
+	}
+	slog.Warn("db search runs failed, falling back to in-memory", "err", err)
+	return r.mem.Search(ctx, query, limit)
+}
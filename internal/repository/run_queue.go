@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// ErrLeaseLost is returned when a worker renews or completes a queued run
+// whose lease expired and was claimed by another worker.
+var ErrLeaseLost = errors.New("run queue lease lost")
+
+// ErrAlreadyQueued is returned by Enqueue when an item with the same ID was
+// already queued, typically the same schedule tick queued by another
+// instance.
+var ErrAlreadyQueued = errors.New("run already queued")
+
+// RunQueueRepository stores scheduled ticks until a worker has executed them.
+type RunQueueRepository interface {
+	// Enqueue adds q, or returns ErrAlreadyQueued when its ID is taken. IDs
+	// act as idempotency keys, so every instance may enqueue the same tick
+	// and only the first one counts.
+	Enqueue(ctx context.Context, q *upal.QueuedRun) error
+	// Claim leases the oldest claimable item to owner, or returns nil when
+	// none is claimable at now.
+	Claim(ctx context.Context, owner string, lease time.Duration, now time.Time) (*upal.QueuedRun, error)
+	Renew(ctx context.Context, id, owner string, expiresAt time.Time) error
+	Complete(ctx context.Context, id, owner string) error
+}
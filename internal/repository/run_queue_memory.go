@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+type MemoryRunQueueRepository struct {
+	mu    sync.Mutex
+	items map[string]*upal.QueuedRun
+}
+
+func NewMemoryRunQueueRepository() *MemoryRunQueueRepository {
+	return &MemoryRunQueueRepository{items: make(map[string]*upal.QueuedRun)}
+}
+
+// Enqueue adds q unless an item with its ID is still queued. Completed items
+// are forgotten; a single process does not enqueue the same tick twice.
+func (r *MemoryRunQueueRepository) Enqueue(_ context.Context, q *upal.QueuedRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[q.ID]; ok {
+		return ErrAlreadyQueued
+	}
+	cp := *q
+	r.items[q.ID] = &cp
+	return nil
+}
+
+func (r *MemoryRunQueueRepository) Claim(_ context.Context, owner string, lease time.Duration, now time.Time) (*upal.QueuedRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimable []*upal.QueuedRun
+	for _, q := range r.items {
+		if q.Claimable(now) {
+			claimable = append(claimable, q)
+		}
+	}
+	if len(claimable) == 0 {
+		return nil, nil
+	}
+	sort.Slice(claimable, func(i, j int) bool { return claimable[i].EnqueuedAt.Before(claimable[j].EnqueuedAt) })
+
+	q := claimable[0]
+	expires := now.Add(lease)
+	q.LeaseOwner = owner
+	q.LeaseExpiresAt = &expires
+	q.Attempts++
+	cp := *q
+	return &cp, nil
+}
+
+func (r *MemoryRunQueueRepository) Renew(_ context.Context, id, owner string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q, ok := r.items[id]
+	if !ok || q.LeaseOwner != owner {
+		return ErrLeaseLost
+	}
+	q.LeaseExpiresAt = &expiresAt
+	return nil
+}
+
+func (r *MemoryRunQueueRepository) Complete(_ context.Context, id, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q, ok := r.items[id]
+	if !ok || q.LeaseOwner != owner {
+		return ErrLeaseLost
+	}
+	delete(r.items, id)
+	return nil
+}
+
+// Len returns the number of queued items, leased or not.
+func (r *MemoryRunQueueRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.items)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestMemoryRunQueue_ExpiredLeaseIsReclaimed(t *testing.T) {
+	repo := NewMemoryRunQueueRepository()
+	ctx := context.Background()
+	t0 := time.Now()
+
+	if err := repo.Enqueue(ctx, &upal.QueuedRun{ID: "q1", ScheduleID: "sched-1", EnqueuedAt: t0}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	first, err := repo.Claim(ctx, "worker-a", time.Minute, t0)
+	if err != nil || first == nil || first.Attempts != 1 {
+		t.Fatalf("first claim = %+v, %v; want attempt 1", first, err)
+	}
+
+	// Still leased: nothing to claim.
+	if q, _ := repo.Claim(ctx, "worker-b", time.Minute, t0.Add(30*time.Second)); q != nil {
+		t.Fatalf("claimed %q while its lease was held", q.ID)
+	}
+
+	// worker-a died; once its lease expires worker-b takes the item over.
+	second, err := repo.Claim(ctx, "worker-b", time.Minute, t0.Add(2*time.Minute))
+	if err != nil || second == nil || second.ID != "q1" || second.Attempts != 2 {
+		t.Fatalf("re-claim = %+v, %v; want q1 at attempt 2", second, err)
+	}
+
+	if err := repo.Renew(ctx, "q1", "worker-a", t0.Add(3*time.Minute)); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("stale renew: err = %v, want ErrLeaseLost", err)
+	}
+	if err := repo.Complete(ctx, "q1", "worker-a"); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("stale complete: err = %v, want ErrLeaseLost", err)
+	}
+	if err := repo.Complete(ctx, "q1", "worker-b"); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if repo.Len() != 0 {
+		t.Errorf("queue length = %d, want 0", repo.Len())
+	}
+}
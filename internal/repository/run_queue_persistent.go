@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// RunQueueDB defines the database methods used by PersistentRunQueueRepository.
+type RunQueueDB interface {
+	EnqueueRun(ctx context.Context, q *upal.QueuedRun) (bool, error)
+	ClaimQueuedRun(ctx context.Context, owner string, lease time.Duration, now time.Time) (*upal.QueuedRun, error)
+	RenewQueuedRunLease(ctx context.Context, id, owner string, expiresAt time.Time) (bool, error)
+	CompleteQueuedRun(ctx context.Context, id, owner string) (bool, error)
+}
+
+// PersistentRunQueueRepository keeps the queue in Postgres only. Unlike the
+// other persistent repositories it has no in-memory fallback: leases and
+// idempotency keys must be shared by every instance to be meaningful.
+type PersistentRunQueueRepository struct {
+	db RunQueueDB
+}
+
+func NewPersistentRunQueueRepository(database RunQueueDB) *PersistentRunQueueRepository {
+	return &PersistentRunQueueRepository{db: database}
+}
+
+func (r *PersistentRunQueueRepository) Enqueue(ctx context.Context, q *upal.QueuedRun) error {
+	ok, err := r.db.EnqueueRun(ctx, q)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAlreadyQueued
+	}
+	return nil
+}
+
+func (r *PersistentRunQueueRepository) Claim(ctx context.Context, owner string, lease time.Duration, now time.Time) (*upal.QueuedRun, error) {
+	return r.db.ClaimQueuedRun(ctx, owner, lease, now)
+}
+
+func (r *PersistentRunQueueRepository) Renew(ctx context.Context, id, owner string, expiresAt time.Time) error {
+	ok, err := r.db.RenewQueuedRunLease(ctx, id, owner, expiresAt)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *PersistentRunQueueRepository) Complete(ctx context.Context, id, owner string) error {
+	ok, err := r.db.CompleteQueuedRun(ctx, id, owner)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLeaseLost
+	}
+	return nil
+}
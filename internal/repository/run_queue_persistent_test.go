@@ -0,0 +1,147 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// stubRunQueueDB is a fake run_queue table. Like the Postgres one it keeps
+// completed rows, so their IDs cannot be enqueued again.
+type stubRunQueueDB struct {
+	items     map[string]*upal.QueuedRun
+	completed map[string]bool
+	err       error
+}
+
+func newStubRunQueueDB() *stubRunQueueDB {
+	return &stubRunQueueDB{items: make(map[string]*upal.QueuedRun), completed: make(map[string]bool)}
+}
+
+func (s *stubRunQueueDB) EnqueueRun(_ context.Context, q *upal.QueuedRun) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	if _, ok := s.items[q.ID]; ok {
+		return false, nil
+	}
+	cp := *q
+	s.items[q.ID] = &cp
+	return true, nil
+}
+func (s *stubRunQueueDB) ClaimQueuedRun(_ context.Context, owner string, lease time.Duration, now time.Time) (*upal.QueuedRun, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var oldest *upal.QueuedRun
+	for id, q := range s.items {
+		if s.completed[id] || !q.Claimable(now) {
+			continue
+		}
+		if oldest == nil || q.EnqueuedAt.Before(oldest.EnqueuedAt) {
+			oldest = q
+		}
+	}
+	if oldest == nil {
+		return nil, nil
+	}
+	expires := now.Add(lease)
+	oldest.LeaseOwner = owner
+	oldest.LeaseExpiresAt = &expires
+	oldest.Attempts++
+	cp := *oldest
+	return &cp, nil
+}
+func (s *stubRunQueueDB) RenewQueuedRunLease(_ context.Context, id, owner string, expiresAt time.Time) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	q, ok := s.items[id]
+	if !ok || s.completed[id] || q.LeaseOwner != owner {
+		return false, nil
+	}
+	q.LeaseExpiresAt = &expiresAt
+	return true, nil
+}
+func (s *stubRunQueueDB) CompleteQueuedRun(_ context.Context, id, owner string) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	q, ok := s.items[id]
+	if !ok || s.completed[id] || q.LeaseOwner != owner {
+		return false, nil
+	}
+	s.completed[id] = true
+	return true, nil
+}
+
+func TestPersistentRunQueue_TickEnqueuedOnce(t *testing.T) {
+	repo := repository.NewPersistentRunQueueRepository(newStubRunQueueDB())
+	ctx := context.Background()
+	t0 := time.Now()
+	tick := &upal.QueuedRun{ID: "tick-sched-1-1700000000", ScheduleID: "sched-1", EnqueuedAt: t0}
+
+	if err := repo.Enqueue(ctx, tick); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	// A second instance firing the same tick.
+	if err := repo.Enqueue(ctx, tick); !errors.Is(err, repository.ErrAlreadyQueued) {
+		t.Fatalf("duplicate Enqueue: err = %v, want ErrAlreadyQueued", err)
+	}
+
+	claimed, err := repo.Claim(ctx, "worker-a", time.Minute, t0)
+	if err != nil || claimed == nil || claimed.ID != tick.ID {
+		t.Fatalf("Claim = %+v, %v; want %s", claimed, err, tick.ID)
+	}
+	if err := repo.Complete(ctx, tick.ID, "worker-a"); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	// An instance that lagged past the run still cannot queue the tick again.
+	if err := repo.Enqueue(ctx, tick); !errors.Is(err, repository.ErrAlreadyQueued) {
+		t.Errorf("Enqueue after completion: err = %v, want ErrAlreadyQueued", err)
+	}
+	if q, _ := repo.Claim(ctx, "worker-b", time.Minute, t0.Add(time.Hour)); q != nil {
+		t.Errorf("claimed completed item %q", q.ID)
+	}
+}
+
+func TestPersistentRunQueue_LostLease(t *testing.T) {
+	repo := repository.NewPersistentRunQueueRepository(newStubRunQueueDB())
+	ctx := context.Background()
+	t0 := time.Now()
+
+	repo.Enqueue(ctx, &upal.QueuedRun{ID: "q1", ScheduleID: "sched-1", EnqueuedAt: t0})
+	if _, err := repo.Claim(ctx, "worker-a", time.Minute, t0); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	second, err := repo.Claim(ctx, "worker-b", time.Minute, t0.Add(2*time.Minute))
+	if err != nil || second == nil || second.Attempts != 2 {
+		t.Fatalf("re-claim = %+v, %v; want attempt 2", second, err)
+	}
+
+	if err := repo.Renew(ctx, "q1", "worker-a", t0.Add(3*time.Minute)); !errors.Is(err, repository.ErrLeaseLost) {
+		t.Errorf("stale renew: err = %v, want ErrLeaseLost", err)
+	}
+	if err := repo.Complete(ctx, "q1", "worker-a"); !errors.Is(err, repository.ErrLeaseLost) {
+		t.Errorf("stale complete: err = %v, want ErrLeaseLost", err)
+	}
+	if err := repo.Complete(ctx, "q1", "worker-b"); err != nil {
+		t.Errorf("Complete: %v", err)
+	}
+}
+
+func TestPersistentRunQueue_DBErrorPropagates(t *testing.T) {
+	db := newStubRunQueueDB()
+	db.err = errors.New("connection refused")
+	repo := repository.NewPersistentRunQueueRepository(db)
+
+	err := repo.Enqueue(context.Background(), &upal.QueuedRun{ID: "q1", ScheduleID: "sched-1", EnqueuedAt: time.Now()})
+	if err == nil || errors.Is(err, repository.ErrAlreadyQueued) {
+		t.Errorf("Enqueue err = %v, want the DB error", err)
+	}
+}
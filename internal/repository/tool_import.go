@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// OpenAPIImportRepository stores imported OpenAPI toolsets by namespace.
+// Tool namespaces are shared by every user, so imports are not user-scoped;
+// each import records its owner in UserID instead.
+type OpenAPIImportRepository interface {
+	// Save creates or replaces the import for imp.Namespace.
+	Save(ctx context.Context, imp *upal.OpenAPIImport) error
+	Get(ctx context.Context, namespace string) (*upal.OpenAPIImport, error)
+	List(ctx context.Context) ([]*upal.OpenAPIImport, error)
+	Delete(ctx context.Context, namespace string) error
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	memstore "github.com/soochol/upal/internal/repository/memory"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type MemoryOpenAPIImportRepository struct {
+	store *memstore.Store[*upal.OpenAPIImport]
+}
+
+func NewMemoryOpenAPIImportRepository() *MemoryOpenAPIImportRepository {
+	return &MemoryOpenAPIImportRepository{
+		store: memstore.New(func(imp *upal.OpenAPIImport) string { return imp.Namespace }),
+	}
+}
+
+func (r *MemoryOpenAPIImportRepository) Save(ctx context.Context, imp *upal.OpenAPIImport) error {
+	return r.store.Set(ctx, imp)
+}
+
+func (r *MemoryOpenAPIImportRepository) Get(ctx context.Context, namespace string) (*upal.OpenAPIImport, error) {
+	imp, err := r.store.Get(ctx, namespace)
+	if errors.Is(err, memstore.ErrNotFound) {
+		return nil, fmt.Errorf("openapi import %q: %w", namespace, ErrNotFound)
+	}
+	return imp, err
+}
+
+func (r *MemoryOpenAPIImportRepository) List(ctx context.Context) ([]*upal.OpenAPIImport, error) {
+	return r.store.All(ctx)
+}
+
+func (r *MemoryOpenAPIImportRepository) Delete(ctx context.Context, namespace string) error {
+	err := r.store.Delete(ctx, namespace)
+	if errors.Is(err, memstore.ErrNotFound) {
+		return fmt.Errorf("openapi import %q: %w", namespace, ErrNotFound)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/soochol/upal/internal/db"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type PersistentOpenAPIImportRepository struct {
+	mem *MemoryOpenAPIImportRepository
+	db  *db.DB
+}
+
+func NewPersistentOpenAPIImportRepository(mem *MemoryOpenAPIImportRepository, database *db.DB) *PersistentOpenAPIImportRepository {
+	return &PersistentOpenAPIImportRepository{mem: mem, db: database}
+}
+
+func (r *PersistentOpenAPIImportRepository) Save(ctx context.Context, imp *upal.OpenAPIImport) error {
+	_ = r.mem.Save(ctx, imp)
+	if err := r.db.SaveOpenAPIImport(ctx, imp); err != nil {
+		slog.Warn("db save openapi import failed, in-memory only", "err", err)
+	}
+	return nil
+}
+
+func (r *PersistentOpenAPIImportRepository) Get(ctx context.Context, namespace string) (*upal.OpenAPIImport, error) {
+	imp, err := r.db.GetOpenAPIImport(ctx, namespace)
+	if err == nil {
+		return imp, nil
+	}
+	return r.mem.Get(ctx, namespace)
+}
+
+func (r *PersistentOpenAPIImportRepository) List(ctx context.Context) ([]*upal.OpenAPIImport, error) {
+	imports, err := r.db.ListOpenAPIImports(ctx)
+	if err == nil {
+		return imports, nil
+	}
+	slog.Warn("db list openapi imports failed, falling back to in-memory", "err", err)
+	return r.mem.List(ctx)
+}
+
+func (r *PersistentOpenAPIImportRepository) Delete(ctx context.Context, namespace string) error {
+	_ = r.mem.Delete(ctx, namespace)
+	if err := r.db.DeleteOpenAPIImport(ctx, namespace); err != nil {
+		slog.Warn("db delete openapi import failed", "err", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/soochol/upal/internal/upal"
 )
@@ -13,3 +14,22 @@ type WorkflowRepository interface {
 	Update(ctx context.Context, name string, wf *upal.WorkflowDefinition) error
 	Delete(ctx context.Context, name string) error
 }
+
+// stampWorkflow fills in wf's ownership metadata for a save. prev is the
+// stored definition being replaced, or nil on create. Its created_* fields
+// are carried over, as are its updated_* when wf has none; the API handlers
+// set updated_* for user edits, so system writes leave them alone. On create,
+// created_* start as wf's updated_*.
+func stampWorkflow(wf, prev *upal.WorkflowDefinition) {
+	if prev != nil && !prev.CreatedAt.IsZero() {
+		wf.CreatedBy, wf.CreatedAt = prev.CreatedBy, prev.CreatedAt
+		if wf.UpdatedAt.IsZero() {
+			wf.UpdatedBy, wf.UpdatedAt = prev.UpdatedBy, prev.UpdatedAt
+		}
+		return
+	}
+	if wf.UpdatedAt.IsZero() {
+		wf.UpdatedAt = time.Now()
+	}
+	wf.CreatedBy, wf.CreatedAt = wf.UpdatedBy, wf.UpdatedAt
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 
+	"github.com/soochol/upal/internal/config"
 	"github.com/soochol/upal/internal/crypto"
+	upalmodel "github.com/soochol/upal/internal/model"
 	"github.com/soochol/upal/internal/repository"
 	"github.com/soochol/upal/internal/upal"
 )
@@ -109,26 +113,31 @@ func (s *AIProviderService) SetDefault(ctx context.Context, id string) error {
 
 // Resolve returns a provider with the API key decrypted, for runtime LLM building.
 func (s *AIProviderService) Resolve(ctx context.Context, id string) (*upal.AIProvider, error) {
-	p, err := s.repo.Get(ctx, id)
+	stored, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if err := s.decryptKey(p); err != nil {
+	// Decrypt a copy: repositories may hand out their stored value.
+	p := *stored
+	if err := s.decryptKey(&p); err != nil {
 		return nil, err
 	}
-	return p, nil
+	return &p, nil
 }
 
 // ListAll returns full provider objects with decrypted API keys (for building ProviderConfigs).
 func (s *AIProviderService) ListAll(ctx context.Context) ([]*upal.AIProvider, error) {
-	providers, err := s.repo.List(ctx)
+	stored, err := s.repo.List(ctx)
 	if err != nil {
 		return nil, err
 	}
-	for _, p := range providers {
-		if err := s.decryptKey(p); err != nil {
+	providers := make([]*upal.AIProvider, len(stored))
+	for i, sp := range stored {
+		p := *sp
+		if err := s.decryptKey(&p); err != nil {
 			return nil, err
 		}
+		providers[i] = &p
 	}
 	return providers, nil
 }
@@ -156,3 +165,73 @@ func (s *AIProviderService) decryptKey(p *upal.AIProvider) error {
 	p.APIKey = decrypted
 	return nil
 }
+
+// DefaultLLM returns the default LLM provider with its API key decrypted.
+// Should several be marked default, the first in name order wins, so the
+// choice does not depend on storage order.
+func (s *AIProviderService) DefaultLLM(ctx context.Context) (*upal.AIProvider, error) {
+	providers, err := s.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found *upal.AIProvider
+	llms := 0
+	for _, p := range providers {
+		if p.Category != upal.AICategoryLLM {
+			continue
+		}
+		llms++
+		if p.IsDefault && (found == nil || p.Name < found.Name) {
+			found = p
+		}
+	}
+	switch {
+	case llms == 0:
+		return nil, upal.ErrNoLLMProvider
+	case found == nil:
+		return nil, fmt.Errorf("%w as default", upal.ErrNoLLMProvider)
+	}
+	return found, nil
+}
+
+// ImportConfigProviders registers config.yaml providers when no providers are
+// stored yet. Providers are created in name order: the first of each category
+// becomes its default, so the default model is the same on every startup with
+// the same config. Unknown provider types are skipped.
+func (s *AIProviderService) ImportConfigProviders(ctx context.Context, providers map[string]config.ProviderConfig) error {
+	existing, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+	typeToCategory := make(map[string]upal.AIProviderCategory)
+	for cat, types := range upal.ValidProviderTypes {
+		for _, t := range types {
+			typeToCategory[t] = cat
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(providers)) {
+		pc := providers[name]
+		cat, ok := typeToCategory[pc.Type]
+		if !ok {
+			slog.Warn("skipping migration: unknown provider type", "name", name, "type", pc.Type)
+			continue
+		}
+		modelName, _ := upalmodel.FirstModelForType(pc.Type)
+		p := &upal.AIProvider{
+			Name:     name,
+			Category: cat,
+			Type:     pc.Type,
+			Model:    modelName,
+			APIKey:   pc.APIKey,
+		}
+		if err := s.Create(ctx, p); err != nil {
+			slog.Warn("failed to migrate config.yaml provider to DB", "name", name, "err", err)
+		} else {
+			slog.Info("migrated config.yaml provider to DB", "name", name, "type", pc.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soochol/upal/internal/config"
+	"github.com/soochol/upal/internal/crypto"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestAIProviderService_DefaultLLMStableAcrossStartups(t *testing.T) {
+	providers := map[string]config.ProviderConfig{
+		"zeta":   {Type: "openai", APIKey: "k1"},
+		"alpha":  {Type: "anthropic", APIKey: "k2"},
+		"middle": {Type: "gemini", APIKey: "k3"},
+		"voice":  {Type: "openai-tts", APIKey: "k4"},
+		"bogus":  {Type: "unknown"},
+	}
+	enc, err := crypto.NewEncryptor([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		svc := NewAIProviderService(repository.NewMemoryAIProviderRepository(), enc)
+		if err := svc.ImportConfigProviders(ctx, providers); err != nil {
+			t.Fatal(err)
+		}
+		p, err := svc.DefaultLLM(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "alpha" || p.APIKey != "k2" {
+			t.Fatalf("startup %d: default LLM = %s (key %q), want alpha", i, p.Name, p.APIKey)
+		}
+		all, _ := svc.ListAll(ctx)
+		if len(all) != 4 {
+			t.Fatalf("expected 4 imported providers, got %d", len(all))
+		}
+	}
+}
+
+func TestAIProviderService_ImportSkipsWhenProvidersExist(t *testing.T) {
+	ctx := context.Background()
+	svc := NewAIProviderService(repository.NewMemoryAIProviderRepository(), nil)
+	if err := svc.Create(ctx, &upal.AIProvider{Name: "mine", Category: upal.AICategoryLLM, Type: "openai"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ImportConfigProviders(ctx, map[string]config.ProviderConfig{"alpha": {Type: "anthropic"}}); err != nil {
+		t.Fatal(err)
+	}
+	all, _ := svc.ListAll(ctx)
+	if len(all) != 1 || all[0].Name != "mine" {
+		t.Errorf("import touched a populated repository: %+v", all)
+	}
+	if p, err := svc.DefaultLLM(ctx); err != nil || p.Name != "mine" {
+		t.Errorf("DefaultLLM = %v, %v", p, err)
+	}
+}
+
+func TestAIProviderService_DefaultLLMNoProviders(t *testing.T) {
+	ctx := context.Background()
+	svc := NewAIProviderService(repository.NewMemoryAIProviderRepository(), nil)
+	if _, err := svc.DefaultLLM(ctx); !errors.Is(err, upal.ErrNoLLMProvider) {
+		t.Fatalf("empty repo: expected ErrNoLLMProvider, got %v", err)
+	}
+
+	if err := svc.Create(ctx, &upal.AIProvider{Name: "pics", Category: upal.AICategoryImage, Type: "gemini-image"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.DefaultLLM(ctx); !errors.Is(err, upal.ErrNoLLMProvider) {
+		t.Errorf("only an image provider: expected ErrNoLLMProvider, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+var _ ports.AuditRecorder = (*AuditService)(nil)
+
+// AuditService records and queries the audit log of mutating operations.
+type AuditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record fills in the ID, actor (from the request's user) and timestamp, then
+// stores the entry. Failures are logged, not returned.
+func (s *AuditService) Record(ctx context.Context, entry *upal.AuditEntry) {
+	if entry.ID == "" {
+		entry.ID = upal.GenerateID("audit")
+	}
+	if entry.Actor == "" {
+		entry.Actor = upal.UserIDFromContext(ctx)
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		slog.WarnContext(ctx, "audit: record failed", "resource", entry.ResourceType, "id", entry.ResourceID, "err", err)
+	}
+}
+
+// List returns the current user's audit entries matching filter, newest first.
+func (s *AuditService) List(ctx context.Context, filter upal.AuditFilter) ([]*upal.AuditEntry, error) {
+	return s.repo.List(ctx, filter)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -87,6 +88,26 @@ func (s *AuthService) Enabled() bool {
 	return s.authCfg.Google.IsConfigured() || s.authCfg.GitHub.IsConfigured()
 }
 
+// IsAdmin reports whether userID may use the admin API: with auth disabled
+// every caller is the default user and is allowed; otherwise the user's ID
+// or email must be listed in auth.admins.
+func (s *AuthService) IsAdmin(ctx context.Context, userID string) bool {
+	if !s.Enabled() {
+		return true
+	}
+	if slices.Contains(s.authCfg.Admins, userID) {
+		return true
+	}
+	if s.database == nil || len(s.authCfg.Admins) == 0 {
+		return false
+	}
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(s.authCfg.Admins, func(a string) bool { return strings.EqualFold(a, user.Email) })
+}
+
 func (s *AuthService) OAuthConfig(provider string) (*oauth2.Config, error) {
 	switch provider {
 	case "google":
@@ -4,12 +4,16 @@ import (
 	"context"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/soochol/upal/internal/upal"
 	"github.com/soochol/upal/internal/upal/ports"
 )
 
-var _ ports.ConcurrencyControl = (*ConcurrencyLimiter)(nil)
+var (
+	_ ports.ConcurrencyControl = (*ConcurrencyLimiter)(nil)
+	_ ports.StartLimiter       = (*ConcurrencyLimiter)(nil)
+)
 
 // ConcurrencyLimiter controls how many workflows can execute simultaneously
 // using channel-based semaphores at global and per-workflow levels.
@@ -19,6 +23,9 @@ type ConcurrencyLimiter struct {
 	mu          sync.Mutex
 	limits      upal.ConcurrencyLimits
 	activeCount atomic.Int64
+
+	rate  *startRate // nil when starts are not rate limited
+	usage concurrencyUsage
 }
 
 func NewConcurrencyLimiter(limits upal.ConcurrencyLimits) *ConcurrencyLimiter {
@@ -33,9 +40,15 @@ func NewConcurrencyLimiter(limits upal.ConcurrencyLimits) *ConcurrencyLimiter {
 		global:      make(chan struct{}, limits.GlobalMax),
 		perWorkflow: make(map[string]chan struct{}),
 		limits:      limits,
+		rate:        newStartRate(limits, time.Now),
+		usage:       concurrencyUsage{now: time.Now},
 	}
 }
 
+// Acquire blocks until a global and a per-workflow slot are free and, when
+// starts are rate limited, until the start window has room for another run.
+// Slots are held while waiting for the window so a backlog cannot start in a
+// burst once capacity frees up.
 func (c *ConcurrencyLimiter) Acquire(ctx context.Context, workflowName string) error {
 	select {
 	case c.global <- struct{}{}:
@@ -46,17 +59,62 @@ func (c *ConcurrencyLimiter) Acquire(ctx context.Context, workflowName string) e
 	wfCh := c.getOrCreateWorkflowChan(workflowName)
 	select {
 	case wfCh <- struct{}{}:
-		c.activeCount.Add(1)
-		return nil
 	case <-ctx.Done():
 		<-c.global
 		return ctx.Err()
 	}
+
+	if err := c.rate.wait(ctx, workflowName); err != nil {
+		c.releaseSlots(workflowName)
+		return err
+	}
+	c.activeCount.Add(1)
+	c.observe()
+	return nil
+}
+
+// TryAcquire is the non-blocking form of Acquire. It reports false, holding
+// nothing, when either the global or the per-workflow limit is saturated or
+// the start window is full.
+func (c *ConcurrencyLimiter) TryAcquire(workflowName string) bool {
+	select {
+	case c.global <- struct{}{}:
+	default:
+		return false
+	}
+
+	wfCh := c.getOrCreateWorkflowChan(workflowName)
+	select {
+	case wfCh <- struct{}{}:
+	default:
+		<-c.global
+		return false
+	}
+
+	if !c.rate.tryReserve(workflowName) {
+		c.releaseSlots(workflowName)
+		return false
+	}
+	c.activeCount.Add(1)
+	c.observe()
+	return true
+}
+
+// ReserveStart waits until the start window has room for another start of
+// workflowName and records it. No slot is taken: the caller already holds
+// one, as a retry executor does across attempts.
+func (c *ConcurrencyLimiter) ReserveStart(ctx context.Context, workflowName string) error {
+	return c.rate.wait(ctx, workflowName)
 }
 
 func (c *ConcurrencyLimiter) Release(workflowName string) {
 	c.activeCount.Add(-1)
+	c.observe()
+	c.releaseSlots(workflowName)
+}
 
+// releaseSlots frees the global and per-workflow slots of one run.
+func (c *ConcurrencyLimiter) releaseSlots(workflowName string) {
 	c.mu.Lock()
 	if ch, ok := c.perWorkflow[workflowName]; ok {
 		select {
@@ -73,17 +131,27 @@ func (c *ConcurrencyLimiter) Release(workflowName string) {
 }
 
 type ConcurrencyStats struct {
-	ActiveRuns int `json:"active_runs"`
-	GlobalMax  int `json:"global_max"`
+	ActiveRuns  int `json:"active_runs"`
+	GlobalMax   int `json:"global_max"`
 	PerWorkflow int `json:"per_workflow"`
+	// HighWater is the most runs active at once since the server started.
+	HighWater      int                 `json:"high_water"`
+	SaturatedSince *time.Time          `json:"saturated_since,omitempty"`
+	Windows        []ConcurrencyWindow `json:"windows"`
+	// StartRate is set when run starts are rate limited.
+	StartRate *StartRateStats `json:"start_rate,omitempty"`
 }
 
 func (c *ConcurrencyLimiter) Stats() ConcurrencyStats {
-	return ConcurrencyStats{
-		ActiveRuns:  int(c.activeCount.Load()),
+	active := int(c.activeCount.Load())
+	stats := ConcurrencyStats{
+		ActiveRuns:  active,
 		GlobalMax:   c.limits.GlobalMax,
 		PerWorkflow: c.limits.PerWorkflow,
 	}
+	c.usage.fill(&stats, active)
+	stats.StartRate = c.rate.stats()
+	return stats
 }
 
 func (c *ConcurrencyLimiter) getOrCreateWorkflowChan(name string) chan struct{} {
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// defaultStartWindow is the start-rate window when limits set a cap but no
+// StartWindow.
+const defaultStartWindow = time.Minute
+
+// StartRateStats reports the start-rate caps of a ConcurrencyLimiter and how
+// many starts the current window holds.
+type StartRateStats struct {
+	WindowSeconds  float64        `json:"window_seconds"`
+	GlobalMax      int            `json:"global_max,omitempty"`
+	PerWorkflowMax int            `json:"per_workflow_max,omitempty"`
+	GlobalStarts   int            `json:"global_starts"`
+	WorkflowStarts map[string]int `json:"workflow_starts,omitempty"`
+	// Delayed counts Acquire calls that had to wait for the window and
+	// Rejected counts TryAcquire calls refused because it was full.
+	Delayed  int64 `json:"delayed"`
+	Rejected int64 `json:"rejected"`
+}
+
+// startRate caps run starts within a sliding window, globally and per
+// workflow, by keeping the start times still inside the window. A nil
+// *startRate allows every start.
+type startRate struct {
+	mu             sync.Mutex
+	now            func() time.Time
+	window         time.Duration
+	globalMax      int
+	perWorkflowMax int
+	global         []time.Time
+	perWorkflow    map[string][]time.Time
+	delayed        int64
+	rejected       int64
+}
+
+// newStartRate returns nil when limits set no start cap.
+func newStartRate(limits upal.ConcurrencyLimits, now func() time.Time) *startRate {
+	if limits.GlobalStartsPerWindow <= 0 && limits.PerWorkflowStartsPerWindow <= 0 {
+		return nil
+	}
+	window := limits.StartWindow
+	if window <= 0 {
+		window = defaultStartWindow
+	}
+	return &startRate{
+		now:            now,
+		window:         window,
+		globalMax:      max(limits.GlobalStartsPerWindow, 0),
+		perWorkflowMax: max(limits.PerWorkflowStartsPerWindow, 0),
+		perWorkflow:    make(map[string][]time.Time),
+	}
+}
+
+// reserve records a start of workflowName and returns zero when the window
+// has room; otherwise it records nothing and returns how long until the
+// oldest blocking start leaves the window.
+func (r *startRate) reserve(workflowName string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.prune(workflowName, now)
+	starts := r.perWorkflow[workflowName]
+	var wait time.Duration
+	if r.globalMax > 0 && len(r.global) >= r.globalMax {
+		wait = max(wait, r.global[len(r.global)-r.globalMax].Add(r.window).Sub(now))
+	}
+	if r.perWorkflowMax > 0 && len(starts) >= r.perWorkflowMax {
+		wait = max(wait, starts[len(starts)-r.perWorkflowMax].Add(r.window).Sub(now))
+	}
+	if wait > 0 {
+		return wait
+	}
+	r.global = append(r.global, now)
+	r.perWorkflow[workflowName] = append(starts, now)
+	return 0
+}
+
+// wait blocks until a start of workflowName fits in the window, then records
+// it. It returns ctx's error if ctx ends first.
+func (r *startRate) wait(ctx context.Context, workflowName string) error {
+	if r == nil {
+		return nil
+	}
+	for delayed := false; ; delayed = true {
+		d := r.reserve(workflowName)
+		if d == 0 {
+			return nil
+		}
+		if !delayed {
+			r.mu.Lock()
+			r.delayed++
+			r.mu.Unlock()
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// tryReserve is the non-blocking form of wait.
+func (r *startRate) tryReserve(workflowName string) bool {
+	if r == nil || r.reserve(workflowName) == 0 {
+		return true
+	}
+	r.mu.Lock()
+	r.rejected++
+	r.mu.Unlock()
+	return false
+}
+
+func (r *startRate) stats() *StartRateStats {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	stats := &StartRateStats{
+		WindowSeconds:  r.window.Seconds(),
+		GlobalMax:      r.globalMax,
+		PerWorkflowMax: r.perWorkflowMax,
+		Delayed:        r.delayed,
+		Rejected:       r.rejected,
+	}
+	for name := range r.perWorkflow {
+		r.prune(name, now)
+		if n := len(r.perWorkflow[name]); n > 0 {
+			if stats.WorkflowStarts == nil {
+				stats.WorkflowStarts = make(map[string]int)
+			}
+			stats.WorkflowStarts[name] = n
+		}
+	}
+	stats.GlobalStarts = len(r.global)
+	return stats
+}
+
+// prune drops starts that have left the window from the global list and
+// workflowName's list, forgetting workflows with none left. r.mu is held.
+func (r *startRate) prune(workflowName string, now time.Time) {
+	cutoff := now.Add(-r.window)
+	r.global = dropBefore(r.global, cutoff)
+	if starts := dropBefore(r.perWorkflow[workflowName], cutoff); len(starts) > 0 {
+		r.perWorkflow[workflowName] = starts
+	} else {
+		delete(r.perWorkflow, workflowName)
+	}
+}
+
+// dropBefore removes the leading times not after cutoff from the ascending
+// slice ts.
+func dropBefore(ts []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(ts) && !ts[i].After(cutoff) {
+		i++
+	}
+	return ts[i:]
+}
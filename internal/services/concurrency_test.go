@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -115,3 +116,215 @@ func TestConcurrencyLimiter_ConcurrentAccess(t *testing.T) {
 		t.Fatalf("expected 0 active after all done, got %d", stats.ActiveRuns)
 	}
 }
+
+func TestConcurrencyLimiter_TryAcquire(t *testing.T) {
+	limiter := NewConcurrencyLimiter(upal.ConcurrencyLimits{
+		GlobalMax:   2,
+		PerWorkflow: 1,
+	})
+
+	if !limiter.TryAcquire("wf-a") {
+		t.Fatal("expected first TryAcquire to succeed")
+	}
+	// Per-workflow limit reached; the global slot must not leak.
+	if limiter.TryAcquire("wf-a") {
+		t.Fatal("expected per-workflow limit to reject")
+	}
+	if !limiter.TryAcquire("wf-b") {
+		t.Fatal("expected wf-b to get the remaining global slot")
+	}
+	if limiter.TryAcquire("wf-c") {
+		t.Fatal("expected global limit to reject")
+	}
+
+	limiter.Release("wf-a")
+	if !limiter.TryAcquire("wf-c") {
+		t.Fatal("expected TryAcquire to succeed after release")
+	}
+}
+
+func TestConcurrencyLimiter_HighWaterAndSaturationAlert(t *testing.T) {
+	limiter := NewConcurrencyLimiter(upal.ConcurrencyLimits{GlobalMax: 2, PerWorkflow: 2})
+	alerts := make(chan SaturationEvent, 1)
+	limiter.SetSaturationAlert(20*time.Millisecond, func(ev SaturationEvent) { alerts <- ev })
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Acquire(ctx, "wf"); err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+	}
+
+	select {
+	case ev := <-alerts:
+		if ev.Duration < 20*time.Millisecond || ev.GlobalMax != 2 {
+			t.Errorf("alert = %+v, want >= 20ms at global max 2", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a saturation alert")
+	}
+
+	stats := limiter.Stats()
+	if stats.HighWater != 2 || stats.SaturatedSince == nil {
+		t.Errorf("stats while saturated = %+v", stats)
+	}
+
+	limiter.Release("wf")
+	limiter.Release("wf")
+	stats = limiter.Stats()
+	if stats.HighWater != 2 || stats.ActiveRuns != 0 || stats.SaturatedSince != nil {
+		t.Errorf("stats after release = %+v", stats)
+	}
+	if len(stats.Windows) != 3 {
+		t.Fatalf("got %d windows, want 3", len(stats.Windows))
+	}
+	for _, w := range stats.Windows {
+		if w.HighWater != 2 || w.SaturatedSeconds < 0.02 {
+			t.Errorf("window %s = %+v, want high water 2 and >= 20ms saturated", w.Window, w)
+		}
+	}
+}
+
+func TestConcurrencyLimiter_ShortSaturationDoesNotAlert(t *testing.T) {
+	limiter := NewConcurrencyLimiter(upal.ConcurrencyLimits{GlobalMax: 1, PerWorkflow: 1})
+	alerted := make(chan struct{}, 1)
+	limiter.SetSaturationAlert(50*time.Millisecond, func(SaturationEvent) { alerted <- struct{}{} })
+
+	if !limiter.TryAcquire("wf") {
+		t.Fatal("TryAcquire failed")
+	}
+	limiter.Release("wf")
+
+	select {
+	case <-alerted:
+		t.Fatal("alert fired for a saturation shorter than the threshold")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestConcurrencyUsage_SaturationSpansMinutes(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC)
+	now := base
+	limiter := NewConcurrencyLimiter(upal.ConcurrencyLimits{GlobalMax: 1, PerWorkflow: 1})
+	limiter.usage.now = func() time.Time { return now }
+
+	limiter.TryAcquire("wf")
+	now = base.Add(90 * time.Second) // 10:02:00
+	limiter.Release("wf")
+	now = base.Add(150 * time.Second) // 10:03:00
+
+	byName := map[string]ConcurrencyWindow{}
+	for _, w := range limiter.Stats().Windows {
+		byName[w.Window] = w
+	}
+	if got := byName["1m"].SaturatedSeconds; got != 0 {
+		t.Errorf("1m saturated = %v, want 0", got)
+	}
+	if got := byName["15m"].SaturatedSeconds; got != 90 {
+		t.Errorf("15m saturated = %v, want 90", got)
+	}
+	if got := byName["1m"].HighWater; got != 0 {
+		t.Errorf("1m high water = %d, want 0", got)
+	}
+}
+
+func TestConcurrencyLimiter_StartRateRejectsExcess(t *testing.T) {
+	limiter := NewConcurrencyLimiter(upal.ConcurrencyLimits{
+		GlobalMax:                  10,
+		PerWorkflow:                10,
+		GlobalStartsPerWindow:      3,
+		PerWorkflowStartsPerWindow: 2,
+		StartWindow:                time.Minute,
+	})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.rate.now = func() time.Time { return now }
+
+	start := func(wf string) bool {
+		if !limiter.TryAcquire(wf) {
+			return false
+		}
+		limiter.Release(wf) // in-flight capacity is never the constraint here
+		return true
+	}
+
+	if !start("wf-a") || !start("wf-a") {
+		t.Fatal("first two starts of wf-a were refused")
+	}
+	if start("wf-a") {
+		t.Error("third start of wf-a within the window was allowed")
+	}
+	if !start("wf-b") {
+		t.Error("wf-b was refused while the global window had room")
+	}
+	if start("wf-c") {
+		t.Error("fourth start within the window was allowed globally")
+	}
+
+	stats := limiter.Stats().StartRate
+	if stats == nil || stats.GlobalStarts != 3 || stats.WorkflowStarts["wf-a"] != 2 || stats.Rejected != 2 || stats.WindowSeconds != 60 {
+		t.Fatalf("start rate stats = %+v", stats)
+	}
+
+	now = now.Add(time.Minute + time.Second)
+	if !start("wf-c") {
+		t.Error("start refused after the window passed")
+	}
+	if got := limiter.Stats().StartRate.GlobalStarts; got != 1 {
+		t.Errorf("global starts after the window passed = %d, want 1", got)
+	}
+}
+
+func TestConcurrencyLimiter_StartRateDelaysAcquire(t *testing.T) {
+	const window = 100 * time.Millisecond
+	limiter := NewConcurrencyLimiter(upal.ConcurrencyLimits{
+		GlobalMax:             10,
+		PerWorkflow:           10,
+		GlobalStartsPerWindow: 2,
+		StartWindow:           window,
+	})
+
+	begin := time.Now()
+	var mu sync.Mutex
+	var waited []time.Duration
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Acquire(context.Background(), "wf"); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			waited = append(waited, time.Since(begin))
+			mu.Unlock()
+			limiter.Release("wf")
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(waited)
+	if waited[1] >= window/2 {
+		t.Errorf("first two starts were delayed: %v", waited)
+	}
+	if waited[2] < window-10*time.Millisecond {
+		t.Errorf("third start was not delayed to the next window: %v", waited)
+	}
+	if d := limiter.Stats().StartRate.Delayed; d != 2 {
+		t.Errorf("delayed = %d, want 2", d)
+	}
+
+	// A caller that gives up while waiting holds no slot.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	limiter.Acquire(context.Background(), "wf")
+	limiter.Release("wf")
+	limiter.Acquire(context.Background(), "wf")
+	limiter.Release("wf")
+	if err := limiter.Acquire(ctx, "wf"); err == nil {
+		t.Fatal("expected the full window to outlast the context")
+	}
+	if stats := limiter.Stats(); stats.ActiveRuns != 0 || len(limiter.global) != 0 {
+		t.Errorf("slots held after a cancelled wait: active %d, global %d", stats.ActiveRuns, len(limiter.global))
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/notify"
+)
+
+// usageWindows are the rolling windows reported in ConcurrencyStats.
+var usageWindows = []struct {
+	name    string
+	minutes int64
+}{{"1m", 1}, {"15m", 15}, {"1h", 60}}
+
+// usageHistoryMinutes is how many per-minute buckets are kept; it must cover
+// the longest usage window.
+const usageHistoryMinutes = 60
+
+// ConcurrencyWindow summarizes limiter usage over a rolling window of whole
+// minutes, including the current one.
+type ConcurrencyWindow struct {
+	Window           string  `json:"window"`
+	HighWater        int     `json:"high_water"`
+	SaturatedSeconds float64 `json:"saturated_seconds"` // time spent with active runs at GlobalMax
+}
+
+// SaturationEvent reports that active runs have stayed at GlobalMax for at
+// least the configured alert threshold.
+type SaturationEvent struct {
+	Since      time.Time
+	Duration   time.Duration
+	ActiveRuns int
+	GlobalMax  int
+}
+
+type usageBucket struct {
+	minute    int64 // unix minute the bucket holds; stale buckets are reset on use
+	peak      int
+	saturated time.Duration
+}
+
+// concurrencyUsage records peaks and saturation of a ConcurrencyLimiter.
+type concurrencyUsage struct {
+	mu             sync.Mutex
+	now            func() time.Time
+	highWater      int
+	saturatedSince time.Time
+	buckets        [usageHistoryMinutes]usageBucket
+
+	alertAfter time.Duration
+	alertFn    func(SaturationEvent)
+	alertTimer *time.Timer
+}
+
+// SetSaturationAlert calls fn once per saturation episode in which active
+// runs stay at GlobalMax for longer than after. fn runs on its own goroutine.
+func (c *ConcurrencyLimiter) SetSaturationAlert(after time.Duration, fn func(SaturationEvent)) {
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	c.usage.alertAfter = after
+	c.usage.alertFn = fn
+}
+
+// observe records the current active count. It loads the count under the
+// usage lock so the last observer always sees the latest value, whatever
+// order concurrent acquires and releases reach this point in.
+func (c *ConcurrencyLimiter) observe() {
+	u := &c.usage
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := u.now()
+	active := int(c.activeCount.Load())
+	if b := u.bucket(now); active > b.peak {
+		b.peak = active
+	}
+	if active > u.highWater {
+		u.highWater = active
+	}
+
+	saturated := active >= c.limits.GlobalMax
+	switch {
+	case saturated && u.saturatedSince.IsZero():
+		u.saturatedSince = now
+		u.armAlert(now, c.limits.GlobalMax)
+	case !saturated && !u.saturatedSince.IsZero():
+		u.addSaturation(u.saturatedSince, now)
+		u.saturatedSince = time.Time{}
+		if u.alertTimer != nil {
+			u.alertTimer.Stop()
+			u.alertTimer = nil
+		}
+	}
+}
+
+// armAlert schedules the saturation alert for the episode starting at since.
+func (u *concurrencyUsage) armAlert(since time.Time, globalMax int) {
+	if u.alertFn == nil || u.alertAfter <= 0 {
+		return
+	}
+	fn := u.alertFn
+	u.alertTimer = time.AfterFunc(u.alertAfter, func() {
+		u.mu.Lock()
+		still := u.saturatedSince.Equal(since)
+		ev := SaturationEvent{Since: since, Duration: u.now().Sub(since), GlobalMax: globalMax, ActiveRuns: globalMax}
+		u.mu.Unlock()
+		if still {
+			fn(ev)
+		}
+	})
+}
+
+// bucket returns the bucket for t's minute, resetting it if it held an older one.
+func (u *concurrencyUsage) bucket(t time.Time) *usageBucket {
+	minute := t.Unix() / 60
+	b := &u.buckets[minute%usageHistoryMinutes]
+	if b.minute != minute {
+		*b = usageBucket{minute: minute}
+	}
+	return b
+}
+
+// addSaturation spreads the saturated interval [from, to) over the minute
+// buckets it covers, ignoring minutes older than the kept history.
+func (u *concurrencyUsage) addSaturation(from, to time.Time) {
+	if oldest := to.Truncate(time.Minute).Add(-(usageHistoryMinutes - 1) * time.Minute); from.Before(oldest) {
+		from = oldest
+	}
+	for from.Before(to) {
+		end := from.Truncate(time.Minute).Add(time.Minute)
+		if end.After(to) {
+			end = to
+		}
+		u.bucket(from).saturated += end.Sub(from)
+		from = end
+	}
+}
+
+// fill adds high-water marks and per-window usage to stats.
+func (u *concurrencyUsage) fill(stats *ConcurrencyStats, active int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := u.now()
+	nowMinute := now.Unix() / 60
+	stats.HighWater = u.highWater
+	if !u.saturatedSince.IsZero() {
+		since := u.saturatedSince
+		stats.SaturatedSince = &since
+	}
+
+	for _, w := range usageWindows {
+		first := nowMinute - w.minutes + 1
+		win := ConcurrencyWindow{Window: w.name, HighWater: active}
+		var saturated time.Duration
+		for _, b := range u.buckets {
+			if b.minute < first || b.minute > nowMinute {
+				continue
+			}
+			win.HighWater = max(win.HighWater, b.peak)
+			saturated += b.saturated
+		}
+		if !u.saturatedSince.IsZero() {
+			start := time.Unix(first*60, 0)
+			if u.saturatedSince.After(start) {
+				start = u.saturatedSince
+			}
+			saturated += now.Sub(start)
+		}
+		win.SaturatedSeconds = saturated.Seconds()
+		stats.Windows = append(stats.Windows, win)
+	}
+}
+
+// SaturationNotifier returns a saturation alert callback that sends a message
+// through the connection connectionID.
+func SaturationNotifier(senderReg *notify.SenderRegistry, conns agents.ConnectionResolver, connectionID string) func(SaturationEvent) {
+	return func(ev SaturationEvent) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		conn, err := conns.Resolve(ctx, connectionID)
+		if err != nil {
+			slog.Warn("saturation alert: resolve connection failed", "connection", connectionID, "err", err)
+			return
+		}
+		sender, err := senderReg.Get(conn.Type)
+		if err != nil {
+			slog.Warn("saturation alert: no sender", "connection", connectionID, "err", err)
+			return
+		}
+		msg := fmt.Sprintf("Upal concurrency saturated: %d/%d runs active for %s (since %s).",
+			ev.ActiveRuns, ev.GlobalMax, ev.Duration.Round(time.Second), ev.Since.Format(time.RFC3339))
+		if err := sender.Send(ctx, conn, msg); err != nil {
+			slog.Warn("saturation alert: send failed", "connection", connectionID, "err", err)
+		}
+	}
+}
@@ -2,6 +2,11 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/soochol/upal/internal/crypto"
 	"github.com/soochol/upal/internal/repository"
@@ -11,10 +16,21 @@ import (
 type ConnectionService struct {
 	repo repository.ConnectionRepository
 	enc  *crypto.Encryptor
+
+	httpClient *http.Client
+	refreshers map[upal.ConnectionType]TokenRefresher
+	refreshMu  sync.Mutex
 }
 
 func NewConnectionService(repo repository.ConnectionRepository, enc *crypto.Encryptor) *ConnectionService {
-	return &ConnectionService{repo: repo, enc: enc}
+	return &ConnectionService{
+		repo:       repo,
+		enc:        enc,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		refreshers: map[upal.ConnectionType]TokenRefresher{
+			upal.ConnTypeReddit: refreshOAuthBasicAuth,
+		},
+	}
 }
 
 func (s *ConnectionService) Create(ctx context.Context, conn *upal.Connection) error {
@@ -32,17 +48,34 @@ func (s *ConnectionService) Get(ctx context.Context, id string) (*upal.Connectio
 }
 
 // Resolve retrieves a connection with secrets decrypted for runtime use.
+// An expired OAuth access token is refreshed (and persisted) first.
 func (s *ConnectionService) Resolve(ctx context.Context, id string) (*upal.Connection, error) {
-	conn, err := s.repo.Get(ctx, id)
+	conn, err := s.decrypted(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if err := s.decryptSecrets(conn); err != nil {
-		return nil, err
+	if tokenExpired(conn, time.Now()) {
+		if conn, err = s.refreshToken(ctx, id); err != nil {
+			return nil, fmt.Errorf("connection %q: refresh token: %w", id, err)
+		}
 	}
 	return conn, nil
 }
 
+// decrypted returns a decrypted copy of the stored connection, leaving the
+// repository's value encrypted.
+func (s *ConnectionService) decrypted(ctx context.Context, id string) (*upal.Connection, error) {
+	stored, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	conn := *stored
+	if err := s.decryptSecrets(&conn); err != nil {
+		return nil, err
+	}
+	return &conn, nil
+}
+
 func (s *ConnectionService) List(ctx context.Context) ([]upal.ConnectionSafe, error) {
 	conns, err := s.repo.List(ctx)
 	if err != nil {
@@ -56,9 +89,19 @@ func (s *ConnectionService) List(ctx context.Context) ([]upal.ConnectionSafe, er
 }
 
 func (s *ConnectionService) Update(ctx context.Context, conn *upal.Connection) error {
+	// A client that edited a ConnectionSafe sends the headers back masked;
+	// keep the stored value rather than the mask.
+	keepHeaders := conn.Extras[upal.ConnectionHeadersKey] == upal.MaskedSecret
 	if err := s.encryptSecrets(conn); err != nil {
 		return err
 	}
+	if keepHeaders {
+		stored, err := s.repo.Get(ctx, conn.ID)
+		if err != nil {
+			return err
+		}
+		conn.Extras[upal.ConnectionHeadersKey] = stored.Extras[upal.ConnectionHeadersKey]
+	}
 	return s.repo.Update(ctx, conn)
 }
 
@@ -67,15 +110,77 @@ func (s *ConnectionService) Delete(ctx context.Context, id string) error {
 }
 
 func (s *ConnectionService) encryptSecrets(conn *upal.Connection) error {
-	return s.transformSecrets(conn, s.enc.Encrypt)
+	if err := s.transformSecrets(conn, s.enc.Encrypt); err != nil {
+		return err
+	}
+	return s.encryptHeaders(conn)
 }
 
 func (s *ConnectionService) decryptSecrets(conn *upal.Connection) error {
-	return s.transformSecrets(conn, s.enc.Decrypt)
+	if err := s.transformSecrets(conn, s.enc.Decrypt); err != nil {
+		return err
+	}
+	return s.decryptHeaders(conn)
+}
+
+// encryptHeaders replaces Extras["headers"], which usually carries API keys,
+// with its JSON encoding encrypted. Extras is copied so the caller's map is
+// left untouched.
+func (s *ConnectionService) encryptHeaders(conn *upal.Connection) error {
+	h, ok := conn.Extras[upal.ConnectionHeadersKey]
+	if !ok || h == nil {
+		return nil
+	}
+	plain, ok := h.(string)
+	if !ok {
+		b, err := json.Marshal(h)
+		if err != nil {
+			return fmt.Errorf("encode headers: %w", err)
+		}
+		plain = string(b)
+	}
+	if plain == "" {
+		return nil
+	}
+	enc, err := s.enc.Encrypt(plain)
+	if err != nil {
+		return err
+	}
+	conn.Extras = copyExtras(conn.Extras)
+	conn.Extras[upal.ConnectionHeadersKey] = enc
+	return nil
+}
+
+// decryptHeaders reverses encryptHeaders, leaving Extras["headers"] as an
+// object. Headers stored before encryption (an object) are left as they are.
+func (s *ConnectionService) decryptHeaders(conn *upal.Connection) error {
+	enc, ok := conn.Extras[upal.ConnectionHeadersKey].(string)
+	if !ok || enc == "" {
+		return nil
+	}
+	plain, err := s.enc.Decrypt(enc)
+	if err != nil {
+		return fmt.Errorf("decrypt headers: %w", err)
+	}
+	var headers map[string]any
+	if err := json.Unmarshal([]byte(plain), &headers); err != nil {
+		return fmt.Errorf("headers must be a JSON object: %w", err)
+	}
+	conn.Extras = copyExtras(conn.Extras)
+	conn.Extras[upal.ConnectionHeadersKey] = headers
+	return nil
+}
+
+func copyExtras(extras map[string]any) map[string]any {
+	out := make(map[string]any, len(extras))
+	for k, v := range extras {
+		out[k] = v
+	}
+	return out
 }
 
 func (s *ConnectionService) transformSecrets(conn *upal.Connection, fn func(string) (string, error)) error {
-	for _, field := range []*string{&conn.Password, &conn.Token} {
+	for _, field := range []*string{&conn.Password, &conn.Token, &conn.RefreshToken} {
 		if *field == "" {
 			continue
 		}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// tokenExpirySkew refreshes tokens slightly early so a token does not expire
+// between Resolve and its use.
+const tokenExpirySkew = time.Minute
+
+// OAuthToken is the result of a refresh_token grant.
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // set when the provider rotates it
+	ExpiresIn    int    `json:"expires_in,omitempty"`    // seconds
+}
+
+// TokenRefresher exchanges a connection's refresh token for a new access
+// token. Providers differ in how client credentials are presented, so the
+// refresher is chosen per connection type.
+type TokenRefresher func(ctx context.Context, client *http.Client, conn *upal.Connection) (*OAuthToken, error)
+
+// RegisterRefresher overrides the refresh logic for a connection type.
+// Types without one use a standard refresh_token grant with the client
+// credentials in the form body.
+func (s *ConnectionService) RegisterRefresher(connType upal.ConnectionType, fn TokenRefresher) {
+	s.refreshers[connType] = fn
+}
+
+func tokenExpired(conn *upal.Connection, now time.Time) bool {
+	return conn.TokenURL != "" && conn.RefreshToken != "" &&
+		conn.TokenExpiresAt != nil && !now.Add(tokenExpirySkew).Before(*conn.TokenExpiresAt)
+}
+
+// refreshToken refreshes the connection's access token and stores the result
+// encrypted. Refreshes are serialized, and a token already refreshed by a
+// concurrent caller is reused rather than refreshed again.
+func (s *ConnectionService) refreshToken(ctx context.Context, id string) (*upal.Connection, error) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	conn, err := s.decrypted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !tokenExpired(conn, time.Now()) {
+		return conn, nil
+	}
+
+	refresh, ok := s.refreshers[conn.Type]
+	if !ok {
+		refresh = refreshOAuthForm
+	}
+	tok, err := refresh(ctx, s.httpClient, conn)
+	if err != nil {
+		return nil, err
+	}
+	if tok.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint returned no access_token")
+	}
+
+	conn.Token = tok.AccessToken
+	if tok.RefreshToken != "" {
+		conn.RefreshToken = tok.RefreshToken
+	}
+	conn.TokenExpiresAt = nil
+	if tok.ExpiresIn > 0 {
+		exp := time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+		conn.TokenExpiresAt = &exp
+	}
+
+	stored := *conn
+	if err := s.encryptSecrets(&stored); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, &stored); err != nil {
+		return nil, fmt.Errorf("store refreshed token: %w", err)
+	}
+	return conn, nil
+}
+
+// refreshOAuthForm performs a refresh_token grant with client_id and
+// client_secret in the form body (Google/YouTube and most providers).
+func refreshOAuthForm(ctx context.Context, client *http.Client, conn *upal.Connection) (*OAuthToken, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {conn.RefreshToken},
+	}
+	if conn.Login != "" {
+		form.Set("client_id", conn.Login)
+	}
+	if conn.Password != "" {
+		form.Set("client_secret", conn.Password)
+	}
+	return postTokenRequest(ctx, client, conn.TokenURL, form, nil)
+}
+
+// refreshOAuthBasicAuth performs a refresh_token grant with the client
+// credentials in an HTTP Basic header, as Reddit requires.
+func refreshOAuthBasicAuth(ctx context.Context, client *http.Client, conn *upal.Connection) (*OAuthToken, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {conn.RefreshToken},
+	}
+	return postTokenRequest(ctx, client, conn.TokenURL, form, func(req *http.Request) {
+		req.SetBasicAuth(conn.Login, conn.Password)
+	})
+}
+
+func postTokenRequest(ctx context.Context, client *http.Client, tokenURL string, form url.Values, decorate func(*http.Request)) (*OAuthToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if decorate != nil {
+		decorate(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var tok OAuthToken
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("decode token response: %w", err)
+	}
+	return &tok, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/crypto"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func newTestConnectionService(t *testing.T) (*ConnectionService, repository.ConnectionRepository) {
+	t.Helper()
+	enc, err := crypto.NewEncryptor([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewEncryptor: %v", err)
+	}
+	repo := repository.NewMemoryConnectionRepository()
+	return NewConnectionService(repo, enc), repo
+}
+
+func TestConnectionResolve_RefreshesExpiredToken(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-1" {
+			t.Errorf("unexpected form: %v", r.Form)
+		}
+		if r.Form.Get("client_id") != "client" || r.Form.Get("client_secret") != "shh" {
+			t.Errorf("client credentials not sent: %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"access-2","refresh_token":"refresh-2","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	svc, repo := newTestConnectionService(t)
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Hour)
+	if err := svc.Create(ctx, &upal.Connection{
+		ID: "conn_yt", Name: "yt", Type: upal.ConnTypeYouTube,
+		Login: "client", Password: "shh",
+		Token: "access-1", RefreshToken: "refresh-1",
+		TokenURL: server.URL, TokenExpiresAt: &expired,
+	}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	conn, err := svc.Resolve(ctx, "conn_yt")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if conn.Token != "access-2" || conn.RefreshToken != "refresh-2" {
+		t.Errorf("resolved tokens = %q/%q, want refreshed values", conn.Token, conn.RefreshToken)
+	}
+	if conn.TokenExpiresAt == nil || time.Until(*conn.TokenExpiresAt) < 50*time.Minute {
+		t.Errorf("TokenExpiresAt = %v, want about an hour from now", conn.TokenExpiresAt)
+	}
+
+	// The new token is persisted encrypted.
+	stored, _ := repo.Get(ctx, "conn_yt")
+	if stored.Token == "access-2" || stored.Token == "" {
+		t.Errorf("stored token = %q, want ciphertext", stored.Token)
+	}
+
+	// A second resolve uses the stored token without another refresh.
+	conn, err = svc.Resolve(ctx, "conn_yt")
+	if err != nil {
+		t.Fatalf("second Resolve: %v", err)
+	}
+	if conn.Token != "access-2" {
+		t.Errorf("second resolve token = %q, want access-2", conn.Token)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("token endpoint called %d times, want 1", n)
+	}
+}
+
+func TestConnectionResolve_RedditUsesBasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client" || pass != "shh" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"access_token":"reddit-2","expires_in":86400}`))
+	}))
+	defer server.Close()
+
+	svc, _ := newTestConnectionService(t)
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Minute)
+	svc.Create(ctx, &upal.Connection{
+		ID: "conn_rd", Type: upal.ConnTypeReddit,
+		Login: "client", Password: "shh",
+		Token: "reddit-1", RefreshToken: "r",
+		TokenURL: server.URL, TokenExpiresAt: &expired,
+	})
+
+	conn, err := svc.Resolve(ctx, "conn_rd")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if conn.Token != "reddit-2" || conn.RefreshToken != "r" {
+		t.Errorf("tokens = %q/%q, want new access token and kept refresh token", conn.Token, conn.RefreshToken)
+	}
+}
+
+func TestConnectionResolve_RefreshFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	svc, _ := newTestConnectionService(t)
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Minute)
+	svc.Create(ctx, &upal.Connection{
+		ID: "conn_bad", Type: upal.ConnTypeYouTube,
+		Token: "old", RefreshToken: "revoked",
+		TokenURL: server.URL, TokenExpiresAt: &expired,
+	})
+
+	if _, err := svc.Resolve(ctx, "conn_bad"); err == nil {
+		t.Fatal("expected refresh error")
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/notify"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestConnectionHeaders_EncryptedAtRestAndMasked(t *testing.T) {
+	svc, repo := newTestConnectionService(t)
+	ctx := context.Background()
+	conn := &upal.Connection{
+		ID: "conn_wh", Name: "wh", Type: upal.ConnTypeWebhook,
+		Extras: map[string]any{
+			"url":     "https://example.com/hook",
+			"headers": map[string]any{"X-Api-Key": "s3cret"},
+		},
+	}
+	if err := svc.Create(ctx, conn); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	stored, err := repo.Get(ctx, "conn_wh")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	raw, ok := stored.Extras["headers"].(string)
+	if !ok || strings.Contains(raw, "s3cret") {
+		t.Fatalf("stored headers not encrypted: %#v", stored.Extras["headers"])
+	}
+
+	safe := stored.Safe()
+	if safe.Extras["headers"] != upal.MaskedSecret {
+		t.Errorf("safe headers = %#v, want masked", safe.Extras["headers"])
+	}
+	if safe.Extras["url"] != "https://example.com/hook" {
+		t.Errorf("safe url = %#v, want it kept", safe.Extras["url"])
+	}
+
+	resolved, err := svc.Resolve(ctx, "conn_wh")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	headers, err := notify.WebhookHeaders(resolved)
+	if err != nil {
+		t.Fatalf("WebhookHeaders: %v", err)
+	}
+	if headers["X-Api-Key"] != "s3cret" {
+		t.Errorf("resolved headers = %v, want decrypted", headers)
+	}
+	if _, ok := stored.Extras["headers"].(string); !ok {
+		t.Error("Resolve decrypted the repository's copy")
+	}
+}
+
+func TestConnectionUpdate_MaskedHeadersKeepStoredValue(t *testing.T) {
+	svc, _ := newTestConnectionService(t)
+	ctx := context.Background()
+	if err := svc.Create(ctx, &upal.Connection{
+		ID: "conn_wh", Name: "wh", Type: upal.ConnTypeWebhook,
+		Extras: map[string]any{"headers": map[string]any{"X-Api-Key": "s3cret"}},
+	}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := svc.Update(ctx, &upal.Connection{
+		ID: "conn_wh", Name: "renamed", Type: upal.ConnTypeWebhook,
+		Extras: map[string]any{"headers": upal.MaskedSecret},
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	resolved, err := svc.Resolve(ctx, "conn_wh")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	headers, _ := resolved.Extras["headers"].(map[string]any)
+	if resolved.Name != "renamed" || headers["X-Api-Key"] != "s3cret" {
+		t.Errorf("after update: name %q headers %#v, want renamed with headers kept", resolved.Name, resolved.Extras["headers"])
+	}
+}
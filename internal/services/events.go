@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/notify"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+var _ ports.EventPublisher = (*EventBus)(nil)
+
+// EventBus delivers outbound events to the event subscriptions stored in its
+// repository. Each matching subscription is delivered on its own goroutine
+// with its own retry policy, so a slow or failing subscriber never delays
+// or repeats delivery to the others.
+type EventBus struct {
+	subs      repository.EventSubscriptionRepository
+	senderReg *notify.SenderRegistry
+	conns     agents.ConnectionResolver
+
+	wg sync.WaitGroup
+}
+
+func NewEventBus(subs repository.EventSubscriptionRepository, senderReg *notify.SenderRegistry, conns agents.ConnectionResolver) *EventBus {
+	return &EventBus{subs: subs, senderReg: senderReg, conns: conns}
+}
+
+// Publish starts delivering ev to every matching subscription and returns
+// without waiting. Subscriptions are looked up with ctx, so they are scoped
+// to the user who owns the run.
+func (b *EventBus) Publish(ctx context.Context, ev upal.OutboundEvent) {
+	subs, err := b.subs.List(ctx)
+	if err != nil {
+		slog.Warn("events: list subscriptions failed", "event", ev.Type, "err", err)
+		return
+	}
+	// Delivery outlives the request or run that produced the event.
+	ctx = context.WithoutCancel(ctx)
+	for _, sub := range subs {
+		if !sub.Matches(ev) {
+			continue
+		}
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.deliver(ctx, sub, ev)
+		}()
+	}
+}
+
+// Wait blocks until every delivery started so far has succeeded or run out
+// of retries.
+func (b *EventBus) Wait() { b.wg.Wait() }
+
+// deliver sends ev to one subscription, retrying failed sends with the
+// subscription's backoff policy.
+func (b *EventBus) deliver(ctx context.Context, sub *upal.EventSubscription, ev upal.OutboundEvent) {
+	policy := upal.DefaultRetryPolicy()
+	if sub.RetryPolicy != nil {
+		policy = *sub.RetryPolicy
+	}
+	var err error
+	for attempt := 0; attempt <= policy.MaxRetries; attempt++ {
+		if attempt > 0 {
+			sleepWithBackoff(ctx, policy, attempt-1)
+		}
+		if err = b.send(ctx, sub, ev); err == nil {
+			return
+		}
+		slog.Warn("events: delivery failed", "subscription", sub.ID, "event", ev.Type, "run_id", ev.RunID, "attempt", attempt+1, "err", err)
+	}
+	slog.Error("events: giving up on delivery", "subscription", sub.ID, "event", ev.Type, "run_id", ev.RunID, "err", err)
+}
+
+func (b *EventBus) send(ctx context.Context, sub *upal.EventSubscription, ev upal.OutboundEvent) error {
+	conn, err := b.conns.Resolve(ctx, sub.ConnectionID)
+	if err != nil {
+		return fmt.Errorf("resolve connection %q: %w", sub.ConnectionID, err)
+	}
+	sender, err := b.senderReg.Get(conn.Type)
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, conn, eventMessage(conn.Type, ev))
+}
+
+// eventMessage renders ev for a connection: webhooks receive the event as
+// JSON, chat and email connections a one-line summary.
+func eventMessage(connType upal.ConnectionType, ev upal.OutboundEvent) string {
+	if connType == upal.ConnTypeWebhook {
+		body, _ := json.Marshal(ev)
+		return string(body)
+	}
+	msg := fmt.Sprintf("Workflow %q run %s finished with status %s.", ev.WorkflowName, ev.RunID, ev.Status)
+	if ev.Error != "" {
+		msg += " Error: " + ev.Error
+	}
+	return msg
+}
+
+// runEvent builds the outbound event for a run that reached a terminal
+// status, or reports false for statuses that publish no event.
+func runEvent(record *upal.RunRecord) (upal.OutboundEvent, bool) {
+	var eventType string
+	switch record.Status {
+	case upal.RunStatusSuccess, upal.RunStatusCompletedWithErrors:
+		eventType = upal.EventTypeRunCompleted
+	case upal.RunStatusFailed, upal.RunStatusContractViolation:
+		eventType = upal.EventTypeRunFailed
+	default:
+		return upal.OutboundEvent{}, false
+	}
+	ev := upal.OutboundEvent{
+		ID:           upal.GenerateID("evt"),
+		Type:         eventType,
+		WorkflowName: record.WorkflowName,
+		RunID:        record.ID,
+		Status:       record.Status,
+		Outputs:      maps.Clone(record.Outputs),
+	}
+	if record.Error != nil {
+		ev.Error = *record.Error
+	}
+	if record.CompletedAt != nil {
+		ev.OccurredAt = *record.CompletedAt
+	}
+	return ev, true
+}
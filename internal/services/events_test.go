@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/notify"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// recordingSender records messages per connection and fails the first
+// failures[connID] sends to that connection.
+type recordingSender struct {
+	mu       sync.Mutex
+	failures map[string]int
+	attempts map[string]int
+	received map[string][]string
+}
+
+func (s *recordingSender) Type() upal.ConnectionType { return upal.ConnTypeWebhook }
+
+func (s *recordingSender) Send(_ context.Context, conn *upal.Connection, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts[conn.ID]++
+	if s.attempts[conn.ID] <= s.failures[conn.ID] {
+		return errors.New("webhook returned 503")
+	}
+	s.received[conn.ID] = append(s.received[conn.ID], message)
+	return nil
+}
+
+func newEventBusFixture(t *testing.T, failures map[string]int, subs ...*upal.EventSubscription) (*EventBus, *recordingSender) {
+	t.Helper()
+	sender := &recordingSender{failures: failures, attempts: map[string]int{}, received: map[string][]string{}}
+	senderReg := notify.NewSenderRegistry()
+	senderReg.Register(sender)
+	conns := staticConnResolver{}
+	repo := repository.NewMemoryEventSubscriptionRepository()
+	for _, sub := range subs {
+		conns[sub.ConnectionID] = &upal.Connection{ID: sub.ConnectionID, Type: upal.ConnTypeWebhook}
+		if err := repo.Create(context.Background(), sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewEventBus(repo, senderReg, conns), sender
+}
+
+func fastRetry(max int) *upal.RetryPolicy {
+	return &upal.RetryPolicy{MaxRetries: max, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, BackoffFactor: 1}
+}
+
+func TestEventBus_RunCompletionFansOutWithIndependentRetry(t *testing.T) {
+	bus, sender := newEventBusFixture(t, map[string]int{"flaky": 2},
+		&upal.EventSubscription{ID: "s1", ConnectionID: "crm", Enabled: true, RetryPolicy: fastRetry(3)},
+		&upal.EventSubscription{ID: "s2", ConnectionID: "flaky", Enabled: true, RetryPolicy: fastRetry(3)},
+		&upal.EventSubscription{ID: "s3", ConnectionID: "audit", Enabled: true, Events: []string{upal.EventTypeRunCompleted}},
+	)
+	runRepo := repository.NewMemoryRunRepository()
+	history := NewRunHistoryService(runRepo)
+	history.SetEventPublisher(bus)
+
+	ctx := context.Background()
+	record, err := history.StartRun(ctx, "digest", "manual", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := history.CompleteRun(ctx, record.ID, map[string]any{"summary": "ok"}); err != nil {
+		t.Fatal(err)
+	}
+	bus.Wait()
+
+	for _, conn := range []string{"crm", "flaky", "audit"} {
+		msgs := sender.received[conn]
+		if len(msgs) != 1 {
+			t.Fatalf("%s received %d events, want 1", conn, len(msgs))
+		}
+		var ev upal.OutboundEvent
+		if err := json.Unmarshal([]byte(msgs[0]), &ev); err != nil {
+			t.Fatalf("%s: decode event: %v", conn, err)
+		}
+		if ev.Type != upal.EventTypeRunCompleted || ev.RunID != record.ID || ev.WorkflowName != "digest" || ev.Outputs["summary"] != "ok" {
+			t.Errorf("%s: unexpected event %+v", conn, ev)
+		}
+	}
+	// The flaky subscriber retried on its own; the others were sent once.
+	if got := sender.attempts["flaky"]; got != 3 {
+		t.Errorf("flaky attempts = %d, want 3", got)
+	}
+	if sender.attempts["crm"] != 1 || sender.attempts["audit"] != 1 {
+		t.Errorf("healthy subscribers were retried: %v", sender.attempts)
+	}
+}
+
+func TestEventBus_GivesUpAfterRetries(t *testing.T) {
+	bus, sender := newEventBusFixture(t, map[string]int{"down": 100},
+		&upal.EventSubscription{ID: "s1", ConnectionID: "down", Enabled: true, RetryPolicy: fastRetry(2)},
+		&upal.EventSubscription{ID: "s2", ConnectionID: "up", Enabled: true, RetryPolicy: fastRetry(2)},
+	)
+	bus.Publish(context.Background(), upal.OutboundEvent{Type: upal.EventTypeRunFailed, RunID: "r1"})
+	bus.Wait()
+
+	if got := sender.attempts["down"]; got != 3 {
+		t.Errorf("down attempts = %d, want 3", got)
+	}
+	if len(sender.received["up"]) != 1 {
+		t.Errorf("up received %d events, want 1", len(sender.received["up"]))
+	}
+}
+
+// ownerOnlySubs lists subscriptions only for owner, like the persistent
+// repository scopes them to the context's user.
+type ownerOnlySubs struct {
+	repository.EventSubscriptionRepository
+	owner string
+}
+
+func (r ownerOnlySubs) List(ctx context.Context) ([]*upal.EventSubscription, error) {
+	if upal.UserIDFromContext(ctx) != r.owner {
+		return nil, nil
+	}
+	return r.EventSubscriptionRepository.List(ctx)
+}
+
+func TestEventBus_PublishesToRunOwnersSubscriptions(t *testing.T) {
+	sender := &recordingSender{attempts: map[string]int{}, received: map[string][]string{}}
+	senderReg := notify.NewSenderRegistry()
+	senderReg.Register(sender)
+	repo := repository.NewMemoryEventSubscriptionRepository()
+	if err := repo.Create(context.Background(), &upal.EventSubscription{ID: "s1", ConnectionID: "crm", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	conns := staticConnResolver{"crm": {ID: "crm", Type: upal.ConnTypeWebhook}}
+	bus := NewEventBus(ownerOnlySubs{repo, "alice"}, senderReg, conns)
+	history := NewRunHistoryService(repository.NewMemoryRunRepository())
+	history.SetEventPublisher(bus)
+
+	record, err := history.StartRun(upal.WithUserID(context.Background(), "alice"), "digest", "manual", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Runs finish on a background context that no longer carries the user.
+	if err := history.FailRun(context.Background(), record.ID, "boom"); err != nil {
+		t.Fatal(err)
+	}
+	bus.Wait()
+
+	if len(sender.received["crm"]) != 1 {
+		t.Fatalf("alice's subscription received %d events, want 1", len(sender.received["crm"]))
+	}
+}
+
+func TestEventSubscription_Matches(t *testing.T) {
+	ev := upal.OutboundEvent{Type: upal.EventTypeRunFailed, WorkflowName: "digest"}
+	cases := []struct {
+		name string
+		sub  upal.EventSubscription
+		want bool
+	}{
+		{"all events", upal.EventSubscription{Enabled: true}, true},
+		{"disabled", upal.EventSubscription{}, false},
+		{"other workflow", upal.EventSubscription{Enabled: true, WorkflowName: "news"}, false},
+		{"same workflow", upal.EventSubscription{Enabled: true, WorkflowName: "digest"}, true},
+		{"completions only", upal.EventSubscription{Enabled: true, Events: []string{upal.EventTypeRunCompleted}}, false},
+		{"failures", upal.EventSubscription{Enabled: true, Events: []string{upal.EventTypeRunFailed}}, true},
+	}
+	for _, c := range cases {
+		if got := c.sub.Matches(ev); got != c.want {
+			t.Errorf("%s: Matches = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRunEvent_StatusMapping(t *testing.T) {
+	for status, want := range map[upal.RunStatus]string{
+		upal.RunStatusSuccess:             upal.EventTypeRunCompleted,
+		upal.RunStatusCompletedWithErrors: upal.EventTypeRunCompleted,
+		upal.RunStatusFailed:              upal.EventTypeRunFailed,
+		upal.RunStatusContractViolation:   upal.EventTypeRunFailed,
+		upal.RunStatusCancelled:           "",
+		upal.RunStatusRunning:             "",
+	} {
+		ev, ok := runEvent(&upal.RunRecord{ID: "r1", Status: status})
+		if got := ev.Type; ok != (want != "") || got != want {
+			t.Errorf("%s: event %q (ok=%v), want %q", status, got, ok, want)
+		}
+	}
+}
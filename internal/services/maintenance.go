@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+var _ ports.MaintenanceGate = (*MaintenanceService)(nil)
+
+// MaintenanceService owns the server-wide maintenance flag. The flag is
+// cached so hot paths (run starts, schedule ticks) never hit storage.
+type MaintenanceService struct {
+	repo    repository.MaintenanceRepository
+	enabled atomic.Bool
+}
+
+// NewMaintenanceService loads the persisted flag so maintenance mode
+// survives a restart.
+func NewMaintenanceService(ctx context.Context, repo repository.MaintenanceRepository) *MaintenanceService {
+	s := &MaintenanceService{repo: repo}
+	if st, err := repo.Get(ctx); err != nil {
+		slog.Warn("maintenance: failed to load state", "err", err)
+	} else {
+		s.enabled.Store(st.Enabled)
+	}
+	return s
+}
+
+// InMaintenance reports whether new runs must be rejected.
+func (s *MaintenanceService) InMaintenance() bool {
+	return s.enabled.Load()
+}
+
+// State returns the stored maintenance state.
+func (s *MaintenanceService) State(ctx context.Context) (*upal.MaintenanceState, error) {
+	return s.repo.Get(ctx)
+}
+
+// Set turns maintenance mode on or off and persists the change.
+func (s *MaintenanceService) Set(ctx context.Context, enabled bool, reason string) (*upal.MaintenanceState, error) {
+	st := &upal.MaintenanceState{
+		Enabled:   enabled,
+		Reason:    reason,
+		UpdatedBy: upal.UserIDFromContext(ctx),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.repo.Set(ctx, st); err != nil {
+		return nil, err
+	}
+	s.enabled.Store(enabled)
+	slog.InfoContext(ctx, "maintenance mode changed", "enabled", enabled, "reason", reason)
+	return st, nil
+}
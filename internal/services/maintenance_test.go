@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+)
+
+func TestMaintenanceService_StateSurvivesReload(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryMaintenanceRepository()
+
+	svc := NewMaintenanceService(ctx, repo)
+	if svc.InMaintenance() {
+		t.Fatal("maintenance should default to off")
+	}
+	if _, err := svc.Set(ctx, true, "upgrade"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	reloaded := NewMaintenanceService(ctx, repo)
+	if !reloaded.InMaintenance() {
+		t.Fatal("expected maintenance mode to be restored from the repository")
+	}
+	st, _ := reloaded.State(ctx)
+	if st.Reason != "upgrade" {
+		t.Errorf("reason = %q, want upgrade", st.Reason)
+	}
+}
@@ -0,0 +1,289 @@
+// internal/services/pipeline_graph.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// usesStageDependencies reports whether any stage declares depends_on, which
+// switches the runner from list order to dependency order.
+func usesStageDependencies(p *upal.Pipeline) bool {
+	for _, stage := range p.Stages {
+		if len(stage.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// stageDependencyCycle returns the stage IDs forming a depends_on cycle, or
+// nil. References to unknown stages are ignored.
+func stageDependencyCycle(stages []upal.Stage) []string {
+	deps := make(map[string][]string, len(stages))
+	for _, stage := range stages {
+		deps[stage.ID] = stage.DependsOn
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(stages))
+	var path []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case visiting:
+			for i, p := range path {
+				if p == id {
+					return append(append([]string{}, path[i:]...), id)
+				}
+			}
+		case done:
+			return nil
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range deps[id] {
+			if _, known := deps[dep]; !known {
+				continue
+			}
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+	for _, stage := range stages {
+		if cycle := visit(stage.ID); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// stageOutcome is the result of one stage run by executeGraph.
+type stageOutcome struct {
+	stage    upal.Stage
+	result   *upal.StageResult
+	attempts int
+	err      error
+}
+
+// executeGraph runs a pipeline that declares depends_on. A stage starts once
+// every stage it depends on has completed or been skipped, so stages without
+// depends_on start immediately and independent branches run concurrently; a
+// stage depending on several others joins them. Each stage receives its
+// completed dependencies' outputs merged in depends_on order (later ones win
+// on key clashes); stages without dependencies receive the pipeline inputs.
+// Workflow stages hold a concurrency limiter slot while they run. Once a
+// stage fails or waits for approval no further stages start, and the run
+// ends when those already running finish.
+func (r *PipelineRunner) executeGraph(ctx context.Context, pipeline *upal.Pipeline, run *upal.PipelineRun, inputs map[string]any) error {
+	// mu guards run: stage goroutines record retries on it while the
+	// scheduling loop records starts and outcomes.
+	var mu sync.Mutex
+	finished := make(map[string]bool, len(pipeline.Stages))
+	for id, res := range run.StageResults {
+		if res.Status == upal.StageStatusCompleted || res.Status == upal.StageStatusSkipped {
+			finished[id] = true
+		}
+	}
+	started := make(map[string]bool, len(pipeline.Stages))
+	outcomes := make(chan stageOutcome)
+	inFlight := 0
+	var failErr error
+	var failStage string
+	waiting := false
+
+	fail := func(stageResult *upal.StageResult, err error) {
+		now := time.Now()
+		stageResult.Status = upal.StageStatusFailed
+		stageResult.Error = err.Error()
+		stageResult.CompletedAt = &now
+		run.StageResults[stageResult.StageID] = stageResult
+		if failErr == nil {
+			failErr, failStage = err, stageResult.StageID
+		}
+	}
+
+	// launch starts every stage whose dependencies have finished. Skipped
+	// stages finish immediately and may unblock others, so it repeats until
+	// nothing changes.
+	launch := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for progressed := true; progressed && failErr == nil && !waiting; {
+			progressed = false
+			for _, stage := range pipeline.Stages {
+				if started[stage.ID] || finished[stage.ID] || !dependenciesFinished(stage, finished) {
+					continue
+				}
+				started[stage.ID] = true
+				progressed = true
+				now := time.Now()
+
+				executor, ok := r.executors[stage.Type]
+				if !ok {
+					fail(&upal.StageResult{StageID: stage.ID, StartedAt: now}, fmt.Errorf("no executor registered for stage type %q", stage.Type))
+					break
+				}
+				prevResult := dependencyResult(stage, run, inputs)
+				if stage.Config.Condition != "" {
+					ok, err := evaluateStageCondition(stage.Config.Condition, run, prevResult)
+					if err != nil {
+						fail(&upal.StageResult{StageID: stage.ID, StartedAt: now}, fmt.Errorf("condition: %w", err))
+						break
+					}
+					if !ok {
+						run.StageResults[stage.ID] = &upal.StageResult{
+							StageID:     stage.ID,
+							Status:      upal.StageStatusSkipped,
+							StartedAt:   now,
+							CompletedAt: &now,
+						}
+						finished[stage.ID] = true
+						r.runRepo.Update(ctx, run)
+						continue
+					}
+				}
+
+				run.CurrentStage = stage.ID
+				stageResult := &upal.StageResult{
+					StageID:   stage.ID,
+					Status:    upal.StageStatusRunning,
+					StartedAt: now,
+				}
+				run.StageResults[stage.ID] = stageResult
+				r.runRepo.Update(ctx, run)
+
+				stageCtx := upal.WithStageOutputs(ctx, completedStageOutputs(run))
+				inFlight++
+				go func() {
+					result, attempts, err := r.runGraphStage(stageCtx, executor, pipeline, stage, prevResult, func(attempts int, err error) {
+						mu.Lock()
+						defer mu.Unlock()
+						stageResult.Attempts = attempts
+						stageResult.Error = err.Error()
+						r.runRepo.Update(ctx, run)
+					})
+					outcomes <- stageOutcome{stage: stage, result: result, attempts: attempts, err: err}
+				}()
+			}
+		}
+	}
+
+	launch()
+	for inFlight > 0 {
+		o := <-outcomes
+		inFlight--
+
+		mu.Lock()
+		switch {
+		case o.err != nil:
+			stageResult := run.StageResults[o.stage.ID]
+			stageResult.Attempts = o.attempts
+			fail(stageResult, o.err)
+		case o.result.Status == upal.StageStatusWaiting:
+			o.result.Attempts = o.attempts
+			run.StageResults[o.stage.ID] = o.result
+			run.CurrentStage = o.stage.ID
+			waiting = true
+		default:
+			now := time.Now()
+			o.result.Attempts = o.attempts
+			o.result.CompletedAt = &now
+			run.StageResults[o.stage.ID] = o.result
+			finished[o.stage.ID] = true
+		}
+		r.runRepo.Update(ctx, run)
+		mu.Unlock()
+
+		launch()
+	}
+
+	now := time.Now()
+	if failErr == nil && !waiting {
+		for _, stage := range pipeline.Stages {
+			if !finished[stage.ID] {
+				failErr, failStage = fmt.Errorf("depends on unknown or cyclic stages %v", stage.DependsOn), stage.ID
+				break
+			}
+		}
+	}
+	switch {
+	case failErr != nil:
+		run.Status = upal.PipelineRunFailed
+		run.CompletedAt = &now
+		r.runRepo.Update(ctx, run)
+		return fmt.Errorf("stage %q failed: %w", failStage, failErr)
+	case waiting:
+		run.Status = upal.PipelineRunWaiting
+		r.runRepo.Update(ctx, run)
+		return nil
+	}
+	run.Status = upal.PipelineRunCompleted
+	run.CompletedAt = &now
+	r.runRepo.Update(ctx, run)
+	return nil
+}
+
+// resumeGraph continues a dependency-ordered run after approval: stages
+// waiting for approval count as completed, and the rest of the graph runs.
+func (r *PipelineRunner) resumeGraph(ctx context.Context, pipeline *upal.Pipeline, run *upal.PipelineRun) error {
+	now := time.Now()
+	for _, res := range run.StageResults {
+		if res.Status == upal.StageStatusWaiting {
+			res.Status = upal.StageStatusCompleted
+			res.CompletedAt = &now
+		}
+	}
+	return r.executeGraph(ctx, pipeline, run, nil)
+}
+
+// runGraphStage executes one stage of a dependency-ordered run, holding a
+// concurrency slot for workflow stages.
+func (r *PipelineRunner) runGraphStage(ctx context.Context, executor StageExecutor, pipeline *upal.Pipeline, stage upal.Stage, prevResult *upal.StageResult, onRetry func(attempts int, err error)) (*upal.StageResult, int, error) {
+	if r.limiter != nil && stage.Type == "workflow" && stage.Config.WorkflowName != "" {
+		if err := r.limiter.Acquire(ctx, stage.Config.WorkflowName); err != nil {
+			return nil, 0, fmt.Errorf("acquire concurrency slot: %w", err)
+		}
+		defer r.limiter.Release(stage.Config.WorkflowName)
+	}
+	return r.executeStage(ctx, executor, pipeline, stage, prevResult, onRetry)
+}
+
+func dependenciesFinished(stage upal.Stage, finished map[string]bool) bool {
+	for _, dep := range stage.DependsOn {
+		if !finished[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// dependencyResult builds the input a stage receives: the pipeline inputs
+// for a stage without dependencies, otherwise its completed dependencies'
+// outputs merged in depends_on order.
+func dependencyResult(stage upal.Stage, run *upal.PipelineRun, inputs map[string]any) *upal.StageResult {
+	if len(stage.DependsOn) == 0 {
+		if inputs == nil {
+			return nil
+		}
+		return &upal.StageResult{Output: inputs, Status: upal.StageStatusCompleted}
+	}
+	merged := make(map[string]any)
+	for _, dep := range stage.DependsOn {
+		if res, ok := run.StageResults[dep]; ok && res.Status == upal.StageStatusCompleted {
+			maps.Copy(merged, res.Output)
+		}
+	}
+	return &upal.StageResult{Output: merged, Status: upal.StageStatusCompleted}
+}
@@ -4,8 +4,11 @@ package services
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/expr-lang/expr"
+	"github.com/soochol/upal/internal/agents"
 	"github.com/soochol/upal/internal/repository"
 	"github.com/soochol/upal/internal/upal"
 	"github.com/soochol/upal/internal/upal/ports"
@@ -21,6 +24,7 @@ type StageExecutor interface {
 type PipelineRunner struct {
 	executors map[string]StageExecutor
 	runRepo   repository.PipelineRunRepository
+	limiter   ports.ConcurrencyControl
 }
 
 func NewPipelineRunner(runRepo repository.PipelineRunRepository) *PipelineRunner {
@@ -34,6 +38,10 @@ func (r *PipelineRunner) RegisterExecutor(exec StageExecutor) {
 	r.executors[exec.Type()] = exec
 }
 
+// SetConcurrencyLimiter bounds workflow stages that run in parallel branches
+// by the workflow concurrency limits.
+func (r *PipelineRunner) SetConcurrencyLimiter(l ports.ConcurrencyControl) { r.limiter = l }
+
 func (r *PipelineRunner) Start(ctx context.Context, pipeline *upal.Pipeline, inputs map[string]any) (*upal.PipelineRun, error) {
 	run := &upal.PipelineRun{
 		ID:           upal.GenerateID("prun"),
@@ -62,10 +70,16 @@ func (r *PipelineRunner) Resume(ctx context.Context, pipeline *upal.Pipeline, ru
 	if currentIdx == -1 {
 		return fmt.Errorf("current stage %q not found in pipeline %q", run.CurrentStage, pipeline.ID)
 	}
+	if usesStageDependencies(pipeline) {
+		return r.resumeGraph(ctx, pipeline, run)
+	}
 	return r.executeFrom(ctx, pipeline, run, currentIdx+1, nil)
 }
 
 func (r *PipelineRunner) executeFrom(ctx context.Context, pipeline *upal.Pipeline, run *upal.PipelineRun, startIdx int, inputs map[string]any) error {
+	if usesStageDependencies(pipeline) {
+		return r.executeGraph(ctx, pipeline, run, inputs)
+	}
 	var prevResult *upal.StageResult
 	if inputs != nil {
 		prevResult = &upal.StageResult{Output: inputs, Status: upal.StageStatusCompleted}
@@ -89,6 +103,35 @@ func (r *PipelineRunner) executeFrom(ctx context.Context, pipeline *upal.Pipelin
 			return fmt.Errorf("no executor registered for stage type %q", stage.Type)
 		}
 
+		if stage.Config.Condition != "" {
+			ok, err := evaluateStageCondition(stage.Config.Condition, run, prevResult)
+			if err != nil {
+				now := time.Now()
+				run.StageResults[stage.ID] = &upal.StageResult{
+					StageID:     stage.ID,
+					Status:      upal.StageStatusFailed,
+					Error:       err.Error(),
+					StartedAt:   now,
+					CompletedAt: &now,
+				}
+				run.Status = upal.PipelineRunFailed
+				run.CompletedAt = &now
+				r.runRepo.Update(ctx, run)
+				return fmt.Errorf("stage %q condition: %w", stage.ID, err)
+			}
+			if !ok {
+				now := time.Now()
+				run.StageResults[stage.ID] = &upal.StageResult{
+					StageID:     stage.ID,
+					Status:      upal.StageStatusSkipped,
+					StartedAt:   now,
+					CompletedAt: &now,
+				}
+				r.runRepo.Update(ctx, run)
+				continue
+			}
+		}
+
 		run.CurrentStage = stage.ID
 		stageResult := &upal.StageResult{
 			StageID:   stage.ID,
@@ -98,11 +141,16 @@ func (r *PipelineRunner) executeFrom(ctx context.Context, pipeline *upal.Pipelin
 		run.StageResults[stage.ID] = stageResult
 		r.runRepo.Update(ctx, run)
 
-		result, err := executor.Execute(ctx, pipeline, stage, prevResult)
+		result, attempts, err := r.executeStage(upal.WithStageOutputs(ctx, completedStageOutputs(run)), executor, pipeline, stage, prevResult, func(attempts int, err error) {
+			stageResult.Attempts = attempts
+			stageResult.Error = err.Error()
+			r.runRepo.Update(ctx, run)
+		})
 		if err != nil {
 			now := time.Now()
 			stageResult.Status = upal.StageStatusFailed
 			stageResult.Error = err.Error()
+			stageResult.Attempts = attempts
 			stageResult.CompletedAt = &now
 			run.Status = upal.PipelineRunFailed
 			run.CompletedAt = &now
@@ -110,6 +158,7 @@ func (r *PipelineRunner) executeFrom(ctx context.Context, pipeline *upal.Pipelin
 			return fmt.Errorf("stage %q failed: %w", stage.ID, err)
 		}
 
+		result.Attempts = attempts
 		if result.Status == upal.StageStatusWaiting {
 			run.Status = upal.PipelineRunWaiting
 			run.StageResults[stage.ID] = result
@@ -131,3 +180,63 @@ func (r *PipelineRunner) executeFrom(ctx context.Context, pipeline *upal.Pipelin
 	r.runRepo.Update(ctx, run)
 	return nil
 }
+
+// executeStage runs stage, retrying failures with backoff as its retry
+// policy allows; onRetry records each failed attempt before the next. It
+// returns the result and how many executions were made.
+func (r *PipelineRunner) executeStage(ctx context.Context, executor StageExecutor, pipeline *upal.Pipeline, stage upal.Stage, prevResult *upal.StageResult, onRetry func(attempts int, err error)) (*upal.StageResult, int, error) {
+	maxRetries := 0
+	if stage.Config.Retry != nil {
+		maxRetries = stage.Config.Retry.MaxRetries
+	}
+	for attempt := 0; ; attempt++ {
+		result, err := executor.Execute(ctx, pipeline, stage, prevResult)
+		if err == nil {
+			return result, attempt + 1, nil
+		}
+		if attempt >= maxRetries || ctx.Err() != nil {
+			return nil, attempt + 1, err
+		}
+		slog.Warn("pipeline stage failed, retrying", "pipeline", pipeline.ID, "stage", stage.ID, "attempt", attempt+1, "err", err)
+		onRetry(attempt+1, err)
+		sleepWithBackoff(ctx, *stage.Config.Retry, attempt)
+	}
+}
+
+// completedStageOutputs snapshots the output of every completed stage of run
+// by stage ID, for inputs_from references.
+func completedStageOutputs(run *upal.PipelineRun) map[string]map[string]any {
+	outputs := make(map[string]map[string]any, len(run.StageResults))
+	for id, res := range run.StageResults {
+		if res.Status == upal.StageStatusCompleted {
+			outputs[id] = res.Output
+		}
+	}
+	return outputs
+}
+
+// evaluateStageCondition evaluates a stage's skip-if condition. The previous
+// stage's output keys are exposed as top-level variables, alongside "prev"
+// (the same map) and "stages" (every completed stage's output by stage ID).
+// The previous output's keys vary from run to run, so a name it lacks
+// evaluates to nil rather than failing to compile.
+func evaluateStageCondition(condition string, run *upal.PipelineRun, prevResult *upal.StageResult) (bool, error) {
+	prev := map[string]any{}
+	if prevResult != nil && prevResult.Output != nil {
+		prev = prevResult.Output
+	}
+	stages := make(map[string]any, len(run.StageResults))
+	for id, res := range run.StageResults {
+		if res.Status == upal.StageStatusCompleted {
+			stages[id] = res.Output
+		}
+	}
+
+	env := make(map[string]any, len(prev)+2)
+	for k, v := range prev {
+		env[k] = v
+	}
+	env["prev"] = prev
+	env["stages"] = stages
+	return agents.EvaluateExpression(condition, env, expr.AllowUndefinedVariables())
+}
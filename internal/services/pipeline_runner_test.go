@@ -3,7 +3,13 @@ package services
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/soochol/upal/internal/repository"
 	"github.com/soochol/upal/internal/upal"
@@ -201,3 +207,291 @@ func TestPipelineRunner_Resume_CurrentStageNotFound(t *testing.T) {
 		t.Fatal("expected error for nonexistent current stage")
 	}
 }
+
+func TestPipelineRunner_ConditionSkipsStage(t *testing.T) {
+	runRepo := repository.NewMemoryPipelineRunRepository()
+	wfExec := &mockStageExecutor{stageType: "workflow", output: map[string]any{"publish": false}}
+	notifyExec := &mockStageExecutor{stageType: "notification", output: map[string]any{"sent": true}}
+
+	runner := NewPipelineRunner(runRepo)
+	runner.RegisterExecutor(wfExec)
+	runner.RegisterExecutor(notifyExec)
+
+	pipeline := &upal.Pipeline{
+		ID: "pipe-cond",
+		Stages: []upal.Stage{
+			{ID: "s1", Type: "workflow"},
+			{ID: "s2", Type: "notification", Config: upal.StageConfig{Condition: "publish == true"}},
+		},
+	}
+
+	run, err := runner.Start(context.Background(), pipeline, nil)
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if run.Status != upal.PipelineRunCompleted {
+		t.Errorf("expected status 'completed', got %q", run.Status)
+	}
+	if len(notifyExec.calls) != 0 {
+		t.Errorf("expected notification stage to be skipped, got calls %v", notifyExec.calls)
+	}
+	if got := run.StageResults["s2"].Status; got != upal.StageStatusSkipped {
+		t.Errorf("expected s2 status 'skipped', got %q", got)
+	}
+}
+
+func TestPipelineRunner_ConditionRunsStage(t *testing.T) {
+	runRepo := repository.NewMemoryPipelineRunRepository()
+	wfExec := &mockStageExecutor{stageType: "workflow", output: map[string]any{"publish": true}}
+	notifyExec := &mockStageExecutor{stageType: "notification", output: map[string]any{"sent": true}}
+
+	runner := NewPipelineRunner(runRepo)
+	runner.RegisterExecutor(wfExec)
+	runner.RegisterExecutor(notifyExec)
+
+	pipeline := &upal.Pipeline{
+		ID: "pipe-cond",
+		Stages: []upal.Stage{
+			{ID: "s1", Type: "workflow"},
+			{ID: "s2", Type: "notification", Config: upal.StageConfig{Condition: `stages["s1"].publish`}},
+		},
+	}
+
+	run, err := runner.Start(context.Background(), pipeline, nil)
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if len(notifyExec.calls) != 1 || notifyExec.calls[0] != "s2" {
+		t.Errorf("expected notification executor called with s2, got %v", notifyExec.calls)
+	}
+	if got := run.StageResults["s2"].Status; got != upal.StageStatusCompleted {
+		t.Errorf("expected s2 status 'completed', got %q", got)
+	}
+}
+
+func TestPipelineRunner_ConditionOnMissingKeySkipsStage(t *testing.T) {
+	runRepo := repository.NewMemoryPipelineRunRepository()
+	wfExec := &mockStageExecutor{stageType: "workflow", output: map[string]any{"sent": true}}
+	notifyExec := &mockStageExecutor{stageType: "notification", output: map[string]any{"sent": true}}
+
+	runner := NewPipelineRunner(runRepo)
+	runner.RegisterExecutor(wfExec)
+	runner.RegisterExecutor(notifyExec)
+
+	pipeline := &upal.Pipeline{
+		ID: "pipe-cond",
+		Stages: []upal.Stage{
+			{ID: "s1", Type: "workflow"},
+			{ID: "s2", Type: "notification", Config: upal.StageConfig{Condition: "publish == true"}},
+		},
+	}
+
+	run, err := runner.Start(context.Background(), pipeline, nil)
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if run.Status != upal.PipelineRunCompleted {
+		t.Errorf("expected status 'completed', got %q", run.Status)
+	}
+	if got := run.StageResults["s2"].Status; got != upal.StageStatusSkipped {
+		t.Errorf("expected s2 status 'skipped', got %q", got)
+	}
+}
+
+// flakyStageExecutor fails its first failures calls, then succeeds.
+type flakyStageExecutor struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyStageExecutor) Type() string { return "notification" }
+func (f *flakyStageExecutor) Execute(_ context.Context, _ *upal.Pipeline, stage upal.Stage, _ *upal.StageResult) (*upal.StageResult, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errors.New("webhook returned 503")
+	}
+	return &upal.StageResult{StageID: stage.ID, Status: upal.StageStatusCompleted}, nil
+}
+
+func TestPipelineRunner_StageRetry(t *testing.T) {
+	retry := &upal.RetryPolicy{MaxRetries: 2, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, BackoffFactor: 1}
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		flaky := &flakyStageExecutor{failures: 2}
+		wfExec := &mockStageExecutor{stageType: "workflow"}
+		runner := NewPipelineRunner(repository.NewMemoryPipelineRunRepository())
+		runner.RegisterExecutor(flaky)
+		runner.RegisterExecutor(wfExec)
+
+		pipeline := &upal.Pipeline{ID: "pipe-retry", Stages: []upal.Stage{
+			{ID: "notify", Type: "notification", Config: upal.StageConfig{Retry: retry}},
+			{ID: "next", Type: "workflow"},
+		}}
+		run, err := runner.Start(context.Background(), pipeline, nil)
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		if run.Status != upal.PipelineRunCompleted {
+			t.Fatalf("status = %q, want completed", run.Status)
+		}
+		if got := run.StageResults["notify"].Attempts; got != 3 {
+			t.Errorf("notify attempts = %d, want 3", got)
+		}
+		if got := run.StageResults["next"].Attempts; got != 1 {
+			t.Errorf("next attempts = %d, want 1", got)
+		}
+		if len(wfExec.calls) != 1 {
+			t.Errorf("later stage calls = %v, want one", wfExec.calls)
+		}
+	})
+
+	t.Run("fails when retries exhausted", func(t *testing.T) {
+		flaky := &flakyStageExecutor{failures: 5}
+		runner := NewPipelineRunner(repository.NewMemoryPipelineRunRepository())
+		runner.RegisterExecutor(flaky)
+
+		pipeline := &upal.Pipeline{ID: "pipe-retry", Stages: []upal.Stage{
+			{ID: "notify", Type: "notification", Config: upal.StageConfig{Retry: retry}},
+		}}
+		run, err := runner.Start(context.Background(), pipeline, nil)
+		if err == nil || run.Status != upal.PipelineRunFailed {
+			t.Fatalf("err = %v, status = %q, want failed run", err, run.Status)
+		}
+		if flaky.calls != 3 || run.StageResults["notify"].Attempts != 3 {
+			t.Errorf("calls = %d, attempts = %d, want 3", flaky.calls, run.StageResults["notify"].Attempts)
+		}
+	})
+}
+
+// barrierStageExecutor completes a stage only once `want` stages are inside
+// Execute at the same time, so it fails unless they run concurrently.
+type barrierStageExecutor struct {
+	want    int32
+	inside  atomic.Int32
+	maxSeen atomic.Int32
+	release chan struct{}
+	once    sync.Once
+}
+
+func (b *barrierStageExecutor) Type() string { return "workflow" }
+func (b *barrierStageExecutor) Execute(ctx context.Context, _ *upal.Pipeline, stage upal.Stage, _ *upal.StageResult) (*upal.StageResult, error) {
+	n := b.inside.Add(1)
+	defer b.inside.Add(-1)
+	for {
+		if seen := b.maxSeen.Load(); n <= seen || b.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	if n >= b.want {
+		b.once.Do(func() { close(b.release) })
+	}
+	select {
+	case <-b.release:
+	case <-time.After(2 * time.Second):
+		return nil, errors.New("timed out waiting for the parallel stage")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &upal.StageResult{StageID: stage.ID, Status: upal.StageStatusCompleted, Output: map[string]any{stage.ID: "done"}}, nil
+}
+
+// joinStageExecutor records the input each call receives.
+type joinStageExecutor struct {
+	mu     sync.Mutex
+	inputs map[string]map[string]any
+}
+
+func (j *joinStageExecutor) Type() string { return "transform" }
+func (j *joinStageExecutor) Execute(_ context.Context, _ *upal.Pipeline, stage upal.Stage, prev *upal.StageResult) (*upal.StageResult, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.inputs == nil {
+		j.inputs = map[string]map[string]any{}
+	}
+	var in map[string]any
+	if prev != nil {
+		in = maps.Clone(prev.Output)
+	}
+	j.inputs[stage.ID] = in
+	return &upal.StageResult{StageID: stage.ID, Status: upal.StageStatusCompleted, Output: map[string]any{"joined": len(in)}}, nil
+}
+
+func TestPipelineRunner_ParallelStagesJoin(t *testing.T) {
+	branches := &barrierStageExecutor{want: 2, release: make(chan struct{})}
+	join := &joinStageExecutor{}
+	runner := NewPipelineRunner(repository.NewMemoryPipelineRunRepository())
+	runner.RegisterExecutor(branches)
+	runner.RegisterExecutor(join)
+
+	pipeline := &upal.Pipeline{ID: "pipe-par", Stages: []upal.Stage{
+		{ID: "news", Type: "workflow", Config: upal.StageConfig{WorkflowName: "news"}},
+		{ID: "weather", Type: "workflow", Config: upal.StageConfig{WorkflowName: "weather"}},
+		{ID: "collect", Type: "transform", DependsOn: []string{"news", "weather"}},
+	}}
+	run, err := runner.Start(context.Background(), pipeline, nil)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if run.Status != upal.PipelineRunCompleted {
+		t.Fatalf("status = %q, want completed", run.Status)
+	}
+	if got := branches.maxSeen.Load(); got != 2 {
+		t.Errorf("max concurrent branch stages = %d, want 2", got)
+	}
+
+	// Outputs are only passed on from completed stages, so the join saw
+	// both branches finish.
+	want := map[string]any{"news": "done", "weather": "done"}
+	if got := join.inputs["collect"]; !maps.Equal(got, want) {
+		t.Errorf("collect input = %v, want %v", got, want)
+	}
+}
+
+func TestPipelineRunner_ParallelStagesRespectLimiter(t *testing.T) {
+	// With one global slot the branches cannot overlap, so the barrier
+	// never opens and both time out; a short context keeps the test fast.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	branches := &barrierStageExecutor{want: 2, release: make(chan struct{})}
+	runner := NewPipelineRunner(repository.NewMemoryPipelineRunRepository())
+	runner.RegisterExecutor(branches)
+	runner.RegisterExecutor(&joinStageExecutor{})
+	runner.SetConcurrencyLimiter(NewConcurrencyLimiter(upal.ConcurrencyLimits{GlobalMax: 1, PerWorkflow: 1}))
+
+	pipeline := &upal.Pipeline{ID: "pipe-lim", Stages: []upal.Stage{
+		{ID: "a", Type: "workflow", Config: upal.StageConfig{WorkflowName: "a"}},
+		{ID: "b", Type: "workflow", Config: upal.StageConfig{WorkflowName: "b"}},
+		{ID: "join", Type: "transform", DependsOn: []string{"a", "b"}},
+	}}
+	run, err := runner.Start(ctx, pipeline, nil)
+	if err == nil || run.Status != upal.PipelineRunFailed {
+		t.Fatalf("err = %v, status = %q, want failed run", err, run.Status)
+	}
+	if got := branches.maxSeen.Load(); got != 1 {
+		t.Errorf("max concurrent branch stages = %d, want 1 under the limiter", got)
+	}
+	if _, ran := run.StageResults["join"]; ran {
+		t.Error("join stage started although a dependency failed")
+	}
+}
+
+func TestValidatePipeline_DependsOn(t *testing.T) {
+	p := &upal.Pipeline{Name: "p", Stages: []upal.Stage{
+		{ID: "a", Type: "transform", DependsOn: []string{"c"}},
+		{ID: "b", Type: "transform", DependsOn: []string{"a", "ghost"}},
+		{ID: "c", Type: "transform", DependsOn: []string{"b"}},
+		{ID: "d", Type: "transform", DependsOn: []string{"d"}},
+	}}
+	var got []string
+	for _, issue := range ValidatePipeline(context.Background(), p, nil, nil) {
+		got = append(got, issue.StageID+": "+issue.Message)
+	}
+	want := []string{
+		`b: unknown stage "ghost"`,
+		"d: stage cannot depend on itself",
+		"a: dependency cycle: a -> c -> b -> a",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("issues = %q, want %q", got, want)
+	}
+}
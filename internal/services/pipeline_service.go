@@ -34,9 +34,6 @@ func (s *PipelineService) Create(ctx context.Context, p *upal.Pipeline) error {
 			p.Stages[i].ID = fmt.Sprintf("stage-%d", i+1)
 		}
 	}
-	now := time.Now()
-	p.CreatedAt = now
-	p.UpdatedAt = now
 	return s.repo.Create(ctx, p)
 }
 
@@ -49,7 +46,6 @@ func (s *PipelineService) List(ctx context.Context) ([]*upal.Pipeline, error) {
 }
 
 func (s *PipelineService) Update(ctx context.Context, p *upal.Pipeline) error {
-	p.UpdatedAt = time.Now()
 	return s.repo.Update(ctx, p)
 }
 
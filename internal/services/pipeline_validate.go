@@ -0,0 +1,185 @@
+// internal/services/pipeline_validate.go
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/soochol/upal/internal/services/scheduler"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// WorkflowLookup finds a workflow definition by name.
+type WorkflowLookup interface {
+	Lookup(ctx context.Context, name string) (*upal.WorkflowDefinition, error)
+}
+
+// ConnectionLookup finds a connection by ID.
+type ConnectionLookup interface {
+	Get(ctx context.Context, id string) (*upal.Connection, error)
+}
+
+// ValidatePipeline checks a pipeline definition for problems that would
+// otherwise only surface when it runs: unknown stage types, missing required
+// stage config, references to workflows or connections that do not exist,
+// depends_on references that are unknown or cyclic, and unparseable cron
+// expressions. A nil lookup skips its reference checks.
+func ValidatePipeline(ctx context.Context, p *upal.Pipeline, workflows WorkflowLookup, conns ConnectionLookup) []upal.PipelineIssue {
+	var issues []upal.PipelineIssue
+	add := func(stageID, field, format string, args ...any) {
+		issues = append(issues, upal.PipelineIssue{StageID: stageID, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if p.Name == "" {
+		add("", "name", "name is required")
+	}
+
+	seen := make(map[string]bool, len(p.Stages))
+	for i, stage := range p.Stages {
+		id := stage.ID
+		if id == "" {
+			id = fmt.Sprintf("#%d", i+1)
+			add(id, "id", "stage id is required")
+		} else if seen[id] {
+			add(id, "id", "duplicate stage id %q", id)
+		}
+		seen[id] = true
+
+		if !upal.KnownStageTypes[stage.Type] {
+			add(id, "type", "unknown stage type %q", stage.Type)
+			continue
+		}
+
+		cfg := stage.Config
+		if cfg.Retry != nil {
+			if err := cfg.Retry.Validate(); err != nil {
+				add(id, "config.retry", "%v", err)
+			}
+		}
+		requireConnection := func(required bool) {
+			if cfg.ConnectionID == "" {
+				if required {
+					add(id, "config.connection_id", "connection_id is required for %s stages", stage.Type)
+				}
+				return
+			}
+			if conns != nil {
+				if _, err := conns.Get(ctx, cfg.ConnectionID); err != nil {
+					add(id, "config.connection_id", "connection %q not found", cfg.ConnectionID)
+				}
+			}
+		}
+
+		switch stage.Type {
+		case "workflow":
+			if cfg.WorkflowName == "" {
+				add(id, "config.workflow_name", "workflow_name is required for workflow stages")
+			} else if workflows != nil {
+				if wf, err := workflows.Lookup(ctx, cfg.WorkflowName); err != nil {
+					add(id, "config.workflow_name", "workflow %q not found", cfg.WorkflowName)
+				} else {
+					for nodeID := range cfg.InputsFrom {
+						if !isWorkflowInput(wf, nodeID) {
+							add(id, "config.inputs_from", "workflow %q has no input node %q", cfg.WorkflowName, nodeID)
+						}
+					}
+				}
+			}
+			if len(cfg.InputsFrom) > 0 {
+				upstream := upstreamStages(p, i)
+				for nodeID, ref := range cfg.InputsFrom {
+					stageID, _, ok := upal.ParseStageOutputRef(ref)
+					switch {
+					case !ok:
+						add(id, "config.inputs_from", "%s: reference %q must be <stage_id>.<output_key>", nodeID, ref)
+					case !upstream[stageID]:
+						add(id, "config.inputs_from", "%s: stage %q does not run before this stage", nodeID, stageID)
+					}
+				}
+			}
+		case "approval":
+			requireConnection(false)
+		case "notification":
+			requireConnection(true)
+		case "schedule":
+			if cfg.Cron == "" {
+				add(id, "config.cron", "cron is required for schedule stages")
+			} else if err := scheduler.ValidateCronExpr(cfg.Cron, cfg.Timezone); err != nil {
+				add(id, "config.cron", "%v", err)
+			}
+		case "transform":
+			if cfg.Expression != "" && !json.Valid([]byte(cfg.Expression)) {
+				add(id, "config.expression", "expression must be a JSON template")
+			}
+			for j, op := range cfg.Operations {
+				if err := op.Validate(); err != nil {
+					add(id, fmt.Sprintf("config.operations[%d]", j), "%v", err)
+				}
+			}
+		case "collect":
+			if len(cfg.Sources) == 0 {
+				add(id, "config.sources", "at least one source is required for collect stages")
+			}
+			for j, src := range cfg.Sources {
+				field := fmt.Sprintf("config.sources[%d]", j)
+				switch src.Type {
+				case "rss", "http", "scrape":
+					if src.URL == "" {
+						add(id, field+".url", "url is required for %s sources", src.Type)
+					}
+				case "research":
+					if src.Topic == "" {
+						add(id, field+".topic", "topic is required for research sources")
+					}
+				case "social":
+				default:
+					add(id, field+".type", "unknown source type %q", src.Type)
+				}
+			}
+		}
+	}
+	for _, stage := range p.Stages {
+		for _, dep := range stage.DependsOn {
+			switch {
+			case dep == stage.ID:
+				add(stage.ID, "depends_on", "stage cannot depend on itself")
+			case !seen[dep]:
+				add(stage.ID, "depends_on", "unknown stage %q", dep)
+			}
+		}
+	}
+	if cycle := stageDependencyCycle(p.Stages); len(cycle) > 2 {
+		add(cycle[0], "depends_on", "dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return issues
+}
+
+// upstreamStages returns the IDs of the stages guaranteed to have run before
+// p.Stages[i]: its transitive depends_on when the pipeline uses them,
+// otherwise every earlier stage.
+func upstreamStages(p *upal.Pipeline, i int) map[string]bool {
+	upstream := make(map[string]bool)
+	if !usesStageDependencies(p) {
+		for _, s := range p.Stages[:i] {
+			upstream[s.ID] = true
+		}
+		return upstream
+	}
+	deps := make(map[string][]string, len(p.Stages))
+	for _, s := range p.Stages {
+		deps[s.ID] = s.DependsOn
+	}
+	queue := append([]string(nil), p.Stages[i].DependsOn...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if upstream[id] || id == p.Stages[i].ID {
+			continue
+		}
+		upstream[id] = true
+		queue = append(queue, deps[id]...)
+	}
+	return upstream
+}
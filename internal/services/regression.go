@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+// ErrUnknownComparisonMode is returned by RegressionChecker.Check for a mode
+// with no registered comparator.
+var ErrUnknownComparisonMode = errors.New("unknown comparison mode")
+
+// OutputComparator scores how similar an output is to its baseline, from 0
+// (unrelated) to 1 (identical).
+type OutputComparator interface {
+	Compare(ctx context.Context, baseline, actual string) (float64, error)
+}
+
+// ExactComparator scores 1 for byte-identical outputs and 0 otherwise.
+type ExactComparator struct{}
+
+func (ExactComparator) Compare(_ context.Context, baseline, actual string) (float64, error) {
+	if baseline == actual {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// NormalizedComparator ignores case and whitespace. Outputs that are equal
+// after normalization score 1; others score the Jaccard similarity of their
+// word sets.
+type NormalizedComparator struct{}
+
+func (NormalizedComparator) Compare(_ context.Context, baseline, actual string) (float64, error) {
+	a, b := strings.Fields(strings.ToLower(baseline)), strings.Fields(strings.ToLower(actual))
+	if slices.Equal(a, b) {
+		return 1, nil
+	}
+	set := make(map[string]int, len(a)+len(b))
+	for _, w := range a {
+		set[w] |= 1
+	}
+	for _, w := range b {
+		set[w] |= 2
+	}
+	shared := 0
+	for _, bits := range set {
+		if bits == 3 {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(set)), nil
+}
+
+// EmbeddingComparator scores the cosine similarity of the two outputs'
+// embeddings.
+type EmbeddingComparator struct {
+	Embedder ports.Embedder
+}
+
+func (c EmbeddingComparator) Compare(ctx context.Context, baseline, actual string) (float64, error) {
+	vecs, err := c.Embedder.Embed(ctx, []string{baseline, actual})
+	if err != nil {
+		return 0, err
+	}
+	if len(vecs) != 2 {
+		return 0, fmt.Errorf("embedder returned %d vectors, want 2", len(vecs))
+	}
+	return cosineSimilarity(vecs[0], vecs[1]), nil
+}
+
+// defaultRegressionThresholds apply when a check does not set a threshold.
+var defaultRegressionThresholds = map[string]float64{
+	upal.RegressionExact:      1,
+	upal.RegressionNormalized: 1,
+	upal.RegressionEmbedding:  0.9,
+}
+
+// RegressionChecker re-runs a workflow with its baseline run's inputs and
+// compares each output node's result against the baseline.
+type RegressionChecker struct {
+	workflows   ports.WorkflowExecutor
+	comparators map[string]OutputComparator
+}
+
+// NewRegressionChecker returns a checker with the exact and normalized
+// comparators; SetComparator adds embedding similarity when available.
+func NewRegressionChecker(workflows ports.WorkflowExecutor) *RegressionChecker {
+	return &RegressionChecker{
+		workflows: workflows,
+		comparators: map[string]OutputComparator{
+			upal.RegressionExact:      ExactComparator{},
+			upal.RegressionNormalized: NormalizedComparator{},
+		},
+	}
+}
+
+// SetComparator registers cmp for mode, replacing any existing comparator.
+func (c *RegressionChecker) SetComparator(mode string, cmp OutputComparator) {
+	c.comparators[mode] = cmp
+}
+
+// Modes lists the available comparison modes.
+func (c *RegressionChecker) Modes() []string {
+	return slices.Sorted(maps.Keys(c.comparators))
+}
+
+// Check runs wf with baseline's inputs and compares outputs using mode (default
+// exact). A threshold of zero uses the mode's default. A run that fails is
+// reported as a failed check rather than an error; the error is only set
+// for an unknown mode or a comparator failure.
+func (c *RegressionChecker) Check(ctx context.Context, wf *upal.WorkflowDefinition, baseline *upal.RunRecord, mode string, threshold float64) (*upal.RegressionResult, error) {
+	if mode == "" {
+		mode = upal.RegressionExact
+	}
+	cmp, ok := c.comparators[mode]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownComparisonMode, mode, strings.Join(c.Modes(), ", "))
+	}
+	if threshold <= 0 {
+		threshold = defaultRegressionThresholds[mode]
+		if threshold == 0 {
+			threshold = 1
+		}
+	}
+
+	result := &upal.RegressionResult{
+		WorkflowName:  wf.Name,
+		BaselineRunID: baseline.ID,
+		Mode:          mode,
+		Threshold:     threshold,
+		Outputs:       []upal.RegressionOutput{},
+	}
+
+	actual, runErr := c.run(ctx, wf, baseline.Inputs)
+	if runErr != nil {
+		result.Error = runErr.Error()
+		return result, nil
+	}
+
+	expected, _ := baseline.Outputs["__output__"].(map[string]any)
+	nodeIDs := slices.Sorted(maps.Keys(expected))
+	for id := range actual {
+		if _, ok := expected[id]; !ok {
+			nodeIDs = append(nodeIDs, id)
+		}
+	}
+	slices.Sort(nodeIDs)
+
+	result.Similarity = 1
+	for _, id := range nodeIDs {
+		out := upal.RegressionOutput{NodeID: id, Baseline: comparableText(expected[id]), Actual: comparableText(actual[id])}
+		_, inBaseline := expected[id]
+		_, inActual := actual[id]
+		if inBaseline && inActual {
+			score, err := cmp.Compare(ctx, out.Baseline, out.Actual)
+			if err != nil {
+				return nil, fmt.Errorf("compare output %q: %w", id, err)
+			}
+			out.Similarity = score
+		}
+		out.Passed = out.Similarity >= threshold
+		result.Similarity = min(result.Similarity, out.Similarity)
+		result.Outputs = append(result.Outputs, out)
+	}
+	result.Passed = result.Similarity >= threshold
+	return result, nil
+}
+
+// run executes wf and returns its output node results.
+func (c *RegressionChecker) run(ctx context.Context, wf *upal.WorkflowDefinition, inputs map[string]any) (map[string]any, error) {
+	events, results, err := c.workflows.Run(ctx, wf, inputs)
+	if err != nil {
+		return nil, err
+	}
+	var runErr error
+	for ev := range events {
+		if ev.Type == upal.EventError && runErr == nil {
+			runErr = fmt.Errorf("%v", ev.Payload["error"])
+		}
+	}
+	res, ok := <-results
+	if runErr != nil {
+		return nil, runErr
+	}
+	if !ok {
+		return nil, fmt.Errorf("workflow produced no result")
+	}
+	// Baselines store every output node under __output__, so compare the same set.
+	outputs, _ := res.State["__output__"].(map[string]any)
+	return outputs, nil
+}
+
+// comparableText renders an output value for comparison; non-strings are
+// encoded as JSON.
+func comparableText(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
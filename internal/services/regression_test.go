@@ -0,0 +1,33 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/soochol/upal/internal/services"
+)
+
+func TestOutputComparators(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		cmp      services.OutputComparator
+		baseline string
+		actual   string
+		want     float64
+	}{
+		{"exact match", services.ExactComparator{}, "Hello world", "Hello world", 1},
+		{"exact whitespace differs", services.ExactComparator{}, "Hello world", "hello  world\n", 0},
+		{"normalized ignores case and spacing", services.NormalizedComparator{}, "Hello world", "hello  world\n", 1},
+		{"normalized word overlap", services.NormalizedComparator{}, "a b c", "a b d", 0.5},
+		{"normalized disjoint", services.NormalizedComparator{}, "a", "b", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cmp.Compare(ctx, tt.baseline, tt.actual)
+			if err != nil || got != tt.want {
+				t.Errorf("Compare = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
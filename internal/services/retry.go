@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
@@ -17,6 +18,8 @@ var _ ports.RetryExecutor = (*RetryExecutor)(nil)
 type RetryExecutor struct {
 	workflowExec  ports.WorkflowExecutor
 	runHistorySvc ports.RunHistoryPort
+	runManager    ports.RunManagerPort
+	starts        ports.StartLimiter
 }
 
 func NewRetryExecutor(workflowExec ports.WorkflowExecutor, runHistorySvc ports.RunHistoryPort) *RetryExecutor {
@@ -26,6 +29,14 @@ func NewRetryExecutor(workflowExec ports.WorkflowExecutor, runHistorySvc ports.R
 	}
 }
 
+// SetRunManager registers each attempt with rm so it is listed among active
+// runs and can be cancelled. A cancelled attempt is not retried.
+func (r *RetryExecutor) SetRunManager(rm ports.RunManagerPort) { r.runManager = rm }
+
+// SetStartLimiter makes each retry wait for room under the run start rate
+// limits. The first attempt is covered by the caller's Acquire.
+func (r *RetryExecutor) SetStartLimiter(l ports.StartLimiter) { r.starts = l }
+
 func (r *RetryExecutor) ExecuteWithRetry(
 	ctx context.Context,
 	wf *upal.WorkflowDefinition,
@@ -47,26 +58,33 @@ func (r *RetryExecutor) ExecuteWithRetry(
 			if attempt > 0 && firstRunID != "" {
 				retryOf = &firstRunID
 			}
+			if attempt > 0 && r.starts != nil {
+				if err := r.starts.ReserveStart(ctx, wf.Name); err != nil {
+					outEvents <- upal.WorkflowEvent{
+						Type:    upal.EventError,
+						Payload: map[string]any{"error": err.Error()},
+					}
+					return
+				}
+			}
 
-			record, err := r.runHistorySvc.StartRun(ctx, wf.Name, triggerType, triggerRef, inputs, wf)
+			runCtx, run, err := TrackRun(ctx, r.runHistorySvc, r.runManager, wf, triggerType, triggerRef, inputs)
 			if err != nil {
 				slog.Warn("retry: failed to create run record", "err", err)
-			} else {
-				if err := r.runHistorySvc.UpdateRunRetryMeta(ctx, record.ID, attempt, retryOf); err != nil {
+			} else if run != nil {
+				if err := r.runHistorySvc.UpdateRunRetryMeta(ctx, run.ID, attempt, retryOf); err != nil {
 					slog.Warn("retry: failed to update retry metadata", "err", err)
 				}
 				if attempt == 0 {
-					firstRunID = record.ID
+					firstRunID = run.ID
 				}
 			}
 
-			events, result, execErr := r.workflowExec.Run(ctx, wf, inputs)
+			events, result, execErr := r.workflowExec.Run(runCtx, wf, inputs)
 			if execErr != nil {
-				if record != nil {
-					r.runHistorySvc.FailRun(ctx, record.ID, execErr.Error())
-				}
+				run.Finish(runCtx, upal.RunResult{}, execErr)
 
-				if !isRetryable(execErr) || attempt >= policy.MaxRetries {
+				if run.Cancelled(runCtx) || !isRetryable(execErr) || attempt >= policy.MaxRetries {
 					outEvents <- upal.WorkflowEvent{
 						Type:    upal.EventError,
 						Payload: map[string]any{"error": execErr.Error()},
@@ -81,6 +99,9 @@ func (r *RetryExecutor) ExecuteWithRetry(
 			var hadError bool
 			var errMsg string
 			for ev := range events {
+				if !run.Observe(runCtx, ev) {
+					continue
+				}
 				outEvents <- ev
 				if ev.Type == upal.EventError {
 					hadError = true
@@ -91,11 +112,9 @@ func (r *RetryExecutor) ExecuteWithRetry(
 			res := <-result
 
 			if hadError {
-				if record != nil {
-					r.runHistorySvc.FailRun(ctx, record.ID, errMsg)
-				}
+				run.Finish(runCtx, res, errors.New(errMsg))
 
-				if !isRetryableMsg(errMsg) || attempt >= policy.MaxRetries {
+				if run.Cancelled(runCtx) || !isRetryableMsg(errMsg) || attempt >= policy.MaxRetries {
 					return
 				}
 
@@ -103,8 +122,9 @@ func (r *RetryExecutor) ExecuteWithRetry(
 				continue
 			}
 
-			if record != nil {
-				r.runHistorySvc.CompleteRun(ctx, record.ID, res.State)
+			run.Finish(runCtx, res, nil)
+			if run.Cancelled(runCtx) {
+				return
 			}
 			outResult <- res
 			return
@@ -127,9 +147,20 @@ func sleepWithBackoff(ctx context.Context, policy upal.RetryPolicy, attempt int)
 	}
 }
 
+// RetryDelays returns the backoff applied before each retry the policy
+// allows, in order.
+func RetryDelays(policy upal.RetryPolicy) []time.Duration {
+	delays := make([]time.Duration, policy.MaxRetries)
+	for i := range delays {
+		delays[i] = calculateBackoff(policy, i)
+	}
+	return delays
+}
+
 func calculateBackoff(policy upal.RetryPolicy, attempt int) time.Duration {
 	delay := float64(policy.InitialDelay) * math.Pow(policy.BackoffFactor, float64(attempt))
-	if time.Duration(delay) > policy.MaxDelay {
+	// Compare as floats: large attempts overflow time.Duration.
+	if delay > float64(policy.MaxDelay) {
 		return policy.MaxDelay
 	}
 	return time.Duration(delay)
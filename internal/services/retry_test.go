@@ -303,3 +303,19 @@ func TestNewRetryExecutor(t *testing.T) {
 		t.Error("expected runHistorySvc to be nil")
 	}
 }
+
+func TestRetryDelays_LargeAttemptsStayCapped(t *testing.T) {
+	policy := upal.RetryPolicy{
+		MaxRetries:    upal.MaxRetryPolicyRetries,
+		InitialDelay:  time.Second,
+		MaxDelay:      time.Hour,
+		BackoffFactor: 10,
+	}
+	delays := RetryDelays(policy)
+	if len(delays) != policy.MaxRetries {
+		t.Fatalf("got %d delays, want %d", len(delays), policy.MaxRetries)
+	}
+	if last := delays[len(delays)-1]; last != time.Hour {
+		t.Errorf("last delay = %v, want the 1h cap", last)
+	}
+}
@@ -2,6 +2,7 @@ package run
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -16,6 +17,7 @@ type RunPublisher struct {
 	runManager    ports.RunManagerPort
 	runHistorySvc ports.RunHistoryPort
 	executionReg  ports.ExecutionRegistryPort
+	limiter       ports.ConcurrencyControl
 }
 
 func NewRunPublisher(
@@ -32,29 +34,64 @@ func NewRunPublisher(
 	}
 }
 
+// SetConcurrencyLimiter makes Launch hold a concurrency slot, and so pass
+// the run start rate limits, for the whole run. Every run started through
+// the publisher (manual runs, reruns, resumes, replays) is then limited like
+// scheduled and webhook runs.
+func (p *RunPublisher) SetConcurrencyLimiter(l ports.ConcurrencyControl) { p.limiter = l }
+
 // Launch starts background execution and publishes events to RunManager.
-// Caller must call runManager.Register(runID) before calling Launch.
+// Caller must call runManager.Register(runID) before calling Launch. With a
+// concurrency limiter set, the run waits for a slot first; cancelling it
+// through RunManager stops the wait.
 func (p *RunPublisher) Launch(ctx context.Context, runID string, wf *upal.WorkflowDefinition, inputs map[string]any) {
+	p.launch(ctx, runID, wf, inputs, false)
+}
+
+// LaunchHeld is Launch for callers that already acquired wf's concurrency
+// slot, e.g. to reject a request when the limiter is saturated. The caller
+// releases the slot once LaunchHeld returns.
+func (p *RunPublisher) LaunchHeld(ctx context.Context, runID string, wf *upal.WorkflowDefinition, inputs map[string]any) {
+	p.launch(ctx, runID, wf, inputs, true)
+}
+
+func (p *RunPublisher) launch(ctx context.Context, runID string, wf *upal.WorkflowDefinition, inputs map[string]any, slotHeld bool) {
 	if p.executionReg != nil {
 		p.executionReg.Register(runID)
 		defer p.executionReg.Unregister(runID)
 	}
 
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	p.runManager.Attach(runID, wf.Name, cancel)
+
+	if p.limiter != nil && !slotHeld {
+		if err := p.limiter.Acquire(ctx, wf.Name); err != nil {
+			if !p.finishCancelled(ctx, runID, nil) {
+				p.failStart(ctx, runID, err)
+			}
+			return
+		}
+		defer p.limiter.Release(wf.Name)
+	}
+
 	events, result, err := p.workflowExec.Run(ctx, wf, inputs)
 	if err != nil {
-		slog.Error("background run failed to start", "run_id", runID, "err", err)
-		if p.runHistorySvc != nil {
-			p.runHistorySvc.FailRun(ctx, runID, err.Error())
+		if p.finishCancelled(ctx, runID, nil) {
+			return
 		}
-		p.runManager.Fail(runID, err.Error())
+		p.failStart(ctx, runID, err)
 		return
 	}
 
 	var totalUsage upal.TokenUsage
 	for ev := range events {
 		if ev.Type == upal.EventError {
+			if p.finishCancelled(ctx, runID, nil) {
+				return
+			}
 			errMsg := fmt.Sprintf("%v", ev.Payload["error"])
-			slog.Error("background run error", "run_id", runID, "err", errMsg)
+			slog.ErrorContext(ctx, "background run error", "run_id", runID, "err", errMsg)
 			p.runManager.Append(runID, upal.EventRecord{
 				WorkflowEvent: ev,
 			})
@@ -65,6 +102,14 @@ func (p *RunPublisher) Launch(ctx context.Context, runID string, wf *upal.Workfl
 			return
 		}
 
+		// Run context is persisted on the record, not streamed to clients.
+		if ev.Type == upal.EventRunContext {
+			if rc, ok := ev.Payload["run_context"].(*upal.RunContext); ok && p.runHistorySvc != nil {
+				p.runHistorySvc.SetRunContext(ctx, runID, rc)
+			}
+			continue
+		}
+
 		if ev.Type == upal.EventNodeStarted {
 			ev.Payload["started_at"] = time.Now().UnixMilli()
 		}
@@ -88,6 +133,9 @@ func (p *RunPublisher) Launch(ctx context.Context, runID string, wf *upal.Workfl
 	}
 
 	res := <-result
+	if p.finishCancelled(ctx, runID, res.State) {
+		return
+	}
 
 	donePayload := map[string]any{
 		"status":     "completed",
@@ -95,13 +143,55 @@ func (p *RunPublisher) Launch(ctx context.Context, runID string, wf *upal.Workfl
 		"state":      res.State,
 		"run_id":     runID,
 	}
+	if len(res.NodeErrors) > 0 {
+		donePayload["status"] = string(upal.RunStatusCompletedWithErrors)
+		donePayload["node_errors"] = res.NodeErrors
+	}
 
 	if p.runHistorySvc != nil {
-		p.runHistorySvc.CompleteRun(ctx, runID, res.State)
+		p.runHistorySvc.CompleteRunWithErrors(ctx, runID, res.State, res.NodeErrors)
 	}
 	p.runManager.Complete(runID, donePayload)
 }
 
+// failStart records runID as failed, or blocked for moderation errors,
+// before any node ran.
+func (p *RunPublisher) failStart(ctx context.Context, runID string, err error) {
+	slog.ErrorContext(ctx, "background run failed to start", "run_id", runID, "err", err)
+	if p.runHistorySvc != nil {
+		if errors.Is(err, upal.ErrModerationBlocked) {
+			p.runHistorySvc.BlockRun(ctx, runID, err.Error())
+		} else {
+			p.runHistorySvc.FailRun(ctx, runID, err.Error())
+		}
+	}
+	p.runManager.Fail(runID, err.Error())
+}
+
+// finishCancelled records runID as cancelled when ctx was cancelled through
+// RunManager.Cancel, reporting whether it did. partial is the state of the
+// nodes that completed; it is kept on the record and sent in the done event.
+func (p *RunPublisher) finishCancelled(ctx context.Context, runID string, partial map[string]any) bool {
+	cause := context.Cause(ctx)
+	if !errors.Is(cause, upal.ErrRunCancelled) {
+		return false
+	}
+	reason := upal.CancelReason(cause)
+	slog.InfoContext(ctx, "run cancelled", "run_id", runID, "reason", reason)
+	if p.runHistorySvc != nil {
+		p.runHistorySvc.CancelRun(context.WithoutCancel(ctx), runID, reason, partial)
+	}
+	payload := map[string]any{"status": string(upal.RunStatusCancelled), "run_id": runID}
+	if reason != "" {
+		payload["cancel_reason"] = reason
+	}
+	if len(partial) > 0 {
+		payload["state"] = partial
+	}
+	p.runManager.Complete(runID, payload)
+	return true
+}
+
 func (p *RunPublisher) trackNodeRun(ctx context.Context, runID string, ev upal.WorkflowEvent) *upal.TokenUsage {
 	if p.runHistorySvc == nil || ev.NodeID == "" {
 		return nil
@@ -110,6 +200,8 @@ func (p *RunPublisher) trackNodeRun(ctx context.Context, runID string, ev upal.W
 	now := time.Now()
 
 	switch ev.Type {
+	case upal.EventProgress:
+		p.runHistorySvc.UpdateRunProgress(ctx, runID, toInt(ev.Payload["percent"]))
 	case upal.EventNodeStarted:
 		p.runHistorySvc.UpdateNodeRun(ctx, runID, upal.NodeRunRecord{
 			NodeID:    ev.NodeID,
@@ -131,12 +223,33 @@ func (p *RunPublisher) trackNodeRun(ctx context.Context, runID string, ev upal.W
 			StartedAt:   now,
 			CompletedAt: &now,
 			Usage:       usage,
+			Output:      nodeOutput(ev),
 		})
 		return usage
+	case upal.EventNodeFailed:
+		errMsg, _ := ev.Payload["error"].(string)
+		p.runHistorySvc.UpdateNodeRun(ctx, runID, upal.NodeRunRecord{
+			NodeID:      ev.NodeID,
+			Status:      upal.NodeRunError,
+			StartedAt:   now,
+			CompletedAt: &now,
+			Error:       &errMsg,
+		})
 	}
 	return nil
 }
 
+// nodeOutput returns the state value a completed node produced, falling back
+// to the event's text output.
+func nodeOutput(ev upal.WorkflowEvent) any {
+	if delta, ok := ev.Payload["state_delta"].(map[string]any); ok {
+		if v, ok := delta[ev.NodeID]; ok && v != nil {
+			return v
+		}
+	}
+	return ev.Payload["output"]
+}
+
 func toInt(v any) int {
 	switch n := v.(type) {
 	case float64:
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// saveArtifacts stores each output node result of a run as a file and
+// returns references to them. Failures are logged and skipped so that a
+// storage problem never fails an otherwise successful run.
+func (s *RunHistoryService) saveArtifacts(ctx context.Context, runID string, outputs map[string]any) []upal.RunArtifact {
+	nodeOutputs, _ := outputs["__output__"].(map[string]any)
+	nodeIDs := make([]string, 0, len(nodeOutputs))
+	for id := range nodeOutputs {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Strings(nodeIDs)
+
+	var artifacts []upal.RunArtifact
+	for _, nodeID := range nodeIDs {
+		data, contentType := artifactContent(nodeOutputs[nodeID])
+		if len(data) == 0 {
+			continue
+		}
+		name := nodeID + artifactExtension(contentType)
+		info, err := s.store.Save(ctx, runID+"-"+name, contentType, bytes.NewReader(data))
+		if err != nil {
+			slog.WarnContext(ctx, "failed to store run artifact", "run_id", runID, "node", nodeID, "err", err)
+			continue
+		}
+		artifacts = append(artifacts, upal.RunArtifact{
+			Name:        name,
+			NodeID:      nodeID,
+			FileID:      info.ID,
+			ContentType: contentType,
+			Size:        info.Size,
+		})
+	}
+	return artifacts
+}
+
+// artifactContent serializes an output value. Strings are stored verbatim
+// with a sniffed content type (HTML layouts become text/html); other values
+// are stored as JSON.
+func artifactContent(v any) ([]byte, string) {
+	if str, ok := v.(string); ok {
+		data := []byte(str)
+		return data, http.DetectContentType(data)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, ""
+	}
+	return data, "application/json"
+}
+
+func artifactExtension(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "text/html"):
+		return ".html"
+	case strings.HasPrefix(contentType, "application/json"):
+		return ".json"
+	case strings.HasPrefix(contentType, "text/plain"):
+		return ".txt"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/soochol/upal/internal/dag"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// ReusableOutputs returns the checkpointed outputs of prev that a resumed
+// run of wf may reuse, keyed by node ID. A node qualifies when it completed
+// in prev with a stored output, its definition and incoming edges are
+// unchanged since prev started, it is not part of a loop, and every parent
+// qualifies too — so a stale checkpoint is never mixed with fresh upstream
+// results.
+func ReusableOutputs(prev *upal.RunRecord, wf *upal.WorkflowDefinition) (map[string]any, error) {
+	reusable := make(map[string]any)
+	if prev.WorkflowDef == nil {
+		return reusable, nil
+	}
+
+	current, err := dag.Build(wf)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := dag.Build(prev.WorkflowDef)
+	if err != nil {
+		// The recorded definition no longer builds; nothing can be trusted.
+		return reusable, nil
+	}
+
+	checkpoints := make(map[string]any, len(prev.NodeRuns))
+	for _, nr := range prev.NodeRuns {
+		if nr.Status == upal.NodeRunCompleted && nr.Output != nil {
+			checkpoints[nr.NodeID] = nr.Output
+		}
+	}
+	looped := make(map[string]bool)
+	for _, e := range current.BackEdges() {
+		looped[e.From], looped[e.To] = true, true
+	}
+
+	for _, nodeID := range current.TopologicalOrder() {
+		output, ok := checkpoints[nodeID]
+		if !ok || looped[nodeID] || previous.Node(nodeID) == nil {
+			continue
+		}
+		if nodeFingerprint(current, nodeID) != nodeFingerprint(previous, nodeID) {
+			continue
+		}
+		parentsReused := true
+		for _, parentID := range current.Parents(nodeID) {
+			if _, ok := reusable[parentID]; !ok {
+				parentsReused = false
+				break
+			}
+		}
+		if parentsReused {
+			reusable[nodeID] = output
+		}
+	}
+	return reusable, nil
+}
+
+// nodeFingerprint captures everything that determines a node's output apart
+// from its parents' outputs: its own definition and its incoming edges.
+func nodeFingerprint(d *dag.DAG, nodeID string) string {
+	n := d.Node(nodeID)
+	parents := append([]string(nil), d.Parents(nodeID)...)
+	sort.Strings(parents)
+	edges := make([]upal.EdgeDefinition, 0, len(parents))
+	for _, p := range parents {
+		if e, ok := d.Edge(p, nodeID); ok {
+			edges = append(edges, e)
+		}
+	}
+	b, _ := json.Marshal(struct {
+		Type   upal.NodeType         `json:"type"`
+		Config map[string]any        `json:"config"`
+		Edges  []upal.EdgeDefinition `json:"edges"`
+	}{n.Type, n.Config, edges})
+	return string(b)
+}
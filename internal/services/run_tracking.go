@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+// TrackedRun is a run executed outside RunPublisher.Launch: a retry executor
+// attempt, a sync webhook, or an A2A call. It keeps the run's history record
+// and run manager entry current so the run is listed as active and can be
+// cancelled. Its methods are no-ops on a nil *TrackedRun.
+type TrackedRun struct {
+	ID      string
+	history ports.RunHistoryPort
+	manager ports.RunManagerPort
+	cancel  context.CancelCauseFunc
+}
+
+// TrackRun records a new run of wf and registers it with manager, returning
+// the context to execute it with. Either dependency may be nil; without
+// history there is no record to track and TrackRun returns ctx and a nil run.
+func TrackRun(ctx context.Context, history ports.RunHistoryPort, manager ports.RunManagerPort, wf *upal.WorkflowDefinition, triggerType, triggerRef string, inputs map[string]any) (context.Context, *TrackedRun, error) {
+	if history == nil {
+		return ctx, nil, nil
+	}
+	record, err := history.StartRun(ctx, wf.Name, triggerType, triggerRef, inputs, wf)
+	if err != nil {
+		return ctx, nil, err
+	}
+	t := &TrackedRun{ID: record.ID, history: history, manager: manager}
+	if manager != nil {
+		ctx, t.cancel = manager.Track(ctx, record.ID, wf.Name)
+	}
+	return ctx, t, nil
+}
+
+// Observe records ev and reports whether the caller should pass it on. Run
+// context events are stored on the record rather than forwarded.
+func (t *TrackedRun) Observe(ctx context.Context, ev upal.WorkflowEvent) bool {
+	if ev.Type == upal.EventRunContext {
+		if rc, ok := ev.Payload["run_context"].(*upal.RunContext); ok && t != nil {
+			t.history.SetRunContext(ctx, t.ID, rc)
+		}
+		return false
+	}
+	if t != nil && t.manager != nil {
+		t.manager.Append(t.ID, upal.EventRecord{WorkflowEvent: ev})
+	}
+	return true
+}
+
+// Cancelled reports whether ctx, as returned by TrackRun, was cancelled
+// through RunManager.Cancel.
+func (t *TrackedRun) Cancelled(ctx context.Context) bool {
+	return t != nil && errors.Is(context.Cause(ctx), upal.ErrRunCancelled)
+}
+
+// Finish records the run's outcome: cancelled when ctx was cancelled through
+// RunManager.Cancel, failed when err is set (blocked for moderation errors),
+// and completed with res otherwise.
+func (t *TrackedRun) Finish(ctx context.Context, res upal.RunResult, err error) {
+	if t == nil {
+		return
+	}
+	defer func() {
+		if t.cancel != nil {
+			t.cancel(nil)
+		}
+	}()
+	cause := context.Cause(ctx)
+	ctx = context.WithoutCancel(ctx)
+
+	switch {
+	case errors.Is(cause, upal.ErrRunCancelled):
+		reason := upal.CancelReason(cause)
+		slog.InfoContext(ctx, "run cancelled", "run_id", t.ID, "reason", reason)
+		t.history.CancelRun(ctx, t.ID, reason, res.State)
+		t.complete(map[string]any{"status": string(upal.RunStatusCancelled), "run_id": t.ID, "cancel_reason": reason})
+	case errors.Is(err, upal.ErrModerationBlocked):
+		t.history.BlockRun(ctx, t.ID, err.Error())
+		t.fail(err.Error())
+	case err != nil:
+		t.history.FailRun(ctx, t.ID, err.Error())
+		t.fail(err.Error())
+	default:
+		t.history.CompleteRunWithErrors(ctx, t.ID, res.State, res.NodeErrors)
+		t.complete(map[string]any{"status": "completed", "session_id": res.SessionID, "run_id": t.ID})
+	}
+}
+
+func (t *TrackedRun) complete(payload map[string]any) {
+	if t.manager != nil {
+		t.manager.Complete(t.ID, payload)
+	}
+}
+
+func (t *TrackedRun) fail(errMsg string) {
+	if t.manager != nil {
+		t.manager.Fail(t.ID, errMsg)
+	}
+}
@@ -2,24 +2,46 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/storage"
 	"github.com/soochol/upal/internal/upal"
 	"github.com/soochol/upal/internal/upal/ports"
 )
 
 var _ ports.RunHistoryPort = (*RunHistoryService)(nil)
 
+// runFeedBuffer is how many changes a run-list subscriber may fall behind
+// before further changes are dropped for it.
+const runFeedBuffer = 64
+
 type RunHistoryService struct {
 	runRepo repository.RunRepository
+	store   storage.Storage
+	events  ports.EventPublisher
+
+	mu          sync.Mutex
+	subscribers map[chan upal.RunChange]struct{}
 }
 
 func NewRunHistoryService(runRepo repository.RunRepository) *RunHistoryService {
 	return &RunHistoryService{runRepo: runRepo}
 }
 
+// SetStorage enables persisting output node results as run artifacts.
+func (s *RunHistoryService) SetStorage(store storage.Storage) { s.store = store }
+
+// SetEventPublisher enables run.completed and run.failed events for
+// finished runs.
+func (s *RunHistoryService) SetEventPublisher(p ports.EventPublisher) { s.events = p }
+
 func (s *RunHistoryService) StartRun(ctx context.Context, workflowName string, triggerType, triggerRef string, inputs map[string]any, wfDef *upal.WorkflowDefinition) (*upal.RunRecord, error) {
 	now := time.Now()
 	record := &upal.RunRecord{
@@ -32,15 +54,76 @@ func (s *RunHistoryService) StartRun(ctx context.Context, workflowName string, t
 		Inputs:       inputs,
 		CreatedAt:    now,
 		StartedAt:    &now,
+		UserID:       upal.UserIDFromContext(ctx),
+	}
+
+	if err := s.runRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, "created", record)
+	return record, nil
+}
+
+// StartRerun records a new manual run of original's workflow with the given
+// inputs, linked back to original via RerunOf.
+func (s *RunHistoryService) StartRerun(ctx context.Context, original *upal.RunRecord, inputs map[string]any, wfDef *upal.WorkflowDefinition) (*upal.RunRecord, error) {
+	now := time.Now()
+	originalID := original.ID
+	record := &upal.RunRecord{
+		ID:           upal.GenerateID("run"),
+		WorkflowName: original.WorkflowName,
+		WorkflowDef:  wfDef,
+		TriggerType:  "manual",
+		Status:       upal.RunStatusRunning,
+		Inputs:       inputs,
+		RerunOf:      &originalID,
+		CreatedAt:    now,
+		StartedAt:    &now,
+		UserID:       upal.UserIDFromContext(ctx),
+	}
+
+	if err := s.runRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, "created", record)
+	return record, nil
+}
+
+// StartReplay records a new run that replays original, a run started by a
+// trigger, with its recorded inputs against wfDef. The trigger type and
+// reference are kept so the replay counts as another attempt of the same
+// delivery; RerunOf links it to deliveryID, the delivery's first run.
+func (s *RunHistoryService) StartReplay(ctx context.Context, original *upal.RunRecord, deliveryID string, wfDef *upal.WorkflowDefinition) (*upal.RunRecord, error) {
+	now := time.Now()
+	record := &upal.RunRecord{
+		ID:           upal.GenerateID("run"),
+		WorkflowName: wfDef.Name,
+		WorkflowDef:  wfDef,
+		TriggerType:  original.TriggerType,
+		TriggerRef:   original.TriggerRef,
+		Status:       upal.RunStatusRunning,
+		Inputs:       original.Inputs,
+		RerunOf:      &deliveryID,
+		CreatedAt:    now,
+		StartedAt:    &now,
+		UserID:       upal.UserIDFromContext(ctx),
 	}
 
 	if err := s.runRepo.Create(ctx, record); err != nil {
 		return nil, err
 	}
+	s.publish(ctx, "created", record)
 	return record, nil
 }
 
 func (s *RunHistoryService) CompleteRun(ctx context.Context, id string, outputs map[string]any) error {
+	return s.CompleteRunWithErrors(ctx, id, outputs, nil)
+}
+
+// CompleteRunWithErrors records a finished run. Node errors absorbed by the
+// best_effort failure mode mark it completed_with_errors; an outputs
+// contract violation still takes precedence.
+func (s *RunHistoryService) CompleteRunWithErrors(ctx context.Context, id string, outputs map[string]any, nodeErrors map[string]string) error {
 	record, err := s.runRepo.Get(ctx, id)
 	if err != nil {
 		return err
@@ -48,9 +131,30 @@ func (s *RunHistoryService) CompleteRun(ctx context.Context, id string, outputs
 
 	now := time.Now()
 	record.Status = upal.RunStatusSuccess
+	record.Progress = 100
 	record.Outputs = outputs
 	record.CompletedAt = &now
-	return s.runRepo.Update(ctx, record)
+	if len(nodeErrors) > 0 {
+		ids := slices.Sorted(maps.Keys(nodeErrors))
+		parts := make([]string, len(ids))
+		for i, nodeID := range ids {
+			parts[i] = fmt.Sprintf("node %q: %s", nodeID, nodeErrors[nodeID])
+		}
+		msg := strings.Join(parts, "; ")
+		record.Status = upal.RunStatusCompletedWithErrors
+		record.Error = &msg
+	}
+	if record.WorkflowDef != nil {
+		if violations := record.WorkflowDef.CheckOutputs(outputs); len(violations) > 0 {
+			msg := upal.ContractError(violations)
+			record.Status = upal.RunStatusContractViolation
+			record.Error = &msg
+		}
+	}
+	if s.store != nil {
+		record.Artifacts = s.saveArtifacts(ctx, id, outputs)
+	}
+	return s.finish(ctx, record)
 }
 
 func (s *RunHistoryService) FailRun(ctx context.Context, id string, errMsg string) error {
@@ -63,7 +167,40 @@ func (s *RunHistoryService) FailRun(ctx context.Context, id string, errMsg strin
 	record.Status = upal.RunStatusFailed
 	record.Error = &errMsg
 	record.CompletedAt = &now
-	return s.runRepo.Update(ctx, record)
+	return s.finish(ctx, record)
+}
+
+// BlockRun ends a run whose inputs were rejected by moderation.
+func (s *RunHistoryService) BlockRun(ctx context.Context, id string, reason string) error {
+	record, err := s.runRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	record.Status = upal.RunStatusBlockedModeration
+	record.Error = &reason
+	record.CompletedAt = &now
+	return s.update(ctx, record)
+}
+
+// CancelRun ends a run that was stopped on request. partial holds the
+// outputs of the nodes that completed before it stopped and is kept as the
+// run's outputs.
+func (s *RunHistoryService) CancelRun(ctx context.Context, id, reason string, partial map[string]any) error {
+	record, err := s.runRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	record.Status = upal.RunStatusCancelled
+	record.CancelReason = reason
+	if len(partial) > 0 {
+		record.Outputs = partial
+	}
+	record.CompletedAt = &now
+	return s.update(ctx, record)
 }
 
 func (s *RunHistoryService) UpdateNodeRun(ctx context.Context, runID string, nodeRun upal.NodeRunRecord) error {
@@ -84,7 +221,27 @@ func (s *RunHistoryService) UpdateNodeRun(ctx context.Context, runID string, nod
 		record.NodeRuns = append(record.NodeRuns, nodeRun)
 	}
 
-	return s.runRepo.Update(ctx, record)
+	return s.update(ctx, record)
+}
+
+// UpdateRunProgress records the percentage of the run's nodes that finished.
+func (s *RunHistoryService) UpdateRunProgress(ctx context.Context, id string, progress int) error {
+	record, err := s.runRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	record.Progress = progress
+	return s.update(ctx, record)
+}
+
+// SetRunContext attaches the execution environment captured at run start.
+func (s *RunHistoryService) SetRunContext(ctx context.Context, id string, rc *upal.RunContext) error {
+	record, err := s.runRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	record.Context = rc
+	return s.update(ctx, record)
 }
 
 func (s *RunHistoryService) UpdateRunRetryMeta(ctx context.Context, id string, retryCount int, retryOf *string) error {
@@ -94,7 +251,7 @@ func (s *RunHistoryService) UpdateRunRetryMeta(ctx context.Context, id string, r
 	}
 	record.RetryCount = retryCount
 	record.RetryOf = retryOf
-	return s.runRepo.Update(ctx, record)
+	return s.update(ctx, record)
 }
 
 func (s *RunHistoryService) GetRun(ctx context.Context, id string) (*upal.RunRecord, error) {
@@ -109,6 +266,92 @@ func (s *RunHistoryService) ListAllRuns(ctx context.Context, limit, offset int,
 	return s.runRepo.ListAll(ctx, limit, offset, status)
 }
 
+// ListAllRunsAfter pages through all runs newest first, starting after the
+// given cursor. Unlike offset paging, pages neither repeat nor skip runs as
+// new ones are recorded.
+func (s *RunHistoryService) ListAllRunsAfter(ctx context.Context, status string, after *upal.RunCursor, limit int) ([]*upal.RunRecord, error) {
+	return s.runRepo.ListAllAfter(ctx, status, after, limit)
+}
+
+func (s *RunHistoryService) SearchRuns(ctx context.Context, query string, limit int) ([]*upal.RunRecord, error) {
+	return s.runRepo.Search(ctx, query, limit)
+}
+
+// SubscribeRuns returns a feed of run records as they are created and
+// updated, and a function that ends the subscription. A subscriber that falls
+// more than runFeedBuffer changes behind misses the ones in between.
+func (s *RunHistoryService) SubscribeRuns() (<-chan upal.RunChange, func()) {
+	ch := make(chan upal.RunChange, runFeedBuffer)
+	s.mu.Lock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[chan upal.RunChange]struct{})
+	}
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, ch)
+			s.mu.Unlock()
+		})
+	}
+}
+
+// update persists record and publishes it to run-list subscribers.
+func (s *RunHistoryService) update(ctx context.Context, record *upal.RunRecord) error {
+	if err := s.runRepo.Update(ctx, record); err != nil {
+		return err
+	}
+	s.publish(ctx, "updated", record)
+	return nil
+}
+
+// finish stores a run that reached a terminal status and publishes its
+// outbound event.
+func (s *RunHistoryService) finish(ctx context.Context, record *upal.RunRecord) error {
+	if err := s.update(ctx, record); err != nil {
+		return err
+	}
+	if s.events != nil {
+		if ev, ok := runEvent(record); ok {
+			s.events.Publish(ownerContext(ctx, record), ev)
+		}
+	}
+	return nil
+}
+
+// ownerContext scopes ctx to the user who started record, when known.
+func ownerContext(ctx context.Context, record *upal.RunRecord) context.Context {
+	if record.UserID == "" {
+		return ctx
+	}
+	return upal.WithUserID(ctx, record.UserID)
+}
+
+// publish sends a snapshot of record to every subscriber without blocking.
+// The workflow definition is left out to keep the feed light; clients fetch
+// the full record when they need it.
+func (s *RunHistoryService) publish(ctx context.Context, changeType string, record *upal.RunRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.subscribers) == 0 {
+		return
+	}
+	snapshot := *record
+	snapshot.WorkflowDef = nil
+	snapshot.NodeRuns = slices.Clone(record.NodeRuns)
+	snapshot.Artifacts = slices.Clone(record.Artifacts)
+	change := upal.RunChange{Type: changeType, Run: &snapshot, UserID: upal.UserIDFromContext(ownerContext(ctx, record))}
+	for ch := range s.subscribers {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
 // CleanupOrphanedRuns marks all running/pending runs as failed on startup.
 func (s *RunHistoryService) CleanupOrphanedRuns(ctx context.Context) {
 	type orphanCleaner interface {
@@ -56,6 +56,81 @@ func TestRunHistoryService_StartAndComplete(t *testing.T) {
 	}
 }
 
+func TestRunHistoryService_CompleteRunChecksOutputsContract(t *testing.T) {
+	wf := &upal.WorkflowDefinition{
+		Name: "contract-wf",
+		Outputs: map[string]upal.OutputType{
+			"summary": upal.OutputTypeString,
+			"score":   upal.OutputTypeNumber,
+			"tags":    upal.OutputTypeArray,
+		},
+	}
+	tests := []struct {
+		name       string
+		outputs    map[string]any
+		wantStatus upal.RunStatus
+		wantErr    string
+	}{
+		{
+			name:       "conforming",
+			outputs:    map[string]any{"summary": "fine", "score": "0.9", "tags": []any{"a"}},
+			wantStatus: upal.RunStatusSuccess,
+		},
+		{
+			name:       "violating",
+			outputs:    map[string]any{"summary": "fine", "score": "high"},
+			wantStatus: upal.RunStatusContractViolation,
+			wantErr:    "outputs contract violated: score: expected number, got string; tags: missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewRunHistoryService(repository.NewMemoryRunRepository())
+			ctx := context.Background()
+			record, err := svc.StartRun(ctx, wf.Name, "manual", "", nil, wf)
+			if err != nil {
+				t.Fatalf("StartRun: %v", err)
+			}
+			if err := svc.CompleteRun(ctx, record.ID, tt.outputs); err != nil {
+				t.Fatalf("CompleteRun: %v", err)
+			}
+
+			got, _ := svc.GetRun(ctx, record.ID)
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			gotErr := ""
+			if got.Error != nil {
+				gotErr = *got.Error
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("error = %q, want %q", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunHistoryService_CompleteRunWithErrors(t *testing.T) {
+	svc := NewRunHistoryService(repository.NewMemoryRunRepository())
+	ctx := context.Background()
+	record, _ := svc.StartRun(ctx, "report", "manual", "", nil, nil)
+
+	errs := map[string]string{"fetch": "timeout", "chart": "bad data"}
+	if err := svc.CompleteRunWithErrors(ctx, record.ID, map[string]any{"summary": "partial"}, errs); err != nil {
+		t.Fatalf("CompleteRunWithErrors: %v", err)
+	}
+	got, _ := svc.GetRun(ctx, record.ID)
+	if got.Status != upal.RunStatusCompletedWithErrors {
+		t.Errorf("status = %s, want completed_with_errors", got.Status)
+	}
+	if got.Error == nil || *got.Error != `node "chart": bad data; node "fetch": timeout` {
+		t.Errorf("error = %v", got.Error)
+	}
+	if got.Outputs["summary"] != "partial" || got.Progress != 100 {
+		t.Errorf("outputs/progress not recorded: %+v", got)
+	}
+}
+
 func TestRunHistoryService_StartAndFail(t *testing.T) {
 	repo := repository.NewMemoryRunRepository()
 	svc := NewRunHistoryService(repo)
@@ -161,3 +236,25 @@ func TestRunHistoryService_UpdateNodeRun(t *testing.T) {
 		t.Fatalf("expected completed, got %s", got.NodeRuns[0].Status)
 	}
 }
+
+func TestRunHistoryService_SubscribeRunsTagsChangesWithOwner(t *testing.T) {
+	svc := NewRunHistoryService(repository.NewMemoryRunRepository())
+	changes, unsubscribe := svc.SubscribeRuns()
+	defer unsubscribe()
+
+	record, err := svc.StartRun(upal.WithUserID(context.Background(), "alice"), "wf", "manual", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Node progress and completion are recorded from a background context.
+	if err := svc.CompleteRun(context.Background(), record.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"created", "updated"} {
+		change := <-changes
+		if change.Type != want || change.UserID != "alice" {
+			t.Errorf("change: got %s for %q, want %s for alice", change.Type, change.UserID, want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +20,13 @@ type runEntry struct {
 	donePayload map[string]any
 	subs        []chan struct{}
 	completedAt time.Time
+
+	startedAt   time.Time
+	workflow    string
+	currentNode string
+	cancel      context.CancelCauseFunc
+	// pendingCancel holds the reason of a Cancel that arrived before Attach.
+	pendingCancel *string
 }
 
 func (e *runEntry) snapshot(startSeq int) (events []upal.EventRecord, notify <-chan struct{}, done bool, donePayload map[string]any) {
@@ -59,10 +69,96 @@ func (rm *RunManager) Stop() {
 
 func (rm *RunManager) Register(runID string) {
 	rm.mu.Lock()
-	rm.runs[runID] = &runEntry{}
+	rm.runs[runID] = &runEntry{startedAt: time.Now()}
 	rm.mu.Unlock()
 }
 
+func (rm *RunManager) Attach(runID, workflowName string, cancel context.CancelCauseFunc) {
+	rm.mu.RLock()
+	entry, ok := rm.runs[runID]
+	rm.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	entry.mu.Lock()
+	entry.workflow = workflowName
+	entry.cancel = cancel
+	pending := entry.pendingCancel
+	entry.pendingCancel = nil
+	entry.mu.Unlock()
+
+	if pending != nil {
+		cancel(&upal.RunCancelledError{Reason: *pending})
+	}
+}
+
+// Track registers runID as executing workflowName and returns a context that
+// Cancel cancels. Runs executed outside RunPublisher.Launch use it so they are
+// listed as active and can be cancelled; the caller reports the outcome with
+// Complete or Fail and then releases the returned cancel function.
+func (rm *RunManager) Track(ctx context.Context, runID, workflowName string) (context.Context, context.CancelCauseFunc) {
+	rm.Register(runID)
+	ctx, cancel := context.WithCancelCause(ctx)
+	rm.Attach(runID, workflowName, cancel)
+	return ctx, cancel
+}
+
+// Active returns the runs that have not finished, oldest first.
+func (rm *RunManager) Active() []upal.ActiveRun {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	active := make([]upal.ActiveRun, 0)
+	for id, entry := range rm.runs {
+		entry.mu.RLock()
+		if !entry.done {
+			active = append(active, upal.ActiveRun{
+				RunID:        id,
+				WorkflowName: entry.workflow,
+				StartedAt:    entry.startedAt,
+				CurrentNode:  entry.currentNode,
+			})
+		}
+		entry.mu.RUnlock()
+	}
+	slices.SortFunc(active, func(a, b upal.ActiveRun) int {
+		if c := a.StartedAt.Compare(b.StartedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.RunID, b.RunID)
+	})
+	return active
+}
+
+// Cancel stops a run, recording reason as the cancellation cause. A run that
+// is registered but not yet attached, such as one waiting for a concurrency
+// slot, is cancelled as soon as it attaches. It reports false when the run is
+// unknown or already finished.
+func (rm *RunManager) Cancel(runID, reason string) bool {
+	rm.mu.RLock()
+	entry, ok := rm.runs[runID]
+	rm.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	entry.mu.Lock()
+	if entry.done {
+		entry.mu.Unlock()
+		return false
+	}
+	cancel := entry.cancel
+	if cancel == nil {
+		entry.pendingCancel = &reason
+	}
+	entry.mu.Unlock()
+	if cancel != nil {
+		cancel(&upal.RunCancelledError{Reason: reason})
+	}
+	return true
+}
+
 func (rm *RunManager) Append(runID string, ev upal.EventRecord) {
 	rm.mu.RLock()
 	entry, ok := rm.runs[runID]
@@ -74,6 +170,9 @@ func (rm *RunManager) Append(runID string, ev upal.EventRecord) {
 	entry.mu.Lock()
 	ev.Seq = len(entry.events)
 	entry.events = append(entry.events, ev)
+	if ev.Type == upal.EventNodeStarted {
+		entry.currentNode = ev.NodeID
+	}
 	subs := entry.subs
 	entry.subs = nil
 	entry.mu.Unlock()
@@ -95,6 +194,7 @@ func (rm *RunManager) Complete(runID string, payload map[string]any) {
 	entry.done = true
 	entry.donePayload = payload
 	entry.completedAt = time.Now()
+	entry.cancel = nil
 	subs := entry.subs
 	entry.subs = nil
 	entry.mu.Unlock()
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestRunManager_SubscribeResumesAfterSeq(t *testing.T) {
+	rm := NewRunManager(time.Hour)
+	defer rm.Stop()
+	rm.Register("r1")
+	for i := 0; i < 5; i++ {
+		rm.Append("r1", upal.EventRecord{WorkflowEvent: upal.WorkflowEvent{Type: upal.EventNodeStarted, NodeID: "n"}})
+	}
+
+	events, notify, done, _, found := rm.Subscribe("r1", 3)
+	if !found || done {
+		t.Fatalf("found=%v done=%v, want running run", found, done)
+	}
+	if len(events) != 2 || events[0].Seq != 3 || events[1].Seq != 4 {
+		t.Fatalf("events after seq 2 = %+v, want seqs 3,4", events)
+	}
+
+	rm.Append("r1", upal.EventRecord{WorkflowEvent: upal.WorkflowEvent{Type: upal.EventNodeCompleted, NodeID: "n"}})
+	select {
+	case <-notify:
+	case <-time.After(time.Second):
+		t.Fatal("subscriber was not notified of the new event")
+	}
+	events, _, _, _, _ = rm.Subscribe("r1", 5)
+	if len(events) != 1 || events[0].Seq != 5 {
+		t.Fatalf("events after seq 4 = %+v, want seq 5", events)
+	}
+
+	// A client that has seen everything gets nothing replayed.
+	if events, _, _, _, _ = rm.Subscribe("r1", 6); len(events) != 0 {
+		t.Errorf("expected no events past the end, got %+v", events)
+	}
+}
+
+func TestRunManager_CancelBeforeAttachIsQueued(t *testing.T) {
+	rm := NewRunManager(time.Hour)
+	defer rm.Stop()
+	rm.Register("r1")
+
+	// A run waiting for a concurrency slot has no cancel function yet.
+	if !rm.Cancel("r1", "not needed") {
+		t.Fatal("cancel of a registered run was refused")
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	rm.Attach("r1", "wf", cancel)
+	if cause := context.Cause(ctx); !errors.Is(cause, upal.ErrRunCancelled) || upal.CancelReason(cause) != "not needed" {
+		t.Fatalf("cause after attach = %v, want the queued cancellation", cause)
+	}
+
+	rm.Complete("r1", nil)
+	if rm.Cancel("r1", "again") {
+		t.Error("cancel of a finished run was accepted")
+	}
+}
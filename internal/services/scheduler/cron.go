@@ -1,23 +1,93 @@
 package scheduler
 
 import (
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/soochol/upal/internal/upal"
 )
 
+// cronPrecision reports whether expr is a 6-field (seconds) or 5-field
+// (minutes) expression. Any other field count is rejected up front so a
+// mistyped expression is never silently read in the other format.
+func cronPrecision(expr string) (string, error) {
+	fields := strings.Fields(expr)
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "CRON_TZ=") || strings.HasPrefix(fields[0], "TZ=")) {
+		fields = fields[1:]
+	}
+	switch len(fields) {
+	case 6:
+		return upal.CronPrecisionSeconds, nil
+	case 5:
+		return upal.CronPrecisionMinutes, nil
+	default:
+		return "", fmt.Errorf("invalid cron expression %q: expected 5 or 6 fields, got %d", expr, len(fields))
+	}
+}
+
 func parseCronExpr(expr string, timezone string) (cron.Schedule, error) {
+	precision, err := cronPrecision(expr)
+	if err != nil {
+		return nil, err
+	}
+	fields := cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow
+	if precision == upal.CronPrecisionSeconds {
+		fields |= cron.Second
+	}
+
+	spec := expr
 	if timezone != "" && timezone != "UTC" {
-		expr = "CRON_TZ=" + timezone + " " + expr
+		spec = "CRON_TZ=" + timezone + " " + spec
+	}
+	sched, err := cron.NewParser(fields).Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s-precision cron expression %q: %w", precision, expr, err)
 	}
-	parser6 := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	sched, err := parser6.Parse(expr)
-	if err == nil {
-		return sched, nil
+	return sched, nil
+}
+
+// scheduledTick returns the activation of sched that is firing at now: the
+// latest one not after now. Instances whose clocks or cron goroutines lag
+// each other slightly still agree on it. A job running more than a minute
+// late falls back to now.
+func scheduledTick(sched cron.Schedule, now time.Time) time.Time {
+	tick := sched.Next(now.Add(-time.Minute))
+	if tick.After(now) {
+		return now.Truncate(time.Second)
 	}
-	parser5 := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	return parser5.Parse(expr)
+	for next := sched.Next(tick); !next.After(now); next = sched.Next(tick) {
+		tick = next
+	}
+	return tick
+}
+
+// ValidateCronExpr reports whether expr (5 or 6 fields) parses in timezone.
+func ValidateCronExpr(expr, timezone string) error {
+	_, err := parseCronExpr(expr, timezone)
+	return err
+}
+
+// withCronPrecision fills in the derived CronPrecision field for responses.
+func withCronPrecision(schedules ...*upal.Schedule) {
+	for _, sched := range schedules {
+		sched.CronPrecision, _ = cronPrecision(sched.CronExpr)
+	}
+}
+
+// validateWindows checks the schedule's blackout windows and active hours.
+func validateWindows(schedule *upal.Schedule) error {
+	for _, b := range schedule.Blackout {
+		if err := b.Validate(); err != nil {
+			return err
+		}
+	}
+	if schedule.ActiveHours != nil {
+		return schedule.ActiveHours.Validate()
+	}
+	return nil
 }
 
 func (s *SchedulerService) registerCronJob(schedule *upal.Schedule) error {
@@ -27,7 +97,7 @@ func (s *SchedulerService) registerCronJob(schedule *upal.Schedule) error {
 	}
 
 	entryID := s.cron.Schedule(cronSched, cron.FuncJob(func() {
-		s.executeScheduledRun(schedule)
+		s.executeScheduledRun(schedule, scheduledTick(cronSched, time.Now()))
 	}))
 
 	s.mu.Lock()
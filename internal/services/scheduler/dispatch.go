@@ -2,15 +2,48 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/soochol/upal/internal/repository"
 	"github.com/soochol/upal/internal/upal"
 )
 
-func (s *SchedulerService) executeScheduledRun(schedule *upal.Schedule) {
-	ctx := context.Background()
+// executeScheduledRun runs or queues one tick of schedule. tick is the cron
+// activation being run, or zero for TriggerNow.
+func (s *SchedulerService) executeScheduledRun(schedule *upal.Schedule, tick time.Time) {
+	ctx := upal.WithRunID(context.Background(), upal.GenerateID("sched"))
 
+	if s.maintenance != nil && s.maintenance.InMaintenance() {
+		slog.InfoContext(ctx, "scheduler: maintenance mode, skipping tick",
+			"schedule", schedule.ID, "workflow", schedule.WorkflowName, "pipeline", schedule.PipelineID)
+		s.recordSkip(ctx, schedule, upal.ScheduleOutcomeSkippedMaintenance)
+		return
+	}
+
+	if schedule.InBlackout(time.Now()) {
+		s.skipBlackout(ctx, schedule)
+		return
+	}
+
+	if !schedule.IsActive(time.Now()) {
+		slog.InfoContext(ctx, "scheduler: tick outside active hours, skipping",
+			"schedule", schedule.ID, "workflow", schedule.WorkflowName, "pipeline", schedule.PipelineID)
+		s.recordSkip(ctx, schedule, upal.ScheduleOutcomeSkippedInactive)
+		return
+	}
+
+	if s.runQueue != nil {
+		s.enqueue(ctx, schedule, tick)
+		return
+	}
+	s.dispatch(ctx, schedule)
+}
+
+// dispatch runs the schedule's pipeline or workflow.
+func (s *SchedulerService) dispatch(ctx context.Context, schedule *upal.Schedule) {
 	if schedule.PipelineID != "" && s.pipelineSvc != nil && s.pipelineRunner != nil {
 		s.executePipelineRun(ctx, schedule)
 		return
@@ -20,23 +53,23 @@ func (s *SchedulerService) executeScheduledRun(schedule *upal.Schedule) {
 }
 
 func (s *SchedulerService) executePipelineRun(ctx context.Context, schedule *upal.Schedule) {
-	slog.Info("scheduler: executing scheduled pipeline run",
+	slog.InfoContext(ctx, "scheduler: executing scheduled pipeline run",
 		"schedule", schedule.ID, "pipeline", schedule.PipelineID)
 
 	pipeline, err := s.pipelineSvc.Get(ctx, schedule.PipelineID)
 	if err != nil {
-		slog.Error("scheduler: pipeline not found",
+		slog.ErrorContext(ctx, "scheduler: pipeline not found",
 			"schedule", schedule.ID, "pipeline", schedule.PipelineID, "err", err)
 		return
 	}
 
 	if s.contentCollector != nil {
 		if err := s.contentCollector.CollectPipeline(ctx, schedule.PipelineID); err != nil {
-			slog.Error("scheduler: content pipeline collection failed",
+			slog.ErrorContext(ctx, "scheduler: content pipeline collection failed",
 				"schedule", schedule.ID, "pipeline", schedule.PipelineID, "err", err)
 		}
 	} else if _, err := s.pipelineRunner.Start(ctx, pipeline, nil); err != nil {
-		slog.Error("scheduler: pipeline execution failed",
+		slog.ErrorContext(ctx, "scheduler: pipeline execution failed",
 			"schedule", schedule.ID, "pipeline", schedule.PipelineID, "err", err)
 	}
 
@@ -44,22 +77,31 @@ func (s *SchedulerService) executePipelineRun(ctx context.Context, schedule *upa
 }
 
 func (s *SchedulerService) executeWorkflowRun(ctx context.Context, schedule *upal.Schedule) {
-	slog.Info("scheduler: executing scheduled run",
+	slog.InfoContext(ctx, "scheduler: executing scheduled run",
 		"schedule", schedule.ID, "workflow", schedule.WorkflowName)
 
 	if err := s.limiter.Acquire(ctx, schedule.WorkflowName); err != nil {
-		slog.Warn("scheduler: concurrency limit reached, skipping",
+		slog.WarnContext(ctx, "scheduler: concurrency limit reached, skipping",
 			"schedule", schedule.ID, "err", err)
 		return
 	}
 	defer s.limiter.Release(schedule.WorkflowName)
 
 	wf, err := s.workflowExec.Lookup(ctx, schedule.WorkflowName)
+	if errors.Is(err, repository.ErrNotFound) {
+		slog.ErrorContext(ctx, "scheduler: workflow not found",
+			"schedule", schedule.ID, "workflow", schedule.WorkflowName, "err", err)
+		s.recordMissingWorkflow(ctx, schedule)
+		return
+	}
 	if err != nil {
-		slog.Error("scheduler: workflow not found",
+		// A lookup that failed for any other reason says nothing about the
+		// workflow, so the tick is skipped without counting toward auto-pause.
+		slog.ErrorContext(ctx, "scheduler: workflow lookup failed",
 			"schedule", schedule.ID, "workflow", schedule.WorkflowName, "err", err)
 		return
 	}
+	schedule.ConsecutiveFailures = 0
 
 	policy := upal.DefaultRetryPolicy()
 	if schedule.RetryPolicy != nil {
@@ -71,7 +113,7 @@ func (s *SchedulerService) executeWorkflowRun(ctx context.Context, schedule *upa
 		string(upal.TriggerCron), schedule.ID,
 	)
 	if err != nil {
-		slog.Error("scheduler: execution failed",
+		slog.ErrorContext(ctx, "scheduler: execution failed",
 			"schedule", schedule.ID, "err", err)
 		return
 	}
@@ -81,26 +123,75 @@ func (s *SchedulerService) executeWorkflowRun(ctx context.Context, schedule *upa
 
 	res, ok := <-result
 	if ok {
-		slog.Info("scheduler: run completed",
+		slog.InfoContext(ctx, "scheduler: run completed",
 			"schedule", schedule.ID, "session", res.SessionID)
 	} else {
-		slog.Warn("scheduler: run result channel closed without value",
+		slog.WarnContext(ctx, "scheduler: run result channel closed without value",
 			"schedule", schedule.ID)
 	}
 
 	s.updateScheduleTimestamps(ctx, schedule)
 }
 
+// recordMissingWorkflow counts a "workflow not found" failure and pauses the
+// schedule once the configured threshold of consecutive failures is reached.
+func (s *SchedulerService) recordMissingWorkflow(ctx context.Context, schedule *upal.Schedule) {
+	schedule.ConsecutiveFailures++
+	schedule.UpdatedAt = time.Now()
+
+	if s.autoPauseAfter > 0 && schedule.ConsecutiveFailures >= s.autoPauseAfter {
+		s.mu.Lock()
+		if entryID, ok := s.entryMap[schedule.ID]; ok {
+			s.cron.Remove(entryID)
+			delete(s.entryMap, schedule.ID)
+		}
+		s.mu.Unlock()
+
+		schedule.Enabled = false
+		schedule.PausedReason = fmt.Sprintf("auto-paused after %d consecutive failures: workflow %q not found",
+			schedule.ConsecutiveFailures, schedule.WorkflowName)
+		slog.WarnContext(ctx, "scheduler: schedule auto-paused",
+			"schedule", schedule.ID, "workflow", schedule.WorkflowName, "failures", schedule.ConsecutiveFailures)
+	}
+
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		slog.WarnContext(ctx, "scheduler: failed to record schedule failure", "err", err)
+	}
+}
+
+// skipBlackout records a tick suppressed by a blackout window. LastRunAt is
+// left untouched since nothing ran.
+func (s *SchedulerService) skipBlackout(ctx context.Context, schedule *upal.Schedule) {
+	slog.InfoContext(ctx, "scheduler: tick inside blackout window, skipping",
+		"schedule", schedule.ID, "workflow", schedule.WorkflowName, "pipeline", schedule.PipelineID)
+	s.recordSkip(ctx, schedule, upal.ScheduleOutcomeSkippedBlackout)
+}
+
+// recordSkip stores a tick that was suppressed with the given outcome.
+func (s *SchedulerService) recordSkip(ctx context.Context, schedule *upal.Schedule, outcome upal.ScheduleOutcome) {
+	now := time.Now()
+	schedule.LastOutcome = outcome
+	schedule.UpdatedAt = now
+	if cronSched, err := parseCronExpr(schedule.CronExpr, schedule.Timezone); err == nil {
+		schedule.NextRunAt = cronSched.Next(now)
+	}
+
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		slog.WarnContext(ctx, "scheduler: failed to record skipped tick", "outcome", outcome, "err", err)
+	}
+}
+
 func (s *SchedulerService) updateScheduleTimestamps(ctx context.Context, schedule *upal.Schedule) {
 	now := time.Now()
 	schedule.LastRunAt = &now
 	schedule.UpdatedAt = now
+	schedule.LastOutcome = upal.ScheduleOutcomeExecuted
 
 	if cronSched, err := parseCronExpr(schedule.CronExpr, schedule.Timezone); err == nil {
 		schedule.NextRunAt = cronSched.Next(now)
 	}
 
 	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
-		slog.Warn("scheduler: failed to update schedule timestamps", "err", err)
+		slog.WarnContext(ctx, "scheduler: failed to update schedule timestamps", "err", err)
 	}
 }
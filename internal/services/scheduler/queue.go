@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+const (
+	// defaultQueueLease is how long a claimed tick stays leased without a
+	// heartbeat when SetRunQueue is given no lease.
+	defaultQueueLease = time.Minute
+	// queuePollInterval is how often an idle worker looks for claimable ticks.
+	queuePollInterval = time.Second
+	// maxQueueAttempts drops a tick whose workers keep dying on it.
+	maxQueueAttempts = 3
+)
+
+// SetRunQueue routes scheduled ticks through a persistent queue instead of
+// executing them in the cron goroutine. A worker loop started by Start claims
+// ticks under a lease renewed while the run is in progress; when a worker
+// dies its lease expires and another worker re-runs the tick.
+func (s *SchedulerService) SetRunQueue(q repository.RunQueueRepository, lease time.Duration) {
+	if lease <= 0 {
+		lease = defaultQueueLease
+	}
+	s.runQueue = q
+	s.queueLease = lease
+	s.queuePoll = queuePollInterval
+	s.workerID = upal.GenerateID("worker")
+}
+
+// enqueue stores a tick for the worker loop. Every instance's cron fires the
+// same tick, so a cron tick is keyed by schedule and activation time and only
+// the first instance to enqueue it wins. If the queue is unavailable the tick
+// runs in-process so it is not lost.
+func (s *SchedulerService) enqueue(ctx context.Context, schedule *upal.Schedule, tick time.Time) {
+	id := upal.GenerateID("queued")
+	if !tick.IsZero() {
+		id = fmt.Sprintf("tick-%s-%d", schedule.ID, tick.Unix())
+	}
+	item := &upal.QueuedRun{
+		ID:         id,
+		ScheduleID: schedule.ID,
+		EnqueuedAt: time.Now(),
+	}
+	err := s.runQueue.Enqueue(ctx, item)
+	if errors.Is(err, repository.ErrAlreadyQueued) {
+		slog.DebugContext(ctx, "scheduler: tick already queued", "schedule", schedule.ID, "queued_run", item.ID)
+		return
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "scheduler: enqueue failed, running in-process",
+			"schedule", schedule.ID, "err", err)
+		s.dispatch(ctx, schedule)
+		return
+	}
+	slog.InfoContext(ctx, "scheduler: tick queued", "schedule", schedule.ID, "queued_run", item.ID)
+}
+
+// startQueueWorker claims queued ticks until Stop is called.
+func (s *SchedulerService) startQueueWorker() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopQueue = cancel
+	s.queueWG.Add(1)
+	go func() {
+		defer s.queueWG.Done()
+		ticker := time.NewTicker(s.queuePoll)
+		defer ticker.Stop()
+		for {
+			s.claimQueued(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// claimQueued starts every tick claimable right now.
+func (s *SchedulerService) claimQueued(ctx context.Context) {
+	for ctx.Err() == nil {
+		item, err := s.runQueue.Claim(ctx, s.workerID, s.queueLease, time.Now())
+		if err != nil {
+			slog.Warn("scheduler: claim queued run failed", "err", err)
+			return
+		}
+		if item == nil {
+			return
+		}
+		s.queueWG.Add(1)
+		go func() {
+			defer s.queueWG.Done()
+			s.processQueued(item)
+		}()
+	}
+}
+
+// processQueued runs one claimed tick and removes it from the queue. Runs
+// are not tied to the worker loop's lifetime: Stop waits for them to finish.
+func (s *SchedulerService) processQueued(item *upal.QueuedRun) {
+	ctx := upal.WithRunID(context.Background(), upal.GenerateID("sched"))
+	if item.Attempts > maxQueueAttempts {
+		slog.ErrorContext(ctx, "scheduler: dropping queued run after repeated lease expiry",
+			"queued_run", item.ID, "schedule", item.ScheduleID, "attempts", item.Attempts)
+		s.completeQueued(ctx, item)
+		return
+	}
+	if item.Attempts > 1 {
+		slog.WarnContext(ctx, "scheduler: re-running queued run after lease expiry",
+			"queued_run", item.ID, "schedule", item.ScheduleID, "attempt", item.Attempts)
+	}
+
+	schedule, err := s.scheduleRepo.Get(ctx, item.ScheduleID)
+	if err != nil {
+		slog.WarnContext(ctx, "scheduler: queued run's schedule not found, dropping",
+			"queued_run", item.ID, "schedule", item.ScheduleID, "err", err)
+		s.completeQueued(ctx, item)
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.heartbeat(runCtx, item, cancel)
+
+	s.dispatch(runCtx, schedule)
+	s.completeQueued(ctx, item)
+}
+
+// heartbeat renews item's lease until ctx ends. If another worker has taken
+// the lease over, the local run is cancelled since it is now a duplicate.
+func (s *SchedulerService) heartbeat(ctx context.Context, item *upal.QueuedRun, cancel context.CancelFunc) {
+	ticker := time.NewTicker(s.queueLease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := s.runQueue.Renew(ctx, item.ID, s.workerID, time.Now().Add(s.queueLease))
+		if errors.Is(err, repository.ErrLeaseLost) {
+			slog.WarnContext(ctx, "scheduler: queued run lease lost, cancelling", "queued_run", item.ID)
+			cancel()
+			return
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "scheduler: renew queued run lease failed", "queued_run", item.ID, "err", err)
+		}
+	}
+}
+
+func (s *SchedulerService) completeQueued(ctx context.Context, item *upal.QueuedRun) {
+	if err := s.runQueue.Complete(ctx, item.ID, s.workerID); err != nil {
+		slog.WarnContext(ctx, "scheduler: complete queued run failed", "queued_run", item.ID, "err", err)
+	}
+}
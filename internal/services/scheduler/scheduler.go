@@ -3,6 +3,8 @@ package scheduler
 import (
 	"context"
 	"log/slog"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -16,19 +18,35 @@ var _ ports.SchedulerPort = (*SchedulerService)(nil)
 
 // SchedulerService manages cron-based workflow and pipeline scheduling.
 type SchedulerService struct {
-	cron           *cron.Cron
-	scheduleRepo   repository.ScheduleRepository
-	workflowExec   ports.WorkflowExecutor
-	retryExecutor  ports.RetryExecutor
-	limiter        ports.ConcurrencyControl
-	runHistorySvc  ports.RunHistoryPort
-	entryMap       map[string]cron.EntryID // schedule ID → cron entry
-	mu             sync.RWMutex
-	pipelineRunner     ports.PipelineRunner
-	pipelineSvc        ports.PipelineRegistry
-	contentCollector   ContentCollector
+	cron             *cron.Cron
+	scheduleRepo     repository.ScheduleRepository
+	workflowExec     ports.WorkflowExecutor
+	retryExecutor    ports.RetryExecutor
+	limiter          ports.ConcurrencyControl
+	runHistorySvc    ports.RunHistoryPort
+	entryMap         map[string]cron.EntryID // schedule ID → cron entry
+	mu               sync.RWMutex
+	pipelineRunner   ports.PipelineRunner
+	pipelineSvc      ports.PipelineRegistry
+	contentCollector ContentCollector
+	autoPauseAfter   int
+	defaultTimezone  string
+	audit            ports.AuditRecorder
+	maintenance      ports.MaintenanceGate
+
+	// Persistent run queue; nil runs ticks directly in the cron goroutine.
+	runQueue   repository.RunQueueRepository
+	queueLease time.Duration
+	queuePoll  time.Duration
+	workerID   string
+	stopQueue  context.CancelFunc
+	queueWG    sync.WaitGroup
 }
 
+// defaultAutoPauseAfter is how many consecutive "workflow not found" failures
+// pause a schedule when no threshold is configured.
+const defaultAutoPauseAfter = 5
+
 type ContentCollector interface {
 	CollectPipeline(ctx context.Context, pipelineID string) error
 }
@@ -45,6 +63,42 @@ func (s *SchedulerService) SetContentCollector(c ContentCollector) {
 	s.contentCollector = c
 }
 
+// SetAutoPauseAfter sets how many consecutive "workflow not found" failures
+// auto-pause a schedule. Zero or negative disables auto-pausing.
+func (s *SchedulerService) SetAutoPauseAfter(n int) {
+	s.autoPauseAfter = n
+}
+
+// SetDefaultTimezone sets the timezone given to new schedules that do not
+// specify one. Empty keeps UTC.
+func (s *SchedulerService) SetDefaultTimezone(tz string) {
+	s.defaultTimezone = tz
+}
+
+// SetAuditRecorder records schedule creates, updates and deletes in the audit log.
+func (s *SchedulerService) SetAuditRecorder(r ports.AuditRecorder) {
+	s.audit = r
+}
+
+// SetMaintenanceGate makes ticks during maintenance mode be skipped and
+// recorded instead of executed.
+func (s *SchedulerService) SetMaintenanceGate(g ports.MaintenanceGate) {
+	s.maintenance = g
+}
+
+// recordAudit is a no-op when no audit recorder is configured.
+func (s *SchedulerService) recordAudit(ctx context.Context, action upal.AuditAction, id string, changes []upal.AuditChange) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, &upal.AuditEntry{
+		Action:       action,
+		ResourceType: "schedule",
+		ResourceID:   id,
+		Changes:      changes,
+	})
+}
+
 func NewSchedulerService(
 	scheduleRepo repository.ScheduleRepository,
 	workflowExec ports.WorkflowExecutor,
@@ -53,13 +107,14 @@ func NewSchedulerService(
 	runHistorySvc ports.RunHistoryPort,
 ) *SchedulerService {
 	return &SchedulerService{
-		cron:          cron.New(cron.WithSeconds()),
-		scheduleRepo:  scheduleRepo,
-		workflowExec:  workflowExec,
-		retryExecutor: retryExecutor,
-		limiter:       limiter,
-		runHistorySvc: runHistorySvc,
-		entryMap:      make(map[string]cron.EntryID),
+		cron:           cron.New(cron.WithSeconds()),
+		scheduleRepo:   scheduleRepo,
+		workflowExec:   workflowExec,
+		retryExecutor:  retryExecutor,
+		limiter:        limiter,
+		runHistorySvc:  runHistorySvc,
+		entryMap:       make(map[string]cron.EntryID),
+		autoPauseAfter: defaultAutoPauseAfter,
 	}
 }
 
@@ -80,6 +135,9 @@ func (s *SchedulerService) Start(ctx context.Context) error {
 	}
 
 	s.cron.Start()
+	if s.runQueue != nil {
+		s.startQueueWorker()
+	}
 	slog.Info("scheduler: started")
 	return nil
 }
@@ -87,27 +145,39 @@ func (s *SchedulerService) Start(ctx context.Context) error {
 func (s *SchedulerService) Stop() {
 	ctx := s.cron.Stop()
 	<-ctx.Done()
+	if s.stopQueue != nil {
+		s.stopQueue()
+		s.queueWG.Wait()
+	}
 	slog.Info("scheduler: stopped")
 }
 
 func (s *SchedulerService) AddSchedule(ctx context.Context, schedule *upal.Schedule) error {
+	if schedule.Timezone == "" {
+		schedule.Timezone = s.defaultTimezone
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
 	cronSched, err := parseCronExpr(schedule.CronExpr, schedule.Timezone)
 	if err != nil {
 		return err
 	}
+	if err := validateWindows(schedule); err != nil {
+		return err
+	}
 
 	now := time.Now()
 	schedule.ID = upal.GenerateID("sched")
 	schedule.NextRunAt = cronSched.Next(now)
+	withCronPrecision(schedule)
 	schedule.CreatedAt = now
 	schedule.UpdatedAt = now
-	if schedule.Timezone == "" {
-		schedule.Timezone = "UTC"
-	}
 
 	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
 		return err
 	}
+	s.recordAudit(ctx, upal.AuditCreate, schedule.ID, nil)
 
 	if schedule.Enabled {
 		return s.registerCronJob(schedule)
@@ -115,6 +185,37 @@ func (s *SchedulerService) AddSchedule(ctx context.Context, schedule *upal.Sched
 	return nil
 }
 
+// CopySchedule creates a schedule for workflowName with the same cron,
+// timezone, inputs, retry policy and tick windows as schedule id. Run history
+// (last run, outcome, failure count, pause reason) is not carried over.
+func (s *SchedulerService) CopySchedule(ctx context.Context, id, workflowName string) (*upal.Schedule, error) {
+	src, err := s.scheduleRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dst := &upal.Schedule{
+		WorkflowName: workflowName,
+		CronExpr:     src.CronExpr,
+		Inputs:       maps.Clone(src.Inputs),
+		Enabled:      src.Enabled,
+		Timezone:     src.Timezone,
+		Blackout:     slices.Clone(src.Blackout),
+	}
+	if src.RetryPolicy != nil {
+		policy := *src.RetryPolicy
+		dst.RetryPolicy = &policy
+	}
+	if src.ActiveHours != nil {
+		hours := *src.ActiveHours
+		hours.Days = slices.Clone(hours.Days)
+		dst.ActiveHours = &hours
+	}
+	if err := s.AddSchedule(ctx, dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
 func (s *SchedulerService) RemoveSchedule(ctx context.Context, id string) error {
 	s.mu.Lock()
 	if entryID, ok := s.entryMap[id]; ok {
@@ -123,10 +224,22 @@ func (s *SchedulerService) RemoveSchedule(ctx context.Context, id string) error
 	}
 	s.mu.Unlock()
 
-	return s.scheduleRepo.Delete(ctx, id)
+	if err := s.scheduleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.recordAudit(ctx, upal.AuditDelete, id, nil)
+	return nil
 }
 
 func (s *SchedulerService) UpdateSchedule(ctx context.Context, schedule *upal.Schedule) error {
+	if _, err := parseCronExpr(schedule.CronExpr, schedule.Timezone); err != nil {
+		return err
+	}
+	withCronPrecision(schedule)
+	if err := validateWindows(schedule); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	if entryID, ok := s.entryMap[schedule.ID]; ok {
 		s.cron.Remove(entryID)
@@ -134,10 +247,21 @@ func (s *SchedulerService) UpdateSchedule(ctx context.Context, schedule *upal.Sc
 	}
 	s.mu.Unlock()
 
+	var before upal.Schedule
+	if s.audit != nil {
+		if prev, err := s.scheduleRepo.Get(ctx, schedule.ID); err == nil {
+			before = *prev
+			withCronPrecision(&before)
+		}
+	}
+
 	schedule.UpdatedAt = time.Now()
 	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
 		return err
 	}
+	if s.audit != nil {
+		s.recordAudit(ctx, upal.AuditUpdate, schedule.ID, upal.DiffFields(&before, schedule))
+	}
 
 	if schedule.Enabled {
 		return s.registerCronJob(schedule)
@@ -170,6 +294,8 @@ func (s *SchedulerService) ResumeSchedule(ctx context.Context, id string) error
 	}
 
 	schedule.Enabled = true
+	schedule.ConsecutiveFailures = 0
+	schedule.PausedReason = ""
 	schedule.UpdatedAt = time.Now()
 
 	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
@@ -180,11 +306,21 @@ func (s *SchedulerService) ResumeSchedule(ctx context.Context, id string) error
 }
 
 func (s *SchedulerService) GetSchedule(ctx context.Context, id string) (*upal.Schedule, error) {
-	return s.scheduleRepo.Get(ctx, id)
+	schedule, err := s.scheduleRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	withCronPrecision(schedule)
+	return schedule, nil
 }
 
 func (s *SchedulerService) ListSchedules(ctx context.Context) ([]*upal.Schedule, error) {
-	return s.scheduleRepo.List(ctx)
+	schedules, err := s.scheduleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	withCronPrecision(schedules...)
+	return schedules, nil
 }
 
 func (s *SchedulerService) TriggerNow(ctx context.Context, id string) error {
@@ -192,6 +328,6 @@ func (s *SchedulerService) TriggerNow(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-	s.executeScheduledRun(schedule)
+	s.executeScheduledRun(schedule, time.Time{})
 	return nil
 }
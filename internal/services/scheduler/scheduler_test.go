@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,6 +39,38 @@ func TestParseCronExpr_6Field(t *testing.T) {
 	}
 }
 
+func TestCronPrecision(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr string
+	}{
+		{expr: "*/5 * * *", wantErr: "expected 5 or 6 fields, got 4"},
+		{expr: "30 9 * * 1-5", want: upal.CronPrecisionMinutes},
+		{expr: "0 30 9 * * 1-5", want: upal.CronPrecisionSeconds},
+		{expr: "CRON_TZ=Asia/Seoul 30 9 * * *", want: upal.CronPrecisionMinutes},
+	}
+	for _, tt := range tests {
+		got, err := cronPrecision(tt.expr)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("cronPrecision(%q) err = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("cronPrecision(%q) = %q, %v; want %q", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseCronExpr_FourFieldError(t *testing.T) {
+	_, err := parseCronExpr("0 9 * *", "")
+	if err == nil || err.Error() != `invalid cron expression "0 9 * *": expected 5 or 6 fields, got 4` {
+		t.Fatalf("err = %v, want field-count error", err)
+	}
+}
+
 func TestParseCronExpr_Invalid(t *testing.T) {
 	_, err := parseCronExpr("invalid cron", "")
 	if err == nil {
@@ -64,6 +98,9 @@ func TestSchedulerService_AddSchedule_5Field(t *testing.T) {
 	if schedule.NextRunAt.IsZero() {
 		t.Fatal("expected NextRunAt to be set")
 	}
+	if schedule.CronPrecision != upal.CronPrecisionMinutes {
+		t.Errorf("CronPrecision = %q, want %q", schedule.CronPrecision, upal.CronPrecisionMinutes)
+	}
 
 	// Verify it was stored in the repository.
 	stored, err := repo.Get(context.Background(), schedule.ID)
@@ -344,6 +381,35 @@ func TestSchedulerService_AddSchedule_DefaultTimezone(t *testing.T) {
 	svc.Stop()
 }
 
+func TestSchedulerService_AddSchedule_ConfiguredDefaultTimezone(t *testing.T) {
+	repo := repository.NewMemoryScheduleRepository()
+	svc := NewSchedulerService(repo, nil, nil, noopLimiter{}, nil)
+	svc.SetDefaultTimezone("Asia/Seoul")
+	defer svc.Stop()
+
+	ctx := context.Background()
+	schedule := &upal.Schedule{WorkflowName: "test-workflow", CronExpr: "0 9 * * *", Enabled: true}
+	if err := svc.AddSchedule(ctx, schedule); err != nil {
+		t.Fatalf("AddSchedule failed: %v", err)
+	}
+	if schedule.Timezone != "Asia/Seoul" {
+		t.Fatalf("Timezone = %q, want configured default Asia/Seoul", schedule.Timezone)
+	}
+	seoul, _ := time.LoadLocation("Asia/Seoul")
+	if next := schedule.NextRunAt.In(seoul); next.Hour() != 9 || next.Minute() != 0 {
+		t.Errorf("NextRunAt = %v, want 09:00 Seoul time", next)
+	}
+
+	// An explicit timezone still wins over the default.
+	explicit := &upal.Schedule{WorkflowName: "test-workflow", CronExpr: "0 9 * * *", Enabled: true, Timezone: "UTC"}
+	if err := svc.AddSchedule(ctx, explicit); err != nil {
+		t.Fatalf("AddSchedule failed: %v", err)
+	}
+	if explicit.Timezone != "UTC" || explicit.NextRunAt.UTC().Hour() != 9 {
+		t.Errorf("explicit schedule = %s at %v, want UTC 09:00", explicit.Timezone, explicit.NextRunAt)
+	}
+}
+
 func TestSchedulerService_Start_LoadsExisting(t *testing.T) {
 	repo := repository.NewMemoryScheduleRepository()
 	ctx := context.Background()
@@ -385,3 +451,377 @@ func TestSchedulerService_Start_LoadsExisting(t *testing.T) {
 		t.Fatal("expected sched-3 (disabled) to NOT be registered in entryMap")
 	}
 }
+
+// missingWorkflowExec is a WorkflowExecutor whose lookups always fail.
+type missingWorkflowExec struct{}
+
+func (missingWorkflowExec) Lookup(_ context.Context, name string) (*upal.WorkflowDefinition, error) {
+	return nil, fmt.Errorf("workflow %q: %w", name, repository.ErrNotFound)
+}
+func (missingWorkflowExec) Validate(*upal.WorkflowDefinition) error { return nil }
+func (missingWorkflowExec) Run(context.Context, *upal.WorkflowDefinition, map[string]any) (<-chan upal.WorkflowEvent, <-chan upal.RunResult, error) {
+	return nil, nil, fmt.Errorf("not implemented")
+}
+
+func TestSchedulerService_AutoPausesMissingWorkflow(t *testing.T) {
+	repo := repository.NewMemoryScheduleRepository()
+	svc := NewSchedulerService(repo, missingWorkflowExec{}, nil, noopLimiter{}, nil)
+	svc.SetAutoPauseAfter(3)
+	ctx := context.Background()
+
+	schedule := &upal.Schedule{WorkflowName: "deleted-wf", CronExpr: "0 0 * * *", Enabled: true}
+	if err := svc.AddSchedule(ctx, schedule); err != nil {
+		t.Fatalf("AddSchedule: %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		if err := svc.TriggerNow(ctx, schedule.ID); err != nil {
+			t.Fatalf("TriggerNow: %v", err)
+		}
+		stored, _ := repo.Get(ctx, schedule.ID)
+		if !stored.Enabled {
+			t.Fatalf("schedule paused after only %d failures", i)
+		}
+		if stored.ConsecutiveFailures != i {
+			t.Fatalf("expected %d consecutive failures, got %d", i, stored.ConsecutiveFailures)
+		}
+	}
+
+	if err := svc.TriggerNow(ctx, schedule.ID); err != nil {
+		t.Fatalf("TriggerNow: %v", err)
+	}
+	stored, _ := repo.Get(ctx, schedule.ID)
+	if stored.Enabled {
+		t.Fatal("expected schedule to be auto-paused")
+	}
+	if stored.PausedReason == "" {
+		t.Error("expected paused_reason to be recorded")
+	}
+	svc.mu.RLock()
+	_, registered := svc.entryMap[schedule.ID]
+	svc.mu.RUnlock()
+	if registered {
+		t.Error("expected cron entry to be removed")
+	}
+
+	if err := svc.ResumeSchedule(ctx, schedule.ID); err != nil {
+		t.Fatalf("ResumeSchedule: %v", err)
+	}
+	stored, _ = repo.Get(ctx, schedule.ID)
+	if stored.ConsecutiveFailures != 0 || stored.PausedReason != "" {
+		t.Errorf("expected resume to clear failure state, got %d / %q", stored.ConsecutiveFailures, stored.PausedReason)
+	}
+}
+
+// failingWorkflowExec is a WorkflowExecutor whose lookups fail with an error
+// other than not found, such as a database outage.
+type failingWorkflowExec struct{ missingWorkflowExec }
+
+func (failingWorkflowExec) Lookup(context.Context, string) (*upal.WorkflowDefinition, error) {
+	return nil, fmt.Errorf("get workflow: connection refused")
+}
+
+func TestSchedulerService_LookupErrorDoesNotCountAsMissing(t *testing.T) {
+	repo := repository.NewMemoryScheduleRepository()
+	svc := NewSchedulerService(repo, failingWorkflowExec{}, nil, noopLimiter{}, nil)
+	svc.SetAutoPauseAfter(1)
+	ctx := context.Background()
+
+	schedule := &upal.Schedule{WorkflowName: "wf", CronExpr: "0 0 * * *", Enabled: true}
+	if err := svc.AddSchedule(ctx, schedule); err != nil {
+		t.Fatalf("AddSchedule: %v", err)
+	}
+	if err := svc.TriggerNow(ctx, schedule.ID); err != nil {
+		t.Fatalf("TriggerNow: %v", err)
+	}
+
+	stored, _ := repo.Get(ctx, schedule.ID)
+	if !stored.Enabled || stored.ConsecutiveFailures != 0 || stored.PausedReason != "" {
+		t.Errorf("expected the failed lookup not to count, got enabled=%v failures=%d reason=%q",
+			stored.Enabled, stored.ConsecutiveFailures, stored.PausedReason)
+	}
+}
+
+func TestSchedulerService_BlackoutWindow(t *testing.T) {
+	now := time.Now()
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name         string
+		window       upal.BlackoutWindow
+		wantOutcome  upal.ScheduleOutcome
+		wantAttempts int
+	}{
+		{"inside one-off range", upal.BlackoutWindow{Start: &before, End: &after}, upal.ScheduleOutcomeSkippedBlackout, 0},
+		{"outside one-off range", upal.BlackoutWindow{Start: &after, End: &later}, "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryScheduleRepository()
+			svc := NewSchedulerService(repo, missingWorkflowExec{}, nil, noopLimiter{}, nil)
+			ctx := context.Background()
+
+			schedule := &upal.Schedule{
+				WorkflowName: "wf",
+				CronExpr:     "0 0 * * *",
+				Blackout:     []upal.BlackoutWindow{tt.window},
+			}
+			if err := svc.AddSchedule(ctx, schedule); err != nil {
+				t.Fatalf("AddSchedule: %v", err)
+			}
+			svc.executeScheduledRun(schedule, time.Time{})
+
+			stored, _ := repo.Get(ctx, schedule.ID)
+			if stored.LastOutcome != tt.wantOutcome {
+				t.Errorf("last_outcome: got %q, want %q", stored.LastOutcome, tt.wantOutcome)
+			}
+			// missingWorkflowExec counts every attempted run as a failure.
+			if stored.ConsecutiveFailures != tt.wantAttempts {
+				t.Errorf("attempted runs: got %d, want %d", stored.ConsecutiveFailures, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+type fixedMaintenance bool
+
+func (m fixedMaintenance) InMaintenance() bool { return bool(m) }
+
+func TestSchedulerService_MaintenanceSkipsTicks(t *testing.T) {
+	repo := repository.NewMemoryScheduleRepository()
+	svc := NewSchedulerService(repo, missingWorkflowExec{}, nil, noopLimiter{}, nil)
+	svc.SetMaintenanceGate(fixedMaintenance(true))
+	ctx := context.Background()
+
+	schedule := &upal.Schedule{WorkflowName: "wf", CronExpr: "0 0 * * *"}
+	if err := svc.AddSchedule(ctx, schedule); err != nil {
+		t.Fatalf("AddSchedule: %v", err)
+	}
+	svc.executeScheduledRun(schedule, time.Time{})
+
+	stored, _ := repo.Get(ctx, schedule.ID)
+	if stored.LastOutcome != upal.ScheduleOutcomeSkippedMaintenance {
+		t.Errorf("last_outcome: got %q, want %q", stored.LastOutcome, upal.ScheduleOutcomeSkippedMaintenance)
+	}
+	if stored.ConsecutiveFailures != 0 || stored.LastRunAt != nil {
+		t.Errorf("expected no run attempt, got %d failures, last_run_at %v", stored.ConsecutiveFailures, stored.LastRunAt)
+	}
+
+	svc.SetMaintenanceGate(fixedMaintenance(false))
+	svc.executeScheduledRun(schedule, time.Time{})
+	stored, _ = repo.Get(ctx, schedule.ID)
+	if stored.ConsecutiveFailures != 1 {
+		t.Errorf("expected the tick to run after maintenance, got %d attempts", stored.ConsecutiveFailures)
+	}
+}
+
+func TestBlackoutWindow_DailyWindow(t *testing.T) {
+	w := upal.BlackoutWindow{DailyStart: "22:00", DailyEnd: "06:00", Timezone: "Asia/Seoul"}
+	if err := w.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	seoul, _ := time.LoadLocation("Asia/Seoul")
+	cases := map[string]bool{
+		"2024-03-01T23:30": true,  // after start, before midnight
+		"2024-03-02T05:59": true,  // wrapped past midnight
+		"2024-03-02T06:00": false, // end is exclusive
+		"2024-03-02T12:00": false,
+	}
+	for ts, want := range cases {
+		tm, _ := time.ParseInLocation("2006-01-02T15:04", ts, seoul)
+		if got := w.Contains(tm.UTC(), "UTC"); got != want {
+			t.Errorf("Contains(%s) = %v, want %v", ts, got, want)
+		}
+	}
+
+	if err := (upal.BlackoutWindow{DailyStart: "25:00", DailyEnd: "06:00"}).Validate(); err == nil {
+		t.Error("expected invalid daily_start to be rejected")
+	}
+}
+
+func TestSchedulerService_ActiveHours(t *testing.T) {
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	now := time.Now().In(kolkata)
+	clock := func(d time.Duration) string { return now.Add(d).Format("15:04") }
+
+	tests := []struct {
+		name         string
+		hours        upal.ActiveHours
+		wantOutcome  upal.ScheduleOutcome
+		wantAttempts int
+	}{
+		{"inside window", upal.ActiveHours{Start: clock(-time.Hour), End: clock(time.Hour), Timezone: "Asia/Kolkata"}, "", 1},
+		{"outside window", upal.ActiveHours{Start: clock(time.Hour), End: clock(2 * time.Hour), Timezone: "Asia/Kolkata"}, upal.ScheduleOutcomeSkippedInactive, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryScheduleRepository()
+			svc := NewSchedulerService(repo, missingWorkflowExec{}, nil, noopLimiter{}, nil)
+			ctx := context.Background()
+
+			hours := tt.hours
+			schedule := &upal.Schedule{
+				WorkflowName: "wf",
+				CronExpr:     "0 0 * * *",
+				ActiveHours:  &hours,
+			}
+			if err := svc.AddSchedule(ctx, schedule); err != nil {
+				t.Fatalf("AddSchedule: %v", err)
+			}
+			svc.executeScheduledRun(schedule, time.Time{})
+
+			stored, _ := repo.Get(ctx, schedule.ID)
+			if stored.LastOutcome != tt.wantOutcome {
+				t.Errorf("last_outcome: got %q, want %q", stored.LastOutcome, tt.wantOutcome)
+			}
+			if stored.ConsecutiveFailures != tt.wantAttempts {
+				t.Errorf("attempted runs: got %d, want %d", stored.ConsecutiveFailures, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestActiveHours_TimezoneBoundary(t *testing.T) {
+	// Business hours in New York; ticks are evaluated from UTC instants.
+	w := upal.ActiveHours{Start: "09:00", End: "17:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}}
+	if err := w.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	cases := map[string]bool{
+		"2024-03-04T14:00": true,  // Mon 09:00 in New York
+		"2024-03-04T22:30": false, // Mon 17:30 in New York
+		"2024-03-05T02:00": false, // Tue in UTC, still Mon 21:00 in New York
+		"2024-03-09T15:00": false, // Sat 10:00 in New York
+	}
+	for ts, want := range cases {
+		tm, _ := time.Parse("2006-01-02T15:04", ts)
+		if got := w.Contains(tm, "America/New_York"); got != want {
+			t.Errorf("Contains(%s UTC) = %v, want %v", ts, got, want)
+		}
+	}
+
+	// An overnight window belongs to the day it opens: Friday 22:00 through
+	// Saturday 02:00 is active, Saturday 22:00 is not.
+	night := upal.ActiveHours{Start: "22:00", End: "02:00", Days: []string{"fri"}, Timezone: "Asia/Seoul"}
+	seoul, _ := time.LoadLocation("Asia/Seoul")
+	nightCases := map[string]bool{
+		"2024-03-08T23:00": true,  // Fri
+		"2024-03-09T01:30": true,  // Sat, after midnight of Friday's window
+		"2024-03-09T23:00": false, // Sat
+	}
+	for ts, want := range nightCases {
+		tm, _ := time.ParseInLocation("2006-01-02T15:04", ts, seoul)
+		if got := night.Contains(tm.UTC(), "UTC"); got != want {
+			t.Errorf("overnight Contains(%s) = %v, want %v", ts, got, want)
+		}
+	}
+
+	if err := (upal.ActiveHours{Start: "09:00", End: "17:00", Days: []string{"funday"}}).Validate(); err == nil {
+		t.Error("expected unknown day to be rejected")
+	}
+}
+
+func TestSchedulerService_QueueReclaimsExpiredLease(t *testing.T) {
+	repo := repository.NewMemoryScheduleRepository()
+	queue := repository.NewMemoryRunQueueRepository()
+	svc := NewSchedulerService(repo, missingWorkflowExec{}, nil, noopLimiter{}, nil)
+	svc.SetRunQueue(queue, time.Minute)
+	svc.queuePoll = 10 * time.Millisecond
+	ctx := context.Background()
+
+	schedule := &upal.Schedule{WorkflowName: "wf", CronExpr: "0 0 * * *"}
+	if err := svc.AddSchedule(ctx, schedule); err != nil {
+		t.Fatalf("AddSchedule: %v", err)
+	}
+
+	// A tick claimed by a worker that crashed: its lease runs out shortly.
+	queue.Enqueue(ctx, &upal.QueuedRun{ID: "q1", ScheduleID: schedule.ID, EnqueuedAt: time.Now()})
+	if _, err := queue.Claim(ctx, "dead-worker", 50*time.Millisecond, time.Now()); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer svc.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for queue.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if queue.Len() != 0 {
+		t.Fatal("expected the expired tick to be re-claimed and completed")
+	}
+	// missingWorkflowExec counts every attempted run as a failure.
+	if stored, _ := repo.Get(ctx, schedule.ID); stored.ConsecutiveFailures != 1 {
+		t.Errorf("attempted runs: got %d, want 1", stored.ConsecutiveFailures)
+	}
+}
+
+func TestSchedulerService_QueueDefersTicks(t *testing.T) {
+	repo := repository.NewMemoryScheduleRepository()
+	queue := repository.NewMemoryRunQueueRepository()
+	svc := NewSchedulerService(repo, missingWorkflowExec{}, nil, noopLimiter{}, nil)
+	svc.SetRunQueue(queue, time.Minute)
+	ctx := context.Background()
+
+	schedule := &upal.Schedule{WorkflowName: "wf", CronExpr: "0 0 * * *"}
+	if err := svc.AddSchedule(ctx, schedule); err != nil {
+		t.Fatalf("AddSchedule: %v", err)
+	}
+	svc.executeScheduledRun(schedule, time.Time{})
+
+	if queue.Len() != 1 {
+		t.Fatalf("queue length = %d, want 1", queue.Len())
+	}
+	if stored, _ := repo.Get(ctx, schedule.ID); stored.ConsecutiveFailures != 0 {
+		t.Error("tick ran in-process instead of being queued")
+	}
+}
+
+func TestSchedulerService_QueueTickEnqueuedOnceAcrossInstances(t *testing.T) {
+	repo := repository.NewMemoryScheduleRepository()
+	queue := repository.NewMemoryRunQueueRepository()
+	ctx := context.Background()
+
+	schedule := &upal.Schedule{WorkflowName: "wf", CronExpr: "0 0 * * *"}
+	var instances []*SchedulerService
+	for range 3 {
+		svc := NewSchedulerService(repo, missingWorkflowExec{}, nil, noopLimiter{}, nil)
+		svc.SetRunQueue(queue, time.Minute)
+		instances = append(instances, svc)
+	}
+	if err := instances[0].AddSchedule(ctx, schedule); err != nil {
+		t.Fatalf("AddSchedule: %v", err)
+	}
+
+	tick := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, svc := range instances {
+		svc.executeScheduledRun(schedule, tick)
+	}
+
+	if queue.Len() != 1 {
+		t.Fatalf("queue length = %d, want 1", queue.Len())
+	}
+	// A duplicate must not fall back to running in-process.
+	if stored, _ := repo.Get(ctx, schedule.ID); stored.ConsecutiveFailures != 0 {
+		t.Error("duplicate tick ran in-process")
+	}
+}
+
+func TestScheduledTick(t *testing.T) {
+	sched, err := parseCronExpr("*/5 * * * *", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	activation := time.Date(2026, 1, 1, 12, 5, 0, 0, time.UTC)
+	for _, now := range []time.Time{
+		activation.Add(3 * time.Millisecond),
+		activation.Add(40 * time.Second), // a lagging instance
+	} {
+		if got := scheduledTick(sched, now); !got.Equal(activation) {
+			t.Errorf("scheduledTick(%s) = %s, want %s", now.Format(time.TimeOnly), got, activation)
+		}
+	}
+}
@@ -34,15 +34,11 @@ func (s *SchedulerService) SyncPipelineSchedules(ctx context.Context, pipeline *
 		if stage.Type != "schedule" || stage.Config.Cron == "" || stage.Config.ScheduleID != "" {
 			continue
 		}
-		tz := stage.Config.Timezone
-		if tz == "" {
-			tz = "UTC"
-		}
 		sched := &upal.Schedule{
 			PipelineID: pipeline.ID,
 			CronExpr:   stage.Config.Cron,
 			Enabled:    true,
-			Timezone:   tz,
+			Timezone:   stage.Config.Timezone,
 		}
 		if err := s.AddSchedule(ctx, sched); err != nil {
 			slog.Warn("scheduler: failed to register pipeline schedule stage",
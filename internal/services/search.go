@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+const (
+	// searchRunScanLimit bounds how many matching runs are ranked.
+	searchRunScanLimit = 500
+	// searchSnippetRadius is the number of characters kept on each side of a match.
+	searchSnippetRadius = 40
+)
+
+// SearchService performs ranked keyword search over workflows, pipelines, and runs.
+// Candidate runs come from the run repository's search, which uses Postgres
+// full-text search when the repository is database-backed.
+type SearchService struct {
+	workflows repository.WorkflowRepository
+	pipelines ports.PipelineServicePort
+	runs      ports.RunHistoryPort
+}
+
+func NewSearchService(workflows repository.WorkflowRepository, pipelines ports.PipelineServicePort, runs ports.RunHistoryPort) *SearchService {
+	return &SearchService{workflows: workflows, pipelines: pipelines, runs: runs}
+}
+
+// searchField is a piece of text with a ranking weight.
+type searchField struct {
+	text   string
+	weight float64
+}
+
+// Search returns results matching every term of query, best match first.
+// types restricts the searched object kinds; empty means all.
+func (s *SearchService) Search(ctx context.Context, query string, types []upal.SearchResultType, limit int) ([]upal.SearchResult, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	want := func(t upal.SearchResultType) bool {
+		if len(types) == 0 {
+			return true
+		}
+		for _, x := range types {
+			if x == t {
+				return true
+			}
+		}
+		return false
+	}
+
+	var results []upal.SearchResult
+
+	if want(upal.SearchTypeWorkflow) && s.workflows != nil {
+		wfs, err := s.workflows.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list workflows: %w", err)
+		}
+		for _, wf := range wfs {
+			fields := []searchField{{wf.Name, 3}, {wf.Description, 2}}
+			for _, n := range wf.Nodes {
+				if label, ok := n.Config["label"].(string); ok {
+					fields = append(fields, searchField{label, 2})
+				}
+				if desc, ok := n.Config["description"].(string); ok {
+					fields = append(fields, searchField{desc, 1})
+				}
+			}
+			if r, ok := scoreFields(terms, fields); ok {
+				r.Type, r.ID, r.Name = upal.SearchTypeWorkflow, wf.Name, wf.Name
+				results = append(results, r)
+			}
+		}
+	}
+
+	if want(upal.SearchTypePipeline) && s.pipelines != nil {
+		pipelines, err := s.pipelines.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list pipelines: %w", err)
+		}
+		for _, p := range pipelines {
+			fields := []searchField{{p.Name, 3}, {p.Description, 2}}
+			for _, st := range p.Stages {
+				fields = append(fields, searchField{st.Name, 2}, searchField{st.Description, 1})
+			}
+			if r, ok := scoreFields(terms, fields); ok {
+				r.Type, r.ID, r.Name = upal.SearchTypePipeline, p.ID, p.Name
+				results = append(results, r)
+			}
+		}
+	}
+
+	if want(upal.SearchTypeRun) && s.runs != nil {
+		runs, err := s.runs.SearchRuns(ctx, query, searchRunScanLimit)
+		if err != nil {
+			return nil, fmt.Errorf("search runs: %w", err)
+		}
+		for _, run := range runs {
+			fields := []searchField{{run.WorkflowName, 2}, {marshalForSearch(run.Inputs), 1}, {marshalForSearch(run.Outputs), 1}}
+			if r, ok := scoreFields(terms, fields); ok {
+				r.Type, r.ID, r.Name = upal.SearchTypeRun, run.ID, run.WorkflowName
+				results = append(results, r)
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// scoreFields requires every term to appear in some field. The score sums the
+// weights of fields containing each term; the snippet comes from the
+// highest-weighted field containing the first term.
+func scoreFields(terms []string, fields []searchField) (upal.SearchResult, bool) {
+	lowered := make([]foldedText, len(fields))
+	for i, f := range fields {
+		lowered[i] = foldText(f.text)
+	}
+
+	var res upal.SearchResult
+	bestWeight := 0.0
+	for i, term := range terms {
+		matched := false
+		for j, f := range fields {
+			idx := strings.Index(lowered[j].lower, term)
+			if idx < 0 {
+				continue
+			}
+			matched = true
+			res.Score += f.weight
+			if i == 0 && f.weight > bestWeight {
+				bestWeight = f.weight
+				start, end := lowered[j].offsets[idx], lowered[j].offsets[idx+len(term)]
+				res.Snippet = snippetAround(f.text, start, end)
+			}
+		}
+		if !matched {
+			return upal.SearchResult{}, false
+		}
+	}
+	return res, true
+}
+
+// foldedText is a lowercased copy of a text together with, for every byte
+// offset of lower (and one past its end), the matching offset in the
+// original. Lowercasing can change a rune's encoded length, so offsets found
+// in lower cannot be used on the original directly.
+type foldedText struct {
+	lower   string
+	offsets []int
+}
+
+func foldText(text string) foldedText {
+	var b strings.Builder
+	b.Grow(len(text))
+	offsets := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		n := b.Len()
+		b.WriteRune(unicode.ToLower(r))
+		for range b.Len() - n {
+			offsets = append(offsets, i)
+		}
+	}
+	offsets = append(offsets, len(text))
+	return foldedText{lower: b.String(), offsets: offsets}
+}
+
+// snippetAround returns text surrounding [start, end), trimmed with ellipses.
+// The cut points are moved back to rune boundaries.
+func snippetAround(text string, start, end int) string {
+	from := max(0, start-searchSnippetRadius)
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	to := min(len(text), end+searchSnippetRadius)
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to--
+	}
+	snippet := text[from:to]
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+func marshalForSearch(v map[string]any) string {
+	if len(v) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strings"
 	"time"
 
 	"github.com/soochol/upal/internal/agents"
@@ -12,6 +14,14 @@ import (
 
 // NotificationStageExecutor sends a notification and completes immediately.
 // Unlike ApprovalStageExecutor, it does not pause the pipeline.
+//
+// For webhook connections, a non-empty stage Body is rendered as a template
+// and posted as the request body. Placeholders resolve against the previous
+// stage's output plus pipeline_id, pipeline_name, stage_id, stage_name and
+// message; use the json filter ({{title | json}}) to embed values safely.
+//
+// For SMTP connections the subject, HTML body and attachment IDs are
+// rendered the same way, and stage CC/BCC extend the connection recipients.
 type NotificationStageExecutor struct {
 	senderReg    *notify.SenderRegistry
 	connResolver agents.ConnectionResolver
@@ -23,7 +33,7 @@ func NewNotificationStageExecutor(senderReg *notify.SenderRegistry, connResolver
 
 func (e *NotificationStageExecutor) Type() string { return "notification" }
 
-func (e *NotificationStageExecutor) Execute(ctx context.Context, _ *upal.Pipeline, stage upal.Stage, _ *upal.StageResult) (*upal.StageResult, error) {
+func (e *NotificationStageExecutor) Execute(ctx context.Context, pipeline *upal.Pipeline, stage upal.Stage, prevResult *upal.StageResult) (*upal.StageResult, error) {
 	fail := func(errMsg string) (*upal.StageResult, error) {
 		now := time.Now()
 		return &upal.StageResult{
@@ -52,17 +62,33 @@ func (e *NotificationStageExecutor) Execute(ctx context.Context, _ *upal.Pipelin
 		return fail(fmt.Sprintf("no sender for connection type %q: %v", conn.Type, err))
 	}
 
+	// Resolved connections may share state with the repository; copy before
+	// applying per-stage overrides.
+	resolved := *conn
+	conn = &resolved
+	conn.Extras = maps.Clone(conn.Extras)
+	if conn.Extras == nil {
+		conn.Extras = map[string]any{}
+	}
 	if stage.Config.Subject != "" {
-		if conn.Extras == nil {
-			conn.Extras = map[string]any{}
-		}
 		conn.Extras["subject"] = stage.Config.Subject
 	}
+	if len(stage.Config.Blocks) > 0 {
+		conn.Extras["blocks"] = stage.Config.Blocks
+	}
 
 	msg := stage.Config.Message
 	if msg == "" {
 		msg = stage.Name
 	}
+	values := notificationTemplateValues(pipeline, stage, prevResult, msg)
+
+	if conn.Type == upal.ConnTypeWebhook && stage.Config.Body != "" {
+		msg = agents.ResolveTemplate(stage.Config.Body, values)
+	}
+	if conn.Type == upal.ConnTypeSMTP {
+		applyEmailOptions(conn, stage.Config, values)
+	}
 
 	if err := sender.Send(ctx, conn, msg); err != nil {
 		return fail(fmt.Sprintf("send failed: %v", err))
@@ -81,3 +107,74 @@ func (e *NotificationStageExecutor) Execute(ctx context.Context, _ *upal.Pipelin
 		CompletedAt: &now,
 	}, nil
 }
+
+// applyEmailOptions merges the stage's email settings into the connection
+// extras read by notify.SMTPSender. Subject and HTML templates, whether set on
+// the stage or stored on the connection, and attachment IDs are rendered
+// against values; stage CC/BCC are appended to the connection's.
+func applyEmailOptions(conn *upal.Connection, cfg upal.StageConfig, values map[string]any) {
+	if cfg.HTML != "" {
+		conn.Extras["html"] = cfg.HTML
+	}
+	for _, key := range []string{"subject", "html"} {
+		if tmpl, ok := conn.Extras[key].(string); ok && tmpl != "" {
+			conn.Extras[key] = agents.ResolveTemplate(tmpl, values)
+		}
+	}
+	if len(cfg.CC) > 0 {
+		conn.Extras["cc"] = appendRecipients(conn.Extras["cc"], cfg.CC)
+	}
+	if len(cfg.BCC) > 0 {
+		conn.Extras["bcc"] = appendRecipients(conn.Extras["bcc"], cfg.BCC)
+	}
+	if len(cfg.Attachments) > 0 {
+		ids := make([]string, 0, len(cfg.Attachments))
+		for _, a := range cfg.Attachments {
+			if id := strings.TrimSpace(agents.ResolveTemplate(a, values)); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		conn.Extras["attachments"] = ids
+	}
+}
+
+// appendRecipients combines a connection recipient extra (a comma-separated
+// string or a list) with stage-level recipients.
+func appendRecipients(existing any, extra []string) []any {
+	var out []any
+	switch v := existing.(type) {
+	case string:
+		if strings.TrimSpace(v) != "" {
+			out = append(out, v)
+		}
+	case []any:
+		out = append(out, v...)
+	case []string:
+		for _, s := range v {
+			out = append(out, s)
+		}
+	}
+	for _, s := range extra {
+		out = append(out, s)
+	}
+	return out
+}
+
+// notificationTemplateValues builds the placeholder values for webhook body
+// and email subject/HTML templates.
+func notificationTemplateValues(pipeline *upal.Pipeline, stage upal.Stage, prevResult *upal.StageResult, message string) map[string]any {
+	values := make(map[string]any)
+	if prevResult != nil {
+		for k, v := range prevResult.Output {
+			values[k] = v
+		}
+	}
+	if pipeline != nil {
+		values["pipeline_id"] = pipeline.ID
+		values["pipeline_name"] = pipeline.Name
+	}
+	values["stage_id"] = stage.ID
+	values["stage_name"] = stage.Name
+	values["message"] = message
+	return values
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/notify"
+	"github.com/soochol/upal/internal/storage"
+	"github.com/soochol/upal/internal/upal"
+)
+
+type staticConnResolver map[string]*upal.Connection
+
+func (r staticConnResolver) Resolve(_ context.Context, id string) (*upal.Connection, error) {
+	return r[id], nil
+}
+
+func TestNotificationStage_WebhookTemplatedBody(t *testing.T) {
+	type request struct {
+		body   []byte
+		header http.Header
+	}
+	got := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- request{body: body, header: r.Header.Clone()}
+	}))
+	defer srv.Close()
+
+	senderReg := notify.NewSenderRegistry()
+	senderReg.Register(&notify.WebhookSender{})
+	conns := staticConnResolver{"hook": {
+		ID:     "hook",
+		Name:   "ops hook",
+		Type:   upal.ConnTypeWebhook,
+		Host:   srv.URL,
+		Extras: map[string]any{"headers": map[string]any{"X-Api-Key": "secret"}},
+	}}
+	exec := NewNotificationStageExecutor(senderReg, conns)
+
+	pipeline := &upal.Pipeline{ID: "p1", Name: "daily digest"}
+	stage := upal.Stage{
+		ID:   "notify",
+		Name: "Notify",
+		Type: "notification",
+		Config: upal.StageConfig{
+			ConnectionID: "hook",
+			Message:      "done",
+			Body:         `{"pipeline": {{pipeline_name | json}}, "title": {{title | upper | json}}, "text": "{{message}}"}`,
+		},
+	}
+	prev := &upal.StageResult{Output: map[string]any{"title": `say "hi"`}}
+
+	res, err := exec.Execute(context.Background(), pipeline, stage, prev)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if res.Status != upal.StageStatusCompleted {
+		t.Fatalf("status: got %q", res.Status)
+	}
+
+	req := <-got
+	var body map[string]string
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatalf("body is not JSON: %v (%s)", err, req.body)
+	}
+	want := map[string]string{"pipeline": "daily digest", "title": `SAY "HI"`, "text": "done"}
+	for k, v := range want {
+		if body[k] != v {
+			t.Errorf("body[%q]: got %q, want %q", k, body[k], v)
+		}
+	}
+	if h := req.header.Get("X-Api-Key"); h != "secret" {
+		t.Errorf("X-Api-Key header: got %q", h)
+	}
+	if ct := req.header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type: got %q", ct)
+	}
+}
+
+func TestNotificationStage_EmailOptions(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := store.Save(context.Background(), "report.csv", "text/csv", strings.NewReader("a,b\n1,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotTo []string
+	var gotMsg []byte
+	senderReg := notify.NewSenderRegistry()
+	senderReg.Register(&notify.SMTPSender{
+		Storage: store,
+		SendMail: func(_ string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+			gotTo, gotMsg = to, msg
+			return nil
+		},
+	})
+	stored := &upal.Connection{
+		ID:     "mail",
+		Type:   upal.ConnTypeSMTP,
+		Host:   "smtp.example.com",
+		Login:  "bot@example.com",
+		Extras: map[string]any{"to": "ops@example.com", "subject": "[{{pipeline_name}}] {{title}}"},
+	}
+	exec := NewNotificationStageExecutor(senderReg, staticConnResolver{"mail": stored})
+
+	stage := upal.Stage{ID: "notify", Type: "notification", Config: upal.StageConfig{
+		ConnectionID: "mail",
+		Message:      "Report attached.",
+		HTML:         "<p>{{title}}</p>",
+		CC:           []string{"lead@example.com"},
+		BCC:          []string{"audit@example.com"},
+		Attachments:  []string{"{{report_file_id}}"},
+	}}
+	prev := &upal.StageResult{Output: map[string]any{"title": "Weekly", "report_file_id": report.ID}}
+	if _, err := exec.Execute(context.Background(), &upal.Pipeline{Name: "sales"}, stage, prev); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	if strings.Join(gotTo, ",") != "ops@example.com,lead@example.com,audit@example.com" {
+		t.Errorf("envelope recipients = %v", gotTo)
+	}
+	msg := string(gotMsg)
+	for _, want := range []string{"Subject: [sales] Weekly", "Cc: <lead@example.com>", "<p>Weekly</p>", `filename=report.csv`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+	if stored.Extras["subject"] != "[{{pipeline_name}}] {{title}}" || stored.Extras["cc"] != nil {
+		t.Errorf("stored connection extras were modified: %v", stored.Extras)
+	}
+}
+
+// pagerSender stands in for a plugin-provided connection type.
+type pagerSender struct{ got chan string }
+
+func (s *pagerSender) Type() upal.ConnectionType { return "pager" }
+
+func (s *pagerSender) Send(_ context.Context, conn *upal.Connection, message string) error {
+	s.got <- conn.Host + ": " + message
+	return nil
+}
+
+func TestNotificationStage_CustomSenderType(t *testing.T) {
+	pager := &pagerSender{got: make(chan string, 1)}
+	senderReg := notify.NewSenderRegistry()
+	senderReg.RegisterType("pager", func(notify.SenderDeps) (notify.Sender, error) { return pager, nil })
+	conns := staticConnResolver{"oncall": {ID: "oncall", Name: "on-call", Type: "pager", Host: "team-a"}}
+	exec := NewNotificationStageExecutor(senderReg, conns)
+
+	stage := upal.Stage{ID: "page", Type: "notification", Config: upal.StageConfig{ConnectionID: "oncall", Message: "pipeline failed"}}
+	res, err := exec.Execute(context.Background(), &upal.Pipeline{ID: "p1"}, stage, nil)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := <-pager.got; got != "team-a: pipeline failed" {
+		t.Errorf("pager received %q", got)
+	}
+	if res.Output["type"] != "pager" || res.Output["channel"] != "on-call" {
+		t.Errorf("unexpected output: %v", res.Output)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 
+	"github.com/soochol/upal/internal/agents"
 	"github.com/soochol/upal/internal/upal"
 )
 
@@ -38,9 +40,87 @@ func (e *TransformStageExecutor) Execute(_ context.Context, _ *upal.Pipeline, st
 		output["expression_result"] = parsed
 	}
 
+	for i, op := range stage.Config.Operations {
+		var err error
+		if output, err = applyTransformOp(op, output); err != nil {
+			return nil, fmt.Errorf("transform operation %d: %w", i+1, err)
+		}
+	}
+
 	return &upal.StageResult{
 		StageID: stage.ID,
 		Status:  upal.StageStatusCompleted,
 		Output:  output,
 	}, nil
 }
+
+// applyTransformOp returns the result of applying op to in. in may be
+// modified.
+func applyTransformOp(op upal.TransformOp, in map[string]any) (map[string]any, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "pick":
+		out := make(map[string]any, len(op.Keys))
+		for _, k := range op.Keys {
+			if v, ok := in[k]; ok {
+				out[k] = v
+			}
+		}
+		return out, nil
+	case "rename":
+		out := maps.Clone(in)
+		for from := range op.Mapping {
+			delete(out, from)
+		}
+		for from, to := range op.Mapping {
+			if v, ok := in[from]; ok {
+				out[to] = v
+			}
+		}
+		return out, nil
+	case "flatten":
+		sep := op.Separator
+		if sep == "" {
+			sep = "."
+		}
+		out := make(map[string]any, len(in))
+		flattenInto(out, "", sep, in)
+		return out, nil
+	case "template":
+		in[op.Target] = agents.ResolveTemplate(op.Template, in)
+		return in, nil
+	case "parse_json":
+		raw, ok := in[op.Key].(string)
+		if !ok {
+			return nil, fmt.Errorf("parse_json: %q is not a string", op.Key)
+		}
+		var parsed any
+		if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+			return nil, fmt.Errorf("parse_json: %q: %w", op.Key, err)
+		}
+		target := op.Target
+		if target == "" {
+			target = op.Key
+		}
+		in[target] = parsed
+		return in, nil
+	}
+	return in, nil
+}
+
+// flattenInto copies m into out, joining nested object keys with sep.
+func flattenInto(out map[string]any, prefix, sep string, m map[string]any) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + sep + k
+		}
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			flattenInto(out, key, sep, nested)
+			continue
+		}
+		out[key] = v
+	}
+}
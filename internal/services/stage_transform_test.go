@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/services"
+	"github.com/soochol/upal/internal/upal"
+)
+
+func runTransform(t *testing.T, prev map[string]any, ops ...upal.TransformOp) (map[string]any, error) {
+	t.Helper()
+	stage := upal.Stage{ID: "t1", Type: "transform", Config: upal.StageConfig{Operations: ops}}
+	res, err := (&services.TransformStageExecutor{}).Execute(context.Background(), nil, stage,
+		&upal.StageResult{Status: upal.StageStatusCompleted, Output: prev})
+	if err != nil {
+		return nil, err
+	}
+	return res.Output, nil
+}
+
+func TestTransformStage_Operations(t *testing.T) {
+	tests := []struct {
+		name string
+		prev map[string]any
+		op   upal.TransformOp
+		want map[string]any
+	}{
+		{
+			name: "pick",
+			prev: map[string]any{"title": "T", "body": "B", "raw": "x"},
+			op:   upal.TransformOp{Op: "pick", Keys: []string{"title", "body", "missing"}},
+			want: map[string]any{"title": "T", "body": "B"},
+		},
+		{
+			name: "rename",
+			prev: map[string]any{"a": 1, "b": 2, "keep": 3},
+			op:   upal.TransformOp{Op: "rename", Mapping: map[string]string{"a": "b", "b": "c"}},
+			want: map[string]any{"b": 1, "c": 2, "keep": 3},
+		},
+		{
+			name: "flatten",
+			prev: map[string]any{"user": map[string]any{"name": "kim", "geo": map[string]any{"city": "Seoul"}}, "n": 1},
+			op:   upal.TransformOp{Op: "flatten", Separator: "_"},
+			want: map[string]any{"user_name": "kim", "user_geo_city": "Seoul", "n": 1},
+		},
+		{
+			name: "template",
+			prev: map[string]any{"title": "Launch", "count": 3},
+			op:   upal.TransformOp{Op: "template", Template: "{{title|upper}}: {{count}} items", Target: "summary"},
+			want: map[string]any{"title": "Launch", "count": 3, "summary": "LAUNCH: 3 items"},
+		},
+		{
+			name: "parse_json",
+			prev: map[string]any{"raw": `{"score": 7, "tags": ["a"]}`},
+			op:   upal.TransformOp{Op: "parse_json", Key: "raw", Target: "data"},
+			want: map[string]any{"raw": `{"score": 7, "tags": ["a"]}`, "data": map[string]any{"score": 7.0, "tags": []any{"a"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runTransform(t, tt.prev, tt.op)
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("output = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformStage_ChainedOperations(t *testing.T) {
+	prev := map[string]any{"response": `{"article": {"title": "Hi", "words": 120}, "debug": true}`}
+	got, err := runTransform(t, prev,
+		upal.TransformOp{Op: "parse_json", Key: "response"},
+		upal.TransformOp{Op: "flatten"},
+		upal.TransformOp{Op: "rename", Mapping: map[string]string{"response.article.title": "title", "response.article.words": "words"}},
+		upal.TransformOp{Op: "template", Template: "{{title}} ({{words}} words)", Target: "headline"},
+		upal.TransformOp{Op: "pick", Keys: []string{"headline", "title"}},
+	)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	want := map[string]any{"title": "Hi", "headline": "Hi (120 words)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("output = %v, want %v", got, want)
+	}
+}
+
+func TestTransformStage_OperationErrors(t *testing.T) {
+	if _, err := runTransform(t, map[string]any{"raw": "{not json"}, upal.TransformOp{Op: "parse_json", Key: "raw"}); err == nil || !strings.Contains(err.Error(), "parse_json") {
+		t.Errorf("invalid JSON err = %v, want parse_json error", err)
+	}
+	if _, err := runTransform(t, nil, upal.TransformOp{Op: "explode"}); err == nil || !strings.Contains(err.Error(), "unknown transform op") {
+		t.Errorf("unknown op err = %v", err)
+	}
+}
@@ -44,6 +44,10 @@ func (e *WorkflowStageExecutor) Execute(ctx context.Context, _ *upal.Pipeline, s
 		return nil, fmt.Errorf("workflow %q not found: %w", wfName, err)
 	}
 
+	if err := applyInputsFrom(ctx, wf, stage.Config.InputsFrom, inputs); err != nil {
+		return nil, fmt.Errorf("workflow %q: %w", wfName, err)
+	}
+
 	eventCh, resultCh, err := e.workflowSvc.Run(ctx, wf, inputs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start workflow %q: %w", wfName, err)
@@ -54,9 +58,52 @@ func (e *WorkflowStageExecutor) Execute(ctx context.Context, _ *upal.Pipeline, s
 
 	result := <-resultCh
 
+	// Downstream stages rely on the workflow's outputs contract.
+	if violations := wf.CheckOutputs(result.State); len(violations) > 0 {
+		return nil, fmt.Errorf("workflow %q: %s", wfName, upal.ContractError(violations))
+	}
+
 	return &upal.StageResult{
 		StageID: stage.ID,
 		Status:  upal.StageStatusCompleted,
 		Output:  result.State,
 	}, nil
 }
+
+// applyInputsFrom sets each input node named in inputsFrom to the earlier
+// stage output it references, taken from the stage outputs in ctx.
+func applyInputsFrom(ctx context.Context, wf *upal.WorkflowDefinition, inputsFrom map[string]string, inputs map[string]any) error {
+	if len(inputsFrom) == 0 {
+		return nil
+	}
+	outputs := upal.StageOutputsFromContext(ctx)
+	for nodeID, ref := range inputsFrom {
+		if !isWorkflowInput(wf, nodeID) {
+			return fmt.Errorf("inputs_from: %q is not an input node", nodeID)
+		}
+		stageID, key, ok := upal.ParseStageOutputRef(ref)
+		if !ok {
+			return fmt.Errorf("inputs_from %q: reference %q must be <stage_id>.<output_key>", nodeID, ref)
+		}
+		out, ok := outputs[stageID]
+		if !ok {
+			return fmt.Errorf("inputs_from %q: stage %q has not completed", nodeID, stageID)
+		}
+		val, ok := out[key]
+		if !ok {
+			return fmt.Errorf("inputs_from %q: stage %q has no output %q", nodeID, stageID, key)
+		}
+		inputs[nodeID] = val
+	}
+	return nil
+}
+
+// isWorkflowInput reports whether id names an input node of wf.
+func isWorkflowInput(wf *upal.WorkflowDefinition, id string) bool {
+	for _, n := range wf.Nodes {
+		if n.ID == id && n.Type == upal.NodeTypeInput {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// recordingWorkflowExecutor runs no nodes; it records the inputs each run
+// received and echoes them back as the final state.
+type recordingWorkflowExecutor struct {
+	workflows map[string]*upal.WorkflowDefinition
+	inputs    map[string]map[string]any
+}
+
+func (e *recordingWorkflowExecutor) Lookup(_ context.Context, name string) (*upal.WorkflowDefinition, error) {
+	if wf, ok := e.workflows[name]; ok {
+		return wf, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (e *recordingWorkflowExecutor) Validate(*upal.WorkflowDefinition) error { return nil }
+
+func (e *recordingWorkflowExecutor) Run(_ context.Context, wf *upal.WorkflowDefinition, inputs map[string]any) (<-chan upal.WorkflowEvent, <-chan upal.RunResult, error) {
+	e.inputs[wf.Name] = inputs
+	events := make(chan upal.WorkflowEvent)
+	close(events)
+	result := make(chan upal.RunResult, 1)
+	result <- upal.RunResult{State: inputs}
+	return events, result, nil
+}
+
+func newRecordingWorkflowExecutor() *recordingWorkflowExecutor {
+	return &recordingWorkflowExecutor{
+		workflows: map[string]*upal.WorkflowDefinition{
+			"summarise": {Name: "summarise", Nodes: []upal.NodeDefinition{
+				{ID: "article", Type: upal.NodeTypeInput},
+				{ID: "tone", Type: upal.NodeTypeInput},
+				{ID: "out", Type: upal.NodeTypeOutput},
+			}},
+		},
+		inputs: map[string]map[string]any{},
+	}
+}
+
+func TestWorkflowStageExecutor_InputsFrom(t *testing.T) {
+	wfExec := newRecordingWorkflowExecutor()
+	runner := NewPipelineRunner(repository.NewMemoryPipelineRunRepository())
+	runner.RegisterExecutor(&mockStageExecutor{stageType: "collect", output: map[string]any{"body": "Go 1.24 released"}})
+	runner.RegisterExecutor(&mockStageExecutor{stageType: "transform", output: map[string]any{"style": "casual"}})
+	runner.RegisterExecutor(NewWorkflowStageExecutor(wfExec))
+
+	pipeline := &upal.Pipeline{ID: "pipe-if", Stages: []upal.Stage{
+		{ID: "fetch", Type: "collect"},
+		{ID: "shape", Type: "transform"},
+		{ID: "sum", Type: "workflow", Config: upal.StageConfig{
+			WorkflowName: "summarise",
+			InputsFrom:   map[string]string{"article": "fetch.body", "tone": "shape.style"},
+		}},
+	}}
+	run, err := runner.Start(context.Background(), pipeline, nil)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	got := wfExec.inputs["summarise"]
+	if got["article"] != "Go 1.24 released" || got["tone"] != "casual" {
+		t.Errorf("workflow inputs = %v", got)
+	}
+	if out := run.StageResults["sum"].Output; out["article"] != "Go 1.24 released" {
+		t.Errorf("stage output = %v", out)
+	}
+}
+
+func TestWorkflowStageExecutor_InputsFromMissingOutput(t *testing.T) {
+	exec := NewWorkflowStageExecutor(newRecordingWorkflowExecutor())
+	ctx := upal.WithStageOutputs(context.Background(), map[string]map[string]any{"fetch": {"body": "x"}})
+	for ref, want := range map[string]string{
+		"fetch.title": `stage "fetch" has no output "title"`,
+		"later.body":  `stage "later" has not completed`,
+		"fetch":       "must be <stage_id>.<output_key>",
+	} {
+		stage := upal.Stage{ID: "sum", Type: "workflow", Config: upal.StageConfig{
+			WorkflowName: "summarise",
+			InputsFrom:   map[string]string{"article": ref},
+		}}
+		if _, err := exec.Execute(ctx, nil, stage, nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ref %q: err = %v, want %q", ref, err, want)
+		}
+	}
+}
+
+func TestValidatePipeline_InputsFrom(t *testing.T) {
+	p := &upal.Pipeline{Name: "p", Stages: []upal.Stage{
+		{ID: "fetch", Type: "transform"},
+		{ID: "sum", Type: "workflow", Config: upal.StageConfig{
+			WorkflowName: "summarise",
+			InputsFrom:   map[string]string{"article": "fetch.body", "topic": "fetch.title", "tone": "review.style"},
+		}},
+		{ID: "review", Type: "transform"},
+	}}
+	var got []string
+	for _, issue := range ValidatePipeline(context.Background(), p, newRecordingWorkflowExecutor(), nil) {
+		got = append(got, issue.StageID+" "+issue.Field+": "+issue.Message)
+	}
+	want := map[string]bool{
+		`sum config.inputs_from: workflow "summarise" has no input node "topic"`:      true,
+		`sum config.inputs_from: tone: stage "review" does not run before this stage`: true,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("issues = %q", got)
+	}
+	for _, g := range got {
+		if !want[g] {
+			t.Errorf("unexpected issue %q", g)
+		}
+	}
+}
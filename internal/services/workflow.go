@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -32,6 +33,10 @@ type WorkflowService struct {
 	toolReg        *tools.Registry
 	nodeRegistry   *agents.NodeRegistry
 	buildDeps      agents.BuildDeps
+	dedup          runDeduper
+	moderator      ports.Moderator
+	ctxProviders   map[string]upal.ProviderInfo
+	ctxConfig      map[string]any
 }
 
 func NewWorkflowService(
@@ -55,11 +60,23 @@ func NewWorkflowService(
 	}
 }
 
+// SetLayoutConfig sets the default model used by output nodes to generate
+// HTML layouts, or disables layout generation so they return plain text.
+func (s *WorkflowService) SetLayoutConfig(model string, disabled bool) {
+	s.buildDeps.LayoutModel = model
+	s.buildDeps.LayoutDisabled = disabled
+}
+
 func (s *WorkflowService) Lookup(ctx context.Context, name string) (*upal.WorkflowDefinition, error) {
 	return s.repo.Get(ctx, name)
 }
 
 func (s *WorkflowService) Validate(wf *upal.WorkflowDefinition) error {
+	switch wf.FailureMode {
+	case "", upal.FailureModeFailFast, upal.FailureModeBestEffort:
+	default:
+		return fmt.Errorf("unknown failure_mode %q (want fail_fast or best_effort)", wf.FailureMode)
+	}
 	for _, n := range wf.Nodes {
 		if n.Type != upal.NodeTypeAgent {
 			continue
@@ -79,7 +96,49 @@ func (s *WorkflowService) Validate(wf *upal.WorkflowDefinition) error {
 	return nil
 }
 
+// Run executes wf with inputs, streaming its events. When ctx is cancelled
+// with upal.ErrRunCancelled as the cause, the run ends without an error event
+// and its result holds the state of the nodes that completed.
 func (s *WorkflowService) Run(ctx context.Context, wf *upal.WorkflowDefinition, inputs map[string]any) (<-chan upal.WorkflowEvent, <-chan upal.RunResult, error) {
+	if overrides, ok := upal.ModelOverridesFromContext(ctx); ok {
+		wf = upal.ApplyModelOverrides(wf, overrides)
+	}
+	if wf.Moderate {
+		if err := s.moderateInputs(ctx, inputs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	inputState := make(map[string]any)
+	for k, v := range inputs {
+		inputState["__user_input__"+k] = v
+	}
+	// Run inputs from pipeline are passed under __run_inputs__ key.
+	if runInputs, ok := inputs["__run_inputs__"].(map[string]any); ok {
+		for k, v := range runInputs {
+			inputState["__run_input__"+k] = v
+		}
+		delete(inputState, "__user_input____run_inputs__")
+	}
+
+	// Outputs restored from a checkpoint are seeded into the session and
+	// flagged so the DAG reuses them instead of running those nodes.
+	restored, resuming := upal.RestoredOutputsFromContext(ctx)
+	for nodeID, output := range restored {
+		inputState[nodeID] = output
+		inputState[agents.RestoredKeyPrefix+nodeID] = true
+	}
+
+	if wf.DedupWindowSeconds > 0 && !resuming {
+		if key, err := dedupKey(ctx, wf, inputs); err == nil {
+			return s.runDeduped(ctx, key, wf, inputState)
+		}
+	}
+	return s.runWithState(ctx, wf, inputState)
+}
+
+// runWithState executes wf in a fresh session seeded with initialState.
+func (s *WorkflowService) runWithState(ctx context.Context, wf *upal.WorkflowDefinition, initialState map[string]any) (<-chan upal.WorkflowEvent, <-chan upal.RunResult, error) {
 	dagAgent, err := agents.NewDAGAgent(wf, s.nodeRegistry, s.buildDeps)
 	if err != nil {
 		return nil, nil, fmt.Errorf("build DAG: %w", err)
@@ -97,23 +156,11 @@ func (s *WorkflowService) Run(ctx context.Context, wf *upal.WorkflowDefinition,
 	sessionID := fmt.Sprintf("session-%d", time.Now().UnixNano())
 	userID := upal.UserIDFromContext(ctx)
 
-	inputState := make(map[string]any)
-	for k, v := range inputs {
-		inputState["__user_input__"+k] = v
-	}
-	// Run inputs from pipeline are passed under __run_inputs__ key.
-	if runInputs, ok := inputs["__run_inputs__"].(map[string]any); ok {
-		for k, v := range runInputs {
-			inputState["__run_input__"+k] = v
-		}
-		delete(inputState, "__user_input____run_inputs__")
-	}
-
 	_, err = s.sessionService.Create(ctx, &session.CreateRequest{
 		AppName:   wf.Name,
 		UserID:    userID,
 		SessionID: sessionID,
-		State:     inputState,
+		State:     initialState,
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("create session: %w", err)
@@ -132,7 +179,7 @@ func (s *WorkflowService) Run(ctx context.Context, wf *upal.WorkflowDefinition,
 		defer close(done)
 
 		nodeLogFn := agents.NodeLogFunc(func(nodeID, msg string) {
-			slog.Info("model-log", "node", nodeID, "msg", msg)
+			slog.InfoContext(ctx, "model-log", "node", nodeID, "msg", msg)
 			select {
 			case <-done:
 				return
@@ -147,8 +194,19 @@ func (s *WorkflowService) Run(ctx context.Context, wf *upal.WorkflowDefinition,
 		})
 		logCtx := agents.WithNodeLogFunc(ctx, nodeLogFn)
 
+		eventCh <- upal.WorkflowEvent{
+			Type:    upal.EventRunContext,
+			Payload: map[string]any{"run_context": s.runContext(wf)},
+		}
+
+		progress := newRunProgress(wf)
+		nodeErrors := make(map[string]string)
 		userContent := genai.NewContentFromText("run", genai.RoleUser)
 		for event, err := range adkRunner.Run(logCtx, userID, sessionID, userContent, agent.RunConfig{}) {
+			if err != nil && errors.Is(context.Cause(ctx), upal.ErrRunCancelled) {
+				// Cancelled on request: report what completed so far.
+				break
+			}
 			if err != nil {
 				eventCh <- upal.WorkflowEvent{
 					Type:    upal.EventError,
@@ -160,11 +218,17 @@ func (s *WorkflowService) Run(ctx context.Context, wf *upal.WorkflowDefinition,
 				continue
 			}
 			wfEvent := classifyEvent(event)
+			if wfEvent.Type == upal.EventNodeFailed {
+				nodeErrors[wfEvent.NodeID], _ = wfEvent.Payload["error"].(string)
+			}
 			eventCh <- wfEvent
+			if progressEvent, ok := progress.observe(wfEvent); ok {
+				eventCh <- progressEvent
+			}
 		}
 
 		finalState := make(map[string]any)
-		getResp, err := s.sessionService.Get(ctx, &session.GetRequest{
+		getResp, err := s.sessionService.Get(context.WithoutCancel(ctx), &session.GetRequest{
 			AppName:   wf.Name,
 			UserID:    userID,
 			SessionID: sessionID,
@@ -177,28 +241,60 @@ func (s *WorkflowService) Run(ctx context.Context, wf *upal.WorkflowDefinition,
 			}
 		}
 
-		// Collect output node results under __output__ for deterministic frontend access.
-		outputs := make(map[string]any)
-		for _, n := range wf.Nodes {
-			if n.Type == upal.NodeTypeOutput {
-				if v, ok := finalState[n.ID]; ok {
-					outputs[n.ID] = v
-				}
-			}
-		}
-		if len(outputs) > 0 {
-			finalState["__output__"] = outputs
+		// Collect every output node's result under __output__ for deterministic
+		// frontend access, including output nodes that feed later nodes.
+		allOutputs := collectOutputs(wf, finalState)
+		if len(allOutputs) > 0 {
+			finalState["__output__"] = allOutputs
 		}
+		outputs := terminalOutputs(wf, allOutputs)
 
-		resultCh <- upal.RunResult{
+		result := upal.RunResult{
 			SessionID: sessionID,
 			State:     finalState,
+			Outputs:   outputs,
+		}
+		if len(nodeErrors) > 0 {
+			result.NodeErrors = nodeErrors
 		}
+		resultCh <- result
 	}()
 
 	return eventCh, resultCh, nil
 }
 
+// collectOutputs returns the results of all of wf's output nodes keyed by
+// node ID.
+func collectOutputs(wf *upal.WorkflowDefinition, state map[string]any) map[string]any {
+	outputs := make(map[string]any)
+	for _, n := range wf.Nodes {
+		if n.Type != upal.NodeTypeOutput {
+			continue
+		}
+		if v, ok := state[n.ID]; ok {
+			outputs[n.ID] = v
+		}
+	}
+	return outputs
+}
+
+// terminalOutputs returns the entries of outputs whose node has no outgoing
+// edges. An output node that feeds other nodes is an intermediate step, so it
+// is left out of the run result's Outputs, while __output__ keeps it.
+func terminalOutputs(wf *upal.WorkflowDefinition, outputs map[string]any) map[string]any {
+	hasOutgoing := make(map[string]bool, len(wf.Edges))
+	for _, e := range wf.Edges {
+		hasOutgoing[e.From] = true
+	}
+	terminal := make(map[string]any, len(outputs))
+	for id, v := range outputs {
+		if !hasOutgoing[id] {
+			terminal[id] = v
+		}
+	}
+	return terminal
+}
+
 func classifyEvent(event *session.Event) upal.WorkflowEvent {
 	nodeID := event.Author
 	content := event.LLMResponse.Content
@@ -209,7 +305,26 @@ func classifyEvent(event *session.Event) upal.WorkflowEvent {
 			return upal.WorkflowEvent{Type: upal.EventNodeSkipped, NodeID: nodeID, Payload: map[string]any{"node_id": nodeID}}
 		case "waiting":
 			return upal.WorkflowEvent{Type: upal.EventNodeWaiting, NodeID: nodeID, Payload: map[string]any{"node_id": nodeID}}
+		case string(upal.NodeStatusFailed):
+			errMsg, _ := event.Actions.StateDelta[nodeID+agents.NodeErrorSuffix].(string)
+			return upal.WorkflowEvent{Type: upal.EventNodeFailed, NodeID: nodeID, Payload: map[string]any{"node_id": nodeID, "error": errMsg}}
+		case string(upal.NodeStatusRestored):
+			output := event.Actions.StateDelta[nodeID]
+			return upal.WorkflowEvent{Type: upal.EventNodeCompleted, NodeID: nodeID, Payload: map[string]any{
+				"node_id":     nodeID,
+				"output":      fmt.Sprintf("%v", output),
+				"state_delta": map[string]any{nodeID: output},
+				"restored":    true,
+			}}
+		}
+	}
+
+	if call, ok := event.LLMResponse.CustomMetadata["llm_call"].(map[string]any); ok {
+		payload := map[string]any{"node_id": nodeID}
+		for k, v := range call {
+			payload[k] = v
 		}
+		return upal.WorkflowEvent{Type: upal.EventLLMCall, NodeID: nodeID, Payload: payload}
 	}
 
 	if content == nil || len(content.Parts) == 0 {
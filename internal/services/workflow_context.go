@@ -0,0 +1,104 @@
+package services
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/soochol/upal/internal/output"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// SetRunContextSource sets the provider descriptions and redacted config
+// snapshot attached to every run's RunContext.
+func (s *WorkflowService) SetRunContextSource(providers map[string]upal.ProviderInfo, config map[string]any) {
+	s.ctxProviders = providers
+	s.ctxConfig = config
+}
+
+// runContext captures the effective models, tools and environment wf will
+// execute with. Model overrides must already be applied to wf.
+func (s *WorkflowService) runContext(wf *upal.WorkflowDefinition) *upal.RunContext {
+	rc := &upal.RunContext{
+		CapturedAt:    time.Now(),
+		ServerVersion: serverVersion(),
+		Config:        s.ctxConfig,
+	}
+	used := make(map[string]bool)
+	for _, n := range wf.Nodes {
+		nc := upal.NodeContext{NodeID: n.ID, Type: n.Type}
+		if modelID, ok := s.nodeModelID(n); ok {
+			nc.Model = modelID
+			if modelID != "" {
+				nc.Provider, _, _ = strings.Cut(modelID, "/")
+				used[nc.Provider] = true
+			}
+			if s.llmResolver != nil {
+				if _, name, err := s.llmResolver.Resolve(modelID); err != nil {
+					nc.ResolveError = err.Error()
+				} else {
+					nc.ResolvedModel = name
+				}
+			}
+		}
+		toolNames, _ := n.Config["tools"].([]any)
+		for _, t := range toolNames {
+			name, _ := t.(string)
+			if name == "" {
+				continue
+			}
+			tc := upal.ToolContext{Name: name}
+			if s.toolReg != nil {
+				tc.Native = s.toolReg.IsNative(name)
+				_, registered := s.toolReg.Get(name)
+				tc.Available = registered || tc.Native
+			}
+			nc.Tools = append(nc.Tools, tc)
+		}
+		rc.Nodes = append(rc.Nodes, nc)
+	}
+	for name, info := range s.ctxProviders {
+		if used[name] {
+			if rc.Providers == nil {
+				rc.Providers = make(map[string]upal.ProviderInfo)
+			}
+			rc.Providers[name] = info
+		}
+	}
+	return rc
+}
+
+// nodeModelID reports the model n calls, chosen the way its builder chooses
+// it, and whether it calls one at all. An empty ID means the resolver's
+// default model.
+func (s *WorkflowService) nodeModelID(n upal.NodeDefinition) (string, bool) {
+	switch n.Type {
+	case upal.NodeTypeOutput:
+		return output.LayoutModelID(n.Config, output.Layout{
+			DefaultModel: s.buildDeps.LayoutModel,
+			Disabled:     s.buildDeps.LayoutDisabled,
+		})
+	case upal.NodeTypeAgent:
+		modelID, _ := n.Config["model"].(string)
+		return modelID, true
+	default:
+		modelID, _ := n.Config["model"].(string)
+		return modelID, modelID != ""
+	}
+}
+
+// serverVersion identifies the running build: the VCS revision when
+// available, otherwise the main module version.
+var serverVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+	return info.Main.Version
+})
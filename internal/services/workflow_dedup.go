@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// runFlight is a run that identical requests may attach to while its dedup
+// window is open.
+type runFlight struct {
+	started time.Time
+	done    chan struct{} // closed once result/err are final
+
+	result upal.RunResult
+	ok     bool   // result was produced
+	err    string // run failed to start or emitted an error event
+}
+
+// runDeduper tracks in-flight runs keyed by user, workflow and inputs.
+type runDeduper struct {
+	mu      sync.Mutex
+	flights map[string]*runFlight
+}
+
+// join returns the open flight for key, or registers a new one. leader is
+// true when the caller must execute the run itself.
+func (d *runDeduper) join(key string, window time.Duration) (f *runFlight, leader bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if f := d.flights[key]; f != nil && time.Since(f.started) < window {
+		return f, false
+	}
+	if d.flights == nil {
+		d.flights = make(map[string]*runFlight)
+	}
+	f = &runFlight{started: time.Now(), done: make(chan struct{})}
+	d.flights[key] = f
+	return f, true
+}
+
+// forget removes key if it still refers to f.
+func (d *runDeduper) forget(key string, f *runFlight) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.flights[key] == f {
+		delete(d.flights, key)
+	}
+}
+
+// dedupKey hashes everything that makes two run requests interchangeable.
+func dedupKey(ctx context.Context, wf *upal.WorkflowDefinition, inputs map[string]any) (string, error) {
+	overrides, _ := upal.ModelOverridesFromContext(ctx)
+	b, err := json.Marshal(struct {
+		User      string              `json:"user"`
+		Workflow  string              `json:"workflow"`
+		Overrides upal.ModelOverrides `json:"overrides"`
+		Inputs    map[string]any      `json:"inputs"`
+	}{upal.UserIDFromContext(ctx), wf.Name, overrides, inputs})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// runDeduped executes wf unless an identical run started within the
+// workflow's dedup window, in which case the caller shares that run's result.
+func (s *WorkflowService) runDeduped(ctx context.Context, key string, wf *upal.WorkflowDefinition, initialState map[string]any) (<-chan upal.WorkflowEvent, <-chan upal.RunResult, error) {
+	window := time.Duration(wf.DedupWindowSeconds) * time.Second
+	f, leader := s.dedup.join(key, window)
+	if !leader {
+		slog.InfoContext(ctx, "coalescing duplicate run", "workflow", wf.Name, "started", f.started)
+		events, result := f.follow(ctx)
+		return events, result, nil
+	}
+
+	events, results, err := s.runWithState(ctx, wf, initialState)
+	if err != nil {
+		f.err = err.Error()
+		close(f.done)
+		s.dedup.forget(key, f)
+		return nil, nil, err
+	}
+
+	eventCh := make(chan upal.WorkflowEvent, 64)
+	resultCh := make(chan upal.RunResult, 1)
+	go func() {
+		defer close(resultCh)
+		for ev := range events {
+			if ev.Type == upal.EventError && f.err == "" {
+				f.err, _ = ev.Payload["error"].(string)
+				if f.err == "" {
+					f.err = "run failed"
+				}
+			}
+			eventCh <- ev
+		}
+		close(eventCh)
+
+		res, ok := <-results
+		f.result, f.ok = res, ok
+		close(f.done)
+		if ok {
+			resultCh <- res
+		}
+
+		// Failed runs are not shared with later duplicates so they can retry.
+		if f.err != "" || !ok {
+			s.dedup.forget(key, f)
+			return
+		}
+		time.AfterFunc(time.Until(f.started.Add(window)), func() { s.dedup.forget(key, f) })
+	}()
+	return eventCh, resultCh, nil
+}
+
+// follow returns channels that deliver f's outcome to a duplicate caller.
+// No node events are replayed; the event channel closes once the shared run
+// finishes, carrying a single error event if it failed.
+func (f *runFlight) follow(ctx context.Context) (<-chan upal.WorkflowEvent, <-chan upal.RunResult) {
+	eventCh := make(chan upal.WorkflowEvent, 1)
+	resultCh := make(chan upal.RunResult, 1)
+	go func() {
+		defer close(resultCh)
+		defer close(eventCh)
+		select {
+		case <-ctx.Done():
+			eventCh <- upal.WorkflowEvent{Type: upal.EventError, Payload: map[string]any{"error": ctx.Err().Error()}}
+			return
+		case <-f.done:
+		}
+		switch {
+		case f.err != "":
+			eventCh <- upal.WorkflowEvent{Type: upal.EventError, Payload: map[string]any{"error": f.err}}
+		case !f.ok:
+			eventCh <- upal.WorkflowEvent{Type: upal.EventError, Payload: map[string]any{"error": "deduplicated run ended without a result"}}
+		default:
+			resultCh <- f.result
+		}
+	}()
+	return eventCh, resultCh
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+// SetModerator enables the input moderation pre-check for workflows that
+// opt in with WorkflowDefinition.Moderate.
+func (s *WorkflowService) SetModerator(m ports.Moderator) { s.moderator = m }
+
+// moderateInputs screens the text in inputs. It returns an error wrapping
+// upal.ErrModerationBlocked when any category is flagged. Runs fail closed:
+// a workflow that asks for moderation does not run when it is unavailable.
+func (s *WorkflowService) moderateInputs(ctx context.Context, inputs map[string]any) error {
+	if s.moderator == nil {
+		return fmt.Errorf("workflow requires moderation but no moderation provider is configured")
+	}
+	texts := collectTexts(inputs, nil)
+	if len(texts) == 0 {
+		return nil
+	}
+	flagged, err := s.moderator.Moderate(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("moderation check: %w", err)
+	}
+	if len(flagged) > 0 {
+		return fmt.Errorf("%w: %s", upal.ErrModerationBlocked, strings.Join(flagged, ", "))
+	}
+	return nil
+}
+
+// collectTexts appends every non-empty string in v, walking maps in key
+// order and slices in index order.
+func collectTexts(v any, texts []string) []string {
+	switch v := v.(type) {
+	case string:
+		if strings.TrimSpace(v) != "" {
+			texts = append(texts, v)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			texts = collectTexts(v[k], texts)
+		}
+	case []any:
+		for _, item := range v {
+			texts = collectTexts(item, texts)
+		}
+	}
+	return texts
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/agents"
+	upalmodel "github.com/soochol/upal/internal/model"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+	"google.golang.org/adk/session"
+)
+
+// newModerationServer mocks an OpenAI-compatible /moderations endpoint that
+// flags any input containing "attack".
+func newModerationServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/moderations" {
+			t.Errorf("path = %s, want /moderations", r.URL.Path)
+		}
+		var req struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		type result struct {
+			Flagged    bool            `json:"flagged"`
+			Categories map[string]bool `json:"categories"`
+		}
+		var results []result
+		for _, in := range req.Input {
+			bad := strings.Contains(in, "attack")
+			results = append(results, result{Flagged: bad, Categories: map[string]bool{"violence": bad, "hate": false}})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRun_ModerationBlocksFlaggedInputs(t *testing.T) {
+	mod := newModerationServer(t)
+	svc := NewWorkflowService(repository.NewMemory(), nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+	svc.SetModerator(upalmodel.NewOpenAIModerator("", mod.URL, ""))
+
+	wf := &upal.WorkflowDefinition{
+		Name:     "moderated",
+		Moderate: true,
+		Nodes: []upal.NodeDefinition{
+			{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "output1", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "input1", To: "output1"}},
+	}
+
+	_, _, err := svc.Run(context.Background(), wf, map[string]any{"input1": "plan an attack"})
+	if !errors.Is(err, upal.ErrModerationBlocked) {
+		t.Fatalf("flagged input: err = %v, want ErrModerationBlocked", err)
+	}
+	if !strings.Contains(err.Error(), "violence") {
+		t.Errorf("error %q does not name the flagged category", err)
+	}
+
+	events, result, err := svc.Run(context.Background(), wf, map[string]any{"input1": "plan a picnic"})
+	if err != nil {
+		t.Fatalf("clean input: unexpected error: %v", err)
+	}
+	for range events {
+	}
+	if res := <-result; res.State["output1"] == nil {
+		t.Errorf("clean run produced no output: %v", res.State)
+	}
+}
+
+func TestRun_ModerationIsOptIn(t *testing.T) {
+	svc := NewWorkflowService(repository.NewMemory(), nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+	wf := &upal.WorkflowDefinition{
+		Name:  "unmoderated",
+		Nodes: []upal.NodeDefinition{{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}}},
+	}
+	// No moderator is configured, but the workflow did not opt in.
+	events, _, err := svc.Run(context.Background(), wf, map[string]any{"input1": "plan an attack"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range events {
+	}
+
+	wf.Moderate = true
+	if _, _, err := svc.Run(context.Background(), wf, map[string]any{"input1": "hi"}); err == nil {
+		t.Error("expected an error when moderation is required but unavailable")
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// RunNode executes a single node of wf in isolation. values seeds the session
+// state so {{refs}} to upstream nodes resolve without running them; each
+// value is also exposed as user input so input nodes can be tested too.
+// With dryRun set, agent nodes skip the LLM call and only report their
+// resolved prompt.
+func (s *WorkflowService) RunNode(ctx context.Context, wf *upal.WorkflowDefinition, nodeID string, values map[string]any, dryRun bool) (*upal.NodeTestResult, error) {
+	var node *upal.NodeDefinition
+	for i := range wf.Nodes {
+		if wf.Nodes[i].ID == nodeID {
+			node = &wf.Nodes[i]
+			break
+		}
+	}
+	if node == nil {
+		return nil, fmt.Errorf("node %q not found in workflow %q", nodeID, wf.Name)
+	}
+
+	result := &upal.NodeTestResult{NodeID: nodeID, DryRun: dryRun}
+	if node.Type == upal.NodeTypeAgent {
+		promptTpl, _ := node.Config["prompt"].(string)
+		result.Prompt = agents.ResolveTemplate(promptTpl, values)
+		if dryRun {
+			return result, nil
+		}
+	}
+
+	single := &upal.WorkflowDefinition{
+		Name:  wf.Name,
+		Nodes: []upal.NodeDefinition{*node},
+	}
+	state := make(map[string]any, len(values)*2)
+	for k, v := range values {
+		state[k] = v
+		state["__user_input__"+k] = v
+	}
+
+	events, results, err := s.runWithState(ctx, single, state)
+	if err != nil {
+		return nil, err
+	}
+	var runErr error
+	for ev := range events {
+		switch ev.Type {
+		case upal.EventToolCall:
+			if calls, ok := ev.Payload["calls"].([]map[string]any); ok {
+				result.ToolCalls = append(result.ToolCalls, calls...)
+			}
+		case upal.EventToolResult:
+			if res, ok := ev.Payload["results"].([]map[string]any); ok {
+				result.ToolResults = append(result.ToolResults, res...)
+			}
+		case upal.EventError:
+			runErr = fmt.Errorf("node %q: %v", nodeID, ev.Payload["error"])
+		}
+	}
+	if runErr != nil {
+		return nil, runErr
+	}
+	final := <-results
+	result.Output = final.State[nodeID]
+	return result, nil
+}
+
+// ResolveTemplates previews wf's resolved prompts for the given inputs
+// without calling any LLM or tool; see agents.SimulateTemplates.
+func (s *WorkflowService) ResolveTemplates(wf *upal.WorkflowDefinition, inputs map[string]any) ([]upal.ResolvedNodeTemplate, error) {
+	return agents.SimulateTemplates(wf, inputs)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/soochol/upal/internal/agents"
+	"github.com/soochol/upal/internal/llmutil"
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/tools"
+	"github.com/soochol/upal/internal/upal"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// scriptedLLM replays its replies in order and records each prompt it saw.
+type scriptedLLM struct {
+	mu      sync.Mutex
+	replies []*genai.Content
+	prompts []string
+}
+
+func (l *scriptedLLM) Name() string { return "scripted" }
+
+func (l *scriptedLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	l.mu.Lock()
+	var text []string
+	for _, p := range req.Contents[0].Parts {
+		text = append(text, p.Text)
+	}
+	l.prompts = append(l.prompts, strings.Join(text, ""))
+	reply := genai.NewContentFromText("done", genai.RoleModel)
+	if len(l.replies) > 0 {
+		reply, l.replies = l.replies[0], l.replies[1:]
+	}
+	l.mu.Unlock()
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: reply}, nil)
+	}
+}
+
+type upperTool struct{}
+
+func (upperTool) Name() string                { return "upper" }
+func (upperTool) Description() string         { return "Uppercases text" }
+func (upperTool) InputSchema() map[string]any { return map[string]any{"type": "object"} }
+func (upperTool) Execute(_ context.Context, input any) (any, error) {
+	args, _ := input.(map[string]any)
+	s, _ := args["text"].(string)
+	return strings.ToUpper(s), nil
+}
+
+func newNodeTestService(llm adkmodel.LLM, toolReg *tools.Registry) *WorkflowService {
+	llms := map[string]adkmodel.LLM{"stub": llm}
+	resolver := llmutil.NewMapResolver(llms, nil, "")
+	return NewWorkflowService(repository.NewMemory(), llms, session.InMemoryService(), toolReg, agents.DefaultRegistry(), "", "", resolver)
+}
+
+func TestRunNode_AgentWithTemplateValues(t *testing.T) {
+	llm := &scriptedLLM{}
+	svc := newNodeTestService(llm, nil)
+	wf := &upal.WorkflowDefinition{
+		Name: "node-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "topic", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "research", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "stub/m", "prompt": "Write about {{topic}}"}},
+			{ID: "writer", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "stub/m", "prompt": "Topic {{topic}}, notes: {{research}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "topic", To: "research"}, {From: "research", To: "writer"}},
+	}
+	values := map[string]any{"topic": "go", "research": "goroutines"}
+
+	dry, err := svc.RunNode(context.Background(), wf, "writer", values, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.Prompt != "Topic go, notes: goroutines" || dry.Output != nil {
+		t.Errorf("dry run = %+v", dry)
+	}
+	if len(llm.prompts) != 0 {
+		t.Fatalf("dry run called the LLM: %v", llm.prompts)
+	}
+
+	res, err := svc.RunNode(context.Background(), wf, "writer", values, false)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(llm.prompts) != 1 || llm.prompts[0] != "Topic go, notes: goroutines" {
+		t.Errorf("prompts = %v, want only the writer prompt with mocked refs", llm.prompts)
+	}
+	if res.Output != "done" {
+		t.Errorf("output = %v, want done", res.Output)
+	}
+
+	if _, err := svc.RunNode(context.Background(), wf, "missing", nil, false); err == nil {
+		t.Error("expected error for unknown node")
+	}
+}
+
+func TestRunNode_ToolCalls(t *testing.T) {
+	llm := &scriptedLLM{replies: []*genai.Content{
+		{Role: genai.RoleModel, Parts: []*genai.Part{genai.NewPartFromFunctionCall("upper", map[string]any{"text": "hi"})}},
+		genai.NewContentFromText("HI", genai.RoleModel),
+	}}
+	reg := tools.NewRegistry()
+	reg.Register(upperTool{})
+	svc := newNodeTestService(llm, reg)
+	wf := &upal.WorkflowDefinition{
+		Name: "tool-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "shout", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "stub/m", "prompt": "Shout {{word}}", "tools": []any{"upper"}}},
+		},
+	}
+
+	res, err := svc.RunNode(context.Background(), wf, "shout", map[string]any{"word": "hi"}, false)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(res.ToolCalls) != 1 || res.ToolCalls[0]["name"] != "upper" {
+		t.Errorf("tool calls = %v", res.ToolCalls)
+	}
+	if len(res.ToolResults) != 1 {
+		t.Errorf("tool results = %v", res.ToolResults)
+	}
+	if res.Output != "HI" {
+		t.Errorf("output = %v, want HI", res.Output)
+	}
+}
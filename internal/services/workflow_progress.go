@@ -0,0 +1,74 @@
+package services
+
+import "github.com/soochol/upal/internal/upal"
+
+// runProgress derives progress events from a run's node lifecycle events.
+// Completed, skipped and (best_effort) failed nodes all count as finished so
+// a run with untaken branches still reaches 100%.
+type runProgress struct {
+	nodes    map[string]bool
+	finished map[string]bool
+	running  []string // started but not finished, in start order
+}
+
+func newRunProgress(wf *upal.WorkflowDefinition) *runProgress {
+	nodes := make(map[string]bool, len(wf.Nodes))
+	for _, n := range wf.Nodes {
+		nodes[n.ID] = true
+	}
+	return &runProgress{nodes: nodes, finished: make(map[string]bool, len(wf.Nodes))}
+}
+
+// observe records ev and returns a progress event when it finishes a node
+// for the first time.
+func (p *runProgress) observe(ev upal.WorkflowEvent) (upal.WorkflowEvent, bool) {
+	if !p.nodes[ev.NodeID] {
+		return upal.WorkflowEvent{}, false
+	}
+	switch ev.Type {
+	case upal.EventNodeStarted, upal.EventNodeResumed:
+		p.removeRunning(ev.NodeID)
+		p.running = append(p.running, ev.NodeID)
+		return upal.WorkflowEvent{}, false
+	case upal.EventNodeCompleted, upal.EventNodeSkipped, upal.EventNodeFailed:
+		p.removeRunning(ev.NodeID)
+		if p.finished[ev.NodeID] {
+			return upal.WorkflowEvent{}, false
+		}
+		p.finished[ev.NodeID] = true
+	default:
+		return upal.WorkflowEvent{}, false
+	}
+
+	current := ""
+	if len(p.running) > 0 {
+		current = p.running[len(p.running)-1]
+	}
+	return upal.WorkflowEvent{
+		Type:   upal.EventProgress,
+		NodeID: ev.NodeID,
+		Payload: map[string]any{
+			"node_id":      ev.NodeID,
+			"completed":    len(p.finished),
+			"total":        len(p.nodes),
+			"percent":      p.percent(),
+			"current_node": current,
+		},
+	}, true
+}
+
+func (p *runProgress) percent() int {
+	if len(p.nodes) == 0 {
+		return 100
+	}
+	return len(p.finished) * 100 / len(p.nodes)
+}
+
+func (p *runProgress) removeRunning(nodeID string) {
+	for i, id := range p.running {
+		if id == nodeID {
+			p.running = append(p.running[:i], p.running[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+	"github.com/soochol/upal/internal/upal/ports"
+)
+
+// suggestMinTermLen skips short words ("a", "to", "of") in keyword matching.
+const suggestMinTermLen = 3
+
+// WorkflowSuggestService ranks existing workflows by similarity to a task
+// description so they can be reused instead of generating a new one. It uses
+// cosine similarity of embeddings when an Embedder is set and falls back to
+// keyword overlap otherwise.
+type WorkflowSuggestService struct {
+	repo     repository.WorkflowRepository
+	embedder ports.Embedder
+
+	mu    sync.Mutex
+	cache map[string]suggestEmbedding // workflow name → embedding of its text
+}
+
+// suggestEmbedding is a cached workflow embedding and the hash of the text it
+// was computed from; an edited workflow replaces its entry.
+type suggestEmbedding struct {
+	hash   [sha256.Size]byte
+	vector []float64
+}
+
+func NewWorkflowSuggestService(repo repository.WorkflowRepository) *WorkflowSuggestService {
+	return &WorkflowSuggestService{repo: repo, cache: make(map[string]suggestEmbedding)}
+}
+
+// SetEmbedder enables embedding-based ranking.
+func (s *WorkflowSuggestService) SetEmbedder(e ports.Embedder) { s.embedder = e }
+
+// Suggest returns up to limit workflows most similar to description, best first.
+func (s *WorkflowSuggestService) Suggest(ctx context.Context, description string, limit int) ([]upal.WorkflowSuggestion, error) {
+	wfs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list workflows: %w", err)
+	}
+
+	var suggestions []upal.WorkflowSuggestion
+	if s.embedder != nil {
+		suggestions, err = s.rankByEmbedding(ctx, description, wfs)
+		if err != nil {
+			slog.WarnContext(ctx, "workflow suggest: embedding failed, using keyword match", "err", err)
+			suggestions = nil
+		}
+	}
+	if s.embedder == nil || err != nil {
+		suggestions = rankByKeywords(description, wfs)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].Score > suggestions[j].Score })
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+func (s *WorkflowSuggestService) rankByEmbedding(ctx context.Context, description string, wfs []*upal.WorkflowDefinition) ([]upal.WorkflowSuggestion, error) {
+	texts := []string{description}
+	var stale []*upal.WorkflowDefinition
+	s.mu.Lock()
+	for _, wf := range wfs {
+		if t := suggestText(wf); t != "" {
+			if e, ok := s.cache[wf.Name]; !ok || e.hash != sha256.Sum256([]byte(t)) {
+				texts = append(texts, t)
+				stale = append(stale, wf)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	vectors, err := s.embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, wf := range stale {
+		s.cache[wf.Name] = suggestEmbedding{hash: sha256.Sum256([]byte(texts[i+1])), vector: vectors[i+1]}
+	}
+	query := vectors[0]
+	var out []upal.WorkflowSuggestion
+	for _, wf := range wfs {
+		if suggestText(wf) == "" {
+			continue
+		}
+		out = append(out, upal.WorkflowSuggestion{
+			Name:        wf.Name,
+			Description: wf.Description,
+			Score:       cosineSimilarity(query, s.cache[wf.Name].vector),
+			Method:      "embedding",
+		})
+	}
+	return out, nil
+}
+
+// rankByKeywords scores each workflow by the fraction of description terms
+// found in its name and description. Workflows sharing no terms are dropped.
+func rankByKeywords(description string, wfs []*upal.WorkflowDefinition) []upal.WorkflowSuggestion {
+	var terms []string
+	for _, t := range strings.Fields(strings.ToLower(description)) {
+		t = strings.Trim(t, ".,;:!?\"'()")
+		if len(t) >= suggestMinTermLen {
+			terms = append(terms, t)
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var out []upal.WorkflowSuggestion
+	for _, wf := range wfs {
+		text := strings.ToLower(suggestText(wf))
+		matched := 0
+		for _, t := range terms {
+			if strings.Contains(text, t) {
+				matched++
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+		out = append(out, upal.WorkflowSuggestion{
+			Name:        wf.Name,
+			Description: wf.Description,
+			Score:       float64(matched) / float64(len(terms)),
+			Method:      "keyword",
+		})
+	}
+	return out
+}
+
+// suggestText is the text compared against a task description.
+func suggestText(wf *upal.WorkflowDefinition) string {
+	return strings.TrimSpace(wf.Name + "\n" + wf.Description)
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
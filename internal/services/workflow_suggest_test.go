@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// topicEmbedder embeds text as counts of a few topic words, so similarity is
+// driven by shared topics rather than exact wording.
+type topicEmbedder struct {
+	calls int
+	texts int
+	err   error
+}
+
+func (e *topicEmbedder) Embed(_ context.Context, texts []string) ([][]float64, error) {
+	e.calls++
+	e.texts += len(texts)
+	if e.err != nil {
+		return nil, e.err
+	}
+	topics := []string{"news", "image", "email"}
+	out := make([][]float64, len(texts))
+	for i, t := range texts {
+		v := make([]float64, len(topics))
+		for j, topic := range topics {
+			v[j] = float64(strings.Count(strings.ToLower(t), topic))
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func seedSuggestWorkflows(t *testing.T) repository.WorkflowRepository {
+	t.Helper()
+	repo := repository.NewMemory()
+	for _, wf := range []*upal.WorkflowDefinition{
+		{Name: "image-maker", Description: "Generate an image from a prompt"},
+		{Name: "news-digest", Description: "Summarize today's news headlines"},
+		{Name: "email-reply", Description: "Draft an email reply"},
+	} {
+		if err := repo.Create(context.Background(), wf); err != nil {
+			t.Fatalf("create %s: %v", wf.Name, err)
+		}
+	}
+	return repo
+}
+
+func TestWorkflowSuggest_EmbeddingRanksMostSimilarFirst(t *testing.T) {
+	svc := NewWorkflowSuggestService(seedSuggestWorkflows(t))
+	emb := &topicEmbedder{}
+	svc.SetEmbedder(emb)
+
+	got, err := svc.Suggest(context.Background(), "a morning news briefing", 2)
+	if err != nil {
+		t.Fatalf("Suggest: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d suggestions, want 2", len(got))
+	}
+	if got[0].Name != "news-digest" || got[0].Method != "embedding" {
+		t.Errorf("top suggestion: got %+v, want news-digest via embedding", got[0])
+	}
+
+	// Workflow embeddings are cached; only the query is embedded again.
+	if _, err := svc.Suggest(context.Background(), "image please", 1); err != nil {
+		t.Fatalf("Suggest: %v", err)
+	}
+	if emb.calls != 2 {
+		t.Errorf("embed calls: got %d, want 2", emb.calls)
+	}
+}
+
+func TestWorkflowSuggest_EditReplacesCachedEmbedding(t *testing.T) {
+	repo := seedSuggestWorkflows(t)
+	svc := NewWorkflowSuggestService(repo)
+	emb := &topicEmbedder{}
+	svc.SetEmbedder(emb)
+	ctx := context.Background()
+
+	if _, err := svc.Suggest(ctx, "news email", 1); err != nil {
+		t.Fatalf("Suggest: %v", err)
+	}
+	for i := range 3 {
+		wf, _ := repo.Get(ctx, "email-reply")
+		wf.Description = strings.Repeat("news ", i+1) + "email"
+		repo.Update(ctx, wf.Name, wf)
+		got, err := svc.Suggest(ctx, "news email", 1)
+		if err != nil {
+			t.Fatalf("Suggest: %v", err)
+		}
+		if got[0].Name != "email-reply" {
+			t.Errorf("edit %d: top suggestion %s, want the edited email-reply", i, got[0].Name)
+		}
+	}
+	// One entry per workflow, however often it is edited.
+	if len(svc.cache) != 3 {
+		t.Errorf("cache holds %d entries, want 3", len(svc.cache))
+	}
+	// 1 query + 3 workflows, then 1 query + the edited workflow per edit.
+	if emb.texts != 4+3*2 {
+		t.Errorf("embedded %d texts, want %d", emb.texts, 4+3*2)
+	}
+}
+
+func TestWorkflowSuggest_KeywordFallback(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		embedder *topicEmbedder
+	}{
+		{"no embedder", nil},
+		{"embedder error", &topicEmbedder{err: errors.New("provider down")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewWorkflowSuggestService(seedSuggestWorkflows(t))
+			if tc.embedder != nil {
+				svc.SetEmbedder(tc.embedder)
+			}
+			got, err := svc.Suggest(context.Background(), "draft a polite email reply", 5)
+			if err != nil {
+				t.Fatalf("Suggest: %v", err)
+			}
+			if len(got) == 0 || got[0].Name != "email-reply" || got[0].Method != "keyword" {
+				t.Fatalf("suggestions: got %+v, want email-reply first via keyword", got)
+			}
+		})
+	}
+}
@@ -2,11 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
+	"iter"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/soochol/upal/internal/agents"
 	"github.com/soochol/upal/internal/llmutil"
 	"github.com/soochol/upal/internal/repository"
+	"github.com/soochol/upal/internal/tools"
 	"github.com/soochol/upal/internal/upal"
 	adkmodel "google.golang.org/adk/model"
 	"google.golang.org/adk/session"
@@ -197,3 +202,462 @@ func TestRun_InputOutput(t *testing.T) {
 		t.Error("expected non-empty session ID")
 	}
 }
+
+func TestRun_EmitsProgress(t *testing.T) {
+	repo := repository.NewMemory()
+	svc := NewWorkflowService(repo, nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+
+	wf := &upal.WorkflowDefinition{
+		Name: "progress-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "output1", Type: upal.NodeTypeOutput, Config: map[string]any{}},
+		},
+		Edges: []upal.EdgeDefinition{
+			{From: "input1", To: "output1"},
+		},
+	}
+
+	events, result, err := svc.Run(context.Background(), wf, map[string]any{"input1": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var progress []upal.WorkflowEvent
+	for ev := range events {
+		if ev.Type == upal.EventProgress {
+			progress = append(progress, ev)
+		}
+	}
+	<-result
+
+	if len(progress) != 2 {
+		t.Fatalf("expected 2 progress events, got %d", len(progress))
+	}
+	want := []struct {
+		node      string
+		completed int
+		percent   int
+	}{
+		{"input1", 1, 50},
+		{"output1", 2, 100},
+	}
+	for i, w := range want {
+		p := progress[i].Payload
+		if p["node_id"] != w.node {
+			t.Errorf("event %d: node_id = %v, want %s", i, p["node_id"], w.node)
+		}
+		if p["completed"] != w.completed || p["total"] != 2 {
+			t.Errorf("event %d: completed/total = %v/%v, want %d/2", i, p["completed"], p["total"], w.completed)
+		}
+		if p["percent"] != w.percent {
+			t.Errorf("event %d: percent = %v, want %d", i, p["percent"], w.percent)
+		}
+		if _, ok := p["current_node"]; !ok {
+			t.Errorf("event %d: missing current_node", i)
+		}
+	}
+}
+
+// recordingLLM answers every request with a fixed reply and records the
+// model name it was asked for.
+type recordingLLM struct {
+	mu     sync.Mutex
+	models []string
+}
+
+func (l *recordingLLM) Name() string { return "recording" }
+
+func (l *recordingLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	l.mu.Lock()
+	l.models = append(l.models, req.Model)
+	l.mu.Unlock()
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func (l *recordingLLM) calls() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.models...)
+}
+
+func TestRun_ModelOverrideFromContext(t *testing.T) {
+	primary, alt := &recordingLLM{}, &recordingLLM{}
+	llms := map[string]adkmodel.LLM{"primary": primary, "alt": alt}
+	resolver := llmutil.NewMapResolver(llms, nil, "")
+	svc := NewWorkflowService(repository.NewMemory(), llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", resolver)
+
+	wf := &upal.WorkflowDefinition{
+		Name: "override-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "agent1", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "primary/m1", "prompt": "{{input1}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "input1", To: "agent1"}},
+	}
+
+	ctx := upal.WithModelOverrides(context.Background(), upal.ModelOverrides{Default: "alt/m2"})
+	events, result, err := svc.Run(ctx, wf, map[string]any{"input1": "hi"})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for range events {
+	}
+	<-result
+
+	if got := alt.calls(); len(got) == 0 || got[0] != "m2" {
+		t.Errorf("alt calls = %v, want model m2", got)
+	}
+	if got := primary.calls(); len(got) != 0 {
+		t.Errorf("primary should not be called, got %v", got)
+	}
+	if wf.Nodes[1].Config["model"] != "primary/m1" {
+		t.Errorf("original workflow mutated: %v", wf.Nodes[1].Config["model"])
+	}
+}
+
+func TestRun_DedupWindowCoalescesIdenticalRuns(t *testing.T) {
+	llm := &recordingLLM{}
+	llms := map[string]adkmodel.LLM{"primary": llm}
+	resolver := llmutil.NewMapResolver(llms, nil, "")
+	svc := NewWorkflowService(repository.NewMemory(), llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", resolver)
+
+	wf := &upal.WorkflowDefinition{
+		Name:               "dedup-test",
+		DedupWindowSeconds: 60,
+		Nodes: []upal.NodeDefinition{
+			{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "agent1", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "primary/m1", "prompt": "{{input1}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "input1", To: "agent1"}},
+	}
+
+	var wg sync.WaitGroup
+	results := make([]upal.RunResult, 2)
+	errs := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			events, result, err := svc.Run(context.Background(), wf, map[string]any{"input1": "hi"})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for range events {
+			}
+			results[i] = <-result
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+	}
+	if got := llm.calls(); len(got) != 1 {
+		t.Fatalf("expected a single execution, got %d LLM calls", len(got))
+	}
+	for i, res := range results {
+		if res.SessionID == "" || res.State["agent1"] != "ok" {
+			t.Errorf("run %d: result = %+v, want agent1 output", i, res)
+		}
+	}
+	if results[0].SessionID != results[1].SessionID {
+		t.Errorf("session IDs differ: %q vs %q", results[0].SessionID, results[1].SessionID)
+	}
+
+	// Different inputs are not coalesced.
+	events, result, err := svc.Run(context.Background(), wf, map[string]any{"input1": "other"})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for range events {
+	}
+	<-result
+	if got := llm.calls(); len(got) != 2 {
+		t.Errorf("expected a second execution for different inputs, got %d LLM calls", len(got))
+	}
+}
+
+func TestRun_MultipleOutputsKeyedByNode(t *testing.T) {
+	svc := NewWorkflowService(repository.NewMemory(), nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+
+	wf := &upal.WorkflowDefinition{
+		Name: "multi-output",
+		Nodes: []upal.NodeDefinition{
+			{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "summary", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": "summary: {{input1}}"}},
+			{ID: "detail", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": "detail: {{input1}}"}},
+		},
+		Edges: []upal.EdgeDefinition{
+			{From: "input1", To: "summary"},
+			{From: "input1", To: "detail"},
+		},
+	}
+
+	events, result, err := svc.Run(context.Background(), wf, map[string]any{"input1": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range events {
+	}
+	res := <-result
+
+	if len(res.Outputs) != 2 {
+		t.Fatalf("expected 2 outputs, got %v", res.Outputs)
+	}
+	if res.Outputs["summary"] != "summary: hello" {
+		t.Errorf("summary: got %v", res.Outputs["summary"])
+	}
+	if res.Outputs["detail"] != "detail: hello" {
+		t.Errorf("detail: got %v", res.Outputs["detail"])
+	}
+}
+
+func TestRun_ChainedOutputNodes(t *testing.T) {
+	svc := NewWorkflowService(repository.NewMemory(), nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+
+	wf := &upal.WorkflowDefinition{
+		Name: "chained-output",
+		Nodes: []upal.NodeDefinition{
+			{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "draft", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": "{{input1}}"}},
+			{ID: "final", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": "final: {{draft}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "input1", To: "draft"}, {From: "draft", To: "final"}},
+	}
+
+	events, result, err := svc.Run(context.Background(), wf, map[string]any{"input1": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range events {
+	}
+	res := <-result
+
+	if len(res.Outputs) != 1 || res.Outputs["final"] != "final: hello" {
+		t.Errorf("Outputs: got %v, want only the terminal node", res.Outputs)
+	}
+	all, _ := res.State["__output__"].(map[string]any)
+	if len(all) != 2 || all["draft"] != "hello" {
+		t.Errorf("__output__: got %v, want both output nodes", all)
+	}
+}
+
+func TestRun_EmitsRunContextWithResolvedModels(t *testing.T) {
+	llms := map[string]adkmodel.LLM{"primary": &recordingLLM{}, "alt": &recordingLLM{}}
+	resolver := llmutil.NewMapResolver(llms, nil, "")
+	svc := NewWorkflowService(repository.NewMemory(), llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", resolver)
+	svc.SetRunContextSource(map[string]upal.ProviderInfo{
+		"primary": {Type: "openai", URL: "http://primary"},
+		"unused":  {Type: "anthropic"},
+	}, map[string]any{"runs": map[string]any{"ttl": "15m"}})
+
+	wf := &upal.WorkflowDefinition{
+		Name: "context-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}},
+			{ID: "agent1", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "primary/m1", "prompt": "{{input1}}"}},
+			{ID: "agent2", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "alt/m2", "prompt": "{{agent1}}"}},
+		},
+		Edges: []upal.EdgeDefinition{{From: "input1", To: "agent1"}, {From: "agent1", To: "agent2"}},
+	}
+
+	ctx := upal.WithModelOverrides(context.Background(), upal.ModelOverrides{Nodes: map[string]string{"agent2": "primary/m3"}})
+	events, result, err := svc.Run(ctx, wf, map[string]any{"input1": "hi"})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var rc *upal.RunContext
+	for ev := range events {
+		if ev.Type == upal.EventRunContext {
+			rc, _ = ev.Payload["run_context"].(*upal.RunContext)
+		}
+	}
+	<-result
+
+	if rc == nil {
+		t.Fatal("no run_context event emitted")
+	}
+	want := map[string]string{"agent1": "m1", "agent2": "m3"}
+	for _, n := range rc.Nodes {
+		if n.Type != upal.NodeTypeAgent {
+			continue
+		}
+		if n.ResolvedModel != want[n.NodeID] || n.Provider != "primary" {
+			t.Errorf("node %s: provider/resolved = %s/%s, want primary/%s", n.NodeID, n.Provider, n.ResolvedModel, want[n.NodeID])
+		}
+		delete(want, n.NodeID)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing agent nodes in run context: %v", want)
+	}
+	if _, ok := rc.Providers["primary"]; !ok || len(rc.Providers) != 1 {
+		t.Errorf("providers = %v, want only primary", rc.Providers)
+	}
+	if rc.Config["runs"] == nil {
+		t.Errorf("config snapshot missing: %v", rc.Config)
+	}
+}
+
+func TestRunContext_ResolvesModelsLikeBuilders(t *testing.T) {
+	llms := map[string]adkmodel.LLM{"primary": &recordingLLM{}, "alt": &recordingLLM{}}
+	resolver := llmutil.NewMapResolver(llms, llms["primary"], "default-m")
+	svc := NewWorkflowService(repository.NewMemory(), llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", resolver)
+	svc.SetLayoutConfig("alt/layout-m", false)
+
+	rc := svc.runContext(&upal.WorkflowDefinition{
+		Name: "context-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "agent", Type: upal.NodeTypeAgent, Config: map[string]any{"prompt": "hi"}},
+			{ID: "layout", Type: upal.NodeTypeOutput, Config: map[string]any{"system_prompt": "lay out"}},
+			{ID: "own", Type: upal.NodeTypeOutput, Config: map[string]any{"system_prompt": "lay out", "model": "primary/m1", "layout_model": "alt/m2"}},
+			{ID: "md", Type: upal.NodeTypeOutput, Config: map[string]any{"system_prompt": "lay out", "output_format": "md", "model": "primary/m1"}},
+		},
+	})
+
+	want := map[string][2]string{
+		"agent":  {"", "default-m"},
+		"layout": {"alt/layout-m", "layout-m"},
+		"own":    {"alt/m2", "m2"},
+		"md":     {"", ""},
+	}
+	for _, n := range rc.Nodes {
+		if got := [2]string{n.Model, n.ResolvedModel}; got != want[n.NodeID] {
+			t.Errorf("node %s: model/resolved = %v, want %v", n.NodeID, got, want[n.NodeID])
+		}
+	}
+}
+
+func TestRun_EmitsLLMCallEvents(t *testing.T) {
+	llms := map[string]adkmodel.LLM{"primary": &recordingLLM{}}
+	resolver := llmutil.NewMapResolver(llms, nil, "")
+	svc := NewWorkflowService(repository.NewMemory(), llms, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", resolver)
+
+	wf := &upal.WorkflowDefinition{
+		Name: "llm-call-test",
+		Nodes: []upal.NodeDefinition{
+			{ID: "agent1", Type: upal.NodeTypeAgent, Config: map[string]any{"model": "primary/m1", "prompt": "hi"}},
+		},
+	}
+	events, result, err := svc.Run(context.Background(), wf, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var calls []upal.WorkflowEvent
+	for ev := range events {
+		if ev.Type == upal.EventLLMCall {
+			calls = append(calls, ev)
+		}
+	}
+	<-result
+
+	if len(calls) != 1 {
+		t.Fatalf("llm_call events = %d, want 1", len(calls))
+	}
+	p := calls[0].Payload
+	if calls[0].NodeID != "agent1" || p["node_id"] != "agent1" || p["model"] != "m1" || p["attempt"] != 1 {
+		t.Errorf("payload = %v, want node agent1, model m1, attempt 1", p)
+	}
+	if _, ok := p["latency_ms"]; !ok {
+		t.Errorf("payload missing latency_ms: %v", p)
+	}
+}
+
+// failingTool always returns an error.
+type failingTool struct{}
+
+func (failingTool) Name() string                { return "explode" }
+func (failingTool) Description() string         { return "always fails" }
+func (failingTool) InputSchema() map[string]any { return map[string]any{"type": "object"} }
+func (failingTool) Execute(context.Context, any) (any, error) {
+	return nil, errors.New("upstream unavailable")
+}
+
+func TestRun_FailureModeWithFailingMiddleNode(t *testing.T) {
+	newWorkflow := func(mode upal.FailureMode) *upal.WorkflowDefinition {
+		return &upal.WorkflowDefinition{
+			Name:        "failure-mode",
+			FailureMode: mode,
+			Nodes: []upal.NodeDefinition{
+				{ID: "input1", Type: upal.NodeTypeInput, Config: map[string]any{}},
+				{ID: "fetch", Type: upal.NodeTypeTool, Config: map[string]any{"tool": "explode"}},
+				{ID: "report", Type: upal.NodeTypeOutput, Config: map[string]any{"prompt": "{{input1}} [{{fetch}}] {{fetch.error}}"}},
+			},
+			Edges: []upal.EdgeDefinition{
+				{From: "input1", To: "fetch"},
+				{From: "fetch", To: "report"},
+			},
+		}
+	}
+	reg := tools.NewRegistry()
+	reg.Register(failingTool{})
+	svc := NewWorkflowService(repository.NewMemory(), nil, session.InMemoryService(), reg, agents.DefaultRegistry(), "", "", nil)
+
+	t.Run("fail_fast", func(t *testing.T) {
+		events, result, err := svc.Run(context.Background(), newWorkflow(""), map[string]any{"input1": "hi"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sawError, reportRan bool
+		for ev := range events {
+			sawError = sawError || ev.Type == upal.EventError
+			reportRan = reportRan || (ev.Type == upal.EventNodeCompleted && ev.NodeID == "report")
+		}
+		if !sawError || reportRan {
+			t.Errorf("error event = %v, report ran = %v; want the run to stop at fetch", sawError, reportRan)
+		}
+		if _, ok := <-result; ok {
+			t.Error("fail_fast run produced a result")
+		}
+	})
+
+	t.Run("best_effort", func(t *testing.T) {
+		events, result, err := svc.Run(context.Background(), newWorkflow(upal.FailureModeBestEffort), map[string]any{"input1": "hi"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var failed []string
+		var lastPercent any
+		for ev := range events {
+			switch ev.Type {
+			case upal.EventError:
+				t.Fatalf("unexpected run error: %v", ev.Payload["error"])
+			case upal.EventNodeFailed:
+				failed = append(failed, ev.NodeID)
+			case upal.EventProgress:
+				lastPercent = ev.Payload["percent"]
+			}
+		}
+		res := <-result
+		if len(failed) != 1 || failed[0] != "fetch" {
+			t.Errorf("node_failed events = %v, want [fetch]", failed)
+		}
+		if lastPercent != 100 {
+			t.Errorf("final progress = %v, want 100", lastPercent)
+		}
+		if msg := res.NodeErrors["fetch"]; !strings.Contains(msg, "upstream unavailable") {
+			t.Errorf("NodeErrors = %v", res.NodeErrors)
+		}
+		if res.State["fetch"] != "" || !strings.Contains(res.State["fetch.error"].(string), "upstream unavailable") {
+			t.Errorf("fetch state = %q / %v", res.State["fetch"], res.State["fetch.error"])
+		}
+		if out, _ := res.Outputs["report"].(string); !strings.HasPrefix(out, "hi [] ") || !strings.Contains(out, "upstream unavailable") {
+			t.Errorf("report = %q, want downstream to run with the error", out)
+		}
+	})
+}
+
+func TestValidate_FailureMode(t *testing.T) {
+	svc := NewWorkflowService(repository.NewMemory(), nil, session.InMemoryService(), nil, agents.DefaultRegistry(), "", "", nil)
+	if err := svc.Validate(&upal.WorkflowDefinition{FailureMode: upal.FailureModeBestEffort}); err != nil {
+		t.Errorf("best_effort rejected: %v", err)
+	}
+	if err := svc.Validate(&upal.WorkflowDefinition{FailureMode: "yolo"}); err == nil {
+		t.Error("unknown failure_mode accepted")
+	}
+}
@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ArgumentIssue describes one tool-call argument that does not match the
+// tool's input schema.
+type ArgumentIssue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ArgumentError reports tool-call arguments rejected by ValidateArgs. It is
+// returned to the model in place of the tool result so it can correct the
+// call.
+type ArgumentError struct {
+	Tool   string
+	Issues []ArgumentIssue
+}
+
+func (e *ArgumentError) Error() string {
+	parts := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		parts[i] = issue.Field + ": " + issue.Message
+	}
+	return fmt.Sprintf("invalid arguments for tool %q: %s", e.Tool, strings.Join(parts, "; "))
+}
+
+// response is the function response sent back to the model.
+func (e *ArgumentError) response() map[string]any {
+	return map[string]any{
+		"error":             e.Error(),
+		"invalid_arguments": e.Issues,
+		"hint":              "Fix the listed arguments to match the tool's parameter schema and call the tool again.",
+	}
+}
+
+// ValidateArgs checks tool-call arguments against a JSON schema from
+// Tool.InputSchema. Required properties must be present and non-null, and
+// declared properties are coerced to their schema type where the intent is
+// unambiguous: numeric and boolean strings become numbers and booleans,
+// scalars become strings, and JSON-encoded objects and arrays are decoded.
+// Integers are kept as float64, as encoding/json decodes them. Properties
+// the schema does not declare pass through unchanged.
+//
+// It returns a copy of args with the coerced values, or an *ArgumentError
+// listing every problem.
+func ValidateArgs(toolName string, schema map[string]any, args map[string]any) (map[string]any, error) {
+	props, _ := schema["properties"].(map[string]any)
+	required := schemaRequired(schema)
+	if len(props) == 0 && len(required) == 0 {
+		return args, nil
+	}
+
+	out := maps.Clone(args)
+	if out == nil {
+		out = map[string]any{}
+	}
+	var issues []ArgumentIssue
+	for _, name := range required {
+		if v, ok := out[name]; !ok || v == nil {
+			issues = append(issues, ArgumentIssue{Field: name, Message: "required argument is missing" + describeProperty(props[name])})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		v, ok := out[name]
+		if !ok || v == nil {
+			continue
+		}
+		prop, _ := props[name].(map[string]any)
+		coerced, err := coerceArg(prop, v)
+		if err != nil {
+			issues = append(issues, ArgumentIssue{Field: name, Message: err.Error()})
+			continue
+		}
+		out[name] = coerced
+	}
+	if len(issues) > 0 {
+		return nil, &ArgumentError{Tool: toolName, Issues: issues}
+	}
+	return out, nil
+}
+
+// schemaRequired returns the schema's required property names, which tools
+// declare as either []any or []string.
+func schemaRequired(schema map[string]any) []string {
+	switch req := schema["required"].(type) {
+	case []string:
+		return req
+	case []any:
+		names := make([]string, 0, len(req))
+		for _, r := range req {
+			if s, ok := r.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// describeProperty renders " (expected <type>: <description>)" for a missing
+// argument so the model knows what to supply.
+func describeProperty(p any) string {
+	prop, _ := p.(map[string]any)
+	typ, _ := prop["type"].(string)
+	desc, _ := prop["description"].(string)
+	switch {
+	case typ != "" && desc != "":
+		return fmt.Sprintf(" (expected %s: %s)", typ, desc)
+	case typ != "":
+		return fmt.Sprintf(" (expected %s)", typ)
+	}
+	return ""
+}
+
+// coerceArg converts v to the type declared by prop, checking enum values
+// and array items.
+func coerceArg(prop map[string]any, v any) (any, error) {
+	typ, _ := prop["type"].(string)
+	var out any
+	switch typ {
+	case "string":
+		switch x := v.(type) {
+		case string:
+			out = x
+		case float64, bool, int, int64:
+			out = fmt.Sprint(x)
+		default:
+			return nil, mismatch(typ, v)
+		}
+	case "number", "integer":
+		var f float64
+		switch x := v.(type) {
+		case float64:
+			f = x
+		case int:
+			f = float64(x)
+		case int64:
+			f = float64(x)
+		case json.Number:
+			parsed, err := x.Float64()
+			if err != nil {
+				return nil, mismatch(typ, v)
+			}
+			f = parsed
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+			if err != nil {
+				return nil, mismatch(typ, v)
+			}
+			f = parsed
+		default:
+			return nil, mismatch(typ, v)
+		}
+		if typ == "integer" && f != math.Trunc(f) {
+			return nil, fmt.Errorf("expected integer, got %v", f)
+		}
+		out = f
+	case "boolean":
+		switch x := v.(type) {
+		case bool:
+			out = x
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(x))
+			if err != nil {
+				return nil, mismatch(typ, v)
+			}
+			out = b
+		default:
+			return nil, mismatch(typ, v)
+		}
+	case "array":
+		list, ok := decodeJSONString(v).([]any)
+		if !ok {
+			if strs, isStrs := v.([]string); isStrs {
+				list = make([]any, len(strs))
+				for i, s := range strs {
+					list[i] = s
+				}
+			} else {
+				return nil, mismatch(typ, v)
+			}
+		}
+		items, _ := prop["items"].(map[string]any)
+		coerced := make([]any, len(list))
+		for i, item := range list {
+			c, err := coerceArg(items, item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			coerced[i] = c
+		}
+		out = coerced
+	case "object":
+		obj, ok := decodeJSONString(v).(map[string]any)
+		if !ok {
+			return nil, mismatch(typ, v)
+		}
+		out = obj
+	default:
+		out = v
+	}
+
+	if enum, ok := prop["enum"].([]any); ok && len(enum) > 0 && !slices.ContainsFunc(enum, func(e any) bool {
+		return fmt.Sprint(e) == fmt.Sprint(out)
+	}) {
+		allowed := make([]string, len(enum))
+		for i, e := range enum {
+			allowed[i] = fmt.Sprintf("%q", fmt.Sprint(e))
+		}
+		return nil, fmt.Errorf("%s is not one of the allowed values %s", formatArg(out), strings.Join(allowed, ", "))
+	}
+	return out, nil
+}
+
+// decodeJSONString decodes v when it is a string holding a JSON object or
+// array, which models sometimes send instead of the structured value.
+func decodeJSONString(v any) any {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") && !strings.HasPrefix(s, "[") {
+		return v
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+		return v
+	}
+	return decoded
+}
+
+func mismatch(want string, got any) error {
+	return fmt.Errorf("expected %s, got %s %s", want, jsonTypeName(got), formatArg(got))
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64, int, int64, json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any, []string:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// formatArg renders a value for an error message, truncated so a large
+// argument does not flood the model's context.
+func formatArg(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	const maxRunes = 80
+	if r := []rune(string(b)); len(r) > maxRunes {
+		return string(r[:maxRunes]) + "…"
+	}
+	return string(b)
+}
@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+// fetchTool has a schema with required, typed and enum properties and
+// records whether it ran.
+type fetchTool struct {
+	calls int
+	input any
+}
+
+func (f *fetchTool) Name() string        { return "fetch" }
+func (f *fetchTool) Description() string { return "Fetches a URL" }
+func (f *fetchTool) InputSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"url":     map[string]any{"type": "string", "description": "Absolute URL to fetch"},
+			"retries": map[string]any{"type": "integer"},
+			"verbose": map[string]any{"type": "boolean"},
+			"method":  map[string]any{"type": "string", "enum": []any{"GET", "HEAD"}},
+			"headers": map[string]any{"type": "object"},
+			"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "number"}},
+		},
+		"required": []any{"url"},
+	}
+}
+func (f *fetchTool) Execute(_ context.Context, input any) (any, error) {
+	f.calls++
+	f.input = input
+	return map[string]any{"ok": true}, nil
+}
+
+func callFetch(tool *fetchTool, args map[string]any) map[string]any {
+	content := ExecuteToolCalls(context.Background(), []*genai.FunctionCall{{Name: "fetch", Args: args}}, map[string]Tool{"fetch": tool})
+	return content.Parts[0].FunctionResponse.Response
+}
+
+func TestExecuteToolCalls_MissingRequiredArg(t *testing.T) {
+	tool := &fetchTool{}
+	resp := callFetch(tool, map[string]any{"verbose": true})
+
+	if tool.calls != 0 {
+		t.Fatal("tool ran despite a missing required argument")
+	}
+	msg, _ := resp["error"].(string)
+	if !strings.Contains(msg, `invalid arguments for tool "fetch"`) || !strings.Contains(msg, "url: required argument is missing (expected string: Absolute URL to fetch)") {
+		t.Errorf("error = %q", msg)
+	}
+	issues, ok := resp["invalid_arguments"].([]ArgumentIssue)
+	if !ok || len(issues) != 1 || issues[0].Field != "url" {
+		t.Errorf("invalid_arguments = %#v, want one issue for url", resp["invalid_arguments"])
+	}
+	if resp["hint"] == nil {
+		t.Error("response has no correction hint")
+	}
+}
+
+func TestExecuteToolCalls_TypeMismatch(t *testing.T) {
+	tool := &fetchTool{}
+	resp := callFetch(tool, map[string]any{"url": "https://example.com", "retries": "three", "method": "POST"})
+
+	if tool.calls != 0 {
+		t.Fatal("tool ran despite mistyped arguments")
+	}
+	issues, _ := resp["invalid_arguments"].([]ArgumentIssue)
+	want := []ArgumentIssue{
+		{Field: "method", Message: `"POST" is not one of the allowed values "GET", "HEAD"`},
+		{Field: "retries", Message: `expected integer, got string "three"`},
+	}
+	if len(issues) != len(want) || issues[0] != want[0] || issues[1] != want[1] {
+		t.Errorf("invalid_arguments = %+v, want %+v", issues, want)
+	}
+}
+
+func TestExecuteToolCalls_CoercesArgs(t *testing.T) {
+	tool := &fetchTool{}
+	args := map[string]any{
+		"url":     "https://example.com",
+		"retries": "2",
+		"verbose": "true",
+		"headers": `{"Accept": "text/html"}`,
+		"tags":    []any{"1.5", 2.0},
+		"extra":   "kept",
+	}
+	if resp := callFetch(tool, args); resp["error"] != nil {
+		t.Fatalf("unexpected error: %v", resp["error"])
+	}
+	got := tool.input.(map[string]any)
+	if got["retries"] != 2.0 || got["verbose"] != true || got["extra"] != "kept" {
+		t.Errorf("coerced args = %v", got)
+	}
+	if h, _ := got["headers"].(map[string]any); h["Accept"] != "text/html" {
+		t.Errorf("headers = %#v, want decoded object", got["headers"])
+	}
+	if tags, _ := got["tags"].([]any); len(tags) != 2 || tags[0] != 1.5 || tags[1] != 2.0 {
+		t.Errorf("tags = %#v", got["tags"])
+	}
+	// The model's original arguments are not modified.
+	if args["retries"] != "2" {
+		t.Errorf("original args mutated: %v", args)
+	}
+}
+
+func TestValidateArgs_NoSchemaPassesThrough(t *testing.T) {
+	args := map[string]any{"x": 1}
+	got, err := ValidateArgs("echo", map[string]any{"type": "object"}, args)
+	if err != nil || got["x"] != 1 {
+		t.Errorf("got %v, %v", got, err)
+	}
+	_, err = ValidateArgs("t", map[string]any{"required": []string{"q"}}, nil)
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) || argErr.Issues[0].Field != "q" {
+		t.Errorf("[]string required: err = %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// Idempotency is implemented by tools that report whether a repeated call
+// with the same arguments may be answered with an earlier result. Tools with
+// side effects (publishing, writing, sending) return false. Tools that do
+// not implement it are treated as idempotent.
+type Idempotency interface {
+	Idempotent() bool
+}
+
+// IsIdempotent reports whether t's results may be cached.
+func IsIdempotent(t Tool) bool {
+	if i, ok := t.(Idempotency); ok {
+		return i.Idempotent()
+	}
+	return true
+}
+
+// ResultCache memoizes successful tool results by tool name and arguments.
+// Agent nodes that opt in with cache_tool_results hold one per execution,
+// so a repeated identical call within the run skips the external call.
+type ResultCache struct {
+	mu      sync.Mutex
+	entries map[string]map[string]any
+	hits    int
+}
+
+func NewResultCache() *ResultCache {
+	return &ResultCache{entries: make(map[string]map[string]any)}
+}
+
+// Hits returns how many calls were answered from the cache.
+func (c *ResultCache) Hits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+func (c *ResultCache) get(key string) (map[string]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, ok := c.entries[key]
+	if ok {
+		c.hits++
+	}
+	return resp, ok
+}
+
+func (c *ResultCache) put(key string, resp map[string]any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = resp
+}
+
+// resultCacheKey hashes the tool name and arguments. encoding/json sorts map
+// keys, so argument order does not matter. It reports false for arguments
+// that cannot be encoded.
+func resultCacheKey(name string, args map[string]any) (string, bool) {
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(append([]byte(name+"\x00"), encoded...))
+	return hex.EncodeToString(sum[:]), true
+}
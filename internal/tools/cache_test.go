@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+// counterTool counts executions; it fails when args carry "fail" and reports
+// itself non-idempotent when sideEffects is set.
+type counterTool struct {
+	name        string
+	calls       int
+	sideEffects bool
+}
+
+func (c *counterTool) Name() string                { return c.name }
+func (c *counterTool) Description() string         { return "counts calls" }
+func (c *counterTool) InputSchema() map[string]any { return map[string]any{"type": "object"} }
+func (c *counterTool) Execute(_ context.Context, input any) (any, error) {
+	c.calls++
+	if args, _ := input.(map[string]any); args["fail"] == true {
+		return nil, errors.New("boom")
+	}
+	return map[string]any{"call": c.calls}, nil
+}
+func (c *counterTool) Idempotent() bool { return !c.sideEffects }
+
+func callTwice(t *testing.T, tool Tool, cache *ResultCache, first, second map[string]any) []map[string]any {
+	t.Helper()
+	tools := map[string]Tool{tool.Name(): tool}
+	var out []map[string]any
+	for _, args := range []map[string]any{first, second} {
+		content := ExecuteToolCallsCached(context.Background(), []*genai.FunctionCall{{Name: tool.Name(), Args: args}}, tools, cache)
+		out = append(out, content.Parts[0].FunctionResponse.Response)
+	}
+	return out
+}
+
+func TestResultCache_RepeatedCallHits(t *testing.T) {
+	tool := &counterTool{name: "lookup"}
+	cache := NewResultCache()
+	resp := callTwice(t, tool, cache, map[string]any{"q": "go", "n": 1}, map[string]any{"n": 1, "q": "go"})
+	if tool.calls != 1 || cache.Hits() != 1 {
+		t.Fatalf("calls = %d, hits = %d; want 1 and 1", tool.calls, cache.Hits())
+	}
+	if resp[1]["call"] != resp[0]["call"] {
+		t.Errorf("cached response = %v, want %v", resp[1], resp[0])
+	}
+
+	callTwice(t, tool, cache, map[string]any{"q": "rust"}, map[string]any{"q": "zig"})
+	if tool.calls != 3 || cache.Hits() != 1 {
+		t.Errorf("different args: calls = %d, hits = %d; want 3 and 1", tool.calls, cache.Hits())
+	}
+}
+
+func TestResultCache_SkipsNonIdempotentAndFailures(t *testing.T) {
+	cache := NewResultCache()
+	sender := &counterTool{name: "send", sideEffects: true}
+	callTwice(t, sender, cache, map[string]any{"to": "a"}, map[string]any{"to": "a"})
+	if sender.calls != 2 {
+		t.Errorf("non-idempotent tool ran %d times, want 2", sender.calls)
+	}
+
+	flaky := &counterTool{name: "flaky"}
+	resp := callTwice(t, flaky, cache, map[string]any{"fail": true}, map[string]any{"fail": true})
+	if flaky.calls != 2 || resp[1]["error"] == nil {
+		t.Errorf("failed call was cached: calls = %d, response %v", flaky.calls, resp[1])
+	}
+
+	// Registry wrapping keeps the tool's idempotency.
+	reg := NewRegistry()
+	reg.Register(NewPublishTool(t.TempDir()))
+	publish, _ := reg.Get("publish")
+	if IsIdempotent(publish) {
+		t.Error("registered publish tool reported idempotent")
+	}
+	if cache.Hits() != 0 {
+		t.Errorf("hits = %d, want 0", cache.Hits())
+	}
+}
+
+func TestResultCache_NilCacheAlwaysRuns(t *testing.T) {
+	tool := &counterTool{name: "lookup"}
+	callTwice(t, tool, nil, map[string]any{"q": "go"}, map[string]any{"q": "go"})
+	if tool.calls != 2 {
+		t.Errorf("calls = %d, want 2", tool.calls)
+	}
+}
@@ -12,22 +12,34 @@ import (
 	"sync"
 )
 
+// ContentStoreBackend persists the key-value pairs behind the content_store
+// tool. repository.PersistentContentStore is the database-backed implementation.
+type ContentStoreBackend interface {
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	Set(ctx context.Context, key, value string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+	Delete(ctx context.Context, key string) error
+}
+
 type ContentStoreTool struct {
-	mu   sync.RWMutex
-	path string
-	data map[string]string
+	backend ContentStoreBackend
 }
 
+// NewContentStoreTool creates a content store backed by a JSON file at path.
 func NewContentStoreTool(path string) *ContentStoreTool {
-	t := &ContentStoreTool{
-		path: path,
-		data: make(map[string]string),
-	}
-	t.load()
-	return t
+	return NewContentStoreToolWithBackend(NewFileContentStore(path))
+}
+
+// NewContentStoreToolWithBackend creates a content store using the given backend.
+func NewContentStoreToolWithBackend(backend ContentStoreBackend) *ContentStoreTool {
+	return &ContentStoreTool{backend: backend}
 }
 
 func (c *ContentStoreTool) Name() string { return "content_store" }
+
+// Idempotent is false: set calls change what later get calls return.
+func (c *ContentStoreTool) Idempotent() bool { return false }
+
 func (c *ContentStoreTool) Description() string {
 	return "Persistent key-value store for tracking state across pipeline runs. Use for deduplication (seen URLs), timestamps (last collection), counters, and any data that must survive between executions."
 }
@@ -58,7 +70,7 @@ func (c *ContentStoreTool) InputSchema() map[string]any {
 	}
 }
 
-func (c *ContentStoreTool) Execute(_ context.Context, input any) (any, error) {
+func (c *ContentStoreTool) Execute(ctx context.Context, input any) (any, error) {
 	args, ok := input.(map[string]any)
 	if !ok {
 		return nil, fmt.Errorf("invalid input: expected object")
@@ -72,9 +84,10 @@ func (c *ContentStoreTool) Execute(_ context.Context, input any) (any, error) {
 		if key == "" {
 			return nil, fmt.Errorf("key is required for get")
 		}
-		c.mu.RLock()
-		val, exists := c.data[key]
-		c.mu.RUnlock()
+		val, exists, err := c.backend.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
 		if !exists {
 			return map[string]any{"value": nil, "found": false}, nil
 		}
@@ -85,37 +98,24 @@ func (c *ContentStoreTool) Execute(_ context.Context, input any) (any, error) {
 			return nil, fmt.Errorf("key is required for set")
 		}
 		value, _ := args["value"].(string)
-		c.mu.Lock()
-		c.data[key] = value
-		err := c.save()
-		c.mu.Unlock()
-		if err != nil {
+		if err := c.backend.Set(ctx, key, value); err != nil {
 			return nil, fmt.Errorf("failed to persist: %w", err)
 		}
 		return map[string]any{"status": "ok"}, nil
 
 	case "list":
 		prefix, _ := args["prefix"].(string)
-		c.mu.RLock()
-		var keys []string
-		for k := range c.data {
-			if prefix == "" || strings.HasPrefix(k, prefix) {
-				keys = append(keys, k)
-			}
+		keys, err := c.backend.List(ctx, prefix)
+		if err != nil {
+			return nil, err
 		}
-		c.mu.RUnlock()
-		sort.Strings(keys)
 		return map[string]any{"keys": keys, "count": len(keys)}, nil
 
 	case "delete":
 		if key == "" {
 			return nil, fmt.Errorf("key is required for delete")
 		}
-		c.mu.Lock()
-		delete(c.data, key)
-		err := c.save()
-		c.mu.Unlock()
-		if err != nil {
+		if err := c.backend.Delete(ctx, key); err != nil {
 			return nil, fmt.Errorf("failed to persist: %w", err)
 		}
 		return map[string]any{"status": "ok"}, nil
@@ -125,22 +125,72 @@ func (c *ContentStoreTool) Execute(_ context.Context, input any) (any, error) {
 	}
 }
 
-func (c *ContentStoreTool) load() {
-	raw, err := os.ReadFile(c.path)
+// FileContentStore keeps all pairs in memory and rewrites a JSON file on change.
+type FileContentStore struct {
+	mu   sync.RWMutex
+	path string
+	data map[string]string
+}
+
+func NewFileContentStore(path string) *FileContentStore {
+	f := &FileContentStore{
+		path: path,
+		data: make(map[string]string),
+	}
+	f.load()
+	return f
+}
+
+func (f *FileContentStore) Get(_ context.Context, key string) (string, bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	val, ok := f.data[key]
+	return val, ok, nil
+}
+
+func (f *FileContentStore) Set(_ context.Context, key, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = value
+	return f.save()
+}
+
+func (f *FileContentStore) List(_ context.Context, prefix string) ([]string, error) {
+	f.mu.RLock()
+	var keys []string
+	for k := range f.data {
+		if prefix == "" || strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	f.mu.RUnlock()
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (f *FileContentStore) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return f.save()
+}
+
+func (f *FileContentStore) load() {
+	raw, err := os.ReadFile(f.path)
 	if err != nil {
 		return // file doesn't exist yet — start empty
 	}
-	json.Unmarshal(raw, &c.data)
+	json.Unmarshal(raw, &f.data)
 }
 
-func (c *ContentStoreTool) save() error {
-	dir := filepath.Dir(c.path)
+func (f *FileContentStore) save() error {
+	dir := filepath.Dir(f.path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
-	raw, err := json.Marshal(c.data)
+	raw, err := json.Marshal(f.data)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(c.path, raw, 0644)
+	return os.WriteFile(f.path, raw, 0644)
 }
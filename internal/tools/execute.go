@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"runtime/debug"
@@ -75,6 +76,14 @@ func ToGenaiSchema(schema map[string]any) *genai.Schema {
 // Content with FunctionResponse parts for feeding back to the LLM.
 // Native tool calls (e.g. web_search handled by the provider) are skipped.
 func ExecuteToolCalls(ctx context.Context, calls []*genai.FunctionCall, customTools map[string]Tool) *genai.Content {
+	return ExecuteToolCallsCached(ctx, calls, customTools, nil)
+}
+
+// ExecuteToolCallsCached is ExecuteToolCalls with a result cache: calls to
+// idempotent tools repeating an earlier call's name and arguments get the
+// earlier response without running the tool. Failed calls are not cached.
+// A nil cache disables caching.
+func ExecuteToolCallsCached(ctx context.Context, calls []*genai.FunctionCall, customTools map[string]Tool, cache *ResultCache) *genai.Content {
 	var parts []*genai.Part
 	for _, fc := range calls {
 		t, ok := customTools[fc.Name]
@@ -82,7 +91,7 @@ func ExecuteToolCalls(ctx context.Context, calls []*genai.FunctionCall, customTo
 			// Native tool call — provider handles it; skip.
 			continue
 		}
-		output := executeSingleToolSafe(ctx, fc, t)
+		output := executeCached(ctx, fc, t, cache)
 		parts = append(parts, &genai.Part{
 			FunctionResponse: &genai.FunctionResponse{
 				Name:     fc.Name,
@@ -99,6 +108,25 @@ func ExecuteToolCalls(ctx context.Context, calls []*genai.FunctionCall, customTo
 	}
 }
 
+func executeCached(ctx context.Context, fc *genai.FunctionCall, t Tool, cache *ResultCache) map[string]any {
+	if cache == nil || !IsIdempotent(t) {
+		return executeSingleToolSafe(ctx, fc, t)
+	}
+	key, ok := resultCacheKey(fc.Name, fc.Args)
+	if !ok {
+		return executeSingleToolSafe(ctx, fc, t)
+	}
+	if output, hit := cache.get(key); hit {
+		slog.Debug("tool result served from cache", "tool", fc.Name)
+		return output
+	}
+	output := executeSingleToolSafe(ctx, fc, t)
+	if _, failed := output["error"]; !failed {
+		cache.put(key, output)
+	}
+	return output
+}
+
 func executeSingleToolSafe(ctx context.Context, fc *genai.FunctionCall, t Tool) (output map[string]any) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -107,7 +135,18 @@ func executeSingleToolSafe(ctx context.Context, fc *genai.FunctionCall, t Tool)
 		}
 	}()
 
-	result, err := t.Execute(ctx, fc.Args)
+	args, err := ValidateArgs(fc.Name, t.InputSchema(), fc.Args)
+	var argErr *ArgumentError
+	if errors.As(err, &argErr) {
+		slog.Warn("tool call rejected: invalid arguments", "tool", fc.Name, "err", argErr)
+		return argErr.response()
+	}
+
+	result, err := t.Execute(ctx, args)
+	if errors.Is(err, ErrToolThrottled) {
+		// Tell the model plainly so it stops retrying the same call.
+		return map[string]any{"error": err.Error(), "throttled": true}
+	}
 	if err != nil {
 		return map[string]any{"error": err.Error()}
 	}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
+
+	"github.com/soochol/upal/internal/upal"
 )
 
 // maxResponseBody caps how much of an HTTP response body we return to the LLM.
@@ -17,11 +20,27 @@ var allowedMethods = map[string]bool{
 	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true,
 }
 
+// ConnectionResolver resolves a stored connection with its secrets decrypted.
+type ConnectionResolver interface {
+	Resolve(ctx context.Context, id string) (*upal.Connection, error)
+}
+
 // HTTPRequestTool makes HTTP requests to external APIs and URLs.
-type HTTPRequestTool struct{}
+type HTTPRequestTool struct {
+	connections ConnectionResolver
+}
+
+// SetConnectionResolver enables the connection_id argument, which injects a
+// stored connection's credentials into the request at call time.
+func (h *HTTPRequestTool) SetConnectionResolver(r ConnectionResolver) {
+	h.connections = r
+}
 
 func (h *HTTPRequestTool) Name() string { return "http_request" }
 
+// Idempotent is false: requests may use any method.
+func (h *HTTPRequestTool) Idempotent() bool { return false }
+
 func (h *HTTPRequestTool) Description() string {
 	return "Make HTTP requests to external APIs and URLs. Returns the response status, headers, and body."
 }
@@ -46,6 +65,10 @@ func (h *HTTPRequestTool) InputSchema() map[string]any {
 				"type":        "string",
 				"description": "Optional request body (for POST, PUT, PATCH)",
 			},
+			"connection_id": map[string]any{
+				"type":        "string",
+				"description": "Optional connection whose stored credentials are added to the request; the url must be on the connection's host",
+			},
 		},
 		"required": []any{"method", "url"},
 	}
@@ -63,11 +86,19 @@ func (h *HTTPRequestTool) Execute(ctx context.Context, input any) (any, error) {
 		return nil, fmt.Errorf("unsupported HTTP method: %q", method)
 	}
 
-	url, _ := args["url"].(string)
-	if url == "" {
+	rawURL, _ := args["url"].(string)
+	if rawURL == "" {
 		return nil, fmt.Errorf("url is required")
 	}
 
+	var connHeaders map[string]string
+	if connID, _ := args["connection_id"].(string); connID != "" {
+		var err error
+		if connHeaders, err = h.connectionHeaders(ctx, connID, rawURL); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build request body
 	var bodyReader io.Reader
 	if body, ok := args["body"].(string); ok && body != "" {
@@ -78,7 +109,7 @@ func (h *HTTPRequestTool) Execute(ctx context.Context, input any) (any, error) {
 	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(reqCtx, method, url, bodyReader)
+	req, err := http.NewRequestWithContext(reqCtx, method, rawURL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -89,6 +120,10 @@ func (h *HTTPRequestTool) Execute(ctx context.Context, input any) (any, error) {
 			req.Header.Set(k, fmt.Sprintf("%v", v))
 		}
 	}
+	// Connection headers win over model-supplied ones.
+	for k, v := range connHeaders {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -107,11 +142,12 @@ func (h *HTTPRequestTool) Execute(ctx context.Context, input any) (any, error) {
 	if len(bodyBytes) > maxResponseBody {
 		bodyStr = bodyStr[:maxResponseBody] + "\n... [truncated at 100KB]"
 	}
+	bodyStr = redactSecrets(bodyStr, connHeaders)
 
 	// Collect response headers
 	respHeaders := make(map[string]string)
 	for k := range resp.Header {
-		respHeaders[k] = resp.Header.Get(k)
+		respHeaders[k] = redactSecrets(resp.Header.Get(k), connHeaders)
 	}
 
 	return map[string]any{
@@ -121,3 +157,59 @@ func (h *HTTPRequestTool) Execute(ctx context.Context, input any) (any, error) {
 		"body":        bodyStr,
 	}, nil
 }
+
+// connectionHeaders resolves connID and returns the headers it contributes:
+// Extras["headers"] entries plus a bearer Authorization header for Token.
+// The request must target the connection's Host so the credentials cannot be
+// sent elsewhere; connections without a Host are refused.
+func (h *HTTPRequestTool) connectionHeaders(ctx context.Context, connID, rawURL string) (map[string]string, error) {
+	if h.connections == nil {
+		return nil, fmt.Errorf("connection_id is not supported: no connection resolver configured")
+	}
+	conn, err := h.connections.Resolve(ctx, connID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve connection %q: %w", connID, err)
+	}
+	if conn.Host == "" {
+		return nil, fmt.Errorf("connection %q has no host; set one to use it with http_request", connID)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if !strings.EqualFold(u.Hostname(), conn.Host) {
+		return nil, fmt.Errorf("connection %q is restricted to host %q", connID, conn.Host)
+	}
+
+	return connectionAuthHeaders(conn), nil
+}
+
+// connectionAuthHeaders returns the headers a connection contributes to a
+// request: Extras["headers"] entries plus a bearer Authorization for Token.
+func connectionAuthHeaders(conn *upal.Connection) map[string]string {
+	headers := make(map[string]string)
+	if extra, ok := conn.Extras["headers"].(map[string]any); ok {
+		for k, v := range extra {
+			headers[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	if conn.Token != "" {
+		headers["Authorization"] = "Bearer " + conn.Token
+	}
+	return headers
+}
+
+// redactSecrets masks connection header values (and bare bearer tokens) that
+// the remote end echoed back, so they never reach the model.
+func redactSecrets(s string, headers map[string]string) string {
+	for _, v := range headers {
+		if v == "" {
+			continue
+		}
+		s = strings.ReplaceAll(s, v, "[redacted]")
+		if token, ok := strings.CutPrefix(v, "Bearer "); ok && token != "" {
+			s = strings.ReplaceAll(s, token, "[redacted]")
+		}
+	}
+	return s
+}
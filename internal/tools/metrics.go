@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrToolThrottled is returned when a call exceeds the tool's rate limit.
+var ErrToolThrottled = errors.New("tool rate limit exceeded")
+
+// ToolStats reports usage of one custom tool since startup.
+type ToolStats struct {
+	Name         string     `json:"name"`
+	Calls        int64      `json:"calls"`
+	Errors       int64      `json:"errors"`
+	Throttled    int64      `json:"throttled"`
+	AvgLatencyMS float64    `json:"avg_latency_ms"`
+	MaxLatencyMS int64      `json:"max_latency_ms"`
+	LastCalledAt *time.Time `json:"last_called_at,omitempty"`
+	// RateLimit is "<calls>/<window>" when a limit is configured.
+	RateLimit string `json:"rate_limit,omitempty"`
+}
+
+// toolMeter accumulates usage and enforces the optional rate limit of one
+// tool. Calls and Errors count executions only; throttled calls never reach
+// the tool.
+type toolMeter struct {
+	mu           sync.Mutex
+	calls        int64
+	errors       int64
+	throttled    int64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+	lastCalledAt time.Time
+
+	// Sliding-window rate limit; limit 0 disables it.
+	limit  int
+	window time.Duration
+	recent []time.Time
+}
+
+// allow reserves a slot in the rate-limit window, or counts a throttled call.
+func (m *toolMeter) allow(now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.limit <= 0 {
+		return true
+	}
+	cutoff := now.Add(-m.window)
+	m.recent = slices.DeleteFunc(m.recent, func(t time.Time) bool { return !t.After(cutoff) })
+	if len(m.recent) >= m.limit {
+		m.throttled++
+		return false
+	}
+	m.recent = append(m.recent, now)
+	return true
+}
+
+func (m *toolMeter) record(start time.Time, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if err != nil {
+		m.errors++
+	}
+	m.totalLatency += latency
+	m.maxLatency = max(m.maxLatency, latency)
+	m.lastCalledAt = start
+}
+
+func (m *toolMeter) stats(name string) ToolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := ToolStats{
+		Name:         name,
+		Calls:        m.calls,
+		Errors:       m.errors,
+		Throttled:    m.throttled,
+		MaxLatencyMS: m.maxLatency.Milliseconds(),
+	}
+	if m.calls > 0 {
+		s.AvgLatencyMS = float64(m.totalLatency.Microseconds()) / float64(m.calls) / 1000
+		last := m.lastCalledAt
+		s.LastCalledAt = &last
+	}
+	if m.limit > 0 {
+		s.RateLimit = fmt.Sprintf("%d/%s", m.limit, m.window)
+	}
+	return s
+}
+
+// meteredTool wraps a registered tool so every execution is counted and
+// rate-limited, whichever code path runs it.
+type meteredTool struct {
+	Tool
+	meter *toolMeter
+	now   func() time.Time
+}
+
+// Idempotent forwards the wrapped tool's Idempotency, which embedding the
+// Tool interface would hide.
+func (t *meteredTool) Idempotent() bool { return IsIdempotent(t.Tool) }
+
+func (t *meteredTool) Execute(ctx context.Context, input any) (any, error) {
+	start := t.now()
+	if !t.meter.allow(start) {
+		return nil, fmt.Errorf("%w: %q allows %d calls per %s, try again later", ErrToolThrottled, t.Name(), t.meter.limit, t.meter.window)
+	}
+	result, err := t.Tool.Execute(ctx, input)
+	t.meter.record(start, t.now().Sub(start), err)
+	return result, err
+}
+
+// SetRateLimit caps the named tool at calls executions per window (default
+// one minute). A non-positive calls removes the limit.
+func (r *Registry) SetRateLimit(name string, calls int, window time.Duration) {
+	if window <= 0 {
+		window = time.Minute
+	}
+	m := r.meterFor(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limit, m.window = calls, window
+	m.recent = nil
+}
+
+// Stats returns usage for every custom tool, sorted by name.
+func (r *Registry) Stats() []ToolStats {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	slices.Sort(names)
+
+	result := make([]ToolStats, 0, len(names))
+	for _, name := range names {
+		result = append(result, r.meterFor(name).stats(name))
+	}
+	return result
+}
+
+func (r *Registry) meterFor(name string) *toolMeter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.meters[name]
+	if !ok {
+		m = &toolMeter{}
+		r.meters[name] = m
+	}
+	return m
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+type failingTool struct{ echoTool }
+
+func (f *failingTool) Name() string { return "fail" }
+func (f *failingTool) Execute(context.Context, any) (any, error) {
+	return nil, errors.New("boom")
+}
+
+func TestRegistry_StatsCountCalls(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&echoTool{})
+	reg.Register(&failingTool{})
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := reg.Execute(ctx, "echo", "hi"); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+	reg.Execute(ctx, "fail", nil)
+
+	stats := reg.Stats()
+	if len(stats) != 2 || stats[0].Name != "echo" || stats[1].Name != "fail" {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats[0].Calls != 3 || stats[0].Errors != 0 || stats[0].LastCalledAt == nil {
+		t.Errorf("echo stats = %+v, want 3 calls", stats[0])
+	}
+	if stats[1].Calls != 1 || stats[1].Errors != 1 {
+		t.Errorf("fail stats = %+v, want 1 call with 1 error", stats[1])
+	}
+}
+
+func TestRegistry_RateLimitThrottles(t *testing.T) {
+	reg := NewRegistry()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	reg.now = func() time.Time { return now }
+	reg.Register(&echoTool{})
+	reg.SetRateLimit("echo", 2, time.Minute)
+
+	tool, _ := reg.Get("echo")
+	custom := map[string]Tool{"echo": tool}
+	call := func() map[string]any {
+		content := ExecuteToolCalls(context.Background(), []*genai.FunctionCall{{Name: "echo", Args: map[string]any{"x": 1}}}, custom)
+		return content.Parts[0].FunctionResponse.Response
+	}
+
+	for i := range 2 {
+		if resp := call(); resp["error"] != nil {
+			t.Fatalf("call %d: unexpected error %v", i+1, resp["error"])
+		}
+	}
+	resp := call()
+	if resp["throttled"] != true || !strings.Contains(resp["error"].(string), "rate limit") {
+		t.Fatalf("third call response = %v, want throttled error", resp)
+	}
+
+	stats := reg.Stats()[0]
+	if stats.Calls != 2 || stats.Throttled != 1 || stats.RateLimit != "2/1m0s" {
+		t.Errorf("stats = %+v, want 2 calls and 1 throttled", stats)
+	}
+
+	// The window slides: once the first calls age out, calls go through again.
+	now = now.Add(time.Minute)
+	if resp := call(); resp["error"] != nil {
+		t.Errorf("after window: unexpected error %v", resp["error"])
+	}
+}
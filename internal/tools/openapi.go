@@ -0,0 +1,459 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/soochol/upal/internal/upal"
+	"gopkg.in/yaml.v3"
+)
+
+// maxSpecSize caps how much of a remote OpenAPI document LoadOpenAPISpec reads.
+const maxSpecSize = 5 << 20
+
+// openAPIMethods lists the operation keys of a path item, in the order tools
+// are generated.
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+var toolNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// OpenAPIToolset turns each operation of an OpenAPI 3 document into a Tool
+// named "<namespace>_<operationId>". Requests go to BaseURL (the spec's first
+// server unless overridden) with the credentials of ConnectionID, resolved at
+// call time so rotated secrets take effect without re-importing. When Owner
+// is set the connection is resolved as that user rather than the caller.
+type OpenAPIToolset struct {
+	Namespace    string
+	Title        string
+	BaseURL      string
+	ConnectionID string
+	Owner        string
+	Client       *http.Client
+
+	connections ConnectionResolver
+	ops         []*openAPIOperation
+}
+
+// openAPIOperation is one generated tool.
+type openAPIOperation struct {
+	set         *OpenAPIToolset
+	name        string
+	description string
+	method      string
+	path        string
+	params      []openAPIParam
+	hasBody     bool
+	schema      map[string]any
+}
+
+type openAPIParam struct {
+	Name     string
+	In       string // "path" | "query" | "header"
+	Required bool
+}
+
+// LoadOpenAPISpec fetches an OpenAPI document (JSON or YAML) from rawURL.
+func LoadOpenAPISpec(ctx context.Context, rawURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec url: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch spec: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch spec: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read spec: %w", err)
+	}
+	if len(data) > maxSpecSize {
+		return nil, fmt.Errorf("spec exceeds %d bytes", maxSpecSize)
+	}
+	return data, nil
+}
+
+// NewOpenAPIToolset parses an OpenAPI 3 document (JSON or YAML) and generates
+// one tool per operation. Operations without an operationId are named after
+// their method and path.
+func NewOpenAPIToolset(namespace string, spec []byte) (*OpenAPIToolset, error) {
+	if namespace == "" || toolNameUnsafe.MatchString(namespace) {
+		return nil, fmt.Errorf("namespace must be non-empty and contain only letters, digits and underscores")
+	}
+	var raw any
+	if err := yaml.Unmarshal(spec, &raw); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	doc, ok := jsonCompatible(raw).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("parse spec: expected an object")
+	}
+	if v, _ := doc["openapi"].(string); !strings.HasPrefix(v, "3.") {
+		return nil, fmt.Errorf("only OpenAPI 3.x specs are supported")
+	}
+
+	set := &OpenAPIToolset{Namespace: namespace}
+	if info, ok := doc["info"].(map[string]any); ok {
+		set.Title, _ = info["title"].(string)
+	}
+	if servers, ok := doc["servers"].([]any); ok && len(servers) > 0 {
+		if srv, ok := servers[0].(map[string]any); ok {
+			set.BaseURL, _ = srv["url"].(string)
+		}
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	keys := make([]string, 0, len(paths))
+	for p := range paths {
+		keys = append(keys, p)
+	}
+	slices.Sort(keys)
+	seen := make(map[string]bool)
+	for _, path := range keys {
+		item, _ := paths[path].(map[string]any)
+		shared, _ := item["parameters"].([]any)
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			o, err := set.buildOperation(doc, method, path, op, shared)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			if seen[o.name] {
+				return nil, fmt.Errorf("duplicate tool name %q", o.name)
+			}
+			seen[o.name] = true
+			set.ops = append(set.ops, o)
+		}
+	}
+	if len(set.ops) == 0 {
+		return nil, fmt.Errorf("spec defines no operations")
+	}
+	return set, nil
+}
+
+// SetConnectionResolver enables ConnectionID; without it the generated tools
+// send no credentials.
+func (s *OpenAPIToolset) SetConnectionResolver(r ConnectionResolver) {
+	s.connections = r
+}
+
+// Tools returns the generated tools in spec order.
+func (s *OpenAPIToolset) Tools() []Tool {
+	out := make([]Tool, len(s.ops))
+	for i, o := range s.ops {
+		out[i] = o
+	}
+	return out
+}
+
+func (s *OpenAPIToolset) buildOperation(doc map[string]any, method, path string, op map[string]any, shared []any) (*openAPIOperation, error) {
+	id, _ := op["operationId"].(string)
+	if id == "" {
+		id = method + "_" + path
+	}
+	name := s.Namespace + "_" + strings.Trim(toolNameUnsafe.ReplaceAllString(id, "_"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	desc, _ := op["summary"].(string)
+	if desc == "" {
+		desc, _ = op["description"].(string)
+	}
+	route := strings.ToUpper(method) + " " + path
+	if desc == "" {
+		desc = route
+	} else {
+		desc += " (" + route + ")"
+	}
+
+	o := &openAPIOperation{set: s, name: name, description: desc, method: strings.ToUpper(method), path: path}
+	props := map[string]any{}
+	var required []any
+
+	// Operation parameters override path-level ones with the same name and location.
+	byKey := map[string]map[string]any{}
+	var order []string
+	for _, list := range [][]any{shared, asSlice(op["parameters"])} {
+		for _, p := range list {
+			pm, _ := resolveRef(doc, p, nil).(map[string]any)
+			pname, _ := pm["name"].(string)
+			in, _ := pm["in"].(string)
+			if pname == "" || (in != "path" && in != "query" && in != "header") {
+				continue
+			}
+			key := in + ":" + pname
+			if _, ok := byKey[key]; !ok {
+				order = append(order, key)
+			}
+			byKey[key] = pm
+		}
+	}
+	for _, key := range order {
+		pm := byKey[key]
+		pname, _ := pm["name"].(string)
+		in, _ := pm["in"].(string)
+		if _, dup := props[pname]; dup {
+			return nil, fmt.Errorf("parameter %q is defined in more than one location", pname)
+		}
+		req, _ := pm["required"].(bool)
+		req = req || in == "path"
+		schema, _ := resolveRef(doc, pm["schema"], nil).(map[string]any)
+		prop := map[string]any{"type": "string"}
+		if schema != nil {
+			prop = schema
+		}
+		if d, _ := pm["description"].(string); d != "" {
+			prop = maps.Clone(prop)
+			prop["description"] = d
+		}
+		props[pname] = prop
+		if req {
+			required = append(required, pname)
+		}
+		o.params = append(o.params, openAPIParam{Name: pname, In: in, Required: req})
+	}
+
+	if rb, ok := resolveRef(doc, op["requestBody"], nil).(map[string]any); ok {
+		content, _ := rb["content"].(map[string]any)
+		if media, ok := content["application/json"].(map[string]any); ok {
+			if _, dup := props["body"]; dup {
+				return nil, fmt.Errorf("parameter %q collides with the request body", "body")
+			}
+			schema, _ := resolveRef(doc, media["schema"], nil).(map[string]any)
+			if schema == nil {
+				schema = map[string]any{"type": "object"}
+			}
+			schema = maps.Clone(schema)
+			if d, _ := rb["description"].(string); d != "" {
+				schema["description"] = d
+			} else if _, ok := schema["description"]; !ok {
+				schema["description"] = "JSON request body"
+			}
+			props["body"] = schema
+			o.hasBody = true
+			if req, _ := rb["required"].(bool); req {
+				required = append(required, "body")
+			}
+		}
+	}
+
+	o.schema = map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		o.schema["required"] = required
+	}
+	return o, nil
+}
+
+func (o *openAPIOperation) Name() string                { return o.name }
+func (o *openAPIOperation) Description() string         { return o.description }
+func (o *openAPIOperation) InputSchema() map[string]any { return o.schema }
+
+// Idempotent reports whether the operation only reads (GET or HEAD).
+func (o *openAPIOperation) Idempotent() bool {
+	return o.method == http.MethodGet || o.method == http.MethodHead
+}
+
+func (o *openAPIOperation) Execute(ctx context.Context, input any) (any, error) {
+	args, _ := input.(map[string]any)
+	if input != nil && args == nil {
+		return nil, fmt.Errorf("invalid input: expected object")
+	}
+	if o.set.BaseURL == "" {
+		return nil, fmt.Errorf("no base URL configured for %q", o.set.Namespace)
+	}
+
+	path := o.path
+	query := url.Values{}
+	headers := map[string]string{}
+	for _, p := range o.params {
+		v, ok := args[p.Name]
+		if !ok || v == nil {
+			if p.Required {
+				return nil, fmt.Errorf("%s is required", p.Name)
+			}
+			continue
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(fmt.Sprint(v)))
+		case "query":
+			if list, ok := v.([]any); ok {
+				for _, item := range list {
+					query.Add(p.Name, fmt.Sprint(item))
+				}
+			} else {
+				query.Set(p.Name, fmt.Sprint(v))
+			}
+		case "header":
+			headers[p.Name] = fmt.Sprint(v)
+		}
+	}
+	target := strings.TrimRight(o.set.BaseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var bodyReader io.Reader
+	if body, ok := args["body"]; ok && o.hasBody {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encode body: %w", err)
+		}
+		bodyReader = bytes.NewReader(data)
+	}
+
+	var connHeaders map[string]string
+	if o.set.ConnectionID != "" {
+		if o.set.connections == nil {
+			return nil, fmt.Errorf("connection_id is not supported: no connection resolver configured")
+		}
+		resolveCtx := ctx
+		if o.set.Owner != "" {
+			resolveCtx = upal.WithUserID(ctx, o.set.Owner)
+		}
+		conn, err := o.set.connections.Resolve(resolveCtx, o.set.ConnectionID)
+		if err != nil {
+			return nil, fmt.Errorf("resolve connection %q: %w", o.set.ConnectionID, err)
+		}
+		connHeaders = connectionAuthHeaders(conn)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, o.method, target, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if bodyReader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range connHeaders {
+		req.Header.Set(k, v)
+	}
+
+	client := o.set.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	bodyStr := string(bodyBytes)
+	if len(bodyBytes) > maxResponseBody {
+		bodyStr = bodyStr[:maxResponseBody] + "\n... [truncated at 100KB]"
+	}
+	return map[string]any{
+		"status_code": resp.StatusCode,
+		"status":      resp.Status,
+		"body":        redactSecrets(bodyStr, connHeaders),
+	}, nil
+}
+
+// resolveRef replaces local "#/..." references, recursively, with the
+// definitions they point to. Cyclic references collapse to a plain object.
+func resolveRef(doc map[string]any, v any, visiting []string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		if ref, ok := t["$ref"].(string); ok {
+			if slices.Contains(visiting, ref) {
+				return map[string]any{"type": "object"}
+			}
+			target, ok := lookupPointer(doc, ref)
+			if !ok {
+				return map[string]any{"type": "object"}
+			}
+			return resolveRef(doc, target, append(visiting, ref))
+		}
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			out[k] = resolveRef(doc, val, visiting)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = resolveRef(doc, val, visiting)
+		}
+		return out
+	}
+	return v
+}
+
+// lookupPointer follows a local JSON pointer such as "#/components/schemas/Pet".
+func lookupPointer(doc map[string]any, ref string) (any, bool) {
+	rest, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, false
+	}
+	var cur any = doc
+	for _, part := range strings.Split(rest, "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// jsonCompatible converts YAML-decoded maps with non-string keys (such as
+// numeric response codes) into map[string]any.
+func jsonCompatible(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			t[k] = jsonCompatible(val)
+		}
+		return t
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			out[fmt.Sprint(k)] = jsonCompatible(val)
+		}
+		return out
+	case []any:
+		for i, val := range t {
+			t[i] = jsonCompatible(val)
+		}
+		return t
+	}
+	return v
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+const petstoreSpec = `
+openapi: 3.0.3
+info:
+  title: Petstore
+servers:
+  - url: https://pets.example.com/v1
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        schema: {type: integer}
+    get:
+      operationId: getPet
+      summary: Fetch a pet
+      parameters:
+        - name: fields
+          in: query
+          description: Comma-separated fields to return
+          schema: {type: string}
+      responses:
+        200: {description: ok}
+  /pets:
+    post:
+      operationId: create-pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        201: {description: created}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        tag: {type: string}
+`
+
+func TestOpenAPIToolset_GeneratesTools(t *testing.T) {
+	set, err := NewOpenAPIToolset("pets", []byte(petstoreSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Title != "Petstore" || set.BaseURL != "https://pets.example.com/v1" {
+		t.Errorf("title/base = %q %q", set.Title, set.BaseURL)
+	}
+	ts := set.Tools()
+	if len(ts) != 2 || ts[0].Name() != "pets_create_pet" || ts[1].Name() != "pets_getPet" {
+		t.Fatalf("unexpected tools: %v", ts)
+	}
+
+	get := ts[1]
+	if get.Description() != "Fetch a pet (GET /pets/{petId})" {
+		t.Errorf("description = %q", get.Description())
+	}
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"petId":  map[string]any{"type": "integer"},
+			"fields": map[string]any{"type": "string", "description": "Comma-separated fields to return"},
+		},
+		"required": []any{"petId"},
+	}
+	if !reflect.DeepEqual(get.InputSchema(), want) {
+		t.Errorf("schema = %v", get.InputSchema())
+	}
+
+	// The request body schema is inlined from components.
+	body := ts[0].InputSchema()["properties"].(map[string]any)["body"].(map[string]any)
+	if body["type"] != "object" || body["properties"].(map[string]any)["name"] == nil {
+		t.Errorf("body schema = %v", body)
+	}
+	if req := ts[0].InputSchema()["required"]; !reflect.DeepEqual(req, []any{"body"}) {
+		t.Errorf("required = %v", req)
+	}
+}
+
+func TestOpenAPIToolset_ExecuteCallsOperation(t *testing.T) {
+	var gotMethod, gotPath, gotQuery, gotAuth, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotQuery = r.Method, r.URL.Path, r.URL.RawQuery
+		gotAuth = r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.Write([]byte(`{"id": 42, "token": "s3cret"}`))
+	}))
+	defer srv.Close()
+
+	set, err := NewOpenAPIToolset("pets", []byte(petstoreSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set.BaseURL = srv.URL + "/v1"
+	set.ConnectionID = "conn-1"
+	set.SetConnectionResolver(stubConnections{"conn-1": {ID: "conn-1", Type: upal.ConnTypeHTTP, Token: "s3cret"}})
+	ts := set.Tools()
+
+	res, err := ts[1].Execute(context.Background(), map[string]any{"petId": 42.0, "fields": "name,tag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotMethod != "GET" || gotPath != "/v1/pets/42" || gotQuery != "fields=name%2Ctag" {
+		t.Errorf("request = %s %s?%s", gotMethod, gotPath, gotQuery)
+	}
+	if gotAuth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	out := res.(map[string]any)
+	if out["status_code"] != 200 || out["body"] != `{"id": 42, "token": "[redacted]"}` {
+		t.Errorf("result = %v", out)
+	}
+
+	if _, err := ts[0].Execute(context.Background(), map[string]any{"body": map[string]any{"name": "Rex"}}); err != nil {
+		t.Fatal(err)
+	}
+	var sent map[string]any
+	json.Unmarshal([]byte(gotBody), &sent)
+	if gotMethod != "POST" || gotPath != "/v1/pets" || sent["name"] != "Rex" {
+		t.Errorf("request = %s %s %s", gotMethod, gotPath, gotBody)
+	}
+
+	if _, err := ts[1].Execute(context.Background(), map[string]any{}); err == nil {
+		t.Error("expected error for missing path parameter")
+	}
+}
+
+func TestOpenAPIToolset_RejectsInvalidSpecs(t *testing.T) {
+	for name, spec := range map[string]string{
+		"swagger2": `{"swagger": "2.0", "paths": {}}`,
+		"no ops":   `{"openapi": "3.1.0", "paths": {}}`,
+		"garbage":  `[1, 2`,
+	} {
+		if _, err := NewOpenAPIToolset("ns", []byte(spec)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := NewOpenAPIToolset("bad ns", []byte(petstoreSpec)); err == nil {
+		t.Error("expected error for invalid namespace")
+	}
+}
+
+func TestRegistry_RegisterNamespaceReplaces(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&echoTool{})
+	set, _ := NewOpenAPIToolset("pets", []byte(petstoreSpec))
+	if err := reg.RegisterNamespace("pets", set.Tools()); err != nil {
+		t.Fatal(err)
+	}
+	if len(reg.List()) != 3 {
+		t.Fatalf("expected 3 tools, got %d", len(reg.List()))
+	}
+	if err := reg.RegisterNamespace("pets", set.Tools()[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reg.Get("pets_getPet"); ok {
+		t.Error("re-import kept a stale tool")
+	}
+	if !reg.UnregisterNamespace("pets") || len(reg.List()) != 1 {
+		t.Errorf("unregister left %d tools", len(reg.List()))
+	}
+	if reg.UnregisterNamespace("pets") {
+		t.Error("second unregister reported success")
+	}
+}
+
+func TestRegistry_RegisterNamespaceRejectsCollisions(t *testing.T) {
+	reg := NewRegistry()
+	set, _ := NewOpenAPIToolset("pets", []byte(petstoreSpec))
+	if err := reg.RegisterNamespace("pets", set.Tools()); err != nil {
+		t.Fatal(err)
+	}
+	// Another namespace, or a built-in, may not take over the same names.
+	if err := reg.RegisterNamespace("other", set.Tools()); err == nil {
+		t.Error("expected a collision with namespace pets")
+	}
+	builtin := NewRegistry()
+	builtin.Register(set.Tools()[0])
+	if err := builtin.RegisterNamespace("pets", set.Tools()); err == nil {
+		t.Error("expected a collision with a built-in tool")
+	}
+	if len(builtin.List()) != 1 {
+		t.Errorf("failed registration left %d tools, want 1", len(builtin.List()))
+	}
+}
+
+// userConnections records the user each connection was resolved for.
+type userConnections struct{ users []string }
+
+func (c *userConnections) Resolve(ctx context.Context, id string) (*upal.Connection, error) {
+	c.users = append(c.users, upal.UserIDFromContext(ctx))
+	return &upal.Connection{ID: id, Type: upal.ConnTypeHTTP, Token: "t"}, nil
+}
+
+func TestOpenAPIToolset_ResolvesConnectionAsOwner(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	set, err := NewOpenAPIToolset("pets", []byte(petstoreSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set.BaseURL = srv.URL
+	set.ConnectionID = "conn-1"
+	set.Owner = "owner-1"
+	conns := &userConnections{}
+	set.SetConnectionResolver(conns)
+
+	ctx := upal.WithUserID(context.Background(), "caller-1")
+	if _, err := set.Tools()[1].Execute(ctx, map[string]any{"petId": 1.0}); err != nil {
+		t.Fatal(err)
+	}
+	if len(conns.users) != 1 || conns.users[0] != "owner-1" {
+		t.Errorf("connection resolved as %v, want owner-1", conns.users)
+	}
+}
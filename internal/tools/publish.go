@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -15,16 +14,37 @@ import (
 )
 
 type PublishTool struct {
-	outputDir string
+	outputDir         string
+	target            PublishTarget
+	connections       ConnectionResolver
+	defaultConnection string
 }
 
+// NewPublishTool publishes markdown files into outputDir unless a connection
+// selects another target.
 func NewPublishTool(outputDir string) *PublishTool {
-	return &PublishTool{outputDir: outputDir}
+	return &PublishTool{outputDir: outputDir, target: &LocalPublishTarget{Dir: outputDir}}
+}
+
+// SetTarget replaces the local directory as the default markdown_file target.
+func (p *PublishTool) SetTarget(t PublishTarget) { p.target = t }
+
+// SetConnectionResolver enables the connection_id argument, which publishes
+// markdown_file content to the target a stored connection describes (http,
+// webhook or s3). defaultConnectionID, when set, is used for calls that do
+// not name a connection.
+func (p *PublishTool) SetConnectionResolver(r ConnectionResolver, defaultConnectionID string) {
+	p.connections = r
+	p.defaultConnection = defaultConnectionID
 }
 
 func (p *PublishTool) Name() string { return "publish" }
+
+// Idempotent is false: every call publishes.
+func (p *PublishTool) Idempotent() bool { return false }
+
 func (p *PublishTool) Description() string {
-	return "Publish content to various channels. Supports 'markdown_file' (save to local file) and 'webhook' (POST to external URL). Extensible for future platform integrations."
+	return "Publish content to various channels. Supports 'markdown_file' (save as a markdown file to the configured target: local directory, S3 or an HTTP endpoint) and 'webhook' (POST to external URL). Returns the published URL."
 }
 
 func (p *PublishTool) InputSchema() map[string]any {
@@ -48,6 +68,10 @@ func (p *PublishTool) InputSchema() map[string]any {
 				"type":        "object",
 				"description": "Channel-specific metadata. For webhook: { webhook_url: string }",
 			},
+			"connection_id": map[string]any{
+				"type":        "string",
+				"description": "Optional stored connection (http, webhook or s3) to publish markdown_file content to instead of the default target",
+			},
 		},
 		"required": []any{"channel", "content"},
 	}
@@ -70,7 +94,8 @@ func (p *PublishTool) Execute(ctx context.Context, input any) (any, error) {
 
 	switch channel {
 	case "markdown_file":
-		return p.publishMarkdown(title, content, metadata)
+		connID, _ := args["connection_id"].(string)
+		return p.publishMarkdown(ctx, connID, title, content, metadata)
 	case "webhook":
 		return p.publishWebhook(ctx, title, content, metadata)
 	default:
@@ -78,9 +103,10 @@ func (p *PublishTool) Execute(ctx context.Context, input any) (any, error) {
 	}
 }
 
-func (p *PublishTool) publishMarkdown(title, content string, metadata map[string]any) (any, error) {
-	if err := os.MkdirAll(p.outputDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create output directory: %w", err)
+func (p *PublishTool) publishMarkdown(ctx context.Context, connID, title, content string, metadata map[string]any) (any, error) {
+	target, err := p.resolveTarget(ctx, connID)
+	if err != nil {
+		return nil, err
 	}
 
 	slug := slugify(title)
@@ -88,7 +114,6 @@ func (p *PublishTool) publishMarkdown(title, content string, metadata map[string
 		slug = "untitled"
 	}
 	filename := fmt.Sprintf("%s-%s.md", time.Now().Format("2006-01-02"), slug)
-	path := filepath.Join(p.outputDir, filename)
 
 	var buf strings.Builder
 	if title != "" {
@@ -104,14 +129,44 @@ func (p *PublishTool) publishMarkdown(title, content string, metadata map[string
 	}
 	buf.WriteString(content)
 
-	if err := os.WriteFile(path, []byte(buf.String()), 0644); err != nil {
-		return nil, fmt.Errorf("failed to write file: %w", err)
+	publishedURL, err := target.Publish(ctx, PublishDocument{
+		Filename:    filename,
+		Title:       title,
+		Content:     buf.String(),
+		ContentType: "text/markdown; charset=utf-8",
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return map[string]any{
+	result := map[string]any{
 		"status": "published",
-		"path":   path,
-	}, nil
+		"url":    publishedURL,
+	}
+	if local, ok := target.(*LocalPublishTarget); ok {
+		result["path"] = filepath.Join(local.Dir, filename)
+	}
+	return result, nil
+}
+
+// resolveTarget returns the target for connID, the default connection, or
+// the tool's own target, in that order.
+func (p *PublishTool) resolveTarget(ctx context.Context, connID string) (PublishTarget, error) {
+	if connID == "" {
+		connID = p.defaultConnection
+	}
+	if connID == "" {
+		return p.target, nil
+	}
+	if p.connections == nil {
+		return nil, fmt.Errorf("connection_id %q given but no connection store is configured", connID)
+	}
+	conn, err := p.connections.Resolve(ctx, connID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve connection %q: %w", connID, err)
+	}
+	return publishTargetFromConnection(conn)
 }
 
 func (p *PublishTool) publishWebhook(ctx context.Context, title, content string, metadata map[string]any) (any, error) {
@@ -0,0 +1,276 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/soochol/upal/internal/notify"
+	"github.com/soochol/upal/internal/upal"
+)
+
+// PublishDocument is one piece of content handed to a PublishTarget.
+type PublishDocument struct {
+	Filename    string
+	Title       string
+	Content     string
+	ContentType string
+	Metadata    map[string]any
+}
+
+// PublishTarget stores a published document and returns the URL it can be
+// reached at.
+type PublishTarget interface {
+	Publish(ctx context.Context, doc PublishDocument) (string, error)
+}
+
+// LocalPublishTarget writes documents into Dir. The returned URL is BaseURL
+// joined with the filename when BaseURL is set, otherwise the file path.
+type LocalPublishTarget struct {
+	Dir     string
+	BaseURL string
+}
+
+func (t *LocalPublishTarget) Publish(_ context.Context, doc PublishDocument) (string, error) {
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	path := filepath.Join(t.Dir, doc.Filename)
+	if err := os.WriteFile(path, []byte(doc.Content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if t.BaseURL != "" {
+		return strings.TrimRight(t.BaseURL, "/") + "/" + url.PathEscape(doc.Filename), nil
+	}
+	return path, nil
+}
+
+// HTTPPublishTarget POSTs documents as JSON ({filename, title, content,
+// metadata}) to URL. The published URL is read from the response's "url"
+// field, falling back to its Location header.
+type HTTPPublishTarget struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+func (t *HTTPPublishTarget) Publish(ctx context.Context, doc PublishDocument) (string, error) {
+	payload, _ := json.Marshal(map[string]any{
+		"filename": doc.Filename,
+		"title":    doc.Title,
+		"content":  doc.Content,
+		"metadata": doc.Metadata,
+	})
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, t.URL, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("publish request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("publish endpoint returned status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		URL string `json:"url"`
+	}
+	if json.Unmarshal(body, &out) == nil && out.URL != "" {
+		return out.URL, nil
+	}
+	return resp.Header.Get("Location"), nil
+}
+
+// S3PublishTarget uploads documents to an S3-compatible bucket with a
+// SigV4-signed PUT (path-style addressing). Endpoint defaults to AWS for
+// Region; PublicURL, when set, replaces the bucket URL in the returned link.
+type S3PublishTarget struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+	Client    *http.Client
+	now       func() time.Time
+}
+
+func (t *S3PublishTarget) Publish(ctx context.Context, doc PublishDocument) (string, error) {
+	if t.Bucket == "" {
+		return "", fmt.Errorf("s3 target: bucket is required")
+	}
+	region := t.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimRight(t.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	key := strings.TrimLeft(t.Prefix+doc.Filename, "/")
+	objectPath := "/" + t.Bucket + "/" + escapeS3Key(key)
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPut, endpoint+objectPath, strings.NewReader(doc.Content))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	contentType := doc.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+	signS3Request(req, []byte(doc.Content), region, t.AccessKey, t.SecretKey, now().UTC())
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("s3 upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("s3 upload returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if t.PublicURL != "" {
+		return strings.TrimRight(t.PublicURL, "/") + "/" + escapeS3Key(key), nil
+	}
+	return endpoint + objectPath, nil
+}
+
+// escapeS3Key percent-encodes each path segment of an object key.
+func escapeS3Key(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// signS3Request adds AWS Signature Version 4 headers for the s3 service.
+func signS3Request(req *http.Request, body []byte, region, accessKey, secretKey string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"content-type":         req.Header.Get("Content-Type"),
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	var canonicalHeaders strings.Builder
+	for _, h := range signed {
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(values[h]) + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// publishTargetFromConnection builds the target a stored connection points
+// at. http and webhook connections POST to Extras["url"] or Host with the
+// connection's headers and bearer token; s3 connections use Host as the
+// endpoint, Login/Password as the access key pair and Extras bucket, region,
+// prefix and public_url.
+func publishTargetFromConnection(conn *upal.Connection) (PublishTarget, error) {
+	switch conn.Type {
+	case upal.ConnTypeHTTP, upal.ConnTypeWebhook:
+		target, _ := conn.Extras["url"].(string)
+		if target == "" {
+			target = conn.Host
+		}
+		if target == "" {
+			return nil, fmt.Errorf("connection %q has no url", conn.ID)
+		}
+		headers, err := notify.WebhookHeaders(conn)
+		if err != nil {
+			return nil, err
+		}
+		if conn.Token != "" {
+			headers["Authorization"] = "Bearer " + conn.Token
+		}
+		return &HTTPPublishTarget{URL: target, Headers: headers}, nil
+	case upal.ConnTypeS3:
+		extra := func(k string) string { s, _ := conn.Extras[k].(string); return s }
+		return &S3PublishTarget{
+			Endpoint:  conn.Host,
+			Region:    extra("region"),
+			Bucket:    extra("bucket"),
+			Prefix:    extra("prefix"),
+			PublicURL: extra("public_url"),
+			AccessKey: conn.Login,
+			SecretKey: conn.Password,
+		}, nil
+	default:
+		return nil, fmt.Errorf("connection %q: type %q cannot be used as a publish target", conn.ID, conn.Type)
+	}
+}
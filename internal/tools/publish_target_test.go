@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+func TestPublishTool_HTTPTarget(t *testing.T) {
+	var received map[string]any
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"url": "https://blog.example.com/posts/` + received["filename"].(string) + `"}`))
+	}))
+	defer srv.Close()
+
+	tool := NewPublishTool(t.TempDir())
+	tool.SetConnectionResolver(stubConnections{
+		"blog": {ID: "blog", Type: upal.ConnTypeHTTP, Host: srv.URL, Token: "secret"},
+	}, "")
+
+	result, err := tool.Execute(context.Background(), map[string]any{
+		"channel":       "markdown_file",
+		"title":         "Launch Notes",
+		"content":       "We shipped it.",
+		"connection_id": "blog",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	res := result.(map[string]any)
+	filename, _ := received["filename"].(string)
+	if !strings.HasSuffix(filename, "-launch-notes.md") {
+		t.Errorf("filename = %q", filename)
+	}
+	if content, _ := received["content"].(string); !strings.Contains(content, `title: "Launch Notes"`) || !strings.HasSuffix(content, "We shipped it.") {
+		t.Errorf("published content = %q", content)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if res["url"] != "https://blog.example.com/posts/"+filename {
+		t.Errorf("url = %v", res["url"])
+	}
+	if _, ok := res["path"]; ok {
+		t.Errorf("remote publish should not report a local path: %v", res)
+	}
+}
+
+func TestHTTPPublishTarget_LocationHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "https://cdn.example.com/a.md")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	got, err := (&HTTPPublishTarget{URL: srv.URL}).Publish(context.Background(), PublishDocument{Filename: "a.md", Content: "x"})
+	if err != nil || got != "https://cdn.example.com/a.md" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
+
+func TestPublishTool_DefaultConnectionS3(t *testing.T) {
+	var method, path, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, auth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	tool := NewPublishTool(t.TempDir())
+	tool.SetConnectionResolver(stubConnections{
+		"bucket": {ID: "bucket", Type: upal.ConnTypeS3, Host: srv.URL, Login: "AKID", Password: "sk",
+			Extras: map[string]any{"bucket": "site", "prefix": "posts/", "region": "ap-northeast-2"}},
+	}, "bucket")
+
+	result, err := tool.Execute(context.Background(), map[string]any{
+		"channel": "markdown_file",
+		"title":   "Hello",
+		"content": "body",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != http.MethodPut || !strings.HasPrefix(path, "/site/posts/") || !strings.HasSuffix(body, "\n\nbody") {
+		t.Errorf("unexpected upload: %s %s", method, path)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/ap-northeast-2/s3/aws4_request") {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if url := result.(map[string]any)["url"]; url != srv.URL+path {
+		t.Errorf("url = %v, want %s", url, srv.URL+path)
+	}
+}
@@ -17,6 +17,9 @@ type PythonExecTool struct{}
 
 func (p *PythonExecTool) Name() string { return "python_exec" }
 
+// Idempotent is false: scripts may have side effects.
+func (p *PythonExecTool) Idempotent() bool { return false }
+
 func (p *PythonExecTool) Description() string {
 	return "Execute Python code and return stdout and stderr. Use this to run calculations, process data, or perform any task that benefits from code execution."
 }
@@ -4,21 +4,31 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // Registry holds both custom tools (executed by Upal) and native tools
 // (executed by the LLM provider). It provides a unified list for the API
-// and lets callers distinguish between the two via IsNative.
+// and lets callers distinguish between the two via IsNative. Custom tools
+// returned by Get are metered: see Stats and SetRateLimit.
 type Registry struct {
 	mu     sync.RWMutex
 	tools  map[string]Tool
 	native map[string]NativeTool
+	meters map[string]*toolMeter
+	now    func() time.Time
+
+	// namespaces maps a namespace to the tool names registered under it.
+	namespaces map[string][]string
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		tools:  make(map[string]Tool),
-		native: make(map[string]NativeTool),
+		tools:      make(map[string]Tool),
+		native:     make(map[string]NativeTool),
+		meters:     make(map[string]*toolMeter),
+		now:        time.Now,
+		namespaces: make(map[string][]string),
 	}
 }
 
@@ -29,6 +39,50 @@ func (r *Registry) Register(t Tool) {
 	r.tools[t.Name()] = t
 }
 
+// RegisterNamespace registers a group of generated tools (such as an
+// imported OpenAPI toolset) under ns, replacing any tools previously
+// registered under the same namespace. It fails, registering nothing, when a
+// tool name is already taken by a built-in tool, a native tool or another
+// namespace.
+func (r *Registry) RegisterNamespace(ns string, ts []Tool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	own := make(map[string]bool, len(r.namespaces[ns]))
+	for _, name := range r.namespaces[ns] {
+		own[name] = true
+	}
+	for _, t := range ts {
+		_, custom := r.tools[t.Name()]
+		_, native := r.native[t.Name()]
+		if (custom && !own[t.Name()]) || native {
+			return fmt.Errorf("tool %q is already registered", t.Name())
+		}
+	}
+	for name := range own {
+		delete(r.tools, name)
+	}
+	names := make([]string, 0, len(ts))
+	for _, t := range ts {
+		r.tools[t.Name()] = t
+		names = append(names, t.Name())
+	}
+	r.namespaces[ns] = names
+	return nil
+}
+
+// UnregisterNamespace removes every tool registered under ns and reports
+// whether the namespace existed.
+func (r *Registry) UnregisterNamespace(ns string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names, ok := r.namespaces[ns]
+	for _, name := range names {
+		delete(r.tools, name)
+	}
+	delete(r.namespaces, ns)
+	return ok
+}
+
 // RegisterNative adds a provider-managed tool (not executed by Upal).
 func (r *Registry) RegisterNative(t NativeTool) {
 	r.mu.Lock()
@@ -36,12 +90,16 @@ func (r *Registry) RegisterNative(t NativeTool) {
 	r.native[t.Name()] = t
 }
 
-// Get returns a custom tool by name.
+// Get returns a custom tool by name, wrapped so its executions count toward
+// the tool's usage stats and rate limit.
 func (r *Registry) Get(name string) (Tool, bool) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
 	t, ok := r.tools[name]
-	return t, ok
+	r.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return &meteredTool{Tool: t, meter: r.meterFor(name), now: r.now}, true
 }
 
 // IsNative returns true if the name refers to a registered native tool.
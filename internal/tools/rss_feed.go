@@ -3,14 +3,31 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/mmcdole/gofeed"
 )
 
-type RSSFeedTool struct{}
+// rssSeenKeyPrefix prefixes the content store key holding a feed's seen item IDs.
+const rssSeenKeyPrefix = "fetch_rss:seen:"
+
+// rssSeenLimit caps the seen IDs remembered per feed; the oldest are dropped.
+const rssSeenLimit = 1000
+
+type RSSFeedTool struct {
+	store ContentStoreBackend
+	mu    sync.Mutex // serializes read-modify-write of seen IDs
+}
+
+// SetContentStore enables only_new mode and the reset action, which keep the
+// IDs of already returned items per feed URL in the content store.
+func (r *RSSFeedTool) SetContentStore(store ContentStoreBackend) {
+	r.store = store
+}
 
 func (r *RSSFeedTool) Name() string { return "fetch_rss" }
 func (r *RSSFeedTool) Description() string {
@@ -21,10 +38,19 @@ func (r *RSSFeedTool) InputSchema() map[string]any {
 	return map[string]any{
 		"type": "object",
 		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        []any{"fetch", "reset"},
+				"description": "\"fetch\" (default) returns items; \"reset\" forgets the items only_new has already returned for this feed",
+			},
 			"url": map[string]any{
 				"type":        "string",
 				"description": "URL of the RSS/Atom/JSON feed to fetch",
 			},
+			"only_new": map[string]any{
+				"type":        "boolean",
+				"description": "Return only items not returned by an earlier only_new call for this feed, and remember them as seen",
+			},
 			"max_items": map[string]any{
 				"type":        "number",
 				"description": "Maximum number of items to return (default: all)",
@@ -49,6 +75,22 @@ func (r *RSSFeedTool) Execute(ctx context.Context, input any) (any, error) {
 		return nil, fmt.Errorf("url is required")
 	}
 
+	action, _ := args["action"].(string)
+	onlyNew, _ := args["only_new"].(bool)
+	if (onlyNew || action == "reset") && r.store == nil {
+		return nil, fmt.Errorf("only_new and reset require a content store")
+	}
+	switch action {
+	case "", "fetch":
+	case "reset":
+		if err := r.store.Delete(ctx, rssSeenKeyPrefix+url); err != nil {
+			return nil, fmt.Errorf("failed to reset seen items: %w", err)
+		}
+		return map[string]any{"status": "ok"}, nil
+	default:
+		return nil, fmt.Errorf("unknown action %q: use fetch or reset", action)
+	}
+
 	maxItems := 0
 	if v, ok := args["max_items"].(float64); ok && v > 0 {
 		maxItems = int(v)
@@ -74,8 +116,26 @@ func (r *RSSFeedTool) Execute(ctx context.Context, input any) (any, error) {
 		return nil, fmt.Errorf("failed to fetch/parse feed: %w", err)
 	}
 
+	var seen []string
+	seenSet := make(map[string]bool)
+	if onlyNew {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if seen, err = r.loadSeen(ctx, url); err != nil {
+			return nil, err
+		}
+		for _, id := range seen {
+			seenSet[id] = true
+		}
+	}
+
 	var items []map[string]any
 	for _, item := range feed.Items {
+		id := rssItemID(item)
+		if onlyNew && seenSet[id] {
+			continue
+		}
+
 		// When since_date is set, exclude items with no parseable date and items older than the cutoff.
 		if !sinceDate.IsZero() && (item.PublishedParsed == nil || item.PublishedParsed.Before(sinceDate)) {
 			continue
@@ -101,11 +161,22 @@ func (r *RSSFeedTool) Execute(ctx context.Context, input any) (any, error) {
 			"author":    author,
 		})
 
+		if onlyNew && id != "" && !seenSet[id] {
+			seenSet[id] = true
+			seen = append(seen, id)
+		}
+
 		if maxItems > 0 && len(items) >= maxItems {
 			break
 		}
 	}
 
+	if onlyNew {
+		if err := r.saveSeen(ctx, url, seen); err != nil {
+			return nil, err
+		}
+	}
+
 	return map[string]any{
 		"items":      items,
 		"feed_title": feed.Title,
@@ -113,3 +184,45 @@ func (r *RSSFeedTool) Execute(ctx context.Context, input any) (any, error) {
 		"item_count": len(items),
 	}, nil
 }
+
+// rssItemID identifies a feed item across fetches: its GUID, else its link,
+// else its title.
+func rssItemID(item *gofeed.Item) string {
+	switch {
+	case item.GUID != "":
+		return item.GUID
+	case item.Link != "":
+		return item.Link
+	default:
+		return item.Title
+	}
+}
+
+func (r *RSSFeedTool) loadSeen(ctx context.Context, url string) ([]string, error) {
+	raw, found, err := r.store.Get(ctx, rssSeenKeyPrefix+url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load seen items: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	var seen []string
+	if err := json.Unmarshal([]byte(raw), &seen); err != nil {
+		return nil, fmt.Errorf("corrupt seen items for %s: %w", url, err)
+	}
+	return seen, nil
+}
+
+func (r *RSSFeedTool) saveSeen(ctx context.Context, url string, seen []string) error {
+	if len(seen) > rssSeenLimit {
+		seen = seen[len(seen)-rssSeenLimit:]
+	}
+	raw, err := json.Marshal(seen)
+	if err != nil {
+		return err
+	}
+	if err := r.store.Set(ctx, rssSeenKeyPrefix+url, string(raw)); err != nil {
+		return fmt.Errorf("failed to persist seen items: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -150,3 +152,58 @@ func TestRSSFeedTool_InvalidURL(t *testing.T) {
 		t.Fatal("expected error for invalid URL")
 	}
 }
+
+func TestRSSFeedTool_OnlyNewReturnsUnseenItems(t *testing.T) {
+	feed := testRSSFeed
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(feed))
+	}))
+	defer srv.Close()
+
+	tool := &RSSFeedTool{}
+	tool.SetContentStore(NewFileContentStore(filepath.Join(t.TempDir(), "store.json")))
+	fetch := func() []string {
+		t.Helper()
+		result, err := tool.Execute(context.Background(), map[string]any{"url": srv.URL, "only_new": true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var titles []string
+		for _, item := range result.(map[string]any)["items"].([]map[string]any) {
+			titles = append(titles, item["title"].(string))
+		}
+		return titles
+	}
+
+	if got := fetch(); len(got) != 2 {
+		t.Fatalf("first call: got %v, want both items", got)
+	}
+	if got := fetch(); len(got) != 0 {
+		t.Fatalf("second call with no new items: got %v, want none", got)
+	}
+
+	feed = strings.Replace(testRSSFeed, "<item>", `<item>
+      <title>Article Three</title>
+      <link>https://example.com/3</link>
+    </item>
+    <item>`, 1)
+	if got := fetch(); len(got) != 1 || got[0] != "Article Three" {
+		t.Fatalf("third call: got %v, want only Article Three", got)
+	}
+
+	if _, err := tool.Execute(context.Background(), map[string]any{"url": srv.URL, "action": "reset"}); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if got := fetch(); len(got) != 3 {
+		t.Fatalf("after reset: got %v, want all 3 items", got)
+	}
+}
+
+func TestRSSFeedTool_OnlyNewRequiresStore(t *testing.T) {
+	tool := &RSSFeedTool{}
+	_, err := tool.Execute(context.Background(), map[string]any{"url": "http://example.com/feed", "only_new": true})
+	if err == nil {
+		t.Fatal("expected error without a content store")
+	}
+}
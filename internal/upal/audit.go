@@ -0,0 +1,115 @@
+package upal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// AuditAction is the kind of mutation recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// AuditEntry records a single mutating operation: who changed which resource, and how.
+type AuditEntry struct {
+	ID           string        `json:"id"`
+	Actor        string        `json:"actor"`
+	Action       AuditAction   `json:"action"`
+	ResourceType string        `json:"resource_type"` // "workflow" | "schedule" | "trigger" | "connection" | "pipeline"
+	ResourceID   string        `json:"resource_id,omitempty"`
+	Method       string        `json:"method,omitempty"` // HTTP method; empty for service-level changes
+	Path         string        `json:"path,omitempty"`
+	Status       int           `json:"status,omitempty"`
+	Changes      []AuditChange `json:"changes,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// AuditChange summarises how one top-level field changed. Scalar values are
+// kept verbatim; lists and objects are summarised to keep entries small.
+type AuditChange struct {
+	Field   string `json:"field"`
+	Before  any    `json:"before,omitempty"`
+	After   any    `json:"after,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// AuditFilter narrows an audit log query. Zero values match everything.
+type AuditFilter struct {
+	ResourceType string
+	ResourceID   string
+	Action       AuditAction
+	Limit        int
+}
+
+// auditRedacted lists fields whose values never appear in the audit log.
+var auditRedacted = map[string]bool{"password": true, "token": true, "api_key": true, "secret": true}
+
+// DiffFields compares the top-level JSON fields of before and after and
+// returns one AuditChange per field that differs, sorted by field name.
+// A nil before (or after) is treated as an empty object.
+func DiffFields(before, after any) []AuditChange {
+	b, a := toFieldMap(before), toFieldMap(after)
+	keys := make(map[string]struct{}, len(b)+len(a))
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	fields := make([]string, 0, len(keys))
+	for k := range keys {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	var changes []AuditChange
+	for _, f := range fields {
+		bv, av := b[f], a[f]
+		if reflect.DeepEqual(bv, av) {
+			continue
+		}
+		changes = append(changes, summarizeChange(f, bv, av))
+	}
+	return changes
+}
+
+func summarizeChange(field string, before, after any) AuditChange {
+	c := AuditChange{Field: field}
+	if auditRedacted[field] {
+		c.Summary = "changed (redacted)"
+		return c
+	}
+	bl, bList := before.([]any)
+	al, aList := after.([]any)
+	_, bMap := before.(map[string]any)
+	_, aMap := after.(map[string]any)
+	switch {
+	case bList || aList:
+		c.Summary = fmt.Sprintf("%d → %d items", len(bl), len(al))
+	case bMap || aMap:
+		c.Summary = "changed"
+	default:
+		c.Before, c.After = before, after
+	}
+	return c
+}
+
+func toFieldMap(v any) map[string]any {
+	m := map[string]any{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return m
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(data, &m)
+	return m
+}
@@ -1,5 +1,7 @@
 package upal
 
+import "time"
+
 // ConnectionType identifies the kind of external service a connection targets.
 type ConnectionType string
 
@@ -8,6 +10,8 @@ const (
 	ConnTypeSlack    ConnectionType = "slack"
 	ConnTypeHTTP     ConnectionType = "http"
 	ConnTypeSMTP     ConnectionType = "smtp"
+	ConnTypeWebhook  ConnectionType = "webhook"
+	ConnTypeS3       ConnectionType = "s3"
 
 	// Content media pipeline connections
 	ConnTypeReddit   ConnectionType = "reddit"
@@ -18,6 +22,11 @@ const (
 	ConnTypeSerpAPI  ConnectionType = "serpapi"
 )
 
+// ConnectionHeadersKey is the Extras key holding request headers sent with
+// the connection. They often carry API keys, so they are encrypted at rest
+// and masked in ConnectionSafe.
+const ConnectionHeadersKey = "headers"
+
 // Connection stores credentials and configuration for an external service.
 type Connection struct {
 	ID       string         `json:"id"`
@@ -28,7 +37,14 @@ type Connection struct {
 	Login    string         `json:"login,omitempty"`
 	Password string         `json:"password,omitempty"` // encrypted at rest
 	Token    string         `json:"token,omitempty"`    // encrypted at rest
-	Extras   map[string]any `json:"extras,omitempty"`
+	Extras   map[string]any `json:"extras,omitempty"`   // "headers" encrypted at rest
+
+	// OAuth refresh: when TokenURL and RefreshToken are set, an access token
+	// past TokenExpiresAt is refreshed before the connection is handed out.
+	// Login and Password, when set, are sent as the OAuth client ID and secret.
+	RefreshToken   string     `json:"refresh_token,omitempty"` // encrypted at rest
+	TokenURL       string     `json:"token_url,omitempty"`
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"`
 }
 
 // ConnectionSafe is the API-safe view of a Connection with secrets masked.
@@ -40,10 +56,25 @@ type ConnectionSafe struct {
 	Port   int            `json:"port,omitempty"`
 	Login  string         `json:"login,omitempty"`
 	Extras map[string]any `json:"extras,omitempty"`
+
+	TokenURL       string     `json:"token_url,omitempty"`
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"`
 }
 
+// MaskedSecret replaces secret values in a ConnectionSafe. Sending it back
+// in an update keeps the stored value.
+const MaskedSecret = "********"
+
 // Safe returns a ConnectionSafe view with secrets removed.
 func (c *Connection) Safe() ConnectionSafe {
+	extras := c.Extras
+	if _, ok := extras[ConnectionHeadersKey]; ok {
+		extras = make(map[string]any, len(c.Extras))
+		for k, v := range c.Extras {
+			extras[k] = v
+		}
+		extras[ConnectionHeadersKey] = MaskedSecret
+	}
 	return ConnectionSafe{
 		ID:     c.ID,
 		Name:   c.Name,
@@ -51,6 +82,9 @@ func (c *Connection) Safe() ConnectionSafe {
 		Host:   c.Host,
 		Port:   c.Port,
 		Login:  c.Login,
-		Extras: c.Extras,
+		Extras: extras,
+
+		TokenURL:       c.TokenURL,
+		TokenExpiresAt: c.TokenExpiresAt,
 	}
 }
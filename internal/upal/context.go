@@ -18,3 +18,73 @@ func UserIDFromContext(ctx context.Context) string {
 	}
 	return "default"
 }
+
+const runIDKey contextKey = "runID"
+
+// WithRunID returns a new context carrying the correlation ID shared by all
+// log lines of one request or run.
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey, runID)
+}
+
+// RunIDFromContext extracts the correlation ID from the context, or "" if unset.
+func RunIDFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(runIDKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
+const modelOverridesKey contextKey = "modelOverrides"
+
+// ModelOverrides substitutes the model of agent nodes for a single run.
+// Nodes entries (node ID → "provider/model") take precedence over Default.
+type ModelOverrides struct {
+	Default string
+	Nodes   map[string]string
+}
+
+// IsZero reports whether no override is set.
+func (o ModelOverrides) IsZero() bool {
+	return o.Default == "" && len(o.Nodes) == 0
+}
+
+// WithModelOverrides returns a new context carrying per-run model overrides.
+func WithModelOverrides(ctx context.Context, o ModelOverrides) context.Context {
+	return context.WithValue(ctx, modelOverridesKey, o)
+}
+
+// ModelOverridesFromContext extracts model overrides from the context.
+func ModelOverridesFromContext(ctx context.Context) (ModelOverrides, bool) {
+	o, ok := ctx.Value(modelOverridesKey).(ModelOverrides)
+	return o, ok && !o.IsZero()
+}
+
+const restoredOutputsKey contextKey = "restoredOutputs"
+
+// WithRestoredOutputs returns a new context carrying node outputs checkpointed
+// by an earlier run. Those nodes are not re-executed; their stored output is
+// placed in the session state instead.
+func WithRestoredOutputs(ctx context.Context, outputs map[string]any) context.Context {
+	return context.WithValue(ctx, restoredOutputsKey, outputs)
+}
+
+// RestoredOutputsFromContext extracts restored node outputs from the context.
+func RestoredOutputsFromContext(ctx context.Context) (map[string]any, bool) {
+	o, ok := ctx.Value(restoredOutputsKey).(map[string]any)
+	return o, ok && len(o) > 0
+}
+
+const stageOutputsKey contextKey = "stageOutputs"
+
+// WithStageOutputs returns a new context carrying the outputs of the pipeline
+// stages completed so far, keyed by stage ID.
+func WithStageOutputs(ctx context.Context, outputs map[string]map[string]any) context.Context {
+	return context.WithValue(ctx, stageOutputsKey, outputs)
+}
+
+// StageOutputsFromContext extracts completed stage outputs from the context.
+func StageOutputsFromContext(ctx context.Context) map[string]map[string]any {
+	o, _ := ctx.Value(stageOutputsKey).(map[string]map[string]any)
+	return o
+}
@@ -3,5 +3,36 @@ package upal
 import "errors"
 
 var (
-	ErrInvalidStatus = errors.New("invalid status for operation")
+	ErrInvalidStatus     = errors.New("invalid status for operation")
+	ErrMaintenance       = errors.New("server is in maintenance mode")
+	ErrModerationBlocked = errors.New("inputs blocked by moderation")
+	ErrRunCancelled      = errors.New("run cancelled")
+	// ErrNoLLMProvider means generation or agent execution was requested
+	// but no LLM provider is configured to serve it.
+	ErrNoLLMProvider = errors.New("no LLM providers configured")
 )
+
+// RunCancelledError is the cancellation cause of a run stopped on request,
+// carrying the reason given. It matches ErrRunCancelled.
+type RunCancelledError struct {
+	Reason string
+}
+
+func (e *RunCancelledError) Error() string {
+	if e.Reason == "" {
+		return ErrRunCancelled.Error()
+	}
+	return ErrRunCancelled.Error() + ": " + e.Reason
+}
+
+func (e *RunCancelledError) Is(target error) bool { return target == ErrRunCancelled }
+
+// CancelReason returns the reason carried by a RunCancelledError in err's
+// chain, or "".
+func CancelReason(err error) string {
+	var ce *RunCancelledError
+	if errors.As(err, &ce) {
+		return ce.Reason
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package upal
+
+import (
+	"slices"
+	"time"
+)
+
+// Outbound event types published when a run reaches a terminal status.
+const (
+	// EventTypeRunCompleted is published for runs that finished, including
+	// best_effort runs completed with node errors.
+	EventTypeRunCompleted = "run.completed"
+	// EventTypeRunFailed is published for failed runs and runs whose outputs
+	// broke the workflow's contract.
+	EventTypeRunFailed = "run.failed"
+)
+
+// ValidEventType reports whether t is a known outbound event type.
+func ValidEventType(t string) bool {
+	return t == EventTypeRunCompleted || t == EventTypeRunFailed
+}
+
+// NewEventSubscriptionID returns a unique identifier for an event subscription.
+func NewEventSubscriptionID() string {
+	return GenerateID("esub")
+}
+
+// OutboundEvent is a run lifecycle event delivered to event subscribers.
+type OutboundEvent struct {
+	ID           string         `json:"id"`
+	Type         string         `json:"type"`
+	WorkflowName string         `json:"workflow_name"`
+	RunID        string         `json:"run_id"`
+	Status       RunStatus      `json:"status"`
+	Outputs      map[string]any `json:"outputs,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	OccurredAt   time.Time      `json:"occurred_at"`
+}
+
+// EventSubscription delivers outbound events to a connection. Empty Events
+// and WorkflowName match every event type and workflow. Webhook connections
+// receive the event as JSON; other connection types receive a one-line
+// summary.
+type EventSubscription struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	ConnectionID string       `json:"connection_id"`
+	Events       []string     `json:"events,omitempty"`
+	WorkflowName string       `json:"workflow_name,omitempty"`
+	RetryPolicy  *RetryPolicy `json:"retry_policy,omitempty"`
+	Enabled      bool         `json:"enabled"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// Matches reports whether the subscription wants ev.
+func (s *EventSubscription) Matches(ev OutboundEvent) bool {
+	if !s.Enabled {
+		return false
+	}
+	if s.WorkflowName != "" && s.WorkflowName != ev.WorkflowName {
+		return false
+	}
+	return len(s.Events) == 0 || slices.Contains(s.Events, ev.Type)
+}
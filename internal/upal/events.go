@@ -9,6 +9,8 @@ type WorkflowEvent struct {
 }
 
 // EventRecord is a sequenced workflow event stored in the per-run buffer.
+// Seq starts at 0 and increases by one per event within a run; it is sent as
+// the SSE event id so reconnecting clients can resume after it.
 type EventRecord struct {
 	WorkflowEvent
 	Seq int `json:"seq"`
@@ -18,6 +20,34 @@ type EventRecord struct {
 type RunResult struct {
 	SessionID string
 	State     map[string]any
+	// Outputs holds the results of the workflow's terminal output nodes
+	// (those without outgoing edges), keyed by node ID. State["__output__"]
+	// holds the results of every output node.
+	Outputs map[string]any
+	// NodeErrors holds the errors of nodes that failed under the best_effort
+	// failure mode, keyed by node ID. The run itself still completed.
+	NodeErrors map[string]string
+}
+
+// NodeTestResult is the outcome of executing a single node in isolation.
+type NodeTestResult struct {
+	NodeID      string           `json:"node_id"`
+	Output      any              `json:"output,omitempty"`
+	ToolCalls   []map[string]any `json:"tool_calls,omitempty"`
+	ToolResults []map[string]any `json:"tool_results,omitempty"`
+	Prompt      string           `json:"prompt,omitempty"`
+	DryRun      bool             `json:"dry_run,omitempty"`
+}
+
+// ResolvedNodeTemplate is one node's templates as they would be sent for a
+// given set of inputs, computed without executing the workflow.
+type ResolvedNodeTemplate struct {
+	NodeID       string         `json:"node_id"`
+	Type         NodeType       `json:"type"`
+	Prompt       string         `json:"prompt,omitempty"`
+	SystemPrompt string         `json:"system_prompt,omitempty"`
+	Input        map[string]any `json:"input,omitempty"` // tool node arguments
+	Output       any            `json:"output"`          // value downstream {{refs}} see; a placeholder for agent and tool nodes
 }
 
 // SSE event type constants.
@@ -27,7 +57,11 @@ const (
 	EventToolResult    = "tool_result"
 	EventNodeCompleted = "node_completed"
 	EventNodeSkipped   = "node_skipped"
+	EventNodeFailed    = "node_failed" // best_effort mode only; fail_fast ends the run with EventError
 	EventNodeWaiting   = "node_waiting"
 	EventNodeResumed   = "node_resumed"
+	EventProgress      = "progress"
+	EventLLMCall       = "llm_call" // one per model call made by an agent node
+	EventRunContext    = "run_context" // first event of a run; payload "run_context" holds a *RunContext
 	EventError         = "error"
 )
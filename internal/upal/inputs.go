@@ -0,0 +1,24 @@
+package upal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// UnmarshalInputs decodes JSON run inputs into v like json.Unmarshal, except
+// that numbers held in interface values decode as json.Number instead of
+// float64, so integers beyond 2^53 (external IDs and the like) survive
+// exactly.
+func UnmarshalInputs(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
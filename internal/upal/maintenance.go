@@ -0,0 +1,12 @@
+package upal
+
+import "time"
+
+// MaintenanceState is the server-wide maintenance flag. While Enabled, new
+// runs are rejected and schedule ticks are skipped; reads keep working.
+type MaintenanceState struct {
+	Enabled   bool      `json:"enabled"`
+	Reason    string    `json:"reason,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,122 @@
+package upal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OutputType is a value type in a workflow outputs contract.
+type OutputType string
+
+const (
+	OutputTypeString  OutputType = "string"
+	OutputTypeNumber  OutputType = "number"
+	OutputTypeInteger OutputType = "integer"
+	OutputTypeBoolean OutputType = "boolean"
+	OutputTypeObject  OutputType = "object"
+	OutputTypeArray   OutputType = "array"
+	OutputTypeAny     OutputType = "any"
+)
+
+// CheckOutputs validates a run's final state against wf.Outputs and returns
+// one message per violated key, sorted by key. Every declared key is
+// required. Node outputs are usually text, so a string holding JSON satisfies
+// the non-string types when it decodes to the expected kind.
+func (wf *WorkflowDefinition) CheckOutputs(state map[string]any) []string {
+	keys := make([]string, 0, len(wf.Outputs))
+	for k := range wf.Outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var violations []string
+	for _, key := range keys {
+		want := wf.Outputs[key]
+		v, ok := state[key]
+		if !ok || v == nil {
+			violations = append(violations, fmt.Sprintf("%s: missing", key))
+			continue
+		}
+		if !matchesOutputType(v, want) {
+			violations = append(violations, fmt.Sprintf("%s: expected %s, got %s", key, want, describeOutputValue(v)))
+		}
+	}
+	return violations
+}
+
+// ContractError formats CheckOutputs violations as a single error message.
+func ContractError(violations []string) string {
+	return "outputs contract violated: " + strings.Join(violations, "; ")
+}
+
+func matchesOutputType(v any, want OutputType) bool {
+	if want == OutputTypeAny || want == "" {
+		return true
+	}
+	if s, ok := v.(string); ok {
+		if want == OutputTypeString {
+			return true
+		}
+		var decoded any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s)), &decoded); err != nil {
+			return false
+		}
+		v = decoded
+	}
+	switch want {
+	case OutputTypeString:
+		_, ok := v.(string)
+		return ok
+	case OutputTypeNumber, OutputTypeInteger:
+		f, ok := toFloat(v)
+		return ok && (want == OutputTypeNumber || f == float64(int64(f)))
+	case OutputTypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case OutputTypeObject:
+		_, ok := v.(map[string]any)
+		return ok
+	case OutputTypeArray:
+		_, ok := v.([]any)
+		return ok
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func describeOutputValue(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	if _, ok := toFloat(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -1,6 +1,10 @@
 package upal
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Pipeline orchestrates a sequence of Stages (workflows, approvals, schedules).
 // Settings (sources, schedule, model, workflows, context) live on ContentSession.
@@ -12,8 +16,12 @@ type Pipeline struct {
 	ThumbnailSVG        string     `json:"thumbnail_svg,omitempty"`
 	LastCollectedAt     *time.Time `json:"last_collected_at,omitempty"`
 	PendingSessionCount int        `json:"pending_session_count,omitempty"`
-	CreatedAt           time.Time  `json:"created_at"`
-	UpdatedAt           time.Time  `json:"updated_at"`
+	// CreatedBy and UpdatedBy are the users who created and last edited
+	// the pipeline. They and the timestamps are set by the repository.
+	CreatedBy string    `json:"created_by,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // Stage is a single step in a Pipeline.
@@ -26,6 +34,25 @@ type Stage struct {
 	DependsOn   []string    `json:"depends_on,omitempty"`
 }
 
+// KnownStageTypes is the set of stage types the pipeline runner supports.
+var KnownStageTypes = map[string]bool{
+	"workflow":     true,
+	"approval":     true,
+	"notification": true,
+	"schedule":     true,
+	"trigger":      true,
+	"transform":    true,
+	"collect":      true,
+}
+
+// PipelineIssue is one problem found when validating a pipeline definition.
+// StageID is empty for pipeline-level issues.
+type PipelineIssue struct {
+	StageID string `json:"stage_id,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
 // CollectSource defines a single data source for a collect stage.
 type CollectSource struct {
 	ID          string            `json:"id"`
@@ -53,6 +80,10 @@ type StageConfig struct {
 	// Workflow stage
 	WorkflowName string            `json:"workflow_name,omitempty"`
 	InputMapping map[string]string `json:"input_mapping,omitempty"`
+	// InputsFrom maps the workflow's input node IDs to earlier stage
+	// outputs, each written "<stage_id>.<output_key>". It is applied after
+	// InputMapping and fails the stage when a referenced output is missing.
+	InputsFrom map[string]string `json:"inputs_from,omitempty"`
 
 	// Approval stage
 	Message      string `json:"message,omitempty"`
@@ -65,16 +96,98 @@ type StageConfig struct {
 	ScheduleID string `json:"schedule_id,omitempty"`
 
 	// Notification stage (also shared with Approval for connection_id + message)
-	Subject string `json:"subject,omitempty"` // optional email subject override
+	Subject string              `json:"subject,omitempty"` // optional email subject template
+	Blocks  []NotificationBlock `json:"blocks,omitempty"`  // optional rich layout (Slack); message stays the fallback text
+	Body    string              `json:"body,omitempty"`    // webhook: JSON body template, see NotificationStageExecutor
+
+	// Email (SMTP) options. CC and BCC add to the connection's recipients;
+	// HTML is a body template sent with the message as its plain-text
+	// alternative; Attachments are storage file IDs (e.g. a run artifact's
+	// file_id) and may be templates.
+	CC          []string `json:"cc,omitempty"`
+	BCC         []string `json:"bcc,omitempty"`
+	HTML        string   `json:"html,omitempty"`
+	Attachments []string `json:"attachments,omitempty"`
 
 	// Trigger stage
 	TriggerID string `json:"trigger_id,omitempty"`
 
 	// Transform stage
-	Expression string `json:"expression,omitempty"`
+	Expression string        `json:"expression,omitempty"`
+	Operations []TransformOp `json:"operations,omitempty"` // applied in order after input_mapping and expression
 
 	// Collect stage
 	Sources []CollectSource `json:"sources,omitempty"`
+
+	// Condition is an optional expr-lang expression evaluated before the stage
+	// runs; when falsy the stage is recorded as skipped. It can reference the
+	// previous stage's output keys directly, "prev", or "stages[<id>]".
+	Condition string `json:"condition,omitempty"`
+
+	// Retry re-executes the stage with backoff when it fails, before the
+	// pipeline run is failed. Nil runs the stage once.
+	Retry *RetryPolicy `json:"retry,omitempty"`
+}
+
+// ParseStageOutputRef splits an inputs_from reference "<stage_id>.<output_key>"
+// at its first dot. ok is false when either part is empty.
+func ParseStageOutputRef(ref string) (stageID, key string, ok bool) {
+	stageID, key, _ = strings.Cut(ref, ".")
+	return stageID, key, stageID != "" && key != ""
+}
+
+// TransformOp is one named operation of a transform stage. Which fields are
+// used depends on Op:
+//
+//   - "pick": keep only Keys.
+//   - "rename": move each Mapping key to its value.
+//   - "flatten": collapse nested objects into Separator-joined keys
+//     (default ".").
+//   - "template": render Template against the current output into Target.
+//   - "parse_json": decode the JSON string at Key into Target (default Key).
+type TransformOp struct {
+	Op        string            `json:"op"`
+	Keys      []string          `json:"keys,omitempty"`
+	Mapping   map[string]string `json:"mapping,omitempty"`
+	Separator string            `json:"separator,omitempty"`
+	Template  string            `json:"template,omitempty"`
+	Key       string            `json:"key,omitempty"`
+	Target    string            `json:"target,omitempty"`
+}
+
+// Validate reports whether the operation is known and has the fields it needs.
+func (o TransformOp) Validate() error {
+	switch o.Op {
+	case "pick":
+		if len(o.Keys) == 0 {
+			return fmt.Errorf("pick: keys is required")
+		}
+	case "rename":
+		if len(o.Mapping) == 0 {
+			return fmt.Errorf("rename: mapping is required")
+		}
+	case "flatten":
+	case "template":
+		if o.Target == "" {
+			return fmt.Errorf("template: target is required")
+		}
+	case "parse_json":
+		if o.Key == "" {
+			return fmt.Errorf("parse_json: key is required")
+		}
+	default:
+		return fmt.Errorf("unknown transform op %q", o.Op)
+	}
+	return nil
+}
+
+// NotificationBlock is one element of a structured notification layout.
+// Senders that support rich formatting (Slack Block Kit) render it; others
+// fall back to the plain message.
+type NotificationBlock struct {
+	Type   string   `json:"type"`             // "header" | "section" | "divider"
+	Text   string   `json:"text,omitempty"`   // header: plain text; section: markdown
+	Fields []string `json:"fields,omitempty"` // section: markdown fields shown in two columns
 }
 
 // PipelineContext carries session-level context injected into all child layers.
@@ -161,6 +274,7 @@ type StageResult struct {
 	Status      StageStatus `json:"status"`
 	Output      map[string]any `json:"output,omitempty"`
 	Error       string         `json:"error,omitempty"`
+	Attempts    int            `json:"attempts,omitempty"` // executions including retries
 	StartedAt   time.Time      `json:"started_at"`
 	CompletedAt *time.Time     `json:"completed_at,omitempty"`
 }
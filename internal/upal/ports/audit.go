@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/soochol/upal/internal/upal"
+)
+
+// AuditRecorder appends entries to the audit log. Recording is best-effort
+// and never fails the operation being audited.
+type AuditRecorder interface {
+	Record(ctx context.Context, entry *upal.AuditEntry)
+}
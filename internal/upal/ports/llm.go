@@ -1,6 +1,10 @@
 package ports
 
-import adkmodel "google.golang.org/adk/model"
+import (
+	"context"
+
+	adkmodel "google.golang.org/adk/model"
+)
 
 // LLMResolver resolves a "provider/model" ID string to an LLM instance
 // and the model name to use in LLMRequest. Empty modelID returns the
@@ -8,3 +12,14 @@ import adkmodel "google.golang.org/adk/model"
 type LLMResolver interface {
 	Resolve(modelID string) (adkmodel.LLM, string, error)
 }
+
+// Embedder converts texts into embedding vectors, one per input text, in order.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// Moderator screens texts against a content policy and returns the flagged
+// categories. An empty result means the texts are allowed.
+type Moderator interface {
+	Moderate(ctx context.Context, texts []string) ([]string, error)
+}
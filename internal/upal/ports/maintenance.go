@@ -0,0 +1,6 @@
+package ports
+
+// MaintenanceGate reports whether the server is in maintenance mode.
+type MaintenanceGate interface {
+	InMaintenance() bool
+}
@@ -1,6 +1,10 @@
 package ports
 
-import "github.com/soochol/upal/internal/upal"
+import (
+	"context"
+
+	"github.com/soochol/upal/internal/upal"
+)
 
 // RunManagerPort defines the run event buffering and streaming boundary.
 type RunManagerPort interface {
@@ -9,6 +13,14 @@ type RunManagerPort interface {
 	Complete(runID string, payload map[string]any)
 	Fail(runID string, errMsg string)
 	Subscribe(runID string, startSeq int) (events []upal.EventRecord, notify <-chan struct{}, done bool, donePayload map[string]any, found bool)
+	// Attach records the workflow and cancel function of a registered run
+	// once it starts executing.
+	Attach(runID, workflowName string, cancel context.CancelCauseFunc)
+	// Track registers and attaches a run executed outside RunPublisher,
+	// returning the context to execute it with.
+	Track(ctx context.Context, runID, workflowName string) (context.Context, context.CancelCauseFunc)
+	Active() []upal.ActiveRun
+	Cancel(runID, reason string) bool
 }
 
 // ExecutionRegistryPort defines the execution pause/resume boundary.
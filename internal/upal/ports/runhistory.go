@@ -9,11 +9,26 @@ import (
 // RunHistoryPort defines the contract for recording and querying workflow run history.
 type RunHistoryPort interface {
 	StartRun(ctx context.Context, workflowName string, triggerType, triggerRef string, inputs map[string]any, wfDef *upal.WorkflowDefinition) (*upal.RunRecord, error)
+	StartRerun(ctx context.Context, original *upal.RunRecord, inputs map[string]any, wfDef *upal.WorkflowDefinition) (*upal.RunRecord, error)
+	StartReplay(ctx context.Context, original *upal.RunRecord, deliveryID string, wfDef *upal.WorkflowDefinition) (*upal.RunRecord, error)
 	CompleteRun(ctx context.Context, id string, outputs map[string]any) error
+	CompleteRunWithErrors(ctx context.Context, id string, outputs map[string]any, nodeErrors map[string]string) error
 	FailRun(ctx context.Context, id string, errMsg string) error
+	BlockRun(ctx context.Context, id string, reason string) error
+	CancelRun(ctx context.Context, id, reason string, partial map[string]any) error
 	UpdateRunRetryMeta(ctx context.Context, id string, retryCount int, retryOf *string) error
 	UpdateNodeRun(ctx context.Context, runID string, nodeRun upal.NodeRunRecord) error
+	UpdateRunProgress(ctx context.Context, id string, progress int) error
+	SetRunContext(ctx context.Context, id string, rc *upal.RunContext) error
 	GetRun(ctx context.Context, id string) (*upal.RunRecord, error)
 	ListRuns(ctx context.Context, workflowName string, limit, offset int) ([]*upal.RunRecord, int, error)
 	ListAllRuns(ctx context.Context, limit, offset int, status string) ([]*upal.RunRecord, int, error)
+	ListAllRunsAfter(ctx context.Context, status string, after *upal.RunCursor, limit int) ([]*upal.RunRecord, error)
+	SearchRuns(ctx context.Context, query string, limit int) ([]*upal.RunRecord, error)
+}
+
+// EventPublisher fans run lifecycle events out to external subscribers.
+// Publish must not block on delivery.
+type EventPublisher interface {
+	Publish(ctx context.Context, ev upal.OutboundEvent)
 }
@@ -23,6 +23,13 @@ type ConcurrencyControl interface {
 	Release(workflowName string)
 }
 
+// StartLimiter rate limits run starts. ReserveStart waits until workflowName
+// may start another run and records the start, without taking a concurrency
+// slot; it is for runs that already hold one and start again, like retries.
+type StartLimiter interface {
+	ReserveStart(ctx context.Context, workflowName string) error
+}
+
 // PipelineRunner starts and resumes pipeline stage execution.
 type PipelineRunner interface {
 	Start(ctx context.Context, pipeline *upal.Pipeline, inputs map[string]any) (*upal.PipelineRun, error)
@@ -34,10 +41,12 @@ type PipelineRegistry interface {
 	Get(ctx context.Context, id string) (*upal.Pipeline, error)
 }
 
-// SchedulerPort defines the contract for pipeline-schedule synchronization.
+// SchedulerPort defines the contract for pipeline-schedule synchronization
+// and schedule management.
 type SchedulerPort interface {
 	SyncPipelineSchedules(ctx context.Context, pipeline *upal.Pipeline) error
 	RemovePipelineSchedules(ctx context.Context, pipelineID string) error
 	AddSchedule(ctx context.Context, schedule *upal.Schedule) error
 	RemoveSchedule(ctx context.Context, id string) error
+	CopySchedule(ctx context.Context, id, workflowName string) (*upal.Schedule, error)
 }
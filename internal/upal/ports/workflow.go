@@ -12,3 +12,13 @@ type WorkflowExecutor interface {
 	Validate(wf *upal.WorkflowDefinition) error
 	Run(ctx context.Context, wf *upal.WorkflowDefinition, inputs map[string]any) (<-chan upal.WorkflowEvent, <-chan upal.RunResult, error)
 }
+
+// TemplateResolver previews a workflow's resolved node templates.
+type TemplateResolver interface {
+	ResolveTemplates(wf *upal.WorkflowDefinition, inputs map[string]any) ([]upal.ResolvedNodeTemplate, error)
+}
+
+// NodeRunner executes a single workflow node in isolation.
+type NodeRunner interface {
+	RunNode(ctx context.Context, wf *upal.WorkflowDefinition, nodeID string, values map[string]any, dryRun bool) (*upal.NodeTestResult, error)
+}
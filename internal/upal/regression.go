@@ -0,0 +1,31 @@
+package upal
+
+// Regression comparison modes.
+const (
+	RegressionExact      = "exact"
+	RegressionNormalized = "normalized"
+	RegressionEmbedding  = "embedding"
+)
+
+// RegressionResult compares a fresh run of a workflow against its baseline
+// run. Similarity is the lowest output similarity; the check passes when it
+// reaches Threshold.
+type RegressionResult struct {
+	WorkflowName  string             `json:"workflow_name"`
+	BaselineRunID string             `json:"baseline_run_id"`
+	Mode          string             `json:"mode"`
+	Threshold     float64            `json:"threshold"`
+	Similarity    float64            `json:"similarity"`
+	Passed        bool               `json:"passed"`
+	Error         string             `json:"error,omitempty"` // set when the run itself failed
+	Outputs       []RegressionOutput `json:"outputs"`
+}
+
+// RegressionOutput is the comparison of one output node.
+type RegressionOutput struct {
+	NodeID     string  `json:"node_id"`
+	Baseline   string  `json:"baseline"`
+	Actual     string  `json:"actual"`
+	Similarity float64 `json:"similarity"`
+	Passed     bool    `json:"passed"`
+}
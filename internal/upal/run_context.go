@@ -0,0 +1,42 @@
+package upal
+
+import "time"
+
+// RunContext records the environment a run executed in, so a result can be
+// traced back to the models, providers, tools and configuration that
+// produced it.
+type RunContext struct {
+	CapturedAt    time.Time               `json:"captured_at"`
+	ServerVersion string                  `json:"server_version,omitempty"`
+	Nodes         []NodeContext           `json:"nodes,omitempty"`
+	Providers     map[string]ProviderInfo `json:"providers,omitempty"`
+	// Config is a snapshot of the server configuration with credentials redacted.
+	Config map[string]any `json:"config,omitempty"`
+}
+
+// NodeContext is the effective execution setup of one node. Model is the
+// "provider/model" ID after overrides; ResolvedModel is the model name the
+// provider was actually called with.
+type NodeContext struct {
+	NodeID        string        `json:"node_id"`
+	Type          NodeType      `json:"type"`
+	Model         string        `json:"model,omitempty"`
+	Provider      string        `json:"provider,omitempty"`
+	ResolvedModel string        `json:"resolved_model,omitempty"`
+	ResolveError  string        `json:"resolve_error,omitempty"`
+	Tools         []ToolContext `json:"tools,omitempty"`
+}
+
+// ToolContext identifies a tool available to a node. Tools are compiled into
+// the server, so their version is the run's ServerVersion.
+type ToolContext struct {
+	Name      string `json:"name"`
+	Native    bool   `json:"native,omitempty"` // executed by the LLM provider
+	Available bool   `json:"available"`        // registered on this server
+}
+
+// ProviderInfo describes a configured LLM provider without its credentials.
+type ProviderInfo struct {
+	Type string `json:"type"`
+	URL  string `json:"url,omitempty"`
+}
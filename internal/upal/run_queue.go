@@ -0,0 +1,20 @@
+package upal
+
+import "time"
+
+// QueuedRun is a scheduled tick waiting in the persistent run queue. A worker
+// claims it by taking a lease; if the worker dies the lease expires and
+// another worker claims it again, so every tick runs at least once.
+type QueuedRun struct {
+	ID             string     `json:"id"`
+	ScheduleID     string     `json:"schedule_id"`
+	EnqueuedAt     time.Time  `json:"enqueued_at"`
+	Attempts       int        `json:"attempts"` // claims so far, including the current one
+	LeaseOwner     string     `json:"lease_owner,omitempty"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+}
+
+// Claimable reports whether the item is unleased or its lease has expired at now.
+func (q *QueuedRun) Claimable(now time.Time) bool {
+	return q.LeaseExpiresAt == nil || q.LeaseExpiresAt.Before(now)
+}
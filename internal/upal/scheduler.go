@@ -1,6 +1,10 @@
 package upal
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // RunStatus represents the lifecycle state of a workflow run.
 type RunStatus string
@@ -12,6 +16,15 @@ const (
 	RunStatusFailed    RunStatus = "failed"
 	RunStatusCancelled RunStatus = "cancelled"
 	RunStatusRetrying  RunStatus = "retrying"
+	// RunStatusContractViolation marks a run that finished but whose final
+	// state did not satisfy the workflow's outputs contract.
+	RunStatusContractViolation RunStatus = "contract_violation"
+	// RunStatusBlockedModeration marks a run whose inputs were rejected by
+	// the moderation pre-check, so no node executed.
+	RunStatusBlockedModeration RunStatus = "blocked_moderation"
+	// RunStatusCompletedWithErrors marks a best_effort run that finished
+	// although some nodes failed.
+	RunStatusCompletedWithErrors RunStatus = "completed_with_errors"
 )
 
 // NodeRunStatus represents the execution state of a single node within a run record.
@@ -38,10 +51,13 @@ type RunRecord struct {
 	TriggerType  string              `json:"trigger_type"`                 // "manual" | "cron" | "webhook"
 	TriggerRef   string              `json:"trigger_ref"`                  // schedule ID or trigger ID
 	Status       RunStatus           `json:"status"`
+	Progress     int                 `json:"progress"`                     // percent of nodes finished, 0–100
 	Inputs       map[string]any      `json:"inputs"`
 	Outputs      map[string]any      `json:"outputs,omitempty"`
 	Error        *string             `json:"error,omitempty"`
+	CancelReason string              `json:"cancel_reason,omitempty"`      // why a cancelled run was stopped
 	RetryOf      *string             `json:"retry_of,omitempty"`           // original run ID if this is a retry
+	RerunOf      *string             `json:"rerun_of,omitempty"`           // source run ID if this is a rerun with modified inputs
 	RetryCount   int                 `json:"retry_count"`
 	SessionID    *string             `json:"session_id,omitempty"`         // set when run was triggered from a ContentSession
 	CreatedAt    time.Time           `json:"created_at"`
@@ -49,6 +65,40 @@ type RunRecord struct {
 	CompletedAt  *time.Time          `json:"completed_at,omitempty"`
 	NodeRuns     []NodeRunRecord     `json:"node_runs,omitempty"`
 	Usage        *TokenUsage         `json:"usage,omitempty"`
+	Artifacts    []RunArtifact       `json:"artifacts,omitempty"`
+	Context      *RunContext         `json:"context,omitempty"` // models, providers and config the run executed with
+
+	// UserID is the user who started the run. Work that finishes the run
+	// from a background context (storing it, publishing its events) acts
+	// as this user rather than the default one.
+	UserID string `json:"-"`
+}
+
+// RunCursor marks a position in the run list, which is ordered newest first
+// by (CreatedAt, ID). Listing after a cursor returns the runs that sort after
+// it, so pages stay stable while new runs are being recorded.
+type RunCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// ActiveRun is a run that is currently executing on this server.
+type ActiveRun struct {
+	RunID        string    `json:"run_id"`
+	WorkflowName string    `json:"workflow_name"`
+	StartedAt    time.Time `json:"started_at"`
+	CurrentNode  string    `json:"current_node,omitempty"` // most recently started node
+	TriggerType  string    `json:"trigger_type,omitempty"`
+	TriggerRef   string    `json:"trigger_ref,omitempty"`
+}
+
+// RunArtifact references a run output persisted as a file in Storage.
+type RunArtifact struct {
+	Name        string `json:"name"`
+	NodeID      string `json:"node_id"`
+	FileID      string `json:"file_id"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
 }
 
 // NodeRunRecord tracks execution of a single node within a run.
@@ -60,6 +110,9 @@ type NodeRunRecord struct {
 	Error       *string       `json:"error,omitempty"`
 	RetryCount  int           `json:"retry_count"`
 	Usage       *TokenUsage   `json:"usage,omitempty"`
+	// Output is the node's final state value, kept as a checkpoint so a
+	// failed run can be resumed without re-executing this node.
+	Output any `json:"output,omitempty"`
 }
 
 // RetryPolicy defines how failed runs should be retried.
@@ -70,6 +123,24 @@ type RetryPolicy struct {
 	BackoffFactor float64       `json:"backoff_factor" yaml:"backoff_factor"`
 }
 
+// MaxRetryPolicyRetries bounds RetryPolicy.MaxRetries.
+const MaxRetryPolicyRetries = 100
+
+// Validate reports whether the policy's delays and retry count are usable.
+func (p RetryPolicy) Validate() error {
+	switch {
+	case p.MaxRetries < 0 || p.MaxRetries > MaxRetryPolicyRetries:
+		return fmt.Errorf("retry policy: max_retries must be between 0 and %d", MaxRetryPolicyRetries)
+	case p.InitialDelay < 0:
+		return fmt.Errorf("retry policy: initial_delay must not be negative")
+	case p.MaxDelay < 0:
+		return fmt.Errorf("retry policy: max_delay must not be negative")
+	case p.BackoffFactor < 1:
+		return fmt.Errorf("retry policy: backoff_factor must be at least 1")
+	}
+	return nil
+}
+
 // DefaultRetryPolicy returns a sensible default retry policy.
 func DefaultRetryPolicy() RetryPolicy {
 	return RetryPolicy{
@@ -84,6 +155,13 @@ func DefaultRetryPolicy() RetryPolicy {
 type ConcurrencyLimits struct {
 	GlobalMax   int `json:"global_max"   yaml:"global_max"`
 	PerWorkflow int `json:"per_workflow" yaml:"per_workflow"`
+	// GlobalStartsPerWindow and PerWorkflowStartsPerWindow cap how many runs
+	// may start within StartWindow (default one minute) even when in-flight
+	// capacity is free, e.g. to respect a provider's requests-per-minute
+	// limit. Zero disables the cap.
+	GlobalStartsPerWindow      int           `json:"global_starts_per_window,omitempty"       yaml:"global_starts_per_window"`
+	PerWorkflowStartsPerWindow int           `json:"per_workflow_starts_per_window,omitempty" yaml:"per_workflow_starts_per_window"`
+	StartWindow                time.Duration `json:"start_window,omitempty"                   yaml:"start_window"`
 }
 
 // DefaultConcurrencyLimits returns sensible defaults.
@@ -108,6 +186,213 @@ type Schedule struct {
 	LastRunAt    *time.Time     `json:"last_run_at,omitempty"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
+	// ConsecutiveFailures counts back-to-back runs that failed because the
+	// target workflow was missing; PausedReason explains an automatic pause.
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	PausedReason        string `json:"paused_reason,omitempty"`
+	// Blackout lists windows during which ticks are skipped rather than run.
+	Blackout []BlackoutWindow `json:"blackout,omitempty"`
+	// ActiveHours, when set, restricts ticks to a recurring allow-window;
+	// ticks outside it are skipped.
+	ActiveHours *ActiveHours `json:"active_hours,omitempty"`
+	// LastOutcome records what happened at the most recent tick.
+	LastOutcome ScheduleOutcome `json:"last_outcome,omitempty"`
+	// CronPrecision is derived from CronExpr's field count and is not stored.
+	CronPrecision string `json:"cron_precision,omitempty"` // "seconds" | "minutes"
+}
+
+// Cron precisions reported on Schedule.CronPrecision.
+const (
+	CronPrecisionSeconds = "seconds" // 6-field expression with a leading seconds field
+	CronPrecisionMinutes = "minutes" // standard 5-field expression
+)
+
+// ScheduleOutcome describes the result of a single schedule tick.
+type ScheduleOutcome string
+
+const (
+	ScheduleOutcomeExecuted           ScheduleOutcome = "executed"
+	ScheduleOutcomeSkippedBlackout    ScheduleOutcome = "skipped_blackout"
+	ScheduleOutcomeSkippedMaintenance ScheduleOutcome = "skipped_maintenance"
+	ScheduleOutcomeSkippedInactive    ScheduleOutcome = "skipped_inactive"
+)
+
+// BlackoutWindow is a period during which a schedule must not fire. Set
+// Start/End for a one-off range, or DailyStart/DailyEnd ("HH:MM") for a
+// window recurring every day; a daily window whose end precedes its start
+// wraps past midnight. Daily windows are evaluated in Timezone, defaulting to
+// the schedule's timezone.
+type BlackoutWindow struct {
+	Start      *time.Time `json:"start,omitempty"`
+	End        *time.Time `json:"end,omitempty"`
+	DailyStart string     `json:"daily_start,omitempty"`
+	DailyEnd   string     `json:"daily_end,omitempty"`
+	Timezone   string     `json:"timezone,omitempty"`
+}
+
+// Validate reports whether the window is well-formed.
+func (b BlackoutWindow) Validate() error {
+	oneOff := b.Start != nil || b.End != nil
+	daily := b.DailyStart != "" || b.DailyEnd != ""
+	switch {
+	case oneOff && daily:
+		return fmt.Errorf("blackout window: set either start/end or daily_start/daily_end, not both")
+	case oneOff:
+		if b.Start == nil || b.End == nil || !b.End.After(*b.Start) {
+			return fmt.Errorf("blackout window: end must be after start")
+		}
+	case daily:
+		if _, err := parseClock(b.DailyStart); err != nil {
+			return fmt.Errorf("blackout window: daily_start: %w", err)
+		}
+		if _, err := parseClock(b.DailyEnd); err != nil {
+			return fmt.Errorf("blackout window: daily_end: %w", err)
+		}
+		if b.Timezone != "" {
+			if _, err := time.LoadLocation(b.Timezone); err != nil {
+				return fmt.Errorf("blackout window: invalid timezone %q: %w", b.Timezone, err)
+			}
+		}
+	default:
+		return fmt.Errorf("blackout window: empty window")
+	}
+	return nil
+}
+
+// Contains reports whether t falls inside the window. Start is inclusive and
+// End exclusive. defaultTZ is used for daily windows without a Timezone.
+func (b BlackoutWindow) Contains(t time.Time, defaultTZ string) bool {
+	if b.Start != nil && b.End != nil {
+		return !t.Before(*b.Start) && t.Before(*b.End)
+	}
+	start, err1 := parseClock(b.DailyStart)
+	end, err2 := parseClock(b.DailyEnd)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	tz := b.Timezone
+	if tz == "" {
+		tz = defaultTZ
+	}
+	if loc, err := time.LoadLocation(tz); err == nil {
+		t = t.In(loc)
+	}
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// InBlackout reports whether t falls inside any of the schedule's blackout windows.
+func (s *Schedule) InBlackout(t time.Time) bool {
+	for _, b := range s.Blackout {
+		if b.Contains(t, s.Timezone) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveHours is a recurring window during which a schedule may fire. Start
+// and End are "HH:MM"; an End before Start wraps past midnight and the
+// after-midnight part belongs to the day the window opened. Days lists
+// weekdays ("mon".."sun") the window opens on, defaulting to every day. The
+// window is evaluated in Timezone, defaulting to the schedule's timezone.
+type ActiveHours struct {
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Days     []string `json:"days,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Validate reports whether the window is well-formed.
+func (a ActiveHours) Validate() error {
+	start, err := parseClock(a.Start)
+	if err != nil {
+		return fmt.Errorf("active hours: start: %w", err)
+	}
+	end, err := parseClock(a.End)
+	if err != nil {
+		return fmt.Errorf("active hours: end: %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("active hours: start and end must differ")
+	}
+	for _, d := range a.Days {
+		if _, ok := weekdayNames[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("active hours: unknown day %q", d)
+		}
+	}
+	if a.Timezone != "" {
+		if _, err := time.LoadLocation(a.Timezone); err != nil {
+			return fmt.Errorf("active hours: invalid timezone %q: %w", a.Timezone, err)
+		}
+	}
+	return nil
+}
+
+// Contains reports whether t falls inside the window. Start is inclusive and
+// End exclusive. defaultTZ is used when the window has no Timezone.
+func (a ActiveHours) Contains(t time.Time, defaultTZ string) bool {
+	start, err1 := parseClock(a.Start)
+	end, err2 := parseClock(a.End)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	tz := a.Timezone
+	if tz == "" {
+		tz = defaultTZ
+	}
+	if loc, err := time.LoadLocation(tz); err == nil {
+		t = t.In(loc)
+	}
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	day := t.Weekday()
+	switch {
+	case start < end:
+		if now < start || now >= end {
+			return false
+		}
+	case now >= start:
+	case now < end:
+		day = (day + 6) % 7 // after midnight: the window opened yesterday
+	default:
+		return false
+	}
+	return a.onDay(day)
+}
+
+func (a ActiveHours) onDay(day time.Weekday) bool {
+	if len(a.Days) == 0 {
+		return true
+	}
+	for _, d := range a.Days {
+		if weekdayNames[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether t falls inside the schedule's active hours.
+// Schedules without active hours are always active.
+func (s *Schedule) IsActive(t time.Time) bool {
+	return s.ActiveHours == nil || s.ActiveHours.Contains(t, s.Timezone)
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
 }
 
 // TriggerType identifies how a workflow execution was initiated.
@@ -134,4 +419,34 @@ type Trigger struct {
 type TriggerConfig struct {
 	Secret       string            `json:"secret,omitempty"`
 	InputMapping map[string]string `json:"input_mapping,omitempty"` // JSONPath → input key
+	// Sync runs the workflow inline and returns its output in the webhook
+	// response instead of answering 202 and running in the background.
+	Sync           bool `json:"sync,omitempty"`
+	TimeoutSeconds int  `json:"timeout_seconds,omitempty"` // sync mode only; defaults to 30s
+	// PayloadFormat forces how the webhook body is parsed: "json" or "form"
+	// (urlencoded or multipart). Empty detects it from the Content-Type.
+	PayloadFormat string `json:"payload_format,omitempty"`
+	// ChallengeField names a payload field that, when present, is echoed back
+	// as {"<field>": value} without starting a run (Slack URL verification
+	// uses "challenge").
+	ChallengeField string `json:"challenge_field,omitempty"`
+	// ResponseTemplate replaces the default accepted response for async
+	// webhooks. {{status}}, {{trigger}} and top-level payload fields are
+	// substituted.
+	ResponseTemplate string `json:"response_template,omitempty"`
+	// SignatureHeader names the request header carrying the HMAC signature,
+	// e.g. "X-Hub-Signature-256". Defaults to DefaultSignatureHeader.
+	SignatureHeader string `json:"signature_header,omitempty"`
+}
+
+// DefaultSignatureHeader is the webhook signature header used when a trigger
+// does not configure one.
+const DefaultSignatureHeader = "X-Webhook-Signature"
+
+// RunChange is a run record as of a write to run history, published to live
+// run-list subscribers.
+type RunChange struct {
+	Type   string     `json:"type"` // "created" | "updated"
+	Run    *RunRecord `json:"run"`
+	UserID string     `json:"-"` // owner of the run, for scoping subscribers
 }
@@ -0,0 +1,19 @@
+package upal
+
+// SearchResultType identifies the kind of object a search result refers to.
+type SearchResultType string
+
+const (
+	SearchTypeWorkflow SearchResultType = "workflow"
+	SearchTypePipeline SearchResultType = "pipeline"
+	SearchTypeRun      SearchResultType = "run"
+)
+
+// SearchResult is a single ranked hit returned by the search endpoint.
+type SearchResult struct {
+	Type    SearchResultType `json:"type"`
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Snippet string           `json:"snippet,omitempty"`
+	Score   float64          `json:"score"`
+}
@@ -0,0 +1,17 @@
+package upal
+
+import "time"
+
+// OpenAPIImport is an OpenAPI document imported as a namespace of HTTP
+// tools. The document is stored as fetched so the tools can be registered
+// again when the server restarts, without reaching the spec's URL. UserID is
+// the importing user: only they may replace or delete the namespace, and
+// ConnectionID is resolved as them whoever runs the tools.
+type OpenAPIImport struct {
+	Namespace    string    `json:"namespace"`
+	UserID       string    `json:"-"`
+	Spec         string    `json:"spec"`
+	BaseURL      string    `json:"base_url,omitempty"`
+	ConnectionID string    `json:"connection_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
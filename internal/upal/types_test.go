@@ -27,3 +27,44 @@ func TestWorkflowDefinitionJSON(t *testing.T) {
 		t.Fatalf("roundtrip mismatch: %+v", got)
 	}
 }
+
+func TestValidateWorkflow(t *testing.T) {
+	wf := &WorkflowDefinition{
+		FailureMode: "retry_forever",
+		Nodes: []NodeDefinition{
+			{ID: "a", Type: NodeTypeInput},
+			{ID: "a", Type: NodeTypeAgent, Config: map[string]any{"model": ""}},
+			{Type: NodeTypeOutput},
+		},
+		Edges: []EdgeDefinition{{From: "a"}},
+	}
+	var got []string
+	for _, issue := range ValidateWorkflow(wf) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		"name: required",
+		`failure_mode: unknown failure mode "retry_forever" (want fail_fast or best_effort)`,
+		`nodes[1].id: duplicate node id "a"`,
+		"nodes[1].config.model: required",
+		"nodes[2].id: required",
+		"edges[0].to: required",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("issues: got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("issue %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	valid := &WorkflowDefinition{
+		Name:  "ok",
+		Nodes: []NodeDefinition{{ID: "in", Type: NodeTypeInput}, {ID: "out", Type: NodeTypeOutput}},
+		Edges: []EdgeDefinition{{From: "in", To: "out"}},
+	}
+	if issues := ValidateWorkflow(valid); issues != nil {
+		t.Errorf("valid workflow: got %v", issues)
+	}
+}
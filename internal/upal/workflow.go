@@ -1,5 +1,7 @@
 package upal
 
+import "time"
+
 type NodeType string
 
 const (
@@ -9,18 +11,61 @@ const (
 	NodeTypeOutput   NodeType = "output"
 	NodeTypeAsset    NodeType = "asset"
 	NodeTypeTool     NodeType = "tool"
+	NodeTypeSummary  NodeType = "summary"
 )
 
 type WorkflowDefinition struct {
 	Name         string            `json:"name" yaml:"name"`
 	Description  string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags         []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
 	Version      int               `json:"version" yaml:"version"`
 	Nodes        []NodeDefinition  `json:"nodes" yaml:"nodes"`
 	Edges        []EdgeDefinition  `json:"edges" yaml:"edges"`
 	Groups       []GroupDefinition `json:"groups,omitempty" yaml:"groups,omitempty"`
 	ThumbnailSVG string            `json:"thumbnail_svg,omitempty" yaml:"thumbnail_svg,omitempty"`
+
+	// DedupWindowSeconds, when positive, coalesces runs with identical inputs
+	// started within this many seconds of each other into a single execution.
+	DedupWindowSeconds int `json:"dedup_window_seconds,omitempty" yaml:"dedup_window_seconds,omitempty"`
+
+	// Outputs is the workflow's outputs contract: final state keys mapped to
+	// their expected type. See CheckOutputs.
+	Outputs map[string]OutputType `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+
+	// Moderate screens run inputs with the server's moderation provider
+	// before any node executes; flagged runs end as blocked_moderation.
+	Moderate bool `json:"moderate,omitempty" yaml:"moderate,omitempty"`
+
+	// BaselineRunID is the run regression checks compare new runs against.
+	BaselineRunID string `json:"baseline_run_id,omitempty" yaml:"baseline_run_id,omitempty"`
+
+	// FailureMode decides what a node failure does to the run: fail_fast
+	// (default) stops it, best_effort records the error and carries on.
+	FailureMode FailureMode `json:"failure_mode,omitempty" yaml:"failure_mode,omitempty"`
+
+	// WebhookSecret signs ad-hoc runs posted to /api/workflows/{name}/run
+	// without a trigger. It is kept out of JSON and YAML so API responses,
+	// run snapshots and exports never carry it; the database stores it in
+	// its own column.
+	WebhookSecret string `json:"-" yaml:"-"`
+
+	// Ownership metadata, set by the repository on create and update from
+	// the request's user; client-supplied values are ignored. Not exported
+	// to YAML.
+	CreatedBy string    `json:"created_by,omitempty" yaml:"-"`
+	UpdatedBy string    `json:"updated_by,omitempty" yaml:"-"`
+	CreatedAt time.Time `json:"created_at,omitzero" yaml:"-"`
+	UpdatedAt time.Time `json:"updated_at,omitzero" yaml:"-"`
 }
 
+// FailureMode is a workflow's policy for node failures.
+type FailureMode string
+
+const (
+	FailureModeFailFast   FailureMode = "fail_fast"
+	FailureModeBestEffort FailureMode = "best_effort"
+)
+
 type NodeDefinition struct {
 	ID     string         `json:"id" yaml:"id"`
 	Type   NodeType       `json:"type" yaml:"type"`
@@ -51,6 +96,9 @@ const (
 	NodeStatusCompleted NodeStatus = "completed"
 	NodeStatusFailed    NodeStatus = "failed"
 	NodeStatusSkipped   NodeStatus = "skipped"
+	// NodeStatusRestored marks a node whose output was reused from a
+	// checkpoint of an earlier run instead of being executed.
+	NodeStatusRestored NodeStatus = "restored"
 )
 
 type EdgeDefinition struct {
@@ -71,3 +119,38 @@ type GroupDefinition struct {
 	Label string `json:"label" yaml:"label"`
 	Color string `json:"color,omitempty" yaml:"color,omitempty"`
 }
+
+// ApplyModelOverrides returns a copy of wf whose agent nodes use the
+// overridden models. wf itself is not modified.
+func ApplyModelOverrides(wf *WorkflowDefinition, o ModelOverrides) *WorkflowDefinition {
+	if o.IsZero() {
+		return wf
+	}
+	cp := *wf
+	cp.Nodes = make([]NodeDefinition, len(wf.Nodes))
+	for i, n := range wf.Nodes {
+		model := o.Nodes[n.ID]
+		if model == "" && n.Type == NodeTypeAgent {
+			model = o.Default
+		}
+		if model != "" {
+			cfg := make(map[string]any, len(n.Config)+1)
+			for k, v := range n.Config {
+				cfg[k] = v
+			}
+			cfg["model"] = model
+			n.Config = cfg
+		}
+		cp.Nodes[i] = n
+	}
+	return &cp
+}
+
+// WorkflowSuggestion is an existing workflow ranked by similarity to a task
+// description. Method is "embedding" or "keyword".
+type WorkflowSuggestion struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Score       float64 `json:"score"`
+	Method      string  `json:"method"`
+}
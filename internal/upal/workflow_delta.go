@@ -0,0 +1,190 @@
+package upal
+
+import (
+	"fmt"
+	"maps"
+)
+
+// WorkflowNodeDelta describes a single node change operation.
+type WorkflowNodeDelta struct {
+	Op     string          `json:"op"`                // "add", "update", "remove", "set_config"
+	Node   *NodeDefinition `json:"node,omitempty"`    // for "add" and "update"
+	NodeID string          `json:"node_id,omitempty"` // for "remove" and "set_config"
+	Config map[string]any  `json:"config,omitempty"`  // for "set_config"; a null value deletes the field
+}
+
+// WorkflowEdgeDelta describes a single edge change operation. Edges are
+// identified by their (from, to) pair.
+type WorkflowEdgeDelta struct {
+	Op   string         `json:"op"` // "add", "remove"
+	Edge EdgeDefinition `json:"edge"`
+}
+
+// WorkflowDelta is a partial update to a WorkflowDefinition. Nodes and edges
+// not referenced by a change are kept verbatim.
+type WorkflowDelta struct {
+	Description string              `json:"description,omitempty"`
+	NodeChanges []WorkflowNodeDelta `json:"node_changes,omitempty"`
+	EdgeChanges []WorkflowEdgeDelta `json:"edge_changes,omitempty"`
+}
+
+// ApplyWorkflowDelta merges delta into a copy of existing. Node changes are
+// applied before edge changes; removing a node also removes its edges. The
+// delta is rejected if it references unknown nodes or edges, or if it would
+// disconnect an input or output node that was previously wired, or remove the
+// last one of either.
+func ApplyWorkflowDelta(existing *WorkflowDefinition, delta *WorkflowDelta) (*WorkflowDefinition, error) {
+	result := *existing
+	if delta.Description != "" {
+		result.Description = delta.Description
+	}
+
+	nodes := make([]NodeDefinition, len(existing.Nodes))
+	for i, n := range existing.Nodes {
+		n.Config = maps.Clone(n.Config)
+		nodes[i] = n
+	}
+	indexOf := func(id string) int {
+		for i, n := range nodes {
+			if n.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	edges := append([]EdgeDefinition(nil), existing.Edges...)
+
+	for i, change := range delta.NodeChanges {
+		switch change.Op {
+		case "add":
+			if change.Node == nil || change.Node.ID == "" {
+				return nil, fmt.Errorf("node_changes[%d]: add requires a node with an id", i)
+			}
+			if indexOf(change.Node.ID) >= 0 {
+				return nil, fmt.Errorf("node_changes[%d]: node %q already exists", i, change.Node.ID)
+			}
+			nodes = append(nodes, *change.Node)
+		case "update":
+			if change.Node == nil {
+				return nil, fmt.Errorf("node_changes[%d]: update requires a node", i)
+			}
+			idx := indexOf(change.Node.ID)
+			if idx < 0 {
+				return nil, fmt.Errorf("node_changes[%d]: node %q not found", i, change.Node.ID)
+			}
+			nodes[idx] = *change.Node
+		case "remove":
+			idx := indexOf(change.NodeID)
+			if idx < 0 {
+				return nil, fmt.Errorf("node_changes[%d]: node %q not found", i, change.NodeID)
+			}
+			nodes = append(nodes[:idx], nodes[idx+1:]...)
+			kept := edges[:0]
+			for _, e := range edges {
+				if e.From != change.NodeID && e.To != change.NodeID {
+					kept = append(kept, e)
+				}
+			}
+			edges = kept
+		case "set_config":
+			idx := indexOf(change.NodeID)
+			if idx < 0 {
+				return nil, fmt.Errorf("node_changes[%d]: node %q not found", i, change.NodeID)
+			}
+			if nodes[idx].Config == nil {
+				nodes[idx].Config = make(map[string]any, len(change.Config))
+			}
+			for k, v := range change.Config {
+				if v == nil {
+					delete(nodes[idx].Config, k)
+				} else {
+					nodes[idx].Config[k] = v
+				}
+			}
+		default:
+			return nil, fmt.Errorf("node_changes[%d]: unknown op %q", i, change.Op)
+		}
+	}
+
+	for i, change := range delta.EdgeChanges {
+		e := change.Edge
+		pos := -1
+		for j, existing := range edges {
+			if existing.From == e.From && existing.To == e.To {
+				pos = j
+				break
+			}
+		}
+		switch change.Op {
+		case "add":
+			if indexOf(e.From) < 0 || indexOf(e.To) < 0 {
+				return nil, fmt.Errorf("edge_changes[%d]: edge %s -> %s references an unknown node", i, e.From, e.To)
+			}
+			if pos >= 0 {
+				return nil, fmt.Errorf("edge_changes[%d]: edge %s -> %s already exists", i, e.From, e.To)
+			}
+			edges = append(edges, e)
+		case "remove":
+			if pos < 0 {
+				return nil, fmt.Errorf("edge_changes[%d]: edge %s -> %s not found", i, e.From, e.To)
+			}
+			edges = append(edges[:pos], edges[pos+1:]...)
+		default:
+			return nil, fmt.Errorf("edge_changes[%d]: unknown op %q", i, change.Op)
+		}
+	}
+
+	result.Nodes = nodes
+	result.Edges = edges
+	if err := checkIONodesWired(existing, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// checkIONodesWired rejects an edit that leaves the workflow without input or
+// output nodes it used to have, or that disconnects one that was connected.
+func checkIONodesWired(before, after *WorkflowDefinition) error {
+	for _, t := range []NodeType{NodeTypeInput, NodeTypeOutput} {
+		if countNodes(before, t) > 0 && countNodes(after, t) == 0 {
+			return fmt.Errorf("delta would remove every %s node", t)
+		}
+	}
+	wasWired := ioWiring(before)
+	isWired := ioWiring(after)
+	for _, n := range after.Nodes {
+		if wasWired[n.ID] && !isWired[n.ID] {
+			return fmt.Errorf("delta would orphan %s node %q", n.Type, n.ID)
+		}
+	}
+	return nil
+}
+
+func countNodes(wf *WorkflowDefinition, t NodeType) int {
+	n := 0
+	for _, node := range wf.Nodes {
+		if node.Type == t {
+			n++
+		}
+	}
+	return n
+}
+
+// ioWiring reports which input nodes have an outgoing edge and which output
+// nodes have an incoming edge.
+func ioWiring(wf *WorkflowDefinition) map[string]bool {
+	types := make(map[string]NodeType, len(wf.Nodes))
+	for _, n := range wf.Nodes {
+		types[n.ID] = n.Type
+	}
+	wired := make(map[string]bool)
+	for _, e := range wf.Edges {
+		if types[e.From] == NodeTypeInput {
+			wired[e.From] = true
+		}
+		if types[e.To] == NodeTypeOutput {
+			wired[e.To] = true
+		}
+	}
+	return wired
+}
@@ -0,0 +1,54 @@
+package upal
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// WorkflowLimits bounds the size of workflow definitions accepted by the
+// server. A zero value disables that limit.
+type WorkflowLimits struct {
+	MaxNodes       int `json:"max_nodes"        yaml:"max_nodes"`
+	MaxEdges       int `json:"max_edges"        yaml:"max_edges"`
+	MaxPromptChars int `json:"max_prompt_chars" yaml:"max_prompt_chars"` // per prompt or system_prompt
+}
+
+// WorkflowLimitError reports the first limit a workflow exceeds.
+type WorkflowLimitError struct {
+	Limit  string `json:"limit"` // "max_nodes" | "max_edges" | "max_prompt_chars"
+	Max    int    `json:"max"`
+	Actual int    `json:"actual"`
+	NodeID string `json:"node_id,omitempty"`
+	Field  string `json:"field,omitempty"`
+}
+
+func (e *WorkflowLimitError) Error() string {
+	switch e.Limit {
+	case "max_nodes":
+		return fmt.Sprintf("workflow has %d nodes, exceeding max_nodes (%d)", e.Actual, e.Max)
+	case "max_edges":
+		return fmt.Sprintf("workflow has %d edges, exceeding max_edges (%d)", e.Actual, e.Max)
+	}
+	return fmt.Sprintf("node %q %s has %d characters, exceeding %s (%d)", e.NodeID, e.Field, e.Actual, e.Limit, e.Max)
+}
+
+// Check returns a *WorkflowLimitError for the first limit wf exceeds, or nil.
+func (l WorkflowLimits) Check(wf *WorkflowDefinition) error {
+	if l.MaxNodes > 0 && len(wf.Nodes) > l.MaxNodes {
+		return &WorkflowLimitError{Limit: "max_nodes", Max: l.MaxNodes, Actual: len(wf.Nodes)}
+	}
+	if l.MaxEdges > 0 && len(wf.Edges) > l.MaxEdges {
+		return &WorkflowLimitError{Limit: "max_edges", Max: l.MaxEdges, Actual: len(wf.Edges)}
+	}
+	if l.MaxPromptChars > 0 {
+		for _, n := range wf.Nodes {
+			for _, field := range []string{"prompt", "system_prompt"} {
+				text, _ := n.Config[field].(string)
+				if count := utf8.RuneCountInString(text); count > l.MaxPromptChars {
+					return &WorkflowLimitError{Limit: "max_prompt_chars", Max: l.MaxPromptChars, Actual: count, NodeID: n.ID, Field: field}
+				}
+			}
+		}
+	}
+	return nil
+}
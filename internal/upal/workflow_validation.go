@@ -0,0 +1,82 @@
+package upal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationIssue is one problem found in a workflow definition. Path
+// locates the offending field, e.g. "nodes[1].config.model".
+type ValidationIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (i ValidationIssue) String() string { return i.Path + ": " + i.Message }
+
+// ValidationError reports every issue found in a workflow definition.
+type ValidationError struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// Error summarizes the issues on one line.
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		parts[i] = issue.String()
+	}
+	return "invalid workflow: " + strings.Join(parts, "; ")
+}
+
+// ValidateWorkflow checks the structure of a workflow definition and returns
+// every issue found, in document order, or nil when it is valid. Agent nodes
+// must select a model and edges must connect declared nodes; whether the
+// model or a tool is actually available is left to the caller.
+func ValidateWorkflow(wf *WorkflowDefinition) []ValidationIssue {
+	var issues []ValidationIssue
+	add := func(path, format string, args ...any) {
+		issues = append(issues, ValidationIssue{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if wf.Name == "" {
+		add("name", "required")
+	}
+	switch wf.FailureMode {
+	case "", FailureModeFailFast, FailureModeBestEffort:
+	default:
+		add("failure_mode", "unknown failure mode %q (want %s or %s)", wf.FailureMode, FailureModeFailFast, FailureModeBestEffort)
+	}
+
+	nodeIDs := make(map[string]bool, len(wf.Nodes))
+	for i, n := range wf.Nodes {
+		path := fmt.Sprintf("nodes[%d]", i)
+		switch {
+		case n.ID == "":
+			add(path+".id", "required")
+		case nodeIDs[n.ID]:
+			add(path+".id", "duplicate node id %q", n.ID)
+		}
+		nodeIDs[n.ID] = true
+		if n.Type == "" {
+			add(path+".type", "required")
+		}
+		if n.Type == NodeTypeAgent {
+			if model, _ := n.Config["model"].(string); model == "" {
+				add(path+".config.model", "required")
+			}
+		}
+	}
+
+	for i, e := range wf.Edges {
+		path := fmt.Sprintf("edges[%d]", i)
+		for _, end := range []struct{ field, id string }{{"from", e.From}, {"to", e.To}} {
+			switch {
+			case end.id == "":
+				add(path+"."+end.field, "required")
+			case !nodeIDs[end.id]:
+				add(path+"."+end.field, "unknown node %q", end.id)
+			}
+		}
+	}
+	return issues
+}
@@ -1,4 +1,4 @@
-export type ConnectionType = 'telegram' | 'slack' | 'http' | 'smtp'
+export type ConnectionType = 'telegram' | 'slack' | 'http' | 'smtp' | 'webhook' | 's3'
 
 export type Connection = {
   id: string
@@ -1,4 +1,4 @@
-import { Inbox, Bot, ArrowRightFromLine, FileBox, Wrench, Zap } from 'lucide-react'
+import { Inbox, Bot, ArrowRightFromLine, FileBox, Wrench, Zap, Shrink } from 'lucide-react'
 import type { ComponentType } from 'react'
 import type { NodeType, NodeTypeDefinition, NodeEditorProps } from '../types'
 
@@ -99,6 +99,20 @@ registerNode({
   cssVar: 'var(--node-tool)',
 })
 
+registerNode({
+  type: 'summary',
+  label: 'Summary',
+  description: 'Compress long history into a short summary',
+  icon: Shrink,
+  border: 'border-node-agent/20',
+  borderSelected: 'border-node-agent/60',
+  headerBg: 'bg-node-agent/10',
+  accent: 'bg-node-agent text-node-agent-foreground',
+  glow: 'shadow-[0_0_20px_var(--color-node-agent)/0.25]',
+  paletteBg: 'bg-node-agent/10 text-node-agent border-node-agent/20 hover:bg-node-agent/20',
+  cssVar: 'var(--node-agent)',
+})
+
 registerNode({
   type: 'asset',
   label: 'Asset',
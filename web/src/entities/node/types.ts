@@ -1,6 +1,6 @@
 import type { ComponentType } from 'react'
 
-export type NodeType = 'input' | 'run_input' | 'agent' | 'output' | 'asset' | 'tool'
+export type NodeType = 'input' | 'run_input' | 'agent' | 'output' | 'asset' | 'tool' | 'summary'
 
 export type NodeEditorProps<C = Record<string, unknown>> = {
   nodeId: string
@@ -1,5 +1,5 @@
 import { API_BASE, apiFetch, currentToken, tryRefresh } from '@/shared/api/client'
-import type { RunRecord, RunListResponse, RunEvent, ToolCall, TokenUsage } from '../types'
+import type { RunRecord, RunListResponse, RunEvent, ToolCall, TokenUsage, ActiveRun } from '../types'
 import type { WorkflowDefinition } from '@/entities/workflow/lib/serializer'
 
 export async function fetchRuns(limit = 20, offset = 0, status = ''): Promise<RunListResponse> {
@@ -16,6 +16,14 @@ export async function fetchRun(id: string): Promise<RunRecord> {
   return apiFetch<RunRecord>(`${API_BASE}/runs/${encodeURIComponent(id)}`)
 }
 
+export async function fetchActiveRuns(): Promise<{ runs: ActiveRun[] }> {
+  return apiFetch<{ runs: ActiveRun[] }>(`${API_BASE}/runs/active`)
+}
+
+export async function cancelRun(id: string): Promise<{ run_id: string; status: string }> {
+  return apiFetch<{ run_id: string; status: string }>(`${API_BASE}/runs/${encodeURIComponent(id)}/cancel`, { method: 'POST' })
+}
+
 // parseSSEPayload converts a backend SSE event (snake_case) into a typed RunEvent (camelCase).
 function parseSSEPayload(eventType: string, data: Record<string, unknown>): RunEvent {
   const nodeId = data.node_id as string
@@ -42,8 +50,27 @@ function parseSSEPayload(eventType: string, data: Record<string, unknown>): RunE
       return { type: 'node_waiting', nodeId }
     case 'node_resumed':
       return { type: 'node_resumed', nodeId }
+    case 'progress':
+      return {
+        type: 'progress',
+        nodeId,
+        completed: data.completed as number,
+        total: data.total as number,
+        percent: data.percent as number,
+        currentNode: (data.current_node as string) || undefined,
+      }
     case 'log':
       return { type: 'log', nodeId, message: data.message as string }
+    case 'llm_call':
+      return {
+        type: 'llm_call',
+        nodeId,
+        model: data.model as string,
+        attempt: data.attempt as number,
+        latencyMs: data.latency_ms as number,
+        tokens: data.tokens as TokenUsage | undefined,
+        error: data.error as string | undefined,
+      }
     case 'error':
       return { type: 'error', message: data.error as string ?? JSON.stringify(data) }
     default:
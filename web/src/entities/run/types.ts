@@ -10,16 +10,44 @@ export type RunRecord = {
   }
   trigger_type: string
   trigger_ref: string
-  status: 'pending' | 'running' | 'success' | 'failed' | 'cancelled' | 'retrying'
+  status: 'pending' | 'running' | 'success' | 'failed' | 'cancelled' | 'retrying' | 'contract_violation' | 'blocked_moderation' | 'completed_with_errors'
+  progress?: number
   inputs: Record<string, unknown>
   outputs?: Record<string, unknown>
   error?: string
+  cancel_reason?: string
   retry_of?: string
   retry_count: number
   created_at: string
   started_at?: string
   completed_at?: string
   node_runs?: NodeRunRecord[]
+  context?: RunContext
+}
+
+export type RunContext = {
+  captured_at: string
+  server_version?: string
+  nodes?: {
+    node_id: string
+    type: string
+    model?: string
+    provider?: string
+    resolved_model?: string
+    resolve_error?: string
+    tools?: { name: string; native?: boolean; available: boolean }[]
+  }[]
+  providers?: Record<string, { type: string; url?: string }>
+  config?: Record<string, unknown>
+}
+
+export type ActiveRun = {
+  run_id: string
+  workflow_name: string
+  started_at: string
+  current_node?: string
+  trigger_type?: string
+  trigger_ref?: string
 }
 
 export type NodeRunRecord = {
@@ -29,6 +57,7 @@ export type NodeRunRecord = {
   completed_at?: string
   error?: string
   retry_count: number
+  output?: unknown
 }
 
 export type RunListResponse = {
@@ -60,13 +89,15 @@ export type NodeCompletedEvent = {
 export type NodeSkippedEvent = { type: 'node_skipped'; nodeId: string }
 export type NodeWaitingEvent = { type: 'node_waiting'; nodeId: string }
 export type NodeResumedEvent = { type: 'node_resumed'; nodeId: string }
+export type ProgressEvent = { type: 'progress'; nodeId: string; completed: number; total: number; percent: number; currentNode?: string }
 export type WorkflowDoneEvent = { type: 'done'; status: string; sessionId: string; state: Record<string, unknown>; error?: string }
 export type WorkflowErrorEvent = { type: 'error'; message: string }
 export type InfoEvent = { type: 'info'; message: string }
 export type LogEvent = { type: 'log'; nodeId: string; message: string }
+export type LLMCallEvent = { type: 'llm_call'; nodeId: string; model: string; attempt: number; latencyMs: number; tokens?: TokenUsage; error?: string }
 
 export type RunEvent =
   | NodeStartedEvent | ToolCallEvent | ToolResultEvent
-  | NodeCompletedEvent | NodeSkippedEvent | NodeWaitingEvent | NodeResumedEvent
+  | NodeCompletedEvent | NodeSkippedEvent | NodeWaitingEvent | NodeResumedEvent | ProgressEvent
   | WorkflowDoneEvent | WorkflowErrorEvent
-  | InfoEvent | LogEvent
+  | InfoEvent | LogEvent | LLMCallEvent
@@ -62,9 +62,14 @@ export type CollectSource = {
 export type StageConfig = {
   workflow_name?: string
   input_mapping?: Record<string, string>
+  inputs_from?: Record<string, string>
   message?: string
   connection_id?: string
   subject?: string
+  cc?: string[]
+  bcc?: string[]
+  html?: string
+  attachments?: string[]
   timeout?: number
   cron?: string
   timezone?: string
@@ -12,11 +12,18 @@ export type WorkflowGroup = {
 
 export type WorkflowDefinition = {
   name: string
+  tags?: string[]
   version: number
   nodes: WorkflowNode[]
   edges: WorkflowEdge[]
   groups?: WorkflowGroup[]
   thumbnail_svg?: string
+  baseline_run_id?: string
+  failure_mode?: 'fail_fast' | 'best_effort'
+  created_by?: string
+  updated_by?: string
+  created_at?: string
+  updated_at?: string
 }
 
 type WorkflowNode = {
@@ -10,6 +10,7 @@ const typeLabels: Record<ConnectionType, string> = {
   slack: 'Slack',
   http: 'HTTP',
   smtp: 'SMTP',
+  webhook: 'Webhook',
 }
 
 const typeBadgeClass: Record<ConnectionType, string> = {
@@ -17,6 +18,7 @@ const typeBadgeClass: Record<ConnectionType, string> = {
   slack: 'bg-success/10 text-success',
   http: 'bg-warning/10 text-warning',
   smtp: 'bg-muted text-muted-foreground',
+  webhook: 'bg-warning/10 text-warning',
 }
 
 const inputClass = 'w-full text-sm border border-white/10 rounded-lg px-3 py-2 bg-black/20 outline-none focus:ring-1 focus:ring-primary focus:bg-black/40 transition-colors'
@@ -300,6 +302,31 @@ export default function Connections() {
               </>
             )}
 
+            {form.type === 'webhook' && (
+              <>
+                <div className="space-y-1">
+                  <label className="text-xs text-muted-foreground">Endpoint URL</label>
+                  <input
+                    type="text"
+                    value={form.host ?? ''}
+                    onChange={(e) => set('host', e.target.value)}
+                    placeholder="https://your-server.com/hooks/upal"
+                    className={`${inputClass} font-mono`}
+                  />
+                </div>
+                <div className="space-y-1">
+                  <label className="text-xs text-muted-foreground">Headers (JSON, 선택)</label>
+                  <textarea
+                    value={form.extras?.headers ?? ''}
+                    onChange={(e) => setExtra('headers', e.target.value)}
+                    placeholder='{"X-Api-Key": "..."}'
+                    rows={3}
+                    className={`${inputClass} font-mono`}
+                  />
+                </div>
+              </>
+            )}
+
             {error && <p className="text-xs text-destructive">{error}</p>}
 
             <div className="flex justify-end gap-2 pt-2">
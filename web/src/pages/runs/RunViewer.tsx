@@ -24,6 +24,9 @@ const statusConfig: Record<string, { icon: typeof Clock; color: string; label: s
   failed:    { icon: XCircle,      color: 'text-destructive',      label: 'Failed' },
   cancelled: { icon: XCircle,      color: 'text-muted-foreground', label: 'Cancelled' },
   retrying:  { icon: Timer,        color: 'text-warning',          label: 'Retrying' },
+  contract_violation: { icon: XCircle, color: 'text-warning', label: 'Contract violation' },
+  blocked_moderation: { icon: XCircle, color: 'text-destructive', label: 'Blocked' },
+  completed_with_errors: { icon: CheckCircle2, color: 'text-warning', label: 'Completed with errors' },
 }
 
 function formatDuration(run: RunRecord): string {
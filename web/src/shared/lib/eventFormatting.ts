@@ -8,10 +8,12 @@ export const eventColorMap: Record<string, string> = {
   node_skipped:   'text-muted-foreground/60 italic',
   node_waiting:   'text-amber-500 dark:text-amber-400',
   node_resumed:   'text-muted-foreground',
+  progress:       'text-muted-foreground/60',
   done:           'text-node-output font-semibold',
   error:          'text-destructive',
   info:           'text-muted-foreground',
   log:            'text-muted-foreground/60 text-[11px]',
+  llm_call:       'text-muted-foreground/60 text-[11px]',
 }
 
 function truncate(text: string, maxLen: number): string {
@@ -65,8 +67,15 @@ export function formatEvent(event: RunEvent, verbose = false): string {
       return `[${event.nodeId}] waiting`
     case 'node_resumed':
       return `[${event.nodeId}] resumed`
+    case 'progress':
+      return `progress ${event.completed}/${event.total} (${event.percent}%)`
     case 'log':
       return `[${event.nodeId}] ${event.message}`
+    case 'llm_call': {
+      const tokens = event.tokens ? ` ${event.tokens.total} tokens` : ''
+      const status = event.error ? ` failed: ${event.error}` : tokens
+      return `[${event.nodeId}] ${event.model} call #${event.attempt} ${(event.latencyMs / 1000).toFixed(1)}s${status}`
+    }
   }
 }
 
@@ -18,6 +18,8 @@ export type AgentNodeConfig = {
   prompt?: string
   system_prompt?: string
   output?: string
+  // Few-shot input/output pairs sent as earlier turns before the prompt
+  examples?: { input: string; output: string }[]
   tools?: string[]
   description?: string
   // Text model options